@@ -0,0 +1,193 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+)
+
+const (
+	// admissionSampleInterval is the minimum interval between two samples
+	// of the memory of the process. Reading the memory stats of the Go
+	// runtime stops the world, so it isn't done for every query.
+	admissionSampleInterval = 100 * time.Millisecond
+
+	// admissionWideUnion is the number of arguments above which a Union or
+	// Xor call is an expensive query.
+	admissionWideUnion = 16
+)
+
+// Memory pressure levels of an admission controller.
+const (
+	admissionLevelNormal   = "normal"
+	admissionLevelHigh     = "high"
+	admissionLevelCritical = "critical"
+)
+
+// AdmissionStatus describes the memory pressure of a node and the queries
+// it rejected because of it.
+type AdmissionStatus struct {
+	Level             string `json:"level"`
+	Memory            uint64 `json:"memory"`
+	HighMemory        int64  `json:"highMemory"`
+	CriticalMemory    int64  `json:"criticalMemory"`
+	RejectedExpensive uint64 `json:"rejectedExpensive"`
+	RejectedAll       uint64 `json:"rejectedAll"`
+}
+
+// admissionController rejects new queries while the memory used by the
+// process is above a high-water mark, above which expensive queries are
+// rejected, or a critical mark, above which all queries are rejected. A zero
+// mark is disabled.
+type admissionController struct {
+	highMemory     int64
+	criticalMemory int64
+
+	// Queries rejected above each mark.
+	rejectedExpensive uint64
+	rejectedAll       uint64
+
+	mu        sync.Mutex
+	sampledAt time.Time
+	memory    uint64
+
+	// readMemStats reads the memory stats of the Go runtime. Replaced by
+	// tests.
+	readMemStats func(*runtime.MemStats)
+}
+
+func newAdmissionController() *admissionController {
+	return &admissionController{
+		readMemStats: runtime.ReadMemStats,
+	}
+}
+
+// HighMemory returns the number of bytes above which expensive queries are
+// rejected.
+func (a *admissionController) HighMemory() int64 {
+	return atomic.LoadInt64(&a.highMemory)
+}
+
+// setHighMemory sets the number of bytes above which expensive queries are
+// rejected.
+func (a *admissionController) setHighMemory(n int64) {
+	atomic.StoreInt64(&a.highMemory, n)
+}
+
+// CriticalMemory returns the number of bytes above which all queries are
+// rejected.
+func (a *admissionController) CriticalMemory() int64 {
+	return atomic.LoadInt64(&a.criticalMemory)
+}
+
+// setCriticalMemory sets the number of bytes above which all queries are
+// rejected.
+func (a *admissionController) setCriticalMemory(n int64) {
+	atomic.StoreInt64(&a.criticalMemory, n)
+}
+
+// sample returns the memory obtained from the system by the Go runtime and
+// not returned to it, sampling it again if the last sample is too old.
+func (a *admissionController) sample() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if now := time.Now(); now.Sub(a.sampledAt) >= admissionSampleInterval {
+		var ms runtime.MemStats
+		a.readMemStats(&ms)
+		a.memory = ms.Sys - ms.HeapReleased
+		a.sampledAt = now
+	}
+	return a.memory
+}
+
+// level returns the memory pressure level of the process, and its memory.
+func (a *admissionController) level() (string, uint64) {
+	high, critical := a.HighMemory(), a.CriticalMemory()
+	if high <= 0 && critical <= 0 {
+		return admissionLevelNormal, 0
+	}
+
+	memory := a.sample()
+	if critical > 0 && memory >= uint64(critical) {
+		return admissionLevelCritical, memory
+	} else if high > 0 && memory >= uint64(high) {
+		return admissionLevelHigh, memory
+	}
+	return admissionLevelNormal, memory
+}
+
+// admit returns ErrMemoryPressure if a new query must be rejected because
+// of the memory pressure of the process.
+func (a *admissionController) admit(expensive bool) error {
+	switch level, _ := a.level(); level {
+	case admissionLevelCritical:
+		atomic.AddUint64(&a.rejectedAll, 1)
+		return ErrMemoryPressure
+	case admissionLevelHigh:
+		if expensive {
+			atomic.AddUint64(&a.rejectedExpensive, 1)
+			return ErrMemoryPressure
+		}
+	}
+	return nil
+}
+
+// status returns the memory pressure of the process and the number of
+// queries rejected so far.
+func (a *admissionController) status() AdmissionStatus {
+	level, memory := a.level()
+	return AdmissionStatus{
+		Level:             level,
+		Memory:            memory,
+		HighMemory:        a.HighMemory(),
+		CriticalMemory:    a.CriticalMemory(),
+		RejectedExpensive: atomic.LoadUint64(&a.rejectedExpensive),
+		RejectedAll:       atomic.LoadUint64(&a.rejectedAll),
+	}
+}
+
+// isExpensiveQuery returns true if q contains calls whose memory grows with
+// the number of rows they read, such as TopN, or wide unions.
+func isExpensiveQuery(q *pql.Query) bool {
+	for _, c := range q.Calls {
+		if isExpensiveCall(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func isExpensiveCall(c *pql.Call) bool {
+	switch c.Name {
+	case "TopN", "Rows", "GroupBy":
+		return true
+	case "Union", "Xor":
+		if len(c.Children) > admissionWideUnion {
+			return true
+		}
+	}
+	for _, child := range c.Children {
+		if isExpensiveCall(child) {
+			return true
+		}
+	}
+	return false
+}
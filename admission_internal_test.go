@@ -0,0 +1,96 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+)
+
+// Ensure expensive queries are rejected above the high-water mark, and all
+// queries above the critical mark.
+func TestAdmissionController_Admit(t *testing.T) {
+	var memory uint64
+	a := newAdmissionController()
+	a.readMemStats = func(ms *runtime.MemStats) { ms.Sys = memory }
+
+	admit := func(expensive bool) error {
+		a.sampledAt = time.Time{}
+		return a.admit(expensive)
+	}
+
+	// Marks are disabled by default.
+	memory = 1 << 40
+	if err := admit(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a.setHighMemory(1000)
+	a.setCriticalMemory(2000)
+
+	memory = 999
+	if err := admit(true); err != nil {
+		t.Fatalf("unexpected error below high mark: %v", err)
+	}
+
+	memory = 1500
+	if err := admit(false); err != nil {
+		t.Fatalf("unexpected error for cheap query above high mark: %v", err)
+	} else if err := admit(true); err != ErrMemoryPressure {
+		t.Fatalf("expected memory pressure error, got %v", err)
+	}
+
+	memory = 2000
+	if err := admit(false); err != ErrMemoryPressure {
+		t.Fatalf("expected memory pressure error, got %v", err)
+	}
+
+	if status := a.status(); status.Level != admissionLevelCritical || status.Memory != 2000 || status.RejectedExpensive != 1 || status.RejectedAll != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	// Pressure subsides.
+	memory = 10
+	if err := admit(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIsExpensiveQuery(t *testing.T) {
+	wide := "Row(f=0)" + strings.Repeat(", Row(f=0)", admissionWideUnion)
+	tests := []struct {
+		pql       string
+		expensive bool
+	}{
+		{pql: `Count(Row(f=1))`, expensive: false},
+		{pql: `Set(1, f=1)`, expensive: false},
+		{pql: `Count(Union(Row(f=1), Row(f=2)))`, expensive: false},
+		{pql: `TopN(f, n=5)`, expensive: true},
+		{pql: `Count(Row(f=1)) GroupBy(Rows(f))`, expensive: true},
+		{pql: `Count(Union(` + wide + `))`, expensive: true},
+	}
+	for _, tt := range tests {
+		q, err := pql.ParseString(tt.pql)
+		if err != nil {
+			t.Fatal(err)
+		} else if got := isExpensiveQuery(q); got != tt.expensive {
+			t.Errorf("%s: expected expensive=%v, got %v", tt.pql, tt.expensive, got)
+		}
+	}
+}
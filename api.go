@@ -24,6 +24,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -34,6 +35,7 @@ import (
 	"github.com/pilosa/pilosa/v2/stats"
 	"github.com/pilosa/pilosa/v2/tracing"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -48,6 +50,10 @@ type API struct {
 	importWorkerPoolSize int
 	importWork           chan importJob
 
+	// Serializes schema mutations so that generation preconditions are
+	// checked atomically with the mutation.
+	schemaMu sync.Mutex
+
 	Serializer Serializer
 }
 
@@ -131,6 +137,11 @@ func (api *API) Close() error {
 	return nil
 }
 
+// audit records a write operation received from a client in the audit log.
+func (api *API) audit(ctx context.Context, rec *AuditRecord) {
+	api.server.audit.log(ctx, rec)
+}
+
 // Query parses a PQL query out of the request and executes it.
 func (api *API) Query(ctx context.Context, req *QueryRequest) (QueryResponse, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "API.Query")
@@ -144,20 +155,51 @@ func (api *API) Query(ctx context.Context, req *QueryRequest) (QueryResponse, er
 	if err != nil {
 		return QueryResponse{}, errors.Wrap(err, "parsing")
 	}
+	if idx, ierr := api.holder.AcquireIndex(req.Index); ierr == nil {
+		defer idx.Close()
+		if !req.Remote {
+			if shard, ok := writeShard(q, idx.ShardWidth()); ok {
+				if err := api.routeShard(ctx, req.Index, shard); err != nil {
+					return QueryResponse{}, err
+				}
+			}
+			start := time.Now()
+			defer func() { api.queryStats(idx.Index, q, start, err) }()
+		}
+	}
 	execOpts := &execOptions{
 		Remote:          req.Remote,
 		ExcludeRowAttrs: req.ExcludeRowAttrs, // NOTE: Kept for Pilosa 1.x compat.
 		ExcludeColumns:  req.ExcludeColumns,  // NOTE: Kept for Pilosa 1.x compat.
 		ColumnAttrs:     req.ColumnAttrs,     // NOTE: Kept for Pilosa 1.x compat.
+		Session:         req.Session,
+		StoreAs:         req.StoreAs,
+		Snapshot:        req.Snapshot,
+		AsOf:            req.AsOf,
+		Estimate:        req.Estimate,
+		MaxStaleness:    req.MaxStaleness,
 	}
 	resp, err := api.server.executor.Execute(ctx, req.Index, q, req.Shards, execOpts)
 	if err != nil {
 		return QueryResponse{}, errors.Wrap(err, "executing")
 	}
+	resp.Roaring = req.Roaring
 
 	return resp, nil
 }
 
+// queryStats emits the statistics of a query received from a client, which
+// are kept by the stats history.
+func (api *API) queryStats(idx *Index, q *pql.Query, start time.Time, err error) {
+	idx.Stats.Timing(statQuery, time.Since(start), 1.0)
+	for _, c := range q.Calls {
+		idx.Stats.CountWithCustomTags(statQueryCall, 1, 1.0, []string{"call:" + c.Name})
+	}
+	if err != nil {
+		idx.Stats.Count(statQueryError, 1, 1.0)
+	}
+}
+
 // CreateIndex makes a new Pilosa index.
 func (api *API) CreateIndex(ctx context.Context, indexName string, options IndexOptions) (*Index, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.CreateIndex")
@@ -167,6 +209,12 @@ func (api *API) CreateIndex(ctx context.Context, indexName string, options Index
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return nil, err
+	}
+
 	// Create index.
 	index, err := api.holder.CreateIndex(indexName, options)
 	if err != nil {
@@ -182,11 +230,211 @@ func (api *API) CreateIndex(ctx context.Context, indexName string, options Index
 		return nil, errors.Wrap(err, "sending CreateIndex message")
 	}
 	api.holder.Stats.Count("createIndex", 1, 1.0)
+	api.audit(ctx, &AuditRecord{Operation: "createIndex", Index: indexName})
 	return index, nil
 }
 
-// Index retrieves the named index.
-func (api *API) Index(ctx context.Context, indexName string) (*Index, error) {
+// schemaGenerationKey is the context key for a schema generation precondition.
+type schemaGenerationKey struct{}
+
+// WithSchemaGeneration returns a copy of ctx which makes schema mutations
+// through the API fail with a ConflictError unless the local schema
+// generation equals gen.
+func WithSchemaGeneration(ctx context.Context, gen uint64) context.Context {
+	return context.WithValue(ctx, schemaGenerationKey{}, gen)
+}
+
+// checkSchemaGeneration verifies the schema generation precondition in ctx,
+// if there is one.
+func (api *API) checkSchemaGeneration(ctx context.Context) error {
+	if gen, ok := ctx.Value(schemaGenerationKey{}).(uint64); ok && gen != api.holder.SchemaGeneration() {
+		return newConflictError(ErrSchemaGenerationMismatch)
+	}
+	return nil
+}
+
+// SchemaGeneration returns the schema generation of the local node, which
+// increases every time an index or field is created or deleted.
+func (api *API) SchemaGeneration(ctx context.Context) uint64 {
+	return api.holder.SchemaGeneration()
+}
+
+// Routing controls how the API handles a request for a single shard which
+// the local node doesn't own, and reports what was done with it.
+type Routing struct {
+	// Strict makes such requests fail with a ShardOwnerError instead of
+	// being forwarded to the nodes which own the shard.
+	Strict bool
+
+	// ForwardedTo is set to the nodes a request was forwarded to.
+	ForwardedTo []*Node
+}
+
+// routingKey is the context key for the routing of a request.
+type routingKey struct{}
+
+// WithRouting returns a copy of ctx which makes single-shard writes through
+// the API follow r.
+func WithRouting(ctx context.Context, r *Routing) context.Context {
+	return context.WithValue(ctx, routingKey{}, r)
+}
+
+// routeShard applies the routing in ctx, if there is one, to a request for
+// the given shard. Ownership is that of the current topology, which during
+// a resize is still the topology from before the resize.
+func (api *API) routeShard(ctx context.Context, indexName string, shard uint64) error {
+	r, ok := ctx.Value(routingKey{}).(*Routing)
+	if !ok {
+		return nil
+	}
+	owners := api.cluster.preferredShardNodes(indexName, shard)
+	if Nodes(owners).ContainsID(api.Node().ID) {
+		return nil
+	} else if r.Strict {
+		return &ShardOwnerError{Index: indexName, Shard: shard, Owners: owners}
+	}
+	r.ForwardedTo = owners
+	return nil
+}
+
+// writeShard returns the shard written by q if q only sets or clears bits
+// of columns given by id in a single shard.
+func writeShard(q *pql.Query, shardWidth uint64) (uint64, bool) {
+	var shard uint64
+	for i, c := range q.Calls {
+		if c.Name != "Set" && c.Name != "Clear" {
+			return 0, false
+		}
+		col, ok, err := c.UintArg("_" + columnLabel)
+		if err != nil || !ok {
+			return 0, false
+		} else if i > 0 && col/shardWidth != shard {
+			return 0, false
+		}
+		shard = col / shardWidth
+	}
+	return shard, len(q.Calls) > 0
+}
+
+// SetIndexReadOnly sets whether writes to the named index are rejected on
+// every node.
+func (api *API) SetIndexReadOnly(ctx context.Context, indexName string, readOnly bool) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetIndexReadOnly")
+	defer span.Finish()
+
+	if err := api.validate(apiSetIndexReadOnly); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	if err := index.SetReadOnly(readOnly); err != nil {
+		return errors.Wrap(err, "setting read-only")
+	}
+
+	// Send the read-only flag to all nodes.
+	err = api.server.SendSync(
+		&SetIndexReadOnlyMessage{
+			Index:    indexName,
+			ReadOnly: readOnly,
+		})
+	if err != nil {
+		return errors.Wrap(err, "sending SetIndexReadOnly message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "setIndexReadOnly", Index: indexName})
+	return nil
+}
+
+// SetIndexQuota sets the disk quota in bytes of the named index on every
+// node; zero removes the quota. Each node enforces the quota against the
+// data of the index which it stores.
+func (api *API) SetIndexQuota(ctx context.Context, indexName string, quota int64) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetIndexQuota")
+	defer span.Finish()
+
+	if err := api.validate(apiSetIndexQuota); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	if err := index.SetQuota(quota); err != nil {
+		return errors.Wrap(err, "setting quota")
+	}
+
+	// Send the quota to all nodes.
+	err = api.server.SendSync(
+		&SetIndexQuotaMessage{
+			Index: indexName,
+			Quota: quota,
+		})
+	if err != nil {
+		return errors.Wrap(err, "sending SetIndexQuota message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "setIndexQuota", Index: indexName})
+	return nil
+}
+
+// SetIndexDefaultFieldOptions sets the options which fields created in the
+// named index inherit on every node; nil removes them. Fields which already
+// exist are unchanged.
+func (api *API) SetIndexDefaultFieldOptions(ctx context.Context, indexName string, opt *DefaultFieldOptions) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetIndexDefaultFieldOptions")
+	defer span.Finish()
+
+	if err := api.validate(apiSetIndexDefaultFieldOptions); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	if err := index.SetDefaultFieldOptions(opt); err != nil {
+		return errors.Wrap(err, "setting default field options")
+	}
+
+	// Send the defaults to all nodes.
+	err = api.server.SendSync(
+		&SetIndexDefaultFieldOptionsMessage{
+			Index:   indexName,
+			Options: index.DefaultFieldOptions(),
+		})
+	if err != nil {
+		return errors.Wrap(err, "sending SetIndexDefaultFieldOptions message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "setIndexDefaultFieldOptions", Index: indexName})
+	return nil
+}
+
+// Index retrieves a handle to the named index, which must be closed once the
+// caller is done with the index.
+func (api *API) Index(ctx context.Context, indexName string) (*IndexHandle, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.Index")
 	defer span.Finish()
 
@@ -194,11 +442,7 @@ func (api *API) Index(ctx context.Context, indexName string) (*Index, error) {
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
-	index := api.holder.Index(indexName)
-	if index == nil {
-		return nil, newNotFoundError(ErrIndexNotFound)
-	}
-	return index, nil
+	return api.acquireIndex(indexName)
 }
 
 // DeleteIndex removes the named index. If the index is not found it does
@@ -206,13 +450,35 @@ func (api *API) Index(ctx context.Context, indexName string) (*Index, error) {
 func (api *API) DeleteIndex(ctx context.Context, indexName string) error {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteIndex")
 	defer span.Finish()
+	return api.deleteIndex(ctx, indexName, false)
+}
 
+// PurgeIndex removes the named index and deletes its files on every node,
+// bypassing the trash.
+func (api *API) PurgeIndex(ctx context.Context, indexName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.PurgeIndex")
+	defer span.Finish()
+	return api.deleteIndex(ctx, indexName, true)
+}
+
+func (api *API) deleteIndex(ctx context.Context, indexName string, purge bool) error {
 	if err := api.validate(apiDeleteIndex); err != nil {
 		return errors.Wrap(err, "validating api method")
 	}
 
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
 	// Delete index from the holder.
-	err := api.holder.DeleteIndex(indexName)
+	var err error
+	if purge {
+		err = api.holder.PurgeIndex(indexName)
+	} else {
+		err = api.holder.DeleteIndex(indexName)
+	}
 	if err != nil {
 		return errors.Wrap(err, "deleting index")
 	}
@@ -220,15 +486,130 @@ func (api *API) DeleteIndex(ctx context.Context, indexName string) error {
 	err = api.server.SendSync(
 		&DeleteIndexMessage{
 			Index: indexName,
+			Purge: purge,
 		})
 	if err != nil {
 		api.server.logger.Printf("problem sending DeleteIndex message: %s", err)
 		return errors.Wrap(err, "sending DeleteIndex message")
 	}
 	api.holder.Stats.Count("deleteIndex", 1, 1.0)
+	op := "deleteIndex"
+	if purge {
+		op = "purgeIndex"
+	}
+	api.audit(ctx, &AuditRecord{Operation: op, Index: indexName})
 	return nil
 }
 
+// Trash returns the deleted indexes which are kept in the trash of the node.
+func (api *API) Trash(ctx context.Context) ([]TrashEntry, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.Trash")
+	defer span.Finish()
+
+	if err := api.validate(apiTrash); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+	return api.holder.Trash()
+}
+
+// RestoreIndex moves the named index out of the trash on every node. It
+// fails if an index with the same name exists.
+func (api *API) RestoreIndex(ctx context.Context, indexName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RestoreIndex")
+	defer span.Finish()
+
+	if err := api.validate(apiRestoreIndex); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
+	if _, err := api.holder.RestoreIndex(indexName); err != nil {
+		return errors.Wrap(err, "restoring index")
+	}
+	// Send the restore index message to all nodes.
+	err := api.server.SendSync(
+		&RestoreIndexMessage{
+			Index: indexName,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending RestoreIndex message: %s", err)
+		return errors.Wrap(err, "sending RestoreIndex message")
+	}
+	api.holder.Stats.Count("restoreIndex", 1, 1.0)
+	api.audit(ctx, &AuditRecord{Operation: "restoreIndex", Index: indexName})
+	return nil
+}
+
+// PurgeTrash removes the named index from the trash on every node, after
+// which it can no longer be restored.
+func (api *API) PurgeTrash(ctx context.Context, indexName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.PurgeTrash")
+	defer span.Finish()
+
+	if err := api.validate(apiPurgeTrash); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	if err := api.holder.PurgeTrash(indexName); err != nil {
+		return errors.Wrap(err, "purging trash entry")
+	}
+	// Send the purge trash message to all nodes.
+	err := api.server.SendSync(
+		&PurgeTrashMessage{
+			Index: indexName,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending PurgeTrash message: %s", err)
+		return errors.Wrap(err, "sending PurgeTrash message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "purgeTrash", Index: indexName})
+	return nil
+}
+
+// RebuildExistence sets the existence bit of every column which has a bit in
+// any field of the named index, on every node, turning existence tracking on
+// for the index if it was off. It returns the number of columns of the index
+// afterwards.
+func (api *API) RebuildExistence(ctx context.Context, indexName string) (uint64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.RebuildExistence")
+	defer span.Finish()
+
+	if err := api.validate(apiRebuildExistence); err != nil {
+		return 0, errors.Wrap(err, "validating api method")
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return 0, err
+	}
+	defer index.Close()
+	if _, err := index.rebuildExistence(ctx); err != nil {
+		return 0, errors.Wrap(err, "rebuilding existence")
+	}
+
+	// Send the rebuild message to all nodes.
+	err = api.server.SendSync(
+		&RebuildExistenceMessage{
+			Index: indexName,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending RebuildExistence message: %s", err)
+		return 0, errors.Wrap(err, "sending RebuildExistence message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "rebuildExistence", Index: indexName})
+
+	resp, err := api.server.executor.Execute(ctx, indexName, &pql.Query{Calls: []*pql.Call{{Name: "Count"}}}, nil, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "counting columns")
+	}
+	return resp.Results[0].(uint64), nil
+}
+
 // CreateField makes the named field in the named index with the given options.
 // This method currently only takes a single functional option, but that may be
 // changed in the future to support multiple options.
@@ -240,6 +621,12 @@ func (api *API) CreateField(ctx context.Context, indexName string, fieldName str
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return nil, err
+	}
+
 	// Apply functional options.
 	fo := FieldOptions{}
 	for _, opt := range opts {
@@ -250,9 +637,19 @@ func (api *API) CreateField(ctx context.Context, indexName string, fieldName str
 	}
 
 	// Find index.
-	index := api.holder.Index(indexName)
-	if index == nil {
-		return nil, newNotFoundError(ErrIndexNotFound)
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer index.Close()
+
+	// Options which aren't given are inherited from the index.
+	index.DefaultFieldOptions().apply(&fo)
+	if fo.Type == FieldTypeTime && fo.TimeQuantum == "" {
+		return nil, NewBadRequestError(errors.New("timeQuantum is required for field type time"))
+	}
+	if fo.ShareTimeViews && (fo.Type != FieldTypeTime || !sharesTimeViews(fo.TimeQuantum)) {
+		return nil, NewBadRequestError(ErrSharedTimeViewsQuantum)
 	}
 
 	// Create field.
@@ -261,18 +658,21 @@ func (api *API) CreateField(ctx context.Context, indexName string, fieldName str
 		return nil, errors.Wrap(err, "creating field")
 	}
 
-	// Send the create field message to all nodes.
+	// Send the effective options of the field to all nodes, so that they
+	// create identical fields whatever their defaults.
+	meta := field.Options()
 	err = api.server.SendSync(
 		&CreateFieldMessage{
 			Index: indexName,
 			Field: fieldName,
-			Meta:  &fo,
+			Meta:  &meta,
 		})
 	if err != nil {
 		api.server.logger.Printf("problem sending CreateField message: %s", err)
 		return nil, errors.Wrap(err, "sending CreateField message")
 	}
 	api.holder.Stats.CountWithCustomTags("createField", 1, 1.0, []string{fmt.Sprintf("index:%s", indexName)})
+	api.audit(ctx, &AuditRecord{Operation: "createField", Index: indexName, Field: fieldName})
 	return field, nil
 }
 
@@ -309,11 +709,20 @@ type importJob struct {
 	shard   uint64
 	field   *Field
 	errChan chan error
+
+	// Existence field of the index, which is given the columns of the
+	// imported bits, if the index tracks existence.
+	existence *Field
+
+	// Gate which keeps snapshot reads from observing part of the import.
+	gate *txGate
 }
 
 func importWorker(importWork chan importJob) {
 	for j := range importWork {
 		err := func() error {
+			j.gate.enter()
+			defer j.gate.exit()
 			for viewName, viewData := range j.req.Views {
 				if viewName == "" {
 					viewName = viewStandard
@@ -323,9 +732,18 @@ func importWorker(importWork chan importJob) {
 				if len(viewData) == 0 {
 					return fmt.Errorf("no data to import for view: %s", viewName)
 				}
+				// The existence bits are read before importing, which may
+				// rewrite the data.
+				var existence []byte
+				if j.existence != nil && !j.req.Clear {
+					var err error
+					if existence, err = existenceRoaring(viewData, j.field.shardWidth); err != nil {
+						return errors.Wrap(err, "reading existence columns")
+					}
+				}
 				fileMagic := uint32(binary.LittleEndian.Uint16(viewData[0:2]))
 				if fileMagic == roaring.MagicNumber { // if pilosa roaring format
-					if err := j.field.importRoaring(j.ctx, viewData, j.shard, viewName, j.req.Clear); err != nil {
+					if err := j.field.importRoaring(j.ctx, viewData, j.shard, viewName, j.req.Clear, j.req.OperationID); err != nil {
 						return errors.Wrap(err, "importing pilosa roaring")
 					}
 				} else {
@@ -333,10 +751,15 @@ func importWorker(importWork chan importJob) {
 					// field.importRoaring changes the standard roaring run format to pilosa roaring
 					data := make([]byte, len(viewData))
 					copy(data, viewData)
-					if err := j.field.importRoaring(j.ctx, data, j.shard, viewName, j.req.Clear); err != nil {
+					if err := j.field.importRoaring(j.ctx, data, j.shard, viewName, j.req.Clear, j.req.OperationID); err != nil {
 						return errors.Wrap(err, "importing standard roaring")
 					}
 				}
+				if existence != nil {
+					if err := j.existence.importRoaring(j.ctx, existence, j.shard, viewStandard, false, ""); err != nil {
+						return errors.Wrap(err, "importing existence columns")
+					}
+				}
 			}
 			return nil
 		}()
@@ -376,27 +799,69 @@ func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string,
 
 	nodes := api.cluster.shardNodes(indexName, shard)
 
-	field := api.holder.Field(indexName, fieldName)
+	idx, err := api.acquireIndex(indexName)
+	if err != nil {
+		return newNotFoundError(ErrFieldNotFound)
+	}
+	defer idx.Close()
+	field := idx.Field(fieldName)
 	if field == nil {
 		return newNotFoundError(ErrFieldNotFound)
 	}
 
-	// only set and time fields are supported
-	if field.Type() != FieldTypeSet && field.Type() != FieldTypeTime {
+	// only set and time fields are supported, except for the remote imports
+	// of replication, which copy the exact bits of mutex and bool fields.
+	switch field.Type() {
+	case FieldTypeSet, FieldTypeTime:
+	case FieldTypeMutex, FieldTypeBool:
+		if !remote {
+			return NewBadRequestError(errors.New("roaring import is only supported for set and time fields"))
+		}
+	default:
 		return NewBadRequestError(errors.New("roaring import is only supported for set and time fields"))
 	}
 
+	// Remote imports are forwarded by a node which already checked the flag
+	// and the quota, or are anti-entropy repairs, which are allowed on
+	// read-only indexes and indexes over their quota.
+	if !remote {
+		if idx.ReadOnly() {
+			return ErrIndexReadOnly
+		} else if !req.Clear && idx.QuotaExceeded() {
+			return ErrQuotaExceeded
+		}
+	}
+
+	if !remote {
+		if err := api.routeShard(ctx, indexName, shard); err != nil {
+			return err
+		}
+		defer func() {
+			rec := &AuditRecord{Operation: "importRoaring", Index: indexName, Field: fieldName}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			api.audit(ctx, rec)
+		}()
+	}
+
+	newShard := !remote && !req.Clear && field.shardUnannounced(shard)
+
+	existence := idx.existenceField()
+
 	errCh := make(chan error, len(nodes))
 
 	for _, node := range nodes {
 		node := node
 		if node.ID == api.server.nodeID {
 			api.importWork <- importJob{
-				ctx:     ctx,
-				req:     req,
-				shard:   shard,
-				field:   field,
-				errChan: errCh,
+				ctx:       ctx,
+				req:       req,
+				shard:     shard,
+				field:     field,
+				errChan:   errCh,
+				gate:      &api.server.executor.snapshotGate,
+				existence: existence,
 			}
 		} else if !remote { // if remote == true we don't forward to other nodes
 			// forward it on
@@ -422,6 +887,9 @@ func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string,
 
 		// Exit once all nodes are processed.
 		if maxNode == len(nodes) {
+			if newShard {
+				return field.announceShard(shard)
+			}
 			return nil
 		}
 	}
@@ -438,11 +906,18 @@ func (api *API) DeleteField(ctx context.Context, indexName string, fieldName str
 		return errors.Wrap(err, "validating api method")
 	}
 
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
 	// Find index.
-	index := api.holder.Index(indexName)
-	if index == nil {
-		return newNotFoundError(ErrIndexNotFound)
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
 	}
+	defer index.Close()
 
 	// Delete field from the index.
 	if err := index.DeleteField(fieldName); err != nil {
@@ -450,7 +925,7 @@ func (api *API) DeleteField(ctx context.Context, indexName string, fieldName str
 	}
 
 	// Send the delete field message to all nodes.
-	err := api.server.SendSync(
+	err = api.server.SendSync(
 		&DeleteFieldMessage{
 			Index: indexName,
 			Field: fieldName,
@@ -460,9 +935,453 @@ func (api *API) DeleteField(ctx context.Context, indexName string, fieldName str
 		return errors.Wrap(err, "sending DeleteField message")
 	}
 	api.holder.Stats.CountWithCustomTags("deleteField", 1, 1.0, []string{fmt.Sprintf("index:%s", indexName)})
+	api.audit(ctx, &AuditRecord{Operation: "deleteField", Index: indexName, Field: fieldName})
 	return nil
 }
 
+// SetFieldTimeQuantum changes the time quantum of a time field and returns
+// the units which were added. Views for added units are only populated by
+// writes made after the change.
+func (api *API) SetFieldTimeQuantum(ctx context.Context, indexName, fieldName string, q TimeQuantum) (TimeQuantum, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetFieldTimeQuantum")
+	defer span.Finish()
+
+	if err := api.validate(apiSetFieldTimeQuantum); err != nil {
+		return "", errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return "", err
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return "", err
+	}
+	defer index.Close()
+	field := index.Field(fieldName)
+	if field == nil {
+		return "", newNotFoundError(ErrFieldNotFound)
+	}
+	prev := field.TimeQuantum()
+	now := time.Now().UTC()
+	if err := index.setFieldTimeQuantum(fieldName, q, now); err != nil {
+		return "", err
+	}
+
+	// Send the time quantum to all nodes, with the time of the change so
+	// that every node uses the views of added units from the same time.
+	err = api.server.SendSync(
+		&SetFieldTimeQuantumMessage{
+			Index:       indexName,
+			Field:       fieldName,
+			TimeQuantum: q,
+			Time:        now,
+		})
+	if err != nil {
+		return "", errors.Wrap(err, "sending SetFieldTimeQuantum message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "setFieldTimeQuantum", Index: indexName, Field: fieldName})
+
+	var added []rune
+	for _, unit := range q {
+		if !strings.ContainsRune(string(prev), unit) {
+			added = append(added, unit)
+		}
+	}
+	return TimeQuantum(added), nil
+}
+
+// DeleteSession releases the results stored by queries in session on all
+// nodes.
+func (api *API) DeleteSession(ctx context.Context, session string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteSession")
+	defer span.Finish()
+
+	if err := api.validate(apiDeleteSession); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.server.executor.results.release(session)
+
+	// Release the session on all nodes.
+	if err := api.server.SendSync(&DeleteSessionMessage{Session: session}); err != nil {
+		return errors.Wrap(err, "sending DeleteSession message")
+	}
+	return nil
+}
+
+// CreateIngestMapping adds an ingest mapping to the named index.
+func (api *API) CreateIngestMapping(ctx context.Context, indexName string, m *IngestMapping) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.CreateIngestMapping")
+	defer span.Finish()
+
+	if err := api.validate(apiCreateIngestMapping); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	if err := index.CreateIngestMapping(m); err != nil {
+		return err
+	}
+
+	// Send the ingest mapping to all nodes.
+	err = api.server.SendSync(
+		&CreateIngestMappingMessage{
+			Index:   indexName,
+			Mapping: m,
+		})
+	if err != nil {
+		return errors.Wrap(err, "sending CreateIngestMapping message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "createIngestMapping", Index: indexName})
+	return nil
+}
+
+// IngestMapping returns the named ingest mapping of the named index.
+func (api *API) IngestMapping(ctx context.Context, indexName, name string) (*IngestMapping, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.IngestMapping")
+	defer span.Finish()
+
+	if err := api.validate(apiIngestMapping); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer index.Close()
+	m := index.IngestMapping(name)
+	if m == nil {
+		return nil, newNotFoundError(ErrIngestMappingNotFound)
+	}
+	return m, nil
+}
+
+// DeleteIngestMapping removes the named ingest mapping from the named index.
+func (api *API) DeleteIngestMapping(ctx context.Context, indexName, name string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteIngestMapping")
+	defer span.Finish()
+
+	if err := api.validate(apiDeleteIngestMapping); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.checkSchemaGeneration(ctx); err != nil {
+		return err
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	if err := index.DeleteIngestMapping(name); err != nil {
+		return err
+	}
+
+	// Send the deletion to all nodes.
+	err = api.server.SendSync(
+		&DeleteIngestMappingMessage{
+			Index: indexName,
+			Name:  name,
+		})
+	if err != nil {
+		return errors.Wrap(err, "sending DeleteIngestMapping message")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "deleteIngestMapping", Index: indexName})
+	return nil
+}
+
+// Ingest converts records according to the named ingest mapping and imports
+// the resulting bits and values into the fields of the named index. No data
+// is imported if any record is invalid.
+func (api *API) Ingest(ctx context.Context, indexName, name string, records []map[string]interface{}) (err error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.Ingest")
+	defer span.Finish()
+
+	if err := api.validate(apiIngest); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	if index.ReadOnly() {
+		return newConflictError(ErrIndexReadOnly)
+	} else if index.QuotaExceeded() {
+		return ErrQuotaExceeded
+	}
+	batch, err := index.transformIngest(name, records)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		rec := &AuditRecord{Operation: "ingest", Index: indexName, Count: len(records)}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		api.audit(ctx, rec)
+	}()
+
+	// Send the bits and values of each field to the nodes which own them,
+	// or to the coordinator for translation if keys are used.
+	var eg errgroup.Group
+	for fieldName, bits := range batch.bits {
+		fieldName, bits := fieldName, bits
+		field := index.Field(fieldName)
+		if field == nil {
+			return newNotFoundError(ErrFieldNotFound)
+		}
+		if index.Keys() || field.keys() {
+			eg.Go(func() error {
+				return api.server.defaultClient.ImportK(ctx, indexName, fieldName, bits)
+			})
+			continue
+		}
+		for shard, bits := range bitsByShard(bits, index.ShardWidth()) {
+			shard, bits := shard, bits
+			eg.Go(func() error {
+				return api.server.defaultClient.Import(ctx, indexName, fieldName, shard, bits)
+			})
+		}
+	}
+	for fieldName, vals := range batch.values {
+		fieldName, vals := fieldName, vals
+		if index.Keys() {
+			eg.Go(func() error {
+				return api.server.defaultClient.ImportValueK(ctx, indexName, fieldName, vals)
+			})
+			continue
+		}
+		for shard, vals := range valuesByShard(vals, index.ShardWidth()) {
+			shard, vals := shard, vals
+			eg.Go(func() error {
+				return api.server.defaultClient.ImportValue(ctx, indexName, fieldName, shard, vals)
+			})
+		}
+	}
+	err = errors.Wrap(eg.Wait(), "importing")
+	return err
+}
+
+// CreateDeleteJob starts an asynchronous job which deletes columns from an
+// index, as DeleteColumn() calls do. Columns are given by ID, or by key if
+// the index uses keys. Only the coordinator accepts jobs.
+func (api *API) CreateDeleteJob(ctx context.Context, indexName string, columnIDs []uint64, columnKeys []string) (*DeleteJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.CreateDeleteJob")
+	defer span.Finish()
+
+	if err := api.validate(apiCreateDeleteJob); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	} else if !api.cluster.isCoordinator() {
+		return nil, ErrNodeNotCoordinator
+	}
+
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer index.Close()
+	if index.ReadOnly() {
+		return nil, newConflictError(ErrIndexReadOnly)
+	}
+	if index.Keys() != (len(columnKeys) > 0) && len(columnIDs)+len(columnKeys) > 0 {
+		if index.Keys() {
+			return nil, NewBadRequestError(errors.New("column keys required when index 'keys' option enabled"))
+		}
+		return nil, NewBadRequestError(errors.New("column keys not allowed unless index 'keys' option enabled"))
+	} else if len(columnIDs) > 0 && len(columnKeys) > 0 {
+		return nil, NewBadRequestError(errors.New("column IDs and keys are mutually exclusive"))
+	} else if len(columnIDs)+len(columnKeys) == 0 {
+		return nil, NewBadRequestError(errors.New("columns required"))
+	}
+	if len(columnKeys) > 0 {
+		ids, err := index.translateStore.TranslateKeys(columnKeys)
+		if err != nil {
+			return nil, errors.Wrap(err, "translating keys")
+		}
+		columnIDs = ids
+	}
+
+	job, err := api.server.deleteJobs.create(indexName, columnIDs, index.ShardWidth())
+	if err != nil {
+		return nil, errors.Wrap(err, "creating job")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "createDeleteJob", Index: indexName, Count: len(columnIDs)})
+	return job, nil
+}
+
+// DeleteJob returns the status of a delete job run by this node.
+func (api *API) DeleteJob(ctx context.Context, id string) (*DeleteJob, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteJob")
+	defer span.Finish()
+
+	if err := api.validate(apiDeleteJob); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	job := api.server.deleteJobs.job(id)
+	if job == nil {
+		return nil, newNotFoundError(ErrDeleteJobNotFound)
+	}
+	return job, nil
+}
+
+// ResumeDeleteJob restarts the failed chunks of a finished delete job.
+func (api *API) ResumeDeleteJob(ctx context.Context, id string) (*DeleteJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.ResumeDeleteJob")
+	defer span.Finish()
+
+	if err := api.validate(apiResumeDeleteJob); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	job, err := api.server.deleteJobs.resume(id)
+	if err != nil {
+		return nil, err
+	} else if job == nil {
+		return nil, newNotFoundError(ErrDeleteJobNotFound)
+	}
+	api.audit(ctx, &AuditRecord{Operation: "resumeDeleteJob", Index: job.Index})
+	return job, nil
+}
+
+// CreateWarmJob starts an asynchronous job which rebuilds the structures
+// derived from the data of indexes on every node, such as after a restore:
+// ranked caches, block checksums, and existence bits. All indexes are warmed
+// if none are given. Only the coordinator accepts jobs.
+func (api *API) CreateWarmJob(ctx context.Context, indexes []string) (*WarmJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.CreateWarmJob")
+	defer span.Finish()
+
+	if err := api.validate(apiCreateWarmJob); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	} else if !api.cluster.isCoordinator() {
+		return nil, ErrNodeNotCoordinator
+	}
+
+	if len(indexes) == 0 {
+		for _, idx := range api.holder.Indexes() {
+			indexes = append(indexes, idx.Name())
+		}
+		sort.Strings(indexes)
+	}
+	for _, name := range indexes {
+		if api.holder.Index(name) == nil {
+			return nil, newNotFoundError(errors.Wrap(ErrIndexNotFound, name))
+		}
+	}
+
+	msg := &WarmJobMessage{
+		ID:      uuid.NewV4().String(),
+		Indexes: indexes,
+		Created: time.Now().UTC(),
+	}
+	if _, err := api.server.warmJobs.create(msg.ID, msg.Indexes, msg.Created); err != nil {
+		return nil, errors.Wrap(err, "creating job")
+	}
+	if err := api.server.SendSync(msg); err != nil {
+		return nil, errors.Wrap(err, "sending job")
+	}
+	return api.WarmJob(ctx, msg.ID, false)
+}
+
+// WarmJob returns the status of a warm job, with its progress on every node
+// combined, or on this node only if local is set.
+func (api *API) WarmJob(ctx context.Context, id string, local bool) (*WarmJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.WarmJob")
+	defer span.Finish()
+
+	if err := api.validate(apiWarmJob); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	if local {
+		job := api.server.warmJobs.job(id)
+		if job == nil {
+			return nil, newNotFoundError(ErrWarmJobNotFound)
+		}
+		return job, nil
+	}
+
+	// Read the job of every node concurrently. A node which can't be
+	// reached is reported rather than failing the request.
+	nodes := api.cluster.Nodes()
+	jobs := make([]*WarmJob, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		if node.ID == api.server.nodeID {
+			jobs[i] = api.server.warmJobs.job(id)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, node *Node) {
+			defer wg.Done()
+			jobs[i], errs[i] = api.server.defaultClient.WarmJob(ctx, &node.URI, id)
+		}(i, node)
+	}
+	wg.Wait()
+
+	byNode := make(map[string]*WarmJob, len(nodes))
+	errsByNode := make(map[string]error)
+	for i, node := range nodes {
+		byNode[node.ID] = jobs[i]
+		if errs[i] != nil {
+			errsByNode[node.ID] = errs[i]
+		}
+	}
+	job := mergeWarmJobs(byNode, errsByNode)
+	if job == nil {
+		return nil, newNotFoundError(ErrWarmJobNotFound)
+	}
+	return job, nil
+}
+
+// ResumeWarmJob restarts the failed tasks of a warm job on every node where
+// the job is finished. Only the coordinator accepts it.
+func (api *API) ResumeWarmJob(ctx context.Context, id string) (*WarmJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.ResumeWarmJob")
+	defer span.Finish()
+
+	if err := api.validate(apiResumeWarmJob); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	} else if !api.cluster.isCoordinator() {
+		return nil, ErrNodeNotCoordinator
+	}
+
+	job := api.server.warmJobs.job(id)
+	if job == nil {
+		return nil, newNotFoundError(ErrWarmJobNotFound)
+	}
+	if _, err := api.server.warmJobs.create(job.ID, job.Indexes, job.Created); err != nil {
+		return nil, err
+	}
+	msg := &WarmJobMessage{ID: job.ID, Indexes: job.Indexes, Created: job.Created}
+	if err := api.server.SendSync(msg); err != nil {
+		return nil, errors.Wrap(err, "sending job")
+	}
+	return api.WarmJob(ctx, job.ID, false)
+}
+
 // DeleteAvailableShard a shard ID from the available shard set cache.
 func (api *API) DeleteAvailableShard(_ context.Context, indexName, fieldName string, shardID uint64) error {
 	if err := api.validate(apiDeleteAvailableShard); err != nil {
@@ -506,16 +1425,22 @@ func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName strin
 	}
 
 	// Validate that this handler owns the shard.
-	if !api.cluster.ownsShard(api.Node().ID, indexName, shard) {
-		api.server.logger.Printf("node %s does not own shard %d of index %s", api.Node().ID, shard, indexName)
-		return ErrClusterDoesNotOwnShard
+	if err := api.validateShardOwnership(indexName, shard); err != nil {
+		return err
+	}
+
+	// Exports are expensive, so they are rejected under memory pressure.
+	if err := api.server.executor.admission.admit(true); err != nil {
+		api.holder.Stats.Count("admissionRejected", 1, 1.0)
+		return err
 	}
 
 	// Find index.
-	index := api.holder.Index(indexName)
-	if index == nil {
-		return newNotFoundError(ErrIndexNotFound)
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
 	}
+	defer index.Close()
 
 	// Find field from the index.
 	field := index.Field(fieldName)
@@ -573,7 +1498,8 @@ func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName strin
 	return nil
 }
 
-// ShardNodes returns the node and all replicas which should contain a shard's data.
+// ShardNodes returns the node and all replicas which should contain a shard's
+// data. Nodes in maintenance mode or down are listed last.
 func (api *API) ShardNodes(ctx context.Context, indexName string, shard uint64) ([]*Node, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.ShardNodes")
 	defer span.Finish()
@@ -582,7 +1508,27 @@ func (api *API) ShardNodes(ctx context.Context, indexName string, shard uint64)
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
-	return api.cluster.shardNodes(indexName, shard), nil
+	return api.cluster.preferredShardNodes(indexName, shard), nil
+}
+
+// RoutingTable returns the owners of shards of an index in the order in which
+// they should be read, as ShardNodes does, along with the generation of the
+// topology they were computed from. Clients can use it to send the queries
+// of a shard to the same node and to cache the table until the generation
+// changes.
+func (api *API) RoutingTable(ctx context.Context, indexName string, shards []uint64) (*RoutingTable, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RoutingTable")
+	defer span.Finish()
+
+	if err := api.validate(apiRoutingTable); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	if api.holder.Index(indexName) == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	}
+
+	return api.cluster.routingTable(indexName, shards), nil
 }
 
 // FragmentBlockData is an endpoint for internal usage. It is not guaranteed to
@@ -606,6 +1552,11 @@ func (api *API) FragmentBlockData(ctx context.Context, body io.Reader) ([]byte,
 	}
 
 	// Retrieve fragment from holder.
+	index, err := api.holder.AcquireIndex(req.Index)
+	if err != nil {
+		return nil, ErrFragmentNotFound
+	}
+	defer index.Close()
 	f := api.holder.fragment(req.Index, req.Field, req.View, req.Shard)
 	if f == nil {
 		return nil, ErrFragmentNotFound
@@ -623,24 +1574,118 @@ func (api *API) FragmentBlockData(ctx context.Context, body io.Reader) ([]byte,
 	return buf, nil
 }
 
-// FragmentBlocks returns the checksums and block ids for all blocks in the specified fragment.
-func (api *API) FragmentBlocks(ctx context.Context, indexName, fieldName, viewName string, shard uint64) ([]FragmentBlock, error) {
-	span, _ := tracing.StartSpanFromContext(ctx, "API.FragmentBlocks")
+// MaxBlockDataLimit is the maximum number of row/column pairs returned by a
+// single FragmentBlockPairs call.
+const MaxBlockDataLimit = 1 << 16
+
+// FragmentBlockPairs returns the row/column pairs of a block of a fragment. At
+// most req.Limit pairs are returned, capped to MaxBlockDataLimit. If the block
+// contains more pairs, the response contains a Continuation which can be set on
+// the next request to read the remaining pairs.
+func (api *API) FragmentBlockPairs(ctx context.Context, req *BlockDataRequest) (*BlockDataResponse, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FragmentBlockPairs")
+	defer span.Finish()
+
+	if err := api.validate(apiFragmentBlockPairs); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	// Retrieve fragment from holder.
+	index, err := api.holder.AcquireIndex(req.Index)
+	if err != nil {
+		return nil, ErrFragmentNotFound
+	}
+	defer index.Close()
+	f := api.holder.fragment(req.Index, req.Field, req.View, req.Shard)
+	if f == nil {
+		return nil, ErrFragmentNotFound
+	}
+
+	limit := req.Limit
+	if limit == 0 || limit > MaxBlockDataLimit {
+		limit = MaxBlockDataLimit
+	}
+	resp := &BlockDataResponse{}
+	resp.RowIDs, resp.ColumnIDs, resp.Continuation = f.blockDataPage(int(req.Block), req.Continuation, int(limit))
+	return resp, nil
+}
+
+// FragmentBlocks returns the checksums and block ids for all blocks in the specified fragment.
+func (api *API) FragmentBlocks(ctx context.Context, indexName, fieldName, viewName string, shard uint64) ([]FragmentBlock, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FragmentBlocks")
+	defer span.Finish()
+
+	if err := api.validate(apiFragmentBlocks); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	// Retrieve fragment from holder.
+	index, err := api.holder.AcquireIndex(indexName)
+	if err != nil {
+		return nil, ErrFragmentNotFound
+	}
+	defer index.Close()
+	f := api.holder.fragment(indexName, fieldName, viewName, shard)
+	if f == nil {
+		return nil, ErrFragmentNotFound
+	}
+
+	// Retrieve blocks.
+	blocks := f.Blocks()
+	return blocks, nil
+}
+
+// FragmentInspect describes the storage of the specified fragment, and dumps
+// the given rows and blocks.
+func (api *API) FragmentInspect(ctx context.Context, indexName, fieldName, viewName string, shard uint64, rows []uint64, blocks []int) (*FragmentInfo, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FragmentInspect")
+	defer span.Finish()
+
+	if err := api.validate(apiFragmentInspect); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	// Retrieve fragment from holder.
+	index, err := api.holder.AcquireIndex(indexName)
+	if err != nil {
+		return nil, ErrFragmentNotFound
+	}
+	defer index.Close()
+	f := api.holder.fragment(indexName, fieldName, viewName, shard)
+	if f == nil {
+		return nil, ErrFragmentNotFound
+	}
+	return f.inspect(FragmentInspectOptions{Rows: rows, Blocks: blocks})
+}
+
+// CompactFragment rewrites the op log of the specified fragment so that it
+// only holds the net effect of its ops, regardless of how many of them are
+// redundant.
+func (api *API) CompactFragment(ctx context.Context, indexName, fieldName, viewName string, shard uint64) (*FragmentCompaction, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.CompactFragment")
 	defer span.Finish()
 
-	if err := api.validate(apiFragmentBlocks); err != nil {
+	if err := api.validate(apiCompactFragment); err != nil {
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
-	// Retrieve fragment from holder.
+	index, err := api.holder.AcquireIndex(indexName)
+	if err != nil {
+		return nil, ErrFragmentNotFound
+	}
+	defer index.Close()
 	f := api.holder.fragment(indexName, fieldName, viewName, shard)
 	if f == nil {
 		return nil, ErrFragmentNotFound
 	}
-
-	// Retrieve blocks.
-	blocks := f.Blocks()
-	return blocks, nil
+	fc, err := f.CompactOps()
+	if err != nil {
+		return nil, errors.Wrap(err, "compacting op log")
+	} else if fc == nil {
+		// There was nothing to compact.
+		fc = &FragmentCompaction{Time: time.Now().UTC()}
+	}
+	return fc, nil
 }
 
 // FragmentData returns all data in the specified fragment.
@@ -691,6 +1736,104 @@ func (api *API) RecalculateCaches(ctx context.Context) error {
 	return nil
 }
 
+// Transaction applies a batch of Set(), Clear(), and SetRowAttrs() calls
+// to an index atomically. The batch is prepared on every node which owns
+// data it writes and is only committed once all nodes have accepted it. If
+// any node fails to commit then the batch is rolled back on all nodes. If an
+// operation ID is given, a retried batch is only applied once.
+func (api *API) Transaction(ctx context.Context, indexName string, query string, operationID string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.Transaction")
+	defer span.Finish()
+
+	if err := api.validate(apiTransaction); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	handle, err := api.acquireIndex(indexName)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	idx := handle.Index
+	if idx.ReadOnly() {
+		return newConflictError(ErrIndexReadOnly)
+	}
+
+	q, err := pql.NewParser(strings.NewReader(query)).Parse()
+	if err != nil {
+		return NewBadRequestError(errors.Wrap(err, "parsing"))
+	} else if idx.QuotaExceeded() && addsData(q) {
+		return ErrQuotaExceeded
+	} else if err := validateTransactionCalls(q); err != nil {
+		return NewBadRequestError(err)
+	}
+
+	// Translate keys and validate the entire batch before involving other nodes.
+	e := api.server.executor
+	if err := e.translateCalls(ctx, indexName, idx, q.Calls); err != nil {
+		return errors.Wrap(err, "translating calls")
+	} else if _, err := e.transactionOps(indexName, q, true); err != nil {
+		return NewBadRequestError(err)
+	}
+	nodes, err := e.transactionNodes(indexName, idx, q)
+	if err != nil {
+		return NewBadRequestError(err)
+	}
+	newShards := transactionNewShards(idx, q)
+
+	msg := &TransactionMessage{
+		ID:          uuid.NewV4().String(),
+		Index:       indexName,
+		Query:       q.String(),
+		OperationID: operationID,
+	}
+
+	// Prepare on all nodes, aborting if any node rejects the batch.
+	msg.Action = transactionActionPrepare
+	if err := api.sendTransaction(nodes, msg); err != nil {
+		api.abortTransaction(nodes, msg)
+		return errors.Wrap(err, "preparing transaction")
+	}
+
+	// Commit on all nodes. Aborting reverts any node which already committed.
+	msg.Action = transactionActionCommit
+	if err := api.sendTransaction(nodes, msg); err != nil {
+		api.abortTransaction(nodes, msg)
+		return errors.Wrap(err, "committing transaction")
+	}
+	for _, s := range newShards {
+		if err := s.field.announceShard(s.shard); err != nil {
+			return err
+		}
+	}
+	api.audit(ctx, &AuditRecord{Operation: "transaction", Index: indexName, Calls: q.WriteCalls(), Count: q.WriteCallN()})
+	return nil
+}
+
+// sendTransaction sends a transaction message to nodes in parallel.
+func (api *API) sendTransaction(nodes []*Node, msg *TransactionMessage) error {
+	var eg errgroup.Group
+	for _, node := range nodes {
+		node := node
+		eg.Go(func() error {
+			return api.server.SendTo(node, msg)
+		})
+	}
+	return eg.Wait()
+}
+
+// abortTransaction aborts a transaction on all nodes. Errors are logged since
+// nodes discard abandoned transactions on their own.
+func (api *API) abortTransaction(nodes []*Node, msg *TransactionMessage) {
+	abort := *msg
+	abort.Action = transactionActionAbort
+	for _, node := range nodes {
+		if err := api.server.SendTo(node, &abort); err != nil {
+			api.server.logger.Printf("aborting transaction %s on node %s: %s", msg.ID, node.ID, err)
+		}
+	}
+}
+
 // ClusterMessage is for internal use. It decodes a protobuf message out of
 // the body and forwards it to the BroadcastHandler.
 func (api *API) ClusterMessage(ctx context.Context, reqBody io.Reader) error {
@@ -753,10 +1896,62 @@ func (api *API) ApplySchema(ctx context.Context, s *Schema, remote bool) error {
 		}
 	}
 
-	return api.holder.applySchema(s)
+	if err := api.holder.applySchema(s); err != nil {
+		return err
+	}
+	if !remote {
+		api.audit(ctx, &AuditRecord{Operation: "applySchema"})
+	}
+	return nil
+}
+
+// ExportSchema returns the full schema of the local node, including index
+// options and views, and its schema generation. It can be applied to a new
+// node with ProvisionSchema.
+func (api *API) ExportSchema(ctx context.Context) (*Schema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ExportSchema")
+	defer span.Finish()
+
+	if err := api.validate(apiExportSchema); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	// Hold the schema lock so that the generation matches the schema.
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	return &Schema{
+		Indexes:    api.holder.Schema(),
+		Generation: api.holder.SchemaGeneration(),
+	}, nil
+}
+
+// ProvisionSchema applies a schema from ExportSchema to the local node only,
+// creating its indexes, fields and views without any data. It is used to
+// prepare a node before it is added to a cluster, so that the resize job only
+// moves data. If the node then has exactly the exported indexes and fields,
+// it adopts the exported schema generation, which tells the resize job that
+// the node doesn't need the schema.
+func (api *API) ProvisionSchema(ctx context.Context, s *Schema) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ProvisionSchema")
+	defer span.Finish()
+
+	if err := api.validate(apiProvisionSchema); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	api.schemaMu.Lock()
+	defer api.schemaMu.Unlock()
+	if err := api.holder.applySchema(s); err != nil {
+		return errors.Wrap(err, "applying schema")
+	}
+	if s.Generation != 0 && api.holder.matchesSchema(s) {
+		api.holder.adoptSchemaGeneration(s.Generation)
+	}
+	api.audit(ctx, &AuditRecord{Operation: "provisionSchema"})
+	return nil
 }
 
-// Views returns the views in the given field.
+// Views returns the views in the given field, sorted by name.
 func (api *API) Views(ctx context.Context, indexName string, fieldName string) ([]*view, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.Views")
 	defer span.Finish()
@@ -771,13 +1966,162 @@ func (api *API) Views(ctx context.Context, indexName string, fieldName string) (
 		return nil, ErrFieldNotFound
 	}
 
-	// Fetch views.
+	// Fetch views, sorted by name.
 	views := f.views()
+	sort.Slice(views, func(i, j int) bool { return views[i].name < views[j].name })
 	return views, nil
 }
 
-// DeleteView removes the given view.
-func (api *API) DeleteView(ctx context.Context, indexName string, fieldName string, viewName string) error {
+// RetainedSnapshots returns the snapshots retained by the given field on this
+// node, and the disk space they use.
+func (api *API) RetainedSnapshots(ctx context.Context, indexName, fieldName string) (*RetainedSnapshotsInfo, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RetainedSnapshots")
+	defer span.Finish()
+
+	if err := api.validate(apiRetainedSnapshots); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	}
+	return f.RetainedSnapshots()
+}
+
+// FieldChanges returns up to limit changes of the given field on this node
+// which follow the sequence number since. If there are none, it waits up to
+// wait for one.
+func (api *API) FieldChanges(ctx context.Context, indexName, fieldName string, since uint64, limit int, wait time.Duration) (*FieldChanges, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.FieldChanges")
+	defer span.Finish()
+
+	if err := api.validate(apiFieldChanges); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	} else if f.changes == nil {
+		return nil, NewBadRequestError(errors.Errorf("field %s does not track changes", fieldName))
+	}
+
+	changes, next, gap := f.changes.changes(ctx, since, limit, wait)
+	if changes == nil {
+		changes = []FieldChange{}
+	}
+	return &FieldChanges{
+		Node:    api.server.nodeID,
+		Changes: changes,
+		Next:    next,
+		Gap:     gap,
+	}, nil
+}
+
+// ReplicationStatus returns the state of the asynchronous replication of
+// the cluster to another cluster, including the high-water mark of each
+// fragment shipped. Only the coordinator, which replicates the cluster,
+// knows the marks.
+func (api *API) ReplicationStatus(ctx context.Context) (*ReplicationStatus, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ReplicationStatus")
+	defer span.Finish()
+
+	if err := api.validate(apiReplicationStatus); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	st := api.server.replicator.status()
+	if st == nil {
+		return nil, NewBadRequestError(ErrReplicationDisabled)
+	}
+	return st, nil
+}
+
+// StatsHistory returns the per-minute query and write statistics of an
+// index on this node over the given window, or of every index if indexName
+// is empty.
+func (api *API) StatsHistory(ctx context.Context, indexName string, window time.Duration) ([]IndexStatsHistory, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.StatsHistory")
+	defer span.Finish()
+
+	if err := api.validate(apiStatsHistory); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	if indexName != "" && api.holder.Index(indexName) == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	} else if api.server.statsHistory == nil {
+		return nil, NewBadRequestError(errors.New("stats history is disabled"))
+	} else if window <= 0 {
+		return nil, NewBadRequestError(errors.Errorf("invalid window: %s", window))
+	}
+	return api.server.statsHistory.history(indexName, window), nil
+}
+
+// FieldViews returns the views of the given field on this node, sorted by
+// name.
+func (api *API) FieldViews(ctx context.Context, indexName, fieldName string) ([]*ViewInfo, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FieldViews")
+	defer span.Finish()
+
+	if err := api.validate(apiViews); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	}
+
+	views := f.views()
+	infos := make([]*ViewInfo, 0, len(views))
+	for _, v := range views {
+		size, err := dirSize(v.path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "measuring view %s", v.name)
+		}
+		infos = append(infos, &ViewInfo{
+			Name:   v.name,
+			Shards: len(v.allFragments()),
+			Size:   size,
+		})
+	}
+	sort.Sort(viewInfoSlice(infos))
+	return infos, nil
+}
+
+// CreateView creates a view of the given field on all nodes, before data is
+// written to it. The view must be able to hold data of the field: its
+// standard view, or a time view of a unit of its time quantum.
+func (api *API) CreateView(ctx context.Context, indexName, fieldName, viewName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.CreateView")
+	defer span.Finish()
+
+	if err := api.validate(apiCreateView); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return newNotFoundError(ErrFieldNotFound)
+	} else if err := f.validateViewName(viewName); err != nil {
+		return NewBadRequestError(err)
+	} else if f.view(viewName) != nil {
+		return newConflictError(ErrViewExists)
+	}
+
+	if _, err := f.createViewIfNotExists(viewName); err != nil {
+		return errors.Wrap(err, "creating view")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "createView", Index: indexName, Field: fieldName})
+	return nil
+}
+
+// DeleteView removes the given view and its fragments on all nodes. The view
+// which holds the data of a field written without timestamps is only removed
+// if force is set.
+func (api *API) DeleteView(ctx context.Context, indexName string, fieldName string, viewName string, force bool) error {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteView")
 	defer span.Finish()
 
@@ -788,7 +2132,9 @@ func (api *API) DeleteView(ctx context.Context, indexName string, fieldName stri
 	// Retrieve field.
 	f := api.holder.Field(indexName, fieldName)
 	if f == nil {
-		return ErrFieldNotFound
+		return newNotFoundError(ErrFieldNotFound)
+	} else if viewName == f.primaryView() && !force {
+		return NewBadRequestError(errors.Errorf("view %s holds the data of field %s, set force to delete it", viewName, fieldName))
 	}
 
 	// Delete the view.
@@ -808,6 +2154,8 @@ func (api *API) DeleteView(ctx context.Context, indexName string, fieldName stri
 		})
 	if err != nil {
 		api.server.logger.Printf("problem sending DeleteView message: %s", err)
+	} else {
+		api.audit(ctx, &AuditRecord{Operation: "deleteView", Index: indexName, Field: fieldName})
 	}
 
 	return errors.Wrap(err, "sending DeleteView message")
@@ -823,10 +2171,11 @@ func (api *API) IndexAttrDiff(ctx context.Context, indexName string, blocks []At
 	}
 
 	// Retrieve index from holder.
-	index := api.holder.Index(indexName)
-	if index == nil {
-		return nil, newNotFoundError(ErrIndexNotFound)
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
+		return nil, err
 	}
+	defer index.Close()
 
 	// Retrieve local blocks.
 	localBlocks, err := index.ColumnAttrStore().Blocks()
@@ -893,6 +2242,19 @@ func (api *API) FieldAttrDiff(ctx context.Context, indexName string, fieldName s
 type ImportOptions struct {
 	Clear          bool
 	IgnoreKeyCheck bool
+
+	// OperationID identifies the import so that it is only applied once
+	// to each fragment when it is retried.
+	OperationID string
+
+	// DropTimestamps imports timestamped bits into a field without a time
+	// quantum without their timestamps, instead of rejecting them.
+	DropTimestamps bool
+
+	// allowStandardView imports bits without timestamps into a time field
+	// without a standard view, where no query reads them, with a warning
+	// instead of rejecting them.
+	allowStandardView bool
 }
 
 // ImportOption is a functional option type for API.Import.
@@ -916,8 +2278,37 @@ func OptImportOptionsIgnoreKeyCheck(b bool) ImportOption {
 	}
 }
 
+// OptImportOptionsOperationID is a functional option on ImportOption
+// used to specify a client-generated ID which makes the import idempotent.
+func OptImportOptionsOperationID(id string) ImportOption {
+	return func(o *ImportOptions) error {
+		o.OperationID = id
+		return nil
+	}
+}
+
+// OptImportOptionsDropTimestamps is a functional option on ImportOption
+// used to specify whether the timestamps of bits imported into a field
+// without a time quantum are dropped, rather than the import rejected.
+func OptImportOptionsDropTimestamps(b bool) ImportOption {
+	return func(o *ImportOptions) error {
+		o.DropTimestamps = b
+		return nil
+	}
+}
+
+// optImportOptionsAllowStandardView is a functional option on ImportOption
+// used to specify whether bits without timestamps are imported into time
+// fields without a standard view, rather than the import rejected.
+func optImportOptionsAllowStandardView(b bool) ImportOption {
+	return func(o *ImportOptions) error {
+		o.allowStandardView = b
+		return nil
+	}
+}
+
 // Import bulk imports data into a particular index,field,shard.
-func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOption) error {
+func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOption) (err error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.Import")
 	defer span.Finish()
 
@@ -926,15 +2317,48 @@ func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOp
 	}
 
 	// Set up import options.
+	opts = append(opts, optImportOptionsAllowStandardView(!api.server.strictImports))
 	options, err := setUpImportOptions(opts...)
 	if err != nil {
 		return errors.Wrap(err, "setting up import options")
 	}
 
+	// Imports which are forwarded after translating keys were recorded by
+	// the node which translated them.
+	if !options.IgnoreKeyCheck {
+		defer func() {
+			rec := &AuditRecord{Operation: "import", Index: req.Index, Field: req.Field, Count: len(req.ColumnIDs) + len(req.ColumnKeys)}
+			if options.Clear {
+				rec.Operation = "importClear"
+			}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			api.audit(ctx, rec)
+		}()
+	}
+
 	index, field, err := api.indexField(req.Index, req.Field, req.Shard)
 	if err != nil {
 		return errors.Wrap(err, "getting index and field")
 	}
+	defer index.Close()
+	if index.ReadOnly() {
+		return ErrIndexReadOnly
+	} else if !options.Clear && index.QuotaExceeded() {
+		return ErrQuotaExceeded
+	}
+
+	// Reject records without a row or column before translating keys. A
+	// value import sent to a field which isn't an int field has no rows.
+	rows, columns := len(req.RowIDs)+len(req.RowKeys), len(req.ColumnIDs)+len(req.ColumnKeys)
+	var hint string
+	if rows == 0 && len(req.Timestamps) > 0 {
+		hint = "only int fields import values"
+	}
+	if err := field.importCountError("rows", rows, columns, hint); err != nil {
+		return err
+	}
 
 	// Unless explicitly ignoring key validation (meaning keys have been
 	// translated to ids in a previous step at the coordinator node), then
@@ -966,7 +2390,7 @@ func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOp
 			m := make(map[uint64][]Bit)
 
 			for i, colID := range req.ColumnIDs {
-				shard := colID / ShardWidth
+				shard := colID / index.ShardWidth()
 				if _, ok := m[shard]; !ok {
 					m[shard] = make([]Bit, 0)
 				}
@@ -1010,10 +2434,19 @@ func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOp
 		t := time.Unix(0, ts).UTC()
 		timestamps[i] = &t
 	}
+	if timestamps, err = field.validateImport(req.RowIDs, req.ColumnIDs, timestamps, options); err != nil {
+		return err
+	}
+
+	newShard := !options.Clear && field.shardUnannounced(req.Shard)
+
+	// Keep snapshot reads from observing part of the import.
+	api.server.executor.snapshotGate.enter()
+	defer api.server.executor.snapshotGate.exit()
 
 	// Import columnIDs into existence field.
 	if !options.Clear {
-		if err := importExistenceColumns(index, req.ColumnIDs); err != nil {
+		if err := importExistenceColumns(index.Index, req.ColumnIDs); err != nil {
 			api.server.logger.Printf("import existence error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
 			return errors.Wrap(err, "importing existence columns")
 		}
@@ -1023,12 +2456,16 @@ func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOp
 	err = field.Import(req.RowIDs, req.ColumnIDs, timestamps, opts...)
 	if err != nil {
 		api.server.logger.Printf("import error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
+		return errors.Wrap(err, "importing")
+	}
+	if newShard {
+		return field.announceShard(req.Shard)
 	}
-	return errors.Wrap(err, "importing")
+	return nil
 }
 
 // ImportValue bulk imports values into a particular field.
-func (api *API) ImportValue(ctx context.Context, req *ImportValueRequest, opts ...ImportOption) error {
+func (api *API) ImportValue(ctx context.Context, req *ImportValueRequest, opts ...ImportOption) (err error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.ImportValue")
 	defer span.Finish()
 
@@ -1042,10 +2479,37 @@ func (api *API) ImportValue(ctx context.Context, req *ImportValueRequest, opts .
 		return errors.Wrap(err, "setting up import options")
 	}
 
+	// Imports which are forwarded after translating keys were recorded by
+	// the node which translated them.
+	if !options.IgnoreKeyCheck {
+		defer func() {
+			rec := &AuditRecord{Operation: "importValue", Index: req.Index, Field: req.Field, Count: len(req.Values)}
+			if options.Clear {
+				rec.Operation = "importValueClear"
+			}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			api.audit(ctx, rec)
+		}()
+	}
+
 	index, field, err := api.indexField(req.Index, req.Field, req.Shard)
 	if err != nil {
 		return errors.Wrap(err, "getting index and field")
 	}
+	defer index.Close()
+	if index.ReadOnly() {
+		return ErrIndexReadOnly
+	} else if !options.Clear && index.QuotaExceeded() {
+		return ErrQuotaExceeded
+	}
+
+	// Reject records without a value before translating keys. A bit import
+	// sent to an int field has no values.
+	if err := field.importCountError("values", len(req.Values), len(req.ColumnIDs)+len(req.ColumnKeys), "int fields only import values"); err != nil {
+		return err
+	}
 
 	// Unless explicitly ignoring key validation (meaning keys have been
 	// translate to ids in a previous step at the coordinator node), then
@@ -1065,7 +2529,7 @@ func (api *API) ImportValue(ctx context.Context, req *ImportValueRequest, opts .
 			m := make(map[uint64][]FieldValue)
 
 			for i, colID := range req.ColumnIDs {
-				shard := colID / ShardWidth
+				shard := colID / index.ShardWidth()
 				if _, ok := m[shard]; !ok {
 					m[shard] = make([]FieldValue, 0)
 				}
@@ -1096,9 +2560,19 @@ func (api *API) ImportValue(ctx context.Context, req *ImportValueRequest, opts .
 		return errors.Wrap(err, "validating shard ownership")
 	}
 
+	newShard := !options.Clear && field.shardUnannounced(req.Shard)
+
+	if err := field.validateImportValues(req.ColumnIDs, req.Values); err != nil {
+		return err
+	}
+
+	// Keep snapshot reads from observing part of the import.
+	api.server.executor.snapshotGate.enter()
+	defer api.server.executor.snapshotGate.exit()
+
 	// Import columnIDs into existence field.
 	if !options.Clear {
-		if err := importExistenceColumns(index, req.ColumnIDs); err != nil {
+		if err := importExistenceColumns(index.Index, req.ColumnIDs); err != nil {
 			api.server.logger.Printf("import existence error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
 			return errors.Wrap(err, "importing existence columns")
 		}
@@ -1108,8 +2582,12 @@ func (api *API) ImportValue(ctx context.Context, req *ImportValueRequest, opts .
 	err = field.importValue(req.ColumnIDs, req.Values, options)
 	if err != nil {
 		api.server.logger.Printf("import error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
+		return errors.Wrap(err, "importing")
+	}
+	if newShard {
+		return field.announceShard(req.Shard)
 	}
-	return errors.Wrap(err, "importing")
+	return nil
 }
 
 func importExistenceColumns(index *Index, columnIDs []uint64) error {
@@ -1158,37 +2636,51 @@ func (api *API) LongQueryTime() time.Duration {
 	if api.cluster == nil {
 		return 0
 	}
-	return api.cluster.longQueryTime
+	return api.cluster.LongQueryTime()
 }
 
 func (api *API) validateShardOwnership(indexName string, shard uint64) error {
 	// Validate that this handler owns the shard.
-	if !api.cluster.ownsShard(api.Node().ID, indexName, shard) {
+	owners := api.cluster.preferredShardNodes(indexName, shard)
+	if !Nodes(owners).ContainsID(api.Node().ID) {
 		api.server.logger.Printf("node %s does not own shard %d of index %s", api.Node().ID, shard, indexName)
-		return ErrClusterDoesNotOwnShard
+		return &ShardOwnerError{Index: indexName, Shard: shard, Owners: owners}
 	}
 	return nil
 }
 
-func (api *API) indexField(indexName string, fieldName string, shard uint64) (*Index, *Field, error) {
+// indexField returns a handle to the index by name, which must be closed, and
+// its field by name.
+func (api *API) indexField(indexName string, fieldName string, shard uint64) (*IndexHandle, *Field, error) {
 	api.server.logger.Debugf("importing: %v %v %v", indexName, fieldName, shard)
 
 	// Find the Index.
-	index := api.holder.Index(indexName)
-	if index == nil {
+	index, err := api.acquireIndex(indexName)
+	if err != nil {
 		api.server.logger.Printf("fragment error: index=%s, field=%s, shard=%d, err=%s", indexName, fieldName, shard, ErrIndexNotFound.Error())
-		return nil, nil, newNotFoundError(ErrIndexNotFound)
+		return nil, nil, err
 	}
 
 	// Retrieve field.
 	field := index.Field(fieldName)
 	if field == nil {
+		index.Close()
 		api.server.logger.Printf("field error: index=%s, field=%s, shard=%d, err=%s", indexName, fieldName, shard, ErrFieldNotFound.Error())
 		return nil, nil, ErrFieldNotFound
 	}
 	return index, field, nil
 }
 
+// acquireIndex returns a handle to the index by name, which keeps the index
+// from being closed by its deletion until the handle is closed.
+func (api *API) acquireIndex(indexName string) (*IndexHandle, error) {
+	index, err := api.holder.AcquireIndex(indexName)
+	if err != nil {
+		return nil, newNotFoundError(err)
+	}
+	return index, nil
+}
+
 // SetCoordinator makes a new Node the cluster coordinator.
 func (api *API) SetCoordinator(ctx context.Context, id string) (oldNode, newNode *Node, err error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.SetCoordinator")
@@ -1256,6 +2748,109 @@ func (api *API) ResizeAbort() error {
 	return errors.Wrap(err, "complete current job")
 }
 
+// ClusterConfig returns the settings which can be overridden for the whole
+// cluster, their cluster-level values, and their values on each node.
+func (api *API) ClusterConfig(ctx context.Context) (*ClusterConfig, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.ClusterConfig")
+	defer span.Finish()
+
+	if err := api.validate(apiClusterConfig); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	// Read the settings of every node concurrently. A node which can't be
+	// reached is reported rather than failing the request.
+	nodes := api.cluster.Nodes()
+	settings := make([]map[string]*NodeSetting, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		if node.ID == api.server.nodeID {
+			settings[i] = api.server.nodeSettings()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, node *Node) {
+			defer wg.Done()
+			settings[i], errs[i] = api.server.defaultClient.NodeSettings(ctx, &node.URI)
+		}(i, node)
+	}
+	wg.Wait()
+
+	overrides := api.server.settings.Overrides()
+	config := &ClusterConfig{Settings: make([]*ClusterSetting, len(clusterSettings))}
+	for i, cs := range clusterSettings {
+		setting := &ClusterSetting{
+			Name:            cs.name,
+			RestartRequired: cs.restart,
+		}
+		if v, ok := overrides[cs.name]; ok {
+			setting.Value = &v
+		}
+		for j, node := range nodes {
+			ns := settings[j][cs.name]
+			if errs[j] != nil {
+				ns = &NodeSetting{Error: errs[j].Error()}
+			} else if ns == nil {
+				ns = &NodeSetting{Error: "setting not supported"}
+			}
+			ns.NodeID = node.ID
+			setting.Nodes = append(setting.Nodes, ns)
+		}
+		config.Settings[i] = setting
+	}
+	return config, nil
+}
+
+// UpdateClusterConfig sets the cluster-level values of settings, which
+// override the configuration of each node. A nil value removes the
+// cluster-level value, so that the configuration of each node applies again.
+// Settings which are not given keep their cluster-level values. Only the
+// coordinator accepts updates, which it broadcasts to the other nodes.
+func (api *API) UpdateClusterConfig(ctx context.Context, values map[string]*string) (*ClusterConfig, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.UpdateClusterConfig")
+	defer span.Finish()
+
+	if err := api.validate(apiUpdateClusterConfig); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	} else if !api.cluster.isCoordinator() {
+		return nil, ErrNodeNotCoordinator
+	}
+
+	api.server.settings.updateMu.Lock()
+	defer api.server.settings.updateMu.Unlock()
+
+	overrides := api.server.settings.Overrides()
+	for name, v := range values {
+		cs := clusterSettingByName(name)
+		if cs == nil {
+			return nil, NewBadRequestError(errors.Errorf("unknown setting: %s", name))
+		} else if v == nil {
+			delete(overrides, name)
+			continue
+		}
+		value, err := cs.normalize(*v)
+		if err != nil {
+			return nil, NewBadRequestError(errors.Wrapf(err, "setting %s", name))
+		}
+		overrides[name] = value
+	}
+
+	if err := api.server.updateSettings(overrides); err != nil {
+		return nil, errors.Wrap(err, "updating settings")
+	} else if err := api.server.SendSync(&ClusterSettingsMessage{Settings: overrides}); err != nil {
+		return nil, errors.Wrap(err, "broadcasting settings")
+	}
+	api.audit(ctx, &AuditRecord{Operation: "updateClusterConfig", Count: len(values)})
+	return api.ClusterConfig(ctx)
+}
+
+// NodeSettings returns the values of the settings which can be overridden for
+// the whole cluster on this node, by name.
+func (api *API) NodeSettings() map[string]*NodeSetting {
+	return api.server.nodeSettings()
+}
+
 // State returns the cluster state which is usually "NORMAL", but could be
 // "STARTING", "RESIZING", or potentially others. See cluster.go for more
 // details.
@@ -1263,6 +2858,64 @@ func (api *API) State() string {
 	return api.cluster.State()
 }
 
+// ProtocolVersion returns the effective protocol version of the cluster:
+// the newest version all of its nodes speak.
+func (api *API) ProtocolVersion() uint32 {
+	return api.cluster.ProtocolVersion()
+}
+
+// SetMaintenance turns maintenance mode of this node on or off. While in
+// maintenance, the other nodes prefer its replicas for reads, clients are
+// routed to its replicas, and it doesn't start anti-entropy. Writes are still
+// replicated to it.
+func (api *API) SetMaintenance(ctx context.Context, maintenance bool) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetMaintenance")
+	defer span.Finish()
+
+	if err := api.validate(apiSetMaintenance); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	return errors.Wrap(api.cluster.setMaintenance(maintenance), "setting maintenance")
+}
+
+// Readiness describes whether a node should receive traffic.
+type Readiness struct {
+	Ready       bool   `json:"ready"`
+	State       string `json:"state"`
+	Maintenance bool   `json:"maintenance"`
+	WarmingUp   bool   `json:"warmingUp,omitempty"`
+
+	// Warmup is the progress of the warm-up queries of the node, if it
+	// has any.
+	Warmup *WarmupStatus `json:"warmup,omitempty"`
+}
+
+// Readiness returns whether the node should receive traffic, which it
+// should while the cluster serves queries, the node isn't in maintenance,
+// and the node isn't warming up within the warm-up timeout.
+func (api *API) Readiness() Readiness {
+	r := Readiness{
+		State:       api.cluster.State(),
+		Maintenance: api.cluster.inMaintenance(),
+		WarmingUp:   api.server.warmup.isBlocking(),
+		Warmup:      api.server.warmup.status(),
+	}
+	r.Ready = (r.State == ClusterStateNormal || r.State == ClusterStateDegraded) && !r.Maintenance && !r.WarmingUp
+	return r
+}
+
+// ResourceUsage returns the node's open file and mmap usage.
+func (api *API) ResourceUsage() ResourceUsage {
+	return api.holder.resourceUsage()
+}
+
+// AdmissionStatus returns the memory pressure of the node and the number of
+// queries it rejected because of it.
+func (api *API) AdmissionStatus() AdmissionStatus {
+	return api.server.executor.admission.status()
+}
+
 // Version returns the Pilosa version.
 func (api *API) Version() string {
 	return strings.TrimPrefix(Version, "v")
@@ -1374,11 +3027,52 @@ const (
 	//apiVersion // not implemented
 	apiViews
 	apiApplySchema
+	apiTransaction
+	apiSetIndexReadOnly
+	apiFragmentBlockPairs
+	apiSetFieldTimeQuantum
+	apiDeleteSession
+	apiCreateIngestMapping
+	apiIngestMapping
+	apiDeleteIngestMapping
+	apiIngest
+	apiClusterConfig
+	apiUpdateClusterConfig
+	apiSetIndexQuota
+	apiExportSchema
+	apiProvisionSchema
+	apiCreateDeleteJob
+	apiDeleteJob
+	apiResumeDeleteJob
+	apiFragmentInspect
+	apiTrash
+	apiRestoreIndex
+	apiPurgeTrash
+	apiRetainedSnapshots
+	apiRebuildExistence
+	apiCreateWarmJob
+	apiWarmJob
+	apiResumeWarmJob
+	apiSetMaintenance
+	apiFieldChanges
+	apiStatsHistory
+	apiCreateView
+	apiRoutingTable
+	apiCompactFragment
+	apiSetIndexDefaultFieldOptions
+	apiReplicationStatus
 )
 
 var methodsCommon = map[apiMethod]struct{}{
-	apiClusterMessage: {},
-	apiSetCoordinator: {},
+	apiClusterMessage:      {},
+	apiSetCoordinator:      {},
+	apiClusterConfig:       {},
+	apiUpdateClusterConfig: {},
+	apiExportSchema:        {},
+	apiDeleteJob:           {},
+	apiWarmJob:             {},
+	apiSetMaintenance:      {},
+	apiStatsHistory:        {},
 }
 
 var methodsResizing = map[apiMethod]struct{}{
@@ -1387,25 +3081,52 @@ var methodsResizing = map[apiMethod]struct{}{
 }
 
 var methodsNormal = map[apiMethod]struct{}{
-	apiCreateField:          {},
-	apiCreateIndex:          {},
-	apiDeleteField:          {},
-	apiDeleteAvailableShard: {},
-	apiDeleteIndex:          {},
-	apiDeleteView:           {},
-	apiExportCSV:            {},
-	apiFragmentBlockData:    {},
-	apiFragmentBlocks:       {},
-	apiField:                {},
-	apiFieldAttrDiff:        {},
-	apiImport:               {},
-	apiImportValue:          {},
-	apiIndex:                {},
-	apiIndexAttrDiff:        {},
-	apiQuery:                {},
-	apiRecalculateCaches:    {},
-	apiRemoveNode:           {},
-	apiShardNodes:           {},
-	apiViews:                {},
-	apiApplySchema:          {},
+	apiCreateField:                 {},
+	apiCreateIndex:                 {},
+	apiDeleteField:                 {},
+	apiDeleteAvailableShard:        {},
+	apiDeleteIndex:                 {},
+	apiDeleteView:                  {},
+	apiExportCSV:                   {},
+	apiFragmentBlockData:           {},
+	apiFragmentBlocks:              {},
+	apiFragmentInspect:             {},
+	apiField:                       {},
+	apiFieldAttrDiff:               {},
+	apiImport:                      {},
+	apiImportValue:                 {},
+	apiIndex:                       {},
+	apiIndexAttrDiff:               {},
+	apiQuery:                       {},
+	apiRecalculateCaches:           {},
+	apiRemoveNode:                  {},
+	apiShardNodes:                  {},
+	apiViews:                       {},
+	apiApplySchema:                 {},
+	apiTransaction:                 {},
+	apiSetIndexReadOnly:            {},
+	apiSetIndexQuota:               {},
+	apiProvisionSchema:             {},
+	apiFragmentBlockPairs:          {},
+	apiSetFieldTimeQuantum:         {},
+	apiDeleteSession:               {},
+	apiCreateIngestMapping:         {},
+	apiIngestMapping:               {},
+	apiDeleteIngestMapping:         {},
+	apiIngest:                      {},
+	apiCreateDeleteJob:             {},
+	apiResumeDeleteJob:             {},
+	apiTrash:                       {},
+	apiRestoreIndex:                {},
+	apiPurgeTrash:                  {},
+	apiRetainedSnapshots:           {},
+	apiRebuildExistence:            {},
+	apiCreateWarmJob:               {},
+	apiResumeWarmJob:               {},
+	apiFieldChanges:                {},
+	apiCreateView:                  {},
+	apiRoutingTable:                {},
+	apiCompactFragment:             {},
+	apiSetIndexDefaultFieldOptions: {},
+	apiReplicationStatus:           {},
 }
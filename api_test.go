@@ -15,19 +15,24 @@
 package pilosa_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/pilosa/pilosa/v2"
 	"github.com/pilosa/pilosa/v2/boltdb"
 	"github.com/pilosa/pilosa/v2/http"
+	"github.com/pilosa/pilosa/v2/roaring"
 	"github.com/pilosa/pilosa/v2/server"
 	"github.com/pilosa/pilosa/v2/test"
+	"github.com/pkg/errors"
 )
 
 func TestAPI_Import(t *testing.T) {
@@ -181,6 +186,53 @@ func TestAPI_Import(t *testing.T) {
 			t.Fatalf("unexpected column ids: %+v", columns)
 		}
 	})
+
+	t.Run("OperationID", func(t *testing.T) {
+		ctx := context.Background()
+		if _, err := m0.API.CreateIndex(ctx, "opid", pilosa.IndexOptions{}); err != nil {
+			t.Fatalf("creating index: %v", err)
+		} else if _, err := m0.API.CreateField(ctx, "opid", "f", pilosa.OptFieldKeys()); err != nil {
+			t.Fatalf("creating field: %v", err)
+		}
+
+		// Keyed imports are forwarded to the owner of each shard, which
+		// must receive the operation ID.
+		importOp := func(id string) {
+			t.Helper()
+			req := &pilosa.ImportRequest{
+				Index:     "opid",
+				Field:     "f",
+				RowKeys:   []string{"x", "x"},
+				ColumnIDs: []uint64{1, pilosa.ShardWidth + 1},
+			}
+			if err := m0.API.Import(ctx, req, pilosa.OptImportOptionsOperationID(id)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		query := func(exp []uint64) {
+			t.Helper()
+			for _, m := range []*test.Command{m0, m1} {
+				if res, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "opid", Query: `Row(f="x")`}); err != nil {
+					t.Fatal(err)
+				} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, exp) {
+					t.Fatalf("unexpected column ids: %+v", columns)
+				}
+			}
+		}
+
+		importOp("a")
+		if _, err := m0.API.Query(ctx, &pilosa.QueryRequest{Index: "opid", Query: `Clear(1, f="x")`}); err != nil {
+			t.Fatal(err)
+		}
+		query([]uint64{pilosa.ShardWidth + 1})
+
+		// Retrying the operation is a no-op.
+		importOp("a")
+		query([]uint64{pilosa.ShardWidth + 1})
+
+		importOp("b")
+		query([]uint64{1, pilosa.ShardWidth + 1})
+	})
 }
 
 func TestAPI_ImportValue(t *testing.T) {
@@ -260,9 +312,1283 @@ func TestAPI_ImportValue(t *testing.T) {
 	})
 }
 
+func TestAPI_Transaction(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "m", pilosa.OptFieldTypeMutex(pilosa.CacheTypeRanked, 100))
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "v", pilosa.OptFieldTypeInt(-100, 100))
+	c.Query(t, "i", fmt.Sprintf(`Set(1, m=3) Set(%d, f=7)`, 2*ShardWidth+5))
+
+	// queryAll verifies a query returns the same columns on every node.
+	queryAll := func(t *testing.T, query string, exp []uint64) {
+		t.Helper()
+		for i := range c {
+			res, err := c[i].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query})
+			if err != nil {
+				t.Fatal(err)
+			} else if cols := res.Results[0].(*pilosa.Row).Columns(); len(cols) != len(exp) || (len(exp) > 0 && !reflect.DeepEqual(cols, exp)) {
+				t.Fatalf("node %d: unexpected columns for %s: %v", i, query, cols)
+			}
+		}
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		err := c[1].API.Transaction(ctx, "i", fmt.Sprintf(`
+			Set(1, f=1)
+			Set(%d, f=1)
+			Set(%d, v=42)
+			Clear(%d, f=7)
+			Set(1, m=4)
+			SetRowAttrs(f, 1, name="one")`, ShardWidth+2, 2*ShardWidth+3, 2*ShardWidth+5), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		queryAll(t, "Row(f=1)", []uint64{1, ShardWidth + 2})
+		queryAll(t, "Row(v==42)", []uint64{2*ShardWidth + 3})
+		queryAll(t, "Row(f=7)", nil)
+		queryAll(t, "Row(m=3)", nil)
+		queryAll(t, "Row(m=4)", []uint64{1})
+		queryAll(t, "Not(Row(f=0))", []uint64{1, ShardWidth + 2, 2*ShardWidth + 3, 2*ShardWidth + 5})
+
+		res := c.Query(t, "i", "Row(f=1)")
+		if attrs := res.Results[0].(*pilosa.Row).Attrs; !reflect.DeepEqual(attrs, map[string]interface{}{"name": "one"}) {
+			t.Fatalf("unexpected attrs: %v", attrs)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		for _, query := range []string{
+			fmt.Sprintf(`Set(5, f=9) Set(%d, v=1000)`, ShardWidth+6),
+			fmt.Sprintf(`Set(5, f=9) Set(%d, unknown=1)`, ShardWidth+6),
+			`Set(5, f=9) Row(f=1)`,
+			`Set(5, f=9) Clear(5, v=1)`,
+		} {
+			if err := c[0].API.Transaction(ctx, "i", query, ""); !isBadRequestError(err) {
+				t.Fatalf("expected bad request error for %s, got %v", query, err)
+			}
+		}
+		queryAll(t, "Row(f=9)", nil)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		var buf strings.Builder
+		for i := 0; i <= pilosa.MaxTransactionCalls; i++ {
+			fmt.Fprintf(&buf, "Set(%d, f=10)", i)
+		}
+		if err := c[0].API.Transaction(ctx, "i", buf.String(), ""); !isBadRequestError(err) {
+			t.Fatalf("expected bad request error, got %v", err)
+		}
+		queryAll(t, "Row(f=10)", nil)
+	})
+
+	t.Run("OperationID", func(t *testing.T) {
+		query := fmt.Sprintf(`Set(1, f=11) Set(%d, f=11)`, ShardWidth+1)
+		if err := c[0].API.Transaction(ctx, "i", query, "tx1"); err != nil {
+			t.Fatal(err)
+		}
+		c.Query(t, "i", `Clear(1, f=11)`)
+
+		// Retrying the transaction is a no-op, even from another node.
+		if err := c[2].API.Transaction(ctx, "i", query, "tx1"); err != nil {
+			t.Fatal(err)
+		}
+		queryAll(t, "Row(f=11)", []uint64{ShardWidth + 1})
+
+		if err := c[2].API.Transaction(ctx, "i", query, "tx2"); err != nil {
+			t.Fatal(err)
+		}
+		queryAll(t, "Row(f=11)", []uint64{1, ShardWidth + 1})
+	})
+
+	t.Run("IndexNotFound", func(t *testing.T) {
+		if err := c[0].API.Transaction(ctx, "missing", "Set(1, f=1)", ""); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_SetIndexReadOnly(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "v", pilosa.OptFieldTypeInt(0, 100))
+	c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(%d, f=1)`, ShardWidth+1))
+
+	if err := c[1].API.SetIndexReadOnly(ctx, "i", true); err != nil {
+		t.Fatal(err)
+	}
+	for i := range c {
+		idx, err := c[i].API.Index(ctx, "i")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+		if !idx.ReadOnly() {
+			t.Fatalf("node %d: expected index to be read-only", i)
+		}
+	}
+
+	t.Run("RejectWrites", func(t *testing.T) {
+		for i := range c {
+			for _, query := range []string{`Set(2, f=1)`, `Clear(1, f=1)`, `ClearRow(f=1)`, `SetColumnAttrs(1, x=1)`} {
+				if _, err := c[i].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query}); errors.Cause(err) != pilosa.ErrIndexReadOnly {
+					t.Fatalf("node %d: expected read-only error for %s, got %v", i, query, err)
+				}
+			}
+		}
+		if err := c[0].API.Import(ctx, &pilosa.ImportRequest{Index: "i", Field: "f", RowIDs: []uint64{1}, ColumnIDs: []uint64{2}}); errors.Cause(err) != pilosa.ErrIndexReadOnly {
+			t.Fatalf("expected read-only error, got %v", err)
+		} else if err := c[0].API.ImportValue(ctx, &pilosa.ImportValueRequest{Index: "i", Field: "v", ColumnIDs: []uint64{2}, Values: []int64{3}}); errors.Cause(err) != pilosa.ErrIndexReadOnly {
+			t.Fatalf("expected read-only error, got %v", err)
+		} else if err := c[0].API.Transaction(ctx, "i", `Set(2, f=1)`, ""); !isConflictError(err) {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+	})
+
+	t.Run("AllowReads", func(t *testing.T) {
+		for i := range c {
+			res, err := c[i].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))"})
+			if err != nil {
+				t.Fatal(err)
+			} else if n := res.Results[0].(uint64); n != 2 {
+				t.Fatalf("node %d: unexpected count: %d", i, n)
+			}
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		for _, idx := range c[2].API.Schema(ctx) {
+			if idx.Name == "i" && !idx.Options.ReadOnly {
+				t.Fatal("expected read-only option in schema")
+			}
+		}
+	})
+
+	t.Run("Writable", func(t *testing.T) {
+		if err := c[2].API.SetIndexReadOnly(ctx, "i", false); err != nil {
+			t.Fatal(err)
+		}
+		c.Query(t, "i", `Set(2, f=1)`)
+		if res := c.Query(t, "i", "Count(Row(f=1))"); res.Results[0].(uint64) != 3 {
+			t.Fatalf("unexpected count: %v", res.Results[0])
+		}
+	})
+
+	t.Run("IndexNotFound", func(t *testing.T) {
+		if err := c[0].API.SetIndexReadOnly(ctx, "missing", true); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_SetIndexQuota(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "v", pilosa.OptFieldTypeInt(0, 100))
+	c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(%d, f=1)`, ShardWidth+1))
+
+	if err := c[1].API.SetIndexQuota(ctx, "i", 1); err != nil {
+		t.Fatal(err)
+	}
+	for i := range c {
+		idx, err := c[i].API.Index(ctx, "i")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+		if idx.Quota() != 1 || !idx.QuotaExceeded() {
+			t.Fatalf("node %d: expected quota to be exceeded", i)
+		}
+	}
+
+	t.Run("RejectWrites", func(t *testing.T) {
+		for i := range c {
+			for _, query := range []string{`Set(2, f=1)`, `SetColumnAttrs(1, x=1)`, `Clear(1, f=1) Set(2, f=1)`} {
+				if _, err := c[i].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query}); errors.Cause(err) != pilosa.ErrQuotaExceeded {
+					t.Fatalf("node %d: expected quota error for %s, got %v", i, query, err)
+				}
+			}
+		}
+		if err := c[0].API.Import(ctx, &pilosa.ImportRequest{Index: "i", Field: "f", RowIDs: []uint64{1}, ColumnIDs: []uint64{2}}); errors.Cause(err) != pilosa.ErrQuotaExceeded {
+			t.Fatalf("expected quota error, got %v", err)
+		} else if err := c[0].API.ImportValue(ctx, &pilosa.ImportValueRequest{Index: "i", Field: "v", ColumnIDs: []uint64{2}, Values: []int64{3}}); errors.Cause(err) != pilosa.ErrQuotaExceeded {
+			t.Fatalf("expected quota error, got %v", err)
+		} else if err := c[0].API.Transaction(ctx, "i", `Set(2, f=1)`, ""); errors.Cause(err) != pilosa.ErrQuotaExceeded {
+			t.Fatalf("expected quota error, got %v", err)
+		}
+	})
+
+	t.Run("AllowReads", func(t *testing.T) {
+		for i := range c {
+			res, err := c[i].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))"})
+			if err != nil {
+				t.Fatal(err)
+			} else if n := res.Results[0].(uint64); n != 2 {
+				t.Fatalf("node %d: unexpected count: %d", i, n)
+			}
+		}
+	})
+
+	t.Run("AllowClears", func(t *testing.T) {
+		c.Query(t, "i", `Clear(1, f=1)`)
+		for i := range c {
+			req := &pilosa.ImportRequest{Index: "i", Field: "f", RowIDs: []uint64{1}, ColumnIDs: []uint64{3}}
+			if err := c[i].API.Import(ctx, req, pilosa.OptImportOptionsClear(true)); errors.Cause(err) == pilosa.ErrClusterDoesNotOwnShard {
+				continue
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if res := c.Query(t, "i", "Count(Row(f=1))"); res.Results[0].(uint64) != 1 {
+			t.Fatalf("unexpected count: %v", res.Results[0])
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		for _, idx := range c[2].API.Schema(ctx) {
+			if idx.Name == "i" && idx.Options.Quota != 1 {
+				t.Fatalf("unexpected quota in schema: %d", idx.Options.Quota)
+			}
+		}
+	})
+
+	t.Run("RemoveQuota", func(t *testing.T) {
+		if err := c[2].API.SetIndexQuota(ctx, "i", 0); err != nil {
+			t.Fatal(err)
+		}
+		c.Query(t, "i", `Set(2, f=1)`)
+		if res := c.Query(t, "i", "Count(Row(f=1))"); res.Results[0].(uint64) != 2 {
+			t.Fatalf("unexpected count: %v", res.Results[0])
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if err := c[0].API.SetIndexQuota(ctx, "missing", 1); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		} else if err := c[0].API.SetIndexQuota(ctx, "i", -1); !isBadRequestError(err) {
+			t.Fatalf("expected bad request error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_SetIndexDefaultFieldOptions(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+	d := &pilosa.DefaultFieldOptions{CacheType: pilosa.CacheTypeRanked, CacheSize: 100, TimeQuantum: "YMD"}
+	if err := c[1].API.SetIndexDefaultFieldOptions(ctx, "i", d); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c[0].API.CreateField(ctx, "i", "t", pilosa.OptFieldTypeTime("")); err != nil {
+		t.Fatal(err)
+	} else if _, err := c[0].API.CreateField(ctx, "i", "s", pilosa.OptFieldTypeSet("", 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every node has the defaults and creates identical fields.
+	for i := range c {
+		idx, err := c[i].API.Index(ctx, "i")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+		if got := idx.DefaultFieldOptions(); got == nil || *got != *d {
+			t.Fatalf("node %d: unexpected default field options: %+v", i, got)
+		} else if q := idx.Field("t").TimeQuantum(); q != "YMD" {
+			t.Fatalf("node %d: unexpected time quantum: %s", i, q)
+		} else if opt := idx.Field("s").Options(); opt.CacheType != pilosa.CacheTypeRanked || opt.CacheSize != 10 {
+			t.Fatalf("node %d: unexpected cache: %s %d", i, opt.CacheType, opt.CacheSize)
+		} else if opt := idx.Field("f").Options(); opt.CacheSize != pilosa.DefaultCacheSize {
+			t.Fatalf("node %d: unexpected cache size of existing field: %d", i, opt.CacheSize)
+		}
+	}
+	for _, idx := range c[2].API.Schema(ctx) {
+		if idx.Name == "i" && (idx.Options.DefaultFieldOptions == nil || *idx.Options.DefaultFieldOptions != *d) {
+			t.Fatalf("unexpected default field options in schema: %+v", idx.Options.DefaultFieldOptions)
+		}
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		if err := c[0].API.SetIndexDefaultFieldOptions(ctx, "missing", d); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		} else if err := c[0].API.SetIndexDefaultFieldOptions(ctx, "i", &pilosa.DefaultFieldOptions{TimeQuantum: "DY"}); !isBadRequestError(err) {
+			t.Fatalf("expected bad request error, got %v", err)
+		} else if err := c[0].API.SetIndexDefaultFieldOptions(ctx, "i", nil); err != nil {
+			t.Fatal(err)
+		} else if _, err := c[0].API.CreateField(ctx, "i", "t2", pilosa.OptFieldTypeTime("")); !isBadRequestError(err) {
+			t.Fatalf("expected bad request error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_Warmup(t *testing.T) {
+	opt := pilosa.WarmupOptions{
+		Queries: []pilosa.WarmupQuery{
+			{Index: "i", Query: "TopN(f)"},
+			{Index: "i", Query: "Count(Row(f=1))"},
+			{Index: "i", Query: "Set(1, f=1)"},
+		},
+		Timeout: time.Minute,
+	}
+	c := test.MustRunCluster(t, 1, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerWarmup(opt))})
+	defer c.Close()
+
+	waitWarmup := func(failed int) {
+		t.Helper()
+		if err := test.RetryUntil(5*time.Second, func() error {
+			r := c[0].API.Readiness()
+			if !r.Ready || r.WarmingUp {
+				return errors.Errorf("not ready: %+v", r)
+			} else if r.Warmup == nil || *r.Warmup != (pilosa.WarmupStatus{Queries: 3, Done: 3, Failed: failed}) {
+				return errors.Errorf("unexpected warm-up status: %+v", r.Warmup)
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The index doesn't exist yet.
+	waitWarmup(3)
+
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.ImportBits(t, "i", "f", [][2]uint64{{1, 1}, {1, 2}, {2, ShardWidth + 1}})
+	if err := c[0].Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the write query fails, and it doesn't write.
+	waitWarmup(1)
+	if res := c.Query(t, "i", "Count(Row(f=1))"); res.Results[0].(uint64) != 2 {
+		t.Fatalf("unexpected count: %v", res.Results[0])
+	}
+}
+
+func TestAPI_Replication(t *testing.T) {
+	dr := test.MustRunCluster(t, 1)
+	defer dr.Close()
+
+	target := dr[0].API.Node().URI
+	opt := pilosa.ReplicationOptions{
+		Target:            &target,
+		Client:            http.NewInternalClientFromURI(&target, http.GetHTTPClient(nil)),
+		Interval:          10 * time.Millisecond,
+		ReconcileInterval: 50 * time.Millisecond,
+	}
+	c := test.MustRunCluster(t, 2, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplication(opt))})
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f", pilosa.OptFieldTrackChanges())
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "g")
+	c.ImportBits(t, "i", "f", [][2]uint64{{1, 1}, {1, 2}, {1, ShardWidth + 1}, {2, 3}})
+	c.ImportBits(t, "i", "g", [][2]uint64{{1, 1}, {1, ShardWidth + 2}})
+
+	waitCount := func(query string, n uint64) {
+		t.Helper()
+		if err := test.RetryUntil(5*time.Second, func() error {
+			res, err := dr[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query})
+			if err != nil {
+				return err
+			} else if got := res.Results[0].(uint64); got != n {
+				return errors.Errorf("%s: expected %d, got %d", query, n, got)
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitCount("Count(Row(f=1))", 3)
+	waitCount("Count(Row(f=2))", 1)
+	waitCount("Count(Row(g=1))", 2)
+
+	// The index is read-only in the other cluster.
+	idx, err := dr[0].API.Index(ctx, "i")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if !idx.ReadOnly() {
+		t.Fatal("expected replicated index to be read-only")
+	}
+	if _, err := dr[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Set(10, f=1)"}); errors.Cause(err) != pilosa.ErrIndexReadOnly {
+		t.Fatalf("expected read-only error, got %v", err)
+	}
+
+	// A bit set only in the other cluster is cleared once the fragment
+	// changes, along with the bits cleared here.
+	var buf bytes.Buffer
+	if _, err := roaring.NewBitmap(1*ShardWidth + 4).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := dr[0].API.ImportRoaring(ctx, "i", "f", 0, true, &pilosa.ImportRoaringRequest{Views: map[string][]byte{"": buf.Bytes()}}); err != nil {
+		t.Fatal(err)
+	}
+	waitCount("Count(Row(f=1))", 4)
+	c.Query(t, "i", "Clear(1, f=1)")
+	waitCount("Count(Row(f=1))", 2)
+
+	// The coordinator reports the high-water marks of the fragments.
+	st, err := c[0].API.ReplicationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if !st.Active || !st.Bootstrapped || st.Target != target.String() {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+	marks := make(map[string]time.Time)
+	for _, m := range st.Fragments {
+		marks[fmt.Sprintf("%s/%s/%s/%d", m.Index, m.Field, m.View, m.Shard)] = m.Mark
+	}
+	for _, k := range []string{"i/f/standard/0", "i/f/standard/1", "i/g/standard/0", "i/g/standard/1"} {
+		if marks[k].IsZero() {
+			t.Fatalf("expected a mark for %s, got %+v", k, st.Fragments)
+		}
+	}
+	if st, err := c[1].API.ReplicationStatus(ctx); err != nil {
+		t.Fatal(err)
+	} else if st.Active {
+		t.Fatalf("expected inactive replication on node 1: %+v", st)
+	}
+	if _, err := dr[0].API.ReplicationStatus(ctx); !isBadRequestError(err) {
+		t.Fatalf("expected disabled replication, got %v", err)
+	}
+
+	// Deleted fields are deleted from the other cluster.
+	if err := c[0].API.DeleteField(ctx, "i", "g"); err != nil {
+		t.Fatal(err)
+	}
+	if err := test.RetryUntil(5*time.Second, func() error {
+		if _, err := dr[0].API.Field(ctx, "i", "g"); err == nil {
+			return errors.New("field still exists")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPI_Trash(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(%d, f=1)`, ShardWidth+1))
+
+	trashNames := func(i int) []string {
+		entries, err := c[i].API.Trash(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+		return names
+	}
+
+	if err := c[0].API.DeleteIndex(ctx, "i"); err != nil {
+		t.Fatal(err)
+	}
+	for i := range c {
+		if _, err := c[i].API.Index(ctx, "i"); !isNotFoundError(err) {
+			t.Fatalf("node %d: expected not found error, got %v", i, err)
+		} else if len(c[i].API.Schema(ctx)) != 0 {
+			t.Fatalf("node %d: unexpected schema: %v", i, c[i].API.Schema(ctx))
+		} else if _, err := c[i].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))"}); errors.Cause(err) != pilosa.ErrIndexNotFound {
+			t.Fatalf("node %d: expected index not found, got %v", i, err)
+		} else if names := trashNames(i); !reflect.DeepEqual(names, []string{"i"}) {
+			t.Fatalf("node %d: unexpected trash: %v", i, names)
+		}
+	}
+
+	t.Run("RestoreConflict", func(t *testing.T) {
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "g")
+		if err := c[1].API.RestoreIndex(ctx, "i"); !isConflictError(err) {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+		if err := c[1].API.PurgeIndex(ctx, "i"); err != nil {
+			t.Fatal(err)
+		} else if names := trashNames(0); !reflect.DeepEqual(names, []string{"i"}) {
+			t.Fatalf("unexpected trash after purge: %v", names)
+		}
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		if err := c[1].API.RestoreIndex(ctx, "i"); err != nil {
+			t.Fatal(err)
+		}
+		for i := range c {
+			res, err := c[i].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))"})
+			if err != nil {
+				t.Fatal(err)
+			} else if n := res.Results[0].(uint64); n != 2 {
+				t.Fatalf("node %d: unexpected count: %d", i, n)
+			} else if names := trashNames(i); len(names) != 0 {
+				t.Fatalf("node %d: unexpected trash: %v", i, names)
+			}
+		}
+		if err := c[0].API.RestoreIndex(ctx, "missing"); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		if err := c[0].API.DeleteIndex(ctx, "i"); err != nil {
+			t.Fatal(err)
+		} else if err := c[1].API.PurgeTrash(ctx, "i"); err != nil {
+			t.Fatal(err)
+		}
+		for i := range c {
+			if names := trashNames(i); len(names) != 0 {
+				t.Fatalf("node %d: unexpected trash: %v", i, names)
+			}
+		}
+		if err := c[0].API.PurgeTrash(ctx, "i"); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_Ingest(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "color")
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "active", pilosa.OptFieldTypeBool())
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "age", pilosa.OptFieldTypeInt(0, 150))
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "visited", pilosa.OptFieldTypeTime("YMD"))
+
+	m := &pilosa.IngestMapping{
+		Name: "people",
+		Fields: []*pilosa.IngestField{
+			{Name: "id", PrimaryKey: true},
+			{Name: "color", Actions: []*pilosa.IngestAction{{Field: "color", Type: pilosa.IngestActionRowPerValue, ValueMap: map[string]uint64{"red": 1, "blue": 2}}}},
+			{Name: "active", Actions: []*pilosa.IngestAction{{Field: "active", Type: pilosa.IngestActionBoolean}}},
+			{Name: "age", Actions: []*pilosa.IngestAction{{Field: "age", Type: pilosa.IngestActionValue}}},
+			{Name: "page", Actions: []*pilosa.IngestAction{{Field: "visited", Type: pilosa.IngestActionRowPerValue}}},
+			{Name: "ts", Actions: []*pilosa.IngestAction{{Type: pilosa.IngestActionTimestamp, Format: pilosa.IngestTimestampUnix}}},
+		},
+	}
+	if err := c[1].API.CreateIngestMapping(ctx, "i", m); err != nil {
+		t.Fatal(err)
+	} else if err := c[0].API.CreateIngestMapping(ctx, "i", m); !isConflictError(err) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	for i := range c {
+		if other, err := c[i].API.IngestMapping(ctx, "i", "people"); err != nil {
+			t.Fatalf("node %d: %v", i, err)
+		} else if !reflect.DeepEqual(other, m) {
+			t.Fatalf("node %d: unexpected mapping: %+v", i, other)
+		}
+	}
+
+	records := []map[string]interface{}{
+		{"id": 1.0, "color": "red", "active": true, "age": 30.0, "page": 5.0, "ts": 1551675967.0},
+		{"id": float64(ShardWidth + 2), "color": "blue", "active": false, "age": 40.0},
+		{"id": float64(2*ShardWidth + 3), "color": "red", "page": 5.0, "ts": 1554354367.0},
+	}
+	if err := c[2].API.Ingest(ctx, "i", "people", records); err != nil {
+		t.Fatal(err)
+	}
+	for query, exp := range map[string]interface{}{
+		"Row(color=1)":      []uint64{1, 2*ShardWidth + 3},
+		"Row(active=true)":  []uint64{1},
+		"Row(active=false)": []uint64{ShardWidth + 2},
+		"Row(age > 35)":     []uint64{ShardWidth + 2},
+		"Row(visited=5, from=2019-03-01T00:00, to=2019-04-01T00:00)": []uint64{1},
+	} {
+		res, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query})
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		} else if cols := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, exp) {
+			t.Fatalf("%s: unexpected columns: %v", query, cols)
+		}
+	}
+
+	t.Run("InvalidRecord", func(t *testing.T) {
+		err := c[0].API.Ingest(ctx, "i", "people", []map[string]interface{}{{"id": 5.0}, {"id": 6.0, "age": "old"}})
+		if !isBadRequestError(err) || !strings.Contains(err.Error(), `record 1: field "age"`) {
+			t.Fatalf("expected bad request error for record 1, got %v", err)
+		}
+		res, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Not(Row(color=100)))"})
+		if err != nil {
+			t.Fatal(err)
+		} else if n := res.Results[0].(uint64); n != 3 {
+			t.Fatalf("unexpected column count after failed ingest: %d", n)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := c[2].API.DeleteIngestMapping(ctx, "i", "people"); err != nil {
+			t.Fatal(err)
+		}
+		for i := range c {
+			if _, err := c[i].API.IngestMapping(ctx, "i", "people"); !isNotFoundError(err) {
+				t.Fatalf("node %d: expected not found error, got %v", i, err)
+			}
+		}
+		if err := c[0].API.Ingest(ctx, "i", "people", records); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_DeleteJob(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "v", pilosa.OptFieldTypeInt(0, 100))
+	c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(2, f=1) Set(%d, f=2) Set(%d, f=1) Set(1, v=10) Set(%d, v=20)`, ShardWidth+1, 2*ShardWidth+2, ShardWidth+1))
+
+	job, err := c[0].API.CreateDeleteJob(ctx, "i", []uint64{1, ShardWidth + 1, 2*ShardWidth + 2, 3 * ShardWidth}, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(job.Chunks) != 4 {
+		t.Fatalf("unexpected chunks: %d", len(job.Chunks))
+	}
+	for i := 0; job.Status != pilosa.DeleteJobSucceeded; i++ {
+		if i == 500 || job.Status == pilosa.DeleteJobFailed {
+			t.Fatalf("unexpected job: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = c[0].API.DeleteJob(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each value of v is an existence bit and two value bits.
+	var cleared uint64
+	for _, chunk := range job.Chunks {
+		cleared += chunk.Cleared
+	}
+	if cleared != 12 {
+		t.Fatalf("unexpected cleared bits: %d", cleared)
+	}
+	for query, exp := range map[string]uint64{
+		"Count(Row(f=1))":        1,
+		"Count(Row(f=2))":        0,
+		"Count(Row(v>0))":        0,
+		"Count(Not(Row(f=100)))": 1,
+	} {
+		res, err := c[2].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query})
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		} else if n := res.Results[0].(uint64); n != exp {
+			t.Fatalf("%s: unexpected count: %d", query, n)
+		}
+	}
+
+	t.Run("Resume", func(t *testing.T) {
+		if _, err := c[0].API.ResumeDeleteJob(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		} else if _, err := c[0].API.ResumeDeleteJob(ctx, "x"); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := c[0].API.DeleteJob(ctx, "x"); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		} else if _, err := c[0].API.CreateDeleteJob(ctx, "x", []uint64{1}, nil); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		} else if _, err := c[0].API.CreateDeleteJob(ctx, "i", nil, []string{"a"}); !isBadRequestError(err) {
+			t.Fatalf("expected bad request error, got %v", err)
+		} else if _, err := c[0].API.CreateDeleteJob(ctx, "i", nil, nil); !isBadRequestError(err) {
+			t.Fatalf("expected bad request error, got %v", err)
+		} else if _, err := c[1].API.CreateDeleteJob(ctx, "i", []uint64{1}, nil); errors.Cause(err) != pilosa.ErrNodeNotCoordinator {
+			t.Fatalf("expected node not coordinator error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_WarmJob(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "f")
+	c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(%d, f=1) Set(%d, f=2) Set(%d, f=2)`, ShardWidth+1, 2*ShardWidth+2, 3*ShardWidth+3))
+
+	job, err := c[0].API.CreateWarmJob(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(job.Indexes, []string{"i"}) || len(job.Nodes) != 2 {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	for i := 0; job.Status != pilosa.WarmJobSucceeded; i++ {
+		if i == 500 || job.Status == pilosa.WarmJobFailed {
+			t.Fatalf("unexpected job: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = c[0].API.WarmJob(ctx, job.ID, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each of the four shards has a fragment of f and of the existence
+	// field, split between the nodes.
+	if p := job.Progress["i"]; p.Fragments != 8 || p.Succeeded != 8 {
+		t.Fatalf("unexpected progress: %+v", p)
+	}
+	local, err := c[1].API.WarmJob(ctx, job.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if n := local.Progress["i"].Fragments; n == 0 || n == 8 {
+		t.Fatalf("unexpected fragments of node 1: %d", n)
+	}
+
+	t.Run("Resume", func(t *testing.T) {
+		if _, err := c[0].API.ResumeWarmJob(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		} else if _, err := c[0].API.ResumeWarmJob(ctx, "x"); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := c[0].API.WarmJob(ctx, "x", false); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		} else if _, err := c[0].API.CreateWarmJob(ctx, []string{"x"}); !isNotFoundError(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		} else if _, err := c[1].API.CreateWarmJob(ctx, nil); errors.Cause(err) != pilosa.ErrNodeNotCoordinator {
+			t.Fatalf("expected node not coordinator error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_Maintenance(t *testing.T) {
+	c := test.MustRunCluster(t, 2,
+		[]server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplicaN(2))},
+		[]server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplicaN(2))},
+	)
+	defer c.Close()
+
+	ctx := context.Background()
+	node1 := c[1].API.Node().ID
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+	// Find a shard whose primary is the second node, and remove a bit
+	// from its replica of the shard only.
+	var shard uint64
+	for ; shard < 4; shard++ {
+		if nodes, err := c[0].API.ShardNodes(ctx, "i", shard); err != nil {
+			t.Fatal(err)
+		} else if nodes[0].ID == node1 {
+			break
+		}
+	}
+	c.Query(t, "i", fmt.Sprintf(`Set(%d, f=1) Set(%d, f=1)`, shard*ShardWidth+1, shard*ShardWidth+2))
+	hldr := test.Holder{Holder: c[1].Server.Holder()}
+	hldr.ClearBit("i", "f", 1, shard*ShardWidth+2)
+
+	count := func() uint64 {
+		t.Helper()
+		return c.Query(t, "i", "Count(Row(f=1))").Results[0].(uint64)
+	}
+	if n := count(); n != 1 {
+		t.Fatalf("expected the second node to be read, got count %d", n)
+	}
+
+	table, err := c[0].API.RoutingTable(ctx, "i", []uint64{shard})
+	if err != nil {
+		t.Fatal(err)
+	} else if owners := table.Shards[0].Owners; owners[0] != c[1].API.Node().URI.String() {
+		t.Fatalf("expected the second node first: %v", owners)
+	}
+	generation := table.Generation
+
+	if err := c[1].API.SetMaintenance(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if r := c[1].API.Readiness(); r.Ready || !r.Maintenance {
+		t.Fatalf("unexpected readiness: %+v", r)
+	} else if r := c[0].API.Readiness(); !r.Ready {
+		t.Fatalf("unexpected readiness: %+v", r)
+	}
+	for _, n := range c[0].API.Hosts(ctx) {
+		if n.Maintenance != (n.ID == node1) {
+			t.Fatalf("unexpected maintenance: %s", n)
+		}
+	}
+
+	// Reads prefer the other replica, and clients are routed to it.
+	if n := count(); n != 2 {
+		t.Fatalf("expected the other replica to be read, got count %d", n)
+	}
+	if nodes, err := c[0].API.ShardNodes(ctx, "i", shard); err != nil {
+		t.Fatal(err)
+	} else if nodes[len(nodes)-1].ID != node1 {
+		t.Fatalf("expected node in maintenance last: %v", nodes)
+	}
+
+	// The table exposed to clients matches, and every node reports the
+	// same new generation.
+	table, err = c[0].API.RoutingTable(ctx, "i", []uint64{shard})
+	if err != nil {
+		t.Fatal(err)
+	} else if owners := table.Shards[0].Owners; owners[len(owners)-1] != c[1].API.Node().URI.String() {
+		t.Fatalf("expected node in maintenance last: %v", owners)
+	} else if table.Generation == generation {
+		t.Fatalf("expected generation to change")
+	}
+	if other, err := c[1].API.RoutingTable(ctx, "i", []uint64{shard}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(other, table) {
+		t.Fatalf("unexpected table: %+v, want %+v", other, table)
+	}
+
+	// Writes still replicate to the node.
+	c.Query(t, "i", fmt.Sprintf(`Set(%d, f=1)`, shard*ShardWidth+3))
+	if got, want := hldr.Row("i", "f", 1).Columns(), []uint64{shard*ShardWidth + 1, shard*ShardWidth + 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected write on node in maintenance: got %v, want %v", got, want)
+	}
+
+	if err := c[1].API.SetMaintenance(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	if r := c[1].API.Readiness(); !r.Ready || r.Maintenance {
+		t.Fatalf("unexpected readiness: %+v", r)
+	}
+	if n := count(); n != 2 {
+		t.Fatalf("expected the second node to be read, got count %d", n)
+	}
+	if table, err := c[0].API.RoutingTable(ctx, "i", []uint64{shard}); err != nil {
+		t.Fatal(err)
+	} else if table.Generation != generation {
+		t.Fatalf("expected generation to be restored")
+	}
+	if _, err := c[0].API.RoutingTable(ctx, "nosuch", []uint64{0}); !isNotFoundError(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+// Ensure a write into a new highest shard is read immediately afterward by
+// every node.
+func TestAPI_NewShardReadAfterWrite(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+	// otherNode returns a node which doesn't own a shard.
+	otherNode := func(shard uint64) *test.Command {
+		t.Helper()
+		nodes, err := c[0].API.ShardNodes(ctx, "i", shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range c {
+			if m.API.Node().ID != nodes[0].ID {
+				return m
+			}
+		}
+		t.Fatal("no other node")
+		return nil
+	}
+	count := func(m *test.Command, row int) uint64 {
+		t.Helper()
+		resp, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf("Count(Row(f=%d))", row)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Results[0].(uint64)
+	}
+
+	// Imports into the owner of each shard.
+	for i := uint64(0); i < 10; i++ {
+		shard := 100 + i*10
+		c.ImportBits(t, "i", "f", [][2]uint64{{1, shard*ShardWidth + 1}, {1, shard*ShardWidth + 2}})
+		if n := count(otherNode(shard), 1); n != 2*(i+1) {
+			t.Fatalf("shard %d: expected count %d, got %d", shard, 2*(i+1), n)
+		}
+	}
+
+	// Set() on a node which doesn't own the shard.
+	for i := uint64(0); i < 10; i++ {
+		shard := 1000 + i*10
+		m := otherNode(shard)
+		if _, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf("Set(%d, f=2)", shard*ShardWidth+1)}); err != nil {
+			t.Fatal(err)
+		}
+		for _, other := range c {
+			if n := count(other, 2); n != i+1 {
+				t.Fatalf("shard %d on %s: expected count %d, got %d", shard, other.API.Node().ID, i+1, n)
+			}
+		}
+	}
+
+	// Transactions on a node which doesn't own the shard.
+	for i := uint64(0); i < 10; i++ {
+		shard := 2000 + i*10
+		m := otherNode(shard)
+		if err := m.API.Transaction(ctx, "i", fmt.Sprintf("Set(%d, f=3)", shard*ShardWidth+1), ""); err != nil {
+			t.Fatal(err)
+		}
+		for _, other := range c {
+			if n := count(other, 3); n != i+1 {
+				t.Fatalf("shard %d on %s: expected count %d, got %d", shard, other.API.Node().ID, i+1, n)
+			}
+		}
+	}
+
+	// IncrementFieldValue() on a node which doesn't own the shard.
+	if _, err := c[0].API.CreateField(ctx, "i", "v", pilosa.OptFieldTypeInt(0, 100)); err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < 10; i++ {
+		shard := 3000 + i*10
+		m := otherNode(shard)
+		if _, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf("IncrementFieldValue(field=v, column=%d, amount=1)", shard*ShardWidth+1)}); err != nil {
+			t.Fatal(err)
+		}
+		for _, other := range c {
+			resp, err := other.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Row(v == 1))"})
+			if err != nil {
+				t.Fatal(err)
+			} else if n := resp.Results[0].(uint64); n != i+1 {
+				t.Fatalf("shard %d on %s: expected count %d, got %d", shard, other.API.Node().ID, i+1, n)
+			}
+		}
+	}
+}
+
 // offsetModHasher represents a simple, mod-based hashing offset by 1.
 type offsetModHasher struct{}
 
 func (*offsetModHasher) Hash(key uint64, n int) int {
 	return int(key+1) % n
 }
+
+func TestAPI_FieldChanges(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	m := c[0]
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f", pilosa.OptFieldTrackChanges())
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "untracked")
+	if _, err := m.API.CreateField(ctx, "i", "v", pilosa.OptFieldTypeInt(0, 10), pilosa.OptFieldTrackChanges()); err == nil {
+		t.Fatal("expected error creating int field which tracks changes")
+	}
+
+	for _, q := range []string{"Set(1, f=3)", "Set(1, f=3)", "Clear(1, f=3)", "Set(2, f=4)"} {
+		if _, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: q}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.ImportBits(t, "i", "f", [][2]uint64{{4, 3}, {4, 5}, {5, ShardWidth}})
+	if _, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "ClearRow(f=4)"}); err != nil {
+		t.Fatal(err)
+	}
+
+	type change struct {
+		shard, row uint64
+		op         string
+		delta      int64
+	}
+	changes, err := m.API.FieldChanges(ctx, "i", "f", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []change
+	for i, ch := range changes.Changes {
+		if ch.Seq != uint64(i+1) || ch.View != "standard" {
+			t.Fatalf("unexpected change: %+v", ch)
+		}
+		got = append(got, change{ch.Shard, ch.Row, ch.Op, ch.Delta})
+	}
+	want := []change{
+		{0, 3, pilosa.ChangeOpSet, 1},
+		{0, 3, pilosa.ChangeOpClear, -1},
+		{0, 4, pilosa.ChangeOpSet, 1},
+		{0, 4, pilosa.ChangeOpImport, 2},
+		{1, 5, pilosa.ChangeOpImport, 1},
+		{0, 4, pilosa.ChangeOpClearRow, -3},
+	}
+	// The shards of an import are applied concurrently, so their changes are
+	// compared regardless of order.
+	if len(got) == len(want) {
+		imported := got[3:5]
+		sort.Slice(imported, func(i, j int) bool { return imported[i].shard < imported[j].shard })
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected changes:\n got %v\nwant %v", got, want)
+	} else if changes.Next != 6 || changes.Gap || changes.Node != m.API.Node().ID {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	// A consumer which read every change waits for the next one.
+	done := make(chan *pilosa.FieldChanges)
+	go func() {
+		changes, err := m.API.FieldChanges(ctx, "i", "f", 6, 0, 10*time.Second)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- changes
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Set(9, f=6)"}); err != nil {
+		t.Fatal(err)
+	}
+	if changes := <-done; changes == nil || len(changes.Changes) != 1 || changes.Changes[0].Row != 6 || changes.Next != 7 {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	if _, err := m.API.FieldChanges(ctx, "i", "untracked", 0, 0, 0); !isBadRequestError(err) {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+}
+
+func TestAPI_Views(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "t", pilosa.OptFieldTypeTime("YM"))
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "v", pilosa.OptFieldTypeInt(0, 10))
+	c.Query(t, "i", fmt.Sprintf("Set(1, t=1, 2020-01-02T00:00) Set(%d, t=1, 1970-01-01T00:00)", ShardWidth))
+
+	viewNames := func(i int, field string) []string {
+		views, err := c[i].API.FieldViews(ctx, "i", field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, v := range views {
+			names = append(names, v.Name)
+		}
+		return names
+	}
+	views, err := c[0].API.FieldViews(ctx, "i", "t")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(views) != 5 || views[0].Name != "standard" || views[1].Name != "standard_1970" {
+		t.Fatalf("unexpected views: %v", viewNames(0, "t"))
+	}
+	var shards int
+	for i := range c {
+		views, _ := c[i].API.FieldViews(ctx, "i", "t")
+		shards += views[0].Shards
+		if views[0].Shards > 0 && views[0].Size == 0 {
+			t.Fatalf("unexpected size of view: %+v", views[0])
+		}
+	}
+	if shards != 2 {
+		t.Fatalf("unexpected shards of standard view: %d", shards)
+	}
+
+	// Views are created on all nodes, and must be able to hold data.
+	if err := c[0].API.CreateView(ctx, "i", "t", "standard_202002"); err != nil {
+		t.Fatal(err)
+	} else if names := viewNames(1, "t"); !reflect.DeepEqual(names, []string{"standard", "standard_1970", "standard_197001", "standard_2020", "standard_202001", "standard_202002"}) {
+		t.Fatalf("unexpected views: %v", names)
+	} else if err := c[0].API.CreateView(ctx, "i", "t", "standard_202002"); !isConflictError(err) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	for _, name := range []string{"standard_20200203", "standard_2020W05", "standard_2020x", "other", "bsig_t"} {
+		if err := c[0].API.CreateView(ctx, "i", "t", name); !isBadRequestError(err) {
+			t.Fatalf("expected bad request creating %s, got %v", name, err)
+		}
+	}
+	if err := c[0].API.CreateView(ctx, "i", "v", "bsig_v"); err != nil {
+		t.Fatal(err)
+	} else if err := c[0].API.CreateView(ctx, "i", "v", "standard"); !isBadRequestError(err) {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+
+	// Deleting a view removes its data on all nodes.
+	for _, name := range []string{"standard_1970", "standard_197001"} {
+		if err := c[1].API.DeleteView(ctx, "i", "t", name, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range c {
+		if names := viewNames(i, "t"); !reflect.DeepEqual(names, []string{"standard", "standard_2020", "standard_202001", "standard_202002"}) {
+			t.Fatalf("unexpected views on node %d: %v", i, names)
+		}
+	}
+	resp := c.Query(t, "i", "Count(Row(t=1, from=1969-01-01T00:00, to=2021-01-01T00:00)) Count(Row(t=1))")
+	if resp.Results[0].(uint64) != 1 || resp.Results[1].(uint64) != 2 {
+		t.Fatalf("unexpected counts: %v", resp.Results)
+	}
+
+	if err := c[0].API.DeleteView(ctx, "i", "t", "standard", false); !isBadRequestError(err) {
+		t.Fatalf("expected bad request, got %v", err)
+	} else if err := c[0].API.DeleteView(ctx, "i", "t", "standard", true); err != nil {
+		t.Fatal(err)
+	} else if names := viewNames(1, "t"); names[0] != "standard_2020" {
+		t.Fatalf("unexpected views: %v", names)
+	}
+	if _, err := c[0].API.FieldViews(ctx, "i", "x"); !isNotFoundError(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestAPI_StatsHistory(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	m := c[0]
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "j", pilosa.IndexOptions{}, "f")
+	for _, q := range []string{"Set(1, f=3)", "Set(2, f=3)", "Count(Row(f=3)) Row(f=3)", "Count(Row(g=3))"} {
+		_, _ = m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: q})
+	}
+	c.ImportBits(t, "i", "f", [][2]uint64{{4, 3}, {4, 5}})
+
+	// The queries may be recorded in two different minutes.
+	hist, err := m.API.StatsHistory(ctx, "i", 2*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(hist) != 1 || hist[0].Index != "i" || len(hist[0].Points) != 2 {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+	var queries, errs uint64
+	calls, writes := make(map[string]uint64), make(map[string]uint64)
+	for _, p := range hist[0].Points {
+		queries += p.Queries
+		errs += p.Errors
+		for name, n := range p.Calls {
+			calls[name] += n
+		}
+		for name, n := range p.Writes {
+			writes[name] += n
+		}
+	}
+	if queries != 4 || errs != 1 {
+		t.Fatalf("unexpected queries %d, errors %d", queries, errs)
+	} else if !reflect.DeepEqual(calls, map[string]uint64{"Set": 2, "Count": 2, "Row": 1}) {
+		t.Fatalf("unexpected calls: %v", calls)
+	} else if !reflect.DeepEqual(writes, map[string]uint64{"setBit": 2, "ImportedN": 2}) {
+		t.Fatalf("unexpected writes: %v", writes)
+	}
+
+	// Indexes without activity aren't listed.
+	if hist, err := m.API.StatsHistory(ctx, "", time.Minute); err != nil {
+		t.Fatal(err)
+	} else if len(hist) != 1 || hist[0].Index != "i" {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+
+	if _, err := m.API.StatsHistory(ctx, "k", time.Minute); !isNotFoundError(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestAPI_ImportValidation(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	m := c[0]
+
+	ctx := context.Background()
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "f")
+	c.CreateField(t, "k", pilosa.IndexOptions{Keys: true}, "f")
+
+	// Values sent to a set field are decoded without rows.
+	err := m.API.Import(ctx, &pilosa.ImportRequest{Index: "i", Field: "f", ColumnIDs: []uint64{1, 2}, Timestamps: []int64{5, 6}})
+	if !isBadRequestError(err) || !strings.Contains(err.Error(), "only int fields import values") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Records without a row are rejected before keys are translated.
+	err = m.API.Import(ctx, &pilosa.ImportRequest{Index: "k", Field: "f", RowIDs: []uint64{1}, ColumnKeys: []string{"a", "b"}})
+	if !isBadRequestError(err) || !strings.Contains(err.Error(), "1 rows for 2 columns: 1 invalid records: record 1") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Nothing is written by a rejected import.
+	resp, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Not(Row(f=0)))"})
+	if err != nil {
+		t.Fatal(err)
+	} else if n := resp.Results[0].(uint64); n != 0 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+}
+
+// Ensure queries and imports racing with the deletion and creation of their
+// index fail cleanly rather than using the closed index.
+func TestAPI_ConcurrentDeleteIndex(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	m := c[0]
+
+	ctx := context.Background()
+	deadline := time.Now().Add(2 * time.Second)
+	errs := make(chan error, 100)
+	var wg sync.WaitGroup
+	run := func(fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				err := fn()
+				switch errors.Cause(err) {
+				case nil, pilosa.ErrIndexNotFound, pilosa.ErrFieldNotFound, pilosa.ErrIndexExists, pilosa.ErrFieldExists:
+				default:
+					if _, ok := errors.Cause(err).(pilosa.NotFoundError); ok {
+						continue
+					} else if _, ok := errors.Cause(err).(pilosa.ConflictError); ok {
+						continue
+					}
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	run(func() error {
+		if _, err := m.API.CreateIndex(ctx, "i", pilosa.IndexOptions{}); err != nil {
+			return err
+		}
+		_, err := m.API.CreateField(ctx, "i", "f")
+		return err
+	})
+	run(func() error {
+		time.Sleep(time.Millisecond)
+		return m.API.PurgeIndex(ctx, "i")
+	})
+	for i := 0; i < 4; i++ {
+		run(func() error {
+			_, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Set(1, f=1) Set(2000000, f=1) Count(Row(f=1))"})
+			return err
+		})
+	}
+	run(func() error {
+		return m.API.Import(ctx, &pilosa.ImportRequest{Index: "i", Field: "f", RowIDs: []uint64{1, 2}, ColumnIDs: []uint64{3, 4}})
+	})
+	run(func() error {
+		if _, err := m.API.CreateField(ctx, "i", "g"); err != nil {
+			return err
+		}
+		return m.API.DeleteField(ctx, "i", "g")
+	})
+	run(func() error {
+		_, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "SetRowAttrs(f, 1, x=1) SetColumnAttrs(1, y=2)"})
+		return err
+	})
+	wg.Wait()
+
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -33,11 +33,45 @@ func _() {
 	_ = x[apiShardNodes-22]
 	_ = x[apiViews-23]
 	_ = x[apiApplySchema-24]
+	_ = x[apiTransaction-25]
+	_ = x[apiSetIndexReadOnly-26]
+	_ = x[apiFragmentBlockPairs-27]
+	_ = x[apiSetFieldTimeQuantum-28]
+	_ = x[apiDeleteSession-29]
+	_ = x[apiCreateIngestMapping-30]
+	_ = x[apiIngestMapping-31]
+	_ = x[apiDeleteIngestMapping-32]
+	_ = x[apiIngest-33]
+	_ = x[apiClusterConfig-34]
+	_ = x[apiUpdateClusterConfig-35]
+	_ = x[apiSetIndexQuota-36]
+	_ = x[apiExportSchema-37]
+	_ = x[apiProvisionSchema-38]
+	_ = x[apiCreateDeleteJob-39]
+	_ = x[apiDeleteJob-40]
+	_ = x[apiResumeDeleteJob-41]
+	_ = x[apiFragmentInspect-42]
+	_ = x[apiTrash-43]
+	_ = x[apiRestoreIndex-44]
+	_ = x[apiPurgeTrash-45]
+	_ = x[apiRetainedSnapshots-46]
+	_ = x[apiRebuildExistence-47]
+	_ = x[apiCreateWarmJob-48]
+	_ = x[apiWarmJob-49]
+	_ = x[apiResumeWarmJob-50]
+	_ = x[apiSetMaintenance-51]
+	_ = x[apiFieldChanges-52]
+	_ = x[apiStatsHistory-53]
+	_ = x[apiCreateView-54]
+	_ = x[apiRoutingTable-55]
+	_ = x[apiCompactFragment-56]
+	_ = x[apiSetIndexDefaultFieldOptions-57]
+	_ = x[apiReplicationStatus-58]
 }
 
-const _apiMethod_name = "apiClusterMessageapiCreateFieldapiCreateIndexapiDeleteFieldapiDeleteAvailableShardapiDeleteIndexapiDeleteViewapiExportCSVapiFragmentBlockDataapiFragmentBlocksapiFragmentDataapiFieldapiFieldAttrDiffapiImportapiImportValueapiIndexapiIndexAttrDiffapiQueryapiRecalculateCachesapiRemoveNodeapiResizeAbortapiSetCoordinatorapiShardNodesapiViewsapiApplySchema"
+const _apiMethod_name = "apiClusterMessageapiCreateFieldapiCreateIndexapiDeleteFieldapiDeleteAvailableShardapiDeleteIndexapiDeleteViewapiExportCSVapiFragmentBlockDataapiFragmentBlocksapiFragmentDataapiFieldapiFieldAttrDiffapiImportapiImportValueapiIndexapiIndexAttrDiffapiQueryapiRecalculateCachesapiRemoveNodeapiResizeAbortapiSetCoordinatorapiShardNodesapiViewsapiApplySchemaapiTransactionapiSetIndexReadOnlyapiFragmentBlockPairsapiSetFieldTimeQuantumapiDeleteSessionapiCreateIngestMappingapiIngestMappingapiDeleteIngestMappingapiIngestapiClusterConfigapiUpdateClusterConfigapiSetIndexQuotaapiExportSchemaapiProvisionSchemaapiCreateDeleteJobapiDeleteJobapiResumeDeleteJobapiFragmentInspectapiTrashapiRestoreIndexapiPurgeTrashapiRetainedSnapshotsapiRebuildExistenceapiCreateWarmJobapiWarmJobapiResumeWarmJobapiSetMaintenanceapiFieldChangesapiStatsHistoryapiCreateViewapiRoutingTableapiCompactFragmentapiSetIndexDefaultFieldOptionsapiReplicationStatus"
 
-var _apiMethod_index = [...]uint16{0, 17, 31, 45, 59, 82, 96, 109, 121, 141, 158, 173, 181, 197, 206, 220, 228, 244, 252, 272, 285, 299, 316, 329, 337, 351}
+var _apiMethod_index = [...]uint16{0, 17, 31, 45, 59, 82, 96, 109, 121, 141, 158, 173, 181, 197, 206, 220, 228, 244, 252, 272, 285, 299, 316, 329, 337, 351, 365, 384, 405, 427, 443, 465, 481, 503, 512, 528, 550, 566, 581, 599, 617, 629, 647, 665, 673, 688, 701, 721, 740, 756, 766, 782, 799, 814, 829, 842, 857, 875, 905, 925}
 
 func (i apiMethod) String() string {
 	if i < 0 || i >= apiMethod(len(_apiMethod_index)-1) {
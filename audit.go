@@ -0,0 +1,366 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultAuditMaxSize is the size in bytes at which the audit log file
+	// is rotated.
+	defaultAuditMaxSize = 100 * 1024 * 1024
+
+	// defaultAuditMaxBackups is the number of rotated audit log files kept.
+	defaultAuditMaxBackups = 5
+
+	// auditBufferSize is the number of audit records which can be waiting
+	// to be written. Records are dropped when the buffer is full, so that
+	// writes never wait for the audit log.
+	auditBufferSize = 4096
+
+	// auditFlushInterval is how often buffered audit records are written to
+	// the file. Records which have not been written are lost on a crash.
+	auditFlushInterval = time.Second
+
+	// auditWebhookTimeout bounds the time to deliver records to the webhook.
+	auditWebhookTimeout = 10 * time.Second
+)
+
+// AuditOptions configures the audit log of write operations.
+type AuditOptions struct {
+	// Enabled turns on recording of write operations. It can also be changed
+	// while the server runs with the audit.enabled cluster setting.
+	Enabled bool
+
+	// Path of the audit log file. Defaults to audit.log in the data
+	// directory.
+	Path string
+
+	// MaxSize is the size in bytes at which the file is rotated, and
+	// MaxBackups the number of rotated files kept.
+	MaxSize    int64
+	MaxBackups int
+
+	// Webhook is a URL to which records are also posted as JSON arrays, if
+	// set. Delivery is best-effort; the file is the authoritative record.
+	Webhook string
+}
+
+// AuditRecord records a write operation received from a client.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+
+	// Principal is the common name of the client certificate of the request,
+	// if the client was authenticated with TLS.
+	Principal  string `json:"principal,omitempty"`
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+
+	// Operation is the API operation, such as "query" or "createField".
+	Operation string `json:"operation"`
+	Index     string `json:"index,omitempty"`
+	Field     string `json:"field,omitempty"`
+
+	// Calls counts the write calls of a query by name.
+	Calls map[string]int `json:"calls,omitempty"`
+
+	// Count is the number of bits or values written by the operation, or
+	// the number of records which were dropped.
+	Count int `json:"count,omitempty"`
+
+	// Error is set if the operation failed, in which case it may have been
+	// partially applied.
+	Error string `json:"error,omitempty"`
+}
+
+// auditInfoKey is the context key for the client of a request.
+type auditInfoKey struct{}
+
+type auditInfo struct {
+	principal  string
+	remoteAddr string
+}
+
+// WithAuditInfo returns a copy of ctx which identifies the client of a
+// request in the audit records of the write operations it makes.
+func WithAuditInfo(ctx context.Context, principal, remoteAddr string) context.Context {
+	return context.WithValue(ctx, auditInfoKey{}, auditInfo{principal: principal, remoteAddr: remoteAddr})
+}
+
+// auditLog writes audit records to an append-only file, which is rotated
+// when it reaches its maximum size. Records are written by a background
+// goroutine so that writes aren't slowed down by the audit log; in exchange,
+// up to auditFlushInterval of records can be lost on a crash, and records
+// are dropped, and counted in the log, if they arrive faster than they can
+// be written.
+type auditLog struct {
+	enabled int32 // accessed atomically
+	dropped int64 // accessed atomically
+
+	path       string
+	maxSize    int64
+	maxBackups int
+	webhook    string
+
+	records chan *AuditRecord
+	hooks   chan []*AuditRecord
+
+	// File and its size, which are only used by the writing goroutine.
+	file *os.File
+	w    *bufio.Writer
+	size int64
+
+	client *http.Client
+	logger logger.Logger
+}
+
+// newAuditLog returns a new instance of auditLog which writes to a file in
+// dir unless opt has a path.
+func newAuditLog(opt AuditOptions, dir string) *auditLog {
+	a := &auditLog{
+		path:       opt.Path,
+		maxSize:    opt.MaxSize,
+		maxBackups: opt.MaxBackups,
+		webhook:    opt.Webhook,
+		records:    make(chan *AuditRecord, auditBufferSize),
+		hooks:      make(chan []*AuditRecord, 16),
+		client:     &http.Client{Timeout: auditWebhookTimeout},
+		logger:     logger.NopLogger,
+	}
+	if a.path == "" && dir != "" {
+		a.path = filepath.Join(dir, "audit.log")
+	}
+	if a.maxSize <= 0 {
+		a.maxSize = defaultAuditMaxSize
+	}
+	if a.maxBackups <= 0 {
+		a.maxBackups = defaultAuditMaxBackups
+	}
+	a.setEnabled(opt.Enabled)
+	return a
+}
+
+// Enabled returns true if write operations are recorded.
+func (a *auditLog) Enabled() bool {
+	return a != nil && atomic.LoadInt32(&a.enabled) == 1
+}
+
+// setEnabled turns recording of write operations on or off.
+func (a *auditLog) setEnabled(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&a.enabled, v)
+}
+
+// log records a write operation made by the client of ctx, if the audit log
+// is enabled. It never blocks.
+func (a *auditLog) log(ctx context.Context, rec *AuditRecord) {
+	if !a.Enabled() {
+		return
+	}
+	rec.Time = time.Now().UTC()
+	if info, ok := ctx.Value(auditInfoKey{}).(auditInfo); ok {
+		rec.Principal, rec.RemoteAddr = info.principal, info.remoteAddr
+	}
+
+	select {
+	case a.records <- rec:
+	default:
+		atomic.AddInt64(&a.dropped, 1)
+	}
+}
+
+// run writes records until closing is closed, and then writes the records
+// which are still buffered.
+func (a *auditLog) run(closing <-chan struct{}) {
+	if a.webhook != "" {
+		done := make(chan struct{})
+		defer func() { close(a.hooks); <-done }()
+		go func() { defer close(done); a.deliver() }()
+	}
+	defer a.close()
+
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+
+	var batch []*AuditRecord
+	for {
+		select {
+		case <-closing:
+			for {
+				select {
+				case rec := <-a.records:
+					batch = append(batch, rec)
+					continue
+				default:
+				}
+				break
+			}
+			a.flush(batch)
+			return
+		case rec := <-a.records:
+			batch = append(batch, rec)
+			if len(batch) < auditBufferSize {
+				continue
+			}
+			a.flush(batch)
+			batch = nil
+		case <-ticker.C:
+			a.flush(batch)
+			batch = nil
+		}
+	}
+}
+
+// flush writes records, and a record of the number of records which were
+// dropped since the last flush, to the file and the webhook.
+func (a *auditLog) flush(records []*AuditRecord) {
+	if n := atomic.SwapInt64(&a.dropped, 0); n > 0 {
+		a.logger.Printf("audit log dropped %d records", n)
+		records = append(records, &AuditRecord{Time: time.Now().UTC(), Operation: "dropped", Count: int(n)})
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	if err := a.write(records); err != nil {
+		a.logger.Printf("writing audit log: %v", err)
+	}
+	if a.webhook != "" {
+		select {
+		case a.hooks <- records:
+		default:
+			a.logger.Printf("audit webhook is behind, dropped %d records", len(records))
+		}
+	}
+}
+
+// write appends records to the file, rotating it when it is full.
+func (a *auditLog) write(records []*AuditRecord) error {
+	if a.path == "" {
+		return nil
+	}
+	for _, rec := range records {
+		buf, err := json.Marshal(rec)
+		if err != nil {
+			return errors.Wrap(err, "marshaling record")
+		}
+		buf = append(buf, '\n')
+
+		if a.file != nil && a.size > 0 && a.size+int64(len(buf)) > a.maxSize {
+			if err := a.rotate(); err != nil {
+				return errors.Wrap(err, "rotating")
+			}
+		}
+		if a.file == nil {
+			if err := a.open(); err != nil {
+				return errors.Wrap(err, "opening")
+			}
+		}
+		n, err := a.w.Write(buf)
+		a.size += int64(n)
+		if err != nil {
+			return errors.Wrap(err, "writing")
+		}
+	}
+	return errors.Wrap(a.w.Flush(), "flushing")
+}
+
+// open opens the file for appending.
+func (a *auditLog) open() error {
+	if err := os.MkdirAll(filepath.Dir(a.path), 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	}
+	f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrap(err, "statting")
+	}
+	a.file, a.w, a.size = f, bufio.NewWriter(f), fi.Size()
+	return nil
+}
+
+// rotate closes the file and renames it to path.1, shifting older files
+// and removing the oldest one.
+func (a *auditLog) rotate() error {
+	if err := a.close(); err != nil {
+		return err
+	}
+	for i := a.maxBackups - 1; i > 0; i-- {
+		from := fmt.Sprintf("%s.%d", a.path, i)
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", a.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(a.path, a.path+".1")
+}
+
+// close flushes and closes the file, if it is open.
+func (a *auditLog) close() error {
+	if a.file == nil {
+		return nil
+	}
+	err := a.w.Flush()
+	if e := a.file.Close(); err == nil {
+		err = e
+	}
+	a.file, a.w, a.size = nil, nil, 0
+	return err
+}
+
+// deliver posts batches of records to the webhook until the channel of
+// batches is closed.
+func (a *auditLog) deliver() {
+	for records := range a.hooks {
+		if err := a.post(records); err != nil {
+			a.logger.Printf("posting %d records to audit webhook: %v", len(records), err)
+		}
+	}
+}
+
+// post sends records to the webhook as a JSON array.
+func (a *auditLog) post(records []*AuditRecord) error {
+	buf, err := json.Marshal(records)
+	if err != nil {
+		return errors.Wrap(err, "marshaling records")
+	}
+	resp, err := a.client.Post(a.webhook, "application/json", bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
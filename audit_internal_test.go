@@ -0,0 +1,156 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// readAuditRecords returns the records in an audit log file.
+func readAuditRecords(tb testing.TB, path string) []*AuditRecord {
+	tb.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []*AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			tb.Fatal(err)
+		}
+		records = append(records, &rec)
+	}
+	if err := scanner.Err(); err != nil {
+		tb.Fatal(err)
+	}
+	return records
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Run("WriteAndRotate", func(t *testing.T) {
+		dir, err := ioutil.TempDir(*TempDir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		var mu sync.Mutex
+		var posted []*AuditRecord
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var records []*AuditRecord
+			if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			posted = append(posted, records...)
+			mu.Unlock()
+		}))
+		defer srv.Close()
+
+		a := newAuditLog(AuditOptions{Enabled: true, MaxSize: 400, MaxBackups: 2, Webhook: srv.URL}, dir)
+		closing := make(chan struct{})
+		done := make(chan struct{})
+		go func() { defer close(done); a.run(closing) }()
+
+		ctx := WithAuditInfo(context.Background(), "alice", "127.0.0.1:1234")
+		for i := 1; i <= 10; i++ {
+			a.log(ctx, &AuditRecord{Operation: "import", Index: "i", Field: "f", Count: i})
+		}
+		close(closing)
+		<-done
+
+		// The oldest records were rotated out.
+		path := filepath.Join(dir, "audit.log")
+		if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+			t.Fatalf("expected no third backup: %v", err)
+		}
+		var records []*AuditRecord
+		for _, name := range []string{path + ".2", path + ".1", path} {
+			if fi, err := os.Stat(name); err != nil {
+				t.Fatal(err)
+			} else if fi.Size() > 400 {
+				t.Fatalf("unexpected size of %s: %d", name, fi.Size())
+			}
+			records = append(records, readAuditRecords(t, name)...)
+		}
+		if len(records) == 0 || len(records) >= 10 {
+			t.Fatalf("unexpected number of records: %d", len(records))
+		}
+		for i, rec := range records {
+			if rec.Principal != "alice" || rec.RemoteAddr != "127.0.0.1:1234" || rec.Operation != "import" || rec.Time.IsZero() {
+				t.Fatalf("unexpected record: %+v", rec)
+			} else if exp := 10 - len(records) + i + 1; rec.Count != exp {
+				t.Fatalf("unexpected count: %d, expected %d", rec.Count, exp)
+			}
+		}
+
+		// Every record was posted to the webhook.
+		mu.Lock()
+		defer mu.Unlock()
+		if len(posted) != 10 {
+			t.Fatalf("unexpected number of posted records: %d", len(posted))
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		a := newAuditLog(AuditOptions{}, "")
+		a.log(context.Background(), &AuditRecord{Operation: "import"})
+		if len(a.records) != 0 {
+			t.Fatal("expected no records")
+		}
+
+		a.setEnabled(true)
+		a.log(context.Background(), &AuditRecord{Operation: "import"})
+		if len(a.records) != 1 {
+			t.Fatal("expected a record")
+		}
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		dir, err := ioutil.TempDir(*TempDir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		a := newAuditLog(AuditOptions{Enabled: true}, dir)
+		a.records = make(chan *AuditRecord, 1)
+		for i := 0; i < 3; i++ {
+			a.log(context.Background(), &AuditRecord{Operation: "import"})
+		}
+		a.flush([]*AuditRecord{<-a.records})
+		if err := a.close(); err != nil {
+			t.Fatal(err)
+		}
+
+		records := readAuditRecords(t, filepath.Join(dir, "audit.log"))
+		if len(records) != 2 || records[1].Operation != "dropped" || records[1].Count != 2 {
+			t.Fatalf("unexpected records: %+v", records)
+		}
+	})
+}
@@ -69,6 +69,23 @@ const (
 	messageTypeRecalculateCaches
 	messageTypeNodeEvent
 	messageTypeNodeStatus
+	messageTypeTransaction
+	messageTypeSetIndexReadOnly
+	messageTypeSetFieldTimeQuantum
+	messageTypeDeleteSession
+	messageTypeCreateIngestMapping
+	messageTypeDeleteIngestMapping
+	messageTypeClusterSettings
+	messageTypeSetIndexQuota
+	messageTypeRestoreIndex
+	messageTypePurgeTrash
+	messageTypeRebuildExistence
+	messageTypeWarmJob
+	messageTypeMaintenance
+	messageTypeNodeResources
+	messageTypeSyncShard
+	messageTypeCatchingUp
+	messageTypeSetIndexDefaultFieldOptions
 )
 
 // MarshalInternalMessage serializes the pilosa message and adds pilosa internal
@@ -116,6 +133,40 @@ func getMessage(typ byte) Message {
 		return &NodeEvent{}
 	case messageTypeNodeStatus:
 		return &NodeStatus{}
+	case messageTypeTransaction:
+		return &TransactionMessage{}
+	case messageTypeSetIndexReadOnly:
+		return &SetIndexReadOnlyMessage{}
+	case messageTypeSetFieldTimeQuantum:
+		return &SetFieldTimeQuantumMessage{}
+	case messageTypeDeleteSession:
+		return &DeleteSessionMessage{}
+	case messageTypeCreateIngestMapping:
+		return &CreateIngestMappingMessage{}
+	case messageTypeDeleteIngestMapping:
+		return &DeleteIngestMappingMessage{}
+	case messageTypeClusterSettings:
+		return &ClusterSettingsMessage{}
+	case messageTypeSetIndexQuota:
+		return &SetIndexQuotaMessage{}
+	case messageTypeRestoreIndex:
+		return &RestoreIndexMessage{}
+	case messageTypePurgeTrash:
+		return &PurgeTrashMessage{}
+	case messageTypeRebuildExistence:
+		return &RebuildExistenceMessage{}
+	case messageTypeWarmJob:
+		return &WarmJobMessage{}
+	case messageTypeMaintenance:
+		return &MaintenanceMessage{}
+	case messageTypeNodeResources:
+		return &NodeResourcesMessage{}
+	case messageTypeSyncShard:
+		return &SyncShardMessage{}
+	case messageTypeCatchingUp:
+		return &CatchingUpMessage{}
+	case messageTypeSetIndexDefaultFieldOptions:
+		return &SetIndexDefaultFieldOptionsMessage{}
 	default:
 		panic(fmt.Sprintf("unknown message type %d", typ))
 	}
@@ -155,6 +206,40 @@ func getMessageType(m Message) byte {
 		return messageTypeNodeEvent
 	case *NodeStatus:
 		return messageTypeNodeStatus
+	case *TransactionMessage:
+		return messageTypeTransaction
+	case *SetIndexReadOnlyMessage:
+		return messageTypeSetIndexReadOnly
+	case *SetFieldTimeQuantumMessage:
+		return messageTypeSetFieldTimeQuantum
+	case *DeleteSessionMessage:
+		return messageTypeDeleteSession
+	case *CreateIngestMappingMessage:
+		return messageTypeCreateIngestMapping
+	case *DeleteIngestMappingMessage:
+		return messageTypeDeleteIngestMapping
+	case *ClusterSettingsMessage:
+		return messageTypeClusterSettings
+	case *SetIndexQuotaMessage:
+		return messageTypeSetIndexQuota
+	case *RestoreIndexMessage:
+		return messageTypeRestoreIndex
+	case *PurgeTrashMessage:
+		return messageTypePurgeTrash
+	case *RebuildExistenceMessage:
+		return messageTypeRebuildExistence
+	case *WarmJobMessage:
+		return messageTypeWarmJob
+	case *MaintenanceMessage:
+		return messageTypeMaintenance
+	case *NodeResourcesMessage:
+		return messageTypeNodeResources
+	case *SyncShardMessage:
+		return messageTypeSyncShard
+	case *CatchingUpMessage:
+		return messageTypeCatchingUp
+	case *SetIndexDefaultFieldOptionsMessage:
+		return messageTypeSetIndexDefaultFieldOptions
 	default:
 		panic(fmt.Sprintf("don't have type for message %#v", m))
 	}
@@ -184,7 +184,8 @@ func (c *rankCache) Add(id uint64, n uint64) {
 func (c *rankCache) BulkAdd(id uint64, n uint64) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if n < c.thresholdValue {
+	// A count of 0 clears the cache value, as in Add.
+	if n < c.thresholdValue && n > 0 {
 		return
 	}
 
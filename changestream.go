@@ -0,0 +1,215 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultChangeStreamSize is the default number of events buffered by
+	// the change stream of a field on each node.
+	defaultChangeStreamSize = 10000
+
+	// defaultChangeStreamRetention is the default age after which events
+	// are dropped from a change stream.
+	defaultChangeStreamRetention = time.Hour
+)
+
+// Operations of change events.
+const (
+	ChangeOpSet      = "set"
+	ChangeOpClear    = "clear"
+	ChangeOpSetRow   = "setRow"
+	ChangeOpClearRow = "clearRow"
+	ChangeOpImport   = "import"
+)
+
+// changeStreamOptions configures the change streams of the fields of a
+// holder.
+type changeStreamOptions struct {
+	// Maximum number of events buffered by each field.
+	size int
+
+	// How long events are buffered. Zero keeps events until they are
+	// evicted by newer ones.
+	retention time.Duration
+}
+
+// defaultChangeStreamOptions returns the default changeStreamOptions.
+func defaultChangeStreamOptions() changeStreamOptions {
+	return changeStreamOptions{
+		size:      defaultChangeStreamSize,
+		retention: defaultChangeStreamRetention,
+	}
+}
+
+// FieldChange is a change of a row of a fragment, recorded by the node which
+// applied it once it was written to the op log of the fragment.
+type FieldChange struct {
+	// Seq is the sequence number of the change on the node.
+	Seq   uint64 `json:"seq"`
+	View  string `json:"view"`
+	Shard uint64 `json:"shard"`
+	Row   uint64 `json:"row"`
+	Op    string `json:"op"`
+
+	// Delta is the approximate number of bits set, if positive, or cleared,
+	// if negative, in the row. Imports count the bits they were given
+	// rather than the bits which changed.
+	Delta int64     `json:"delta"`
+	Time  time.Time `json:"time"`
+}
+
+// FieldChanges is the list of changes of a field on a node which follow a
+// sequence number.
+type FieldChanges struct {
+	Node    string        `json:"node"`
+	Changes []FieldChange `json:"changes"`
+
+	// Next is the sequence number to read the following changes from.
+	Next uint64 `json:"next"`
+
+	// Gap is true if changes which followed the sequence number were
+	// dropped, because the buffer overflowed, they expired, or the node
+	// restarted, so the consumer must assume any row may have changed.
+	Gap bool `json:"gap"`
+}
+
+// changeStream is a bounded buffer of the changes of the fragments of a
+// field on this node.
+type changeStream struct {
+	mu        sync.Mutex
+	events    []FieldChange
+	start     int // position of the oldest event in events
+	n         int // number of events
+	seq       uint64
+	retention time.Duration
+
+	// changed is closed when an event is recorded, if a reader waits.
+	changed chan struct{}
+}
+
+func newChangeStream(opt changeStreamOptions) *changeStream {
+	size := opt.size
+	if size <= 0 {
+		size = defaultChangeStreamSize
+	}
+	return &changeStream{
+		events:    make([]FieldChange, size),
+		retention: opt.retention,
+	}
+}
+
+// record appends an event to the stream, evicting the oldest event if the
+// buffer is full.
+func (s *changeStream) record(view string, shard, rowID uint64, op string, delta int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	e := FieldChange{
+		Seq:   s.seq,
+		View:  view,
+		Shard: shard,
+		Row:   rowID,
+		Op:    op,
+		Delta: delta,
+		Time:  time.Now().UTC(),
+	}
+	if s.n == len(s.events) {
+		s.events[s.start] = e
+		s.start = (s.start + 1) % len(s.events)
+	} else {
+		s.events[(s.start+s.n)%len(s.events)] = e
+		s.n++
+	}
+
+	if s.changed != nil {
+		close(s.changed)
+		s.changed = nil
+	}
+}
+
+// recordRows records an event for each row of deltas, in row order.
+func (s *changeStream) recordRows(view string, shard uint64, op string, deltas map[uint64]int64) {
+	rowIDs := make([]uint64, 0, len(deltas))
+	for rowID := range deltas {
+		rowIDs = append(rowIDs, rowID)
+	}
+	sort.Slice(rowIDs, func(i, j int) bool { return rowIDs[i] < rowIDs[j] })
+	for _, rowID := range rowIDs {
+		s.record(view, shard, rowID, op, deltas[rowID])
+	}
+}
+
+// read returns up to limit events which follow since, and a channel which
+// is closed when the next event is recorded.
+func (s *changeStream) read(since uint64, limit int) (changes []FieldChange, next uint64, gap bool, changed <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired events.
+	if s.retention > 0 {
+		cutoff := time.Now().Add(-s.retention)
+		for s.n > 0 && s.events[s.start].Time.Before(cutoff) {
+			s.events[s.start] = FieldChange{}
+			s.start = (s.start + 1) % len(s.events)
+			s.n--
+		}
+	}
+
+	// A sequence number past the stream was read before the node
+	// restarted.
+	if since > s.seq {
+		since, gap = 0, true
+	}
+	oldest := s.seq - uint64(s.n) + 1
+	if since+1 < oldest {
+		since, gap = oldest-1, true
+	}
+
+	for k := int(since + 1 - oldest); k < s.n && (limit <= 0 || len(changes) < limit); k++ {
+		changes = append(changes, s.events[(s.start+k)%len(s.events)])
+	}
+	next = since + uint64(len(changes))
+
+	if s.changed == nil {
+		s.changed = make(chan struct{})
+	}
+	return changes, next, gap, s.changed
+}
+
+// changes returns up to limit events which follow since, waiting up to wait
+// for an event if there is none.
+func (s *changeStream) changes(ctx context.Context, since uint64, limit int, wait time.Duration) (changes []FieldChange, next uint64, gap bool) {
+	changes, next, gap, changed := s.read(since, limit)
+	if len(changes) > 0 || gap || wait <= 0 {
+		return changes, next, gap
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-changed:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	changes, next, gap, _ = s.read(since, limit)
+	return changes, next, gap
+}
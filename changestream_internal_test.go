@@ -0,0 +1,90 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChangeStream(t *testing.T) {
+	rows := func(changes []FieldChange) (rowIDs []uint64) {
+		for _, ch := range changes {
+			rowIDs = append(rowIDs, ch.Row)
+		}
+		return rowIDs
+	}
+
+	t.Run("Overflow", func(t *testing.T) {
+		s := newChangeStream(changeStreamOptions{size: 3})
+		for rowID := uint64(1); rowID <= 5; rowID++ {
+			s.record(viewStandard, 0, rowID, ChangeOpSet, 1)
+		}
+
+		// Changes 1 and 2 were evicted.
+		changes, next, gap := s.changes(context.Background(), 1, 0, 0)
+		if !gap || next != 5 || len(changes) != 3 || changes[0].Seq != 3 {
+			t.Fatalf("unexpected changes %v, next %d, gap %v", rows(changes), next, gap)
+		}
+		changes, next, gap = s.changes(context.Background(), 2, 2, 0)
+		if gap || next != 4 || len(changes) != 2 || changes[1].Row != 4 {
+			t.Fatalf("unexpected changes %v, next %d, gap %v", rows(changes), next, gap)
+		}
+		if changes, next, gap = s.changes(context.Background(), 5, 0, 0); gap || next != 5 || len(changes) != 0 {
+			t.Fatalf("unexpected changes %v, next %d, gap %v", rows(changes), next, gap)
+		}
+	})
+
+	// A sequence number past the stream was read before the node restarted.
+	t.Run("Restart", func(t *testing.T) {
+		s := newChangeStream(changeStreamOptions{size: 3})
+		s.record(viewStandard, 0, 1, ChangeOpSet, 1)
+		if changes, next, gap := s.changes(context.Background(), 10, 0, 0); !gap || next != 1 || len(changes) != 1 {
+			t.Fatalf("unexpected changes %v, next %d, gap %v", rows(changes), next, gap)
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		s := newChangeStream(changeStreamOptions{size: 3, retention: time.Minute})
+		s.record(viewStandard, 0, 1, ChangeOpSet, 1)
+		s.record(viewStandard, 0, 2, ChangeOpSet, 1)
+		s.events[0].Time = time.Now().Add(-2 * time.Minute)
+
+		if changes, next, gap := s.changes(context.Background(), 0, 0, 0); !gap || next != 2 || len(changes) != 1 || changes[0].Row != 2 {
+			t.Fatalf("unexpected changes %v, next %d, gap %v", rows(changes), next, gap)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		s := newChangeStream(changeStreamOptions{size: 3})
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			s.recordRows(viewStandard, 1, ChangeOpImport, map[uint64]int64{7: 2, 3: -1})
+		}()
+		changes, next, gap := s.changes(context.Background(), 0, 0, 10*time.Second)
+		if gap || next != 2 || len(changes) != 2 || changes[0].Row != 3 || changes[0].Delta != -1 || changes[1].Row != 7 {
+			t.Fatalf("unexpected changes %v, next %d, gap %v", rows(changes), next, gap)
+		}
+
+		// A reader waits no longer than asked.
+		start := time.Now()
+		if changes, _, _ := s.changes(context.Background(), 2, 0, 50*time.Millisecond); len(changes) != 0 {
+			t.Fatalf("unexpected changes %v", rows(changes))
+		} else if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Fatalf("unexpected elapsed time: %s", elapsed)
+		}
+	})
+}
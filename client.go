@@ -46,6 +46,7 @@ type FieldValue struct {
 type InternalClient interface {
 	MaxShardByIndex(ctx context.Context) (map[string]uint64, error)
 	Schema(ctx context.Context) ([]*IndexInfo, error)
+	SchemaNode(ctx context.Context, uri *URI) ([]*IndexInfo, error)
 	PostSchema(ctx context.Context, uri *URI, s *Schema, remote bool) error
 	CreateIndex(ctx context.Context, index string, opt IndexOptions) error
 	FragmentNodes(ctx context.Context, index string, shard uint64) ([]*Node, error)
@@ -69,6 +70,12 @@ type InternalClient interface {
 	SendMessage(ctx context.Context, uri *URI, msg []byte) error
 	RetrieveShardFromURI(ctx context.Context, index, field, view string, shard uint64, uri URI) (io.ReadCloser, error)
 	ImportRoaring(ctx context.Context, uri *URI, index, field string, shard uint64, remote bool, req *ImportRoaringRequest) error
+	NodeSettings(ctx context.Context, uri *URI) (map[string]*NodeSetting, error)
+	SchemaGeneration(ctx context.Context, uri *URI) (uint64, error)
+	WarmJob(ctx context.Context, uri *URI, id string) (*WarmJob, error)
+	FieldChanges(ctx context.Context, uri *URI, index, field string, since uint64, limit int) (*FieldChanges, error)
+	DeleteIndex(ctx context.Context, index string) error
+	DeleteField(ctx context.Context, index, field string) error
 }
 
 //===============
@@ -104,6 +111,9 @@ func (n nopInternalClient) MaxShardByIndex(context.Context) (map[string]uint64,
 	return nil, nil
 }
 func (n nopInternalClient) Schema(ctx context.Context) ([]*IndexInfo, error) { return nil, nil }
+func (n nopInternalClient) SchemaNode(ctx context.Context, uri *URI) ([]*IndexInfo, error) {
+	return nil, nil
+}
 func (n nopInternalClient) PostSchema(ctx context.Context, uri *URI, s *Schema, remote bool) error {
 	return nil
 }
@@ -172,3 +182,19 @@ func (n nopInternalClient) SendMessage(ctx context.Context, uri *URI, msg []byte
 func (n nopInternalClient) RetrieveShardFromURI(ctx context.Context, index, field, view string, shard uint64, uri URI) (io.ReadCloser, error) {
 	return nil, nil
 }
+func (n nopInternalClient) NodeSettings(ctx context.Context, uri *URI) (map[string]*NodeSetting, error) {
+	return nil, nil
+}
+func (n nopInternalClient) SchemaGeneration(ctx context.Context, uri *URI) (uint64, error) {
+	return 0, nil
+}
+func (n nopInternalClient) WarmJob(ctx context.Context, uri *URI, id string) (*WarmJob, error) {
+	return nil, nil
+}
+func (n nopInternalClient) FieldChanges(ctx context.Context, uri *URI, index, field string, since uint64, limit int) (*FieldChanges, error) {
+	return nil, nil
+}
+func (n nopInternalClient) DeleteIndex(ctx context.Context, index string) error { return nil }
+func (n nopInternalClient) DeleteField(ctx context.Context, index, field string) error {
+	return nil
+}
@@ -15,6 +15,7 @@
 package pilosa
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -65,6 +67,14 @@ const (
 	confirmDownRetries = 10
 	confirmDownSleep   = 1
 	confirmDownTimeout = 2
+
+	// coldStartInterval is the time between attempts by the coordinator to
+	// contact absent nodes of the persisted topology during a cold start.
+	coldStartInterval = time.Second
+
+	// schemaGenerationTimeout bounds the time to ask the nodes of a resize
+	// job for their schema generations.
+	schemaGenerationTimeout = 10 * time.Second
 )
 
 // Node represents a node in the cluster.
@@ -73,6 +83,22 @@ type Node struct {
 	URI           URI    `json:"uri"`
 	IsCoordinator bool   `json:"isCoordinator"`
 	State         string `json:"state"`
+	Maintenance   bool   `json:"maintenance,omitempty"`
+
+	// CatchingUp is set while the node may be missing writes applied by
+	// other replicas, from when it (re)joins the cluster until its first
+	// anti-entropy pass completes.
+	CatchingUp bool `json:"catchingUp,omitempty"`
+
+	// Resources is the latest sample of the resources of the node, which
+	// the node reports to the coordinator.
+	Resources *NodeResources `json:"resources,omitempty"`
+
+	// MinProtocolVersion and MaxProtocolVersion are the range of protocol
+	// versions the node speaks. Nodes which don't advertise them speak
+	// version 1.
+	MinProtocolVersion uint32 `json:"minProtocolVersion,omitempty"`
+	MaxProtocolVersion uint32 `json:"maxProtocolVersion,omitempty"`
 }
 
 func (n *Node) Clone() *Node {
@@ -184,6 +210,12 @@ type nodeAction struct {
 
 // cluster represents a collection of nodes.
 type cluster struct { // nolint: maligned
+	// Threshold for logging long-running queries. It can be changed while
+	// queries execute, so it is accessed atomically. It is the first field
+	// so that it is 64-bit aligned.
+	// TODO(2.0) move this out of cluster. (why is it here??)
+	longQueryTime int64
+
 	id    string
 	Node  *Node
 	nodes []*Node
@@ -197,10 +229,6 @@ type cluster struct { // nolint: maligned
 	// The number of replicas a partition has.
 	ReplicaN int
 
-	// Threshold for logging long-running queries
-	// TODO(2.0) move this out of cluster. (why is it here??)
-	longQueryTime time.Duration
-
 	// Maximum number of Set() or Clear() commands per request.
 	maxWritesPerRequest int
 
@@ -208,6 +236,31 @@ type cluster struct { // nolint: maligned
 	Path     string
 	Topology *Topology
 
+	// lastStatus is the last cluster status persisted to disk, including
+	// the last known address of nodes which are currently absent.
+	lastStatus *ClusterStatus
+
+	// coldStartQuorum is the fraction of the persisted topology which must
+	// be running for the coordinator to bring a restarted cluster to NORMAL.
+	// Zero waits for every node in the topology.
+	coldStartQuorum float64
+
+	// coldStart is true from startup until every node in the persisted
+	// topology has rejoined the cluster.
+	coldStart bool
+
+	// forceSingleNode discards the persisted topology so that this node
+	// starts alone as the coordinator.
+	forceSingleNode bool
+
+	// checkJoinDisk refuses to add a node whose free disk is below the
+	// estimated size of the fragments it would receive.
+	checkJoinDisk bool
+
+	// minProtocolVersion refuses to add a node which doesn't speak at least
+	// this protocol version.
+	minProtocolVersion uint32
+
 	// Required for cluster Resize.
 	Static      bool // Static is primarily used for testing in a non-gossip environment.
 	state       string
@@ -224,6 +277,10 @@ type cluster struct { // nolint: maligned
 
 	abortAntiEntropyCh chan struct{}
 
+	// syncingSchema is true while the schema is synced from the coordinator,
+	// so that the statuses received meanwhile don't sync it again.
+	syncingSchema bool
+
 	mu         sync.RWMutex
 	jobs       map[int64]*resizeJob
 	currentJob *resizeJob
@@ -275,6 +332,16 @@ func (c *cluster) abortAntiEntropyQ() bool {
 	}
 }
 
+// LongQueryTime returns the threshold for logging long-running queries.
+func (c *cluster) LongQueryTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.longQueryTime))
+}
+
+// setLongQueryTime sets the threshold for logging long-running queries.
+func (c *cluster) setLongQueryTime(d time.Duration) {
+	atomic.StoreInt64(&c.longQueryTime, int64(d))
+}
+
 // abortAntiEntropy blocks until the anti-entropy routine calls abortAntiEntropyQ
 func (c *cluster) abortAntiEntropy() {
 	if c.abortAntiEntropyCh != nil {
@@ -403,12 +470,15 @@ func (c *cluster) addNode(node *Node) error {
 		return fmt.Errorf("Cluster.Topology is nil")
 	}
 	if !c.Topology.addID(node.ID) {
-		return nil
+		return c.saveClusterStatus()
 	}
 	c.Topology.nodeStates[node.ID] = node.State
 
 	// save topology
-	return c.saveTopology()
+	if err := c.saveTopology(); err != nil {
+		return err
+	}
+	return c.saveClusterStatus()
 }
 
 // removeNode removes a node from the Cluster and updates and saves the
@@ -430,11 +500,14 @@ func (c *cluster) removeNode(nodeID string) error {
 		return fmt.Errorf("Cluster.Topology is nil")
 	}
 	if !c.Topology.removeID(nodeID) {
-		return nil
+		return c.saveClusterStatus()
 	}
 
 	// save topology
-	return c.saveTopology()
+	if err := c.saveTopology(); err != nil {
+		return err
+	}
+	return c.saveClusterStatus()
 }
 
 // nodeIDs returns the list of IDs in the cluster.
@@ -466,6 +539,11 @@ func (c *cluster) SetState(state string) {
 }
 
 func (c *cluster) unprotectedSetState(state string) {
+	// The cold start is over once every node in the topology has rejoined.
+	if c.coldStart && state == ClusterStateNormal && c.haveTopologyAgreement() {
+		c.coldStart = false
+	}
+
 	// Ignore cases where the state hasn't changed.
 	if state == c.state {
 		return
@@ -537,6 +615,107 @@ func (c *cluster) setNodeState(state string) error { // nolint: unparam
 	return nil
 }
 
+// inMaintenance returns true if this node is in maintenance mode.
+func (c *cluster) inMaintenance() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Node.Maintenance
+}
+
+// setMaintenance turns maintenance mode of this node on or off and
+// broadcasts it to the other nodes. The topology is unchanged; other nodes
+// only route reads away from a node in maintenance.
+func (c *cluster) setMaintenance(maintenance bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Node.Maintenance = maintenance
+	c.unprotectedSetNodeMaintenance(c.Node.ID, maintenance)
+	c.logger.Printf("set maintenance %v", maintenance)
+
+	if c.Static {
+		return nil
+	}
+	return c.unprotectedSendSync(&MaintenanceMessage{NodeID: c.Node.ID, Maintenance: maintenance})
+}
+
+// receiveMaintenance sets the maintenance flag of another node.
+func (c *cluster) receiveMaintenance(nodeID string, maintenance bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nodeID == c.Node.ID {
+		return
+	}
+	c.unprotectedSetNodeMaintenance(nodeID, maintenance)
+	c.logger.Printf("received maintenance %v (%s)", maintenance, nodeID)
+}
+
+// unprotectedSetNodeMaintenance sets the maintenance flag of a node in the
+// cluster's node list.
+func (c *cluster) unprotectedSetNodeMaintenance(nodeID string, maintenance bool) {
+	for _, n := range c.nodes {
+		if n.ID == nodeID {
+			n.Maintenance = maintenance
+		}
+	}
+}
+
+// isCatchingUp returns true if this node is catching up with the other
+// replicas.
+func (c *cluster) isCatchingUp() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Node.CatchingUp
+}
+
+// startCatchingUp marks this node as catching up before it joins the
+// cluster. The other nodes learn it when the node joins.
+func (c *cluster) startCatchingUp() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Node.CatchingUp = true
+	c.unprotectedSetNodeCatchingUp(c.Node.ID, true)
+}
+
+// setCatchingUp marks this node as catching up with the other replicas, or
+// as caught up, and broadcasts it to the other nodes. Reads prefer other
+// replicas to a node which is catching up, unless they accept stale data.
+func (c *cluster) setCatchingUp(catchingUp bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Node.CatchingUp == catchingUp {
+		return nil
+	}
+	c.Node.CatchingUp = catchingUp
+	c.unprotectedSetNodeCatchingUp(c.Node.ID, catchingUp)
+	c.logger.Printf("set catching up %v", catchingUp)
+
+	if c.Static {
+		return nil
+	}
+	return c.unprotectedSendSync(&CatchingUpMessage{NodeID: c.Node.ID, CatchingUp: catchingUp})
+}
+
+// receiveCatchingUp sets the catching up flag of another node.
+func (c *cluster) receiveCatchingUp(nodeID string, catchingUp bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nodeID == c.Node.ID {
+		return
+	}
+	c.unprotectedSetNodeCatchingUp(nodeID, catchingUp)
+	c.logger.Printf("received catching up %v (%s)", catchingUp, nodeID)
+}
+
+// unprotectedSetNodeCatchingUp sets the catching up flag of a node in the
+// cluster's node list.
+func (c *cluster) unprotectedSetNodeCatchingUp(nodeID string, catchingUp bool) {
+	for _, n := range c.nodes {
+		if n.ID == nodeID {
+			n.CatchingUp = catchingUp
+		}
+	}
+}
+
 // receiveNodeState sets node state in Topology in order for the
 // Coordinator to keep track of, during startup, which nodes have
 // finished opening their Holder.
@@ -572,7 +751,7 @@ func (c *cluster) determineClusterState() (clusterState string) {
 	if c.state == ClusterStateResizing {
 		return ClusterStateResizing
 	}
-	if c.haveTopologyAgreement() && c.allNodesReady() {
+	if (c.haveTopologyAgreement() || c.haveColdStartQuorum()) && c.allNodesReady() {
 		return ClusterStateNormal
 	}
 	if len(c.Topology.nodeIDs)-len(c.nodeIDs()) < c.ReplicaN && c.allNodesReady() {
@@ -581,13 +760,27 @@ func (c *cluster) determineClusterState() (clusterState string) {
 	return ClusterStateStarting
 }
 
+// haveColdStartQuorum returns true if enough of the persisted topology is
+// running for a cold start to complete without the remaining nodes.
+// unprotected.
+func (c *cluster) haveColdStartQuorum() bool {
+	if !c.coldStart || c.coldStartQuorum <= 0 {
+		return false
+	}
+	return float64(len(c.nodes)) >= c.coldStartQuorum*float64(len(c.Topology.nodeIDs))
+}
+
 // unprotectedStatus returns the the cluster's status including what nodes it contains, its ID, and current state.
 func (c *cluster) unprotectedStatus() *ClusterStatus {
-	return &ClusterStatus{
+	cs := &ClusterStatus{
 		ClusterID: c.id,
 		State:     c.state,
 		Nodes:     c.nodes,
 	}
+	if c.holder != nil {
+		cs.SchemaGeneration = c.holder.SchemaGeneration()
+	}
+	return cs
 }
 
 func (c *cluster) nodeByID(id string) *Node {
@@ -632,12 +825,21 @@ func (c *cluster) nodePositionByID(nodeID string) int {
 func (c *cluster) addNodeBasicSorted(node *Node) bool {
 	n := c.unprotectedNodeByID(node.ID)
 	if n != nil {
-		if n.State != node.State || n.IsCoordinator != node.IsCoordinator || n.URI != node.URI {
+		// The protocol versions of a node don't change the topology.
+		n.MinProtocolVersion = node.MinProtocolVersion
+		n.MaxProtocolVersion = node.MaxProtocolVersion
+		if n.State != node.State || n.IsCoordinator != node.IsCoordinator || n.URI != node.URI || n.Maintenance != node.Maintenance || n.CatchingUp != node.CatchingUp {
 			n.State = node.State
 			n.IsCoordinator = node.IsCoordinator
 			n.URI = node.URI
+			n.Maintenance = node.Maintenance
+			n.CatchingUp = node.CatchingUp
+			n.Resources = newerResources(n.Resources, node.Resources)
 			return true
 		}
+		// A newer sample of the resources of a node doesn't change the
+		// topology.
+		n.Resources = newerResources(n.Resources, node.Resources)
 		return false
 	}
 
@@ -891,6 +1093,48 @@ func (c *cluster) shardNodes(index string, shard uint64) []*Node {
 	return c.partitionNodes(c.partition(index, shard))
 }
 
+// preferredShardNodes returns the nodes that own a fragment, with nodes in
+// maintenance mode, down or catching up last so that reads and clients
+// prefer the other replicas. Safe for concurrent use.
+func (c *cluster) preferredShardNodes(index string, shard uint64) []*Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.unprotectedPreferredShardNodes(index, shard, false)
+}
+
+// staleShardNodes returns the nodes that own a fragment for reads which
+// accept stale data: as preferredShardNodes, but nodes catching up aren't
+// avoided. Safe for concurrent use.
+func (c *cluster) staleShardNodes(index string, shard uint64) []*Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.unprotectedPreferredShardNodes(index, shard, true)
+}
+
+// unprotectedPreferredShardNodes returns the nodes that own a fragment, with
+// nodes catching up, unless stale is set, and then nodes in maintenance mode
+// or down last.
+func (c *cluster) unprotectedPreferredShardNodes(index string, shard uint64, stale bool) []*Node {
+	rank := func(n *Node) int {
+		if n.Maintenance || n.State == nodeStateDown {
+			return 2
+		} else if n.CatchingUp && !stale {
+			return 1
+		}
+		return 0
+	}
+	nodes := c.shardNodes(index, shard)
+	preferred := make([]*Node, 0, len(nodes))
+	for r := 0; r <= 2; r++ {
+		for _, n := range nodes {
+			if rank(n) == r {
+				preferred = append(preferred, n)
+			}
+		}
+	}
+	return preferred
+}
+
 // ownsShard returns true if a host owns a fragment.
 func (c *cluster) ownsShard(nodeID string, index string, shard uint64) bool {
 	c.mu.RLock()
@@ -967,6 +1211,21 @@ func (c *cluster) setup() error {
 		return errors.Wrap(err, "loading topology")
 	}
 
+	// Load the last known cluster status if it exists.
+	if err := c.loadClusterStatus(); err != nil {
+		return errors.Wrap(err, "loading cluster status")
+	}
+
+	if c.forceSingleNode {
+		c.logger.Printf("forcing single-node start, ignoring persisted topology: %v", c.Topology.nodeIDs)
+		c.Topology.nodeIDs = nil
+		c.lastStatus = nil
+		c.Coordinator = c.Node.ID
+		c.Node.IsCoordinator = true
+	} else if c.lastStatus != nil {
+		c.restoreClusterStatus()
+	}
+
 	c.id = c.Topology.clusterID
 
 	// Only the coordinator needs to consider the .topology file.
@@ -1012,6 +1271,15 @@ func (c *cluster) waitForStarted() error {
 			return fmt.Errorf("sending restart NodeJoin: %v", err)
 		}
 
+		// Also contact the coordinator from the persisted cluster status
+		// directly, since gossip may not have found it yet. The coordinator
+		// may not be running; it contacts this node once it starts.
+		if coord := c.persistedCoordinator(); coord != nil {
+			if err := c.sendTo(coord, msg); err != nil {
+				c.logger.Printf("sending NodeJoin to persisted coordinator %s: %v", coord.ID, err)
+			}
+		}
+
 		c.logger.Printf("%v wait for joining to complete", c.Node.ID)
 		<-c.joining
 		c.logger.Printf("joining has completed")
@@ -1036,6 +1304,11 @@ func (c *cluster) markAsJoined() {
 
 // needTopologyAgreement is unprotected.
 func (c *cluster) needTopologyAgreement() bool {
+	if c.coldStart && c.state == ClusterStateNormal {
+		// Nodes which were absent when a cold start reached its quorum
+		// rejoin without a resize.
+		return !stringSlicesAreEqual(c.Topology.nodeIDs, c.nodeIDs())
+	}
 	return (c.state == ClusterStateStarting || c.state == ClusterStateDegraded) && !stringSlicesAreEqual(c.Topology.nodeIDs, c.nodeIDs())
 }
 
@@ -1061,8 +1334,10 @@ func (c *cluster) allNodesReady() (ret bool) {
 }
 
 func (c *cluster) handleNodeAction(nodeAction nodeAction) error {
+	current := c.schemaCurrentNodes(nodeAction)
+
 	c.mu.Lock()
-	j, err := c.unprotectedGenerateResizeJob(nodeAction)
+	j, err := c.unprotectedGenerateResizeJob(nodeAction, current)
 	c.mu.Unlock()
 	if err != nil {
 		c.logger.Printf("generateResizeJob error: err=%s", err)
@@ -1121,6 +1396,9 @@ func (c *cluster) setStateAndBroadcast(state string) error { // nolint: unparam
 
 func (c *cluster) unprotectedSetStateAndBroadcast(state string) error {
 	c.unprotectedSetState(state)
+	if err := c.saveClusterStatus(); err != nil {
+		c.logger.Printf("saving cluster status: %v", err)
+	}
 	if c.Static {
 		return nil
 	}
@@ -1190,16 +1468,52 @@ func (c *cluster) listenForJoins() {
 	}()
 }
 
+// schemaCurrentNodes returns the IDs of the nodes, including a joining node,
+// which already have the schema of the local node, such as a node which was
+// provisioned with an exported schema. Their resize instructions leave out
+// the schema. Nodes which can't be asked are assumed to need the schema.
+func (c *cluster) schemaCurrentNodes(nodeAction nodeAction) map[string]bool {
+	// A zero generation may be from before generations were persisted,
+	// so it doesn't identify a schema.
+	if c.holder == nil || c.holder.SchemaGeneration() == 0 {
+		return nil
+	}
+	generation := c.holder.SchemaGeneration()
+
+	nodes := c.Nodes()
+	if nodeAction.action == resizeJobActionAdd {
+		nodes = append(nodes, nodeAction.node)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), schemaGenerationTimeout)
+	defer cancel()
+	current := make(map[string]bool)
+	for _, node := range nodes {
+		if node.ID == c.Node.ID {
+			current[node.ID] = true
+			continue
+		}
+		gen, err := c.InternalClient.SchemaGeneration(ctx, &node.URI)
+		if err != nil {
+			c.logger.Printf("getting schema generation of %s: %s", node.ID, err)
+			continue
+		}
+		current[node.ID] = gen == generation
+	}
+	return current
+}
+
 // unprotectedGenerateResizeJob creates a new resizeJob based on the new node being
 // added/removed. It also saves a reference to the resizeJob in the `jobs` map
-// for future lookup by JobID.
-func (c *cluster) unprotectedGenerateResizeJob(nodeAction nodeAction) (*resizeJob, error) {
+// for future lookup by JobID. Nodes in schemaCurrent don't get the schema in
+// their instructions.
+func (c *cluster) unprotectedGenerateResizeJob(nodeAction nodeAction, schemaCurrent map[string]bool) (*resizeJob, error) {
 	c.logger.Printf("generateResizeJob: %v", nodeAction)
 	if c.currentJob != nil {
 		return nil, fmt.Errorf("there is currently a resize job running")
 	}
 
-	j, err := c.unprotectedGenerateResizeJobByAction(nodeAction)
+	j, err := c.unprotectedGenerateResizeJobByAction(nodeAction, schemaCurrent)
 	if err != nil {
 		return nil, errors.Wrap(err, "generating job")
 	}
@@ -1218,7 +1532,7 @@ func (c *cluster) unprotectedGenerateResizeJob(nodeAction nodeAction) (*resizeJo
 // the difference between Cluster and a new Cluster with/without uri.
 // Broadcaster is associated to the resizeJob here for use in broadcasting
 // the resize instructions to other nodes in the cluster.
-func (c *cluster) unprotectedGenerateResizeJobByAction(nodeAction nodeAction) (*resizeJob, error) {
+func (c *cluster) unprotectedGenerateResizeJobByAction(nodeAction nodeAction, schemaCurrent map[string]bool) (*resizeJob, error) {
 	j := newResizeJob(c.nodes, nodeAction.node, nodeAction.action)
 	j.Broadcaster = c.broadcaster
 
@@ -1253,6 +1567,17 @@ func (c *cluster) unprotectedGenerateResizeJobByAction(nodeAction nodeAction) (*
 		}
 	}
 
+	// Refuse a node which can't store the data it would receive, rather than
+	// starting a transfer which fails.
+	if nodeAction.action == resizeJobActionAdd {
+		if err := c.unprotectedCheckJoinDisk(nodeAction.node, multiIndex[nodeAction.node.ID]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Encode the instructions so that every node of toCluster can read them.
+	version := protocolVersion(toCluster.nodes)
+
 	for id, sources := range multiIndex {
 		// If a host doesn't need to request data, mark it as complete.
 		if len(sources) == 0 {
@@ -1266,6 +1591,11 @@ func (c *cluster) unprotectedGenerateResizeJobByAction(nodeAction nodeAction) (*
 			Sources:       sources,
 			NodeStatus:    c.nodeStatus(), // Include the NodeStatus in order to ensure that schema and availableShards are in sync on the receiving node.
 			ClusterStatus: c.unprotectedStatus(),
+
+			ProtocolVersion: version,
+		}
+		if schemaCurrent[id] {
+			instr.NodeStatus.Schema = nil
 		}
 		j.Instructions = append(j.Instructions, instr)
 	}
@@ -1324,10 +1654,14 @@ func (c *cluster) followResizeInstruction(instr *ResizeInstruction) error {
 			defer span.Finish()
 
 			// Sync the NodeStatus received in the resize instruction.
-			// Sync schema.
-			c.logger.Debugf("holder applySchema")
-			if err := c.holder.applySchema(instr.NodeStatus.Schema); err != nil {
-				return errors.Wrap(err, "applying schema")
+			// Sync schema, unless the coordinator found it already current.
+			if instr.NodeStatus.Schema != nil {
+				c.logger.Debugf("holder applySchema")
+				if err := c.holder.applySchema(instr.NodeStatus.Schema); err != nil {
+					return errors.Wrap(err, "applying schema")
+				}
+			} else {
+				c.logger.Printf("schema is current, not applying schema from resize instruction")
 			}
 
 			// Sync available shards.
@@ -1344,6 +1678,7 @@ func (c *cluster) followResizeInstruction(instr *ResizeInstruction) error {
 					if err := f.AddRemoteAvailableShards(fs.AvailableShards); err != nil {
 						return errors.Wrap(err, "adding remote available shards")
 					}
+					f.AddRemoteMaxRowID(fs.MaxRowID)
 				}
 			}
 
@@ -1676,16 +2011,22 @@ func (c *cluster) loadTopology() error {
 	return nil
 }
 
-// saveTopology writes the current topology to disk. unprotected.
+// saveTopology writes the current topology to disk, unless the file already
+// holds the same encoding. unprotected.
 func (c *cluster) saveTopology() error {
+	buf, err := proto.Marshal(encodeTopology(c.Topology))
+	if err != nil {
+		return errors.Wrap(err, "marshalling")
+	}
 
-	if err := os.MkdirAll(c.Path, 0777); err != nil {
-		return errors.Wrap(err, "creating directory")
+	path := filepath.Join(c.Path, ".topology")
+	if prev, err := ioutil.ReadFile(path); err == nil && bytes.Equal(prev, buf) {
+		return nil
 	}
 
-	if buf, err := proto.Marshal(encodeTopology(c.Topology)); err != nil {
-		return errors.Wrap(err, "marshalling")
-	} else if err := ioutil.WriteFile(filepath.Join(c.Path, ".topology"), buf, 0666); err != nil {
+	if err := os.MkdirAll(c.Path, 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	} else if err := ioutil.WriteFile(path, buf, 0666); err != nil {
 		return errors.Wrap(err, "writing file")
 	}
 	return nil
@@ -1718,6 +2059,172 @@ func (c *cluster) considerTopology() error {
 	return nil
 }
 
+// loadClusterStatus reads the last known cluster status for the node.
+// unprotected.
+func (c *cluster) loadClusterStatus() error {
+	buf, err := ioutil.ReadFile(filepath.Join(c.Path, ".cluster"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "reading file")
+	}
+
+	var pb internal.ClusterStatus
+	if err := proto.Unmarshal(buf, &pb); err != nil {
+		return errors.Wrap(err, "unmarshalling")
+	}
+	c.lastStatus = decodeClusterStatus(&pb)
+	return nil
+}
+
+// saveClusterStatus writes the current cluster status to disk. Nodes in the
+// topology which are currently absent keep their last known entry so that
+// they can be reached on a cold start. unprotected.
+func (c *cluster) saveClusterStatus() error {
+	if c.Static || c.Path == "" {
+		return nil
+	}
+
+	cs := c.unprotectedStatus()
+	cs.Nodes = Nodes(cs.Nodes).Clone()
+	if c.lastStatus != nil {
+		for _, n := range c.lastStatus.Nodes {
+			if !Nodes(cs.Nodes).ContainsID(n.ID) && c.Topology.ContainsID(n.ID) {
+				cs.Nodes = append(cs.Nodes, n)
+			}
+		}
+		sort.Sort(byID(cs.Nodes))
+	}
+
+	if err := os.MkdirAll(c.Path, 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	}
+	if buf, err := proto.Marshal(encodeClusterStatus(cs)); err != nil {
+		return errors.Wrap(err, "marshalling")
+	} else if err := ioutil.WriteFile(filepath.Join(c.Path, ".cluster"), buf, 0666); err != nil {
+		return errors.Wrap(err, "writing file")
+	}
+	c.lastStatus = cs
+	return nil
+}
+
+// restoreClusterStatus pre-populates the topology and coordinator from the
+// last known cluster status. unprotected.
+func (c *cluster) restoreClusterStatus() {
+	cs := c.lastStatus
+	if c.Topology.clusterID == "" {
+		c.Topology.clusterID = cs.ClusterID
+	}
+	if len(c.Topology.nodeIDs) == 0 {
+		for _, n := range cs.Nodes {
+			c.Topology.addID(n.ID)
+		}
+	}
+	if c.Coordinator == "" {
+		for _, n := range cs.Nodes {
+			if n.IsCoordinator && n.ID != c.Node.ID {
+				c.Coordinator = n.ID
+			}
+		}
+	}
+	c.coldStart = len(c.Topology.nodeIDs) > 1
+	c.logger.Printf("loaded cluster status: state=%s coordinator=%s nodes=%v", cs.State, c.Coordinator, Nodes(cs.Nodes).IDs())
+}
+
+// persistedCoordinator returns the coordinator from the last known cluster
+// status if it is another node which has not joined yet.
+func (c *cluster) persistedCoordinator() *Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.lastStatus == nil || c.unprotectedIsCoordinator() || c.unprotectedNodeByID(c.Coordinator) != nil {
+		return nil
+	}
+	for _, n := range c.lastStatus.Nodes {
+		if n.ID == c.Coordinator {
+			return n.Clone()
+		}
+	}
+	return nil
+}
+
+// listenForColdStart contacts the nodes of the persisted topology which have
+// not joined the cluster. Nodes which can be reached are added to the
+// cluster without waiting for them to send a NodeJoin.
+func (c *cluster) listenForColdStart() {
+	if !c.isCoordinator() || c.Static {
+		return
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(coldStartInterval)
+		defer ticker.Stop()
+		for c.coldStartJoin() {
+			select {
+			case <-c.closing:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// coldStartJoin sends the cluster status to each absent node of the persisted
+// topology, adding the nodes which receive it. It returns false once the cold
+// start is over.
+func (c *cluster) coldStartJoin() bool {
+	c.mu.RLock()
+	if !c.coldStart || c.lastStatus == nil {
+		c.mu.RUnlock()
+		return false
+	} else if c.state == ClusterStateResizing {
+		c.mu.RUnlock()
+		return true
+	}
+	var absent []*Node
+	for _, n := range c.lastStatus.Nodes {
+		if c.Topology.ContainsID(n.ID) && c.unprotectedNodeByID(n.ID) == nil {
+			node := n.Clone()
+			node.IsCoordinator = false
+			node.State = nodeStateDown
+			absent = append(absent, node)
+		}
+	}
+	status := c.unprotectedStatus()
+	status.Nodes = Nodes(status.Nodes).Clone()
+	c.mu.RUnlock()
+
+	// Contact the nodes without holding the lock since unreachable nodes
+	// may not respond until the request times out.
+	var reached []*Node
+	for _, node := range absent {
+		if err := c.sendTo(node, status); err == nil {
+			reached = append(reached, node)
+		}
+	}
+	if len(reached) == 0 {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, node := range reached {
+		if c.unprotectedNodeByID(node.ID) != nil {
+			continue
+		}
+		c.logger.Printf("cold start: node %s is reachable at %s", node.ID, node.URI)
+		if err := c.addNode(node); err != nil {
+			c.logger.Printf("cold start: adding node %s: %v", node.ID, err)
+		}
+	}
+	if err := c.unprotectedSetStateAndBroadcast(c.determineClusterState()); err != nil {
+		c.logger.Printf("cold start: broadcasting cluster status: %v", err)
+	}
+	return c.coldStart
+}
+
 // band aid to protect against false nodeLeave events from memberlist
 // the test is the lightest weight endpoint of the node in question /version
 // TODO provide more robust solution to false nodeLeave events
@@ -1797,6 +2304,10 @@ func (c *cluster) nodeJoin(node *Node) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.logger.Printf("node join event on coordinator, node: %s, id: %s", node.URI, node.ID)
+	if err := c.unprotectedCheckProtocolVersion(node); err != nil {
+		c.logger.Printf("refusing node join: %v", err)
+		return err
+	}
 	if c.needTopologyAgreement() {
 		// A host that is not part of the topology can't be added to the STARTING cluster.
 		if !c.Topology.ContainsID(node.ID) {
@@ -1815,7 +2326,7 @@ func (c *cluster) nodeJoin(node *Node) error {
 		if ok, err := c.holder.HasData(); !ok && err == nil {
 			// If the result of the previous AddNode completed the joining of nodes
 			// in the topology, then change the state to NORMAL.
-			if c.haveTopologyAgreement() {
+			if c.haveTopologyAgreement() || c.haveColdStartQuorum() {
 				return c.unprotectedSetStateAndBroadcast(ClusterStateNormal)
 			}
 			return nil
@@ -1823,7 +2334,7 @@ func (c *cluster) nodeJoin(node *Node) error {
 			return errors.Wrap(err, "checking if holder has data")
 		}
 
-		if c.haveTopologyAgreement() && c.allNodesReady() {
+		if (c.haveTopologyAgreement() || c.haveColdStartQuorum()) && c.allNodesReady() {
 			return c.unprotectedSetStateAndBroadcast(ClusterStateNormal)
 		}
 		// Send the status to the remote node. This lets the remote node
@@ -1839,6 +2350,10 @@ func (c *cluster) nodeJoin(node *Node) error {
 			c.logger.Printf("node: %v changed URI from %s to %s", cnode.ID, cnode.URI, node.URI)
 			cnode.URI = node.URI
 		}
+		cnode.CatchingUp = node.CatchingUp
+		// The node may have been restarted with a different release.
+		cnode.MinProtocolVersion = node.MinProtocolVersion
+		cnode.MaxProtocolVersion = node.MaxProtocolVersion
 		return c.unprotectedSetStateAndBroadcast(c.determineClusterState())
 	}
 
@@ -1892,6 +2407,7 @@ func (c *cluster) nodeLeave(nodeID string) error {
 		nodeAction{
 			node:   &Node{ID: nodeID},
 			action: resizeJobActionRemove},
+		nil,
 	); err != nil {
 		return errors.Wrap(err, "generating job")
 	}
@@ -1933,6 +2449,7 @@ func (c *cluster) nodeStatus() *NodeStatus {
 			is.Fields = append(is.Fields, &FieldStatus{
 				Name:            f.Name,
 				AvailableShards: availableShards,
+				MaxRowID:        f.MaxRowID,
 			})
 		}
 		ns.Indexes = append(ns.Indexes, is)
@@ -1965,6 +2482,17 @@ func (c *cluster) mergeClusterStatus(cs *ClusterStatus) error {
 				}
 			}(node.State, c.Node.State)
 		}
+		// This node's maintenance and catching up flags, resources and
+		// protocol versions are only changed through this node.
+		if node.ID == c.Node.ID && (node.Maintenance != c.Node.Maintenance || node.CatchingUp != c.Node.CatchingUp || node.Resources != c.Node.Resources ||
+			node.MinProtocolVersion != c.Node.MinProtocolVersion || node.MaxProtocolVersion != c.Node.MaxProtocolVersion) {
+			node = node.Clone()
+			node.Maintenance = c.Node.Maintenance
+			node.CatchingUp = c.Node.CatchingUp
+			node.Resources = c.Node.Resources
+			node.MinProtocolVersion = c.Node.MinProtocolVersion
+			node.MaxProtocolVersion = c.Node.MaxProtocolVersion
+		}
 		if err := c.addNode(node); err != nil {
 			return errors.Wrap(err, "adding node")
 		}
@@ -1993,11 +2521,56 @@ func (c *cluster) mergeClusterStatus(cs *ClusterStatus) error {
 
 	c.unprotectedSetState(cs.State)
 
+	if err := c.saveClusterStatus(); err != nil {
+		c.logger.Printf("saving cluster status: %v", err)
+	}
+
 	c.markAsJoined()
 
+	// Sync the schema from the coordinator if it has drifted.
+	if c.holder != nil && !c.syncingSchema && cs.SchemaGeneration != c.holder.SchemaGeneration() {
+		if coord := c.unprotectedCoordinatorNode(); coord != nil {
+			c.syncingSchema = true
+			go func() {
+				defer func() {
+					c.mu.Lock()
+					c.syncingSchema = false
+					c.mu.Unlock()
+				}()
+				c.syncSchema(coord, cs.SchemaGeneration)
+			}()
+		}
+	}
+
 	return nil
 }
 
+// syncSchema applies the full schema from node and adopts its generation.
+// It waits for the holder to open so that a status received during startup
+// doesn't create indexes which are still being opened from disk. Indexes and
+// fields which node doesn't have are not deleted, since they may have just
+// been created, so the generation is only adopted if the schemas match.
+func (c *cluster) syncSchema(node *Node, generation uint64) {
+	c.holder.opened.Recv()
+	if c.holder.SchemaGeneration() == generation {
+		return
+	}
+	indexes, err := c.InternalClient.SchemaNode(context.Background(), &node.URI)
+	if err != nil {
+		c.logger.Printf("fetching schema from %s: %s", node.ID, err)
+		return
+	}
+	schema := &Schema{Indexes: indexes}
+	if err := c.holder.applySchema(schema); err != nil {
+		c.logger.Printf("applying schema from %s: %s", node.ID, err)
+		return
+	} else if !c.holder.matchesSchema(schema) {
+		c.logger.Printf("schema differs from %s after applying it, not adopting generation %d", node.ID, generation)
+		return
+	}
+	c.holder.adoptSchemaGeneration(generation)
+}
+
 // unprotectedPreviousNode returns the node listed before the current node in c.Nodes.
 // If there is only one node in the cluster, returns nil.
 // If the current node is the first node in the list, returns the last node.
@@ -2050,9 +2623,10 @@ func (c *cluster) setStatic(hosts []string) error {
 // ClusterStatus describes the status of the cluster including its
 // state and node topology.
 type ClusterStatus struct {
-	ClusterID string
-	State     string
-	Nodes     []*Node
+	ClusterID        string
+	State            string
+	Nodes            []*Node
+	SchemaGeneration uint64
 }
 
 // ResizeInstruction contains the instruction provided to a node
@@ -2064,6 +2638,10 @@ type ResizeInstruction struct {
 	Sources       []*ResizeSource
 	NodeStatus    *NodeStatus
 	ClusterStatus *ClusterStatus
+
+	// ProtocolVersion is the protocol version the instruction is encoded
+	// with. It isn't sent.
+	ProtocolVersion uint32
 }
 
 // ResizeSource is the source of data for a node acting on a
@@ -2079,15 +2657,56 @@ type ResizeSource struct {
 // Schema contains information about indexes and their configuration.
 type Schema struct {
 	Indexes []*IndexInfo
+
+	// Generation is the schema generation of the node which the schema was
+	// exported from, if any.
+	Generation uint64 `json:",omitempty"`
+}
+
+func encodeClusterStatus(cs *ClusterStatus) *internal.ClusterStatus {
+	pb := &internal.ClusterStatus{
+		ClusterID:        cs.ClusterID,
+		State:            cs.State,
+		SchemaGeneration: cs.SchemaGeneration,
+	}
+	for _, n := range cs.Nodes {
+		pb.Nodes = append(pb.Nodes, &internal.Node{
+			ID:            n.ID,
+			URI:           &internal.URI{Scheme: n.URI.Scheme, Host: n.URI.Host, Port: uint32(n.URI.Port)},
+			IsCoordinator: n.IsCoordinator,
+			State:         n.State,
+		})
+	}
+	return pb
+}
+
+func decodeClusterStatus(pb *internal.ClusterStatus) *ClusterStatus {
+	cs := &ClusterStatus{
+		ClusterID:        pb.ClusterID,
+		State:            pb.State,
+		SchemaGeneration: pb.SchemaGeneration,
+	}
+	for _, n := range pb.Nodes {
+		node := &Node{ID: n.ID, IsCoordinator: n.IsCoordinator, State: n.State}
+		if n.URI != nil {
+			node.URI = URI{Scheme: n.URI.Scheme, Host: n.URI.Host, Port: uint16(n.URI.Port)}
+		}
+		cs.Nodes = append(cs.Nodes, node)
+	}
+	return cs
 }
 
 func encodeTopology(topology *Topology) *internal.Topology {
 	if topology == nil {
 		return nil
 	}
+	// The IDs are kept sorted, but sort a copy anyway so that the encoding
+	// is the same for the same set of nodes.
+	ids := append([]string(nil), topology.nodeIDs...)
+	sort.Strings(ids)
 	return &internal.Topology{
 		ClusterID: topology.clusterID,
-		NodeIDs:   topology.nodeIDs,
+		NodeIDs:   ids,
 	}
 }
 
@@ -2121,8 +2740,66 @@ type CreateIndexMessage struct {
 }
 
 // DeleteIndexMessage is an internal message indicating index deletion.
+// The index is moved into the trash unless Purge is set.
 type DeleteIndexMessage struct {
 	Index string
+	Purge bool
+}
+
+// RestoreIndexMessage is an internal message indicating that a deleted index
+// was restored from the trash.
+type RestoreIndexMessage struct {
+	Index string
+}
+
+// PurgeTrashMessage is an internal message indicating that a deleted index
+// was removed from the trash.
+type PurgeTrashMessage struct {
+	Index string
+}
+
+// RebuildExistenceMessage is an internal message instructing a node to
+// rebuild the existence of the columns of an index from its fields.
+type RebuildExistenceMessage struct {
+	Index string
+}
+
+// WarmJobMessage is an internal message instructing a node to start its part
+// of a warm job, or to restart its failed tasks if it has the job.
+type WarmJobMessage struct {
+	ID      string
+	Indexes []string
+	Created time.Time
+}
+
+// SetIndexReadOnlyMessage is an internal message indicating a change to the
+// read-only flag of an index.
+type SetIndexReadOnlyMessage struct {
+	Index    string
+	ReadOnly bool
+}
+
+// SetIndexQuotaMessage is an internal message indicating a change to the disk
+// quota of an index.
+type SetIndexQuotaMessage struct {
+	Index string
+	Quota int64
+}
+
+// SetIndexDefaultFieldOptionsMessage is an internal message indicating a
+// change to the options which fields created in an index inherit.
+type SetIndexDefaultFieldOptionsMessage struct {
+	Index   string
+	Options *DefaultFieldOptions
+}
+
+// SetFieldTimeQuantumMessage is an internal message indicating a change to the
+// time quantum of a field.
+type SetFieldTimeQuantumMessage struct {
+	Index       string
+	Field       string
+	TimeQuantum TimeQuantum
+	Time        time.Time
 }
 
 // CreateFieldMessage is an internal message indicating field creation.
@@ -2183,6 +2860,20 @@ type NodeStateMessage struct {
 	State  string `protobuf:"bytes,2,opt,name=State,proto3" json:"State,omitempty"`
 }
 
+// MaintenanceMessage is an internal message for broadcasting a node's
+// maintenance mode.
+type MaintenanceMessage struct {
+	NodeID      string
+	Maintenance bool
+}
+
+// CatchingUpMessage is an internal message for broadcasting whether a node
+// is catching up with the other replicas.
+type CatchingUpMessage struct {
+	NodeID     string
+	CatchingUp bool
+}
+
 // NodeStatus is an internal message representing the contents of a node.
 type NodeStatus struct {
 	Node    *Node
@@ -2200,6 +2891,7 @@ type IndexStatus struct {
 type FieldStatus struct {
 	Name            string
 	AvailableShards *roaring.Bitmap
+	MaxRowID        uint64
 }
 
 // RecalculateCaches is an internal message for recalculating all caches
@@ -16,6 +16,7 @@ package pilosa
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
@@ -24,6 +25,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -34,6 +36,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/gorilla/mux"
 	"github.com/pilosa/pilosa/v2/logger"
+	"github.com/pilosa/pilosa/v2/pql"
 	"github.com/pilosa/pilosa/v2/roaring"
 	"github.com/pkg/errors"
 )
@@ -581,6 +584,56 @@ func TestCluster_Topology(t *testing.T) {
 	})
 }
 
+// Ensure the topology is only written when its encoding changes.
+func TestCluster_SaveTopology(t *testing.T) {
+	path, err := ioutil.TempDir("", "pilosa-cluster-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	c := NewTestCluster(1)
+	c.Path = path
+	c.Topology = &Topology{clusterID: "c", nodeIDs: []string{"node1", "node0"}, nodeStates: make(map[string]string)}
+	if err := c.saveTopology(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(path, ".topology")
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	modTime := func() time.Time {
+		t.Helper()
+		fi, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.ModTime()
+	}
+	if err := os.Chtimes(file, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same IDs in another order are encoded the same way.
+	c.Topology.nodeIDs = []string{"node0", "node1"}
+	if err := c.saveTopology(); err != nil {
+		t.Fatal(err)
+	} else if mt := modTime(); !mt.Equal(past) {
+		t.Fatalf("unchanged topology rewritten at %v", mt)
+	}
+
+	c.Topology.addID("node2")
+	if err := c.saveTopology(); err != nil {
+		t.Fatal(err)
+	} else if mt := modTime(); mt.Equal(past) {
+		t.Fatal("changed topology not written")
+	}
+	if err := c.loadTopology(); err != nil {
+		t.Fatal(err)
+	} else if exp := []string{"node0", "node1", "node2"}; !reflect.DeepEqual(c.Topology.nodeIDs, exp) {
+		t.Fatalf("unexpected topology: %v", c.Topology.nodeIDs)
+	}
+}
+
 // Ensure that general cluster functionality works as expected.
 func TestCluster_ResizeStates(t *testing.T) {
 
@@ -987,3 +1040,365 @@ func TestCluster_confirmNodeDownDown(t *testing.T) {
 	}
 
 }
+
+// schemaInternalClient returns the schema of a holder from SchemaNode.
+type schemaInternalClient struct {
+	nopInternalClient
+	holder *Holder
+}
+
+func (c schemaInternalClient) SchemaNode(ctx context.Context, uri *URI) ([]*IndexInfo, error) {
+	return c.holder.Schema(), nil
+}
+
+// Ensure that a node syncs the schema from the coordinator when the schema
+// generation in the cluster status differs from its own.
+func TestCluster_SchemaGenerationDrift(t *testing.T) {
+	tc := NewClusterCluster(2)
+	coord, c1 := tc.Clusters[0], tc.Clusters[1]
+	for _, c := range tc.Clusters {
+		if err := c.holder.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer c.holder.Close()
+	}
+	c1.InternalClient = schemaInternalClient{holder: coord.holder}
+
+	// Create an index only on the coordinator.
+	idx, err := coord.holder.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := idx.CreateField("f"); err != nil {
+		t.Fatal(err)
+	}
+
+	coord.mu.RLock()
+	status := coord.unprotectedStatus()
+	coord.mu.RUnlock()
+	if status.SchemaGeneration != coord.holder.SchemaGeneration() {
+		t.Fatalf("unexpected status generation: %d", status.SchemaGeneration)
+	} else if err := c1.mergeClusterStatus(status); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for c1.holder.Field("i", "f") == nil || c1.holder.SchemaGeneration() != status.SchemaGeneration {
+		if time.Now().After(deadline) {
+			t.Fatalf("schema not synced: generation=%d", c1.holder.SchemaGeneration())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A field deleted on the coordinator only is not deleted by the sync,
+	// so the generation isn't adopted until the schemas match again.
+	if _, err := idx.CreateField("g"); err != nil {
+		t.Fatal(err)
+	}
+	waitSync := func() {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			c1.mu.RLock()
+			syncing := c1.syncingSchema
+			c1.mu.RUnlock()
+			if !syncing {
+				return
+			} else if time.Now().After(deadline) {
+				t.Fatal("schema sync still running")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	merge := func() *ClusterStatus {
+		t.Helper()
+		coord.mu.RLock()
+		status := coord.unprotectedStatus()
+		coord.mu.RUnlock()
+		if err := c1.mergeClusterStatus(status); err != nil {
+			t.Fatal(err)
+		}
+		waitSync()
+		return status
+	}
+	if status := merge(); c1.holder.Field("i", "g") == nil || c1.holder.SchemaGeneration() != status.SchemaGeneration {
+		t.Fatalf("schema not synced: generation=%d", c1.holder.SchemaGeneration())
+	}
+	if err := idx.DeleteField("g"); err != nil {
+		t.Fatal(err)
+	}
+	if status := merge(); c1.holder.Field("i", "g") == nil {
+		t.Fatal("expected field to be kept")
+	} else if c1.holder.SchemaGeneration() == status.SchemaGeneration {
+		t.Fatal("expected generation not to be adopted")
+	}
+	if err := c1.holder.Index("i").DeleteField("g"); err != nil {
+		t.Fatal(err)
+	}
+	if status := merge(); c1.holder.SchemaGeneration() != status.SchemaGeneration {
+		t.Fatalf("expected generation to be adopted: %d, got %d", status.SchemaGeneration, c1.holder.SchemaGeneration())
+	}
+}
+
+// generationInternalClient reports the same schema generation for every node.
+type generationInternalClient struct {
+	nopInternalClient
+	generation uint64
+}
+
+func (c generationInternalClient) SchemaGeneration(ctx context.Context, uri *URI) (uint64, error) {
+	return c.generation, nil
+}
+
+// Ensure that the resize instructions of a node which already has the schema
+// of the coordinator leave out the schema.
+func TestCluster_ResizeSchemaCurrent(t *testing.T) {
+	tc := NewClusterCluster(1)
+	coord := tc.Clusters[0]
+	if err := coord.holder.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer coord.holder.Close()
+
+	idx, err := coord.holder.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := idx.CreateField("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for shard := uint64(0); shard < 16; shard++ {
+		if _, err := f.SetBit(1, shard*ShardWidth, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	action := nodeAction{node: &Node{ID: "node1"}, action: resizeJobActionAdd}
+	instruction := func(current map[string]bool) *ResizeInstruction {
+		coord.mu.Lock()
+		defer coord.mu.Unlock()
+		j, err := coord.unprotectedGenerateResizeJobByAction(action, current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, instr := range j.Instructions {
+			if instr.Node.ID == "node1" {
+				return instr
+			}
+		}
+		t.Fatal("no instruction for new node")
+		return nil
+	}
+
+	if instr := instruction(nil); instr.NodeStatus.Schema == nil {
+		t.Fatal("expected schema in instruction")
+	}
+
+	coord.InternalClient = generationInternalClient{generation: coord.holder.SchemaGeneration()}
+	current := coord.schemaCurrentNodes(action)
+	if !current["node1"] {
+		t.Fatalf("expected new node to be current: %v", current)
+	} else if instr := instruction(current); instr.NodeStatus.Schema != nil {
+		t.Fatal("expected no schema in instruction")
+	} else if len(instr.NodeStatus.Indexes) != 1 {
+		t.Fatalf("expected available shards in instruction: %v", instr.NodeStatus.Indexes)
+	}
+
+	coord.InternalClient = generationInternalClient{generation: coord.holder.SchemaGeneration() - 1}
+	if current := coord.schemaCurrentNodes(action); current["node1"] {
+		t.Fatal("expected new node not to be current")
+	}
+}
+
+func TestCluster_CheckJoinDisk(t *testing.T) {
+	tc := NewClusterCluster(1)
+	coord := tc.Clusters[0]
+	if err := coord.holder.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer coord.holder.Close()
+
+	idx, err := coord.holder.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := idx.CreateField("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for shard := uint64(0); shard < 16; shard++ {
+		if _, err := f.SetBit(1, shard*ShardWidth, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The coordinator reports fragments of 1MB.
+	coord.mu.Lock()
+	coord.unprotectedSetNodeResources(coord.Node.ID, &NodeResources{DataSize: 16 << 20, Fragments: 16, SampledAt: time.Now()})
+	coord.mu.Unlock()
+
+	generate := func(node *Node) error {
+		coord.mu.Lock()
+		defer coord.mu.Unlock()
+		_, err := coord.unprotectedGenerateResizeJobByAction(nodeAction{node: node, action: resizeJobActionAdd}, nil)
+		return err
+	}
+	small := &Node{ID: "node1", Resources: &NodeResources{DiskFree: 1 << 20, SampledAt: time.Now()}}
+	large := &Node{ID: "node1", Resources: &NodeResources{DiskFree: 1 << 30, SampledAt: time.Now()}}
+
+	// The check is off by default.
+	if err := generate(small); err != nil {
+		t.Fatal(err)
+	}
+
+	coord.checkJoinDisk = true
+	if err := generate(small); err == nil || !strings.Contains(err.Error(), "node node1 has 1048576 bytes of free disk") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := generate(large); err != nil {
+		t.Fatal(err)
+	} else if err := generate(&Node{ID: "node1"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure that nodes speaking different protocol versions use the newest
+// version they all speak, and that a node below the configured minimum is
+// refused.
+func TestCluster_ProtocolVersion(t *testing.T) {
+	tc := NewClusterCluster(0)
+	if err := tc.addNode(); err != nil {
+		t.Fatalf("adding node: %v", err)
+	}
+	node0 := tc.Clusters[0]
+	if err := tc.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer tc.Close()
+
+	if err := tc.CreateField("i", "f", OptFieldTypeDefault()); err != nil {
+		t.Fatalf("creating field: %v", err)
+	}
+	for shard := uint64(0); shard < 4; shard++ {
+		if err := tc.SetBit("i", "f", 1, shard*ShardWidth+1, nil); err != nil {
+			t.Fatalf("setting bit: %v", err)
+		}
+	}
+
+	// A node of an older release joins: the resize instructions and the
+	// cluster fall back to its version.
+	if err := tc.addNodeVersion(1); err != nil {
+		t.Fatalf("adding node: %v", err)
+	}
+	node1 := tc.Clusters[1]
+	if len(tc.instructions) == 0 {
+		t.Fatal("expected resize instructions")
+	}
+	for _, instr := range tc.instructions {
+		if instr.ProtocolVersion != 1 {
+			t.Fatalf("unexpected instruction protocol version: %d", instr.ProtocolVersion)
+		}
+	}
+	if v := node0.ProtocolVersion(); v != 1 {
+		t.Fatalf("unexpected coordinator protocol version: %d", v)
+	} else if v := node1.ProtocolVersion(); v != 1 {
+		t.Fatalf("unexpected node1 protocol version: %d", v)
+	} else if v := node1.Node.MaxProtocolVersion; v != 1 {
+		t.Fatalf("unexpected node1 max protocol version: %d", v)
+	}
+
+	// The node is upgraded and rejoins.
+	upgraded := node1.Node.Clone()
+	upgraded.MaxProtocolVersion = ProtocolVersion
+	if err := node0.ReceiveEvent(&NodeEvent{Event: NodeJoin, Node: upgraded}); err != nil {
+		t.Fatal(err)
+	}
+	if v := node0.ProtocolVersion(); v != ProtocolVersion {
+		t.Fatalf("unexpected coordinator protocol version after upgrade: %d", v)
+	}
+
+	// Once the minimum is raised, a node of the older release is refused.
+	node0.minProtocolVersion = ProtocolVersion
+	if err := tc.addNodeVersion(1); errors.Cause(err) != ErrProtocolVersion {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !strings.Contains(err.Error(), "node node2 speaks protocol versions up to 1, the cluster requires at least 2") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if node0.nodeByID("node2") != nil {
+		t.Fatal("expected node2 to be refused")
+	}
+
+	tc.instructions = nil
+	if err := tc.addNode(); err != nil {
+		t.Fatalf("adding node: %v", err)
+	}
+	for _, instr := range tc.instructions {
+		if instr.ProtocolVersion != ProtocolVersion {
+			t.Fatalf("unexpected instruction protocol version: %d", instr.ProtocolVersion)
+		}
+	}
+	if v := tc.Clusters[3].ProtocolVersion(); v != ProtocolVersion {
+		t.Fatalf("unexpected node3 protocol version: %d", v)
+	}
+}
+
+// queryRecorder is an internal query client which records the queries sent
+// to other nodes.
+type queryRecorder struct {
+	queries []string
+}
+
+func (r *queryRecorder) QueryNode(ctx context.Context, uri *URI, index string, queryRequest *QueryRequest) (*QueryResponse, error) {
+	r.queries = append(r.queries, queryRequest.Query)
+	return &QueryResponse{}, nil
+}
+
+// Ensure a node of an older protocol version is only sent the message types
+// and queries which it can read.
+func TestCluster_ProtocolVersionMixed(t *testing.T) {
+	c := NewTestCluster(2)
+	for _, n := range c.nodes {
+		n.MinProtocolVersion, n.MaxProtocolVersion = MinProtocolVersion, ProtocolVersion
+	}
+	old := c.nodes[1]
+
+	rec := &queryRecorder{}
+	e := newExecutor(OptExecutorInternalQueryClient(rec))
+	defer e.Close()
+	e.Cluster = c
+	e.Node = c.Node
+	q, err := pql.ParseString(`Count(Row(f=[1, 2], from="2010-01-01T00:00")) GroupBy(Rows(g), filter=Row(f=[3]))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		version uint32
+		query   string
+		err     error
+	}{
+		{ProtocolVersion, q.String(), nil},
+		{1, `Count(Union(Row(f=1, from="2010-01-01T00:00"), Row(f=2, from="2010-01-01T00:00")))` + "\n" + `GroupBy(Rows(_field="g"), filter=Union(Row(f=3)))`, ErrProtocolVersion},
+	} {
+		old.MaxProtocolVersion = tt.version
+		if v := c.ProtocolVersion(); v != tt.version {
+			t.Fatalf("unexpected protocol version: %d", v)
+		}
+
+		// Message types which are newer than the version aren't sent.
+		if err := checkMessageProtocolVersion(&SetIndexReadOnlyMessage{Index: "i", ReadOnly: true}, c.nodes...); errors.Cause(err) != tt.err {
+			t.Fatalf("version %d: unexpected error: %v", tt.version, err)
+		} else if err := checkMessageProtocolVersion(&CreateShardMessage{Index: "i", Field: "f", Shard: 1}, c.nodes...); err != nil {
+			t.Fatalf("version %d: unexpected error: %v", tt.version, err)
+		}
+
+		// Queries are written so that the node can parse them.
+		rec.queries = nil
+		if _, err := e.remoteExec(context.Background(), old, "i", q, []uint64{1}, &execOptions{}); err != nil {
+			t.Fatal(err)
+		} else if len(rec.queries) != 1 || rec.queries[0] != tt.query {
+			t.Fatalf("version %d: unexpected queries: %q", tt.version, rec.queries)
+		}
+	}
+	if s := q.String(); !strings.Contains(s, "Row(f=[1,2]") {
+		t.Fatalf("query modified: %s", s)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/v2/ctl"
+)
+
+var Differ *ctl.DiffCommand
+
+func newDiffCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	Differ = ctl.NewDiffCommand(stdin, stdout, stderr)
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare an index on two Pilosa clusters.",
+		Long: `
+Compares the fragments of an index on two clusters, such as a production
+cluster and its disaster recovery copy, and prints each block whose checksum
+differs. For each differing block the number of bits which are only set on
+each cluster is printed:
+
+	INDEX/FIELD/VIEW shard=SHARD block=BLOCK: N only on HOST, M only on OTHERHOST
+
+Only the standard view is compared unless other views are given, such as
+"bsig_FIELD" for int fields or "standard_2019" for time fields. Only the
+primary owner of each shard is compared. The clusters are read
+through the public fragment block endpoints and are not modified.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Differ.Run(context.Background())
+		},
+	}
+	flags := diffCmd.Flags()
+
+	flags.StringVarP(&Differ.Host, "host", "", "localhost:10101", "host:port of a node in the first cluster.")
+	flags.StringVarP(&Differ.OtherHost, "other-host", "", "", "host:port of a node in the second cluster.")
+	flags.StringVarP(&Differ.Index, "index", "i", "", "Pilosa index to compare")
+	flags.StringSliceVarP(&Differ.Fields, "field", "f", nil, "Fields to compare - default all fields")
+	flags.StringSliceVarP(&Differ.Views, "view", "", nil, "Views to compare - default standard")
+	ctl.SetTLSConfig(flags, &Differ.TLS.CertificatePath, &Differ.TLS.CertificateKeyPath, &Differ.TLS.CACertPath, &Differ.TLS.SkipVerify, &Differ.TLS.EnableClientVerification)
+
+	return diffCmd
+}
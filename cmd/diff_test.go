@@ -0,0 +1,52 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2/cmd"
+)
+
+func TestDiffHelp(t *testing.T) {
+	output, err := ExecNewRootCommand(t, "diff", "--help")
+	if !strings.Contains(output, "Usage:") ||
+		!strings.Contains(output, "Flags:") ||
+		!strings.Contains(output, "pilosa diff") || err != nil {
+		t.Fatalf("Command 'diff --help' not working, err: '%v', output: '%s'", err, output)
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	tests := []commandTest{
+		{
+			args: []string{"diff", "--other-host", "dr:10101", "--field", "f1,f2"},
+			env:  map[string]string{"PILOSA_HOST": "localhost:12345"},
+			cfgFileContent: `
+index = "myindex"
+`,
+			validation: func() error {
+				v := validator{}
+				v.Check(cmd.Differ.Host, "localhost:12345")
+				v.Check(cmd.Differ.OtherHost, "dr:10101")
+				v.Check(cmd.Differ.Index, "myindex")
+				v.Check(strings.Join(cmd.Differ.Fields, ","), "f1,f2")
+				return v.Error()
+			},
+		},
+	}
+	executeDry(t, tests)
+}
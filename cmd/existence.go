@@ -0,0 +1,64 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/v2/ctl"
+)
+
+var ExistenceRebuilder *ctl.ExistenceRebuildCommand
+
+func newExistenceCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	existenceCmd := &cobra.Command{
+		Use:   "existence",
+		Short: "Manage the existence of the columns of an index.",
+		Long: `
+Indexes which track existence record every column which has been written, so
+that Not() and Count() can tell which columns exist. Columns written before an
+index tracked existence are missing from it until it is rebuilt.
+`,
+	}
+	existenceCmd.AddCommand(newExistenceRebuildCommand(stdin, stdout, stderr))
+	return existenceCmd
+}
+
+func newExistenceRebuildCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	ExistenceRebuilder = ctl.NewExistenceRebuildCommand(stdin, stdout, stderr)
+	rebuildCmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild the existence of the columns of an index from its fields.",
+		Long: `
+Sets the existence of every column which has a bit or a value in any field of
+the index, on every node of the cluster, and turns existence tracking on for
+the index if it was off. Columns which were deleted from every field are not
+removed from the existence of the index; use DeleteColumn() for that.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExistenceRebuilder.Run(context.Background())
+		},
+	}
+	flags := rebuildCmd.Flags()
+
+	flags.StringVarP(&ExistenceRebuilder.Host, "host", "", "localhost:10101", "host:port of Pilosa.")
+	flags.StringVarP(&ExistenceRebuilder.Index, "index", "i", "", "Pilosa index to rebuild.")
+	ctl.SetTLSConfig(flags, &ExistenceRebuilder.TLS.CertificatePath, &ExistenceRebuilder.TLS.CertificateKeyPath, &ExistenceRebuilder.TLS.CACertPath, &ExistenceRebuilder.TLS.SkipVerify, &ExistenceRebuilder.TLS.EnableClientVerification)
+
+	return rebuildCmd
+}
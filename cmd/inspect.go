@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/spf13/cobra"
 
@@ -45,5 +46,52 @@ Inspects a data file and provides stats.
 			return inspector.Run(context.Background())
 		},
 	}
+	inspectCmd.AddCommand(newInspectFragmentCommand(stdin, stdout, stderr))
 	return inspectCmd
 }
+
+func newInspectFragmentCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	fragmentInspector := ctl.NewInspectFragmentCommand(stdin, stdout, stderr)
+	var rows, blocks []string
+
+	fragmentCmd := &cobra.Command{
+		Use:   "fragment <path>",
+		Short: "Inspect a fragment file.",
+		Long: `
+Inspects a fragment file without a running server, and prints its header,
+container counts, per-row column counts, block checksums and op log length.
+The columns of rows and the row/column pairs of blocks can be dumped too.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("path required")
+			} else if len(args) > 1 {
+				return fmt.Errorf("only one path allowed")
+			}
+			fragmentInspector.Path = args[0]
+			fragmentInspector.Rows = fragmentInspector.Rows[:0]
+			for _, s := range rows {
+				row, err := strconv.ParseUint(s, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid row: %s", s)
+				}
+				fragmentInspector.Rows = append(fragmentInspector.Rows, row)
+			}
+			fragmentInspector.Blocks = fragmentInspector.Blocks[:0]
+			for _, s := range blocks {
+				block, err := strconv.Atoi(s)
+				if err != nil || block < 0 {
+					return fmt.Errorf("invalid block: %s", s)
+				}
+				fragmentInspector.Blocks = append(fragmentInspector.Blocks, block)
+			}
+			return fragmentInspector.Run(context.Background())
+		},
+	}
+	flags := fragmentCmd.Flags()
+	flags.Uint64VarP(&fragmentInspector.ShardWidth, "shard-width", "", fragmentInspector.ShardWidth, "Shard width of the index of the fragment.")
+	flags.StringSliceVarP(&rows, "rows", "", nil, "Rows to dump the columns of.")
+	flags.StringSliceVarP(&blocks, "blocks", "", nil, "Blocks to dump the row/column pairs of.")
+	flags.BoolVarP(&fragmentInspector.JSON, "json", "", false, "Print JSON instead of text.")
+	return fragmentCmd
+}
@@ -40,3 +40,10 @@ func TestInspectMultiPath(t *testing.T) {
 		t.Fatalf("Command 'inspect' without args should error but: err: '%v', output: '%v'", err, output)
 	}
 }
+
+func TestInspectFragmentNoPath(t *testing.T) {
+	output, err := ExecNewRootCommand(t, "inspect", "fragment")
+	if err == nil || !strings.Contains(err.Error(), "path required") {
+		t.Fatalf("Command 'inspect fragment' without args should error but: err: '%v', output: '%v'", err, output)
+	}
+}
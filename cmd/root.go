@@ -70,10 +70,13 @@ Build Time: ` + pilosa.BuildTime + "\n",
 
 	rc.AddCommand(newCheckCommand(stdin, stdout, stderr))
 	rc.AddCommand(newConfigCommand(stdin, stdout, stderr))
+	rc.AddCommand(newDiffCommand(stdin, stdout, stderr))
+	rc.AddCommand(newExistenceCommand(stdin, stdout, stderr))
 	rc.AddCommand(newExportCommand(stdin, stdout, stderr))
 	rc.AddCommand(newGenerateConfigCommand(stdin, stdout, stderr))
 	rc.AddCommand(newImportCommand(stdin, stdout, stderr))
 	rc.AddCommand(newInspectCommand(stdin, stdout, stderr))
+	rc.AddCommand(newSchemaCommand(stdin, stdout, stderr))
 	rc.AddCommand(newServeCmd(stdin, stdout, stderr))
 	rc.AddCommand(newHolderCmd(stdin, stdout, stderr))
 
@@ -133,7 +136,34 @@ func setAllConfig(v *viper.Viper, flags *pflag.FlagSet, envPrefix string) error
 			return
 		}
 		var value string
-		if f.Value.Type() == "stringSlice" {
+		if f.Value.Type() == "stringArray" {
+			// The elements of a stringArray may contain commas, so they
+			// are set one by one rather than joined.
+			if f.Changed {
+				return
+			}
+			switch v := v.Get(f.Name).(type) {
+			case []string:
+				for _, e := range v {
+					if flagErr = f.Value.Set(e); flagErr != nil {
+						return
+					}
+				}
+			case []interface{}:
+				for _, e := range v {
+					if flagErr = f.Value.Set(fmt.Sprint(e)); flagErr != nil {
+						return
+					}
+				}
+			case string:
+				// An environment variable holds a single element, and
+				// the default is left as it is.
+				if v != "" && v != f.DefValue {
+					flagErr = f.Value.Set(v)
+				}
+			}
+			return
+		} else if f.Value.Type() == "stringSlice" {
 			// special handling is needed for stringSlice as v.GetString will
 			// always return "" in the case that the value is an actual string
 			// slice from a config file rather than a comma separated string
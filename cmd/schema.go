@@ -0,0 +1,113 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/v2/ctl"
+)
+
+var SchemaExporter *ctl.SchemaExportCommand
+var SchemaApplier *ctl.SchemaApplyCommand
+
+func newSchemaCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Export a schema from pilosa, or apply it to a new node.",
+		Long: `
+Exports the full schema of a cluster, and applies it to a node before the node
+is added to the cluster, so that the resize job which adds the node only has
+to move data.
+
+To pre-provision a new node:
+
+	1. Export the schema from any node of the cluster:
+
+		pilosa schema export --host cluster-node:10101 -o schema.pb
+
+	2. Start the new node on its own, with the data directory and bind
+	   address it will use in the cluster, but without the cluster
+	   configuration (such as gossip seeds), and apply the schema to it:
+
+		pilosa schema apply --host new-node:10101 -i schema.pb
+
+	3. Stop the new node and start it again with the cluster configuration.
+
+The schema includes index options, fields and views, but no data. The new node
+adopts the schema generation of the cluster if it has exactly the exported
+indexes and fields, and the resize job then leaves the schema out of its
+instructions. If the schema of the cluster changes after the export, the node
+is sent the whole schema when it joins, as usual.
+`,
+	}
+	schemaCmd.AddCommand(newSchemaExportCommand(stdin, stdout, stderr))
+	schemaCmd.AddCommand(newSchemaApplyCommand(stdin, stdout, stderr))
+	return schemaCmd
+}
+
+func newSchemaExportCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	SchemaExporter = ctl.NewSchemaExportCommand(stdin, stdout, stderr)
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the schema of a pilosa node.",
+		Long: `
+Exports the full schema of a node, and its schema generation, in the protobuf
+format accepted by "pilosa schema apply". If the OUTFILE is not specified then
+the output is written to STDOUT.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return SchemaExporter.Run(context.Background())
+		},
+	}
+	flags := exportCmd.Flags()
+
+	flags.StringVarP(&SchemaExporter.Host, "host", "", "localhost:10101", "host:port of Pilosa.")
+	flags.StringVarP(&SchemaExporter.Path, "output-file", "o", "", "File to write the schema to - default stdout")
+	ctl.SetTLSConfig(flags, &SchemaExporter.TLS.CertificatePath, &SchemaExporter.TLS.CertificateKeyPath, &SchemaExporter.TLS.CACertPath, &SchemaExporter.TLS.SkipVerify, &SchemaExporter.TLS.EnableClientVerification)
+
+	return exportCmd
+}
+
+func newSchemaApplyCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	SchemaApplier = ctl.NewSchemaApplyCommand(stdin, stdout, stderr)
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply an exported schema to a single pilosa node.",
+		Long: `
+Applies a schema from "pilosa schema export" to a single node, without sending
+it to the rest of the node's cluster. Indexes, fields and views which don't
+exist are created without data. Nothing is applied if an existing index or
+field has a different shard width, type or key setting. If the INFILE is not
+specified then the schema is read from STDIN.
+
+If the node uses TLS, the client must authenticate with a certificate which the
+node verifies (see --tls.enable-client-verification on the server).
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return SchemaApplier.Run(context.Background())
+		},
+	}
+	flags := applyCmd.Flags()
+
+	flags.StringVarP(&SchemaApplier.Host, "host", "", "localhost:10101", "host:port of Pilosa.")
+	flags.StringVarP(&SchemaApplier.Path, "input-file", "i", "", "File to read the schema from - default stdin")
+	ctl.SetTLSConfig(flags, &SchemaApplier.TLS.CertificatePath, &SchemaApplier.TLS.CertificateKeyPath, &SchemaApplier.TLS.CACertPath, &SchemaApplier.TLS.SkipVerify, &SchemaApplier.TLS.EnableClientVerification)
+
+	return applyCmd
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2/cmd"
+)
+
+func TestSchemaHelp(t *testing.T) {
+	for _, sub := range []string{"export", "apply"} {
+		output, err := ExecNewRootCommand(t, "schema", sub, "--help")
+		if !strings.Contains(output, "Usage:") ||
+			!strings.Contains(output, "Flags:") ||
+			!strings.Contains(output, "pilosa schema "+sub) || err != nil {
+			t.Fatalf("Command 'schema %s --help' not working, err: '%v', output: '%s'", sub, err, output)
+		}
+	}
+}
+
+func TestSchemaConfig(t *testing.T) {
+	tests := []commandTest{
+		{
+			args: []string{"schema", "export", "--output-file", "/somefile"},
+			env:  map[string]string{"PILOSA_HOST": "localhost:12345"},
+			validation: func() error {
+				v := validator{}
+				v.Check(cmd.SchemaExporter.Host, "localhost:12345")
+				v.Check(cmd.SchemaExporter.Path, "/somefile")
+				return v.Error()
+			},
+		},
+		{
+			args: []string{"schema", "apply", "-i", "/somefile"},
+			env:  map[string]string{"PILOSA_HOST": "localhost:12345"},
+			validation: func() error {
+				v := validator{}
+				v.Check(cmd.SchemaApplier.Host, "localhost:12345")
+				v.Check(cmd.SchemaApplier.Path, "/somefile")
+				return v.Error()
+			},
+		},
+	}
+	executeDry(t, tests)
+}
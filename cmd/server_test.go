@@ -159,6 +159,32 @@ func TestServerConfig(t *testing.T) {
 				return nil
 			},
 		},
+		// TEST 3
+		{
+			args: []string{"server", "--warmup.topn-fields", "i:f,i:g", "--translation.map-size", "100000"},
+			env: map[string]string{
+				"PILOSA_WARMUP_TIMEOUT": "30s",
+			},
+			cfgFileContent: `
+	bind = "localhost:0"
+	data-dir = "` + actualDataDir + `"
+	[cluster]
+		disabled = true
+	[warmup]
+		queries = [
+			"i:TopN(f, n=10)",
+			"i:Count(Row(f=1))",
+		]
+		timeout = "1m"
+	`,
+			validation: func() error {
+				v := validator{}
+				v.Check(cmd.Server.Config.Warmup.Queries, []string{"i:TopN(f, n=10)", "i:Count(Row(f=1))"})
+				v.Check(cmd.Server.Config.Warmup.TopNFields, []string{"i:f", "i:g"})
+				v.Check(cmd.Server.Config.Warmup.Timeout, toml.Duration(30*time.Second))
+				return v.Error()
+			},
+		},
 	}
 
 	// run server tests
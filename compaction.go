@@ -0,0 +1,161 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/roaring"
+	"github.com/pkg/errors"
+)
+
+// FragmentCompaction describes a compaction of the op log of a fragment:
+// the number of ops in the log and their total bit count, before and after.
+type FragmentCompaction struct {
+	OpsBefore int       `json:"opsBefore"`
+	OpNBefore int       `json:"opNBefore"`
+	OpsAfter  int       `json:"opsAfter"`
+	OpNAfter  int       `json:"opNAfter"`
+	Time      time.Time `json:"time"`
+}
+
+// CompactOps rewrites the op log of the fragment's data file so that it
+// only holds the net effect of the ops logged since the last snapshot. The
+// snapshot itself isn't rewritten.
+func (f *fragment) CompactOps() (*FragmentCompaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.unprotectedCompactOps(0)
+}
+
+// enqueueCompaction requests that the op log be checked for redundant ops,
+// and compacted if there are enough of them, by the snapshot queue workers.
+// Unlike snapshots, compactions aren't worth waiting for, so the request is
+// dropped if the queue is full; it's made again by a later op. Call this
+// only when the mutex is held.
+func (f *fragment) enqueueCompaction() {
+	if f.compactRequested || f.snapshotting {
+		return
+	}
+	if f.snapshotQueue == nil {
+		// in testing, there may be no holder to handle compactions.
+		if _, err := f.unprotectedCompactOps(f.MaxOpRedundancy); err != nil {
+			f.Logger.Printf("compaction failed: %v", err)
+		}
+		return
+	}
+	select {
+	case f.snapshotQueue <- f:
+		f.compactRequested = true
+	default:
+	}
+}
+
+// compactionDue reports whether enough ops were logged since the op log was
+// last checked for redundant ops to check it again. Call this only when the
+// mutex is held.
+func (f *fragment) compactionDue() bool {
+	if f.MaxOpRedundancy <= 0 {
+		return false
+	}
+	next := f.MaxOpN / 10
+	if f.compactCheckOps > next {
+		next = f.compactCheckOps
+	}
+	return f.ops >= next
+}
+
+// unprotectedCompactOps compacts the op log if the ratio of its bit changes
+// which have no net effect is at least minRedundancy. Returns nil if the op
+// log isn't compacted.
+//
+// The compacted file is written next to the data file, synced and renamed
+// over it, so the data file is always either the old one or the compacted
+// one. The mutex is held throughout, so writes which arrive meanwhile wait
+// and are then appended to the compacted op log.
+func (f *fragment) unprotectedCompactOps(minRedundancy float64) (*FragmentCompaction, error) {
+	// Imports which skip the op log leave changes which are only in memory
+	// until the following snapshot; the data file doesn't have them. Nor
+	// does it have the containers shared with child fragments, which would
+	// be lost by reopening the storage from it.
+	if f.storage == nil || f.storage.OpWriter == nil || f.ops == 0 || len(f.sharedKeys) > 0 {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading data file")
+	}
+	c, err := roaring.CompactOpLog(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "compacting op log")
+	}
+	if c.OpN > 0 && float64(c.OpN-c.CompactedOpN)/float64(c.OpN) < minRedundancy {
+		// Check again once the op log has doubled.
+		f.compactCheckOps = 2 * f.ops
+		return nil, nil
+	}
+
+	compactPath := f.path + compactExt
+	if err := func() error {
+		file, err := os.Create(compactPath)
+		if err != nil {
+			return errors.Wrap(err, "creating file")
+		}
+		defer file.Close()
+		bw := bufio.NewWriter(file)
+		if _, err := c.WriteTo(bw); err != nil {
+			return errors.Wrap(err, "writing")
+		} else if err := bw.Flush(); err != nil {
+			return errors.Wrap(err, "flushing")
+		} else if err := file.Sync(); err != nil {
+			return errors.Wrap(err, "syncing")
+		}
+		return file.Close()
+	}(); err != nil {
+		_ = os.Remove(compactPath)
+		return nil, errors.Wrap(err, "writing compacted file")
+	}
+
+	// Keep the old mapping until the storage is reopened from the
+	// compacted file, which unmaps it.
+	if err := f.closeStorage(false); err != nil {
+		return nil, errors.Wrap(err, "closing storage")
+	}
+	if err := os.Rename(compactPath, f.path); err != nil {
+		return nil, errors.Wrap(err, "renaming compacted file")
+	}
+	if err := f.openStorage(true); err != nil {
+		return nil, errors.Wrap(err, "opening storage")
+	}
+
+	fc := &FragmentCompaction{
+		OpsBefore: c.Ops,
+		OpNBefore: c.OpN,
+		OpsAfter:  c.CompactedOps,
+		OpNAfter:  c.CompactedOpN,
+		Time:      time.Now().UTC(),
+	}
+	f.compactions++
+	f.lastCompaction = fc
+	f.compactCheckOps = 0
+	f.stats.Count("compaction", 1, 1.0)
+	f.stats.Histogram("compactionOpsRemoved", float64(c.Ops-c.CompactedOps), 1.0)
+	f.Logger.Debugf("compacted op log of %s/%s/%s/%d: %d ops (%d bits) to %d ops (%d bits)", f.index, f.field, f.view, f.shard, c.Ops, c.OpN, c.CompactedOps, c.CompactedOpN)
+	return fc, nil
+}
@@ -41,12 +41,18 @@ func SetTLSConfig(flags *pflag.FlagSet, certificatePath *string, certificateKeyP
 
 // commandClient returns a pilosa.InternalHTTPClient for the command
 func commandClient(cmd CommandWithTLSSupport) (*http.InternalClient, error) {
+	return commandClientForHost(cmd, cmd.TLSHost())
+}
+
+// commandClientForHost returns a pilosa.InternalHTTPClient for host using the
+// TLS settings of the command.
+func commandClientForHost(cmd CommandWithTLSSupport, host string) (*http.InternalClient, error) {
 	tls := cmd.TLSConfiguration()
 	tlsConfig, err := server.GetTLSConfig(&tls, cmd.Logger())
 	if err != nil {
 		return nil, errors.Wrap(err, "getting tls config")
 	}
-	client, err := http.NewInternalClient(cmd.TLSHost(), http.GetHTTPClient(tlsConfig))
+	client, err := http.NewInternalClient(host, http.GetHTTPClient(tlsConfig))
 	if err != nil {
 		return nil, errors.Wrap(err, "getting internal client")
 	}
@@ -0,0 +1,268 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/http"
+	"github.com/pilosa/pilosa/v2/server"
+	"github.com/pkg/errors"
+)
+
+// DiffCommand represents a command for comparing the fragments of an index
+// on two clusters.
+type DiffCommand struct {
+	// Hosts of a node in each cluster.
+	Host      string
+	OtherHost string
+
+	// Name of the index to compare, and optionally the fields and views to
+	// compare. All fields are compared if Fields is empty. The standard view
+	// is compared if Views is empty.
+	Index  string
+	Fields []string
+	Views  []string
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewDiffCommand returns a new instance of DiffCommand.
+func NewDiffCommand(stdin io.Reader, stdout, stderr io.Writer) *DiffCommand {
+	return &DiffCommand{
+		CmdIO: pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// diffCluster holds the client and schema of one of the compared clusters.
+type diffCluster struct {
+	host     string
+	client   *http.InternalClient
+	index    *pilosa.IndexInfo
+	maxShard uint64
+}
+
+// Run compares the index on both clusters and prints each differing block.
+func (cmd *DiffCommand) Run(ctx context.Context) error {
+	if cmd.Index == "" {
+		return pilosa.ErrIndexRequired
+	} else if cmd.Host == "" || cmd.OtherHost == "" {
+		return errors.New("both hosts are required")
+	}
+
+	a, err := cmd.openCluster(ctx, cmd.Host)
+	if err != nil {
+		return err
+	}
+	b, err := cmd.openCluster(ctx, cmd.OtherHost)
+	if err != nil {
+		return err
+	}
+
+	fields := cmd.Fields
+	if len(fields) == 0 {
+		fields = fieldNames(a.index, b.index)
+	}
+	views := cmd.Views
+	if len(views) == 0 {
+		views = []string{"standard"}
+	}
+	maxShard := a.maxShard
+	if b.maxShard > maxShard {
+		maxShard = b.maxShard
+	}
+
+	var n int
+	for _, field := range fields {
+		for _, view := range views {
+			for shard := uint64(0); shard <= maxShard; shard++ {
+				m, err := cmd.diffFragment(ctx, a, b, field, view, shard)
+				if err != nil {
+					return errors.Wrapf(err, "comparing %s/%s/%s/%d", cmd.Index, field, view, shard)
+				}
+				n += m
+			}
+		}
+	}
+	fmt.Fprintf(cmd.Stdout, "%d differing blocks\n", n)
+	return nil
+}
+
+// openCluster returns a client and the schema of the index for host.
+func (cmd *DiffCommand) openCluster(ctx context.Context, host string) (*diffCluster, error) {
+	client, err := commandClientForHost(cmd, host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating client for %s", host)
+	}
+	c := &diffCluster{host: host, client: client}
+
+	schema, err := client.Schema(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting schema from %s", host)
+	}
+	for _, index := range schema {
+		if index.Name == cmd.Index {
+			c.index = index
+		}
+	}
+	if c.index == nil {
+		return nil, errors.Wrapf(pilosa.ErrIndexNotFound, "%s", host)
+	}
+
+	maxShards, err := client.MaxShardByIndex(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting shard count from %s", host)
+	}
+	c.maxShard = maxShards[cmd.Index]
+	return c, nil
+}
+
+// diffFragment prints the differing blocks of a fragment and returns the
+// number of differing blocks.
+func (cmd *DiffCommand) diffFragment(ctx context.Context, a, b *diffCluster, field, view string, shard uint64) (int, error) {
+	aURI, aBlocks, err := cmd.fragmentBlocks(ctx, a, field, view, shard)
+	if err != nil {
+		return 0, err
+	}
+	bURI, bBlocks, err := cmd.fragmentBlocks(ctx, b, field, view, shard)
+	if err != nil {
+		return 0, err
+	}
+
+	// Find blocks which are missing on either side or have different checksums.
+	checksums := make(map[int][]byte, len(bBlocks))
+	for _, blk := range bBlocks {
+		checksums[blk.ID] = blk.Checksum
+	}
+	var ids []int
+	for _, blk := range aBlocks {
+		if chksum, ok := checksums[blk.ID]; !ok || !bytes.Equal(chksum, blk.Checksum) {
+			ids = append(ids, blk.ID)
+		}
+		delete(checksums, blk.ID)
+	}
+	for id := range checksums {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		req := pilosa.BlockDataRequest{Index: cmd.Index, Field: field, View: view, Shard: shard, Block: uint64(id)}
+		aPairs, err := blockPairs(ctx, a.client, aURI, req)
+		if err != nil {
+			return 0, errors.Wrapf(err, "reading block %d from %s", id, a.host)
+		}
+		bPairs, err := blockPairs(ctx, b.client, bURI, req)
+		if err != nil {
+			return 0, errors.Wrapf(err, "reading block %d from %s", id, b.host)
+		}
+		aOnly, bOnly := diffPairs(aPairs, bPairs)
+		fmt.Fprintf(cmd.Stdout, "%s/%s/%s shard=%d block=%d: %d only on %s, %d only on %s\n",
+			cmd.Index, field, view, shard, id, aOnly, a.host, bOnly, b.host)
+	}
+	return len(ids), nil
+}
+
+// fragmentBlocks returns the block checksums of a fragment from the primary
+// node which owns the shard, along with the URI of that node. A missing
+// fragment has no blocks.
+func (cmd *DiffCommand) fragmentBlocks(ctx context.Context, c *diffCluster, field, view string, shard uint64) (*pilosa.URI, []pilosa.FragmentBlock, error) {
+	nodes, err := c.client.FragmentNodes(ctx, cmd.Index, shard)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "getting nodes from %s", c.host)
+	} else if len(nodes) == 0 {
+		return nil, nil, errors.Errorf("no nodes own shard %d on %s", shard, c.host)
+	}
+	uri := &nodes[0].URI
+
+	blocks, err := c.client.FragmentBlockChecksums(ctx, uri, cmd.Index, field, view, shard)
+	if errors.Cause(err) == pilosa.ErrFragmentNotFound {
+		return uri, nil, nil
+	} else if err != nil {
+		return nil, nil, errors.Wrapf(err, "getting blocks from %s", uri)
+	}
+	return uri, blocks, nil
+}
+
+// blockPairs returns the positions of all row/column pairs in a block,
+// following continuations until the block has been read.
+func blockPairs(ctx context.Context, client *http.InternalClient, uri *pilosa.URI, req pilosa.BlockDataRequest) ([]pilosa.Bit, error) {
+	var a []pilosa.Bit
+	for {
+		resp, err := client.FragmentBlockPairs(ctx, uri, &req)
+		if errors.Cause(err) == pilosa.ErrFragmentNotFound {
+			return a, nil
+		} else if err != nil {
+			return nil, err
+		}
+		for i := range resp.RowIDs {
+			a = append(a, pilosa.Bit{RowID: resp.RowIDs[i], ColumnID: resp.ColumnIDs[i]})
+		}
+		if resp.Continuation == 0 {
+			return a, nil
+		}
+		req.Continuation = resp.Continuation
+	}
+}
+
+// diffPairs returns the number of pairs only in a and only in b. Both must be
+// sorted by row and then column, as returned by blockPairs.
+func diffPairs(a, b []pilosa.Bit) (aOnly, bOnly int) {
+	for len(a) > 0 && len(b) > 0 {
+		switch x, y := a[0], b[0]; {
+		case x.RowID == y.RowID && x.ColumnID == y.ColumnID:
+			a, b = a[1:], b[1:]
+		case x.RowID < y.RowID || (x.RowID == y.RowID && x.ColumnID < y.ColumnID):
+			aOnly++
+			a = a[1:]
+		default:
+			bOnly++
+			b = b[1:]
+		}
+	}
+	return aOnly + len(a), bOnly + len(b)
+}
+
+// fieldNames returns the sorted names of the fields of either index.
+func fieldNames(a, b *pilosa.IndexInfo) []string {
+	m := make(map[string]struct{})
+	for _, index := range []*pilosa.IndexInfo{a, b} {
+		for _, field := range index.Fields {
+			m[field.Name] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (cmd *DiffCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *DiffCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/test"
+)
+
+func TestDiffCommand_Validation(t *testing.T) {
+	buf := bytes.Buffer{}
+	stdin, stdout, stderr := GetIO(buf)
+
+	cm := NewDiffCommand(stdin, stdout, stderr)
+	if err := cm.Run(context.Background()); err != pilosa.ErrIndexRequired {
+		t.Fatalf("Command not working, expect: %s, actual: '%s'", pilosa.ErrIndexRequired, err)
+	}
+
+	cm.Index = "i"
+	cm.Host = "localhost:10101"
+	if err := cm.Run(context.Background()); err == nil || err.Error() != "both hosts are required" {
+		t.Fatalf("Command not working, actual: '%v'", err)
+	}
+}
+
+func TestDiffCommand_Run(t *testing.T) {
+	a := test.MustRunCluster(t, 1)
+	defer a.Close()
+	b := test.MustRunCluster(t, 1)
+	defer b.Close()
+
+	for _, c := range []test.Cluster{a, b} {
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "g")
+		c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(%d, f=1) Set(2, g=3)`, pilosa.ShardWidth+1))
+	}
+	// Differ in a block of shard 1 and in a row of another block.
+	a.Query(t, "i", fmt.Sprintf(`Set(%d, f=1) Set(%d, f=1)`, pilosa.ShardWidth+2, pilosa.ShardWidth+3))
+	b.Query(t, "i", fmt.Sprintf(`Set(%d, f=1) Set(1, f=200)`, pilosa.ShardWidth+4))
+
+	stdout := &bytes.Buffer{}
+	cm := NewDiffCommand(strings.NewReader(""), stdout, ioutil.Discard)
+	cm.Host = a[0].API.Node().URI.HostPort()
+	cm.OtherHost = b[0].API.Node().URI.HostPort()
+	cm.Index = "i"
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := fmt.Sprintf("i/f/standard shard=0 block=2: 0 only on %[1]s, 1 only on %[2]s\n"+
+		"i/f/standard shard=1 block=0: 2 only on %[1]s, 1 only on %[2]s\n"+
+		"2 differing blocks\n", cm.Host, cm.OtherHost)
+	if got := stdout.String(); got != exp {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", got, exp)
+	}
+
+	// Restricting the comparison to fields which match finds no differences.
+	stdout.Reset()
+	cm.Fields = []string{"g"}
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if got := stdout.String(); got != "0 differing blocks\n" {
+		t.Fatalf("unexpected output: %s", got)
+	}
+}
+
+func TestDiffPairs(t *testing.T) {
+	a := []pilosa.Bit{{RowID: 0, ColumnID: 1}, {RowID: 0, ColumnID: 5}, {RowID: 1, ColumnID: 0}, {RowID: 3, ColumnID: 3}}
+	b := []pilosa.Bit{{RowID: 0, ColumnID: 5}, {RowID: 1, ColumnID: 0}, {RowID: 1, ColumnID: 2}}
+	if aOnly, bOnly := diffPairs(a, b); aOnly != 2 || bOnly != 1 {
+		t.Fatalf("unexpected diff: %d, %d", aOnly, bOnly)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"io"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/server"
+	"github.com/pkg/errors"
+)
+
+// ExistenceRebuildCommand represents a command for rebuilding the existence
+// of the columns of an index from its fields.
+type ExistenceRebuildCommand struct {
+	// Remote host and port.
+	Host string
+
+	// Name of the index.
+	Index string
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewExistenceRebuildCommand returns a new instance of ExistenceRebuildCommand.
+func NewExistenceRebuildCommand(stdin io.Reader, stdout, stderr io.Writer) *ExistenceRebuildCommand {
+	return &ExistenceRebuildCommand{
+		CmdIO: pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run executes the rebuild.
+func (cmd *ExistenceRebuildCommand) Run(ctx context.Context) error {
+	if cmd.Index == "" {
+		return pilosa.ErrIndexRequired
+	}
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+	n, err := client.RebuildExistence(ctx, cmd.Index)
+	if err != nil {
+		return errors.Wrap(err, "rebuilding existence")
+	}
+	cmd.Logger().Printf("rebuilt existence of index %s: %d columns", cmd.Index, n)
+	return nil
+}
+
+func (cmd *ExistenceRebuildCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *ExistenceRebuildCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
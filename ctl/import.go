@@ -23,6 +23,7 @@ import (
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pilosa/pilosa/v2"
@@ -52,6 +53,10 @@ type ImportCommand struct { // nolint: maligned
 	// Clear clears the import data as opposed to setting it.
 	Clear bool
 
+	// DropTimestamps imports timestamped bits into a field without a time
+	// quantum without their timestamps, instead of failing.
+	DropTimestamps bool
+
 	// Filenames to import from.
 	Paths []string `json:"paths"`
 
@@ -61,9 +66,15 @@ type ImportCommand struct { // nolint: maligned
 	// Enables sorting of data file before import.
 	Sort bool `json:"sort"`
 
+	// Time to wait before retrying an import into a read-only index.
+	ReadOnlyRetryInterval time.Duration `json:"readOnlyRetryInterval"`
+
 	// Reusable client.
 	client pilosa.InternalClient
 
+	// Shard width of the destination index.
+	shardWidth uint64
+
 	// Standard input/output
 	*pilosa.CmdIO
 
@@ -75,6 +86,8 @@ func NewImportCommand(stdin io.Reader, stdout, stderr io.Writer) *ImportCommand
 	return &ImportCommand{
 		CmdIO:      pilosa.NewCmdIO(stdin, stdout, stderr),
 		BufferSize: 10000000,
+
+		ReadOnlyRetryInterval: 10 * time.Second,
 	}
 }
 
@@ -123,9 +136,13 @@ func (cmd *ImportCommand) Run(ctx context.Context) error {
 	}
 
 	var useColumnKeys, useRowKeys bool
+	cmd.shardWidth = pilosa.ShardWidth
 	for _, index := range schema {
 		if index.Name == cmd.Index {
 			useColumnKeys = index.Options.Keys
+			if index.ShardWidth != 0 {
+				cmd.shardWidth = index.ShardWidth
+			}
 			for _, field := range index.Fields {
 				if field.Name == cmd.Field {
 					useRowKeys = field.Options.Keys
@@ -260,7 +277,9 @@ func (cmd *ImportCommand) importBits(ctx context.Context, useColumnKeys, useRowK
 	// If keys are used, all bits are sent to the primary translate store (i.e. coordinator).
 	if useColumnKeys || useRowKeys {
 		logger.Printf("importing keys: n=%d", len(bits))
-		if err := cmd.client.ImportK(ctx, cmd.Index, cmd.Field, bits, pilosa.OptImportOptionsClear(cmd.Clear)); err != nil {
+		if err := cmd.retryReadOnly(ctx, func() error {
+			return cmd.client.ImportK(ctx, cmd.Index, cmd.Field, bits, pilosa.OptImportOptionsClear(cmd.Clear), pilosa.OptImportOptionsDropTimestamps(cmd.DropTimestamps))
+		}); err != nil {
 			return errors.Wrap(err, "importing keys")
 		}
 		return nil
@@ -268,7 +287,7 @@ func (cmd *ImportCommand) importBits(ctx context.Context, useColumnKeys, useRowK
 
 	// Group bits by shard.
 	logger.Printf("grouping %d bits", len(bits))
-	bitsByShard := http.Bits(bits).GroupByShard()
+	bitsByShard := http.Bits(bits).GroupByShardWidth(cmd.shardWidth)
 
 	// Parse path into bits.
 	for shard, chunk := range bitsByShard {
@@ -277,7 +296,9 @@ func (cmd *ImportCommand) importBits(ctx context.Context, useColumnKeys, useRowK
 		}
 
 		logger.Printf("importing shard: %d, n=%d", shard, len(chunk))
-		if err := cmd.client.Import(ctx, cmd.Index, cmd.Field, shard, chunk, pilosa.OptImportOptionsClear(cmd.Clear)); err != nil {
+		if err := cmd.retryReadOnly(ctx, func() error {
+			return cmd.client.Import(ctx, cmd.Index, cmd.Field, shard, chunk, pilosa.OptImportOptionsClear(cmd.Clear), pilosa.OptImportOptionsDropTimestamps(cmd.DropTimestamps))
+		}); err != nil {
 			return errors.Wrap(err, "importing")
 		}
 	}
@@ -365,7 +386,9 @@ func (cmd *ImportCommand) importValues(ctx context.Context, useColumnKeys bool,
 	// If keys are used, all values are sent to the primary translate store (i.e. coordinator).
 	if useColumnKeys {
 		logger.Printf("importing keyed values: n=%d", len(vals))
-		if err := cmd.client.ImportValueK(ctx, cmd.Index, cmd.Field, vals); err != nil {
+		if err := cmd.retryReadOnly(ctx, func() error {
+			return cmd.client.ImportValueK(ctx, cmd.Index, cmd.Field, vals)
+		}); err != nil {
 			return errors.Wrap(err, "importing keys")
 		}
 		return nil
@@ -373,7 +396,7 @@ func (cmd *ImportCommand) importValues(ctx context.Context, useColumnKeys bool,
 
 	// Group vals by shard.
 	logger.Printf("grouping %d vals", len(vals))
-	valsByShard := http.FieldValues(vals).GroupByShard()
+	valsByShard := http.FieldValues(vals).GroupByShardWidth(cmd.shardWidth)
 
 	// Parse path into FieldValues.
 	for shard, vals := range valsByShard {
@@ -382,7 +405,9 @@ func (cmd *ImportCommand) importValues(ctx context.Context, useColumnKeys bool,
 		}
 
 		logger.Printf("importing shard: %d, n=%d", shard, len(vals))
-		if err := cmd.client.ImportValue(ctx, cmd.Index, cmd.Field, shard, vals, pilosa.OptImportOptionsClear(cmd.Clear)); err != nil {
+		if err := cmd.retryReadOnly(ctx, func() error {
+			return cmd.client.ImportValue(ctx, cmd.Index, cmd.Field, shard, vals, pilosa.OptImportOptionsClear(cmd.Clear))
+		}); err != nil {
 			return errors.Wrap(err, "importing values")
 		}
 	}
@@ -390,6 +415,25 @@ func (cmd *ImportCommand) importValues(ctx context.Context, useColumnKeys bool,
 	return nil
 }
 
+// retryReadOnly calls fn until it succeeds or fails for a reason other than
+// the index being read-only. Imports into a read-only index are paused rather
+// than aborted so that they resume once the index is writable again.
+func (cmd *ImportCommand) retryReadOnly(ctx context.Context, fn func() error) error {
+	for {
+		err := fn()
+		if err == nil || !strings.Contains(err.Error(), pilosa.ErrIndexReadOnly.Error()) {
+			return err
+		}
+
+		cmd.Logger().Printf("index %s is read-only, pausing import for %s", cmd.Index, cmd.ReadOnlyRetryInterval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cmd.ReadOnlyRetryInterval):
+		}
+	}
+}
+
 func (cmd *ImportCommand) TLSHost() string {
 	return cmd.Host
 }
@@ -22,9 +22,11 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pilosa/pilosa/v2"
 	"github.com/pilosa/pilosa/v2/test"
@@ -52,6 +54,62 @@ func TestImportCommand_Validation(t *testing.T) {
 	}
 }
 
+// Ensure an import into a read-only index pauses until the index is writable.
+func TestImportCommand_ReadOnly(t *testing.T) {
+	buf := bytes.Buffer{}
+	stdin, stdout, stderr := GetIO(buf)
+	cm := NewImportCommand(stdin, stdout, stderr)
+	file, err := ioutil.TempFile("", "import.csv")
+	if err != nil {
+		t.Fatalf("creating tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write([]byte("1,2\n3,4")); err != nil {
+		t.Fatalf("writing to tempfile: %v", err)
+	}
+	ctx := context.Background()
+
+	cluster := test.MustRunCluster(t, 1)
+	defer cluster.Close()
+	cmd := cluster[0]
+	cluster.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	if err := cmd.API.SetIndexReadOnly(ctx, "i", true); err != nil {
+		t.Fatal(err)
+	}
+
+	cm.Host = cmd.API.Node().URI.HostPort()
+	cm.Index = "i"
+	cm.Field = "f"
+	cm.Paths = []string{file.Name()}
+	cm.ReadOnlyRetryInterval = 10 * time.Millisecond
+
+	errc := make(chan error, 1)
+	go func() { errc <- cm.Run(ctx) }()
+
+	// Wait for the import to be rejected before making the index writable.
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case err := <-errc:
+		t.Fatalf("import finished while index was read-only: %v", err)
+	default:
+	}
+	if err := cmd.API.SetIndexReadOnly(ctx, "i", false); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("Import Run doesn't work: %s", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("import did not resume")
+	}
+	if res := cluster.Query(t, "i", "Count(Union(Row(f=1), Row(f=3)))"); res.Results[0].(uint64) != 2 {
+		t.Fatalf("unexpected count: %v", res.Results[0])
+	}
+}
+
 func TestImportCommand_Basic(t *testing.T) {
 	t.Run("set", func(t *testing.T) {
 		buf := bytes.Buffer{}
@@ -306,7 +364,7 @@ func TestImportCommand_KeyReplication(t *testing.T) {
 		}
 
 		// Read body and unmarshal response.
-		exp := `{"results":[100]}` + "\n"
+		exp := `{"results":[100],"shards":[0]}` + "\n"
 		if body, err := ioutil.ReadAll(resp.Body); err != nil {
 			t.Fatalf("reading: %s", err)
 		} else if !reflect.DeepEqual(body, []byte(exp)) {
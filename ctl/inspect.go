@@ -16,9 +16,11 @@ package ctl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"syscall"
 	"text/tabwriter"
 	"time"
@@ -108,3 +110,103 @@ func (cmd *InspectCommand) Run(_ context.Context) error {
 
 	return nil
 }
+
+// InspectFragmentCommand represents a command for inspecting a fragment
+// storage file without a running server.
+type InspectFragmentCommand struct {
+	// Path to the fragment file.
+	Path string
+
+	// Shard width of the index of the fragment.
+	ShardWidth uint64
+
+	// Rows to dump the columns of.
+	Rows []uint64
+
+	// Blocks to dump the row/column pairs of.
+	Blocks []int
+
+	// Print JSON instead of text.
+	JSON bool
+
+	// Standard input/output
+	*pilosa.CmdIO
+}
+
+// NewInspectFragmentCommand returns a new instance of InspectFragmentCommand.
+func NewInspectFragmentCommand(stdin io.Reader, stdout, stderr io.Writer) *InspectFragmentCommand {
+	return &InspectFragmentCommand{
+		ShardWidth: pilosa.ShardWidth,
+		CmdIO:      pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run executes the inspect fragment command.
+func (cmd *InspectFragmentCommand) Run(_ context.Context) error {
+	if cmd.ShardWidth < 1<<16 || cmd.ShardWidth&(cmd.ShardWidth-1) != 0 {
+		return pilosa.ErrInvalidShardWidth
+	}
+	info, err := pilosa.InspectFragmentFile(cmd.Path, pilosa.FragmentInspectOptions{
+		ShardWidth: cmd.ShardWidth,
+		Rows:       cmd.Rows,
+		Blocks:     cmd.Blocks,
+	})
+	if err != nil {
+		return errors.Wrap(err, "inspecting fragment")
+	}
+
+	if cmd.JSON {
+		enc := json.NewEncoder(cmd.Stdout)
+		enc.SetIndent("", "  ")
+		return errors.Wrap(enc.Encode(info), "encoding")
+	}
+
+	fmt.Fprintf(cmd.Stdout, "== Fragment Info ==\n")
+	fmt.Fprintf(cmd.Stdout, "Path: %s\n", info.Path)
+	fmt.Fprintf(cmd.Stdout, "Size: %d\n", info.Size)
+	fmt.Fprintf(cmd.Stdout, "Flags: 0x%02x\n", info.Flags)
+	fmt.Fprintf(cmd.Stdout, "Shard width: %d\n", info.ShardWidth)
+	fmt.Fprintf(cmd.Stdout, "Containers: %d", info.Containers)
+	types := make([]string, 0, len(info.ContainerTypes))
+	for typ := range info.ContainerTypes {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		fmt.Fprintf(cmd.Stdout, " %s=%d", typ, info.ContainerTypes[typ])
+	}
+	fmt.Fprintln(cmd.Stdout, "")
+	fmt.Fprintf(cmd.Stdout, "Operations: %d (%d bits)\n", info.Ops, info.OpN)
+	fmt.Fprintln(cmd.Stdout, "")
+
+	fmt.Fprintln(cmd.Stdout, "== Rows ==")
+	tw := tabwriter.NewWriter(cmd.Stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\n", "ROW", "COUNT")
+	for _, row := range info.Rows {
+		fmt.Fprintf(tw, "%d\t%d\n", row.ID, row.Count)
+	}
+	tw.Flush()
+	fmt.Fprintln(cmd.Stdout, "")
+
+	fmt.Fprintln(cmd.Stdout, "== Blocks ==")
+	tw = tabwriter.NewWriter(cmd.Stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\n", "BLOCK", "CHECKSUM")
+	for _, blk := range info.Blocks {
+		fmt.Fprintf(tw, "%d\t%x\n", blk.ID, blk.Checksum)
+	}
+	tw.Flush()
+
+	for _, row := range info.RowData {
+		fmt.Fprintf(cmd.Stdout, "\n== Row %d ==\n", row.ID)
+		for _, col := range row.Columns {
+			fmt.Fprintln(cmd.Stdout, col)
+		}
+	}
+	for _, blk := range info.BlockData {
+		fmt.Fprintf(cmd.Stdout, "\n== Block %d ==\n", blk.ID)
+		for i := range blk.RowIDs {
+			fmt.Fprintf(cmd.Stdout, "%d,%d\n", blk.RowIDs[i], blk.ColumnIDs[i])
+		}
+	}
+	return nil
+}
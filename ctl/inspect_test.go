@@ -17,11 +17,16 @@ package ctl
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/roaring"
 )
 
 func TestInspectCommand_Run(t *testing.T) {
@@ -58,3 +63,46 @@ func TestInspectCommand_Run(t *testing.T) {
 
 	//	Todo: need correct roaring file for happy path
 }
+
+func TestInspectFragmentCommand_Run(t *testing.T) {
+	file, err := ioutil.TempFile("", "inspectFragmentTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	bm := roaring.NewBitmap(1, 2*pilosa.ShardWidth+5, 2*pilosa.ShardWidth+6)
+	if _, err := bm.WriteTo(file); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	var buf bytes.Buffer
+	cm := NewInspectFragmentCommand(os.Stdin, &buf, ioutil.Discard)
+	cm.Path = file.Name()
+	cm.Rows = []uint64{2}
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"Containers: 2 array=1 run=1", "2   2", "== Row 2 ==\n5\n6\n"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Fatalf("expected %q in output: %s", s, buf.String())
+		}
+	}
+
+	buf.Reset()
+	cm.JSON = true
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var info pilosa.FragmentInfo
+	if err := json.Unmarshal(buf.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(info.Rows, []pilosa.FragmentRowInfo{{ID: 0, Count: 1}, {ID: 2, Count: 2}}) {
+		t.Fatalf("unexpected rows: %v", info.Rows)
+	}
+
+	cm.ShardWidth = 1000
+	if err := cm.Run(context.Background()); err != pilosa.ErrInvalidShardWidth {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
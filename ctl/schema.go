@@ -0,0 +1,148 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/encoding/proto"
+	"github.com/pilosa/pilosa/v2/server"
+	"github.com/pkg/errors"
+)
+
+// SchemaExportCommand represents a command for exporting the full schema of
+// a server, which can be applied to a new node with SchemaApplyCommand.
+type SchemaExportCommand struct {
+	// Remote host and port.
+	Host string
+
+	// Filename to export to.
+	Path string
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewSchemaExportCommand returns a new instance of SchemaExportCommand.
+func NewSchemaExportCommand(stdin io.Reader, stdout, stderr io.Writer) *SchemaExportCommand {
+	return &SchemaExportCommand{
+		CmdIO: pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run executes the export.
+func (cmd *SchemaExportCommand) Run(ctx context.Context) error {
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+	schema, err := client.ExportSchema(ctx)
+	if err != nil {
+		return errors.Wrap(err, "exporting schema")
+	}
+	buf, err := proto.Serializer{}.Marshal(schema)
+	if err != nil {
+		return errors.Wrap(err, "marshalling schema")
+	}
+
+	// Use output file, if specified.
+	// Otherwise use STDOUT.
+	if cmd.Path != "" {
+		if err := ioutil.WriteFile(cmd.Path, buf, 0666); err != nil {
+			return errors.Wrap(err, "writing file")
+		}
+	} else if _, err := cmd.Stdout.Write(buf); err != nil {
+		return errors.Wrap(err, "writing")
+	}
+	cmd.Logger().Printf("exported %d indexes at schema generation %d", len(schema.Indexes), schema.Generation)
+	return nil
+}
+
+func (cmd *SchemaExportCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *SchemaExportCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
+
+// SchemaApplyCommand represents a command for applying an exported schema to
+// a single server, without sending it to the rest of its cluster.
+type SchemaApplyCommand struct {
+	// Remote host and port.
+	Host string
+
+	// Filename to read the exported schema from.
+	Path string
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewSchemaApplyCommand returns a new instance of SchemaApplyCommand.
+func NewSchemaApplyCommand(stdin io.Reader, stdout, stderr io.Writer) *SchemaApplyCommand {
+	return &SchemaApplyCommand{
+		CmdIO: pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run executes the apply.
+func (cmd *SchemaApplyCommand) Run(ctx context.Context) error {
+	// Use input file, if specified.
+	// Otherwise use STDIN.
+	var r io.Reader = cmd.Stdin
+	if cmd.Path != "" {
+		f, err := os.Open(cmd.Path)
+		if err != nil {
+			return errors.Wrap(err, "opening file")
+		}
+		defer f.Close()
+		r = f
+	}
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "reading schema")
+	}
+	schema := &pilosa.Schema{}
+	if err := (proto.Serializer{}).Unmarshal(buf, schema); err != nil {
+		return errors.Wrap(err, "unmarshalling schema")
+	}
+
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+	if err := client.ProvisionSchema(ctx, schema); err != nil {
+		return errors.Wrap(err, "applying schema")
+	}
+	cmd.Logger().Printf("applied %d indexes at schema generation %d", len(schema.Indexes), schema.Generation)
+	return nil
+}
+
+func (cmd *SchemaApplyCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *SchemaApplyCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/test"
+)
+
+func TestSchemaCommands(t *testing.T) {
+	src := test.MustRunCluster(t, 1)
+	defer src.Close()
+	dst := test.MustRunCluster(t, 1)
+	defer dst.Close()
+
+	ctx := context.Background()
+	src.CreateField(t, "i", pilosa.IndexOptions{Keys: true}, "f", pilosa.OptFieldTypeTime("YMD"))
+	src.CreateField(t, "i", pilosa.IndexOptions{Keys: true}, "v", pilosa.OptFieldTypeInt(0, 100))
+	src.Query(t, "i", `Set("a", f=1, 2019-01-02T00:00)`)
+
+	file, err := ioutil.TempFile("", "schema.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	buf := bytes.Buffer{}
+	stdin, stdout, stderr := GetIO(buf)
+	export := NewSchemaExportCommand(stdin, stdout, stderr)
+	export.Host = src[0].API.Node().URI.HostPort()
+	export.Path = file.Name()
+	if err := export.Run(ctx); err != nil {
+		t.Fatalf("exporting schema: %v", err)
+	}
+
+	apply := NewSchemaApplyCommand(stdin, stdout, stderr)
+	apply.Host = dst[0].API.Node().URI.HostPort()
+	apply.Path = file.Name()
+	if err := apply.Run(ctx); err != nil {
+		t.Fatalf("applying schema: %v", err)
+	}
+
+	// The node has the schema, views and generation, but no data.
+	idx, err := dst[0].API.Index(ctx, "i")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if !idx.Keys() {
+		t.Fatal("expected index with keys")
+	} else if f := idx.Field("v"); f == nil || f.Type() != pilosa.FieldTypeInt {
+		t.Fatalf("unexpected field: %v", f)
+	} else if views, err := dst[0].API.Views(ctx, "i", "f"); err != nil || len(views) != 4 {
+		t.Fatalf("unexpected views: %v %v", views, err)
+	} else if g, exp := dst[0].API.SchemaGeneration(ctx), src[0].API.SchemaGeneration(ctx); g != exp {
+		t.Fatalf("unexpected schema generation: %d, expected %d", g, exp)
+	} else if res := dst.Query(t, "i", `Count(Row(f=1))`); res.Results[0].(uint64) != 0 {
+		t.Fatalf("unexpected count: %v", res.Results[0])
+	}
+
+	// An index with a different shard width is not changed.
+	conflict := test.MustRunCluster(t, 1)
+	defer conflict.Close()
+	conflict.CreateField(t, "i", pilosa.IndexOptions{ShardWidth: 1 << 21}, "x")
+	apply.Host = conflict[0].API.Node().URI.HostPort()
+	if err := apply.Run(ctx); err == nil {
+		t.Fatal("expected conflict error")
+	}
+	cidx, err := conflict[0].API.Index(ctx, "i")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cidx.Close()
+	if cidx.Field("f") != nil {
+		t.Fatal("expected schema not to be applied")
+	}
+}
@@ -28,10 +28,12 @@ func BuildServerFlags(cmd *cobra.Command, srv *server.Command) {
 	flags.StringVarP(&srv.Config.Bind, "bind", "b", srv.Config.Bind, "Default URI on which pilosa should listen.")
 	flags.StringVar(&srv.Config.Advertise, "advertise", srv.Config.Advertise, "Address to advertise externally.")
 	flags.IntVarP(&srv.Config.MaxWritesPerRequest, "max-writes-per-request", "", srv.Config.MaxWritesPerRequest, "Number of write commands per request.")
+	flags.BoolVarP(&srv.Config.StrictImports, "strict-imports", "", srv.Config.StrictImports, "Reject imports of bits without timestamps into time fields without a standard view, instead of importing them with a warning.")
 	flags.StringVar(&srv.Config.LogPath, "log-path", srv.Config.LogPath, "Log path")
 	flags.BoolVar(&srv.Config.Verbose, "verbose", srv.Config.Verbose, "Enable verbose logging")
 	flags.Uint64Var(&srv.Config.MaxMapCount, "max-map-count", srv.Config.MaxMapCount, "Limits the maximum number of active mmaps. Pilosa will fall back to reading files once this is exhausted. Set below your system's vm.max_map_count.")
 	flags.Uint64Var(&srv.Config.MaxFileCount, "max-file-count", srv.Config.MaxFileCount, "Soft limit on the maximum number of fragment files Pilosa keeps open simultaneously.")
+	flags.DurationVar((*time.Duration)(&srv.Config.TopNCacheWait), "topn-cache-wait", (time.Duration)(srv.Config.TopNCacheWait), "Duration for which TopN() waits for a cache recalculated in the background after an import. 0 recalculates it immediately.")
 
 	// TLS
 	SetTLSConfig(flags, &srv.Config.TLS.CertificatePath, &srv.Config.TLS.CertificateKeyPath, &srv.Config.TLS.CACertPath, &srv.Config.TLS.SkipVerify, &srv.Config.TLS.EnableClientVerification)
@@ -45,6 +47,11 @@ func BuildServerFlags(cmd *cobra.Command, srv *server.Command) {
 	flags.IntVarP(&srv.Config.Cluster.ReplicaN, "cluster.replicas", "", 1, "Number of hosts each piece of data should be stored on.")
 	flags.StringSliceVarP(&srv.Config.Cluster.Hosts, "cluster.hosts", "", []string{}, "Comma separated list of hosts in cluster. Only used for testing.")
 	flags.DurationVarP((*time.Duration)(&srv.Config.Cluster.LongQueryTime), "cluster.long-query-time", "", time.Minute, "Duration that will trigger log and stat messages for slow queries.")
+	flags.Float64VarP(&srv.Config.Cluster.ColdStartQuorum, "cluster.cold-start-quorum", "", srv.Config.Cluster.ColdStartQuorum, "Fraction of the persisted node list which must be running for a restarted cluster to become NORMAL. 0 waits for every node.")
+	flags.BoolVarP(&srv.Config.Cluster.ForceSingleNode, "cluster.force-single-node", "", srv.Config.Cluster.ForceSingleNode, "Start as a single-node cluster, ignoring the persisted node list (for lab use).")
+	flags.DurationVarP((*time.Duration)(&srv.Config.Cluster.ResourceInterval), "cluster.resource-interval", "", (time.Duration)(srv.Config.Cluster.ResourceInterval), "Interval at which each node samples its disk, memory and load for the cluster status. 0 disables sampling.")
+	flags.BoolVarP(&srv.Config.Cluster.CheckJoinDisk, "cluster.check-join-disk", "", srv.Config.Cluster.CheckJoinDisk, "Refuse to add a node whose free disk is below the estimated size of the data it would receive.")
+	flags.Uint32VarP(&srv.Config.Cluster.MinProtocolVersion, "cluster.min-protocol-version", "", srv.Config.Cluster.MinProtocolVersion, "Refuse to add a node which doesn't speak at least this protocol version.")
 
 	// Translation
 	flags.StringVarP(&srv.Config.Translation.PrimaryURL, "translation.primary-url", "", srv.Config.Translation.PrimaryURL, "DEPRECATED: URL for primary translation node for replication.")
@@ -66,12 +73,81 @@ func BuildServerFlags(cmd *cobra.Command, srv *server.Command) {
 	flags.DurationVarP((*time.Duration)(&srv.Config.Gossip.Interval), "gossip.interval", "", (time.Duration)(srv.Config.Gossip.Interval), "Interval between sending messages that need to be gossiped that haven't piggybacked on probing messages.")
 	flags.DurationVarP((*time.Duration)(&srv.Config.Gossip.ToTheDeadTime), "gossip.to-the-dead-time", "", (time.Duration)(srv.Config.Gossip.ToTheDeadTime), "Interval after which a node has died that we will still try to gossip to it.")
 
+	// ResultHandles
+	flags.DurationVarP((*time.Duration)(&srv.Config.ResultHandles.TTL), "result-handles.ttl", "", (time.Duration)(srv.Config.ResultHandles.TTL), "Duration for which the stored query results of an idle session are kept.")
+	flags.Int64VarP(&srv.Config.ResultHandles.MaxMemory, "result-handles.max-memory", "", srv.Config.ResultHandles.MaxMemory, "Number of bytes of stored query results kept across all sessions. 0 is unlimited.")
+
+	// OperationIDs
+	flags.IntVarP(&srv.Config.OperationIDs.Max, "operation-ids.max", "", srv.Config.OperationIDs.Max, "Number of operation IDs of applied imports recorded by each fragment. 0 disables deduplication.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.OperationIDs.TTL), "operation-ids.ttl", "", (time.Duration)(srv.Config.OperationIDs.TTL), "Duration for which the operation ID of an applied import is recorded.")
+
+	// ChangeStreams
+	flags.IntVarP(&srv.Config.ChangeStreams.BufferSize, "change-streams.buffer-size", "", srv.Config.ChangeStreams.BufferSize, "Number of changes buffered by each node for each field which tracks changes.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.ChangeStreams.Retention), "change-streams.retention", "", (time.Duration)(srv.Config.ChangeStreams.Retention), "Duration for which a change of a field is buffered. 0 keeps changes until they are evicted by newer ones.")
+
+	// Audit
+	flags.BoolVarP(&srv.Config.Audit.Enabled, "audit.enabled", "", srv.Config.Audit.Enabled, "Record write operations in the audit log.")
+	flags.StringVarP(&srv.Config.Audit.Path, "audit.path", "", srv.Config.Audit.Path, "Path of the audit log file. Defaults to audit.log in the data directory.")
+	flags.Int64VarP(&srv.Config.Audit.MaxSize, "audit.max-size", "", srv.Config.Audit.MaxSize, "Size in bytes at which the audit log file is rotated.")
+	flags.IntVarP(&srv.Config.Audit.MaxBackups, "audit.max-backups", "", srv.Config.Audit.MaxBackups, "Number of rotated audit log files kept.")
+	flags.StringVarP(&srv.Config.Audit.Webhook, "audit.webhook", "", srv.Config.Audit.Webhook, "URL to which audit records are also posted.")
+
+	// DeleteJobs
+	flags.IntVarP(&srv.Config.DeleteJobs.Concurrency, "delete-jobs.concurrency", "", srv.Config.DeleteJobs.Concurrency, "Number of chunks of columns deleted at the same time by delete jobs.")
+	flags.IntVarP(&srv.Config.DeleteJobs.Rate, "delete-jobs.rate", "", srv.Config.DeleteJobs.Rate, "Largest number of columns deleted per second by delete jobs. 0 is unlimited.")
+
+	// WarmJobs
+	flags.IntVarP(&srv.Config.WarmJobs.Concurrency, "warm-jobs.concurrency", "", srv.Config.WarmJobs.Concurrency, "Number of fragments warmed at the same time by warm jobs.")
+	flags.IntVarP(&srv.Config.WarmJobs.Rate, "warm-jobs.rate", "", srv.Config.WarmJobs.Rate, "Largest number of bytes of fragments read per second by warm jobs. 0 is unlimited.")
+
+	// Warmup
+	flags.StringArrayVarP(&srv.Config.Warmup.Queries, "warmup.queries", "", srv.Config.Warmup.Queries, "Read query run by the node when it starts, prefixed with its index and a colon, such as events:TopN(browser). May be repeated.")
+	flags.StringSliceVarP(&srv.Config.Warmup.TopNFields, "warmup.topn-fields", "", srv.Config.Warmup.TopNFields, "Comma separated list of fields, as index:field, whose ranked caches are read when the node starts.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.Warmup.Timeout), "warmup.timeout", "", (time.Duration)(srv.Config.Warmup.Timeout), "Longest time for which the node isn't ready while it warms up. 0 waits for every warm-up query.")
+	flags.StringVarP(&srv.Config.Replication.Target, "replication.target", "", srv.Config.Replication.Target, "Address of a node of the cluster to which this cluster is replicated asynchronously. Empty disables replication.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.Replication.Interval), "replication.interval", "", (time.Duration)(srv.Config.Replication.Interval), "Time between the passes of replication.")
+	flags.IntVarP(&srv.Config.Replication.BatchSize, "replication.batch-size", "", srv.Config.Replication.BatchSize, "Maximum number of fragments shipped by a pass of replication.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.Replication.ReconcileInterval), "replication.reconcile-interval", "", (time.Duration)(srv.Config.Replication.ReconcileInterval), "Time between the comparisons of every fragment of the fields which don't track changes with the replicated cluster.")
+
+	// HTTP2
+	flags.BoolVarP(&srv.Config.HTTP2.Enabled, "http2.enabled", "", srv.Config.HTTP2.Enabled, "Send requests to other nodes over HTTP/2, falling back to HTTP/1.1 for nodes which don't support it.")
+	flags.IntVarP(&srv.Config.HTTP2.MaxConnsPerPeer, "http2.max-conns-per-peer", "", srv.Config.HTTP2.MaxConnsPerPeer, "Number of HTTP/2 connections opened to each node.")
+	flags.IntVarP(&srv.Config.HTTP2.MaxStreamsPerConn, "http2.max-streams-per-conn", "", srv.Config.HTTP2.MaxStreamsPerConn, "Number of concurrent requests on each HTTP/2 connection.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.HTTP2.PingInterval), "http2.ping-interval", "", (time.Duration)(srv.Config.HTTP2.PingInterval), "Interval between pings of each HTTP/2 connection.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.HTTP2.PingTimeout), "http2.ping-timeout", "", (time.Duration)(srv.Config.HTTP2.PingTimeout), "Duration to wait for the answer of a ping before closing an HTTP/2 connection.")
+
+	// SnapshotReads
+	flags.Int64VarP(&srv.Config.SnapshotReads.MaxMemory, "snapshot-reads.max-memory", "", srv.Config.SnapshotReads.MaxMemory, "Number of bytes the snapshots of all running snapshot reads may use. 0 is unlimited.")
+	flags.Int64VarP(&srv.Config.SnapshotReads.MaxFragmentMemory, "snapshot-reads.max-fragment-memory", "", srv.Config.SnapshotReads.MaxFragmentMemory, "Number of bytes the snapshot of a single fragment may use. 0 is unlimited.")
+	flags.DurationVarP((*time.Duration)(&srv.Config.SnapshotReads.Timeout), "snapshot-reads.timeout", "", (time.Duration)(srv.Config.SnapshotReads.Timeout), "Duration for which a snapshot read waits for the writes being applied to take its snapshot. 0 waits indefinitely.")
+
+	// Trash
+	flags.DurationVarP((*time.Duration)(&srv.Config.Trash.Retention), "trash.retention", "", (time.Duration)(srv.Config.Trash.Retention), "Duration for which deleted indexes are kept in the trash. 0 deletes indexes immediately.")
+
+	// Admission
+	flags.Int64VarP(&srv.Config.Admission.HighMemory, "admission.high-memory", "", srv.Config.Admission.HighMemory, "Bytes of memory used by the node above which expensive queries are rejected. 0 is disabled.")
+	flags.Int64VarP(&srv.Config.Admission.CriticalMemory, "admission.critical-memory", "", srv.Config.Admission.CriticalMemory, "Bytes of memory used by the node above which all queries are rejected. 0 is disabled.")
+
+	// RetainedSnapshots
+	flags.DurationVarP((*time.Duration)(&srv.Config.RetainedSnapshots.Interval), "retained-snapshots.interval", "", (time.Duration)(srv.Config.RetainedSnapshots.Interval), "Interval between two retained snapshots of a field which retains snapshots.")
+	flags.IntVarP(&srv.Config.RetainedSnapshots.CacheSize, "retained-snapshots.cache-size", "", srv.Config.RetainedSnapshots.CacheSize, "Number of fragments of retained snapshots kept loaded for queries.")
+
+	// ReadVerification
+	flags.Float64VarP(&srv.Config.ReadVerification.Rate, "read-verification.rate", "", srv.Config.ReadVerification.Rate, "Fraction of the shards read by queries which are verified against a second replica. 0 disables verification.")
+	flags.BoolVarP(&srv.Config.ReadVerification.Repair, "read-verification.repair", "", srv.Config.ReadVerification.Repair, "Synchronize the fragments of shards whose replicas return different results.")
+
+	// StatsHistory
+	flags.DurationVarP((*time.Duration)(&srv.Config.StatsHistory.Retention), "stats-history.retention", "", (time.Duration)(srv.Config.StatsHistory.Retention), "Duration for which the per-minute query and write statistics of each index are kept. 0 disables the history.")
+	flags.IntVarP(&srv.Config.StatsHistory.MaxIndexes, "stats-history.max-indexes", "", srv.Config.StatsHistory.MaxIndexes, "Number of indexes whose query and write statistics are kept.")
+
 	// AntiEntropy
 	flags.DurationVarP((*time.Duration)(&srv.Config.AntiEntropy.Interval), "anti-entropy.interval", "", (time.Duration)(srv.Config.AntiEntropy.Interval), "Interval at which to run anti-entropy routine.")
 
 	// Metric
-	flags.StringVarP(&srv.Config.Metric.Service, "metric.service", "", srv.Config.Metric.Service, "Where to send stats: can be expvar (in-memory served at /debug/vars), statsd or none.")
-	flags.StringVarP(&srv.Config.Metric.Host, "metric.host", "", srv.Config.Metric.Host, "URI to send metrics when metric.service is statsd.")
+	flags.StringVarP(&srv.Config.Metric.Service, "metric.service", "", srv.Config.Metric.Service, "Where to send stats: can be expvar (in-memory served at /debug/vars), statsd, statsd-plain (statsd without tags), prometheus or none.")
+	flags.StringVarP(&srv.Config.Metric.Host, "metric.host", "", srv.Config.Metric.Host, "URI to send metrics when metric.service is statsd or statsd-plain.")
+	flags.StringVarP(&srv.Config.Metric.Prefix, "metric.prefix", "", srv.Config.Metric.Prefix, "Prefix of the name of every metric, such as the name of the cluster.")
+	flags.StringSliceVarP(&srv.Config.Metric.Tags, "metric.tags", "", srv.Config.Metric.Tags, "Tags added to every metric, in key:value form, such as cluster:prod,env:production.")
 	flags.DurationVarP((*time.Duration)(&srv.Config.Metric.PollInterval), "metric.poll-interval", "", (time.Duration)(srv.Config.Metric.PollInterval), "Polling interval metrics.")
 	flags.BoolVarP((&srv.Config.Metric.Diagnostics), "metric.diagnostics", "", srv.Config.Metric.Diagnostics, "Enabled diagnostics reporting.")
 
@@ -0,0 +1,118 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxScale is the largest scale of an int field. Larger scales would not
+// leave room for an integer part in an int64.
+const maxScale = 18
+
+// ParseDecimal parses the decimal number s and returns it as an integer in
+// units of 10^-scale, which is how values of an int field with the given
+// scale are stored. For example, "19.99" with a scale of 2 is 1999. Returns
+// ErrDecimalScale if s has more significant decimal places than scale.
+func ParseDecimal(s string, scale int64) (int64, error) {
+	if scale < 0 || scale > maxScale {
+		return 0, ErrInvalidScale
+	}
+
+	digits := s
+	neg := strings.HasPrefix(digits, "-")
+	if neg {
+		digits = digits[1:]
+	}
+	intPart, fracPart := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		intPart, fracPart = digits[:i], digits[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, errors.Errorf("invalid decimal: %q", s)
+	}
+
+	// Trailing zeros don't add precision.
+	fracPart = strings.TrimRight(fracPart, "0")
+	if int64(len(fracPart)) > scale {
+		return 0, errors.Wrapf(ErrDecimalScale, "%s", s)
+	}
+	fracPart += strings.Repeat("0", int(scale)-len(fracPart))
+
+	digits = strings.TrimLeft(intPart+fracPart, "0")
+	if digits == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil || (!neg && v > math.MaxInt64) || v > -math.MinInt64 {
+		return 0, errors.Errorf("decimal out of range: %s", s)
+	}
+	if neg {
+		return int64(-v), nil
+	}
+	return int64(v), nil
+}
+
+// FormatDecimal returns the decimal representation of v, an integer in units
+// of 10^-scale. It is the inverse of ParseDecimal.
+func FormatDecimal(v int64, scale int64) string {
+	if scale <= 0 {
+		return strconv.FormatInt(v, 10)
+	}
+
+	// Negating math.MinInt64 overflows, so work on the unsigned magnitude.
+	u, sign := uint64(v), ""
+	if v < 0 {
+		u, sign = -u, "-"
+	}
+	digits := strconv.FormatUint(u, 10)
+	if pad := int(scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	i := len(digits) - int(scale)
+	return sign + digits[:i] + "." + digits[i:]
+}
+
+// scaledValue converts v, a number from a query or a JSON document, to an
+// integer in units of 10^-scale.
+func scaledValue(v interface{}, scale int64) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return ParseDecimal(strconv.FormatInt(v, 10), scale)
+	case uint64:
+		return ParseDecimal(strconv.FormatUint(v, 10), scale)
+	case float64:
+		// The shortest representation of v is the literal it was parsed from.
+		return ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64), scale)
+	case json.Number:
+		return ParseDecimal(string(v), scale)
+	}
+	return 0, errors.Errorf("expected number, got %v", v)
+}
+
+// isDigits returns true if s only contains decimal digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
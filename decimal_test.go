@@ -0,0 +1,80 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for _, tt := range []struct {
+		s     string
+		scale int64
+		v     int64
+		err   string
+	}{
+		{s: "19.99", scale: 2, v: 1999},
+		{s: "-19.9", scale: 2, v: -1990},
+		{s: "20", scale: 2, v: 2000},
+		{s: "2.", scale: 1, v: 20},
+		{s: "-.5", scale: 1, v: -5},
+		{s: "1.500", scale: 1, v: 15},
+		{s: "0.000", scale: 0, v: 0},
+		{s: "-9223372036854775808", scale: 0, v: math.MinInt64},
+		{s: "922337203685477580.7", scale: 1, v: math.MaxInt64},
+		{s: "1.25", scale: 1, err: "more decimal places"},
+		{s: "922337203685477580.8", scale: 1, err: "out of range"},
+		{s: "1e3", scale: 0, err: "invalid decimal"},
+		{s: ".", scale: 2, err: "invalid decimal"},
+		{s: "1", scale: 19, err: "invalid scale"},
+	} {
+		v, err := pilosa.ParseDecimal(tt.s, tt.scale)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("%s: unexpected error: %v", tt.s, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.s, err)
+		} else if v != tt.v {
+			t.Fatalf("%s: unexpected value: %d", tt.s, v)
+		}
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	for _, tt := range []struct {
+		v     int64
+		scale int64
+		s     string
+	}{
+		{v: 1999, scale: 2, s: "19.99"},
+		{v: -5, scale: 2, s: "-0.05"},
+		{v: 2000, scale: 2, s: "20.00"},
+		{v: 42, scale: 0, s: "42"},
+		{v: math.MinInt64, scale: 3, s: "-9223372036854775.808"},
+	} {
+		if s := pilosa.FormatDecimal(tt.v, tt.scale); s != tt.s {
+			t.Fatalf("%d: unexpected string: %s", tt.v, s)
+		} else if v, err := pilosa.ParseDecimal(s, tt.scale); err != nil {
+			t.Fatal(err)
+		} else if v != tt.v {
+			t.Fatalf("%s: unexpected round trip: %d", s, v)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pilosa/pilosa/v2/internal"
+	"github.com/pilosa/pilosa/v2/logger"
+	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// deleteJobChunkSize is the largest number of columns deleted by a
+	// chunk of a delete job. The columns of a chunk are in the same shard.
+	deleteJobChunkSize = 1000
+
+	// defaultDeleteJobConcurrency is the number of chunks deleted at the
+	// same time across all delete jobs.
+	defaultDeleteJobConcurrency = 4
+)
+
+// Statuses of delete jobs and their chunks.
+const (
+	DeleteJobPending   = "pending"
+	DeleteJobRunning   = "running"
+	DeleteJobSucceeded = "succeeded"
+	DeleteJobFailed    = "failed"
+)
+
+// DeleteJobOptions configures the delete jobs run by a node.
+type DeleteJobOptions struct {
+	// Concurrency is the number of chunks deleted at the same time across
+	// all delete jobs.
+	Concurrency int
+
+	// Rate is the largest number of columns deleted per second across all
+	// delete jobs. Zero is unlimited.
+	Rate int
+}
+
+// DeleteJob is an asynchronous deletion of columns from an index. The columns
+// are split in chunks by shard, which are deleted in the background with
+// DeleteColumn() calls. Jobs are persisted, so that a node resumes its
+// unfinished jobs when it restarts.
+type DeleteJob struct {
+	ID      string            `json:"id"`
+	Index   string            `json:"index"`
+	Status  string            `json:"status"`
+	Created time.Time         `json:"created"`
+	Updated time.Time         `json:"updated"`
+	Chunks  []*DeleteJobChunk `json:"chunks"`
+}
+
+// DeleteJobChunk is a part of a delete job with columns of a single shard.
+type DeleteJobChunk struct {
+	Shard   uint64
+	Columns []uint64
+	Status  string
+
+	// Cleared is the number of bits cleared by the chunk.
+	Cleared uint64
+
+	// Error is set if the chunk failed.
+	Error string
+}
+
+// MarshalJSON marshals the chunk to JSON with the number of its columns
+// rather than the columns.
+func (c *DeleteJobChunk) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Shard   uint64 `json:"shard"`
+		Columns int    `json:"columns"`
+		Status  string `json:"status"`
+		Cleared uint64 `json:"cleared"`
+		Error   string `json:"error,omitempty"`
+	}{
+		Shard:   c.Shard,
+		Columns: len(c.Columns),
+		Status:  c.Status,
+		Cleared: c.Cleared,
+		Error:   c.Error,
+	})
+}
+
+// status returns the status of a job from the statuses of its chunks: a job
+// is pending until a chunk starts, and is running until every chunk is done.
+func (j *DeleteJob) status() string {
+	var pending, failed, done int
+	for _, c := range j.Chunks {
+		switch c.Status {
+		case DeleteJobPending:
+			pending++
+		case DeleteJobFailed:
+			failed++
+			done++
+		case DeleteJobSucceeded:
+			done++
+		}
+	}
+	switch {
+	case pending == len(j.Chunks) && len(j.Chunks) > 0:
+		return DeleteJobPending
+	case done < len(j.Chunks):
+		return DeleteJobRunning
+	case failed > 0:
+		return DeleteJobFailed
+	}
+	return DeleteJobSucceeded
+}
+
+// clone returns a copy of the job and its chunks, with the status of the
+// job set.
+func (j *DeleteJob) clone() *DeleteJob {
+	other := *j
+	other.Status = j.status()
+	other.Chunks = make([]*DeleteJobChunk, len(j.Chunks))
+	for i, c := range j.Chunks {
+		cc := *c
+		other.Chunks[i] = &cc
+	}
+	return &other
+}
+
+// newDeleteJob returns a new job which deletes columns from index. Columns
+// are sorted and split in chunks by shard.
+func newDeleteJob(index string, columns []uint64, shardWidth uint64) *DeleteJob {
+	columns = append([]uint64(nil), columns...)
+	sort.Sort(uint64Slice(columns))
+
+	now := time.Now().UTC()
+	j := &DeleteJob{
+		ID:      uuid.NewV4().String(),
+		Index:   index,
+		Created: now,
+		Updated: now,
+	}
+	var chunk *DeleteJobChunk
+	for i, col := range columns {
+		if i > 0 && col == columns[i-1] {
+			continue
+		}
+		shard := col / shardWidth
+		if chunk == nil || chunk.Shard != shard || len(chunk.Columns) == deleteJobChunkSize {
+			chunk = &DeleteJobChunk{Shard: shard, Status: DeleteJobPending}
+			j.Chunks = append(j.Chunks, chunk)
+		}
+		chunk.Columns = append(chunk.Columns, col)
+	}
+	return j
+}
+
+// deleteJobs runs the delete jobs of a node and persists their state in a
+// directory, one file per job.
+type deleteJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*DeleteJob
+
+	// Directory of the job files. Jobs are not persisted if it is empty.
+	path string
+
+	// Chunks wait for a slot of sem, and for their turn under the rate,
+	// which is tracked by the time the next chunk may start.
+	sem  chan struct{}
+	rate int
+	next time.Time
+
+	// deleteColumns deletes columns from an index and returns the number
+	// of bits cleared.
+	deleteColumns func(ctx context.Context, index string, columns []uint64) (uint64, error)
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+
+	logger logger.Logger
+	stats  stats.StatsClient
+}
+
+// newDeleteJobs returns a new instance of deleteJobs which persists jobs in
+// the given data directory.
+func newDeleteJobs(opt DeleteJobOptions, dir string) *deleteJobs {
+	if opt.Concurrency <= 0 {
+		opt.Concurrency = defaultDeleteJobConcurrency
+	}
+	d := &deleteJobs{
+		jobs:   make(map[string]*DeleteJob),
+		sem:    make(chan struct{}, opt.Concurrency),
+		rate:   opt.Rate,
+		logger: logger.NopLogger,
+		stats:  stats.NopStatsClient,
+	}
+	if dir != "" {
+		d.path = filepath.Join(dir, ".jobs")
+	}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	return d
+}
+
+// open loads the persisted jobs and resumes the unfinished ones. Chunks which
+// were running when the node stopped are deleted again, which is safe since
+// deleting a column is idempotent.
+func (d *deleteJobs) open() error {
+	if d.path == "" {
+		return nil
+	}
+	fis, err := ioutil.ReadDir(d.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "reading directory")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != "" {
+			continue
+		}
+		j, err := d.load(filepath.Join(d.path, fi.Name()))
+		if err != nil {
+			return errors.Wrapf(err, "loading job %s", fi.Name())
+		}
+		d.jobs[j.ID] = j
+
+		resume := false
+		for _, c := range j.Chunks {
+			if c.Status == DeleteJobRunning {
+				c.Status = DeleteJobPending
+			}
+			resume = resume || c.Status == DeleteJobPending
+		}
+		if resume {
+			d.logger.Printf("resuming delete job %s of index %s", j.ID, j.Index)
+			d.start(j)
+		}
+	}
+	return nil
+}
+
+// close stops the running jobs and waits for their chunks to finish.
+func (d *deleteJobs) close() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// create persists and starts a job which deletes columns from index.
+func (d *deleteJobs) create(index string, columns []uint64, shardWidth uint64) (*DeleteJob, error) {
+	j := newDeleteJob(index, columns, shardWidth)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.save(j); err != nil {
+		return nil, errors.Wrap(err, "saving job")
+	}
+	d.jobs[j.ID] = j
+	d.start(j)
+	return j.clone(), nil
+}
+
+// job returns a copy of the job with the given ID, or nil if there is no
+// such job.
+func (d *deleteJobs) job(id string) *DeleteJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if j := d.jobs[id]; j != nil {
+		return j.clone()
+	}
+	return nil
+}
+
+// resume restarts the failed chunks of a finished job. It returns nil if
+// there is no such job.
+func (d *deleteJobs) resume(id string) (*DeleteJob, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	j := d.jobs[id]
+	if j == nil {
+		return nil, nil
+	} else if status := j.status(); status == DeleteJobPending || status == DeleteJobRunning {
+		return nil, newConflictError(errors.Errorf("job is %s", status))
+	}
+
+	var n int
+	for _, c := range j.Chunks {
+		if c.Status == DeleteJobFailed {
+			c.Status, c.Error = DeleteJobPending, ""
+			n++
+		}
+	}
+	if n > 0 {
+		j.Updated = time.Now().UTC()
+		if err := d.save(j); err != nil {
+			return nil, errors.Wrap(err, "saving job")
+		}
+		d.start(j)
+	}
+	return j.clone(), nil
+}
+
+// start runs the pending chunks of j in the background. unprotected.
+func (d *deleteJobs) start(j *DeleteJob) {
+	var chunks []*DeleteJobChunk
+	for _, c := range j.Chunks {
+		if c.Status == DeleteJobPending {
+			chunks = append(chunks, c)
+		}
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		var wg sync.WaitGroup
+		for _, c := range chunks {
+			select {
+			case d.sem <- struct{}{}:
+			case <-d.ctx.Done():
+				wg.Wait()
+				return
+			}
+			if err := d.wait(len(c.Columns)); err != nil {
+				<-d.sem
+				break
+			}
+			wg.Add(1)
+			go func(c *DeleteJobChunk) {
+				defer func() { <-d.sem; wg.Done() }()
+				d.run(j, c)
+			}(c)
+		}
+		wg.Wait()
+		d.finish(j)
+	}()
+}
+
+// wait blocks until n columns can be deleted without exceeding the rate.
+func (d *deleteJobs) wait(n int) error {
+	if d.rate <= 0 {
+		return nil
+	}
+	d.mu.Lock()
+	now := time.Now()
+	if d.next.Before(now) {
+		d.next = now
+	}
+	at := d.next
+	d.next = d.next.Add(time.Duration(n) * time.Second / time.Duration(d.rate))
+	d.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-d.ctx.Done():
+		return d.ctx.Err()
+	}
+}
+
+// run deletes the columns of a chunk and persists its status. A chunk which
+// is interrupted because the node is closing stays pending.
+func (d *deleteJobs) run(j *DeleteJob, c *DeleteJobChunk) {
+	d.update(j, func() { c.Status = DeleteJobRunning })
+
+	n, err := d.deleteColumns(d.ctx, j.Index, c.Columns)
+	d.update(j, func() {
+		switch {
+		case err != nil && d.ctx.Err() != nil:
+			c.Status = DeleteJobPending
+		case err != nil:
+			c.Status, c.Error = DeleteJobFailed, err.Error()
+		default:
+			c.Status, c.Cleared = DeleteJobSucceeded, n
+		}
+	})
+	if err != nil && d.ctx.Err() == nil {
+		d.logger.Printf("delete job %s: deleting %d columns of shard %d: %v", j.ID, len(c.Columns), c.Shard, err)
+	}
+}
+
+// update changes the state of j with fn and persists it.
+func (d *deleteJobs) update(j *DeleteJob, fn func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fn()
+	j.Updated = time.Now().UTC()
+	if err := d.save(j); err != nil {
+		d.logger.Printf("saving delete job %s: %v", j.ID, err)
+	}
+}
+
+// finish records the completion of j, unless it was interrupted.
+func (d *deleteJobs) finish(j *DeleteJob) {
+	d.mu.Lock()
+	status := j.status()
+	var cleared uint64
+	for _, c := range j.Chunks {
+		cleared += c.Cleared
+	}
+	d.mu.Unlock()
+
+	switch status {
+	case DeleteJobSucceeded, DeleteJobFailed:
+		d.logger.Printf("delete job %s of index %s %s, cleared %d bits", j.ID, j.Index, status, cleared)
+		d.stats.CountWithCustomTags("deleteJob", 1, 1.0, []string{"status:" + status})
+	}
+}
+
+// load reads a persisted job.
+func (d *deleteJobs) load(path string) (*DeleteJob, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading file")
+	}
+	var pb internal.DeleteJob
+	if err := proto.Unmarshal(buf, &pb); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling")
+	}
+
+	j := &DeleteJob{
+		ID:      pb.ID,
+		Index:   pb.Index,
+		Created: time.Unix(0, pb.Created).UTC(),
+		Updated: time.Unix(0, pb.Updated).UTC(),
+		Chunks:  make([]*DeleteJobChunk, len(pb.Chunks)),
+	}
+	for i, c := range pb.Chunks {
+		j.Chunks[i] = &DeleteJobChunk{
+			Shard:   c.Shard,
+			Columns: c.Columns,
+			Status:  c.Status,
+			Cleared: c.Cleared,
+			Error:   c.Error,
+		}
+	}
+	return j, nil
+}
+
+// save persists j. The file is replaced atomically, so that a crash leaves
+// either the previous or the new state. unprotected.
+func (d *deleteJobs) save(j *DeleteJob) error {
+	if d.path == "" {
+		return nil
+	}
+
+	pb := &internal.DeleteJob{
+		ID:      j.ID,
+		Index:   j.Index,
+		Created: j.Created.UnixNano(),
+		Updated: j.Updated.UnixNano(),
+		Chunks:  make([]*internal.DeleteJobChunk, len(j.Chunks)),
+	}
+	for i, c := range j.Chunks {
+		pb.Chunks[i] = &internal.DeleteJobChunk{
+			Shard:   c.Shard,
+			Columns: c.Columns,
+			Status:  c.Status,
+			Cleared: c.Cleared,
+			Error:   c.Error,
+		}
+	}
+	buf, err := proto.Marshal(pb)
+	if err != nil {
+		return errors.Wrap(err, "marshalling")
+	}
+
+	if err := os.MkdirAll(d.path, 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	}
+	path := filepath.Join(d.path, j.ID)
+	if err := ioutil.WriteFile(path+".tmp", buf, 0666); err != nil {
+		return errors.Wrap(err, "writing file")
+	}
+	return errors.Wrap(os.Rename(path+".tmp", path), "renaming file")
+}
@@ -0,0 +1,181 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitForDeleteJob waits until a job is finished and returns it.
+func waitForDeleteJob(tb testing.TB, d *deleteJobs, id string) *DeleteJob {
+	tb.Helper()
+	for i := 0; i < 500; i++ {
+		if j := d.job(id); j.Status == DeleteJobSucceeded || j.Status == DeleteJobFailed {
+			return j
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestDeleteJobs(t *testing.T) {
+	t.Run("Chunks", func(t *testing.T) {
+		columns := []uint64{2*ShardWidth + 1, 3, 1, 3}
+		for i := uint64(0); i < deleteJobChunkSize+1; i++ {
+			columns = append(columns, ShardWidth+i)
+		}
+		j := newDeleteJob("i", columns, ShardWidth)
+
+		var shards, sizes []int
+		for _, c := range j.Chunks {
+			shards = append(shards, int(c.Shard))
+			sizes = append(sizes, len(c.Columns))
+		}
+		if !reflect.DeepEqual(shards, []int{0, 1, 1, 2}) || !reflect.DeepEqual(sizes, []int{2, deleteJobChunkSize, 1, 1}) {
+			t.Fatalf("unexpected chunks: shards=%v, sizes=%v", shards, sizes)
+		} else if j.status() != DeleteJobPending {
+			t.Fatalf("unexpected status: %s", j.status())
+		}
+	})
+
+	t.Run("PersistAndResume", func(t *testing.T) {
+		dir, err := ioutil.TempDir(*TempDir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		// Deleting shard 1 fails until fail is cleared.
+		var mu sync.Mutex
+		fail := true
+		deleted := make(map[uint64]int)
+		deleteColumns := func(ctx context.Context, index string, columns []uint64) (uint64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if fail && columns[0]/ShardWidth == 1 {
+				return 0, errors.New("marker")
+			}
+			for _, col := range columns {
+				deleted[col]++
+			}
+			return uint64(2 * len(columns)), nil
+		}
+
+		d := newDeleteJobs(DeleteJobOptions{Concurrency: 2}, dir)
+		d.deleteColumns = deleteColumns
+		if err := d.open(); err != nil {
+			t.Fatal(err)
+		}
+		j, err := d.create("i", []uint64{1, 2, ShardWidth}, ShardWidth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		j = waitForDeleteJob(t, d, j.ID)
+		if j.Status != DeleteJobFailed || j.Chunks[0].Status != DeleteJobSucceeded || j.Chunks[0].Cleared != 4 {
+			t.Fatalf("unexpected job: %+v", j)
+		} else if c := j.Chunks[1]; c.Status != DeleteJobFailed || c.Error != "marker" {
+			t.Fatalf("unexpected chunk: %+v", c)
+		}
+		d.close()
+
+		// The job is loaded when the jobs are opened again, and its failed
+		// chunk is resumed on request.
+		mu.Lock()
+		fail = false
+		mu.Unlock()
+		d = newDeleteJobs(DeleteJobOptions{}, dir)
+		d.deleteColumns = deleteColumns
+		if err := d.open(); err != nil {
+			t.Fatal(err)
+		}
+		defer d.close()
+		if other := d.job(j.ID); !reflect.DeepEqual(other, j) {
+			t.Fatalf("unexpected loaded job: %+v", other)
+		}
+		if _, err := d.resume(j.ID); err != nil {
+			t.Fatal(err)
+		}
+		j = waitForDeleteJob(t, d, j.ID)
+		if j.Status != DeleteJobSucceeded || j.Chunks[1].Cleared != 2 || j.Chunks[1].Error != "" {
+			t.Fatalf("unexpected job: %+v", j)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(deleted, map[uint64]int{1: 1, 2: 1, ShardWidth: 1}) {
+			t.Fatalf("unexpected deleted columns: %v", deleted)
+		}
+
+		if _, err := d.resume("x"); err != nil {
+			t.Fatal(err)
+		} else if j := d.job("x"); j != nil {
+			t.Fatalf("unexpected job: %+v", j)
+		}
+	})
+
+	t.Run("ResumeRunning", func(t *testing.T) {
+		dir, err := ioutil.TempDir(*TempDir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		// A job which was running when the node stopped is resumed when the
+		// jobs are opened.
+		d := newDeleteJobs(DeleteJobOptions{}, dir)
+		j := newDeleteJob("i", []uint64{1, ShardWidth}, ShardWidth)
+		j.Chunks[0].Status, j.Chunks[0].Cleared = DeleteJobSucceeded, 1
+		j.Chunks[1].Status = DeleteJobRunning
+		if err := d.save(j); err != nil {
+			t.Fatal(err)
+		}
+
+		var calls [][]uint64
+		d.deleteColumns = func(ctx context.Context, index string, columns []uint64) (uint64, error) {
+			calls = append(calls, columns)
+			return 1, nil
+		}
+		if err := d.open(); err != nil {
+			t.Fatal(err)
+		}
+		defer d.close()
+		if j := waitForDeleteJob(t, d, j.ID); j.Status != DeleteJobSucceeded {
+			t.Fatalf("unexpected status: %s", j.Status)
+		} else if !reflect.DeepEqual(calls, [][]uint64{{ShardWidth}}) {
+			t.Fatalf("unexpected calls: %v", calls)
+		}
+	})
+
+	t.Run("Rate", func(t *testing.T) {
+		d := newDeleteJobs(DeleteJobOptions{Rate: 100}, "")
+		defer d.close()
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := d.wait(10); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Fatalf("unexpected elapsed time: %s", elapsed)
+		}
+	})
+}
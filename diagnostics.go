@@ -249,6 +249,19 @@ func (d *diagnosticsCollector) EnrichWithSchemaProperties() {
 	d.Set("TimeQuantumEnabled", timeQuantumEnabled)
 }
 
+// EnrichWithStatsHistory adds the query and write totals of the last hour
+// to the diagnostics payload. Index names aren't included.
+func (d *diagnosticsCollector) EnrichWithStatsHistory() {
+	if d.server.statsHistory == nil {
+		return
+	}
+	queries, writes, failed, latency := d.server.statsHistory.totals(time.Hour)
+	d.Set("QueriesLastHour", queries)
+	d.Set("WritesLastHour", writes)
+	d.Set("QueryErrorsLastHour", failed)
+	d.Set("QueryP99MsLastHour", latency.quantile(0.99))
+}
+
 // versionSegments returns the numeric segments of the version as a slice of ints.
 func versionSegments(segments string) []int {
 	segments = strings.Trim(segments, "v")
@@ -275,6 +288,11 @@ type SystemInfo interface {
 	CPUCores() (physical int, logical int, err error)
 	CPUMHz() (int, error)
 	CPUArch() string
+
+	// Resources of the node, which are reported in the cluster status.
+	DiskFree(path string) (uint64, error)
+	MemRSS() (uint64, error)
+	LoadAverage() (float64, error)
 }
 
 // newNopSystemInfo creates a no-op implementation of SystemInfo.
@@ -331,6 +349,21 @@ func (n *nopSystemInfo) CPUArch() string {
 	return ""
 }
 
+// DiskFree is a no-op implementation of SystemInfo.DiskFree.
+func (n *nopSystemInfo) DiskFree(path string) (uint64, error) {
+	return 0, nil
+}
+
+// MemRSS is a no-op implementation of SystemInfo.MemRSS.
+func (n *nopSystemInfo) MemRSS() (uint64, error) {
+	return 0, nil
+}
+
+// LoadAverage is a no-op implementation of SystemInfo.LoadAverage.
+func (n *nopSystemInfo) LoadAverage() (float64, error) {
+	return 0, nil
+}
+
 // CPUModel returns the CPU model string
 func (n *nopSystemInfo) CPUModel() string {
 	return "unknown"
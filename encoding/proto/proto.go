@@ -16,7 +16,10 @@ package proto
 
 import (
 	"fmt"
+	"math"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pilosa/pilosa/v2"
@@ -169,6 +172,14 @@ func (Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		decodeNodeEventMessage(msg, mt)
 		return nil
+	case *pilosa.Schema:
+		msg := &internal.Schema{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling Schema")
+		}
+		decodeSchema(msg, mt)
+		return nil
 	case *pilosa.NodeStatus:
 		msg := &internal.NodeStatus{}
 		err := proto.Unmarshal(buf, msg)
@@ -177,6 +188,142 @@ func (Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		decodeNodeStatus(msg, mt)
 		return nil
+	case *pilosa.TransactionMessage:
+		msg := &internal.TransactionMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling TransactionMessage")
+		}
+		decodeTransactionMessage(msg, mt)
+		return nil
+	case *pilosa.SetIndexReadOnlyMessage:
+		msg := &internal.SetIndexReadOnlyMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SetIndexReadOnlyMessage")
+		}
+		decodeSetIndexReadOnlyMessage(msg, mt)
+		return nil
+	case *pilosa.SetFieldTimeQuantumMessage:
+		msg := &internal.SetFieldTimeQuantumMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SetFieldTimeQuantumMessage")
+		}
+		decodeSetFieldTimeQuantumMessage(msg, mt)
+		return nil
+	case *pilosa.DeleteSessionMessage:
+		msg := &internal.DeleteSessionMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling DeleteSessionMessage")
+		}
+		decodeDeleteSessionMessage(msg, mt)
+		return nil
+	case *pilosa.CreateIngestMappingMessage:
+		msg := &internal.CreateIngestMappingMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling CreateIngestMappingMessage")
+		}
+		decodeCreateIngestMappingMessage(msg, mt)
+		return nil
+	case *pilosa.DeleteIngestMappingMessage:
+		msg := &internal.DeleteIngestMappingMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling DeleteIngestMappingMessage")
+		}
+		decodeDeleteIngestMappingMessage(msg, mt)
+		return nil
+	case *pilosa.ClusterSettingsMessage:
+		msg := &internal.ClusterSettingsMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling ClusterSettingsMessage")
+		}
+		decodeClusterSettingsMessage(msg, mt)
+		return nil
+	case *pilosa.SetIndexQuotaMessage:
+		msg := &internal.SetIndexQuotaMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SetIndexQuotaMessage")
+		}
+		decodeSetIndexQuotaMessage(msg, mt)
+		return nil
+	case *pilosa.RestoreIndexMessage:
+		msg := &internal.RestoreIndexMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling RestoreIndexMessage")
+		}
+		decodeRestoreIndexMessage(msg, mt)
+		return nil
+	case *pilosa.PurgeTrashMessage:
+		msg := &internal.PurgeTrashMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling PurgeTrashMessage")
+		}
+		decodePurgeTrashMessage(msg, mt)
+		return nil
+	case *pilosa.RebuildExistenceMessage:
+		msg := &internal.RebuildExistenceMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling RebuildExistenceMessage")
+		}
+		decodeRebuildExistenceMessage(msg, mt)
+		return nil
+	case *pilosa.WarmJobMessage:
+		msg := &internal.WarmJobMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling WarmJobMessage")
+		}
+		decodeWarmJobMessage(msg, mt)
+		return nil
+	case *pilosa.MaintenanceMessage:
+		msg := &internal.MaintenanceMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling MaintenanceMessage")
+		}
+		decodeMaintenanceMessage(msg, mt)
+		return nil
+	case *pilosa.NodeResourcesMessage:
+		msg := &internal.NodeResourcesMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling NodeResourcesMessage")
+		}
+		decodeNodeResourcesMessage(msg, mt)
+		return nil
+	case *pilosa.SyncShardMessage:
+		msg := &internal.SyncShardMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SyncShardMessage")
+		}
+		decodeSyncShardMessage(msg, mt)
+		return nil
+	case *pilosa.CatchingUpMessage:
+		msg := &internal.CatchingUpMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling CatchingUpMessage")
+		}
+		decodeCatchingUpMessage(msg, mt)
+		return nil
+	case *pilosa.SetIndexDefaultFieldOptionsMessage:
+		msg := &internal.SetIndexDefaultFieldOptionsMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SetIndexDefaultFieldOptionsMessage")
+		}
+		decodeSetIndexDefaultFieldOptionsMessage(msg, mt)
+		return nil
 	case *pilosa.Node:
 		msg := &internal.Node{}
 		err := proto.Unmarshal(buf, msg)
@@ -199,8 +346,7 @@ func (Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		if err != nil {
 			return errors.Wrap(err, "unmarshaling QueryResponse")
 		}
-		decodeQueryResponse(msg, mt)
-		return nil
+		return decodeQueryResponse(msg, mt)
 	case *pilosa.ImportRequest:
 		msg := &internal.ImportRequest{}
 		err := proto.Unmarshal(buf, msg)
@@ -249,6 +395,14 @@ func (Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		decodeBlockDataResponse(msg, mt)
 		return nil
+	case *pilosa.FragmentBlocksResponse:
+		msg := &internal.FragmentBlocksResponse{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling FragmentBlocksResponse")
+		}
+		decodeFragmentBlocksResponse(msg, mt)
+		return nil
 	case *pilosa.TranslateKeysRequest:
 		msg := &internal.TranslateKeysRequest{}
 		err := proto.Unmarshal(buf, msg)
@@ -304,8 +458,44 @@ func encodeToProto(m pilosa.Message) proto.Message {
 		return encodeRecalculateCaches(mt)
 	case *pilosa.NodeEvent:
 		return encodeNodeEventMessage(mt)
+	case *pilosa.Schema:
+		return encodeSchema(mt)
 	case *pilosa.NodeStatus:
 		return encodeNodeStatus(mt)
+	case *pilosa.TransactionMessage:
+		return encodeTransactionMessage(mt)
+	case *pilosa.SetIndexReadOnlyMessage:
+		return encodeSetIndexReadOnlyMessage(mt)
+	case *pilosa.SetFieldTimeQuantumMessage:
+		return encodeSetFieldTimeQuantumMessage(mt)
+	case *pilosa.DeleteSessionMessage:
+		return encodeDeleteSessionMessage(mt)
+	case *pilosa.CreateIngestMappingMessage:
+		return encodeCreateIngestMappingMessage(mt)
+	case *pilosa.DeleteIngestMappingMessage:
+		return encodeDeleteIngestMappingMessage(mt)
+	case *pilosa.ClusterSettingsMessage:
+		return encodeClusterSettingsMessage(mt)
+	case *pilosa.SetIndexQuotaMessage:
+		return encodeSetIndexQuotaMessage(mt)
+	case *pilosa.RestoreIndexMessage:
+		return encodeRestoreIndexMessage(mt)
+	case *pilosa.PurgeTrashMessage:
+		return encodePurgeTrashMessage(mt)
+	case *pilosa.RebuildExistenceMessage:
+		return encodeRebuildExistenceMessage(mt)
+	case *pilosa.WarmJobMessage:
+		return encodeWarmJobMessage(mt)
+	case *pilosa.MaintenanceMessage:
+		return encodeMaintenanceMessage(mt)
+	case *pilosa.NodeResourcesMessage:
+		return encodeNodeResourcesMessage(mt)
+	case *pilosa.SyncShardMessage:
+		return encodeSyncShardMessage(mt)
+	case *pilosa.CatchingUpMessage:
+		return encodeCatchingUpMessage(mt)
+	case *pilosa.SetIndexDefaultFieldOptionsMessage:
+		return encodeSetIndexDefaultFieldOptionsMessage(mt)
 	case *pilosa.Node:
 		return encodeNode(mt)
 	case *pilosa.QueryRequest:
@@ -324,6 +514,8 @@ func encodeToProto(m pilosa.Message) proto.Message {
 		return encodeBlockDataRequest(mt)
 	case *pilosa.BlockDataResponse:
 		return encodeBlockDataResponse(mt)
+	case *pilosa.FragmentBlocksResponse:
+		return encodeFragmentBlocksResponse(mt)
 	case *pilosa.TranslateKeysRequest:
 		return encodeTranslateKeysRequest(mt)
 	case *pilosa.TranslateKeysResponse:
@@ -339,13 +531,30 @@ func encodeBlockDataRequest(m *pilosa.BlockDataRequest) *internal.BlockDataReque
 		View:  m.View,
 		Shard: m.Shard,
 		Block: m.Block,
+
+		Continuation: m.Continuation,
+		Limit:        m.Limit,
 	}
 }
 func encodeBlockDataResponse(m *pilosa.BlockDataResponse) *internal.BlockDataResponse {
 	return &internal.BlockDataResponse{
-		RowIDs:    m.RowIDs,
-		ColumnIDs: m.ColumnIDs,
+		RowIDs:       m.RowIDs,
+		ColumnIDs:    m.ColumnIDs,
+		Continuation: m.Continuation,
+	}
+}
+
+func encodeFragmentBlocksResponse(m *pilosa.FragmentBlocksResponse) *internal.FragmentBlocksResponse {
+	pb := &internal.FragmentBlocksResponse{
+		Blocks: make([]*internal.FragmentBlock, len(m.Blocks)),
 	}
+	for i, blk := range m.Blocks {
+		pb.Blocks[i] = &internal.FragmentBlock{
+			ID:       uint64(blk.ID),
+			Checksum: blk.Checksum,
+		}
+	}
+	return pb
 }
 
 func encodeImportResponse(m *pilosa.ImportResponse) *internal.ImportResponse {
@@ -379,18 +588,18 @@ func encodeImportValueRequest(m *pilosa.ImportValueRequest) *internal.ImportValu
 }
 
 func encodeImportRoaringRequest(m *pilosa.ImportRoaringRequest) *internal.ImportRoaringRequest {
-	views := make([]*internal.ImportRoaringRequestView, len(m.Views))
-	i := 0
+	views := make([]*internal.ImportRoaringRequestView, 0, len(m.Views))
 	for viewName, viewData := range m.Views {
-		views[i] = &internal.ImportRoaringRequestView{
+		views = append(views, &internal.ImportRoaringRequestView{
 			Name: viewName,
 			Data: viewData,
-		}
-		i += 1
+		})
 	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
 	return &internal.ImportRoaringRequest{
-		Clear: m.Clear,
-		Views: views,
+		Clear:       m.Clear,
+		Views:       views,
+		OperationID: m.OperationID,
 	}
 }
 
@@ -402,6 +611,13 @@ func encodeQueryRequest(m *pilosa.QueryRequest) *internal.QueryRequest {
 		Remote:          m.Remote,
 		ExcludeRowAttrs: m.ExcludeRowAttrs,
 		ExcludeColumns:  m.ExcludeColumns,
+		Session:         m.Session,
+		StoreAs:         m.StoreAs,
+		Snapshot:        m.Snapshot,
+		Roaring:         m.Roaring,
+		AsOf:            encodeTime(m.AsOf),
+		Estimate:        m.Estimate,
+		MaxStaleness:    int64(m.MaxStaleness),
 	}
 }
 
@@ -409,6 +625,8 @@ func encodeQueryResponse(m *pilosa.QueryResponse) *internal.QueryResponse {
 	pb := &internal.QueryResponse{
 		Results:        make([]*internal.QueryResult, len(m.Results)),
 		ColumnAttrSets: encodeColumnAttrSets(m.ColumnAttrSets),
+		Shards:         m.Shards,
+		StaleShards:    m.StaleShards,
 	}
 
 	for i := range m.Results {
@@ -417,7 +635,11 @@ func encodeQueryResponse(m *pilosa.QueryResponse) *internal.QueryResponse {
 		switch result := m.Results[i].(type) {
 		case *pilosa.Row:
 			pb.Results[i].Type = queryResultTypeRow
-			pb.Results[i].Row = encodeRow(result)
+			if m.Roaring {
+				pb.Results[i].Row = encodeRowRoaring(result)
+			} else {
+				pb.Results[i].Row = encodeRow(result)
+			}
 		case []pilosa.Pair:
 			pb.Results[i].Type = queryResultTypePairs
 			pb.Results[i].Pairs = encodePairs(result)
@@ -442,6 +664,15 @@ func encodeQueryResponse(m *pilosa.QueryResponse) *internal.QueryResponse {
 		case pilosa.Pair:
 			pb.Results[i].Type = queryResultTypePair
 			pb.Results[i].Pairs = []*internal.Pair{encodePair(result)}
+		case pilosa.FieldCounts:
+			pb.Results[i].Type = queryResultTypeFieldCounts
+			pb.Results[i].Pairs = encodeFieldCounts(result)
+		case *pilosa.QueryEstimate:
+			pb.Results[i].Type = queryResultTypeEstimate
+			pb.Results[i].Pairs = encodeQueryEstimate(result)
+		case *pilosa.SampleResult:
+			pb.Results[i].Type = queryResultTypeSample
+			pb.Results[i].Pairs = encodeSampleResult(result)
 		case nil:
 			pb.Results[i].Type = queryResultTypeNil
 		default:
@@ -461,20 +692,39 @@ func encodeResizeInstruction(m *pilosa.ResizeInstruction) *internal.ResizeInstru
 		JobID:         m.JobID,
 		Node:          encodeNode(m.Node),
 		Coordinator:   encodeNode(m.Coordinator),
-		Sources:       encodeResizeSources(m.Sources),
+		Sources:       encodeResizeSources(m.Sources, m.ProtocolVersion),
 		NodeStatus:    encodeNodeStatus(m.NodeStatus),
 		ClusterStatus: encodeClusterStatus(m.ClusterStatus),
 	}
 }
 
-func encodeResizeSources(srcs []*pilosa.ResizeSource) []*internal.ResizeSource {
+// encodeResizeSources encodes srcs with the encoding of protocol version v.
+// Since version 2, consecutive sources of the same node and view are grouped
+// into a single source with a list of shards.
+func encodeResizeSources(srcs []*pilosa.ResizeSource, v uint32) []*internal.ResizeSource {
 	new := make([]*internal.ResizeSource, 0, len(srcs))
 	for _, src := range srcs {
+		if v >= 2 {
+			if n := len(new); n > 0 && sameResizeSourceView(new[n-1], src) {
+				new[n-1].Shards = append(new[n-1].Shards, src.Shard)
+				continue
+			}
+			pb := encodeResizeSource(src)
+			pb.Shard, pb.Shards = 0, []uint64{src.Shard}
+			new = append(new, pb)
+			continue
+		}
 		new = append(new, encodeResizeSource(src))
 	}
 	return new
 }
 
+// sameResizeSourceView returns true if src is for the same node and view as
+// the grouped source pb.
+func sameResizeSourceView(pb *internal.ResizeSource, src *pilosa.ResizeSource) bool {
+	return pb.Node.ID == src.Node.ID && pb.Index == src.Index && pb.Field == src.Field && pb.View == src.View
+}
+
 func encodeResizeSource(m *pilosa.ResizeSource) *internal.ResizeSource {
 	return &internal.ResizeSource{
 		Node:  encodeNode(m.Node),
@@ -487,43 +737,54 @@ func encodeResizeSource(m *pilosa.ResizeSource) *internal.ResizeSource {
 
 func encodeSchema(m *pilosa.Schema) *internal.Schema {
 	return &internal.Schema{
-		Indexes: encodeIndexInfos(m.Indexes),
+		Indexes:    encodeIndexInfos(m.Indexes),
+		Generation: m.Generation,
 	}
 }
 
+// encodeIndexInfos encodes idxs sorted by name, so that the encoding of a
+// schema doesn't depend on the order in which it was built.
 func encodeIndexInfos(idxs []*pilosa.IndexInfo) []*internal.Index {
 	new := make([]*internal.Index, 0, len(idxs))
 	for _, idx := range idxs {
 		new = append(new, encodeIndexInfo(idx))
 	}
+	sort.Slice(new, func(i, j int) bool { return new[i].Name < new[j].Name })
 	return new
 }
 
 func encodeIndexInfo(idx *pilosa.IndexInfo) *internal.Index {
 	return &internal.Index{
-		Name:   idx.Name,
-		Fields: encodeFieldInfos(idx.Fields),
+		Name:           idx.Name,
+		Fields:         encodeFieldInfos(idx.Fields),
+		ShardWidth:     idx.ShardWidth,
+		IngestMappings: encodeIngestMappings(idx.IngestMappings),
+		Meta:           encodeIndexMeta(&idx.Options),
 	}
 }
 
+// encodeFieldInfos encodes fs sorted by name.
 func encodeFieldInfos(fs []*pilosa.FieldInfo) []*internal.Field {
 	new := make([]*internal.Field, 0, len(fs))
 	for _, f := range fs {
 		new = append(new, encodeFieldInfo(f))
 	}
+	sort.Slice(new, func(i, j int) bool { return new[i].Name < new[j].Name })
 	return new
 }
 
 func encodeFieldInfo(f *pilosa.FieldInfo) *internal.Field {
 	ifield := &internal.Field{
-		Name:  f.Name,
-		Meta:  encodeFieldOptions(&f.Options),
-		Views: make([]string, 0, len(f.Views)),
+		Name:     f.Name,
+		Meta:     encodeFieldOptions(&f.Options),
+		MaxRowID: f.MaxRowID,
+		Views:    make([]string, 0, len(f.Views)),
 	}
 
 	for _, viewinfo := range f.Views {
 		ifield.Views = append(ifield.Views, viewinfo.Name)
 	}
+	sort.Strings(ifield.Views)
 	return ifield
 }
 
@@ -532,20 +793,50 @@ func encodeFieldOptions(o *pilosa.FieldOptions) *internal.FieldOptions {
 		return nil
 	}
 	return &internal.FieldOptions{
-		Type:        o.Type,
-		CacheType:   o.CacheType,
-		CacheSize:   o.CacheSize,
-		Min:         o.Min,
-		Max:         o.Max,
-		Base:        o.Base,
-		BitDepth:    uint64(o.BitDepth),
-		TimeQuantum: string(o.TimeQuantum),
-		Keys:        o.Keys,
+		Type:              o.Type,
+		CacheType:         o.CacheType,
+		CacheSize:         o.CacheSize,
+		Min:               o.Min,
+		Max:               o.Max,
+		Base:              o.Base,
+		BitDepth:          uint64(o.BitDepth),
+		TimeQuantum:       string(o.TimeQuantum),
+		Keys:              o.Keys,
+		ClampIncrements:   o.ClampIncrements,
+		Scale:             o.Scale,
+		SnapshotRetention: int64(o.SnapshotRetention),
+		TrackChanges:      o.TrackChanges,
+		ShareTimeViews:    o.ShareTimeViews,
+		TimeQuantumSince:  encodeTimeQuantumSince(o.TimeQuantum, o.TimeQuantumSince),
+	}
+}
+
+// encodeTimeQuantumSince encodes the start times of the units of q as Unix
+// times in nanoseconds, in the order of the units, with zero for units which
+// hold all data.
+func encodeTimeQuantumSince(q pilosa.TimeQuantum, since map[string]time.Time) []int64 {
+	if len(since) == 0 {
+		return nil
+	}
+	a := make([]int64, len(q))
+	for i, unit := range q {
+		if t, ok := since[string(unit)]; ok {
+			a[i] = t.UnixNano()
+		}
 	}
+	return a
 }
 
-// encodeNodes converts a slice of Nodes into its internal representation.
+// encodeNodes converts a slice of Nodes into its internal representation,
+// sorted by ID.
 func encodeNodes(a []*pilosa.Node) []*internal.Node {
+	a = append([]*pilosa.Node(nil), a...)
+	sort.SliceStable(a, func(i, j int) bool {
+		if a[i].ID != a[j].ID {
+			return a[i].ID < a[j].ID
+		}
+		return a[i].URI.String() < a[j].URI.String()
+	})
 	other := make([]*internal.Node, len(a))
 	for i := range a {
 		other[i] = encodeNode(a[i])
@@ -560,6 +851,26 @@ func encodeNode(n *pilosa.Node) *internal.Node {
 		URI:           encodeURI(n.URI),
 		IsCoordinator: n.IsCoordinator,
 		State:         n.State,
+		Maintenance:   n.Maintenance,
+		Resources:     encodeNodeResources(n.Resources),
+		CatchingUp:    n.CatchingUp,
+
+		MinProtocolVersion: n.MinProtocolVersion,
+		MaxProtocolVersion: n.MaxProtocolVersion,
+	}
+}
+
+func encodeNodeResources(r *pilosa.NodeResources) *internal.NodeResources {
+	if r == nil {
+		return nil
+	}
+	return &internal.NodeResources{
+		DiskFree:  r.DiskFree,
+		DataSize:  r.DataSize,
+		MemRSS:    r.MemRSS,
+		Load:      r.Load,
+		Fragments: r.Fragments,
+		SampledAt: r.SampledAt.UnixNano(),
 	}
 }
 
@@ -573,9 +884,10 @@ func encodeURI(u pilosa.URI) *internal.URI {
 
 func encodeClusterStatus(m *pilosa.ClusterStatus) *internal.ClusterStatus {
 	return &internal.ClusterStatus{
-		State:     m.State,
-		ClusterID: m.ClusterID,
-		Nodes:     encodeNodes(m.Nodes),
+		State:            m.State,
+		ClusterID:        m.ClusterID,
+		Nodes:            encodeNodes(m.Nodes),
+		SchemaGeneration: m.SchemaGeneration,
 	}
 }
 
@@ -595,15 +907,26 @@ func encodeCreateIndexMessage(m *pilosa.CreateIndexMessage) *internal.CreateInde
 }
 
 func encodeIndexMeta(m *pilosa.IndexOptions) *internal.IndexMeta {
-	return &internal.IndexMeta{
+	pb := &internal.IndexMeta{
 		Keys:           m.Keys,
 		TrackExistence: m.TrackExistence,
+		ShardWidth:     m.ShardWidth,
+		ReadOnly:       m.ReadOnly,
+		ShardWindow:    m.ShardWindow,
+		Quota:          m.Quota,
+	}
+	if d := m.DefaultFieldOptions; d != nil {
+		pb.DefaultCacheType = d.CacheType
+		pb.DefaultCacheSize = d.CacheSize
+		pb.DefaultTimeQuantum = string(d.TimeQuantum)
 	}
+	return pb
 }
 
 func encodeDeleteIndexMessage(m *pilosa.DeleteIndexMessage) *internal.DeleteIndexMessage {
 	return &internal.DeleteIndexMessage{
 		Index: m.Index,
+		Purge: m.Purge,
 	}
 }
 
@@ -681,11 +1004,16 @@ func encodeNodeEventMessage(m *pilosa.NodeEvent) *internal.NodeEventMessage {
 }
 
 func encodeNodeStatus(m *pilosa.NodeStatus) *internal.NodeStatus {
-	return &internal.NodeStatus{
+	pb := &internal.NodeStatus{
 		Node:    encodeNode(m.Node),
 		Indexes: encodeIndexStatuses(m.Indexes),
-		Schema:  encodeSchema(m.Schema),
 	}
+	// The schema is left out of resize instructions for nodes which
+	// already have it.
+	if m.Schema != nil {
+		pb.Schema = encodeSchema(m.Schema)
+	}
+	return pb
 }
 
 func encodeIndexStatus(m *pilosa.IndexStatus) *internal.IndexStatus {
@@ -700,6 +1028,7 @@ func encodeIndexStatuses(a []*pilosa.IndexStatus) []*internal.IndexStatus {
 	for i := range a {
 		other[i] = encodeIndexStatus(a[i])
 	}
+	sort.Slice(other, func(i, j int) bool { return other[i].Name < other[j].Name })
 	return other
 }
 
@@ -707,6 +1036,7 @@ func encodeFieldStatus(m *pilosa.FieldStatus) *internal.FieldStatus {
 	return &internal.FieldStatus{
 		Name:            m.Name,
 		AvailableShards: m.AvailableShards.Slice(),
+		MaxRowID:        m.MaxRowID,
 	}
 }
 
@@ -715,6 +1045,7 @@ func encodeFieldStatuses(a []*pilosa.FieldStatus) []*internal.FieldStatus {
 	for i := range a {
 		other[i] = encodeFieldStatus(a[i])
 	}
+	sort.Slice(other, func(i, j int) bool { return other[i].Name < other[j].Name })
 	return other
 }
 
@@ -722,6 +1053,183 @@ func encodeRecalculateCaches(*pilosa.RecalculateCaches) *internal.RecalculateCac
 	return &internal.RecalculateCaches{}
 }
 
+func encodeTransactionMessage(m *pilosa.TransactionMessage) *internal.TransactionMessage {
+	return &internal.TransactionMessage{
+		ID:          m.ID,
+		Index:       m.Index,
+		Action:      m.Action,
+		Query:       m.Query,
+		OperationID: m.OperationID,
+	}
+}
+
+func encodeSetIndexReadOnlyMessage(m *pilosa.SetIndexReadOnlyMessage) *internal.SetIndexReadOnlyMessage {
+	return &internal.SetIndexReadOnlyMessage{
+		Index:    m.Index,
+		ReadOnly: m.ReadOnly,
+	}
+}
+
+func encodeSetFieldTimeQuantumMessage(m *pilosa.SetFieldTimeQuantumMessage) *internal.SetFieldTimeQuantumMessage {
+	return &internal.SetFieldTimeQuantumMessage{
+		Index:       m.Index,
+		Field:       m.Field,
+		TimeQuantum: string(m.TimeQuantum),
+		Time:        m.Time.UnixNano(),
+	}
+}
+
+func encodeDeleteSessionMessage(m *pilosa.DeleteSessionMessage) *internal.DeleteSessionMessage {
+	return &internal.DeleteSessionMessage{
+		Session: m.Session,
+	}
+}
+
+func encodeCreateIngestMappingMessage(m *pilosa.CreateIngestMappingMessage) *internal.CreateIngestMappingMessage {
+	return &internal.CreateIngestMappingMessage{
+		Index:   m.Index,
+		Mapping: encodeIngestMapping(m.Mapping),
+	}
+}
+
+func encodeDeleteIngestMappingMessage(m *pilosa.DeleteIngestMappingMessage) *internal.DeleteIngestMappingMessage {
+	return &internal.DeleteIngestMappingMessage{
+		Index: m.Index,
+		Name:  m.Name,
+	}
+}
+
+func encodeClusterSettingsMessage(m *pilosa.ClusterSettingsMessage) *internal.ClusterSettingsMessage {
+	names := make([]string, 0, len(m.Settings))
+	for name := range m.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pb := &internal.ClusterSettingsMessage{
+		Names:  names,
+		Values: make([]string, len(names)),
+	}
+	for i, name := range names {
+		pb.Values[i] = m.Settings[name]
+	}
+	return pb
+}
+
+func encodeSetIndexQuotaMessage(m *pilosa.SetIndexQuotaMessage) *internal.SetIndexQuotaMessage {
+	return &internal.SetIndexQuotaMessage{
+		Index: m.Index,
+		Quota: m.Quota,
+	}
+}
+
+func encodeRestoreIndexMessage(m *pilosa.RestoreIndexMessage) *internal.RestoreIndexMessage {
+	return &internal.RestoreIndexMessage{
+		Index: m.Index,
+	}
+}
+
+func encodePurgeTrashMessage(m *pilosa.PurgeTrashMessage) *internal.PurgeTrashMessage {
+	return &internal.PurgeTrashMessage{
+		Index: m.Index,
+	}
+}
+
+func encodeRebuildExistenceMessage(m *pilosa.RebuildExistenceMessage) *internal.RebuildExistenceMessage {
+	return &internal.RebuildExistenceMessage{
+		Index: m.Index,
+	}
+}
+
+func encodeWarmJobMessage(m *pilosa.WarmJobMessage) *internal.WarmJobMessage {
+	return &internal.WarmJobMessage{
+		ID:      m.ID,
+		Indexes: m.Indexes,
+		Created: m.Created.UnixNano(),
+	}
+}
+
+func encodeMaintenanceMessage(m *pilosa.MaintenanceMessage) *internal.MaintenanceMessage {
+	return &internal.MaintenanceMessage{
+		NodeID:      m.NodeID,
+		Maintenance: m.Maintenance,
+	}
+}
+
+func encodeNodeResourcesMessage(m *pilosa.NodeResourcesMessage) *internal.NodeResourcesMessage {
+	return &internal.NodeResourcesMessage{
+		NodeID:    m.NodeID,
+		Resources: encodeNodeResources(m.Resources),
+	}
+}
+
+func encodeSyncShardMessage(m *pilosa.SyncShardMessage) *internal.SyncShardMessage {
+	return &internal.SyncShardMessage{
+		Index:  m.Index,
+		Fields: m.Fields,
+		Shard:  m.Shard,
+	}
+}
+
+func encodeCatchingUpMessage(m *pilosa.CatchingUpMessage) *internal.CatchingUpMessage {
+	return &internal.CatchingUpMessage{
+		NodeID:     m.NodeID,
+		CatchingUp: m.CatchingUp,
+	}
+}
+
+func encodeSetIndexDefaultFieldOptionsMessage(m *pilosa.SetIndexDefaultFieldOptionsMessage) *internal.SetIndexDefaultFieldOptionsMessage {
+	pb := &internal.SetIndexDefaultFieldOptionsMessage{
+		Index: m.Index,
+	}
+	if d := m.Options; d != nil {
+		pb.CacheType = d.CacheType
+		pb.CacheSize = d.CacheSize
+		pb.TimeQuantum = string(d.TimeQuantum)
+	}
+	return pb
+}
+
+func encodeIngestMappings(a []*pilosa.IngestMapping) []*internal.IngestMapping {
+	other := make([]*internal.IngestMapping, len(a))
+	for i := range a {
+		other[i] = encodeIngestMapping(a[i])
+	}
+	return other
+}
+
+func encodeIngestMapping(m *pilosa.IngestMapping) *internal.IngestMapping {
+	pb := &internal.IngestMapping{
+		Name:   m.Name,
+		Fields: make([]*internal.IngestField, len(m.Fields)),
+	}
+	for i, f := range m.Fields {
+		pf := &internal.IngestField{
+			Name:       f.Name,
+			PrimaryKey: f.PrimaryKey,
+			Actions:    make([]*internal.IngestAction, len(f.Actions)),
+		}
+		for j, a := range f.Actions {
+			pa := &internal.IngestAction{
+				Field:  a.Field,
+				Type:   a.Type,
+				RowID:  a.RowID,
+				Format: a.Format,
+			}
+			for k := range a.ValueMap {
+				pa.ValueKeys = append(pa.ValueKeys, k)
+			}
+			sort.Strings(pa.ValueKeys)
+			for _, k := range pa.ValueKeys {
+				pa.ValueIDs = append(pa.ValueIDs, a.ValueMap[k])
+			}
+			pf.Actions[j] = pa
+		}
+		pb.Fields[i] = pf
+	}
+	return pb
+}
+
 func encodeTranslateKeysResponse(response *pilosa.TranslateKeysResponse) *internal.TranslateKeysResponse {
 	return &internal.TranslateKeysResponse{
 		IDs: response.IDs,
@@ -742,19 +1250,32 @@ func decodeResizeInstruction(ri *internal.ResizeInstruction, m *pilosa.ResizeIns
 	decodeNode(ri.Node, m.Node)
 	m.Coordinator = &pilosa.Node{}
 	decodeNode(ri.Coordinator, m.Coordinator)
-	m.Sources = make([]*pilosa.ResizeSource, len(ri.Sources))
-	decodeResizeSources(ri.Sources, m.Sources)
+	m.Sources = decodeResizeSources(ri.Sources)
 	m.NodeStatus = &pilosa.NodeStatus{}
 	decodeNodeStatus(ri.NodeStatus, m.NodeStatus)
 	m.ClusterStatus = &pilosa.ClusterStatus{}
 	decodeClusterStatus(ri.ClusterStatus, m.ClusterStatus)
 }
 
-func decodeResizeSources(srcs []*internal.ResizeSource, m []*pilosa.ResizeSource) {
-	for i := range srcs {
-		m[i] = &pilosa.ResizeSource{}
-		decodeResizeSource(srcs[i], m[i])
+// decodeResizeSources decodes srcs, expanding the sources which list their
+// shards into one source per shard.
+func decodeResizeSources(srcs []*internal.ResizeSource) []*pilosa.ResizeSource {
+	m := make([]*pilosa.ResizeSource, 0, len(srcs))
+	for _, src := range srcs {
+		if len(src.Shards) == 0 {
+			rs := &pilosa.ResizeSource{}
+			decodeResizeSource(src, rs)
+			m = append(m, rs)
+			continue
+		}
+		for _, shard := range src.Shards {
+			rs := &pilosa.ResizeSource{}
+			decodeResizeSource(src, rs)
+			rs.Shard = shard
+			m = append(m, rs)
+		}
 	}
+	return m
 }
 
 func decodeResizeSource(rs *internal.ResizeSource, m *pilosa.ResizeSource) {
@@ -769,6 +1290,7 @@ func decodeResizeSource(rs *internal.ResizeSource, m *pilosa.ResizeSource) {
 func decodeSchema(s *internal.Schema, m *pilosa.Schema) {
 	m.Indexes = make([]*pilosa.IndexInfo, len(s.Indexes))
 	decodeIndexes(s.Indexes, m.Indexes)
+	m.Generation = s.Generation
 }
 
 func decodeIndexes(idxs []*internal.Index, m []*pilosa.IndexInfo) {
@@ -782,6 +1304,11 @@ func decodeIndex(idx *internal.Index, m *pilosa.IndexInfo) {
 	m.Name = idx.Name
 	m.Fields = make([]*pilosa.FieldInfo, len(idx.Fields))
 	decodeFields(idx.Fields, m.Fields)
+	m.ShardWidth = idx.ShardWidth
+	m.IngestMappings = decodeIngestMappings(idx.IngestMappings)
+	if idx.Meta != nil {
+		decodeIndexMeta(idx.Meta, &m.Options)
+	}
 }
 
 func decodeFields(fs []*internal.Field, m []*pilosa.FieldInfo) {
@@ -795,6 +1322,7 @@ func decodeField(f *internal.Field, m *pilosa.FieldInfo) {
 	m.Name = f.Name
 	m.Options = pilosa.FieldOptions{}
 	decodeFieldOptions(f.Meta, &m.Options)
+	m.MaxRowID = f.MaxRowID
 	m.Views = make([]*pilosa.ViewInfo, 0, len(f.Views))
 	for _, viewname := range f.Views {
 		m.Views = append(m.Views, &pilosa.ViewInfo{Name: viewname})
@@ -811,6 +1339,27 @@ func decodeFieldOptions(options *internal.FieldOptions, m *pilosa.FieldOptions)
 	m.BitDepth = uint(options.BitDepth)
 	m.TimeQuantum = pilosa.TimeQuantum(options.TimeQuantum)
 	m.Keys = options.Keys
+	m.ClampIncrements = options.ClampIncrements
+	m.Scale = options.Scale
+	m.SnapshotRetention = time.Duration(options.SnapshotRetention)
+	m.TrackChanges = options.TrackChanges
+	m.ShareTimeViews = options.ShareTimeViews
+	m.TimeQuantumSince = decodeTimeQuantumSince(m.TimeQuantum, options.TimeQuantumSince)
+}
+
+// decodeTimeQuantumSince is the inverse of encodeTimeQuantumSince.
+func decodeTimeQuantumSince(q pilosa.TimeQuantum, a []int64) map[string]time.Time {
+	var since map[string]time.Time
+	for i, unit := range q {
+		if i >= len(a) || a[i] == 0 {
+			continue
+		}
+		if since == nil {
+			since = make(map[string]time.Time)
+		}
+		since[string(unit)] = time.Unix(0, a[i]).UTC()
+	}
+	return since
 }
 
 func decodeNodes(a []*internal.Node, m []*pilosa.Node) {
@@ -825,6 +1374,7 @@ func decodeClusterStatus(cs *internal.ClusterStatus, m *pilosa.ClusterStatus) {
 	m.ClusterID = cs.ClusterID
 	m.Nodes = make([]*pilosa.Node, len(cs.Nodes))
 	decodeNodes(cs.Nodes, m.Nodes)
+	m.SchemaGeneration = cs.SchemaGeneration
 }
 
 func decodeNode(node *internal.Node, m *pilosa.Node) {
@@ -832,6 +1382,25 @@ func decodeNode(node *internal.Node, m *pilosa.Node) {
 	decodeURI(node.URI, &m.URI)
 	m.IsCoordinator = node.IsCoordinator
 	m.State = node.State
+	m.Maintenance = node.Maintenance
+	m.Resources = decodeNodeResources(node.Resources)
+	m.CatchingUp = node.CatchingUp
+	m.MinProtocolVersion = node.MinProtocolVersion
+	m.MaxProtocolVersion = node.MaxProtocolVersion
+}
+
+func decodeNodeResources(pb *internal.NodeResources) *pilosa.NodeResources {
+	if pb == nil {
+		return nil
+	}
+	return &pilosa.NodeResources{
+		DiskFree:  pb.DiskFree,
+		DataSize:  pb.DataSize,
+		MemRSS:    pb.MemRSS,
+		Load:      pb.Load,
+		Fragments: pb.Fragments,
+		SampledAt: time.Unix(0, pb.SampledAt).UTC(),
+	}
 }
 
 func decodeURI(i *internal.URI, m *pilosa.URI) {
@@ -855,10 +1424,22 @@ func decodeCreateIndexMessage(pb *internal.CreateIndexMessage, m *pilosa.CreateI
 func decodeIndexMeta(pb *internal.IndexMeta, m *pilosa.IndexOptions) {
 	m.Keys = pb.Keys
 	m.TrackExistence = pb.TrackExistence
+	m.ShardWidth = pb.ShardWidth
+	m.ReadOnly = pb.ReadOnly
+	m.ShardWindow = pb.ShardWindow
+	m.Quota = pb.Quota
+	if pb.DefaultCacheType != "" || pb.DefaultCacheSize != 0 || pb.DefaultTimeQuantum != "" {
+		m.DefaultFieldOptions = &pilosa.DefaultFieldOptions{
+			CacheType:   pb.DefaultCacheType,
+			CacheSize:   pb.DefaultCacheSize,
+			TimeQuantum: pilosa.TimeQuantum(pb.DefaultTimeQuantum),
+		}
+	}
 }
 
 func decodeDeleteIndexMessage(pb *internal.DeleteIndexMessage, m *pilosa.DeleteIndexMessage) {
 	m.Index = pb.Index
+	m.Purge = pb.Purge
 }
 
 func decodeCreateFieldMessage(pb *internal.CreateFieldMessage, m *pilosa.CreateFieldMessage) {
@@ -922,8 +1503,10 @@ func decodeNodeEventMessage(pb *internal.NodeEventMessage, m *pilosa.NodeEvent)
 func decodeNodeStatus(pb *internal.NodeStatus, m *pilosa.NodeStatus) {
 	m.Node = &pilosa.Node{}
 	m.Indexes = decodeIndexStatuses(pb.Indexes)
-	m.Schema = &pilosa.Schema{}
-	decodeSchema(pb.Schema, m.Schema)
+	if pb.Schema != nil {
+		m.Schema = &pilosa.Schema{}
+		decodeSchema(pb.Schema, m.Schema)
+	}
 }
 
 func decodeIndexStatuses(a []*internal.IndexStatus) []*pilosa.IndexStatus {
@@ -952,10 +1535,154 @@ func decodeFieldStatuses(a []*internal.FieldStatus) []*pilosa.FieldStatus {
 func decodeFieldStatus(pb *internal.FieldStatus, m *pilosa.FieldStatus) {
 	m.Name = pb.Name
 	m.AvailableShards = roaring.NewBitmap(pb.AvailableShards...)
+	m.MaxRowID = pb.MaxRowID
 }
 
 func decodeRecalculateCaches(pb *internal.RecalculateCaches, m *pilosa.RecalculateCaches) {}
 
+func decodeTransactionMessage(pb *internal.TransactionMessage, m *pilosa.TransactionMessage) {
+	m.ID = pb.ID
+	m.Index = pb.Index
+	m.Action = pb.Action
+	m.Query = pb.Query
+	m.OperationID = pb.OperationID
+}
+
+func decodeSetIndexReadOnlyMessage(pb *internal.SetIndexReadOnlyMessage, m *pilosa.SetIndexReadOnlyMessage) {
+	m.Index = pb.Index
+	m.ReadOnly = pb.ReadOnly
+}
+
+func decodeSetFieldTimeQuantumMessage(pb *internal.SetFieldTimeQuantumMessage, m *pilosa.SetFieldTimeQuantumMessage) {
+	m.Index = pb.Index
+	m.Field = pb.Field
+	m.TimeQuantum = pilosa.TimeQuantum(pb.TimeQuantum)
+	if pb.Time != 0 {
+		m.Time = time.Unix(0, pb.Time).UTC()
+	}
+}
+
+func decodeDeleteSessionMessage(pb *internal.DeleteSessionMessage, m *pilosa.DeleteSessionMessage) {
+	m.Session = pb.Session
+}
+
+func decodeCreateIngestMappingMessage(pb *internal.CreateIngestMappingMessage, m *pilosa.CreateIngestMappingMessage) {
+	m.Index = pb.Index
+	if pb.Mapping != nil {
+		m.Mapping = decodeIngestMapping(pb.Mapping)
+	}
+}
+
+func decodeDeleteIngestMappingMessage(pb *internal.DeleteIngestMappingMessage, m *pilosa.DeleteIngestMappingMessage) {
+	m.Index = pb.Index
+	m.Name = pb.Name
+}
+
+func decodeClusterSettingsMessage(pb *internal.ClusterSettingsMessage, m *pilosa.ClusterSettingsMessage) {
+	m.Settings = make(map[string]string, len(pb.Names))
+	for i, name := range pb.Names {
+		if i < len(pb.Values) {
+			m.Settings[name] = pb.Values[i]
+		}
+	}
+}
+
+func decodeSetIndexQuotaMessage(pb *internal.SetIndexQuotaMessage, m *pilosa.SetIndexQuotaMessage) {
+	m.Index = pb.Index
+	m.Quota = pb.Quota
+}
+
+func decodeRestoreIndexMessage(pb *internal.RestoreIndexMessage, m *pilosa.RestoreIndexMessage) {
+	m.Index = pb.Index
+}
+
+func decodePurgeTrashMessage(pb *internal.PurgeTrashMessage, m *pilosa.PurgeTrashMessage) {
+	m.Index = pb.Index
+}
+
+func decodeRebuildExistenceMessage(pb *internal.RebuildExistenceMessage, m *pilosa.RebuildExistenceMessage) {
+	m.Index = pb.Index
+}
+
+func decodeWarmJobMessage(pb *internal.WarmJobMessage, m *pilosa.WarmJobMessage) {
+	m.ID = pb.ID
+	m.Indexes = pb.Indexes
+	m.Created = time.Unix(0, pb.Created).UTC()
+}
+
+func decodeMaintenanceMessage(pb *internal.MaintenanceMessage, m *pilosa.MaintenanceMessage) {
+	m.NodeID = pb.NodeID
+	m.Maintenance = pb.Maintenance
+}
+
+func decodeNodeResourcesMessage(pb *internal.NodeResourcesMessage, m *pilosa.NodeResourcesMessage) {
+	m.NodeID = pb.NodeID
+	m.Resources = decodeNodeResources(pb.Resources)
+}
+
+func decodeSyncShardMessage(pb *internal.SyncShardMessage, m *pilosa.SyncShardMessage) {
+	m.Index = pb.Index
+	m.Fields = pb.Fields
+	m.Shard = pb.Shard
+}
+
+func decodeCatchingUpMessage(pb *internal.CatchingUpMessage, m *pilosa.CatchingUpMessage) {
+	m.NodeID = pb.NodeID
+	m.CatchingUp = pb.CatchingUp
+}
+
+func decodeSetIndexDefaultFieldOptionsMessage(pb *internal.SetIndexDefaultFieldOptionsMessage, m *pilosa.SetIndexDefaultFieldOptionsMessage) {
+	m.Index = pb.Index
+	if pb.CacheType != "" || pb.CacheSize != 0 || pb.TimeQuantum != "" {
+		m.Options = &pilosa.DefaultFieldOptions{
+			CacheType:   pb.CacheType,
+			CacheSize:   pb.CacheSize,
+			TimeQuantum: pilosa.TimeQuantum(pb.TimeQuantum),
+		}
+	}
+}
+
+func decodeIngestMappings(a []*internal.IngestMapping) []*pilosa.IngestMapping {
+	if len(a) == 0 {
+		return nil
+	}
+	other := make([]*pilosa.IngestMapping, len(a))
+	for i := range a {
+		other[i] = decodeIngestMapping(a[i])
+	}
+	return other
+}
+
+func decodeIngestMapping(pb *internal.IngestMapping) *pilosa.IngestMapping {
+	m := &pilosa.IngestMapping{
+		Name:   pb.Name,
+		Fields: make([]*pilosa.IngestField, len(pb.Fields)),
+	}
+	for i, pf := range pb.Fields {
+		f := &pilosa.IngestField{
+			Name:       pf.Name,
+			PrimaryKey: pf.PrimaryKey,
+		}
+		for _, pa := range pf.Actions {
+			a := &pilosa.IngestAction{
+				Field:  pa.Field,
+				Type:   pa.Type,
+				RowID:  pa.RowID,
+				Format: pa.Format,
+			}
+			if len(pa.ValueKeys) > 0 {
+				a.ValueMap = make(map[string]uint64, len(pa.ValueKeys))
+				for j, k := range pa.ValueKeys {
+					a.ValueMap[k] = pa.ValueIDs[j]
+				}
+			}
+			f.Actions = append(f.Actions, a)
+		}
+		m.Fields[i] = f
+	}
+	return m
+}
+
 func decodeQueryRequest(pb *internal.QueryRequest, m *pilosa.QueryRequest) {
 	m.Query = pb.Query
 	m.Shards = pb.Shards
@@ -963,6 +1690,30 @@ func decodeQueryRequest(pb *internal.QueryRequest, m *pilosa.QueryRequest) {
 	m.Remote = pb.Remote
 	m.ExcludeRowAttrs = pb.ExcludeRowAttrs
 	m.ExcludeColumns = pb.ExcludeColumns
+	m.Session = pb.Session
+	m.StoreAs = pb.StoreAs
+	m.Snapshot = pb.Snapshot
+	m.Roaring = pb.Roaring
+	m.AsOf = decodeTime(pb.AsOf)
+	m.Estimate = pb.Estimate
+	m.MaxStaleness = time.Duration(pb.MaxStaleness)
+}
+
+// encodeTime encodes t as nanoseconds since the Unix epoch. The zero time
+// is encoded as zero.
+func encodeTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// decodeTime decodes a time encoded by encodeTime.
+func decodeTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
 }
 
 func decodeImportRequest(pb *internal.ImportRequest, m *pilosa.ImportRequest) {
@@ -992,6 +1743,7 @@ func decodeImportRoaringRequest(pb *internal.ImportRoaringRequest, m *pilosa.Imp
 	}
 	m.Clear = pb.Clear
 	m.Views = views
+	m.OperationID = pb.OperationID
 }
 
 func decodeImportResponse(pb *internal.ImportResponse, m *pilosa.ImportResponse) {
@@ -1004,14 +1756,27 @@ func decodeBlockDataRequest(pb *internal.BlockDataRequest, m *pilosa.BlockDataRe
 	m.View = pb.View
 	m.Shard = pb.Shard
 	m.Block = pb.Block
+	m.Continuation = pb.Continuation
+	m.Limit = pb.Limit
 }
 
 func decodeBlockDataResponse(pb *internal.BlockDataResponse, m *pilosa.BlockDataResponse) {
 	m.RowIDs = pb.RowIDs
 	m.ColumnIDs = pb.ColumnIDs
+	m.Continuation = pb.Continuation
 }
 
-func decodeQueryResponse(pb *internal.QueryResponse, m *pilosa.QueryResponse) {
+func decodeFragmentBlocksResponse(pb *internal.FragmentBlocksResponse, m *pilosa.FragmentBlocksResponse) {
+	m.Blocks = make([]pilosa.FragmentBlock, len(pb.Blocks))
+	for i, blk := range pb.Blocks {
+		m.Blocks[i] = pilosa.FragmentBlock{
+			ID:       int(blk.ID),
+			Checksum: blk.Checksum,
+		}
+	}
+}
+
+func decodeQueryResponse(pb *internal.QueryResponse, m *pilosa.QueryResponse) error {
 	m.ColumnAttrSets = make([]*pilosa.ColumnAttrSet, len(pb.ColumnAttrSets))
 	decodeColumnAttrSets(pb.ColumnAttrSets, m.ColumnAttrSets)
 	if pb.Err == "" {
@@ -1020,7 +1785,9 @@ func decodeQueryResponse(pb *internal.QueryResponse, m *pilosa.QueryResponse) {
 		m.Err = errors.New(pb.Err)
 	}
 	m.Results = make([]interface{}, len(pb.Results))
-	decodeQueryResults(pb.Results, m.Results)
+	m.Shards = pb.Shards
+	m.StaleShards = pb.StaleShards
+	return decodeQueryResults(pb.Results, m.Results)
 }
 
 func decodeColumnAttrSets(pb []*internal.ColumnAttrSet, m []*pilosa.ColumnAttrSet) {
@@ -1036,10 +1803,19 @@ func decodeColumnAttrSet(pb *internal.ColumnAttrSet, m *pilosa.ColumnAttrSet) {
 	m.Attrs = decodeAttrs(pb.Attrs)
 }
 
-func decodeQueryResults(pb []*internal.QueryResult, m []interface{}) {
+func decodeQueryResults(pb []*internal.QueryResult, m []interface{}) error {
 	for i := range pb {
+		if pb[i].Type == queryResultTypeRow && len(pb[i].GetRow().GetRoaring()) > 0 {
+			row, err := decodeRowRoaring(pb[i].Row)
+			if err != nil {
+				return errors.Wrapf(err, "decoding result %d", i)
+			}
+			m[i] = row
+			continue
+		}
 		m[i] = decodeQueryResult(pb[i])
 	}
+	return nil
 }
 
 func decodeTranslateKeysRequest(pb *internal.TranslateKeysRequest, m *pilosa.TranslateKeysRequest) {
@@ -1064,6 +1840,9 @@ const (
 	queryResultTypeGroupCounts
 	queryResultTypeRowIdentifiers
 	queryResultTypePair
+	queryResultTypeFieldCounts
+	queryResultTypeEstimate
+	queryResultTypeSample
 )
 
 func decodeQueryResult(pb *internal.QueryResult) interface{} {
@@ -1088,6 +1867,12 @@ func decodeQueryResult(pb *internal.QueryResult) interface{} {
 		return decodeGroupCounts(pb.GroupCounts)
 	case queryResultTypePair:
 		return decodePair(pb.Pairs[0])
+	case queryResultTypeFieldCounts:
+		return decodeFieldCounts(pb.Pairs)
+	case queryResultTypeEstimate:
+		return decodeQueryEstimate(pb.Pairs)
+	case queryResultTypeSample:
+		return decodeSampleResult(pb.Pairs)
 	}
 	panic(fmt.Sprintf("unknown type: %d", pb.Type))
 }
@@ -1107,6 +1892,18 @@ func decodeRow(pr *internal.Row) *pilosa.Row {
 	return r
 }
 
+// decodeRowRoaring converts r from its internal representation as a
+// serialized roaring bitmap.
+func decodeRowRoaring(pr *internal.Row) (*pilosa.Row, error) {
+	r, err := pilosa.NewRowFromRoaring(pr.Roaring)
+	if err != nil {
+		return nil, err
+	}
+	r.Attrs = decodeAttrs(pr.Attrs)
+	r.Keys = pr.Keys
+	return r, nil
+}
+
 func decodeAttrs(pb []*internal.Attr) map[string]interface{} {
 	m := make(map[string]interface{}, len(pb))
 	for i := range pb {
@@ -1186,10 +1983,61 @@ func decodePair(pb *internal.Pair) pilosa.Pair {
 	}
 }
 
+// decodeFieldCounts converts pairs of field names and counts to FieldCounts.
+func decodeFieldCounts(a []*internal.Pair) pilosa.FieldCounts {
+	m := make(pilosa.FieldCounts, len(a))
+	for _, pb := range a {
+		m[pb.Key] = pb.Count
+	}
+	return m
+}
+
+// decodeSampleResult decodes a sample result from the pairs of its
+// statistics and of its result.
+func decodeSampleResult(a []*internal.Pair) *pilosa.SampleResult {
+	m := &pilosa.SampleResult{}
+	if len(a) < sampleResultPairs {
+		return m
+	}
+	m.Count = a[0].Count
+	m.Shards = a[1].Count
+	m.TotalShards = a[2].Count
+	m.Fraction = math.Float64frombits(a[3].Count)
+	m.Seed = int64(a[4].Count)
+	m.Variance = math.Float64frombits(a[5].Count)
+	m.StdErr = math.Float64frombits(a[6].Count)
+	if len(a) > sampleResultPairs {
+		m.Pairs = decodePairs(a[sampleResultPairs:])
+	}
+	return m
+}
+
+// decodeQueryEstimate decodes an estimate from the pairs of its sizes.
+func decodeQueryEstimate(a []*internal.Pair) *pilosa.QueryEstimate {
+	m := &pilosa.QueryEstimate{}
+	for _, pb := range a {
+		switch {
+		case pb.Key == "columns":
+			m.Columns = pb.Count
+		case pb.Key == "candidates":
+			m.Candidates = pb.Count
+		case pb.Key == "length":
+			m.Length = pb.Count
+		case strings.HasPrefix(pb.Key, "bytes."):
+			if m.Bytes == nil {
+				m.Bytes = make(map[string]uint64)
+			}
+			m.Bytes[strings.TrimPrefix(pb.Key, "bytes.")] = pb.Count
+		}
+	}
+	return m
+}
+
 func decodeValCount(pb *internal.ValCount) pilosa.ValCount {
 	return pilosa.ValCount{
 		Val:   pb.Val,
 		Count: pb.Count,
+		Scale: pb.Scale,
 	}
 }
 
@@ -1221,6 +2069,23 @@ func encodeRow(r *pilosa.Row) *internal.Row {
 	}
 }
 
+// encodeRowRoaring is like encodeRow, but serializes the columns of r as a
+// roaring bitmap.
+func encodeRowRoaring(r *pilosa.Row) *internal.Row {
+	if r == nil {
+		return nil
+	}
+	data, err := r.MarshalRoaring()
+	if err != nil {
+		return encodeRow(r)
+	}
+	return &internal.Row{
+		Roaring: data,
+		Keys:    r.Keys,
+		Attrs:   encodeAttrs(r.Attrs),
+	}
+}
+
 func encodeRowIdentifiers(r pilosa.RowIdentifiers) *internal.RowIdentifiers {
 	return &internal.RowIdentifiers{
 		Rows: r.Rows,
@@ -1275,10 +2140,56 @@ func encodePair(p pilosa.Pair) *internal.Pair {
 	}
 }
 
+// encodeFieldCounts converts m to pairs of field names and counts, sorted by
+// field name.
+func encodeFieldCounts(m pilosa.FieldCounts) []*internal.Pair {
+	other := make([]*internal.Pair, 0, len(m))
+	for name, n := range m {
+		other = append(other, &internal.Pair{Key: name, Count: n})
+	}
+	sort.Slice(other, func(i, j int) bool { return other[i].Key < other[j].Key })
+	return other
+}
+
+// sampleResultPairs is the number of pairs which encode the statistics of
+// a sample result, before the pairs of the result.
+const sampleResultPairs = 7
+
+// encodeSampleResult encodes the statistics of a sample result as pairs,
+// the floats as their bits, followed by the pairs of the result.
+func encodeSampleResult(m *pilosa.SampleResult) []*internal.Pair {
+	other := []*internal.Pair{
+		{Key: "count", Count: m.Count},
+		{Key: "shards", Count: m.Shards},
+		{Key: "totalShards", Count: m.TotalShards},
+		{Key: "fraction", Count: math.Float64bits(m.Fraction)},
+		{Key: "seed", Count: uint64(m.Seed)},
+		{Key: "variance", Count: math.Float64bits(m.Variance)},
+		{Key: "stdErr", Count: math.Float64bits(m.StdErr)},
+	}
+	return append(other, encodePairs(m.Pairs)...)
+}
+
+// encodeQueryEstimate encodes the sizes of an estimate as pairs, the sizes
+// of the encodings being prefixed with "bytes.".
+func encodeQueryEstimate(m *pilosa.QueryEstimate) []*internal.Pair {
+	other := []*internal.Pair{
+		{Key: "columns", Count: m.Columns},
+		{Key: "candidates", Count: m.Candidates},
+		{Key: "length", Count: m.Length},
+	}
+	for encoding, n := range m.Bytes {
+		other = append(other, &internal.Pair{Key: "bytes." + encoding, Count: n})
+	}
+	sort.Slice(other[3:], func(i, j int) bool { return other[3+i].Key < other[3+j].Key })
+	return other
+}
+
 func encodeValCount(vc pilosa.ValCount) *internal.ValCount {
 	return &internal.ValCount{
 		Val:   vc.Val,
 		Count: vc.Count,
+		Scale: vc.Scale,
 	}
 }
 
@@ -0,0 +1,177 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	gproto "github.com/gogo/protobuf/proto"
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/encoding/proto"
+	"github.com/pilosa/pilosa/v2/internal"
+)
+
+// Ensure the start times of the units added to the time quantum of a field
+// survive encoding.
+func TestSerializer_TimeQuantumSince(t *testing.T) {
+	ts := time.Date(2019, time.February, 4, 0, 0, 0, 0, time.UTC)
+	for _, m := range []pilosa.Message{
+		&pilosa.CreateFieldMessage{Index: "i", Field: "f", Meta: &pilosa.FieldOptions{
+			Type:             pilosa.FieldTypeTime,
+			TimeQuantum:      "YMWD",
+			TimeQuantumSince: map[string]time.Time{"W": ts},
+		}},
+		&pilosa.SetFieldTimeQuantumMessage{Index: "i", Field: "f", TimeQuantum: "YMWD", Time: ts},
+	} {
+		buf, err := proto.Serializer{}.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		other := reflect.New(reflect.TypeOf(m).Elem()).Interface()
+		if err := (proto.Serializer{}).Unmarshal(buf, other); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(other, m) {
+			t.Fatalf("unexpected message: %+v", other)
+		}
+	}
+}
+
+// Ensure a resize instruction is encoded with the encoding of its protocol
+// version, and decoded to the same sources.
+func TestSerializer_ResizeInstruction(t *testing.T) {
+	node0 := &pilosa.Node{ID: "node0", MinProtocolVersion: 1, MaxProtocolVersion: 2}
+	node1 := &pilosa.Node{ID: "node1", MinProtocolVersion: 1, MaxProtocolVersion: 1}
+	var sources []*pilosa.ResizeSource
+	for _, src := range []struct {
+		node  *pilosa.Node
+		view  string
+		shard uint64
+	}{
+		{node0, "standard", 0}, {node0, "standard", 2}, {node0, "standard", 5},
+		{node1, "standard", 1}, {node0, "other", 3},
+	} {
+		sources = append(sources, &pilosa.ResizeSource{Node: src.node, Index: "i", Field: "f", View: src.view, Shard: src.shard})
+	}
+
+	for v, n := range map[uint32]int{1: 5, 2: 3} {
+		instr := &pilosa.ResizeInstruction{
+			JobID:           1,
+			Node:            node1,
+			Coordinator:     node0,
+			Sources:         sources,
+			NodeStatus:      &pilosa.NodeStatus{Node: node1},
+			ClusterStatus:   &pilosa.ClusterStatus{Nodes: []*pilosa.Node{node0, node1}},
+			ProtocolVersion: v,
+		}
+		buf, err := proto.Serializer{}.Marshal(instr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The sources on the wire are grouped by view since version 2.
+		var pb internal.ResizeInstruction
+		if err := gproto.Unmarshal(buf, &pb); err != nil {
+			t.Fatal(err)
+		} else if len(pb.Sources) != n {
+			t.Fatalf("version %d: unexpected number of encoded sources: %d", v, len(pb.Sources))
+		}
+
+		var other pilosa.ResizeInstruction
+		if err := (proto.Serializer{}).Unmarshal(buf, &other); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(other.Sources, sources) {
+			t.Fatalf("version %d: unexpected sources: %v", v, other.Sources)
+		} else if !reflect.DeepEqual(other.ClusterStatus.Nodes, []*pilosa.Node{node0, node1}) {
+			t.Fatalf("version %d: unexpected nodes: %v", v, other.ClusterStatus.Nodes)
+		}
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "Update the golden files of the tests.")
+
+// Ensure schemas and cluster statuses are encoded to the same bytes
+// regardless of the order of their indexes, fields, views and nodes.
+func TestSerializer_Golden(t *testing.T) {
+	schema := func(reverse bool) *pilosa.Schema {
+		order := func(names ...string) []string {
+			if reverse {
+				for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+					names[i], names[j] = names[j], names[i]
+				}
+			}
+			return names
+		}
+		s := &pilosa.Schema{Generation: 3}
+		for _, name := range order("i0", "i1") {
+			idx := &pilosa.IndexInfo{Name: name, ShardWidth: pilosa.ShardWidth}
+			for _, name := range order("f0", "f1") {
+				f := &pilosa.FieldInfo{Name: name, Options: pilosa.FieldOptions{Type: pilosa.FieldTypeTime, TimeQuantum: "YM"}}
+				for _, name := range order("standard", "standard_2019", "standard_201901") {
+					f.Views = append(f.Views, &pilosa.ViewInfo{Name: name})
+				}
+				idx.Fields = append(idx.Fields, f)
+			}
+			s.Indexes = append(s.Indexes, idx)
+		}
+		return s
+	}
+	status := func(reverse bool) *pilosa.ClusterStatus {
+		nodes := []*pilosa.Node{
+			{ID: "node0", URI: pilosa.URI{Scheme: "http", Host: "localhost", Port: 10101}, IsCoordinator: true},
+			{ID: "node1", URI: pilosa.URI{Scheme: "http", Host: "localhost", Port: 10102}},
+			{ID: "node2", URI: pilosa.URI{Scheme: "http", Host: "localhost", Port: 10103}},
+		}
+		if reverse {
+			nodes[0], nodes[2] = nodes[2], nodes[0]
+		}
+		return &pilosa.ClusterStatus{ClusterID: "c", State: pilosa.ClusterStateNormal, Nodes: nodes, SchemaGeneration: 3}
+	}
+
+	for _, tt := range []struct {
+		golden string
+		msg    func(reverse bool) pilosa.Message
+	}{
+		{"schema.golden", func(reverse bool) pilosa.Message { return schema(reverse) }},
+		{"status.golden", func(reverse bool) pilosa.Message { return status(reverse) }},
+	} {
+		buf, err := proto.Serializer{}.Marshal(tt.msg(false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", tt.golden)
+		if *updateGolden {
+			if err := ioutil.WriteFile(golden, buf, 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if exp, err := ioutil.ReadFile(golden); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(buf, exp) {
+			t.Fatalf("%s: unexpected encoding: %x", tt.golden, buf)
+		}
+
+		if other, err := (proto.Serializer{}).Marshal(tt.msg(true)); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(other, buf) {
+			t.Fatalf("%s: unexpected encoding of the reversed message: %x", tt.golden, other)
+		}
+	}
+}
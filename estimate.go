@@ -0,0 +1,240 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"encoding/json"
+	"math/bits"
+	"strconv"
+
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pilosa/pilosa/v2/tracing"
+	"github.com/pkg/errors"
+)
+
+// QueryEstimate is the result of a call of a query executed with estimate
+// set. Only cardinalities are computed: the columns and rows of the result
+// aren't materialized or transferred between nodes.
+type QueryEstimate struct {
+	// Columns is the number of columns of a row result.
+	Columns uint64
+
+	// Candidates is the number of rows considered by TopN(), Rows(),
+	// GroupBy(), MinRow() and MaxRow(), or the number of columns considered
+	// by Count(), Sum(), Min() and Max().
+	Candidates uint64
+
+	// Length is the expected length of the result: the number of columns of
+	// a row, of pairs of TopN(), of rows of Rows(), an upper bound of the
+	// number of groups of GroupBy(), and 1 for other calls.
+	Length uint64
+
+	// Bytes is the approximate size of a row result in each encoding.
+	Bytes map[string]uint64
+}
+
+// MarshalJSON returns a JSON-encoded byte slice of e, which is labeled as
+// an estimate.
+func (e *QueryEstimate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Estimate   bool              `json:"estimate"`
+		Columns    uint64            `json:"columns"`
+		Candidates uint64            `json:"candidates"`
+		Length     uint64            `json:"length"`
+		Bytes      map[string]uint64 `json:"bytes,omitempty"`
+	}{
+		Estimate:   true,
+		Columns:    e.Columns,
+		Candidates: e.Candidates,
+		Length:     e.Length,
+		Bytes:      e.Bytes,
+	})
+}
+
+// executeEstimate estimates the size of the result of a call. Row results
+// are counted with Count(), and the candidate rows of TopN() and GroupBy()
+// are listed with Rows(), so that remote nodes only return cardinalities.
+func (e *executor) executeEstimate(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions) (*QueryEstimate, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeEstimate")
+	defer span.Finish()
+
+	if err := e.validateCallArgs(c); err != nil {
+		return nil, errors.Wrap(err, "validating args")
+	}
+
+	if sample, _ := sampleCall(c); sample != nil {
+		return nil, NewBadRequestError(errors.New("Sample() cannot be estimated"))
+	}
+
+	switch c.Name {
+	case "Count":
+		n, err := e.executeCount(ctx, index, c, shards, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryEstimate{Candidates: n, Length: 1}, nil
+
+	case "Sum", "Min", "Max":
+		// The columns considered are those with a value, of the filter if
+		// there is one.
+		fieldName, _ := c.Args["field"].(string)
+		if fieldName == "" {
+			return nil, errors.Errorf("%s(): field required", c.Name)
+		} else if len(c.Children) > 1 {
+			return nil, errors.Errorf("%s() only accepts a single bitmap input", c.Name)
+		}
+		input := &pql.Call{Name: "Row", Args: map[string]interface{}{fieldName: &pql.Condition{Op: pql.NEQ}}}
+		if len(c.Children) == 1 {
+			input = &pql.Call{Name: "Intersect", Children: []*pql.Call{c.Children[0], input}}
+		}
+		n, err := e.executeCount(ctx, index, &pql.Call{Name: "Count", Children: []*pql.Call{input}}, shards, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryEstimate{Candidates: n, Length: 1}, nil
+
+	case "MinRow", "MaxRow":
+		fieldName, _ := c.Args["field"].(string)
+		n, err := e.estimateRows(ctx, index, fieldName, shards, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryEstimate{Candidates: n, Length: 1}, nil
+
+	case "TopN":
+		var n uint64
+		if ids, _, err := c.UintSliceArg("ids"); err != nil {
+			return nil, errors.Wrap(err, "TopN()")
+		} else if len(ids) > 0 {
+			n = uint64(len(ids))
+		} else {
+			fieldName, _ := c.Args["_field"].(string)
+			if n, err = e.estimateRows(ctx, index, fieldName, shards, opt); err != nil {
+				return nil, err
+			}
+		}
+		limit, _, err := c.UintArg("n")
+		if err != nil {
+			return nil, errors.Wrap(err, "TopN()")
+		}
+		return &QueryEstimate{Candidates: n, Length: minLimit(n, limit)}, nil
+
+	case "Rows":
+		rows, err := e.executeRows(ctx, index, c, shards, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryEstimate{Candidates: uint64(len(rows)), Length: uint64(len(rows))}, nil
+
+	case "GroupBy":
+		if len(c.Children) == 0 {
+			return nil, errors.New("need at least one child call")
+		}
+		n := uint64(1)
+		for _, child := range c.Children {
+			if child.Name != "Rows" {
+				return nil, errors.Errorf("'%s' is not a valid child query for GroupBy, must be 'Rows'", child.Name)
+			}
+			rows, err := e.executeRows(ctx, index, child, shards, opt)
+			if err != nil {
+				return nil, err
+			}
+			n *= uint64(len(rows))
+		}
+		limit, _, err := c.UintArg("limit")
+		if err != nil {
+			return nil, errors.Wrap(err, "GroupBy()")
+		}
+		return &QueryEstimate{Candidates: n, Length: minLimit(n, limit)}, nil
+
+	case "Options":
+		return nil, NewBadRequestError(errors.New("Options() cannot be estimated"))
+
+	default:
+		idx, err := e.Holder.AcquireIndex(index)
+		if err != nil {
+			return nil, err
+		}
+		defer idx.Close()
+		n, err := e.executeCount(ctx, index, &pql.Call{Name: "Count", Children: []*pql.Call{c}}, shards, opt)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryEstimate{Columns: n, Candidates: n, Length: n, Bytes: estimateRowBytes(n, shards, idx.ShardWidth())}, nil
+	}
+}
+
+// estimateRows returns the number of rows of a field which have a bit.
+func (e *executor) estimateRows(ctx context.Context, index, fieldName string, shards []uint64, opt *execOptions) (uint64, error) {
+	if fieldName == "" {
+		return 0, errors.New("field required")
+	}
+	rows, err := e.executeRows(ctx, index, &pql.Call{Name: "Rows", Args: map[string]interface{}{"_field": fieldName}}, shards, opt)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(len(rows)), nil
+}
+
+// minLimit returns n, or limit if it is set and smaller.
+func minLimit(n, limit uint64) uint64 {
+	if limit > 0 && limit < n {
+		return limit
+	}
+	return n
+}
+
+// estimateRowBytes returns the approximate size of a row result of n columns
+// in the given shards of width shardWidth in each encoding. The columns are
+// assumed to be as large as the largest column of the shards, and spread over
+// as many roaring containers as possible.
+func estimateRowBytes(n uint64, shards []uint64, shardWidth uint64) map[string]uint64 {
+	var maxShard uint64
+	for _, shard := range shards {
+		if shard > maxShard {
+			maxShard = shard
+		}
+	}
+	maxColumn := (maxShard+1)*shardWidth - 1
+
+	// A JSON array of decimal numbers separated by commas.
+	jsonBytes := 2 + n*uint64(len(strconv.FormatUint(maxColumn, 10))+1)
+	if n > 0 {
+		jsonBytes--
+	}
+
+	// Packed varints.
+	protobufBytes := n * uint64((bits.Len64(maxColumn)+6)/7)
+
+	// A header, and a key and offset for each container, which are array
+	// containers of two bytes per column unless they are full enough to be
+	// bitmap containers.
+	containers := uint64(len(shards)) * (shardWidth >> 16)
+	if n < containers {
+		containers = n
+	}
+	data := 2 * n
+	if max := containers * 8192; data > max {
+		data = max
+	}
+	roaringBytes := 8 + 16*containers + data
+
+	return map[string]uint64{
+		"json":     jsonBytes,
+		"protobuf": protobufBytes,
+		"roaring":  roaringBytes,
+	}
+}
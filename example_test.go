@@ -0,0 +1,96 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/pilosa/pilosa/v2"
+)
+
+// Pilosa can be embedded in a program as a library, which executes queries
+// on a local holder without running a server.
+func ExampleExecutor() {
+	path, err := ioutil.TempDir("", "pilosa-example-")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	// Open a holder, which stores the indexes in a directory.
+	holder := pilosa.NewHolder()
+	holder.Path = path
+	if err := holder.Open(); err != nil {
+		log.Fatal(err)
+	}
+	defer holder.Close()
+
+	// Create an index and a field.
+	index, err := holder.CreateIndex("repository", pilosa.IndexOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := index.CreateField("stargazer"); err != nil {
+		log.Fatal(err)
+	}
+
+	// Set bits and query them.
+	executor, err := pilosa.NewExecutor(holder, pilosa.NewLocalCluster(), pilosa.OptExecutorMaxWritesPerRequest(100))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer executor.Close()
+
+	ctx := context.Background()
+	results, err := executor.Execute(ctx, "repository", `
+		Set(1, stargazer=10)
+		Set(2, stargazer=10)
+		Set(3000000, stargazer=10)
+		Set(2, stargazer=11)
+		Set(2, stargazer=11)
+	`)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range results[3:] {
+		if changed, ok := r.Changed(); ok {
+			fmt.Println("changed:", changed)
+		}
+	}
+
+	results, err = executor.Execute(ctx, "repository", `
+		Row(stargazer=10)
+		Count(Intersect(Row(stargazer=10), Row(stargazer=11)))
+	`)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if row, ok := results[0].Row(); ok {
+		fmt.Println("columns:", row.Columns())
+	}
+	if n, ok := results[1].Count(); ok {
+		fmt.Println("count:", n)
+	}
+
+	// Output:
+	// changed: true
+	// changed: false
+	// columns: [1 2 3000000]
+	// count: 1
+}
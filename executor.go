@@ -19,11 +19,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pilosa/pilosa/v2/pql"
-	"github.com/pilosa/pilosa/v2/shardwidth"
+	"github.com/pilosa/pilosa/v2/stats"
 	"github.com/pilosa/pilosa/v2/tracing"
 	"github.com/pkg/errors"
 )
@@ -36,12 +38,29 @@ const (
 	// looking for additional id/count pairs.
 	defaultMinThreshold = 1
 
+	// defaultTopNCacheWait is the default duration for which TopN() waits
+	// for the background recalculation of a cache.
+	defaultTopNCacheWait = time.Second
+
+	// lowPriorityPollInterval is the time between two checks by a
+	// low-priority query of whether other queries are executing.
+	lowPriorityPollInterval = 10 * time.Millisecond
+
 	columnLabel = "col"
 	rowLabel    = "row"
 )
 
 // executor recursively executes calls in a PQL query across all shards.
 type executor struct {
+	// Maximum number of Set() or Clear() commands per request. It can be
+	// changed while queries execute, so it is accessed atomically. It is the
+	// first field so that it is 64-bit aligned.
+	maxWritesPerRequest int64
+
+	// Number of queries executing, not counting the low-priority queries,
+	// which wait for it to be zero. Accessed atomically.
+	activeQueries int64
+
 	Holder *Holder
 
 	// Local hostname & cluster configuration.
@@ -51,41 +70,173 @@ type executor struct {
 	// Client used for remote requests.
 	client InternalQueryClient
 
-	// Maximum number of Set() or Clear() commands per request.
-	MaxWritesPerRequest int
+	// Transactional writes staged on this node, and the gate which keeps
+	// queries from observing a partially committed transaction.
+	txMu   sync.Mutex
+	txs    map[string]*transaction
+	txGate txGate
+
+	// Operation IDs of committed transactional writes. Transactions are
+	// atomic, so they are deduplicated per node rather than per fragment.
+	txOpIDs *operationIDs
 
 	workersWG      sync.WaitGroup
 	workerPoolSize int
 	work           chan job
+
+	// Query results stored by handle for later queries in a session.
+	results *resultStore
+
+	// Memory of the snapshots of snapshot reads, and the gate which keeps
+	// writes from being applied while a snapshot is taken. Writes enter the
+	// gate, and taking a snapshot locks it.
+	snapshots    *readSnapshots
+	snapshotGate txGate
+
+	// Audit log of the write calls of queries received from clients.
+	audit *auditLog
+
+	// How long TopN() waits for the background recalculation of a cache
+	// after an import before recalculating it.
+	topNCacheWait time.Duration
+
+	// Rejects queries while the node is under memory pressure.
+	admission *admissionController
+
+	// Verifies a sample of the shard reads of queries against a second
+	// replica.
+	verifier *readVerifier
+
+	// Statistics of the queries. If nil, the statistics client of the
+	// holder is used.
+	stats stats.StatsClient
+}
+
+// statsClient returns the client which receives the statistics of queries.
+func (e *executor) statsClient() stats.StatsClient {
+	if e.stats != nil {
+		return e.stats
+	} else if e.Holder != nil {
+		return e.Holder.Stats
+	}
+	return stats.NopStatsClient
+}
+
+// MaxWritesPerRequest returns the maximum number of Set() or Clear() commands
+// per request. Zero means there is no maximum.
+func (e *executor) MaxWritesPerRequest() int {
+	return int(atomic.LoadInt64(&e.maxWritesPerRequest))
+}
+
+// setMaxWritesPerRequest sets the maximum number of Set() or Clear() commands
+// per request.
+func (e *executor) setMaxWritesPerRequest(n int) {
+	atomic.StoreInt64(&e.maxWritesPerRequest, int64(n))
 }
 
-// executorOption is a functional option type for pilosa.Executor
-type executorOption func(e *executor) error
+// ExecutorOption is a functional option type for pilosa.Executor
+type ExecutorOption func(e *executor) error
 
-func optExecutorInternalQueryClient(c InternalQueryClient) executorOption {
+// OptExecutorInternalQueryClient is a functional option on Executor used to
+// set the client which executes the calls of queries on other nodes.
+func OptExecutorInternalQueryClient(c InternalQueryClient) ExecutorOption {
 	return func(e *executor) error {
 		e.client = c
 		return nil
 	}
 }
 
-func optExecutorWorkerPoolSize(size int) executorOption {
+// OptExecutorWorkerPoolSize is a functional option on Executor used to set
+// the number of goroutines which execute the calls of queries on shards.
+func OptExecutorWorkerPoolSize(size int) ExecutorOption {
 	return func(e *executor) error {
+		if size <= 0 {
+			return errors.Errorf("invalid worker pool size: %d", size)
+		}
 		e.workerPoolSize = size
 		return nil
 	}
 }
 
+// OptExecutorMaxWritesPerRequest is a functional option on Executor used to
+// set the maximum number of Set() or Clear() commands per query. Zero means
+// there is no maximum.
+func OptExecutorMaxWritesPerRequest(n int) ExecutorOption {
+	return func(e *executor) error {
+		if n < 0 {
+			return errors.Errorf("invalid maximum writes per request: %d", n)
+		}
+		e.setMaxWritesPerRequest(n)
+		return nil
+	}
+}
+
+// OptExecutorStats is a functional option on Executor used to set the client
+// which receives the statistics of queries. Without it, the statistics go to
+// the current statistics client of the holder.
+func OptExecutorStats(c stats.StatsClient) ExecutorOption {
+	return func(e *executor) error {
+		e.stats = c
+		return nil
+	}
+}
+
+func optExecutorResultStore(ttl time.Duration, maxMemory int64) ExecutorOption {
+	return func(e *executor) error {
+		e.results = newResultStore(ttl, maxMemory)
+		return nil
+	}
+}
+
+func optExecutorSnapshotReads(opt SnapshotReadOptions) ExecutorOption {
+	return func(e *executor) error {
+		e.snapshots = newReadSnapshots(opt)
+		return nil
+	}
+}
+
+func optExecutorTopNCacheWait(d time.Duration) ExecutorOption {
+	return func(e *executor) error {
+		e.topNCacheWait = d
+		return nil
+	}
+}
+
+func optExecutorReadVerification(opt ReadVerifyOptions) ExecutorOption {
+	return func(e *executor) error {
+		e.verifier = newReadVerifier(opt)
+		return nil
+	}
+}
+
 // newExecutor returns a new instance of Executor.
-func newExecutor(opts ...executorOption) *executor {
+func newExecutor(opts ...ExecutorOption) *executor {
+	e, err := newExecutorWithOptions(opts)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// newExecutorWithOptions returns a new instance of Executor, or an error if
+// an option fails.
+func newExecutorWithOptions(opts []ExecutorOption) (*executor, error) {
 	e := &executor{
 		client:         newNopInternalQueryClient(),
 		workerPoolSize: 2,
+		results:        newResultStore(defaultResultHandleTTL, defaultResultHandleMaxMemory),
+		snapshots: newReadSnapshots(SnapshotReadOptions{
+			MaxMemory:         defaultSnapshotReadMaxMemory,
+			MaxFragmentMemory: defaultSnapshotReadMaxFragmentMemory,
+			Timeout:           defaultSnapshotReadTimeout,
+		}),
+		topNCacheWait: defaultTopNCacheWait,
+		admission:     newAdmissionController(),
+		verifier:      newReadVerifier(ReadVerifyOptions{}),
 	}
 	for _, opt := range opts {
-		err := opt(e)
-		if err != nil {
-			panic(err)
+		if err := opt(e); err != nil {
+			return nil, errors.Wrap(err, "applying option")
 		}
 	}
 	// this channel cap doesn't necessarily have to be the same as
@@ -100,7 +251,141 @@ func newExecutor(opts ...executorOption) *executor {
 			worker(e.work)
 		}()
 	}
-	return e
+	return e, nil
+}
+
+// Executor executes PQL queries on the indexes of a holder. The server uses
+// one to execute the queries it receives, and a program which embeds Pilosa
+// as a library can create its own with NewExecutor.
+type Executor struct {
+	executor *executor
+}
+
+// NewExecutor returns an executor of the queries on the indexes of holder,
+// which must be open, on the nodes of cluster. A nil cluster is the same as
+// NewLocalCluster().
+func NewExecutor(holder *Holder, cluster *Cluster, opts ...ExecutorOption) (*Executor, error) {
+	e, err := newExecutorWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		cluster = NewLocalCluster()
+	}
+	e.Holder = holder
+	e.Cluster = cluster.cluster
+	e.Node = e.Cluster.Node
+	return &Executor{executor: e}, nil
+}
+
+// Cluster is the cluster of nodes on which an Executor executes queries.
+type Cluster struct {
+	cluster *cluster
+}
+
+// NewLocalCluster returns a cluster of the local node only, which owns all
+// shards, so that an executor needs neither a broadcaster nor an HTTP server.
+func NewLocalCluster() *Cluster {
+	return &Cluster{cluster: newLocalCluster()}
+}
+
+// newLocalCluster returns a cluster of a single node, which owns all shards.
+func newLocalCluster() *cluster {
+	c := newCluster()
+	c.Node = &Node{
+		ID:            "local",
+		URI:           *defaultURI(),
+		IsCoordinator: true,
+		State:         nodeStateReady,
+
+		MinProtocolVersion: MinProtocolVersion,
+		MaxProtocolVersion: ProtocolVersion,
+	}
+	c.nodes = []*Node{c.Node}
+	c.Coordinator = c.Node.ID
+	c.Topology = newTopology()
+	c.Static = true
+	c.SetState(ClusterStateNormal)
+	return c
+}
+
+// Execute parses and executes a PQL query on the shards of the index which
+// have data, and returns the result of each call of the query.
+func (x *Executor) Execute(ctx context.Context, index, query string) ([]QueryResult, error) {
+	q, err := pql.NewParser(strings.NewReader(query)).Parse()
+	if err != nil {
+		return nil, NewBadRequestError(errors.Wrap(err, "parsing"))
+	}
+	resp, err := x.executor.Execute(ctx, index, q, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]QueryResult, len(resp.Results))
+	for i, v := range resp.Results {
+		results[i] = QueryResult{value: v}
+	}
+	return results, nil
+}
+
+// Close stops the workers of the executor. It doesn't close the holder.
+func (x *Executor) Close() error {
+	return x.executor.Close()
+}
+
+// QueryResult is the result of a call of a query executed by an Executor.
+// Each accessor returns the result as the type of the corresponding calls,
+// and false if the call returned another type.
+type QueryResult struct {
+	value interface{}
+}
+
+// Row returns the result of a call returning a row, such as Row() or
+// Intersect().
+func (r QueryResult) Row() (*Row, bool) {
+	v, ok := r.value.(*Row)
+	return v, ok
+}
+
+// Count returns the result of Count().
+func (r QueryResult) Count() (uint64, bool) {
+	v, ok := r.value.(uint64)
+	return v, ok
+}
+
+// Changed returns the result of a write call, such as Set() or Clear(),
+// which is true if the write changed a bit.
+func (r QueryResult) Changed() (bool, bool) {
+	v, ok := r.value.(bool)
+	return v, ok
+}
+
+// Pairs returns the result of TopN().
+func (r QueryResult) Pairs() ([]Pair, bool) {
+	v, ok := r.value.([]Pair)
+	return v, ok
+}
+
+// ValCount returns the result of Sum(), Min() or Max().
+func (r QueryResult) ValCount() (ValCount, bool) {
+	v, ok := r.value.(ValCount)
+	return v, ok
+}
+
+// GroupCounts returns the result of GroupBy().
+func (r QueryResult) GroupCounts() ([]GroupCount, bool) {
+	v, ok := r.value.([]GroupCount)
+	return v, ok
+}
+
+// RowIdentifiers returns the result of Rows().
+func (r QueryResult) RowIdentifiers() (RowIdentifiers, bool) {
+	v, ok := r.value.(RowIdentifiers)
+	return v, ok
+}
+
+// Value returns the result as it is encoded in a QueryResponse.
+func (r QueryResult) Value() interface{} {
+	return r.value
 }
 
 func (e *executor) Close() error {
@@ -121,26 +406,70 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 		return resp, err
 	}
 
+	// Reject the query if the node is using too much memory to run it.
+	if err := e.admission.admit(isExpensiveQuery(q)); err != nil {
+		e.statsClient().Count("admissionRejected", 1, 1.0)
+		return resp, err
+	}
+
 	// Verify that an index is set.
 	if index == "" {
 		return resp, ErrIndexRequired
 	}
 
-	idx := e.Holder.Index(index)
-	if idx == nil {
-		return resp, ErrIndexNotFound
+	// Keep the index from being closed by its deletion while the query runs.
+	handle, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return resp, err
 	}
+	defer handle.Close()
+	idx := handle.Index
 
 	// Verify that the number of writes do not exceed the maximum.
-	if e.MaxWritesPerRequest > 0 && q.WriteCallN() > e.MaxWritesPerRequest {
+	writeN := q.WriteCallN()
+	if max := e.MaxWritesPerRequest(); max > 0 && writeN > max {
 		return resp, ErrTooManyWrites
+	} else if writeN > 0 && idx.ReadOnly() {
+		return resp, ErrIndexReadOnly
+	} else if writeN > 0 && idx.QuotaExceeded() && addsData(q) {
+		return resp, ErrQuotaExceeded
 	}
 
+	e.txGate.enter()
+	defer e.txGate.exit()
+
 	// Default options.
 	if opt == nil {
 		opt = &execOptions{}
 	}
 
+	// Low-priority queries yield to the other queries.
+	if opt.LowPriority {
+		if err := e.waitIdle(ctx); err != nil {
+			return resp, err
+		}
+	} else {
+		atomic.AddInt64(&e.activeQueries, 1)
+		defer atomic.AddInt64(&e.activeQueries, -1)
+	}
+
+	if opt.MaxStaleness > 0 {
+		if writeN > 0 {
+			return resp, NewBadRequestError(errors.New("stale reads cannot write"))
+		}
+		o := *opt
+		o.stale = newStaleShards()
+		opt = &o
+	}
+
+	if opt.Estimate {
+		if writeN > 0 {
+			return resp, NewBadRequestError(errors.New("estimates cannot write"))
+		} else if opt.StoreAs != "" {
+			return resp, NewBadRequestError(errors.New("estimates cannot be stored"))
+		}
+	}
+
 	// Translate query keys to ids, if necessary.
 	// No need to translate a remote call.
 	if !opt.Remote {
@@ -151,14 +480,99 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 		}
 	}
 
+	// Snapshot reads take a snapshot of the shards of this node before
+	// executing, so that they don't observe writes applied meanwhile. Writes
+	// can't be part of a snapshot read, and are kept out of snapshots taken
+	// while they are being applied. The snapshot is taken before the shards
+	// are resolved: a shard created afterwards is empty in the snapshot.
+	if !opt.AsOf.IsZero() {
+		if writeN > 0 {
+			return resp, NewBadRequestError(errors.New("asOf reads cannot write"))
+		} else if opt.Snapshot {
+			return resp, NewBadRequestError(errors.New("asOf reads cannot be snapshot reads"))
+		}
+		ctx = withQueryAsOf(ctx, opt.AsOf)
+	} else if opt.Snapshot {
+		if writeN > 0 {
+			return resp, NewBadRequestError(errors.New("snapshot reads cannot write"))
+		}
+		if !e.snapshotGate.lock(e.snapshots.timeout) {
+			return resp, ErrSnapshotTimeout
+		}
+		snap, err := e.snapshots.acquire(idx, shards)
+		e.snapshotGate.unlock()
+		if err != nil {
+			return resp, err
+		}
+		defer snap.release()
+		ctx = withQuerySnapshot(ctx, snap)
+	} else if writeN > 0 {
+		e.snapshotGate.enter()
+		defer e.snapshotGate.exit()
+	}
+
+	// Resolve the shards up front so that the effective set can be
+	// returned to the client. Remote calls are given their shards by the
+	// coordinating node.
+	if !opt.Remote && needsShards(q.Calls) {
+		var err error
+		if shards, err = e.queryShards(idx, shards); err != nil {
+			return resp, err
+		}
+		resp.Shards = shards
+	}
+
+	// Evaluate Handle() calls against the stored results of the session, and
+	// store the result of the query if requested.
+	if err := validateStoreAs(q, opt); err != nil {
+		return resp, err
+	} else if !opt.Remote {
+		if err := e.results.checkHandles(index, q.Calls, opt); err != nil {
+			return resp, err
+		}
+
+		// Rewrite the calls into cheaper equivalents. Remote calls have
+		// already been optimized by the coordinating node.
+		validate := func(c *pql.Call) error { return e.validateBitmapCall(index, c) }
+		if err := optimizeQuery(q, validate); err != nil {
+			return resp, err
+		}
+	}
+	if opt.Session != "" {
+		ctx = withQuerySession(ctx, opt.Session)
+	}
+	if opt.StoreAs != "" {
+		o := *opt
+		o.stored = e.results.begin(opt.Session, opt.StoreAs, index)
+		opt = &o
+	}
+
 	results, err := e.execute(ctx, index, q, shards, opt)
+	if err == nil {
+		err = validateQueryContext(ctx)
+	}
+	if writeN > 0 && !opt.Remote {
+		rec := &AuditRecord{Operation: "query", Index: index, Calls: q.WriteCalls(), Count: writeN}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		e.audit.log(ctx, rec)
+	}
+	if opt.stored != nil {
+		if err != nil {
+			e.results.discard(opt.stored)
+		} else {
+			e.results.commit(opt.stored)
+		}
+	}
 	if err != nil {
 		return resp, err
-	} else if err := validateQueryContext(ctx); err != nil {
-		return resp, err
 	}
 
 	resp.Results = results
+	if opt.stale != nil {
+		resp.StaleShards = opt.stale.slice()
+	}
 
 	// Fill column attributes if requested.
 	if opt.ColumnAttrs {
@@ -173,7 +587,7 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 		}
 
 		// Retrieve column attributes across all calls.
-		columnAttrSets, err := e.readColumnAttrSets(e.Holder.Index(index), columnIDs)
+		columnAttrSets, err := e.readColumnAttrSets(idx, columnIDs)
 		if err != nil {
 			return resp, errors.Wrap(err, "reading column attrs")
 		}
@@ -205,6 +619,57 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 	return resp, nil
 }
 
+// addsData returns true if q has write calls other than clears. Clears are
+// allowed on an index which is over its disk quota, so that space can be
+// freed.
+func addsData(q *pql.Query) bool {
+	for name := range q.WriteCalls() {
+		switch name {
+		case "Clear", "ClearRow", "DeleteColumn":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// queryShards returns the shards to query on idx. Requested shards must not
+// exceed the max shard of the index. If no shards are requested, all shards
+// are returned, limited to the shard window of the index. The returned
+// shards are sorted.
+func (e *executor) queryShards(idx *Index, shards []uint64) ([]uint64, error) {
+	available := idx.AvailableShards()
+	maxShard := available.Max()
+
+	if len(shards) > 0 {
+		// Sort and remove duplicates, which would otherwise be counted twice.
+		shards = append([]uint64(nil), shards...)
+		sort.Sort(uint64Slice(shards))
+		n := 1
+		for _, shard := range shards[1:] {
+			if shard != shards[n-1] {
+				shards[n] = shard
+				n++
+			}
+		}
+		shards = shards[:n]
+		if shards[len(shards)-1] > maxShard {
+			return nil, errors.Wrapf(ErrShardOutOfRange, "shard %d exceeds max shard %d", shards[len(shards)-1], maxShard)
+		}
+		return shards, nil
+	}
+
+	shards = available.Slice()
+	if window := idx.ShardWindow(); window > 0 && maxShard >= window {
+		i := sort.Search(len(shards), func(i int) bool { return shards[i] > maxShard-window })
+		shards = shards[i:]
+	}
+	if len(shards) == 0 {
+		shards = []uint64{0}
+	}
+	return shards, nil
+}
+
 // readColumnAttrSets returns a list of column attribute objects by id.
 func (e *executor) readColumnAttrSets(index *Index, ids []uint64) ([]*ColumnAttrSet, error) {
 	if index == nil {
@@ -239,10 +704,11 @@ func (e *executor) execute(ctx context.Context, index string, q *pql.Query, shar
 	// specified, then include all of them.
 	if len(shards) == 0 && needsShards {
 		// Round up the number of shards.
-		idx := e.Holder.Index(index)
-		if idx == nil {
-			return nil, ErrIndexNotFound
+		idx, err := e.Holder.AcquireIndex(index)
+		if err != nil {
+			return nil, err
 		}
+		defer idx.Close()
 		shards = idx.AvailableShards().Slice()
 		if len(shards) == 0 {
 			shards = []uint64{0}
@@ -261,7 +727,13 @@ func (e *executor) execute(ctx context.Context, index string, q *pql.Query, shar
 			return nil, err
 		}
 
-		v, err := e.executeCall(ctx, index, call, shards, opt)
+		var v interface{}
+		var err error
+		if opt.Estimate && !opt.Remote {
+			v, err = e.executeEstimate(ctx, index, call, shards, opt)
+		} else {
+			v, err = e.executeCall(ctx, index, call, shards, opt)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -289,22 +761,28 @@ func (e *executor) executeCall(ctx context.Context, index string, c *pql.Call, s
 		e.Holder.Logger.Printf("DEPRECATED: Range() is deprecated, please use Row() instead.")
 	}
 
+	// Sampled calls are executed on some shards and extrapolated.
+	if sample, _ := sampleCall(c); sample != nil {
+		e.statsClient().CountWithCustomTags(sample.Name, 1, 1.0, []string{indexTag})
+		return e.executeSample(ctx, index, c, shards, opt)
+	}
+
 	// Special handling for mutation and top-n calls.
 	switch c.Name {
 	case "Sum":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeSum(ctx, index, c, shards, opt)
 	case "Min":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeMin(ctx, index, c, shards, opt)
 	case "Max":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeMax(ctx, index, c, shards, opt)
 	case "MinRow":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeMinRow(ctx, index, c, shards, opt)
 	case "MaxRow":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeMaxRow(ctx, index, c, shards, opt)
 	case "Clear":
 		return e.executeClearBit(ctx, index, c, opt)
@@ -313,27 +791,31 @@ func (e *executor) executeCall(ctx context.Context, index string, c *pql.Call, s
 	case "Store":
 		return e.executeSetRow(ctx, index, c, shards, opt)
 	case "Count":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeCount(ctx, index, c, shards, opt)
 	case "Set":
 		return e.executeSet(ctx, index, c, opt)
+	case "IncrementFieldValue":
+		return e.executeIncrementFieldValue(ctx, index, c, opt)
+	case "DeleteColumn":
+		return e.executeDeleteColumn(ctx, index, c, opt)
 	case "SetRowAttrs":
 		return nil, e.executeSetRowAttrs(ctx, index, c, opt)
 	case "SetColumnAttrs":
 		return nil, e.executeSetColumnAttrs(ctx, index, c, opt)
 	case "TopN":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeTopN(ctx, index, c, shards, opt)
 	case "Rows":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeRows(ctx, index, c, shards, opt)
 	case "GroupBy":
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeGroupBy(ctx, index, c, shards, opt)
 	case "Options":
 		return e.executeOptionsCall(ctx, index, c, shards, opt)
 	default:
-		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		e.statsClient().CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeBitmapCall(ctx, index, c, shards, opt)
 	}
 }
@@ -407,7 +889,8 @@ func (e *executor) executeSum(ctx context.Context, index string, c *pql.Call, sh
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSum")
 	defer span.Finish()
 
-	if field := c.Args["field"]; field == "" {
+	fieldName, _ := c.Args["field"].(string)
+	if fieldName == "" {
 		return ValCount{}, errors.New("Sum(): field required")
 	}
 
@@ -435,6 +918,7 @@ func (e *executor) executeSum(ctx context.Context, index string, c *pql.Call, sh
 	if other.Count == 0 {
 		return ValCount{}, nil
 	}
+	other.Scale = e.fieldScale(index, fieldName)
 	return other, nil
 }
 
@@ -443,7 +927,8 @@ func (e *executor) executeMin(ctx context.Context, index string, c *pql.Call, sh
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeMin")
 	defer span.Finish()
 
-	if field := c.Args["field"]; field == "" {
+	fieldName, _ := c.Args["field"].(string)
+	if fieldName == "" {
 		return ValCount{}, errors.New("Min(): field required")
 	}
 
@@ -471,6 +956,7 @@ func (e *executor) executeMin(ctx context.Context, index string, c *pql.Call, sh
 	if other.Count == 0 {
 		return ValCount{}, nil
 	}
+	other.Scale = e.fieldScale(index, fieldName)
 	return other, nil
 }
 
@@ -479,7 +965,8 @@ func (e *executor) executeMax(ctx context.Context, index string, c *pql.Call, sh
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeMax")
 	defer span.Finish()
 
-	if field := c.Args["field"]; field == "" {
+	fieldName, _ := c.Args["field"].(string)
+	if fieldName == "" {
 		return ValCount{}, errors.New("Max(): field required")
 	}
 
@@ -507,6 +994,7 @@ func (e *executor) executeMax(ctx context.Context, index string, c *pql.Call, sh
 	if other.Count == 0 {
 		return ValCount{}, nil
 	}
+	other.Scale = e.fieldScale(index, fieldName)
 	return other, nil
 }
 
@@ -583,9 +1071,14 @@ func (e *executor) executeBitmapCall(ctx context.Context, index string, c *pql.C
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeBitmapCall")
 	defer span.Finish()
 
-	// Execute calls in bulk on each remote node and merge.
+	// Execute calls in bulk on each remote node and merge. Results being
+	// stored are retained on the node which computed them.
 	mapFn := func(shard uint64) (interface{}, error) {
-		return e.executeBitmapCallShard(ctx, index, c, shard)
+		row, err := e.executeBitmapCallShard(ctx, index, c, shard)
+		if err == nil && opt.stored != nil {
+			err = e.results.store(opt.stored, shard, row)
+		}
+		return row, err
 	}
 
 	// Merge returned results at coordinating node.
@@ -611,8 +1104,8 @@ func (e *executor) executeBitmapCall(ctx context.Context, index string, c *pql.C
 		if opt.ExcludeRowAttrs {
 			row.Attrs = map[string]interface{}{}
 		} else {
-			idx := e.Holder.Index(index)
-			if idx != nil {
+			if idx, err := e.Holder.AcquireIndex(index); err == nil {
+				defer idx.Close()
 				if columnID, ok, err := c.UintArg("_" + columnLabel); ok && err == nil {
 					attrs, err := idx.ColumnAttrStore().Attrs(columnID)
 					if err != nil {
@@ -624,7 +1117,7 @@ func (e *executor) executeBitmapCall(ctx context.Context, index string, c *pql.C
 				} else {
 					// field, _ := c.Args["field"].(string)
 					fieldName, _ := c.FieldArg()
-					if fr := idx.Field(fieldName); fr != nil {
+					if fr := idx.Field(fieldName); fr != nil && !c.IsListArg(fieldName) {
 						rowID, _, err := c.UintArg(fieldName)
 						if err != nil {
 							return nil, errors.Wrap(err, "getting row")
@@ -671,11 +1164,33 @@ func (e *executor) executeBitmapCallShard(ctx context.Context, index string, c *
 		return e.executeNotShard(ctx, index, c, shard)
 	case "Shift":
 		return e.executeShiftShard(ctx, index, c, shard)
+	case "Handle":
+		return e.executeHandleShard(ctx, index, c, shard)
 	default:
 		return nil, fmt.Errorf("unknown call: %s", c.Name)
 	}
 }
 
+// fragment returns the fragment read by a query: its retained snapshot if the
+// query reads as of a time, its snapshot if the query reads a snapshot, or the
+// live fragment.
+func (e *executor) fragment(ctx context.Context, index, field, view string, shard uint64) *fragment {
+	if asOf, ok := queryAsOf(ctx); ok {
+		return e.Holder.retainedFragment(index, field, view, shard, asOf)
+	}
+	return querySnapshot(ctx).fragment(e.Holder, index, field, view, shard)
+}
+
+// executeHandleShard returns the stored result referenced by a Handle() call
+// for a single shard.
+func (e *executor) executeHandleShard(ctx context.Context, index string, c *pql.Call, shard uint64) (*Row, error) {
+	name := callArgString(c, "name")
+	if name == "" {
+		return nil, errors.New("Handle() argument required: name")
+	}
+	return e.results.row(querySession(ctx), name, index, shard)
+}
+
 // executeSumCountShard calculates the sum and count for bsiGroups on a shard.
 func (e *executor) executeSumCountShard(ctx context.Context, index string, c *pql.Call, shard uint64) (ValCount, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSumCountShard")
@@ -702,7 +1217,7 @@ func (e *executor) executeSumCountShard(ctx context.Context, index string, c *pq
 		return ValCount{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+	fragment := e.fragment(ctx, index, fieldName, viewBSIGroupPrefix+fieldName, shard)
 	if fragment == nil {
 		return ValCount{}, nil
 	}
@@ -743,7 +1258,7 @@ func (e *executor) executeMinShard(ctx context.Context, index string, c *pql.Cal
 		return ValCount{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+	fragment := e.fragment(ctx, index, fieldName, viewBSIGroupPrefix+fieldName, shard)
 	if fragment == nil {
 		return ValCount{}, nil
 	}
@@ -781,7 +1296,7 @@ func (e *executor) executeMaxShard(ctx context.Context, index string, c *pql.Cal
 		return ValCount{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+	fragment := e.fragment(ctx, index, fieldName, viewBSIGroupPrefix+fieldName, shard)
 	if fragment == nil {
 		return ValCount{}, nil
 	}
@@ -813,7 +1328,7 @@ func (e *executor) executeMinRowShard(ctx context.Context, index string, c *pql.
 		return Pair{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewStandard, shard)
+	fragment := e.fragment(ctx, index, fieldName, viewStandard, shard)
 	if fragment == nil {
 		return Pair{}, nil
 	}
@@ -842,7 +1357,7 @@ func (e *executor) executeMaxRowShard(ctx context.Context, index string, c *pql.
 		return Pair{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewStandard, shard)
+	fragment := e.fragment(ctx, index, fieldName, viewStandard, shard)
 	if fragment == nil {
 		return Pair{}, nil
 	}
@@ -971,7 +1486,7 @@ func (e *executor) executeTopNShard(ctx context.Context, index string, c *pql.Ca
 		fieldName = defaultField
 	}
 
-	f := e.Holder.fragment(index, fieldName, viewStandard, shard)
+	f := e.fragment(ctx, index, fieldName, viewStandard, shard)
 	if f == nil {
 		return nil, nil
 	} else if f.CacheType == CacheTypeNone {
@@ -993,6 +1508,7 @@ func (e *executor) executeTopNShard(ctx context.Context, index string, c *pql.Ca
 		FilterValues:      attrValues,
 		MinThreshold:      minThreshold,
 		TanimotoThreshold: tanimotoThreshold,
+		CacheWait:         e.topNCacheWait,
 	})
 }
 
@@ -1243,7 +1759,9 @@ func (e *executor) executeGroupByShard(ctx context.Context, index string, c *pql
 		}
 	}
 
-	iter, err := newGroupByIterator(childRows, c.Children, filterRow, index, shard, e.Holder)
+	iter, err := newGroupByIterator(childRows, c.Children, filterRow, index, shard, e.Holder, func(field string) *fragment {
+		return e.fragment(ctx, index, field, viewStandard, shard)
+	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "getting group by iterator for shard %d", shard)
 	}
@@ -1286,7 +1804,12 @@ func (e *executor) executeRows(ctx context.Context, index string, c *pql.Call, s
 	if columnID, ok, err := c.UintArg("column"); err != nil {
 		return nil, errors.Wrap(err, "getting column")
 	} else if ok {
-		shards = []uint64{columnID / ShardWidth}
+		idx, err := e.Holder.AcquireIndex(index)
+		if err != nil {
+			return nil, err
+		}
+		defer idx.Close()
+		shards = []uint64{columnID / idx.ShardWidth()}
 	}
 
 	// Execute calls in bulk on each remote node and merge.
@@ -1316,12 +1839,13 @@ func (e *executor) executeRows(ctx context.Context, index string, c *pql.Call, s
 	return results, nil
 }
 
-func (e *executor) executeRowsShard(_ context.Context, index string, fieldName string, c *pql.Call, shard uint64) (RowIDs, error) {
+func (e *executor) executeRowsShard(ctx context.Context, index string, fieldName string, c *pql.Call, shard uint64) (RowIDs, error) {
 	// Fetch index.
-	idx := e.Holder.Index(index)
-	if idx == nil {
-		return nil, ErrIndexNotFound
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return nil, err
 	}
+	defer idx.Close()
 	// Fetch field.
 	f := e.Holder.Field(index, fieldName)
 	if f == nil {
@@ -1395,7 +1919,7 @@ func (e *executor) executeRowsShard(_ context.Context, index string, fieldName s
 			}
 
 			// Determine the views based on the specified time range.
-			views = viewsByTimeRange(viewStandard, fromTime, toTime, q)
+			views = f.viewsByTimeRange(fromTime, toTime)
 		}
 	}
 
@@ -1410,11 +1934,11 @@ func (e *executor) executeRowsShard(_ context.Context, index string, fieldName s
 	if columnID, ok, err := c.UintArg("column"); err != nil {
 		return nil, err
 	} else if ok {
-		colShard := columnID >> shardwidth.Exponent
+		colShard := columnID / f.shardWidth
 		if colShard != shard {
 			return rowIDs, nil
 		}
-		filters = append(filters, filterColumn(columnID))
+		filters = append(filters, filterColumn(columnID, f.shardWidth))
 	}
 
 	limit := int(^uint(0) >> 1)
@@ -1426,7 +1950,7 @@ func (e *executor) executeRowsShard(_ context.Context, index string, fieldName s
 	}
 
 	for _, view := range views {
-		frag := e.Holder.fragment(index, fieldName, view, shard)
+		frag := e.fragment(ctx, index, fieldName, view, shard)
 		if frag == nil {
 			continue
 		}
@@ -1448,10 +1972,11 @@ func (e *executor) executeRowShard(ctx context.Context, index string, c *pql.Cal
 	}
 
 	// Fetch column label from index.
-	idx := e.Holder.Index(index)
-	if idx == nil {
-		return nil, ErrIndexNotFound
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return nil, err
 	}
+	defer idx.Close()
 
 	// Fetch field name from argument.
 	fieldName, err := c.FieldArg()
@@ -1463,11 +1988,17 @@ func (e *executor) executeRowShard(ctx context.Context, index string, c *pql.Cal
 		return nil, ErrFieldNotFound
 	}
 
-	rowID, rowOK, rowErr := c.UintArg(fieldName)
-	if rowErr != nil {
-		return nil, fmt.Errorf("Row() error with arg for row: %v", rowErr)
-	} else if !rowOK {
-		return nil, fmt.Errorf("Row() must specify %v", rowLabel)
+	rowIDs, err := rowIDsArg(c, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	// readRow reads the rows of the call from a fragment: the union of a
+	// list of rows is read in a single pass.
+	readRow := func(frag *fragment) *Row {
+		if len(rowIDs) == 1 && !c.IsListArg(fieldName) {
+			return frag.row(rowIDs[0])
+		}
+		return frag.unionRows(rowIDs)
 	}
 
 	// Parse "from" time, if set.
@@ -1488,11 +2019,11 @@ func (e *executor) executeRowShard(ctx context.Context, index string, c *pql.Cal
 
 	// Simply return row if times are not set.
 	if c.Name == "Row" && fromTime.IsZero() && toTime.IsZero() {
-		frag := e.Holder.fragment(index, fieldName, viewStandard, shard)
+		frag := e.fragment(ctx, index, fieldName, viewStandard, shard)
 		if frag == nil {
 			return NewRow(), nil
 		}
-		return frag.row(rowID), nil
+		return readRow(frag), nil
 	}
 
 	// If no quantum exists then return an empty bitmap.
@@ -1509,14 +2040,14 @@ func (e *executor) executeRowShard(ctx context.Context, index string, c *pql.Cal
 	}
 
 	// Union bitmaps across all time-based views.
-	views := viewsByTimeRange(viewStandard, fromTime, toTime, q)
+	views := f.viewsByTimeRange(fromTime, toTime)
 	rows := make([]*Row, 0, len(views))
 	for _, view := range views {
-		f := e.Holder.fragment(index, fieldName, view, shard)
+		f := e.fragment(ctx, index, fieldName, view, shard)
 		if f == nil {
 			continue
 		}
-		rows = append(rows, f.row(rowID))
+		rows = append(rows, readRow(f))
 	}
 	if len(rows) == 0 {
 		return &Row{}, nil
@@ -1524,56 +2055,197 @@ func (e *executor) executeRowShard(ctx context.Context, index string, c *pql.Cal
 		return rows[0], nil
 	}
 	row := rows[0].Union(rows[1:]...)
-	f.Stats.Count("range", 1, 1.0)
+	f.Stats.CountWithCustomTags("range", 1, 1.0, []string{"type:time"})
 	return row, nil
 
 }
 
-// executeRowBSIGroupShard executes a range(bsiGroup) call for a local shard.
-func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c *pql.Call, shard uint64) (*Row, error) {
-	span, _ := tracing.StartSpanFromContext(ctx, "Executor.executeRowBSIGroupShard")
-	defer span.Finish()
-
-	// Only one conditional should be present.
-	if len(c.Args) == 0 {
-		return nil, errors.New("Row(): condition required")
-	} else if len(c.Args) > 1 {
-		return nil, errors.New("Row(): too many arguments")
-	}
-
-	// Extract conditional.
-	var fieldName string
-	var cond *pql.Condition
-	for k, v := range c.Args {
-		vv, ok := v.(*pql.Condition)
-		if !ok {
-			return nil, fmt.Errorf("Row(): %q: expected condition argument, got %v", k, v)
+// rowIDsArg returns the rows of a Row() call of the field fieldName: a single
+// row, as in Row(f=1), or a list of rows, as in Row(f=[1, 2, 3]), which
+// returns their union.
+func rowIDsArg(c *pql.Call, fieldName string) ([]uint64, error) {
+	if c.IsListArg(fieldName) {
+		rowIDs, _, err := c.UintSliceArg(fieldName)
+		if err != nil {
+			return nil, fmt.Errorf("Row() error with arg for rows: %v", err)
 		}
-		fieldName, cond = k, vv
+		return rowIDs, nil
 	}
-
-	f := e.Holder.Field(index, fieldName)
-	if f == nil {
-		return nil, ErrFieldNotFound
+	rowID, rowOK, rowErr := c.UintArg(fieldName)
+	if rowErr != nil {
+		return nil, fmt.Errorf("Row() error with arg for row: %v", rowErr)
+	} else if !rowOK {
+		return nil, fmt.Errorf("Row() must specify %v", rowLabel)
 	}
+	return []uint64{rowID}, nil
+}
 
-	// EQ null           (not implemented: flip frag.NotNull with max ColumnID)
-	// NEQ null          frag.NotNull()
-	// BETWEEN a,b(in)   BETWEEN/frag.RowBetween()
-	// BETWEEN a,b(out)  BETWEEN/frag.NotNull()
-	// EQ <int>          frag.RangeOp
-	// NEQ <int>         frag.RangeOp
-
-	// Handle `!= null`.
-	if cond.Op == pql.NEQ && cond.Value == nil {
-		// Find bsiGroup.
-		bsig := f.bsiGroup(fieldName)
-		if bsig == nil {
-			return nil, ErrBSIGroupNotFound
+// validateBitmapCall returns the error which executing the bitmap call c
+// would return regardless of the data, such as a missing field or an invalid
+// argument, without executing it. Operands which are skipped because the
+// result is known without them are validated with it, so that a query fails
+// the same way whatever the data.
+func (e *executor) validateBitmapCall(index string, c *pql.Call) error {
+	switch c.Name {
+	case "Row", "Range":
+		if c.HasConditionArg() {
+			return e.validateRowBSIGroupCall(index, c)
+		}
+		idx, err := e.Holder.AcquireIndex(index)
+		if err != nil {
+			return err
+		}
+		defer idx.Close()
+		fieldName, err := c.FieldArg()
+		if err != nil {
+			return errors.New("Row() argument required: field")
+		} else if idx.Field(fieldName) == nil {
+			return ErrFieldNotFound
+		} else if _, err := rowIDsArg(c, fieldName); err != nil {
+			return err
+		}
+		for _, k := range []string{"from", "to"} {
+			if v, ok := c.Args[k]; ok {
+				if _, err := parseTime(v); err != nil {
+					return errors.Wrapf(err, "parsing %s time", k)
+				}
+			}
+		}
+	case "Difference", "Intersect":
+		if len(c.Children) == 0 {
+			return fmt.Errorf("empty %s query is currently not supported", c.Name)
+		}
+	case "Union", "Xor":
+	case "Not":
+		if len(c.Children) == 0 {
+			return errors.New("Not() requires an input row")
+		} else if len(c.Children) > 1 {
+			return errors.New("Not() only accepts a single row input")
+		}
+		idx, err := e.Holder.AcquireIndex(index)
+		if err != nil {
+			return err
+		}
+		defer idx.Close()
+		if idx.existenceField() == nil {
+			return errors.Errorf("index does not support existence tracking: %s", index)
+		}
+	case "Shift":
+		if _, _, err := c.IntArg("n"); err != nil {
+			return fmt.Errorf("executeShiftShard: %v", err)
+		} else if len(c.Children) == 0 {
+			return errors.New("Shift() requires an input row")
+		} else if len(c.Children) > 1 {
+			return errors.New("Shift() only accepts a single row input")
+		}
+	case "Handle":
+		if callArgString(c, "name") == "" {
+			return errors.New("Handle() argument required: name")
+		}
+	default:
+		return fmt.Errorf("unknown call: %s", c.Name)
+	}
+
+	for _, child := range c.Children {
+		if err := e.validateBitmapCall(index, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBitmapCalls validates each of calls with validateBitmapCall.
+func (e *executor) validateBitmapCalls(index string, calls []*pql.Call) error {
+	for _, c := range calls {
+		if err := e.validateBitmapCall(index, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRowBSIGroupCall returns the error which executing the Row() call
+// c of a condition would return regardless of the data.
+func (e *executor) validateRowBSIGroupCall(index string, c *pql.Call) error {
+	if len(c.Args) == 0 {
+		return errors.New("Row(): condition required")
+	} else if len(c.Args) > 1 {
+		return errors.New("Row(): too many arguments")
+	}
+	for fieldName, v := range c.Args {
+		cond, ok := v.(*pql.Condition)
+		if !ok {
+			return fmt.Errorf("Row(): %q: expected condition argument, got %v", fieldName, v)
+		}
+		f := e.Holder.Field(index, fieldName)
+		if f == nil {
+			return ErrFieldNotFound
+		} else if f.bsiGroup(fieldName) == nil {
+			return ErrBSIGroupNotFound
+		}
+		switch {
+		case cond.Op == pql.NEQ && cond.Value == nil:
+		case cond.Op == pql.BETWEEN:
+			predicates, err := scaledPredicates(cond, f.Options().Scale)
+			if err != nil {
+				return errors.Wrap(err, "getting condition value")
+			} else if len(predicates) != 2 {
+				return errors.New("Row(): BETWEEN condition requires exactly two integer values")
+			}
+		default:
+			if _, err := scaledValue(cond.Value, f.Options().Scale); err != nil {
+				return errors.Wrap(err, "Row(): conditions only support numeric values")
+			}
+		}
+	}
+	return nil
+}
+
+// executeRowBSIGroupShard executes a range(bsiGroup) call for a local shard.
+func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c *pql.Call, shard uint64) (*Row, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "Executor.executeRowBSIGroupShard")
+	defer span.Finish()
+
+	// Only one conditional should be present.
+	if len(c.Args) == 0 {
+		return nil, errors.New("Row(): condition required")
+	} else if len(c.Args) > 1 {
+		return nil, errors.New("Row(): too many arguments")
+	}
+
+	// Extract conditional.
+	var fieldName string
+	var cond *pql.Condition
+	for k, v := range c.Args {
+		vv, ok := v.(*pql.Condition)
+		if !ok {
+			return nil, fmt.Errorf("Row(): %q: expected condition argument, got %v", k, v)
+		}
+		fieldName, cond = k, vv
+	}
+
+	f := e.Holder.Field(index, fieldName)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+
+	// EQ null           (not implemented: flip frag.NotNull with max ColumnID)
+	// NEQ null          frag.NotNull()
+	// BETWEEN a,b(in)   BETWEEN/frag.RowBetween()
+	// BETWEEN a,b(out)  BETWEEN/frag.NotNull()
+	// EQ <int>          frag.RangeOp
+	// NEQ <int>         frag.RangeOp
+
+	// Handle `!= null`.
+	if cond.Op == pql.NEQ && cond.Value == nil {
+		// Find bsiGroup.
+		bsig := f.bsiGroup(fieldName)
+		if bsig == nil {
+			return nil, ErrBSIGroupNotFound
 		}
 
 		// Retrieve fragment.
-		frag := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+		frag := e.fragment(ctx, index, fieldName, viewBSIGroupPrefix+fieldName, shard)
 		if frag == nil {
 			return NewRow(), nil
 		}
@@ -1581,7 +2253,7 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 		return frag.notNull()
 
 	} else if cond.Op == pql.BETWEEN {
-		predicates, err := cond.IntSliceValue()
+		predicates, err := scaledPredicates(cond, f.Options().Scale)
 		if err != nil {
 			return nil, errors.Wrap(err, "getting condition value")
 		}
@@ -1607,7 +2279,7 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 		}
 
 		// Retrieve fragment.
-		frag := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+		frag := e.fragment(ctx, index, fieldName, viewBSIGroupPrefix+fieldName, shard)
 		if frag == nil {
 			return NewRow(), nil
 		}
@@ -1622,10 +2294,10 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 
 	} else {
 
-		// Only support integers for now.
-		value, ok := cond.Value.(int64)
-		if !ok {
-			return nil, errors.New("Row(): conditions only support integer values")
+		// Only support numbers, which are decimals on fields with a scale.
+		value, err := scaledValue(cond.Value, f.Options().Scale)
+		if err != nil {
+			return nil, errors.Wrap(err, "Row(): conditions only support numeric values")
 		}
 
 		// Find bsiGroup.
@@ -1640,7 +2312,7 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 		}
 
 		// Retrieve fragment.
-		frag := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+		frag := e.fragment(ctx, index, fieldName, viewBSIGroupPrefix+fieldName, shard)
 		if frag == nil {
 			return NewRow(), nil
 		}
@@ -1656,7 +2328,7 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 			return frag.notNull()
 		}
 
-		f.Stats.Count("range:bsigroup", 1, 1.0)
+		f.Stats.CountWithCustomTags("range", 1, 1.0, []string{"type:bsigroup"})
 		return frag.rangeOp(cond.Op, bsig.BitDepth, baseValue)
 	}
 }
@@ -1666,14 +2338,46 @@ func (e *executor) executeIntersectShard(ctx context.Context, index string, c *p
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeIntersectShard")
 	defer span.Finish()
 
-	var other *Row
 	if len(c.Children) == 0 {
 		return nil, fmt.Errorf("empty Intersect query is currently not supported")
 	}
-	for i, input := range c.Children {
-		row, err := e.executeBitmapCallShard(ctx, index, input, shard)
-		if err != nil {
-			return nil, err
+
+	inputs, hints := e.intersectOperands(ctx, index, c.Children, shard)
+	other, skipped, err := e.foldIntersect(ctx, index, inputs, hints, shard)
+	if err != nil {
+		return nil, err
+	}
+	e.operandsSkipped(span, c.Name, skipped)
+	other.invalidateCount()
+	return other, nil
+}
+
+// intersectOperands returns the operands of an Intersect call in the order
+// they are evaluated in a shard: the smallest rows first, and operands of
+// unknown size last.
+func (e *executor) intersectOperands(ctx context.Context, index string, inputs []*pql.Call, shard uint64) ([]*pql.Call, map[*pql.Call]operandHint) {
+	inputs, hints := e.operandHints(ctx, index, inputs, shard)
+	sort.SliceStable(inputs, func(i, j int) bool { return hints[inputs[i]].less(hints[inputs[j]]) })
+	return inputs, hints
+}
+
+// foldIntersect returns the intersection of operands in a shard, which are
+// evaluated in order until the intersection is empty, and the number of
+// operands which were skipped.
+func (e *executor) foldIntersect(ctx context.Context, index string, inputs []*pql.Call, hints map[*pql.Call]operandHint, shard uint64) (*Row, int, error) {
+	var other *Row
+	for i, input := range inputs {
+		var row *Row
+		if h := hints[input]; h.known && h.n == 0 {
+			if err := e.validateBitmapCall(index, input); err != nil {
+				return nil, 0, err
+			}
+			row = NewRow()
+		} else {
+			var err error
+			if row, err = e.executeBitmapCallShard(ctx, index, input, shard); err != nil {
+				return nil, 0, err
+			}
 		}
 
 		if i == 0 {
@@ -1681,9 +2385,68 @@ func (e *executor) executeIntersectShard(ctx context.Context, index string, c *p
 		} else {
 			other = other.Intersect(row)
 		}
+		if !other.Any() {
+			if err := e.validateBitmapCalls(index, inputs[i+1:]); err != nil {
+				return nil, 0, err
+			}
+			return other, len(inputs) - i - 1, nil
+		}
 	}
-	other.invalidateCount()
-	return other, nil
+	return other, 0, nil
+}
+
+// operandsSkipped records the number of operands of a set operation which
+// were skipped in a shard.
+func (e *executor) operandsSkipped(span tracing.Span, name string, skipped int) {
+	if skipped == 0 {
+		return
+	}
+	span.LogKV("operandsSkipped", skipped)
+	e.statsClient().CountWithCustomTags("operandsSkipped", int64(skipped), 1.0, []string{"call:" + name})
+}
+
+// operandHint is the number of columns of an operand of a set operation in
+// a shard, if it is known without reading the operand.
+type operandHint struct {
+	n     uint64
+	known bool
+
+	// full is true if the operand has every column of the shard.
+	full bool
+}
+
+// less returns true if the operand of h is known to be smaller than the
+// operand of other. Operands of unknown size are last.
+func (h operandHint) less(other operandHint) bool {
+	return h.known && (!other.known || h.n < other.n)
+}
+
+// operandHints returns a copy of the operands of a set operation, and the
+// sizes of those which are rows of the standard view, which are counted
+// from the containers of the row without reading it.
+func (e *executor) operandHints(ctx context.Context, index string, inputs []*pql.Call, shard uint64) ([]*pql.Call, map[*pql.Call]operandHint) {
+	hints := make(map[*pql.Call]operandHint, len(inputs))
+	for _, input := range inputs {
+		if input.Name != "Row" || input.HasConditionArg() || input.Args["from"] != nil || input.Args["to"] != nil {
+			continue
+		}
+		fieldName, err := input.FieldArg()
+		if err != nil {
+			continue
+		}
+		rowID, ok, err := input.UintArg(fieldName)
+		if err != nil || !ok || e.Holder.Field(index, fieldName) == nil {
+			continue
+		}
+		frag := e.fragment(ctx, index, fieldName, viewStandard, shard)
+		if frag == nil {
+			hints[input] = operandHint{known: true}
+			continue
+		}
+		n := frag.rowCount(rowID)
+		hints[input] = operandHint{n: n, known: true, full: n == frag.shardWidth}
+	}
+	return append([]*pql.Call(nil), inputs...), hints
 }
 
 // executeUnionShard executes a union() call for a local shard.
@@ -1691,8 +2454,21 @@ func (e *executor) executeUnionShard(ctx context.Context, index string, c *pql.C
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeUnionShard")
 	defer span.Finish()
 
+	// A row which has every column of the shard is the union, so the other
+	// operands are skipped.
+	inputs, hints := e.operandHints(ctx, index, c.Children, shard)
+	for _, input := range inputs {
+		if hints[input].full {
+			if err := e.validateBitmapCalls(index, inputs); err != nil {
+				return nil, err
+			}
+			e.operandsSkipped(span, c.Name, len(inputs)-1)
+			return e.executeBitmapCallShard(ctx, index, input, shard)
+		}
+	}
+
 	other := NewRow()
-	for i, input := range c.Children {
+	for i, input := range inputs {
 		row, err := e.executeBitmapCallShard(ctx, index, input, shard)
 		if err != nil {
 			return nil, err
@@ -1742,15 +2518,17 @@ func (e *executor) executeNotShard(ctx context.Context, index string, c *pql.Cal
 	}
 
 	// Make sure the index supports existence tracking.
-	idx := e.Holder.Index(index)
-	if idx == nil {
-		return nil, ErrIndexNotFound
-	} else if idx.existenceField() == nil {
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	defer idx.Close()
+	if idx.existenceField() == nil {
 		return nil, errors.Errorf("index does not support existence tracking: %s", index)
 	}
 
 	var existenceRow *Row
-	existenceFrag := e.Holder.fragment(index, existenceFieldName, viewStandard, shard)
+	existenceFrag := e.fragment(ctx, index, existenceFieldName, viewStandard, shard)
 	if existenceFrag == nil {
 		existenceRow = NewRow()
 	} else {
@@ -1791,19 +2569,29 @@ func (e *executor) executeCount(ctx context.Context, index string, c *pql.Call,
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeCount")
 	defer span.Finish()
 
-	if len(c.Children) == 0 {
-		return 0, errors.New("Count() requires an input bitmap")
-	} else if len(c.Children) > 1 {
+	if len(c.Children) > 1 {
 		return 0, errors.New("Count() only accepts a single bitmap input")
 	}
 
-	// Execute calls in bulk on each remote node and merge.
-	mapFn := func(shard uint64) (interface{}, error) {
-		row, err := e.executeBitmapCallShard(ctx, index, c.Children[0], shard)
+	// Count() without an input counts the columns of the index, which
+	// requires existence tracking.
+	if len(c.Children) == 0 {
+		idx, err := e.Holder.AcquireIndex(index)
 		if err != nil {
 			return 0, err
 		}
-		return row.Count(), nil
+		defer idx.Close()
+		if idx.existenceField() == nil {
+			return 0, errors.Errorf("Count() requires an input bitmap, or an index which tracks existence: %s", index)
+		}
+	}
+
+	// Execute calls in bulk on each remote node and merge.
+	mapFn := func(shard uint64) (interface{}, error) {
+		if len(c.Children) == 0 {
+			return e.executeCountExistenceShard(ctx, index, shard), nil
+		}
+		return e.executeCountShard(ctx, index, c.Children[0], shard)
 	}
 
 	// Merge returned results at coordinating node.
@@ -1821,6 +2609,97 @@ func (e *executor) executeCount(ctx context.Context, index string, c *pql.Call,
 	return n, nil
 }
 
+// executeCountExistenceShard returns the number of columns which exist in a
+// local shard.
+func (e *executor) executeCountExistenceShard(ctx context.Context, index string, shard uint64) uint64 {
+	frag := e.fragment(ctx, index, existenceFieldName, viewStandard, shard)
+	if frag == nil {
+		return 0
+	}
+	return frag.row(0).Count()
+}
+
+// executeCountShard returns the number of columns in the result of a bitmap
+// call for a local shard. When the call is a Union, Intersect, Difference or
+// Xor, its operands are evaluated as usual but the final operation only
+// counts the result instead of materializing it. Operands of Intersect and
+// Union which can't change the count are skipped.
+func (e *executor) executeCountShard(ctx context.Context, index string, c *pql.Call, shard uint64) (uint64, error) {
+	switch c.Name {
+	case "Union", "Intersect", "Difference", "Xor":
+		if len(c.Children) < 2 {
+			break
+		}
+		span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeCountShard")
+		defer span.Finish()
+
+		switch c.Name {
+		case "Intersect":
+			inputs, hints := e.intersectOperands(ctx, index, c.Children, shard)
+			other, skipped, err := e.foldIntersect(ctx, index, inputs[:len(inputs)-1], hints, shard)
+			if err != nil {
+				return 0, err
+			} else if skipped > 0 || !other.Any() {
+				if err := e.validateBitmapCall(index, inputs[len(inputs)-1]); err != nil {
+					return 0, err
+				}
+				e.operandsSkipped(span, c.Name, skipped+1)
+				return 0, nil
+			}
+			last, err := e.executeBitmapCallShard(ctx, index, inputs[len(inputs)-1], shard)
+			if err != nil {
+				return 0, err
+			}
+			return other.intersectionCount(last), nil
+		case "Union":
+			inputs, hints := e.operandHints(ctx, index, c.Children, shard)
+			for _, input := range inputs {
+				if h := hints[input]; h.full {
+					if err := e.validateBitmapCalls(index, inputs); err != nil {
+						return 0, err
+					}
+					e.operandsSkipped(span, c.Name, len(inputs)-1)
+					return h.n, nil
+				}
+			}
+		}
+
+		rows := make([]*Row, len(c.Children))
+		for i, input := range c.Children {
+			row, err := e.executeBitmapCallShard(ctx, index, input, shard)
+			if err != nil {
+				return 0, err
+			}
+			rows[i] = row
+		}
+
+		// Fold all but the last operand, then count against it.
+		last := rows[len(rows)-1]
+		switch c.Name {
+		case "Union":
+			return rows[0].unionCount(rows[1:]...), nil
+		case "Difference":
+			other := rows[0]
+			for _, row := range rows[1 : len(rows)-1] {
+				other = other.Difference(row)
+			}
+			return other.differenceCount(last), nil
+		case "Xor":
+			other := rows[0]
+			for _, row := range rows[1 : len(rows)-1] {
+				other = other.Xor(row)
+			}
+			return other.xorCount(last), nil
+		}
+	}
+
+	row, err := e.executeBitmapCallShard(ctx, index, c, shard)
+	if err != nil {
+		return 0, err
+	}
+	return row.Count(), nil
+}
+
 // executeClearBit executes a Clear() call.
 func (e *executor) executeClearBit(ctx context.Context, index string, c *pql.Call, opt *execOptions) (bool, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeClearBit")
@@ -1832,10 +2711,11 @@ func (e *executor) executeClearBit(ctx context.Context, index string, c *pql.Cal
 	}
 
 	// Retrieve field.
-	idx := e.Holder.Index(index)
-	if idx == nil {
-		return false, ErrIndexNotFound
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return false, err
 	}
+	defer idx.Close()
 	f := idx.Field(fieldName)
 	if f == nil {
 		return false, ErrFieldNotFound
@@ -1864,7 +2744,7 @@ func (e *executor) executeClearBitField(ctx context.Context, index string, c *pq
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeClearBitField")
 	defer span.Finish()
 
-	shard := colID / ShardWidth
+	shard := colID / f.shardWidth
 	ret := false
 	for _, node := range e.Cluster.shardNodes(index, shard) {
 		// Update locally if host matches.
@@ -1883,7 +2763,7 @@ func (e *executor) executeClearBitField(ctx context.Context, index string, c *pq
 		}
 
 		// Forward call to remote node otherwise.
-		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil)
+		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, opt)
 		if err != nil {
 			return false, err
 		}
@@ -2083,30 +2963,26 @@ func (e *executor) executeSet(ctx context.Context, index string, c *pql.Call, op
 	}
 
 	// Retrieve field.
-	idx := e.Holder.Index(index)
-	if idx == nil {
-		return false, ErrIndexNotFound
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return false, err
 	}
+	defer idx.Close()
 	f := idx.Field(fieldName)
 	if f == nil {
 		return false, ErrFieldNotFound
 	}
 
-	// Set column on existence field.
-	if ef := idx.existenceField(); ef != nil {
-		if _, err := ef.SetBit(0, colID, nil); err != nil {
-			return false, errors.Wrap(err, "setting existence column")
-		}
-	}
-
 	// Int field.
-	if f.Type() == FieldTypeInt {
+	if fo := f.Options(); fo.Type == FieldTypeInt {
 		// Read row value.
-		rowVal, ok, err := c.IntArg(fieldName)
+		arg, ok := c.Args[fieldName]
+		if !ok {
+			return false, fmt.Errorf("Set() row argument '%v' required", rowLabel)
+		}
+		rowVal, err := scaledValue(arg, fo.Scale)
 		if err != nil {
 			return false, fmt.Errorf("reading Set() row: %v", err)
-		} else if !ok {
-			return false, fmt.Errorf("Set() row argument '%v' required", rowLabel)
 		}
 
 		return e.executeSetValueField(ctx, index, c, f, colID, rowVal, opt)
@@ -2133,17 +3009,39 @@ func (e *executor) executeSet(ctx context.Context, index string, c *pql.Call, op
 	return e.executeSetBitField(ctx, index, c, f, colID, rowID, timestamp, opt)
 }
 
+// setExistence sets the existence bit of a column, on a node which owns the
+// shard of the column. Existence bits are set by every write which sets a bit
+// or a value, and are only cleared by DeleteColumn().
+func (e *executor) setExistence(index string, colID uint64) error {
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+	if ef := idx.existenceField(); ef != nil {
+		if _, err := ef.SetBit(0, colID, nil); err != nil {
+			return errors.Wrap(err, "setting existence column")
+		}
+	}
+	return nil
+}
+
 // executeSetBitField executes a Set() call for a specific field.
 func (e *executor) executeSetBitField(ctx context.Context, index string, c *pql.Call, f *Field, colID, rowID uint64, timestamp *time.Time, opt *execOptions) (bool, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSetBitField")
 	defer span.Finish()
 
-	shard := colID / ShardWidth
+	shard := colID / f.shardWidth
 	ret := false
 
+	newShard := !opt.Remote && f.shardUnannounced(shard)
+
 	for _, node := range e.Cluster.shardNodes(index, shard) {
 		// Update locally if host matches.
 		if node.ID == e.Node.ID {
+			if err := e.setExistence(index, colID); err != nil {
+				return false, err
+			}
 			val, err := f.SetBit(rowID, colID, timestamp)
 			if err != nil {
 				return false, err
@@ -2159,12 +3057,17 @@ func (e *executor) executeSetBitField(ctx context.Context, index string, c *pql.
 		}
 
 		// Forward call to remote node otherwise.
-		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil)
+		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, opt)
 		if err != nil {
 			return false, err
 		}
 		ret = res[0].(bool)
 	}
+	if newShard {
+		if err := f.announceShard(shard); err != nil {
+			return false, err
+		}
+	}
 	return ret, nil
 }
 
@@ -2173,12 +3076,17 @@ func (e *executor) executeSetValueField(ctx context.Context, index string, c *pq
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSetValueField")
 	defer span.Finish()
 
-	shard := colID / ShardWidth
+	shard := colID / f.shardWidth
 	ret := false
 
+	newShard := !opt.Remote && f.shardUnannounced(shard)
+
 	for _, node := range e.Cluster.shardNodes(index, shard) {
 		// Update locally if host matches.
 		if node.ID == e.Node.ID {
+			if err := e.setExistence(index, colID); err != nil {
+				return false, err
+			}
 			val, err := f.SetValue(colID, value)
 			if err != nil {
 				return false, err
@@ -2194,15 +3102,252 @@ func (e *executor) executeSetValueField(ctx context.Context, index string, c *pq
 		}
 
 		// Forward call to remote node otherwise.
-		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil)
+		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, opt)
 		if err != nil {
 			return false, err
 		}
 		ret = res[0].(bool)
 	}
+	if newShard {
+		if err := f.announceShard(shard); err != nil {
+			return false, err
+		}
+	}
 	return ret, nil
 }
 
+// executeIncrementFieldValue executes an IncrementFieldValue() call. Each
+// replica applies the same amount to its own copy of the value so that
+// concurrent increments on different nodes don't overwrite each other.
+func (e *executor) executeIncrementFieldValue(ctx context.Context, index string, c *pql.Call, opt *execOptions) (ValCount, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeIncrementFieldValue")
+	defer span.Finish()
+
+	fieldName := callArgString(c, "field")
+	if fieldName == "" {
+		return ValCount{}, errors.New("IncrementFieldValue() argument required: field")
+	}
+	colID, ok, err := c.UintArg("column")
+	if err != nil {
+		return ValCount{}, fmt.Errorf("reading IncrementFieldValue() column: %v", err)
+	} else if !ok {
+		return ValCount{}, errors.New("IncrementFieldValue() argument required: column")
+	}
+	arg, ok := c.Args["amount"]
+	if !ok {
+		return ValCount{}, errors.New("IncrementFieldValue() argument required: amount")
+	}
+
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return ValCount{}, err
+	}
+	defer idx.Close()
+	f := idx.Field(fieldName)
+	if f == nil {
+		return ValCount{}, ErrFieldNotFound
+	}
+	fo := f.Options()
+	if fo.Type != FieldTypeInt {
+		return ValCount{}, fmt.Errorf("IncrementFieldValue() requires an int field: %s", fieldName)
+	}
+	amount, err := scaledValue(arg, fo.Scale)
+	if err != nil {
+		return ValCount{}, fmt.Errorf("reading IncrementFieldValue() amount: %v", err)
+	}
+
+	shard := colID / f.shardWidth
+	newShard := !opt.Remote && f.shardUnannounced(shard)
+
+	var ret ValCount
+	for _, node := range e.Cluster.shardNodes(index, shard) {
+		// Update locally if host matches.
+		if node.ID == e.Node.ID {
+			if err := e.setExistence(index, colID); err != nil {
+				return ValCount{}, err
+			}
+			value, err := f.IncrementValue(colID, amount)
+			if err != nil {
+				return ValCount{}, err
+			}
+			ret = ValCount{Val: value, Count: 1, Scale: fo.Scale}
+			continue
+		}
+
+		// Do not forward call if this is already being forwarded.
+		if opt.Remote {
+			continue
+		}
+
+		// Forward call to remote node otherwise. The call carries the
+		// amount rather than the new value.
+		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, opt)
+		if err != nil {
+			return ValCount{}, err
+		}
+		ret = res[0].(ValCount)
+	}
+	if newShard {
+		if err := f.announceShard(shard); err != nil {
+			return ValCount{}, err
+		}
+	}
+	return ret, nil
+}
+
+// executeDeleteColumn executes a DeleteColumn() call, which clears a column
+// from every view of every field of an index and removes its attributes.
+// The owners of the column's shard clear its bits; every node removes its
+// attributes, since column attributes are stored on every node.
+func (e *executor) executeDeleteColumn(ctx context.Context, index string, c *pql.Call, opt *execOptions) (FieldCounts, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeDeleteColumn")
+	defer span.Finish()
+
+	colID, ok, err := c.UintArg("column")
+	if err != nil {
+		return nil, fmt.Errorf("reading DeleteColumn() column: %v", err)
+	} else if !ok {
+		return nil, errors.New("DeleteColumn() argument required: column")
+	}
+
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	defer idx.Close()
+	shard := colID / idx.ShardWidth()
+
+	// Clear locally, unless the call is forwarded to this node only for
+	// its attributes.
+	ret := make(FieldCounts)
+	if e.Cluster.ownsShard(e.Node.ID, index, shard) {
+		if ret, err = e.deleteColumnShard(idx.Index, colID, shard); err != nil {
+			return nil, err
+		}
+	}
+	if err := deleteAttrs(idx.ColumnAttrStore(), colID); err != nil {
+		return nil, errors.Wrap(err, "removing column attributes")
+	}
+
+	// Do not forward call if this is already being forwarded.
+	if opt.Remote {
+		return ret, nil
+	}
+
+	// Execute on remote nodes in parallel. Replicas clear the same bits, so
+	// the largest count of each field is kept.
+	nodes := Nodes(e.Cluster.nodes).FilterID(e.Node.ID)
+	type response struct {
+		counts FieldCounts
+		err    error
+	}
+	resp := make(chan response, len(nodes))
+	for _, node := range nodes {
+		go func(node *Node) {
+			res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, opt)
+			if err != nil {
+				resp <- response{err: err}
+				return
+			}
+			counts, _ := res[0].(FieldCounts)
+			resp <- response{counts: counts}
+		}(node)
+	}
+	for range nodes {
+		r := <-resp
+		if r.err != nil {
+			return nil, r.err
+		}
+		for name, n := range r.counts {
+			if n > ret[name] {
+				ret[name] = n
+			}
+		}
+	}
+	return ret, nil
+}
+
+// deleteColumns deletes columns from an index as DeleteColumn() calls do,
+// and returns the number of bits cleared. It is used by delete jobs, which
+// hold column IDs even for indexes with keys, so the calls are executed
+// without translating their arguments. It waits until the cluster is in
+// the normal state, since a job may be resumed while the cluster starts.
+func (e *executor) deleteColumns(ctx context.Context, index string, columns []uint64) (uint64, error) {
+	for e.Cluster.State() != ClusterStateNormal {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return 0, err
+	}
+	defer idx.Close()
+	if idx.ReadOnly() {
+		return 0, ErrIndexReadOnly
+	}
+
+	e.txGate.enter()
+	defer e.txGate.exit()
+
+	var cleared uint64
+	for _, col := range columns {
+		if err := validateQueryContext(ctx); err != nil {
+			return cleared, err
+		}
+		c := &pql.Call{Name: "DeleteColumn", Args: map[string]interface{}{"column": col}}
+		counts, err := e.executeDeleteColumn(ctx, index, c, &execOptions{})
+		if err != nil {
+			return cleared, errors.Wrapf(err, "deleting column %d", col)
+		}
+		for _, n := range counts {
+			cleared += n
+		}
+	}
+	return cleared, nil
+}
+
+// deleteColumnShard clears a column from the fragments of shard in every
+// view of every field of idx, and returns the number of bits cleared in
+// each field which had any.
+func (e *executor) deleteColumnShard(idx *Index, colID, shard uint64) (FieldCounts, error) {
+	counts := make(FieldCounts)
+	for _, f := range idx.Fields() {
+		for _, view := range f.views() {
+			frag := view.Fragment(shard)
+			if frag == nil {
+				continue
+			}
+			n, err := frag.clearColumn(colID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "clearing column %d on field %s view %s", colID, f.Name(), view.name)
+			}
+			if n > 0 {
+				counts[f.Name()] += n
+			}
+		}
+	}
+	return counts, nil
+}
+
+// deleteAttrs removes every attribute of id from store.
+func deleteAttrs(store AttrStore, id uint64) error {
+	attrs, err := store.Attrs(id)
+	if err != nil {
+		return err
+	}
+	// Nil values delete keys. The map from the store is not modified, since
+	// it may be cached.
+	m := make(map[string]interface{}, len(attrs))
+	for k := range attrs {
+		m[k] = nil
+	}
+	return store.SetAttrs(id, m)
+}
+
 // executeSetRowAttrs executes a SetRowAttrs() call.
 func (e *executor) executeSetRowAttrs(ctx context.Context, index string, c *pql.Call, opt *execOptions) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSetRowAttrs")
@@ -2248,7 +3393,7 @@ func (e *executor) executeSetRowAttrs(ctx context.Context, index string, c *pql.
 	resp := make(chan error, len(nodes))
 	for _, node := range nodes {
 		go func(node *Node) {
-			_, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil)
+			_, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, opt)
 			resp <- err
 		}(node)
 	}
@@ -2343,7 +3488,7 @@ func (e *executor) executeBulkSetRowAttrs(ctx context.Context, index string, cal
 	resp := make(chan error, len(nodes))
 	for _, node := range nodes {
 		go func(node *Node) {
-			_, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: calls}, nil)
+			_, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: calls}, nil, opt)
 			resp <- err
 		}(node)
 	}
@@ -2365,10 +3510,11 @@ func (e *executor) executeSetColumnAttrs(ctx context.Context, index string, c *p
 	defer span.Finish()
 
 	// Retrieve index.
-	idx := e.Holder.Index(index)
-	if idx == nil {
-		return ErrIndexNotFound
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return err
 	}
+	defer idx.Close()
 
 	col, okCol, errCol := c.UintArg("_" + columnLabel)
 	if errCol != nil || !okCol {
@@ -2395,7 +3541,7 @@ func (e *executor) executeSetColumnAttrs(ctx context.Context, index string, c *p
 	resp := make(chan error, len(nodes))
 	for _, node := range nodes {
 		go func(node *Node) {
-			_, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil)
+			_, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, opt)
 			resp <- err
 		}(node)
 	}
@@ -2411,33 +3557,51 @@ func (e *executor) executeSetColumnAttrs(ctx context.Context, index string, c *p
 }
 
 // remoteExec executes a PQL query remotely for a set of shards on a node.
-func (e *executor) remoteExec(ctx context.Context, node *Node, index string, q *pql.Query, shards []uint64) (results []interface{}, err error) { // nolint: interfacer
+func (e *executor) remoteExec(ctx context.Context, node *Node, index string, q *pql.Query, shards []uint64, opt *execOptions) (results []interface{}, err error) { // nolint: interfacer
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeExec")
 	defer span.Finish()
 
-	// Encode request object.
+	// Encode request object, in the protocol version of the cluster.
 	pbreq := &QueryRequest{
-		Query:  q.String(),
-		Shards: shards,
-		Remote: true,
+		Query:        downgradeQuery(q, e.Cluster.ProtocolVersion()).String(),
+		Shards:       shards,
+		Remote:       true,
+		Session:      opt.Session,
+		StoreAs:      opt.StoreAs,
+		Snapshot:     opt.Snapshot,
+		AsOf:         opt.AsOf,
+		MaxStaleness: opt.MaxStaleness,
 	}
 
 	pb, err := e.client.QueryNode(ctx, &node.URI, index, pbreq)
 	if err != nil {
 		return nil, err
 	}
+	if opt.stale != nil && pb.Err == nil {
+		opt.stale.add(pb.StaleShards)
+	}
 
 	return pb.Results, pb.Err
 }
 
-// shardsByNode returns a mapping of nodes to shards.
+// shardsByNode returns a mapping of nodes to shards. Reads prefer replicas
+// which aren't in maintenance mode, down or, unless they accept stale data,
+// catching up.
 // Returns errShardUnavailable if a shard cannot be allocated to a node.
-func (e *executor) shardsByNode(nodes []*Node, index string, shards []uint64) (map[*Node][]uint64, error) {
+func (e *executor) shardsByNode(nodes []*Node, index string, shards []uint64, read, stale bool) (map[*Node][]uint64, error) {
 	m := make(map[*Node][]uint64)
 
 loop:
 	for _, shard := range shards {
-		for _, node := range e.Cluster.ShardNodes(index, shard) {
+		var owners []*Node
+		if read && stale {
+			owners = e.Cluster.staleShardNodes(index, shard)
+		} else if read {
+			owners = e.Cluster.preferredShardNodes(index, shard)
+		} else {
+			owners = e.Cluster.ShardNodes(index, shard)
+		}
+		for _, node := range owners {
 			if Nodes(nodes).Contains(node) {
 				m[node] = append(m[node], shard)
 				continue loop
@@ -2473,6 +3637,11 @@ func (e *executor) mapReduce(ctx context.Context, index string, shards []uint64,
 		nodes = []*Node{e.Cluster.nodeByID(e.Node.ID)}
 	}
 
+	// Verify a sample of the shards against a second replica.
+	if !c.IsWrite() {
+		e.verifyReads(index, shards, c, opt)
+	}
+
 	// Start mapping across all primary owners.
 	if err := e.mapper(ctx, ch, nodes, index, shards, c, opt, mapFn, reduceFn); err != nil {
 		return nil, errors.Wrap(err, "starting mapper")
@@ -2519,7 +3688,7 @@ func (e *executor) mapper(ctx context.Context, ch chan mapResponse, nodes []*Nod
 	defer span.Finish()
 
 	// Group shards together by nodes.
-	m, err := e.shardsByNode(nodes, index, shards)
+	m, err := e.shardsByNode(nodes, index, shards, !c.IsWrite(), opt.MaxStaleness > 0)
 	if err != nil {
 		return errors.Wrap(err, "shards by node")
 	}
@@ -2529,11 +3698,18 @@ func (e *executor) mapper(ctx context.Context, ch chan mapResponse, nodes []*Nod
 		go func(n *Node, nodeShards []uint64) {
 			resp := mapResponse{node: n, shards: nodeShards}
 
-			// Send local shards to mapper, otherwise remote exec.
+			// Send local shards to mapper, otherwise remote exec. A replica
+			// catching up only serves reads which accept its staleness.
 			if n.ID == e.Node.ID {
-				resp.result, resp.err = e.mapperLocal(ctx, nodeShards, mapFn, reduceFn)
+				var stale bool
+				if stale, resp.err = e.checkStaleness(index, nodeShards, c, opt); resp.err == nil {
+					resp.result, resp.err = e.mapperLocal(ctx, nodeShards, mapFn, reduceFn)
+				}
+				if stale && resp.err == nil && opt.stale != nil {
+					opt.stale.add(nodeShards)
+				}
 			} else if !opt.Remote {
-				results, err := e.remoteExec(ctx, n, index, &pql.Query{Calls: []*pql.Call{c}}, nodeShards)
+				results, err := e.remoteExec(ctx, n, index, &pql.Query{Calls: []*pql.Call{c}}, nodeShards, opt)
 				if len(results) > 0 {
 					resp.result = results[0]
 				}
@@ -2637,6 +3813,8 @@ func (e *executor) translateCall(index string, idx *Index, c *pql.Call) error {
 		colKey = "column"
 	case "GroupBy":
 		return errors.Wrap(e.translateGroupByCall(index, idx, c), "translating GroupBy")
+	case "IncrementFieldValue", "DeleteColumn":
+		colKey = "column"
 	default:
 		colKey = "col"
 		fieldName = callArgString(c, "field")
@@ -2686,10 +3864,21 @@ func (e *executor) translateCall(index string, idx *Index, c *pql.Call) error {
 			}
 			c.Args[rowKey] = rowID
 		} else if field.keys() {
-			if c.Args[rowKey] != nil && !isString(c.Args[rowKey]) {
+			if list, ok := c.Args[rowKey].([]interface{}); ok && c.Name == "Row" {
+				keys := make([]string, len(list))
+				for i, v := range list {
+					if keys[i], ok = v.(string); !ok {
+						return errors.New("row values must be strings when field 'keys' option enabled")
+					}
+				}
+				ids, err := field.translateStore.TranslateKeys(keys)
+				if err != nil {
+					return err
+				}
+				c.Args[rowKey] = ids
+			} else if c.Args[rowKey] != nil && !isString(c.Args[rowKey]) {
 				return errors.New("row value must be a string when field 'keys' option enabled")
-			}
-			if value := callArgString(c, rowKey); value != "" {
+			} else if value := callArgString(c, rowKey); value != "" {
 				id, err := field.translateStore.TranslateKey(value)
 				if err != nil {
 					return err
@@ -2846,6 +4035,17 @@ func (e *executor) translateResult(index string, idx *Index, call *pql.Call, res
 			}
 		}
 
+	case *SampleResult:
+		if _, sampled := sampleCall(call); sampled != nil && sampled.Name == "TopN" {
+			pairs, err := e.translateResult(index, idx, sampled, result.Pairs)
+			if err != nil {
+				return nil, err
+			}
+			other := *result
+			other.Pairs = pairs.([]Pair)
+			return &other, nil
+		}
+
 	case []GroupCount:
 		other := make([]GroupCount, 0)
 		for _, gl := range result {
@@ -2957,6 +4157,47 @@ type execOptions struct {
 	ExcludeRowAttrs bool
 	ExcludeColumns  bool
 	ColumnAttrs     bool
+
+	// Session of the query, and the handle its result is stored as.
+	Session string
+	StoreAs string
+
+	// Read a snapshot of the data as of the start of the query.
+	Snapshot bool
+
+	// Read the retained snapshots of the data as of this time.
+	AsOf time.Time
+
+	// Estimate the size of the result of each call instead of executing it.
+	Estimate bool
+
+	// Allow reads from replicas catching up with the other replicas whose
+	// data was caught up at most this long ago.
+	MaxStaleness time.Duration
+
+	// Wait until no other query executes on this node before executing.
+	LowPriority bool
+
+	// Result being stored by the query on this node.
+	stored *storedResult
+
+	// Shards read from replicas catching up, if MaxStaleness is set.
+	stale *staleShards
+}
+
+// waitIdle waits until no query executes on this node, other than the
+// low-priority queries.
+func (e *executor) waitIdle(ctx context.Context) error {
+	ticker := time.NewTicker(lowPriorityPollInterval)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&e.activeQueries) > 0 {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "waiting for other queries")
+		case <-ticker.C:
+		}
+	}
+	return nil
 }
 
 // hasOnlySetRowAttrs returns true if calls only contains SetRowAttrs() calls.
@@ -2979,7 +4220,7 @@ func needsShards(calls []*pql.Call) bool {
 	}
 	for _, call := range calls {
 		switch call.Name {
-		case "Clear", "Set", "SetRowAttrs", "SetColumnAttrs":
+		case "Clear", "Set", "SetRowAttrs", "SetColumnAttrs", "IncrementFieldValue", "DeleteColumn":
 			continue
 		case "Count", "TopN", "Rows":
 			return true
@@ -2991,10 +4232,51 @@ func needsShards(calls []*pql.Call) bool {
 	return false
 }
 
+// fieldScale returns the scale of a field, or zero if it does not exist.
+func (e *executor) fieldScale(index, fieldName string) int64 {
+	if f := e.Holder.Field(index, fieldName); f != nil {
+		return f.Options().Scale
+	}
+	return 0
+}
+
+// scaledPredicates returns the list of numbers of cond in units of
+// 10^-scale.
+func scaledPredicates(cond *pql.Condition, scale int64) ([]int64, error) {
+	list, ok := cond.Value.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("unexpected condition value type: %T", cond.Value)
+	}
+	predicates := make([]int64, len(list))
+	for i, v := range list {
+		p, err := scaledValue(v, scale)
+		if err != nil {
+			return nil, err
+		}
+		predicates[i] = p
+	}
+	return predicates, nil
+}
+
 // ValCount represents a grouping of sum & count for Sum() and Average() calls.
 type ValCount struct {
 	Val   int64 `json:"value"`
 	Count int64 `json:"count"`
+
+	// Scale of the field the value is from. Val is in units of 10^-Scale.
+	Scale int64 `json:"-"`
+}
+
+// MarshalJSON marshals the ValCount to JSON with the value as a decimal
+// if the field has a scale.
+func (vc ValCount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Val   json.Number `json:"value"`
+		Count int64       `json:"count"`
+	}{
+		Val:   json.Number(FormatDecimal(vc.Val, vc.Scale)),
+		Count: vc.Count,
+	})
 }
 
 func (vc *ValCount) add(other ValCount) ValCount {
@@ -3026,6 +4308,10 @@ func (vc *ValCount) larger(other ValCount) ValCount {
 	}
 }
 
+// FieldCounts is the number of bits cleared in each field by a
+// DeleteColumn() call. Fields without cleared bits are omitted.
+type FieldCounts map[string]uint64
+
 func callArgBool(call *pql.Call, key string) (bool, error) {
 	value, ok := call.Args[key]
 	if !ok {
@@ -3080,7 +4366,7 @@ type groupByIterator struct {
 }
 
 // newGroupByIterator initializes a new groupByIterator.
-func newGroupByIterator(rowIDs []RowIDs, children []*pql.Call, filter *Row, index string, shard uint64, holder *Holder) (*groupByIterator, error) {
+func newGroupByIterator(rowIDs []RowIDs, children []*pql.Call, filter *Row, index string, shard uint64, holder *Holder, fragment func(field string) *fragment) (*groupByIterator, error) {
 	gbi := &groupByIterator{
 		rowIters: make([]*rowIterator, len(children)),
 		rows: make([]struct {
@@ -3103,7 +4389,7 @@ func newGroupByIterator(rowIDs []RowIDs, children []*pql.Call, filter *Row, inde
 		}
 		gbi.fields[i].Field = fieldName
 		// Fetch fragment.
-		frag := holder.fragment(index, fieldName, viewStandard, shard)
+		frag := fragment(fieldName)
 		if frag == nil { // this means this whole shard doesn't have all it needs to continue
 			return nil, nil
 		}
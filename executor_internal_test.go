@@ -15,13 +15,17 @@
 package pilosa
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pkg/errors"
 )
 
 func TestExecutor_TranslateGroupByCall(t *testing.T) {
@@ -222,3 +226,194 @@ func TestFieldRowMarshalJSON(t *testing.T) {
 		t.Fatalf("unexpected json: %s", b)
 	}
 }
+
+func TestReadVerification(t *testing.T) {
+	parse := func(s string) *pql.Call {
+		q, err := pql.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q.Calls[0]
+	}
+
+	for _, tt := range []struct {
+		query      string
+		verifiable bool
+		fields     string
+	}{
+		{"Count(Intersect(Row(f=1), Row(g=2)))", true, "[f g]"},
+		{"Union(Row(f=1), Range(t=2, from=2020-01-01T00:00, to=2020-02-01T00:00))", true, "[f t]"},
+		{"Count(Row(v > 10))", true, "[v]"},
+		{`Count(Handle(name="h"))`, false, "[]"},
+		{"TopN(f, n=2)", false, "[f]"},
+		{"Set(1, f=1)", false, "[]"},
+	} {
+		c := parse(tt.query)
+		if got := verifiableCall(c); got != tt.verifiable {
+			t.Errorf("%s: verifiable %v, want %v", tt.query, got, tt.verifiable)
+		}
+		if got := fmt.Sprint(callFields(c)); got != tt.fields {
+			t.Errorf("%s: fields %s, want %s", tt.query, got, tt.fields)
+		}
+	}
+
+	if !resultsEqual(uint64(2), uint64(2)) || resultsEqual(uint64(2), uint64(3)) {
+		t.Fatal("unexpected comparison of counts")
+	}
+	if !resultsEqual(NewRow(1, 2), NewRow(2, 1)) || resultsEqual(NewRow(1, 2), NewRow(1, 3)) {
+		t.Fatal("unexpected comparison of rows")
+	}
+	if !resultsEqual((*Row)(nil), NewRow()) || resultsEqual(NewRow(1), (*Row)(nil)) {
+		t.Fatal("unexpected comparison of empty rows")
+	}
+}
+
+// Ensure low-priority queries wait until no other query executes.
+func TestExecutor_WaitIdle(t *testing.T) {
+	e := newExecutor()
+	defer e.Close()
+
+	if err := e.waitIdle(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	atomic.AddInt64(&e.activeQueries, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*lowPriorityPollInterval)
+	defer cancel()
+	if err := e.waitIdle(ctx); errors.Cause(err) != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- e.waitIdle(context.Background()) }()
+	atomic.AddInt64(&e.activeQueries, -1)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected low-priority query to proceed")
+	}
+}
+
+// Ensure that a snapshot read which can't take its snapshot while an import
+// is applied times out, and doesn't keep blocking writes.
+func TestExecutor_SnapshotTimeout(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.CreateIndex("i", IndexOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := newExecutor()
+	defer e.Close()
+	e.Holder = h.Holder
+	e.Cluster = NewTestCluster(1)
+	e.Node = e.Cluster.Node
+	e.snapshots = newReadSnapshots(SnapshotReadOptions{Timeout: 50 * time.Millisecond})
+
+	// An import holds the gate for the whole query.
+	e.snapshotGate.enter()
+	defer e.snapshotGate.exit()
+
+	q, err := pql.ParseString(`Count(All())`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Execute(context.Background(), "i", q, nil, &execOptions{Snapshot: true}); errors.Cause(err) != ErrSnapshotTimeout {
+		t.Fatalf("expected snapshot timeout, got %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		e.snapshotGate.enter()
+		e.snapshotGate.exit()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second / 2):
+		t.Fatal("expected write to enter the gate")
+	}
+}
+
+// failShardBroadcaster is a nopBroadcaster which counts the announcements of
+// new shards and fails them while fail is set.
+type failShardBroadcaster struct {
+	nopBroadcaster
+	fail bool
+	n    int
+}
+
+// SendSync is an implementation of Broadcaster SendSync which counts and
+// optionally fails CreateShardMessage.
+func (b *failShardBroadcaster) SendSync(m Message) error {
+	if _, ok := m.(*CreateShardMessage); !ok {
+		return nil
+	}
+	b.n++
+	if b.fail {
+		return errors.New("node unreachable")
+	}
+	return nil
+}
+
+// Ensure a write into a new shard fails if the shard can't be announced to
+// the other nodes, and that the next write to the shard announces it again.
+func TestExecutor_AnnounceShardError(t *testing.T) {
+	b := &failShardBroadcaster{fail: true}
+	h := newHolder()
+	defer h.Close()
+	h.broadcaster = b
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := h.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := idx.CreateField("f"); err != nil {
+		t.Fatal(err)
+	} else if _, err := idx.CreateField("v", OptFieldTypeInt(0, 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	e := newExecutor()
+	defer e.Close()
+	e.Holder = h.Holder
+	e.Cluster = NewTestCluster(1)
+	e.Node = e.Cluster.Node
+
+	for i, s := range []string{
+		`Set(1, f=1)`,
+		`IncrementFieldValue(field=v, column=1, amount=1)`,
+	} {
+		q, err := pql.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b.fail = true
+		if _, err := e.execute(context.Background(), "i", q, nil, &execOptions{}); err == nil || !strings.Contains(err.Error(), "broadcasting create shard: node unreachable") {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+
+		// The retry announces the shard again.
+		b.fail, b.n = false, 0
+		if _, err := e.execute(context.Background(), "i", q, nil, &execOptions{}); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		} else if b.n != 1 {
+			t.Fatalf("%d: expected shard to be announced, got %d announcements", i, b.n)
+		}
+
+		// Once announced, the shard isn't announced again.
+		if _, err := e.execute(context.Background(), "i", q, nil, &execOptions{}); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		} else if b.n != 1 {
+			t.Fatalf("%d: expected no announcement, got %d", i, b.n-1)
+		}
+	}
+}
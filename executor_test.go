@@ -37,6 +37,7 @@ import (
 	"github.com/pilosa/pilosa/v2/server"
 	"github.com/pilosa/pilosa/v2/test"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -363,6 +364,58 @@ func TestExecutor_Execute_Union(t *testing.T) {
 	})
 }
 
+// Ensure Row() returns the union of a list of rows.
+func TestExecutor_Execute_Row_List(t *testing.T) {
+	t.Run("RowIDColumnID", func(t *testing.T) {
+		c := test.MustRunCluster(t, 3)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+		c.ImportBits(t, "i", "f", [][2]uint64{
+			{1, 0}, {1, ShardWidth + 1}, {2, 2}, {2, 2*ShardWidth + 2}, {3, 3}, {4, 4},
+		})
+
+		for _, query := range []string{
+			`Row(f=[1, 2, 4])`,
+			`Union(Row(f=1), Row(f=2), Row(f=4))`,
+			`Union(Row(f=[1, 2]), Row(f=4))`,
+		} {
+			if columns := c.Query(t, "i", query).Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{0, 2, 4, ShardWidth + 1, 2*ShardWidth + 2}) {
+				t.Fatalf("%s: unexpected columns: %+v", query, columns)
+			} else if n := c.Query(t, "i", "Count("+query+")").Results[0].(uint64); n != 5 {
+				t.Fatalf("Count(%s): unexpected n: %d", query, n)
+			}
+		}
+		if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=[1, -2])`}); err == nil {
+			t.Fatal("expected error for negative row")
+		}
+	})
+
+	t.Run("RowKey", func(t *testing.T) {
+		writeQuery := `
+			Set(1, f="ten")
+			Set(2, f="eleven")
+			Set(3, f="twelve")`
+		readQueries := []string{`Row(f=["ten", "twelve"])`}
+		responses := runCallTest(t, writeQuery, readQueries, nil, pilosa.OptFieldKeys())
+		if columns := responses[0].Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1, 3}) {
+			t.Fatalf("unexpected columns: %+v", columns)
+		}
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		writeQuery := `
+			Set(1, f=1, 2000-01-01T00:00)
+			Set(2, f=2, 2000-01-02T00:00)
+			Set(3, f=2, 2001-01-01T00:00)
+			Set(4, f=3, 2000-01-01T00:00)`
+		readQueries := []string{`Row(f=[1, 2], from=2000-01-01T00:00, to=2000-02-01T00:00)`}
+		responses := runCallTest(t, writeQuery, readQueries, nil, pilosa.OptFieldTypeTime("YMD"))
+		if columns := responses[0].Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1, 2}) {
+			t.Fatalf("unexpected columns: %+v", columns)
+		}
+	})
+}
+
 // Ensure an empty union query behaves properly.
 func TestExecutor_Execute_Empty_Union(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
@@ -506,6 +559,258 @@ func TestExecutor_Execute_Count(t *testing.T) {
 		}
 	})
 
+	// Counts of set operations are computed without materializing the
+	// result, so compare them against the materialized rows.
+	t.Run("SetOperations", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+		rnd := rand.New(rand.NewSource(7))
+		req := &pilosa.ImportRequest{Index: "i", Field: "f"}
+		for i := 0; i < 20000; i++ {
+			req.RowIDs = append(req.RowIDs, uint64(rnd.Intn(4)))
+			// Dense columns in the first container of each shard so that
+			// bitmap containers are exercised as well as arrays.
+			col := uint64(rnd.Intn(3))*ShardWidth + uint64(rnd.Intn(1<<17))
+			if i%2 == 0 {
+				col = col - col%ShardWidth + uint64(rnd.Intn(1<<14))
+			}
+			req.ColumnIDs = append(req.ColumnIDs, col)
+		}
+		for shard := uint64(0); shard < 3; shard++ {
+			sreq := &pilosa.ImportRequest{Index: "i", Field: "f", Shard: shard}
+			for i := range req.ColumnIDs {
+				if req.ColumnIDs[i]/ShardWidth == shard {
+					sreq.RowIDs = append(sreq.RowIDs, req.RowIDs[i])
+					sreq.ColumnIDs = append(sreq.ColumnIDs, req.ColumnIDs[i])
+				}
+			}
+			if err := c[0].API.Import(context.Background(), sreq); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, expr := range []string{
+			`Union(Row(f=0), Row(f=1))`,
+			`Union(Row(f=0), Row(f=1), Row(f=2), Row(f=3))`,
+			`Union(Row(f=0), Row(f=9))`,
+			`Intersect(Row(f=0), Row(f=1))`,
+			`Intersect(Row(f=0), Row(f=1), Row(f=2))`,
+			`Difference(Row(f=0), Row(f=1))`,
+			`Difference(Row(f=0), Row(f=1), Row(f=2))`,
+			`Xor(Row(f=0), Row(f=1))`,
+			`Xor(Row(f=0), Row(f=1), Row(f=2))`,
+			`Union(Intersect(Row(f=0), Row(f=1)), Difference(Row(f=2), Row(f=3)))`,
+			`Union(Row(f=0))`,
+		} {
+			row := c.Query(t, "i", expr).Results[0].(*pilosa.Row)
+			if n := c.Query(t, "i", "Count("+expr+")").Results[0].(uint64); n != uint64(len(row.Columns())) {
+				t.Fatalf("Count(%s): unexpected n: %d != %d", expr, n, len(row.Columns()))
+			}
+		}
+	})
+}
+
+// BenchmarkExecutor_CountUnion counts a wide union, which is computed without
+// materializing the union of each shard.
+func BenchmarkExecutor_CountUnion(b *testing.B) {
+	c := test.MustRunCluster(b, 1)
+	defer c.Close()
+	c.CreateField(b, "i", pilosa.IndexOptions{}, "f")
+
+	const rows = 64
+	req := &pilosa.ImportRequest{Index: "i", Field: "f"}
+	for i := 0; i < 200000; i++ {
+		req.RowIDs = append(req.RowIDs, uint64(rand.Intn(rows)))
+		req.ColumnIDs = append(req.ColumnIDs, uint64(rand.Intn(ShardWidth)))
+	}
+	if err := c[0].API.Import(context.Background(), req); err != nil {
+		b.Fatal(err)
+	}
+
+	var buf strings.Builder
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "Row(f=%d)", i)
+	}
+	union := "Union(" + buf.String() + ")"
+
+	for _, query := range []string{union, "Count(" + union + ")"} {
+		b.Run(strings.SplitN(query, "(", 2)[0], func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Ensure Intersect skips operands once the intersection of a shard is
+// empty, and Union skips operands once a row has every column of a shard,
+// without changing results.
+func TestExecutor_SetOperationShortCircuit(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	const w = 1 << 16
+	for _, field := range []string{"f", "g", "h"} {
+		c.CreateField(t, "i", pilosa.IndexOptions{ShardWidth: w}, field)
+	}
+
+	// Row 1 of f and h has columns 1 and 2 of shards 0-3, and of g only of
+	// shard 2. Row 2 of f has every column of shard 0.
+	for shard := uint64(0); shard < 4; shard++ {
+		c.ImportBits(t, "i", "f", [][2]uint64{{1, shard*w + 1}, {1, shard*w + 2}})
+		c.ImportBits(t, "i", "h", [][2]uint64{{1, shard*w + 1}, {1, shard*w + 2}})
+	}
+	c.ImportBits(t, "i", "g", [][2]uint64{{1, 2*w + 2}, {2, 3*w + 5}})
+	var full [][2]uint64
+	for col := uint64(0); col < w; col++ {
+		full = append(full, [2]uint64{2, col})
+	}
+	c.ImportBits(t, "i", "f", full)
+
+	for _, tt := range []struct {
+		query string
+		exp   []uint64
+	}{
+		{`Intersect(Row(f=1), Row(g=1), Row(h=1))`, []uint64{2*w + 2}},
+		{`Intersect(Row(g=1), Row(f=1), Union(Row(h=1), Row(g=2)))`, []uint64{2*w + 2}},
+		{`Intersect(Row(f=1), Row(g=9), Row(h=1))`, nil},
+		{`Intersect(Row(f=2), Row(h=1))`, []uint64{1, 2}},
+		{`Union(Row(f=2), Row(g=1), Row(g=2))`, []uint64{2*w + 2, 3*w + 5}},
+	} {
+		exp := tt.exp
+		if strings.HasPrefix(tt.query, "Union") {
+			exp = nil
+			for col := uint64(0); col < w; col++ {
+				exp = append(exp, col)
+			}
+			exp = append(exp, tt.exp...)
+		}
+		row := c.Query(t, "i", tt.query).Results[0].(*pilosa.Row)
+		if got := row.Columns(); !reflect.DeepEqual(got, exp) && !(len(got) == 0 && len(exp) == 0) {
+			t.Fatalf("%s: unexpected columns: %v", tt.query, got)
+		}
+		if n := c.Query(t, "i", "Count("+tt.query+")").Results[0].(uint64); n != uint64(len(exp)) {
+			t.Fatalf("Count(%s): unexpected n: %d", tt.query, n)
+		}
+	}
+
+	// Skipped operands are still validated, so that an invalid operand is
+	// an error whether the others are empty or full. Row 2 of f is full and
+	// row 9 of g is empty in shard 0.
+	for _, tt := range []struct {
+		query string
+		err   string
+	}{
+		{`Intersect(Row(g=9), Row(nosuch=1))`, "field not found"},
+		{`Intersect(Row(g=9), Row(f=1), Row(h=1, from="bad"))`, "parsing from time"},
+		{`Intersect(Row(g=9), Row(f > 1))`, "bsigroup not found"},
+		{`Intersect(Row(g=9), Not(Row(f=1)))`, "does not support existence tracking"},
+		{`Union(Row(f=2), Row(nosuch=1))`, "field not found"},
+		{`Union(Row(f=2), Intersect())`, "empty Intersect query"},
+	} {
+		for _, query := range []string{tt.query, "Count(" + tt.query + ")"} {
+			_, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query, Shards: []uint64{0}})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("%s: expected error %q, got %v", query, tt.err, err)
+			}
+		}
+	}
+}
+
+// BenchmarkExecutor_IntersectSparse intersects a row of a field which is
+// set in few shards with dense rows of other fields, so that most shards
+// skip the dense operands.
+func BenchmarkExecutor_IntersectSparse(b *testing.B) {
+	c := test.MustRunCluster(b, 1)
+	defer c.Close()
+	for _, field := range []string{"sparse", "dense1", "dense2"} {
+		c.CreateField(b, "i", pilosa.IndexOptions{}, field)
+	}
+
+	const shards = 32
+	for _, field := range []string{"dense1", "dense2"} {
+		req := &pilosa.ImportRequest{Index: "i", Field: field}
+		for i := 0; i < 200000; i++ {
+			req.RowIDs = append(req.RowIDs, 1)
+			req.ColumnIDs = append(req.ColumnIDs, uint64(rand.Intn(shards*ShardWidth)))
+		}
+		if err := c[0].API.Import(context.Background(), req); err != nil {
+			b.Fatal(err)
+		}
+	}
+	var bits [][2]uint64
+	for shard := uint64(0); shard < shards; shard += 8 {
+		for i := 0; i < 1000; i++ {
+			bits = append(bits, [2]uint64{1, shard*ShardWidth + uint64(rand.Intn(ShardWidth))})
+		}
+	}
+	c.ImportBits(b, "i", "sparse", bits)
+
+	for _, query := range []string{
+		"Count(Intersect(Row(sparse=1), Row(dense1=1), Row(dense2=1)))",
+		"Count(Intersect(Row(dense1=1), Row(dense2=1), Row(sparse=1)))",
+		"Count(Intersect(Row(dense1=1), Row(dense2=1), Union(Row(sparse=1))))",
+	} {
+		b.Run(query, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Ensure the shards of a query are resolved, validated, and returned.
+func TestExecutor_Execute_Shards(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "w", pilosa.IndexOptions{ShardWindow: 2}, "f")
+	for _, index := range []string{"i", "w"} {
+		for _, shard := range []uint64{0, 1, 2, 5} {
+			c.Query(t, index, fmt.Sprintf(`Set(%d, f=1)`, shard*ShardWidth+1))
+		}
+	}
+
+	for _, tt := range []struct {
+		index  string
+		shards []uint64
+		n      uint64
+		exp    []uint64
+	}{
+		{index: "i", n: 4, exp: []uint64{0, 1, 2, 5}},
+		{index: "i", shards: []uint64{5, 1, 1}, n: 2, exp: []uint64{1, 5}},
+		{index: "i", shards: []uint64{3, 4}, n: 0, exp: []uint64{3, 4}},
+		{index: "w", n: 1, exp: []uint64{5}},
+		{index: "w", shards: []uint64{0, 1}, n: 2, exp: []uint64{0, 1}},
+	} {
+		resp, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: tt.index, Query: `Count(Row(f=1))`, Shards: tt.shards})
+		if err != nil {
+			t.Fatal(err)
+		} else if n := resp.Results[0].(uint64); n != tt.n {
+			t.Fatalf("%s %v: unexpected n: %d", tt.index, tt.shards, n)
+		} else if !reflect.DeepEqual(resp.Shards, tt.exp) {
+			t.Fatalf("%s %v: unexpected shards: %v", tt.index, tt.shards, resp.Shards)
+		}
+	}
+
+	// Writes do not operate on shards.
+	if resp := c.Query(t, "i", `Set(1, f=2)`); resp.Shards != nil {
+		t.Fatalf("unexpected shards: %v", resp.Shards)
+	}
+
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Count(Row(f=1))`, Shards: []uint64{1, 6}}); errors.Cause(err) != pilosa.ErrShardOutOfRange {
+		t.Fatalf("unexpected error: %v", err)
+	}
 }
 
 // Ensure a set query can be executed.
@@ -1262,7 +1567,7 @@ func TestExecutor_Execute_TopN_Src(t *testing.T) {
 	}
 }
 
-//Ensure TopN handles Attribute filters
+// Ensure TopN handles Attribute filters
 func TestExecutor_Execute_TopN_Attr(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
 	defer c.Close()
@@ -1284,7 +1589,7 @@ func TestExecutor_Execute_TopN_Attr(t *testing.T) {
 
 }
 
-//Ensure TopN handles Attribute filters with source row
+// Ensure TopN handles Attribute filters with source row
 func TestExecutor_Execute_TopN_Attr_Src(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
 	defer c.Close()
@@ -2809,6 +3114,54 @@ func TestExecutor_Execute_Existence(t *testing.T) {
 }
 
 // Ensure a not query can be executed.
+// Ensure Count() without an input counts the columns of the index, which
+// every write which sets a bit or a value adds.
+func TestExecutor_Execute_CountColumns(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	hldr := test.Holder{Holder: c[0].Server.Holder()}
+	hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{TrackExistence: true})
+	hldr.MustCreateIndexIfNotExists("j", pilosa.IndexOptions{})
+	if _, err := c[0].API.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c[0].API.CreateField(context.Background(), "i", "v", pilosa.OptFieldTypeInt(0, 100)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{
+		Index: "i",
+		Query: fmt.Sprintf(`
+			Set(1, f=10)
+			Set(2, f=10)
+			Set(%d, f=20)
+			Set(3, v=5)
+			IncrementFieldValue(field=v, column=%d, amount=1)
+			Clear(2, f=10)`, ShardWidth+1, 2*ShardWidth),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Clearing a bit doesn't remove the column, deleting it does.
+	if res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Count()"}); err != nil {
+		t.Fatal(err)
+	} else if n := res.Results[0].(uint64); n != 5 {
+		t.Fatalf("expected 5 columns, got %d", n)
+	}
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "DeleteColumn(column=2)"}); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Count()"}); err != nil {
+		t.Fatal(err)
+	} else if n := res.Results[0].(uint64); n != 4 {
+		t.Fatalf("expected 4 columns, got %d", n)
+	}
+
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "j", Query: "Count()"}); err == nil || !strings.Contains(err.Error(), "tracks existence") {
+		t.Fatalf("expected existence error, got %v", err)
+	}
+}
+
 func TestExecutor_Execute_Not(t *testing.T) {
 	t.Run("RowIDColumnID", func(t *testing.T) {
 		writeQuery := `` +
@@ -4136,3 +4489,589 @@ func TestExecutor_Execute_Shift(t *testing.T) {
 		}
 	})
 }
+
+// Ensure indexes with different shard widths can be queried side by side.
+func TestExecutor_Execute_ShardWidth(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	const narrowWidth = 1 << 16
+	c.CreateField(t, "narrow", pilosa.IndexOptions{ShardWidth: narrowWidth}, "f")
+	c.CreateField(t, "wide", pilosa.IndexOptions{ShardWidth: 4 * ShardWidth}, "f")
+	c.CreateField(t, "std", pilosa.IndexOptions{}, "f")
+
+	cols := []uint64{1, narrowWidth + 2, 3*narrowWidth + 3, ShardWidth + 4, 3*ShardWidth + 5, 5 * ShardWidth}
+	for _, index := range []string{"narrow", "wide", "std"} {
+		for i, col := range cols {
+			c.Query(t, index, fmt.Sprintf(`Set(%d, f=10)`, col))
+			c.Query(t, index, fmt.Sprintf(`Set(%d, f=%d)`, col, 20+i%2))
+		}
+	}
+
+	for _, index := range []string{"narrow", "wide", "std"} {
+		t.Run(index, func(t *testing.T) {
+			if exp := c[0].Server.Holder().Index(index).ShardWidth(); c[1].Server.Holder().Index(index).ShardWidth() != exp {
+				t.Fatalf("shard width not propagated: %d != %d", c[1].Server.Holder().Index(index).ShardWidth(), exp)
+			}
+
+			if columns := c.Query(t, index, `Row(f=10)`).Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, cols) {
+				t.Fatalf("unexpected columns: %+v", columns)
+			}
+			if n := c.Query(t, index, `Count(Intersect(Row(f=10), Row(f=20)))`).Results[0].(uint64); n != 3 {
+				t.Fatalf("unexpected count: %d", n)
+			}
+			if columns := c.Query(t, index, `Union(Row(f=20), Row(f=21))`).Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, cols) {
+				t.Fatalf("unexpected union columns: %+v", columns)
+			}
+			rows := c.Query(t, index, fmt.Sprintf(`Rows(f, column=%d)`, cols[3])).Results[0].(pilosa.RowIdentifiers)
+			if !reflect.DeepEqual(rows.Rows, []uint64{10, 21}) {
+				t.Fatalf("unexpected rows: %+v", rows)
+			}
+
+			c.Query(t, index, fmt.Sprintf(`Clear(%d, f=10)`, cols[2]))
+			if n := c.Query(t, index, `Count(Row(f=10))`).Results[0].(uint64); n != uint64(len(cols)-1) {
+				t.Fatalf("unexpected count after clear: %d", n)
+			}
+		})
+	}
+}
+
+// Ensure query results can be stored in a session and referenced by later
+// queries across a cluster.
+func TestExecutor_Execute_IncrementFieldValue(t *testing.T) {
+	c := test.MustRunCluster(t, 3, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplicaN(3))})
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f", pilosa.OptFieldTypeInt(-10, 100))
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "g", pilosa.OptFieldTypeInt(0, 10), pilosa.OptFieldClampIncrements())
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "s")
+
+	increment := func(node int, field string, col uint64, amount int64) (int64, error) {
+		res, err := c[node].API.Query(context.Background(), &pilosa.QueryRequest{
+			Index: "i",
+			Query: fmt.Sprintf(`IncrementFieldValue(field=%s, column=%d, amount=%d)`, field, col, amount),
+		})
+		if err != nil {
+			return 0, err
+		}
+		return res.Results[0].(pilosa.ValCount).Val, nil
+	}
+
+	// Concurrent increments on every node are applied to every replica.
+	var eg errgroup.Group
+	for i := range c {
+		node := i
+		eg.Go(func() error {
+			for j := 0; j < 10; j++ {
+				if _, err := increment(node, "f", ShardWidth+1, 2); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range c {
+		if value, exists, err := c[i].Server.Holder().Field("i", "f").Value(ShardWidth + 1); err != nil {
+			t.Fatal(err)
+		} else if !exists || value != 60 {
+			t.Fatalf("node %d: unexpected value: %d, %v", i, value, exists)
+		}
+	}
+
+	if value, err := increment(1, "f", ShardWidth+1, -65); err != nil {
+		t.Fatal(err)
+	} else if value != -5 {
+		t.Fatalf("unexpected value: %d", value)
+	} else if _, err := increment(1, "f", ShardWidth+1, -6); err == nil || !strings.Contains(err.Error(), pilosa.ErrBSIGroupValueTooLow.Error()) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The field clamps increments to its range.
+	if value, err := increment(2, "g", 1, 15); err != nil {
+		t.Fatal(err)
+	} else if value != 10 {
+		t.Fatalf("unexpected value: %d", value)
+	}
+
+	for _, tt := range []struct {
+		query string
+		err   string
+	}{
+		{query: `IncrementFieldValue(column=1, amount=1)`, err: "argument required: field"},
+		{query: `IncrementFieldValue(field=f, amount=1)`, err: "argument required: column"},
+		{query: `IncrementFieldValue(field=f, column=1)`, err: "argument required: amount"},
+		{query: `IncrementFieldValue(field=s, column=1, amount=1)`, err: "requires an int field"},
+		{query: `IncrementFieldValue(field=x, column=1, amount=1)`, err: "field not found"},
+	} {
+		if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: tt.query}); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+	}
+}
+
+// Ensure a column can be deleted from every field of an index and every
+// replica.
+func TestExecutor_Execute_DeleteColumn(t *testing.T) {
+	c := test.MustRunCluster(t, 3, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplicaN(2))})
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "s")
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "t", pilosa.OptFieldTypeTime("YMD"))
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "n", pilosa.OptFieldTypeInt(0, 100))
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "m", pilosa.OptFieldTypeMutex(pilosa.CacheTypeNone, 0))
+
+	col := uint64(ShardWidth + 5)
+	c.Query(t, "i", fmt.Sprintf(`
+		Set(%[1]d, s=1) Set(%[1]d, s=2) Set(%[2]d, s=1)
+		Set(%[1]d, t=3, 2019-01-02T00:00) Set(%[1]d, n=5) Set(%[1]d, m=4)
+		SetColumnAttrs(%[1]d, name="x") SetColumnAttrs(%[2]d, name="y")`, col, col+1))
+
+	deleteColumn := func() pilosa.FieldCounts {
+		return c.Query(t, "i", fmt.Sprintf(`DeleteColumn(column=%d)`, col)).Results[0].(pilosa.FieldCounts)
+	}
+	if counts := deleteColumn(); !reflect.DeepEqual(counts, pilosa.FieldCounts{"s": 2, "t": 4, "n": 3, "m": 1, "_exists": 1}) {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+
+	// Every replica of the shard no longer has the column, but still has
+	// the other column.
+	for i := range c {
+		row, err := c[i].Server.Holder().Field("i", "s").Row(1)
+		if err != nil {
+			t.Fatal(err)
+		} else if cols := row.Columns(); len(cols) > 0 && !reflect.DeepEqual(cols, []uint64{col + 1}) {
+			t.Fatalf("node %d: unexpected columns: %v", i, cols)
+		} else if attrs, err := c[i].Server.Holder().Index("i").ColumnAttrStore().Attrs(col); err != nil {
+			t.Fatal(err)
+		} else if len(attrs) != 0 {
+			t.Fatalf("node %d: unexpected attrs: %v", i, attrs)
+		}
+	}
+	if n := c.Query(t, "i", `Count(Union(Row(s=2), Row(t=3), Row(n > 0), Row(m=4)))`).Results[0].(uint64); n != 0 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+	if pairs := c.Query(t, "i", `TopN(s, n=5)`).Results[0].([]pilosa.Pair); !reflect.DeepEqual(pairs, []pilosa.Pair{{ID: 1, Count: 1}}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+
+	// Deleting the column again doesn't clear anything.
+	if counts := deleteColumn(); len(counts) != 0 {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `DeleteColumn()`}); err == nil || !strings.Contains(err.Error(), "argument required: column") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExecutor_Execute_Snapshot(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "t", pilosa.OptFieldTypeTime("YMD"))
+	ctx := context.Background()
+
+	// Each round sets a bit in every shard with a single query, and imports
+	// a bit into the standard and time views of t.
+	const rounds = 200
+	timestamp := time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC).UnixNano()
+	done := make(chan error, 1)
+	go func() {
+		for i := uint64(0); i < rounds; i++ {
+			q := fmt.Sprintf(`Set(%d, f=1) Set(%d, f=1) Set(%d, f=1) Set(%d, f=1)`, i, ShardWidth+i, 2*ShardWidth+i, 3*ShardWidth+i)
+			if _, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: q}); err != nil {
+				done <- err
+				return
+			}
+			req := &pilosa.ImportRequest{Index: "i", Field: "t", RowIDs: []uint64{1}, ColumnIDs: []uint64{i}, Timestamps: []int64{timestamp}}
+			if err := c[0].API.Import(ctx, req); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	// A snapshot read sees either all of the bits of a round or none of them.
+	query := `Count(Row(f=1)) Count(Row(t=1)) Count(Row(t=1, from=2019-01-01T00:00, to=2019-01-03T00:00))`
+	for running := true; running; {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			running = false
+		default:
+		}
+		res, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query, Snapshot: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, std, views := res.Results[0].(uint64), res.Results[1].(uint64), res.Results[2].(uint64)
+		if f%4 != 0 || std != views {
+			t.Fatalf("inconsistent snapshot: f=%d, t=%d, t views=%d", f, std, views)
+		} else if !running && (f != 4*rounds || std != rounds) {
+			t.Fatalf("unexpected final counts: f=%d, t=%d", f, std)
+		}
+	}
+
+	t.Run("Write", func(t *testing.T) {
+		if _, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: `Set(1, f=2)`, Snapshot: true}); err == nil || !strings.Contains(err.Error(), "snapshot reads cannot write") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	// The memory of a snapshot is released after the query, so a limit
+	// which fits the snapshot of two single-container fragments allows any
+	// number of such queries, but not a query over four fragments.
+	t.Run("MaxMemory", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerSnapshotReads(pilosa.SnapshotReadOptions{MaxMemory: 200}))})
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+		c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(%d, f=1)`, ShardWidth+1))
+
+		for i := 0; i < 3; i++ {
+			if res, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: `Count(Row(f=1))`, Snapshot: true}); err != nil {
+				t.Fatal(err)
+			} else if n := res.Results[0].(uint64); n != 2 {
+				t.Fatalf("unexpected count: %d", n)
+			}
+		}
+
+		c.Query(t, "i", fmt.Sprintf(`Set(%d, f=1) Set(%d, f=1)`, 2*ShardWidth+1, 3*ShardWidth+1))
+		if _, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: `Count(Row(f=1))`, Snapshot: true}); errors.Cause(err) != pilosa.ErrSnapshotMemory {
+			t.Fatalf("expected snapshot memory error, got %v", err)
+		} else if n := c.Query(t, "i", `Count(Row(f=1))`).Results[0].(uint64); n != 4 {
+			t.Fatalf("unexpected count: %d", n)
+		}
+	})
+}
+
+func TestExecutor_Execute_Estimate(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "v", pilosa.OptFieldTypeInt(0, 100))
+	c.Query(t, "i", fmt.Sprintf(`Set(1, f=1) Set(2, f=1) Set(%d, f=1) Set(3, f=2) Set(1, v=10) Set(%d, v=20)`, ShardWidth+1, ShardWidth+1))
+	ctx := context.Background()
+
+	estimate := func(query string) *pilosa.QueryEstimate {
+		t.Helper()
+		res, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query, Estimate: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Results[0].(*pilosa.QueryEstimate)
+	}
+
+	// The columns of the two shards have up to 7 digits.
+	e := estimate(`Union(Row(f=1), Row(f=2))`)
+	if e.Columns != 4 || e.Length != 4 {
+		t.Fatalf("unexpected estimate: %+v", e)
+	} else if got, want := e.Bytes["json"], uint64(2+4*8-1); got != want {
+		t.Fatalf("unexpected json bytes: got %d, want %d", got, want)
+	} else if got, want := e.Bytes["roaring"], uint64(8+4*16+4*2); got != want {
+		t.Fatalf("unexpected roaring bytes: got %d, want %d", got, want)
+	}
+
+	for _, tt := range []struct {
+		query              string
+		candidates, length uint64
+	}{
+		{`TopN(f, n=1)`, 2, 1},
+		{`TopN(f, ids=[1, 2, 3])`, 3, 3},
+		{`Rows(f)`, 2, 2},
+		{`GroupBy(Rows(f), Rows(f), limit=3)`, 4, 3},
+		{`Sum(field=v)`, 2, 1},
+		{`Max(Row(f=2), field=v)`, 0, 1},
+		{`Count(Row(f=1))`, 3, 1},
+	} {
+		if e := estimate(tt.query); e.Candidates != tt.candidates || e.Length != tt.length {
+			t.Fatalf("unexpected estimate of %s: %+v", tt.query, e)
+		}
+	}
+
+	if data, err := json.Marshal(e); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(string(data), `"estimate":true`) {
+		t.Fatalf("expected estimate label: %s", data)
+	}
+
+	// The sizes depend on the shard width of the index: the columns of two
+	// shards of 2^16 columns have up to 6 digits, in one container each.
+	c.CreateField(t, "j", pilosa.IndexOptions{ShardWidth: 1 << 16}, "f")
+	c.Query(t, "j", fmt.Sprintf(`Set(1, f=1) Set(2, f=1) Set(3, f=1) Set(%d, f=1)`, 1<<16+1))
+	res, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "j", Query: `Row(f=1)`, Estimate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := res.Results[0].(*pilosa.QueryEstimate); e.Columns != 4 {
+		t.Fatalf("unexpected estimate: %+v", e)
+	} else if got, want := e.Bytes["json"], uint64(2+4*7-1); got != want {
+		t.Fatalf("unexpected json bytes: got %d, want %d", got, want)
+	} else if got, want := e.Bytes["roaring"], uint64(8+2*16+4*2); got != want {
+		t.Fatalf("unexpected roaring bytes: got %d, want %d", got, want)
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, query := range []string{`Set(1, f=2)`, `Options(Row(f=1), shards=[0])`} {
+			if _, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query, Estimate: true}); !isBadRequestError(err) {
+				t.Fatalf("expected bad request error for %s, got %v", query, err)
+			}
+		}
+	})
+}
+
+func TestExecutor_Execute_Sample(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	ctx := context.Background()
+
+	// Each of 20 shards has 10 bits in row 1 and 5 in row 2, of which 5
+	// are in row 1, and shard s has s bits in row 3.
+	var bits [][2]uint64
+	for shard := uint64(0); shard < 20; shard++ {
+		for i := uint64(0); i < 10; i++ {
+			bits = append(bits, [2]uint64{1, shard*ShardWidth + i})
+			if i < 5 {
+				bits = append(bits, [2]uint64{2, shard*ShardWidth + i})
+			}
+			if i < shard {
+				bits = append(bits, [2]uint64{3, shard*ShardWidth + i})
+			}
+		}
+	}
+	c.ImportBits(t, "i", "f", bits)
+
+	sample := func(query string) *pilosa.SampleResult {
+		t.Helper()
+		res, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Results[0].(*pilosa.SampleResult)
+	}
+
+	// Sampling every shard is exact.
+	if r := sample(`Sample(Row(f=1), fraction=1)`); r.Count != 200 || r.Shards != 20 || r.TotalShards != 20 || r.Variance != 0 || r.StdErr != 0 {
+		t.Fatalf("unexpected sample: %+v", r)
+	}
+
+	// Samples are reproducible, and extrapolate uniform counts exactly.
+	r := sample(`Sample(Row(f=1), fraction=0.3, seed=7)`)
+	if r.Count != 200 || r.Shards == 0 || r.Shards == 20 || r.Fraction != 0.3 || r.Seed != 7 {
+		t.Fatalf("unexpected sample: %+v", r)
+	} else if other := sample(`Count(Sample(Row(f=1), fraction=0.3, seed=7))`); !reflect.DeepEqual(r, other) {
+		t.Fatalf("expected %+v, got %+v", r, other)
+	} else if other := sample(`Sample(Count(Row(f=1)), fraction=0.3, seed=7)`); !reflect.DeepEqual(r, other) {
+		t.Fatalf("expected %+v, got %+v", r, other)
+	}
+	if r := sample(`Sample(Intersect(Row(f=1), Row(f=2)), fraction=0.3, seed=8)`); r.Count != 100 {
+		t.Fatalf("unexpected sample: %+v", r)
+	}
+
+	// Uneven shards have a variance.
+	if r := sample(`Sample(Row(f=3), fraction=0.5, seed=1)`); r.Variance == 0 || r.StdErr == 0 || r.StdErr > 100 {
+		t.Fatalf("unexpected sample: %+v", r)
+	}
+
+	// TopN() finds its candidates in the sampled shards.
+	want := []pilosa.Pair{{ID: 1, Count: 100}, {ID: 2, Count: 100}}
+	if r := sample(`Sample(TopN(f, Row(f=2), n=2), fraction=0.5, seed=3)`); r.Shards == 0 || r.Count != 0 || !reflect.DeepEqual(r.Pairs, want) || r.Variance != 0 {
+		t.Fatalf("unexpected sample: %+v", r)
+	} else if other := sample(`TopN(f, Sample(Row(f=2), fraction=0.5, seed=3), n=2)`); !reflect.DeepEqual(r, other) {
+		t.Fatalf("expected %+v, got %+v", r, other)
+	}
+
+	// The result is labeled as an estimate.
+	if buf, err := json.Marshal(sample(`Sample(Row(f=1), fraction=1)`)); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(string(buf), `"estimate":true,"count":200`) {
+		t.Fatalf("unexpected JSON: %s", buf)
+	}
+
+	for _, query := range []string{
+		`Store(Sample(Row(f=1)), f=10)`,
+		`Intersect(Sample(Row(f=1)), Row(f=2))`,
+		`Sample(Sample(Row(f=1)))`,
+		`Sample(Row(f=1), fraction=0)`,
+		`Sample(Row(f=1), fraction=2)`,
+	} {
+		if _, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: query}); err == nil {
+			t.Fatalf("expected error for %s", query)
+		}
+	}
+}
+
+func TestExecutor_Execute_Decimal(t *testing.T) {
+	c := test.MustRunCluster(t, 2, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplicaN(2))})
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "amount", pilosa.OptFieldTypeInt(-10000, 100000), pilosa.OptFieldScale(2))
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "n", pilosa.OptFieldTypeInt(0, 100))
+
+	query := func(q string) (interface{}, error) {
+		res, err := c[1].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: q})
+		if err != nil {
+			return nil, err
+		}
+		return res.Results[0], nil
+	}
+
+	if _, err := query(`Set(1, amount=19.99) Set(2, amount=-0.5) Set(` + strconv.Itoa(ShardWidth) + `, amount=20) Set(3, amount=0.00001)`); err == nil || !strings.Contains(err.Error(), pilosa.ErrDecimalScale.Error()) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := query(`Set(1, amount=19.99) Set(2, amount=-0.5) Set(` + strconv.Itoa(ShardWidth) + `, amount=20)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Values are stored in units of the scale on every replica.
+	for i := range c {
+		if value, exists, err := c[i].Server.Holder().Field("i", "amount").Value(1); err != nil {
+			t.Fatal(err)
+		} else if !exists || value != 1999 {
+			t.Fatalf("node %d: unexpected value: %d, %v", i, value, exists)
+		}
+	}
+
+	for _, tt := range []struct {
+		query   string
+		columns []uint64
+	}{
+		{query: `Row(amount > 19.99)`, columns: []uint64{ShardWidth}},
+		{query: `Row(amount >= 19.99)`, columns: []uint64{1, ShardWidth}},
+		{query: `Row(amount < 0)`, columns: []uint64{2}},
+		{query: `Row(amount == -0.50)`, columns: []uint64{2}},
+		{query: `Row(amount >< [-1, 19.99])`, columns: []uint64{1, 2}},
+	} {
+		if res, err := query(tt.query); err != nil {
+			t.Fatal(err)
+		} else if columns := res.(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, tt.columns) {
+			t.Fatalf("%s: unexpected columns: %v", tt.query, columns)
+		}
+	}
+
+	for _, tt := range []struct {
+		query string
+		json  string
+	}{
+		{query: `Sum(field=amount)`, json: `{"value":39.49,"count":3}`},
+		{query: `Min(field=amount)`, json: `{"value":-0.50,"count":1}`},
+		{query: `Max(field=amount)`, json: `{"value":20.00,"count":1}`},
+		{query: `IncrementFieldValue(field=amount, column=2, amount=0.75)`, json: `{"value":0.25,"count":1}`},
+	} {
+		if res, err := query(tt.query); err != nil {
+			t.Fatal(err)
+		} else if buf, err := json.Marshal(res); err != nil {
+			t.Fatal(err)
+		} else if string(buf) != tt.json {
+			t.Fatalf("%s: unexpected result: %s", tt.query, buf)
+		}
+	}
+
+	// Fields without a scale only take integers.
+	if _, err := query(`Set(1, n=1.5)`); err == nil || !strings.Contains(err.Error(), pilosa.ErrDecimalScale.Error()) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := query(`Row(n > 1.5)`); err == nil || !strings.Contains(err.Error(), pilosa.ErrDecimalScale.Error()) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExecutor_Execute_Handle(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+	for _, col := range []uint64{1, 2, ShardWidth + 1, 2*ShardWidth + 1, 3*ShardWidth + 2} {
+		c.Query(t, "i", fmt.Sprintf(`Set(%d, f=10)`, col))
+	}
+	for _, col := range []uint64{2, ShardWidth + 1, 3*ShardWidth + 2} {
+		c.Query(t, "i", fmt.Sprintf(`Set(%d, f=20)`, col))
+	}
+	c.Query(t, "i", fmt.Sprintf(`Set(%d, f=30)`, 3*ShardWidth+2))
+
+	query := func(node int, q, storeAs string) (pilosa.QueryResponse, error) {
+		return c[node].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: q, Session: "s1", StoreAs: storeAs})
+	}
+
+	if _, err := query(0, `Row(f=10)`, "h"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Clearing a bit doesn't change the stored result.
+	c.Query(t, "i", `Clear(1, f=10)`)
+	if res, err := query(0, `Count(Handle(name="h"))`, ""); err != nil {
+		t.Fatal(err)
+	} else if n := res.Results[0].(uint64); n != 5 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+
+	// Refine the result under the same handle.
+	if res, err := query(0, `Intersect(Handle(name="h"), Row(f=20))`, "h"); err != nil {
+		t.Fatal(err)
+	} else if cols := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, []uint64{2, ShardWidth + 1, 3*ShardWidth + 2}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+	if res, err := query(0, `Intersect(Handle(name="h"), Row(f=30))`, ""); err != nil {
+		t.Fatal(err)
+	} else if cols := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, []uint64{3*ShardWidth + 2}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+
+	// Handles are scoped to their session.
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Count(Handle(name="h"))`, Session: "s2"}); errors.Cause(err) != pilosa.ErrResultHandleNotFound {
+		t.Fatalf("expected handle not found, got %v", err)
+	}
+	if _, err := query(0, `Count(Row(f=10))`, "h"); err == nil {
+		t.Fatal("expected error storing non-row result")
+	}
+
+	if err := c[0].API.DeleteSession(context.Background(), "s1"); err != nil {
+		t.Fatal(err)
+	}
+	for i := range c {
+		if _, err := query(i, `Count(Handle(name="h"))`, ""); errors.Cause(err) != pilosa.ErrResultHandleNotFound {
+			t.Fatalf("expected handle not found on node %d after delete, got %v", i, err)
+		}
+	}
+}
+
+// Ensure an executor created without a server executes queries on its
+// holder, and applies its options.
+func TestNewExecutor(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+	hldr.SetBit("i", "f", 10, 1)
+	hldr.SetBit("i", "f", 10, 2*ShardWidth)
+
+	if _, err := pilosa.NewExecutor(hldr.Holder, nil, pilosa.OptExecutorWorkerPoolSize(0)); err == nil {
+		t.Fatal("expected error for an empty worker pool")
+	}
+
+	e, err := pilosa.NewExecutor(hldr.Holder, nil, pilosa.OptExecutorMaxWritesPerRequest(1), pilosa.OptExecutorWorkerPoolSize(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	ctx := context.Background()
+	if _, err := e.Execute(ctx, "i", "Set(1, f=1) Set(2, f=1)"); errors.Cause(err) != pilosa.ErrTooManyWrites {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := e.Execute(ctx, "j", "Row(f=10)"); errors.Cause(err) != pilosa.ErrIndexNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := e.Execute(ctx, "i", "Row(f=10"); err == nil {
+		t.Fatal("expected parse error")
+	}
+
+	results, err := e.Execute(ctx, "i", "Row(f=10) TopN(f)")
+	if err != nil {
+		t.Fatal(err)
+	} else if row, ok := results[0].Row(); !ok || !reflect.DeepEqual(row.Columns(), []uint64{1, 2 * ShardWidth}) {
+		t.Fatalf("unexpected row: %v", results[0].Value())
+	} else if _, ok := results[0].Count(); ok {
+		t.Fatal("expected row not to be a count")
+	} else if pairs, ok := results[1].Pairs(); !ok || !reflect.DeepEqual(pairs, []pilosa.Pair{{ID: 10, Count: 2}}) {
+		t.Fatalf("unexpected pairs: %v", results[1].Value())
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pilosa/pilosa/v2/roaring"
+	"github.com/pkg/errors"
+)
+
+// fragmentColumns returns the columns which have a bit in any row of the
+// storage of a fragment, as the bits of row 0.
+func fragmentColumns(b *roaring.Bitmap, shardWidth uint64) *roaring.Bitmap {
+	var rows []*roaring.Bitmap
+	containersPerRow := shardWidth >> 16
+	last := ^uint64(0)
+	citer, _ := b.Containers.Iterator(0)
+	for citer.Next() {
+		key, _ := citer.Value()
+		if row := key / containersPerRow; row != last {
+			rows = append(rows, b.OffsetRange(0, row*shardWidth, (row+1)*shardWidth))
+			last = row
+		}
+	}
+
+	columns := roaring.NewBitmap()
+	columns.UnionInPlace(rows...)
+	return columns
+}
+
+// existenceRoaring returns the roaring data of the existence bits of the
+// columns which have a bit in roaring data imported into a fragment.
+func existenceRoaring(data []byte, shardWidth uint64) ([]byte, error) {
+	b := roaring.NewBitmap()
+	if err := b.UnmarshalBinary(data); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling")
+	}
+	var buf bytes.Buffer
+	if _, err := fragmentColumns(b, shardWidth).WriteTo(&buf); err != nil {
+		return nil, errors.Wrap(err, "marshaling")
+	}
+	return buf.Bytes(), nil
+}
+
+// existenceData returns the roaring data of the existence bits of the
+// columns which have a bit in a fragment.
+func (f *fragment) existenceData() ([]byte, error) {
+	other, _ := f.readSnapshot()
+	defer f.releaseReadSnapshot()
+
+	// The data is written before the snapshot is released, since the
+	// containers of the columns are shared with the storage.
+	var buf bytes.Buffer
+	if _, err := fragmentColumns(other.storage, f.shardWidth).WriteTo(&buf); err != nil {
+		return nil, errors.Wrap(err, "marshaling")
+	}
+	return buf.Bytes(), nil
+}
+
+// enableExistence turns existence tracking on for the index, creating its
+// existence field, if it doesn't track existence.
+func (i *Index) enableExistence() error {
+	if i.existenceField() != nil {
+		return nil
+	}
+	f, err := i.createFieldIfNotExists(existenceFieldName, FieldOptions{CacheType: CacheTypeNone, CacheSize: 0})
+	if err != nil {
+		return errors.Wrap(err, "creating existence field")
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.existenceFld = f
+	i.trackExistence = true
+	if err := i.saveMeta(); err != nil {
+		return errors.Wrap(err, "saving meta")
+	}
+	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
+	}
+	return nil
+}
+
+// rebuildExistence sets the existence bit of every column which has a bit in
+// any field of the index on this node, for indexes which didn't track
+// existence or whose data was written without it. Existence tracking is
+// turned on if it was off. Existence bits are never cleared, so columns
+// deleted from every field before the rebuild are kept. It returns the
+// number of columns which exist on this node.
+func (i *Index) rebuildExistence(ctx context.Context) (uint64, error) {
+	if err := i.enableExistence(); err != nil {
+		return 0, err
+	}
+	ef := i.existenceField()
+
+	for _, f := range i.Fields() {
+		if f.Name() == existenceFieldName {
+			continue
+		}
+		for _, v := range f.views() {
+			for _, frag := range v.allFragments() {
+				if err := ctx.Err(); err != nil {
+					return 0, err
+				}
+				data, err := frag.existenceData()
+				if err != nil {
+					return 0, errors.Wrapf(err, "reading columns of %s/%s/%d", f.Name(), v.name, frag.shard)
+				}
+				if err := ef.importRoaring(ctx, data, frag.shard, viewStandard, false, ""); err != nil {
+					return 0, errors.Wrapf(err, "importing existence of shard %d", frag.shard)
+				}
+			}
+		}
+	}
+
+	var n uint64
+	if v := ef.view(viewStandard); v != nil {
+		for _, frag := range v.allFragments() {
+			n += frag.row(0).Count()
+		}
+	}
+	i.logger.Printf("rebuilt existence of index %s: %d columns", i.name, n)
+	i.Stats.Count("existenceRebuild", 1, 1.0)
+	return n, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2/roaring"
+)
+
+func TestExistenceRoaring(t *testing.T) {
+	b := roaring.NewBitmap(
+		3,
+		ShardWidth+3,
+		ShardWidth+70000,
+		5*ShardWidth+1,
+		1000*ShardWidth+ShardWidth-1,
+	)
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := existenceRoaring(buf.Bytes(), ShardWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := roaring.NewBitmap()
+	if err := columns.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := columns.Slice(), []uint64{1, 3, 70000, ShardWidth - 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// Ensure rebuilding existence turns it on for an index which didn't track
+// existence, and sets the columns of every field.
+func TestIndex_RebuildExistence(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := h.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := idx.CreateField("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := idx.CreateField("v", OptFieldTypeInt(-100, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bit := range [][2]uint64{{1, 3}, {7, 3}, {1000, ShardWidth + 5}, {2, 2*ShardWidth + 9}} {
+		if _, err := f.SetBit(bit[0], bit[1], nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := v.SetValue(4, -7); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.SetValue(ShardWidth+5, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := idx.rebuildExistence(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 4 {
+		t.Fatalf("expected 4 columns, got %d", n)
+	} else if !idx.Options().TrackExistence {
+		t.Fatal("expected existence to be tracked")
+	}
+
+	existence := func() []uint64 {
+		t.Helper()
+		row, err := h.Index("i").existenceField().Row(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return row.Columns()
+	}
+	want := []uint64{3, 4, ShardWidth + 5, 2*ShardWidth + 9}
+	if got := existence(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Existence tracking survives a restart.
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := h.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if idx := h.Index("i"); idx.existenceField() == nil {
+		t.Fatal("expected existence field after reopen")
+	}
+	if got := existence(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after reopen: got %v, want %v", got, want)
+	}
+}
+
+// BenchmarkImportExistence measures the cost of setting the existence of the
+// columns of imported bits.
+func BenchmarkImportExistence(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	rowIDs := make([]uint64, 100000)
+	columnIDs := make([]uint64, len(rowIDs))
+	for i := range rowIDs {
+		rowIDs[i] = uint64(rnd.Intn(100))
+		columnIDs[i] = uint64(rnd.Intn(ShardWidth))
+	}
+
+	for _, trackExistence := range []bool{false, true} {
+		b.Run(fmt.Sprintf("TrackExistence=%v", trackExistence), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				h := newHolder()
+				if err := h.Open(); err != nil {
+					b.Fatal(err)
+				}
+				idx, err := h.CreateIndex("i", IndexOptions{TrackExistence: trackExistence})
+				if err != nil {
+					b.Fatal(err)
+				}
+				f, err := idx.CreateField("f")
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				if err := importExistenceColumns(idx, columnIDs); err != nil {
+					b.Fatal(err)
+				} else if err := f.Import(rowIDs, columnIDs, nil); err != nil {
+					b.Fatal(err)
+				}
+
+				b.StopTimer()
+				h.Close()
+			}
+		})
+	}
+}
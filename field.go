@@ -70,6 +70,9 @@ type Field struct {
 
 	viewMap map[string]*view
 
+	// Number of columns in each shard, inherited from the index.
+	shardWidth uint64
+
 	// Row attribute storage and cache
 	rowAttrStore AttrStore
 
@@ -87,9 +90,27 @@ type Field struct {
 	// Shards with data on any node in the cluster, according to this node.
 	remoteAvailableShards *roaring.Bitmap
 
+	// Highest row ID set on any other node in the cluster, according to this node.
+	remoteMaxRowID uint64
+
+	// Shards written on this node which could not be announced to the other
+	// nodes. They are announced again on the next write to them.
+	unannouncedShards map[uint64]struct{}
+
 	logger logger.Logger
 
 	snapshotQueue chan *fragment
+	opIDOptions   operationIDOptions
+
+	// Change stream of the field on this node, if it tracks changes.
+	changeOptions changeStreamOptions
+	changes       *changeStream
+
+	// Times of the retained snapshots of the field, which are listed on
+	// first use. retainedMu guards the snapshots directory.
+	retainedMu     sync.Mutex
+	retainedTimes  []time.Time
+	retainedListed bool
 
 	// Instantiates new translation store on open.
 	OpenTranslateStore OpenTranslateStoreFunc
@@ -107,6 +128,63 @@ func OptFieldKeys() FieldOption {
 	}
 }
 
+// OptFieldClampIncrements is a functional option on FieldOptions used to
+// specify that increments of an int field are clamped to its range.
+func OptFieldClampIncrements() FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.ClampIncrements = true
+		return nil
+	}
+}
+
+// OptFieldScale is a functional option on FieldOptions used to specify the
+// number of decimal places of an int field. Values of the field are stored
+// as integers in units of 10^-scale, so min and max of the field are given
+// in those units as well.
+func OptFieldScale(scale int64) FieldOption {
+	return func(fo *FieldOptions) error {
+		if scale < 0 || scale > maxScale {
+			return ErrInvalidScale
+		}
+		fo.Scale = scale
+		return nil
+	}
+}
+
+// OptFieldSnapshotRetention is a functional option on FieldOptions used to
+// specify that periodic snapshots of the fragments of the field are retained
+// for d, so that queries can read the field as of a past time.
+func OptFieldSnapshotRetention(d time.Duration) FieldOption {
+	return func(fo *FieldOptions) error {
+		if d < 0 {
+			return errors.New("snapshot retention must not be negative")
+		}
+		fo.SnapshotRetention = d
+		return nil
+	}
+}
+
+// OptFieldTrackChanges is a functional option on FieldOptions used to
+// specify that each node records the changes of the rows of the field in a
+// change stream. Int fields don't track changes.
+func OptFieldTrackChanges() FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.TrackChanges = true
+		return nil
+	}
+}
+
+// OptFieldShareTimeViews is a functional option on FieldOptions used to
+// specify that the day views of a time field share the containers which are
+// identical to the union of those of their hour views on disk, instead of
+// storing copies. It requires a time quantum with day and hour units.
+func OptFieldShareTimeViews() FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.ShareTimeViews = true
+		return nil
+	}
+}
+
 // OptFieldTypeDefault is a functional option on FieldOptions
 // used to set the field type and cache setting to the default values.
 func OptFieldTypeDefault() FieldOption {
@@ -228,6 +306,8 @@ func newField(path, index, name string, opts FieldOption) (*Field, error) {
 
 		viewMap: make(map[string]*view),
 
+		shardWidth: ShardWidth,
+
 		rowAttrStore: nopStore,
 
 		broadcaster: NopBroadcaster,
@@ -237,7 +317,9 @@ func newField(path, index, name string, opts FieldOption) (*Field, error) {
 
 		remoteAvailableShards: roaring.NewBitmap(),
 
-		logger: logger.NopLogger,
+		logger:        logger.NopLogger,
+		opIDOptions:   defaultOperationIDOptions(),
+		changeOptions: defaultChangeStreamOptions(),
 
 		OpenTranslateStore: OpenInMemTranslateStore,
 	}
@@ -279,6 +361,60 @@ func (f *Field) AddRemoteAvailableShards(b *roaring.Bitmap) error {
 	return f.saveAvailableShards()
 }
 
+// hasShard returns true if the shard has data on any node in the cluster,
+// according to this node.
+func (f *Field) hasShard(shard uint64) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.remoteAvailableShards.Contains(shard) {
+		return true
+	}
+	for _, view := range f.viewMap {
+		if view.Fragment(shard) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// shardUnannounced returns true if a write to the shard must announce it,
+// because the shard is unknown to this node or a previous announcement of it
+// failed.
+func (f *Field) shardUnannounced(shard uint64) bool {
+	f.mu.RLock()
+	_, ok := f.unannouncedShards[shard]
+	f.mu.RUnlock()
+	return ok || !f.hasShard(shard)
+}
+
+// announceShard makes every node aware of a shard of the field which was
+// unknown to this node before a write to it. It is called by the node which
+// handles the write, before the write is acknowledged, so that a read on any
+// node which follows the write includes the shard: the broadcast of the node
+// which created the fragment may time out, and the periodic status sync is
+// only a safety net. The write fails if the shard can't be announced, and the
+// shard is announced again by the next write to it.
+func (f *Field) announceShard(shard uint64) error {
+	f.mu.Lock()
+	if f.unannouncedShards == nil {
+		f.unannouncedShards = make(map[uint64]struct{})
+	}
+	f.unannouncedShards[shard] = struct{}{}
+	f.mu.Unlock()
+
+	if err := f.AddRemoteAvailableShards(roaring.NewBitmap(shard)); err != nil {
+		return errors.Wrap(err, "adding available shard")
+	}
+	if err := f.broadcaster.SendSync(&CreateShardMessage{Index: f.index, Field: f.name, Shard: shard}); err != nil {
+		return errors.Wrap(err, "broadcasting create shard")
+	}
+
+	f.mu.Lock()
+	delete(f.unannouncedShards, shard)
+	f.mu.Unlock()
+	return nil
+}
+
 // mergeRemoteAvailableShards merges the set of available shards into the current known set.
 func (f *Field) mergeRemoteAvailableShards(b *roaring.Bitmap) {
 	f.mu.Lock()
@@ -286,6 +422,36 @@ func (f *Field) mergeRemoteAvailableShards(b *roaring.Bitmap) {
 	f.remoteAvailableShards = f.remoteAvailableShards.Union(b)
 }
 
+// MaxRowID returns the highest row ID set in the field on any node in the
+// cluster, as known by this node. Clearing bits or rows does not lower the
+// value, so it may over-report after deletes. Zero is returned for empty
+// fields and for int fields, which do not have rows.
+func (f *Field) MaxRowID() uint64 {
+	if f.Type() == FieldTypeInt {
+		return 0
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	max := f.remoteMaxRowID
+	for _, view := range f.viewMap {
+		if id := view.maxRowID(); id > max {
+			max = id
+		}
+	}
+	return max
+}
+
+// AddRemoteMaxRowID raises the known max row ID of the field to id.
+func (f *Field) AddRemoteMaxRowID(id uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id > f.remoteMaxRowID {
+		f.remoteMaxRowID = id
+	}
+}
+
 // loadAvailableShards reads remoteAvailableShards data for the field, if any.
 func (f *Field) loadAvailableShards() error {
 	bm := roaring.NewBitmap()
@@ -428,6 +594,7 @@ func (f *Field) Open() error {
 		if err := f.openViews(); err != nil {
 			return errors.Wrap(err, "opening views")
 		}
+		f.fillSharedViews()
 
 		f.logger.Debugf("open row attribute store for index/field: %s/%s", f.index, f.name)
 		if err := f.rowAttrStore.Open(); err != nil {
@@ -554,6 +721,12 @@ func (f *Field) loadMeta() error {
 	f.options.TimeQuantum = TimeQuantum(pb.TimeQuantum)
 	f.options.Keys = pb.Keys
 	f.options.NoStandardView = pb.NoStandardView
+	f.options.ClampIncrements = pb.ClampIncrements
+	f.options.Scale = pb.Scale
+	f.options.SnapshotRetention = time.Duration(pb.SnapshotRetention)
+	f.options.TrackChanges = pb.TrackChanges
+	f.options.ShareTimeViews = pb.ShareTimeViews
+	f.options.TimeQuantumSince = decodeTimeQuantumSince(f.options.TimeQuantum, pb.TimeQuantumSince)
 
 	return nil
 }
@@ -617,6 +790,8 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		f.options.BitDepth = opt.BitDepth
 		f.options.TimeQuantum = ""
 		f.options.Keys = opt.Keys
+		f.options.ClampIncrements = opt.ClampIncrements
+		f.options.Scale = opt.Scale
 
 		// Create new bsiGroup.
 		bsig := &bsiGroup{
@@ -644,8 +819,9 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		f.options.BitDepth = 0
 		f.options.Keys = opt.Keys
 		f.options.NoStandardView = opt.NoStandardView
+		f.options.TimeQuantumSince = opt.TimeQuantumSince
 		// Set the time quantum.
-		if err := f.setTimeQuantum(opt.TimeQuantum); err != nil {
+		if err := f.setTimeQuantum(opt.TimeQuantum, time.Time{}); err != nil {
 			f.Close()
 			return errors.Wrap(err, "setting time quantum")
 		}
@@ -662,6 +838,20 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 	default:
 		return errors.New("invalid field type")
 	}
+	f.options.SnapshotRetention = opt.SnapshotRetention
+
+	if opt.TrackChanges && opt.Type == FieldTypeInt {
+		return errors.New("int fields don't track changes")
+	}
+	f.options.TrackChanges = opt.TrackChanges
+	if f.options.TrackChanges && f.changes == nil {
+		f.changes = newChangeStream(f.changeOptions)
+	}
+
+	if opt.ShareTimeViews && (opt.Type != FieldTypeTime || !sharesTimeViews(opt.TimeQuantum)) {
+		return ErrSharedTimeViewsQuantum
+	}
+	f.options.ShareTimeViews = opt.ShareTimeViews
 
 	return nil
 }
@@ -763,18 +953,43 @@ func (f *Field) TimeQuantum() TimeQuantum {
 	return f.options.TimeQuantum
 }
 
-// setTimeQuantum sets the time quantum for the field.
-func (f *Field) setTimeQuantum(q TimeQuantum) error {
+// setTimeQuantum sets the time quantum of the field. The views of units added
+// to the quantum only hold the data written from t on, so range queries only
+// use them from the start of their first period at or after t. A zero t means
+// that added units hold all the data of the field, as when it is created.
+func (f *Field) setTimeQuantum(q TimeQuantum, t time.Time) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	// Validate input.
 	if !q.Valid() {
 		return ErrInvalidTimeQuantum
+	} else if f.options.ShareTimeViews && !sharesTimeViews(q) {
+		return ErrSharedTimeViewsQuantum
+	}
+
+	// Keep the start times of the units which remain and record those of the
+	// added units. The map is replaced rather than changed since it is shared
+	// by copies of the options.
+	var since map[string]time.Time
+	for _, unit := range q {
+		u := string(unit)
+		start, ok := f.options.TimeQuantumSince[u]
+		if !ok {
+			if t.IsZero() || strings.ContainsRune(string(f.options.TimeQuantum), unit) {
+				continue
+			}
+			start = timeUnitSince(t, unit)
+		}
+		if since == nil {
+			since = make(map[string]time.Time)
+		}
+		since[u] = start
 	}
 
 	// Update value on field.
 	f.options.TimeQuantum = q
+	f.options.TimeQuantumSince = since
 
 	// Persist meta data to disk.
 	if err := f.saveMeta(); err != nil {
@@ -784,6 +999,15 @@ func (f *Field) setTimeQuantum(q TimeQuantum) error {
 	return nil
 }
 
+// viewsByTimeRange returns the views to traverse to query a time range of the
+// field.
+func (f *Field) viewsByTimeRange(start, end time.Time) []string {
+	f.mu.RLock()
+	q, since := f.options.TimeQuantum, f.options.TimeQuantumSince
+	f.mu.RUnlock()
+	return viewsByTimeRangeSince(viewStandard, start, end, q, since)
+}
+
 // RowTime gets the row at the particular time with the granularity specified by
 // the quantum.
 func (f *Field) RowTime(rowID uint64, time time.Time, quantum string) (*Row, error) {
@@ -877,23 +1101,51 @@ func (f *Field) createViewIfNotExistsBase(name string) (*view, bool, error) {
 
 func (f *Field) newView(path, name string) *view {
 	view := newView(path, f.index, f.name, name, f.options)
+	view.shardWidth = f.shardWidth
 	view.logger = f.logger
 	view.rowAttrStore = f.rowAttrStore
 	view.stats = f.Stats
 	view.broadcaster = f.broadcaster
 	view.snapshotQueue = f.snapshotQueue
+	view.opIDOptions = f.opIDOptions
+	view.changes = f.changes
+	if f.options.ShareTimeViews {
+		if isDayView(name) {
+			view.sharedChildren = func(shard uint64) []*fragment { return f.sharedChildFragments(name, shard) }
+		} else if isHourView(name) {
+			view.sharedParent = func(shard uint64) *fragment { return f.sharedParentFragment(name, shard) }
+		}
+	}
 	return view
 }
 
-// deleteView removes the view from the field.
+// deleteView removes the view from the field. Queries which still hold the
+// view read its fragments as empty.
 func (f *Field) deleteView(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	view := f.viewMap[name]
 	if view == nil {
 		return ErrInvalidView
 	}
 
+	// The fragments of the day view keep the containers which they share
+	// with those of a deleted hour view.
+	if view.sharedParent != nil {
+		for _, frag := range view.allFragments() {
+			if p := f.unprotectedSharedParentFragment(name, frag.shard); p != nil {
+				release, err := p.holdShared()
+				if err != nil {
+					return err
+				}
+				defer release()
+			}
+		}
+	}
+
 	// Close data files before deletion.
-	if err := view.close(); err != nil {
+	if err := view.discard(); err != nil {
 		return errors.Wrap(err, "closing view")
 	}
 
@@ -907,6 +1159,34 @@ func (f *Field) deleteView(name string) error {
 	return nil
 }
 
+// primaryView returns the name of the view which holds the data of the field
+// when it isn't written with timestamps.
+func (f *Field) primaryView() string {
+	if f.Type() == FieldTypeInt {
+		return viewBSIGroupPrefix + f.name
+	}
+	return viewStandard
+}
+
+// validateViewName returns an error if a view with the given name can't hold
+// data of the field: the primary view, or a time view of a unit of the time
+// quantum of a time field.
+func (f *Field) validateViewName(name string) error {
+	if name == f.primaryView() {
+		return nil
+	}
+	q := f.TimeQuantum()
+	if q == "" || !strings.HasPrefix(name, viewStandard+"_") {
+		return errors.Errorf("invalid view %q for %s field", name, f.Type())
+	}
+	units := map[int]rune{4: 'Y', 6: 'M', 7: 'W', 8: 'D', 10: 'H'}
+	part := strings.TrimPrefix(name, viewStandard+"_")
+	if _, err := timeOfView(name, false); err != nil || !strings.ContainsRune(string(q), units[len(part)]) {
+		return errors.Errorf("invalid view %q for time quantum %s", name, q)
+	}
+	return nil
+}
+
 // Row returns a row of the standard view.
 // It seems this method is only being used by the test
 // package, and the fact that it's only allowed on
@@ -923,44 +1203,61 @@ func (f *Field) Row(rowID uint64) (*Row, error) {
 	return view.row(rowID), nil
 }
 
-// SetBit sets a bit on a view within the field.
+// SetBit sets a bit on a view within the field. If a timestamp is specified,
+// the bit is also set on each time view of the quantum. The fragments of all
+// views are resolved before any of them is written.
 func (f *Field) SetBit(rowID, colID uint64, t *time.Time) (changed bool, err error) {
-	viewName := viewStandard
+	names := make([]string, 0, 1+len(f.TimeQuantum()))
 	if !f.options.NoStandardView {
-		// Retrieve view. Exit if it doesn't exist.
-		view, err := f.createViewIfNotExists(viewName)
-		if err != nil {
-			return changed, errors.Wrap(err, "creating view")
-		}
+		names = append(names, viewStandard)
+	}
+	if t != nil {
+		names = append(names, viewsByTime(viewStandard, *t, f.TimeQuantum())...)
+	}
+
+	frags, err := f.createFragmentsIfNotExists(names, colID/f.shardWidth)
+	if err != nil {
+		return changed, err
+	}
 
-		// Set non-time bit.
-		if v, err := view.setBit(rowID, colID); err != nil {
-			return changed, errors.Wrap(err, "setting on view")
-		} else if v {
-			changed = v
+	// Each fragment has its own lock and op log, and a write may wait for
+	// the snapshot queue, so the fragments are written one at a time.
+	for i, frag := range frags {
+		if c, err := frag.setBit(rowID, colID); err != nil {
+			return changed, errors.Wrapf(err, "setting on view %s", names[i])
+		} else if c {
+			changed = true
 		}
 	}
+	return changed, nil
+}
 
-	// Exit early if no timestamp is specified.
-	if t == nil {
-		return changed, nil
+// createFragmentsIfNotExists returns the fragments of shard in the named
+// views, creating the views and fragments which don't exist. The fragments
+// which exist are looked up under a single lock of the field.
+func (f *Field) createFragmentsIfNotExists(names []string, shard uint64) ([]*fragment, error) {
+	frags := make([]*fragment, len(names))
+	f.mu.RLock()
+	for i, name := range names {
+		if v := f.viewMap[name]; v != nil {
+			frags[i] = v.Fragment(shard)
+		}
 	}
+	f.mu.RUnlock()
 
-	// If a timestamp is specified then set bits across all views for the quantum.
-	for _, subname := range viewsByTime(viewName, *t, f.TimeQuantum()) {
-		view, err := f.createViewIfNotExists(subname)
+	for i, frag := range frags {
+		if frag != nil {
+			continue
+		}
+		v, err := f.createViewIfNotExists(names[i])
 		if err != nil {
-			return changed, errors.Wrapf(err, "creating view %s", subname)
+			return nil, errors.Wrapf(err, "creating view %s", names[i])
 		}
-
-		if c, err := view.setBit(rowID, colID); err != nil {
-			return changed, errors.Wrapf(err, "setting on view %s", subname)
-		} else if c {
-			changed = true
+		if frags[i], err = v.CreateFragmentIfNotExists(shard); err != nil {
+			return nil, errors.Wrapf(err, "creating fragment of view %s", names[i])
 		}
 	}
-
-	return changed, nil
+	return frags, nil
 }
 
 // ClearBit clears a bit within the field.
@@ -1116,6 +1413,46 @@ func (f *Field) SetValue(columnID uint64, value int64) (changed bool, err error)
 	return view.setValue(columnID, bsig.BitDepth, baseValue)
 }
 
+// IncrementValue adds delta to the value of a column and returns the new
+// value. A column without a value is incremented from zero. If the new value
+// is outside of the range of the field then it is clamped to the range if the
+// field clamps increments, or an error is returned otherwise.
+func (f *Field) IncrementValue(columnID uint64, delta int64) (value int64, err error) {
+	bsig := f.bsiGroup(f.name)
+	if bsig == nil {
+		return 0, ErrBSIGroupNotFound
+	}
+
+	// The new value is only known once the fragment is locked, so ensure the
+	// bit depth covers the entire range of the field up front.
+	requiredBitDepth := bitDepthInt64(bsig.Min - bsig.Base)
+	if v := bitDepthInt64(bsig.Max - bsig.Base); v > requiredBitDepth {
+		requiredBitDepth = v
+	}
+	f.mu.Lock()
+	clamp := f.options.ClampIncrements
+	if requiredBitDepth > bsig.BitDepth {
+		bsig.BitDepth = requiredBitDepth
+		f.options.BitDepth = requiredBitDepth
+		if err := f.saveMeta(); err != nil {
+			f.mu.Unlock()
+			return 0, errors.Wrap(err, "increasing bsi bit depth")
+		}
+	}
+	f.mu.Unlock()
+
+	view, err := f.createViewIfNotExists(viewBSIGroupPrefix + f.name)
+	if err != nil {
+		return 0, errors.Wrap(err, "creating view")
+	}
+
+	baseValue, err := view.incrementValue(columnID, bsig.BitDepth, delta, -bsig.Base, bsig.Min-bsig.Base, bsig.Max-bsig.Base, clamp)
+	if err != nil {
+		return 0, err
+	}
+	return baseValue + bsig.Base, nil
+}
+
 // Sum returns the sum and count for a field.
 // An optional filtering row can be provided.
 func (f *Field) Sum(filter *Row, name string) (sum, count int64, err error) {
@@ -1212,48 +1549,42 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 		}
 	}
 
-	// Determine quantum if timestamps are set.
-	q := f.TimeQuantum()
-	if hasTime(timestamps) {
-		if q == "" {
-			return errors.New("time quantum not set in field")
-		} else if options.Clear {
-			return errors.New("import clear is not supported with timestamps")
-		}
+	timestamps, err := f.validateImport(rowIDs, columnIDs, timestamps, options)
+	if err != nil {
+		return err
 	}
+	q := f.TimeQuantum()
 
-	fieldType := f.Type()
-
-	// Split import data by fragment.
+	// Split import data by fragment. The views of the bits with timestamps
+	// in the same hour are only computed once.
+	standard := []string{viewStandard}
+	viewsByHour := make(map[timeViewsKey][]string)
 	dataByFragment := make(map[importKey]importData)
 	for i := range rowIDs {
 		rowID, columnID := rowIDs[i], columnIDs[i]
 
-		// Bool-specific data validation.
-		if fieldType == FieldTypeBool && rowID > 1 {
-			return errors.New("bool field imports only support values 0 and 1")
-		}
-
 		var timestamp *time.Time
 		if len(timestamps) > i {
 			timestamp = timestamps[i]
 		}
 
-		var standard []string
-		if timestamp == nil {
-			standard = []string{viewStandard}
-		} else {
-			standard = viewsByTime(viewStandard, *timestamp, q)
-			if !f.options.NoStandardView {
-				// In order to match the logic of `SetBit()`, we want bits
-				// with timestamps to write to both time and standard views.
-				standard = append(standard, viewStandard)
+		names := standard
+		if timestamp != nil {
+			key := newTimeViewsKey(*timestamp)
+			if names = viewsByHour[key]; names == nil {
+				names = viewsByTime(viewStandard, *timestamp, q)
+				if !f.options.NoStandardView {
+					// In order to match the logic of `SetBit()`, we want bits
+					// with timestamps to write to both time and standard views.
+					names = append(names, viewStandard)
+				}
+				viewsByHour[key] = names
 			}
 		}
 
-		// Attach bit to each standard view.
-		for _, name := range standard {
-			key := importKey{View: name, Shard: columnID / ShardWidth}
+		// Attach bit to each view.
+		for _, name := range names {
+			key := importKey{View: name, Shard: columnID / f.shardWidth}
 			data := dataByFragment[key]
 			data.RowIDs = append(data.RowIDs, rowID)
 			data.ColumnIDs = append(data.ColumnIDs, columnID)
@@ -1261,11 +1592,16 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 		}
 	}
 
-	// Import into each fragment.
+	// Import into each fragment, creating each view once.
+	views := make(map[string]*view)
 	for key, data := range dataByFragment {
-		view, err := f.createViewIfNotExists(key.View)
-		if err != nil {
-			return errors.Wrap(err, "creating view")
+		view := views[key.View]
+		if view == nil {
+			var err error
+			if view, err = f.createViewIfNotExists(key.View); err != nil {
+				return errors.Wrap(err, "creating view")
+			}
+			views[key.View] = view
 		}
 
 		frag, err := view.CreateFragmentIfNotExists(key.Shard)
@@ -1273,7 +1609,9 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 			return errors.Wrap(err, "creating fragment")
 		}
 
-		if err := frag.bulkImport(data.RowIDs, data.ColumnIDs, options); err != nil {
+		if err := frag.importOnce(options.OperationID, func() error {
+			return frag.bulkImport(data.RowIDs, data.ColumnIDs, options)
+		}); err != nil {
 			return err
 		}
 	}
@@ -1283,6 +1621,10 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 
 // importValue bulk imports range-encoded value data.
 func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportOptions) error {
+	if err := f.validateImportValues(columnIDs, values); err != nil {
+		return err
+	}
+
 	viewName := viewBSIGroupPrefix + f.name
 	// Get the bsiGroup so we know bitDepth.
 	bsig := f.bsiGroup(f.name)
@@ -1302,11 +1644,6 @@ func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportO
 	dataByFragment := make(map[importKey]importValueData)
 	for i := range columnIDs {
 		columnID, value := columnIDs[i], values[i]
-		if value > bsig.Max {
-			return fmt.Errorf("%v, columnID=%v, value=%v", ErrBSIGroupValueTooHigh, columnID, value)
-		} else if value < bsig.Min {
-			return fmt.Errorf("%v, columnID=%v, value=%v", ErrBSIGroupValueTooLow, columnID, value)
-		}
 		if value > max {
 			max = value
 		}
@@ -1316,7 +1653,7 @@ func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportO
 
 		// Attach value to each bsiGroup view.
 		for _, name := range []string{viewName} {
-			key := importKey{View: name, Shard: columnID / ShardWidth}
+			key := importKey{View: name, Shard: columnID / f.shardWidth}
 			data := dataByFragment[key]
 			data.ColumnIDs = append(data.ColumnIDs, columnID)
 			data.Values = append(data.Values, value)
@@ -1363,7 +1700,9 @@ func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportO
 			baseValues[i] = value - bsig.Base
 		}
 
-		if err := frag.importValue(data.ColumnIDs, baseValues, requiredDepth, options.Clear); err != nil {
+		if err := frag.importOnce(options.OperationID, func() error {
+			return frag.importValue(data.ColumnIDs, baseValues, requiredDepth, options.Clear)
+		}); err != nil {
 			return err
 		}
 	}
@@ -1371,7 +1710,7 @@ func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportO
 	return nil
 }
 
-func (f *Field) importRoaring(ctx context.Context, data []byte, shard uint64, viewName string, clear bool) error {
+func (f *Field) importRoaring(ctx context.Context, data []byte, shard uint64, viewName string, clear bool, opID string) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Field.importRoaring")
 	defer span.Finish()
 
@@ -1389,7 +1728,9 @@ func (f *Field) importRoaring(ctx context.Context, data []byte, shard uint64, vi
 		return errors.Wrap(err, "creating fragment")
 	}
 
-	if err := frag.importRoaring(ctx, data, clear); err != nil {
+	if err := frag.importOnce(opID, func() error {
+		return frag.importRoaring(ctx, data, clear)
+	}); err != nil {
 		return err
 	}
 
@@ -1404,9 +1745,10 @@ func (p fieldSlice) Less(i, j int) bool { return p[i].Name() < p[j].Name() }
 
 // FieldInfo represents schema information for a field.
 type FieldInfo struct {
-	Name    string       `json:"name"`
-	Options FieldOptions `json:"options"`
-	Views   []*ViewInfo  `json:"views,omitempty"`
+	Name     string       `json:"name"`
+	Options  FieldOptions `json:"options"`
+	MaxRowID uint64       `json:"maxRowID,omitempty"`
+	Views    []*ViewInfo  `json:"views,omitempty"`
 }
 
 type fieldInfoSlice []*FieldInfo
@@ -1427,6 +1769,33 @@ type FieldOptions struct {
 	CacheType      string      `json:"cacheType,omitempty"`
 	Type           string      `json:"type,omitempty"`
 	TimeQuantum    TimeQuantum `json:"timeQuantum,omitempty"`
+
+	// ClampIncrements clamps the result of IncrementFieldValue() on an int
+	// field to the range of the field instead of returning an error.
+	ClampIncrements bool `json:"clampIncrements,omitempty"`
+
+	// Scale is the number of decimal places of an int field. Values are
+	// stored as integers in units of 10^-Scale.
+	Scale int64 `json:"scale,omitempty"`
+
+	// SnapshotRetention is how long periodic snapshots of the fragments of
+	// the field are retained for queries as of a past time. Zero disables
+	// retained snapshots.
+	SnapshotRetention time.Duration `json:"-"`
+
+	// TrackChanges records the changes of the rows of the field in a change
+	// stream on each node.
+	TrackChanges bool `json:"trackChanges,omitempty"`
+
+	// ShareTimeViews stores the containers of the day views of a time field
+	// which are identical to the union of those of their hour views only
+	// once, in the hour views.
+	ShareTimeViews bool `json:"shareTimeViews,omitempty"`
+
+	// TimeQuantumSince holds the time from which the views of each unit
+	// added to the time quantum of an existing time field hold all data.
+	// Range queries don't use the views of a unit for earlier periods.
+	TimeQuantumSince map[string]time.Time `json:"timeQuantumSince,omitempty"`
 }
 
 // applyDefaultOptions returns a new FieldOptions object
@@ -1452,17 +1821,54 @@ func encodeFieldOptions(o *FieldOptions) *internal.FieldOptions {
 		return nil
 	}
 	return &internal.FieldOptions{
-		Type:           o.Type,
-		CacheType:      o.CacheType,
-		CacheSize:      o.CacheSize,
-		Base:           o.Base,
-		BitDepth:       uint64(o.BitDepth),
-		Min:            o.Min,
-		Max:            o.Max,
-		TimeQuantum:    string(o.TimeQuantum),
-		Keys:           o.Keys,
-		NoStandardView: o.NoStandardView,
+		Type:              o.Type,
+		CacheType:         o.CacheType,
+		CacheSize:         o.CacheSize,
+		Base:              o.Base,
+		BitDepth:          uint64(o.BitDepth),
+		Min:               o.Min,
+		Max:               o.Max,
+		TimeQuantum:       string(o.TimeQuantum),
+		Keys:              o.Keys,
+		NoStandardView:    o.NoStandardView,
+		ClampIncrements:   o.ClampIncrements,
+		Scale:             o.Scale,
+		SnapshotRetention: int64(o.SnapshotRetention),
+		TrackChanges:      o.TrackChanges,
+		ShareTimeViews:    o.ShareTimeViews,
+		TimeQuantumSince:  encodeTimeQuantumSince(o.TimeQuantum, o.TimeQuantumSince),
+	}
+}
+
+// encodeTimeQuantumSince encodes the start times of the units of q as Unix
+// times in nanoseconds, in the order of the units, with zero for units which
+// hold all data.
+func encodeTimeQuantumSince(q TimeQuantum, since map[string]time.Time) []int64 {
+	if len(since) == 0 {
+		return nil
+	}
+	a := make([]int64, len(q))
+	for i, unit := range q {
+		if t, ok := since[string(unit)]; ok {
+			a[i] = t.UnixNano()
+		}
+	}
+	return a
+}
+
+// decodeTimeQuantumSince is the inverse of encodeTimeQuantumSince.
+func decodeTimeQuantumSince(q TimeQuantum, a []int64) map[string]time.Time {
+	var since map[string]time.Time
+	for i, unit := range q {
+		if i >= len(a) || a[i] == 0 {
+			continue
+		}
+		if since == nil {
+			since = make(map[string]time.Time)
+		}
+		since[string(unit)] = time.Unix(0, a[i]).UTC()
 	}
+	return since
 }
 
 // MarshalJSON marshals FieldOptions to JSON such that
@@ -1472,66 +1878,134 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 	switch o.Type {
 	case FieldTypeSet:
 		return json.Marshal(struct {
-			Type      string `json:"type"`
-			CacheType string `json:"cacheType"`
-			CacheSize uint32 `json:"cacheSize"`
-			Keys      bool   `json:"keys"`
+			Type              string `json:"type"`
+			CacheType         string `json:"cacheType"`
+			CacheSize         uint32 `json:"cacheSize"`
+			Keys              bool   `json:"keys"`
+			SnapshotRetention string `json:"snapshotRetention,omitempty"`
+			TrackChanges      bool   `json:"trackChanges,omitempty"`
 		}{
 			o.Type,
 			o.CacheType,
 			o.CacheSize,
 			o.Keys,
+			o.snapshotRetention(),
+			o.TrackChanges,
 		})
 	case FieldTypeInt:
 		return json.Marshal(struct {
-			Type     string `json:"type"`
-			Base     int64  `json:"base"`
-			BitDepth uint   `json:"bitDepth"`
-			Min      int64  `json:"min"`
-			Max      int64  `json:"max"`
-			Keys     bool   `json:"keys"`
+			Type              string      `json:"type"`
+			Base              int64       `json:"base"`
+			BitDepth          uint        `json:"bitDepth"`
+			Min               json.Number `json:"min"`
+			Max               json.Number `json:"max"`
+			Keys              bool        `json:"keys"`
+			ClampIncrements   bool        `json:"clampIncrements,omitempty"`
+			Scale             int64       `json:"scale,omitempty"`
+			SnapshotRetention string      `json:"snapshotRetention,omitempty"`
 		}{
 			o.Type,
 			o.Base,
 			o.BitDepth,
-			o.Min,
-			o.Max,
+			json.Number(FormatDecimal(o.Min, o.Scale)),
+			json.Number(FormatDecimal(o.Max, o.Scale)),
 			o.Keys,
+			o.ClampIncrements,
+			o.Scale,
+			o.snapshotRetention(),
 		})
 	case FieldTypeTime:
 		return json.Marshal(struct {
-			Type           string      `json:"type"`
-			TimeQuantum    TimeQuantum `json:"timeQuantum"`
-			Keys           bool        `json:"keys"`
-			NoStandardView bool        `json:"noStandardView"`
+			Type              string               `json:"type"`
+			TimeQuantum       TimeQuantum          `json:"timeQuantum"`
+			Keys              bool                 `json:"keys"`
+			NoStandardView    bool                 `json:"noStandardView"`
+			SnapshotRetention string               `json:"snapshotRetention,omitempty"`
+			TrackChanges      bool                 `json:"trackChanges,omitempty"`
+			ShareTimeViews    bool                 `json:"shareTimeViews,omitempty"`
+			TimeQuantumSince  map[string]time.Time `json:"timeQuantumSince,omitempty"`
 		}{
 			o.Type,
 			o.TimeQuantum,
 			o.Keys,
 			o.NoStandardView,
+			o.snapshotRetention(),
+			o.TrackChanges,
+			o.ShareTimeViews,
+			o.TimeQuantumSince,
 		})
 	case FieldTypeMutex:
 		return json.Marshal(struct {
-			Type      string `json:"type"`
-			CacheType string `json:"cacheType"`
-			CacheSize uint32 `json:"cacheSize"`
-			Keys      bool   `json:"keys"`
+			Type              string `json:"type"`
+			CacheType         string `json:"cacheType"`
+			CacheSize         uint32 `json:"cacheSize"`
+			Keys              bool   `json:"keys"`
+			SnapshotRetention string `json:"snapshotRetention,omitempty"`
+			TrackChanges      bool   `json:"trackChanges,omitempty"`
 		}{
 			o.Type,
 			o.CacheType,
 			o.CacheSize,
 			o.Keys,
+			o.snapshotRetention(),
+			o.TrackChanges,
 		})
 	case FieldTypeBool:
 		return json.Marshal(struct {
-			Type string `json:"type"`
+			Type              string `json:"type"`
+			SnapshotRetention string `json:"snapshotRetention,omitempty"`
+			TrackChanges      bool   `json:"trackChanges,omitempty"`
 		}{
 			o.Type,
+			o.snapshotRetention(),
+			o.TrackChanges,
 		})
 	}
 	return nil, errors.New("invalid field type")
 }
 
+// snapshotRetention returns the snapshot retention of the field as JSON, which
+// is empty if snapshots aren't retained.
+func (o *FieldOptions) snapshotRetention() string {
+	if o.SnapshotRetention <= 0 {
+		return ""
+	}
+	return o.SnapshotRetention.String()
+}
+
+// UnmarshalJSON unmarshals FieldOptions from JSON. The min and max of an
+// int field are decimals with the scale of the field.
+func (o *FieldOptions) UnmarshalJSON(data []byte) error {
+	type fieldOptions FieldOptions
+	v := struct {
+		*fieldOptions
+		Min               json.Number `json:"min,omitempty"`
+		Max               json.Number `json:"max,omitempty"`
+		SnapshotRetention string      `json:"snapshotRetention,omitempty"`
+	}{fieldOptions: (*fieldOptions)(o)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var err error
+	if v.SnapshotRetention != "" {
+		if o.SnapshotRetention, err = time.ParseDuration(v.SnapshotRetention); err != nil {
+			return errors.Wrap(err, "parsing snapshotRetention")
+		}
+	}
+	if v.Min != "" {
+		if o.Min, err = ParseDecimal(string(v.Min), o.Scale); err != nil {
+			return errors.Wrap(err, "parsing min")
+		}
+	}
+	if v.Max != "" {
+		if o.Max, err = ParseDecimal(string(v.Max), o.Scale); err != nil {
+			return errors.Wrap(err, "parsing max")
+		}
+	}
+	return nil
+}
+
 // List of bsiGroup types.
 const (
 	bsiGroupTypeInt = "int"
@@ -170,6 +170,14 @@ func TestField_DeleteView(t *testing.T) {
 	} else if view == nil {
 		t.Fatal("expected view")
 	}
+	frag, err := view.CreateFragmentIfNotExists(0)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := frag.setBit(1, 2); err != nil {
+		t.Fatal(err)
+	} else if err := frag.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
 
 	err = f.deleteView(viewName)
 	if err != nil {
@@ -178,6 +186,13 @@ func TestField_DeleteView(t *testing.T) {
 
 	if f.view(viewName) != nil {
 		t.Fatal("view still exists in field")
+	} else if _, err := os.Stat(view.path); !os.IsNotExist(err) {
+		t.Fatalf("view directory still exists: %v", err)
+	}
+
+	// A query which still holds the fragment reads it as empty.
+	if n := frag.row(1).Count(); n != 0 {
+		t.Fatalf("unexpected count of deleted fragment: %d", n)
 	}
 
 	// Recreate view with same name, verify that the old view was not reused.
@@ -287,7 +302,7 @@ func TestField_SetTimeQuantum(t *testing.T) {
 	defer f.Close()
 
 	// Set & retrieve time quantum.
-	if err := f.setTimeQuantum(TimeQuantum("YMDH")); err != nil {
+	if err := f.setTimeQuantum(TimeQuantum("YMDH"), time.Time{}); err != nil {
 		t.Fatal(err)
 	} else if q := f.TimeQuantum(); q != TimeQuantum("YMDH") {
 		t.Fatalf("unexpected quantum: %s", q)
@@ -299,13 +314,32 @@ func TestField_SetTimeQuantum(t *testing.T) {
 	} else if q := f.TimeQuantum(); q != TimeQuantum("YMDH") {
 		t.Fatalf("unexpected quantum (reopen): %s", q)
 	}
+
+	// Units added to the quantum are only used from their next period on.
+	since := map[string]time.Time{"W": time.Date(2019, time.February, 4, 0, 0, 0, 0, time.UTC)}
+	if err := f.setTimeQuantum(TimeQuantum("YMWDH"), time.Date(2019, time.January, 30, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	} else if s := f.Options().TimeQuantumSince; !reflect.DeepEqual(s, since) {
+		t.Fatalf("unexpected since: %v", s)
+	} else if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if s := f.Options().TimeQuantumSince; !reflect.DeepEqual(s, since) {
+		t.Fatalf("unexpected since (reopen): %v", s)
+	}
+
+	// Removing a unit forgets when it was added.
+	if err := f.setTimeQuantum(TimeQuantum("YMDH"), time.Now()); err != nil {
+		t.Fatal(err)
+	} else if s := f.Options().TimeQuantumSince; s != nil {
+		t.Fatalf("unexpected since: %v", s)
+	}
 }
 
 func TestField_RowTime(t *testing.T) {
 	f := MustOpenField(OptFieldTypeTime(TimeQuantum("")))
 	defer f.Close()
 
-	if err := f.setTimeQuantum(TimeQuantum("YMDH")); err != nil {
+	if err := f.setTimeQuantum(TimeQuantum("YMDH"), time.Time{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -519,3 +553,91 @@ func TestBSIGroup_importValue(t *testing.T) {
 		}
 	}
 }
+
+// Ensure an import mixing timestamps sets each bit in the views of its own
+// timestamp, and that SetBit sets the same views.
+func TestField_ImportMixedTimestamps(t *testing.T) {
+	f := MustOpenField(OptFieldTypeTime(TimeQuantum("YMDH")))
+	defer f.Close()
+	g := MustOpenField(OptFieldTypeTime(TimeQuantum("YMDH")))
+	defer g.Close()
+
+	t0 := time.Date(2019, time.January, 2, 3, 0, 0, 0, time.UTC)
+	t1 := time.Date(2019, time.January, 2, 4, 30, 0, 0, time.UTC)
+	t2 := time.Date(2019, time.February, 1, 0, 0, 0, 0, time.UTC)
+	rowIDs := []uint64{1, 1, 1, 1, 2, 1}
+	columnIDs := []uint64{1, 2, ShardWidth + 3, 4, 5, 6}
+	timestamps := []*time.Time{&t0, &t1, &t0, &t2, &t1, nil}
+	if err := f.Import(rowIDs, columnIDs, timestamps); err != nil {
+		t.Fatal(err)
+	}
+	for i := range rowIDs {
+		if _, err := g.SetBit(rowIDs[i], columnIDs[i], timestamps[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for view, rows := range map[string]map[uint64][]uint64{
+		viewStandard:                 {1: {1, 2, 4, 6, ShardWidth + 3}, 2: {5}},
+		viewStandard + "_2019":       {1: {1, 2, 4, ShardWidth + 3}, 2: {5}},
+		viewStandard + "_201901":     {1: {1, 2, ShardWidth + 3}, 2: {5}},
+		viewStandard + "_201902":     {1: {4}},
+		viewStandard + "_20190102":   {1: {1, 2, ShardWidth + 3}, 2: {5}},
+		viewStandard + "_20190201":   {1: {4}},
+		viewStandard + "_2019010203": {1: {1, ShardWidth + 3}},
+		viewStandard + "_2019010204": {1: {2}, 2: {5}},
+		viewStandard + "_2019020100": {1: {4}},
+	} {
+		for _, field := range []*TestField{f, g} {
+			v := field.view(view)
+			if v == nil {
+				t.Fatalf("missing view %s", view)
+			}
+			for rowID, exp := range rows {
+				if cols := v.row(rowID).Columns(); !reflect.DeepEqual(cols, exp) {
+					t.Fatalf("view %s row %d: unexpected columns: %v", view, rowID, cols)
+				}
+			}
+		}
+	}
+	for _, field := range []*TestField{f, g} {
+		if n := len(field.views()); n != 9 {
+			t.Fatalf("unexpected number of views: %d", n)
+		}
+	}
+}
+
+func BenchmarkField_SetBitTime(b *testing.B) {
+	f := MustOpenField(OptFieldTypeTime(TimeQuantum("YMDH")))
+	defer f.Close()
+
+	ts := time.Date(2019, time.January, 2, 3, 0, 0, 0, time.UTC)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t := ts.Add(time.Duration(i%48) * time.Hour)
+		if _, err := f.SetBit(uint64(i%16), uint64(i), &t); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkField_ImportTime(b *testing.B) {
+	f := MustOpenField(OptFieldTypeTime(TimeQuantum("YMDH")))
+	defer f.Close()
+
+	const n = 10000
+	rowIDs, columnIDs, timestamps := make([]uint64, n), make([]uint64, n), make([]*time.Time, n)
+	ts := time.Date(2019, time.January, 2, 3, 0, 0, 0, time.UTC)
+	for i := range rowIDs {
+		t := ts.Add(time.Duration(i%48) * time.Hour)
+		rowIDs[i], columnIDs[i], timestamps[i] = uint64(i%16), uint64(i*7), &t
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := f.Import(rowIDs, columnIDs, timestamps); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
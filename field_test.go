@@ -18,6 +18,7 @@ import (
 	"io/ioutil"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pilosa/pilosa/v2"
@@ -227,3 +228,64 @@ func TestField_AvailableShards(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+// Ensure the max row ID is tracked across views, shards, and remote nodes.
+func TestField_MaxRowID(t *testing.T) {
+	idx := test.MustOpenIndex()
+	defer idx.Close()
+
+	f, err := idx.CreateField("f", pilosa.OptFieldTypeTime("YMD"))
+	if err != nil {
+		t.Fatal(err)
+	} else if n := f.MaxRowID(); n != 0 {
+		t.Fatalf("unexpected max row ID on empty field: %d", n)
+	}
+
+	ts := time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := f.SetBit(5, 100, &ts); err != nil {
+		t.Fatal(err)
+	} else if n := f.MaxRowID(); n != 5 {
+		t.Fatalf("unexpected max row ID after set: %d", n)
+	}
+
+	// Imports update the watermark on each affected shard.
+	if err := f.Import([]uint64{2, 12}, []uint64{1, ShardWidth * 3}, nil); err != nil {
+		t.Fatal(err)
+	} else if n := f.MaxRowID(); n != 12 {
+		t.Fatalf("unexpected max row ID after import: %d", n)
+	}
+
+	// Clearing does not lower the watermark.
+	if _, err := f.ClearBit(12, ShardWidth*3); err != nil {
+		t.Fatal(err)
+	} else if n := f.MaxRowID(); n != 12 {
+		t.Fatalf("unexpected max row ID after clear: %d", n)
+	}
+
+	// Remote values only raise the watermark.
+	f.AddRemoteMaxRowID(7)
+	if n := f.MaxRowID(); n != 12 {
+		t.Fatalf("unexpected max row ID after lower remote: %d", n)
+	}
+	f.AddRemoteMaxRowID(20)
+	if n := f.MaxRowID(); n != 20 {
+		t.Fatalf("unexpected max row ID after higher remote: %d", n)
+	}
+
+	// Reopening recalculates the local watermark from storage.
+	if err := idx.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if n := idx.Field("f").MaxRowID(); n != 5 {
+		t.Fatalf("unexpected max row ID after reopen: %d", n)
+	}
+
+	// Int fields do not have rows.
+	v, err := idx.CreateField("v", pilosa.OptFieldTypeInt(0, 1000))
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := v.SetValue(1, 500); err != nil {
+		t.Fatal(err)
+	} else if n := v.MaxRowID(); n != 0 {
+		t.Fatalf("unexpected max row ID on int field: %d", n)
+	}
+}
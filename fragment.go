@@ -26,6 +26,7 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
+	"math/bits"
 	"os"
 	"sort"
 	"strings"
@@ -71,6 +72,10 @@ const (
 	// copyExt is the file extension used for the temp file used while copying.
 	copyExt = ".copying"
 
+	// compactExt is the file extension used for an in-process op log
+	// compaction.
+	compactExt = ".compacting"
+
 	// cacheExt is the file extension for persisted cache ids.
 	cacheExt = ".cache"
 
@@ -83,6 +88,10 @@ const (
 	// defaultFragmentMaxOpN is the default value for Fragment.MaxOpN.
 	defaultFragmentMaxOpN = 10000
 
+	// defaultFragmentMaxOpRedundancy is the default value for
+	// Fragment.MaxOpRedundancy.
+	defaultFragmentMaxOpRedundancy = 0.5
+
 	// Row ids used for boolean fields.
 	falseRowID = uint64(0)
 	trueRowID  = uint64(1)
@@ -106,6 +115,9 @@ type fragment struct {
 	view  string
 	shard uint64
 
+	// Number of columns in the shard, inherited from the index.
+	shardWidth uint64
+
 	// File-backed storage
 	path               string
 	flags              byte // user-defined flags passed to roaring
@@ -122,12 +134,22 @@ type fragment struct {
 	snapshotCond       sync.Cond
 	snapshotDelays     int
 	snapshotDelayTime  time.Duration
+	compactRequested   bool                // set to true when requesting a compaction, set to false after it's checked
+	compactCheckOps    int                 // number of ops at which the op log is next checked for compaction
+	compactions        int                 // number of op log compactions
+	lastCompaction     *FragmentCompaction // last op log compaction
 
 	// Cache for row counts.
 	CacheType string // passed in by field
 	cache     cache
 	CacheSize uint32
 
+	// Set while the rankings of the cache are out of date after an import,
+	// until they are recalculated in the background. cacheRecalculated is
+	// closed once they are.
+	cacheDirty        bool
+	cacheRecalculated chan struct{}
+
 	// Stats reporting.
 	maxRowID uint64
 
@@ -142,6 +164,12 @@ type fragment struct {
 	// so that they can be mmapped and heap utilization can be kept low.
 	MaxOpN int
 
+	// Ratio of the bit changes in the op log which have no net effect, such
+	// as bits set and then cleared again, above which the op log is
+	// compacted. The op log is checked once it holds a tenth of MaxOpN ops,
+	// and again whenever it doubles. Zero disables compaction.
+	MaxOpRedundancy float64
+
 	// Logger used for out-of-band log entries.
 	Logger logger.Logger
 
@@ -156,6 +184,44 @@ type fragment struct {
 	stats stats.StatsClient
 
 	snapshotQueue chan *fragment
+
+	// IDs of recently applied imports. opMu serializes imports which carry
+	// an operation ID.
+	opMu  sync.Mutex
+	opIDs *operationIDs
+
+	// Bits changed recently, which estimate the cost of read snapshots.
+	writes writeRate
+
+	// Change stream of the field, if it tracks changes.
+	changes *changeStream
+
+	// Time as of which the fragment is known to hold the writes applied to
+	// the other replicas: the last time it was synchronized with them, or
+	// the last time its file was written when it was opened.
+	caughtUpAt time.Time
+
+	// Number of read snapshots sharing the mapped storage, and old mapped
+	// storage which is unmapped once they are released.
+	readSnapshots int
+	unmapQueue    [][]byte
+
+	// Set for the fragments of the day views of a field which shares its
+	// time views, and for those of its hour views. See sharedview.go.
+	sharedChildren func() []*fragment
+	sharedParent   func() *fragment
+
+	// Keys of the containers left out of the data file because they are
+	// shared with the child fragments, and their encoded size. They are
+	// pending until they are rebuilt from the child fragments once these are
+	// open. Containers aren't shared while sharedHolds is positive.
+	sharedKeys    []uint64
+	sharedBytes   int64
+	sharedPending bool
+	sharedHolds   int
+
+	// Set once the fragment is closed, and its mapped storage unmapped.
+	closed bool
 }
 
 // newFragment returns a new instance of Fragment.
@@ -170,10 +236,15 @@ func newFragment(path, index, field, view string, shard uint64, flags byte) *fra
 		CacheType: DefaultCacheType,
 		CacheSize: DefaultCacheSize,
 
-		Logger: logger.NopLogger,
-		MaxOpN: defaultFragmentMaxOpN,
+		shardWidth: ShardWidth,
+
+		Logger:          logger.NopLogger,
+		MaxOpN:          defaultFragmentMaxOpN,
+		MaxOpRedundancy: defaultFragmentMaxOpRedundancy,
 
 		stats: stats.NopStatsClient,
+
+		opIDs: newOperationIDs(defaultOperationIDOptions()),
 	}
 	f.snapshotCond = sync.Cond{L: &f.mu}
 	return f
@@ -238,6 +309,73 @@ func (f *fragment) enqueueSnapshot() {
 	}
 }
 
+// enqueueCacheRecalculation requests that the rankings of the cache be
+// recalculated by the snapshot queue workers, and marks them as out of date
+// until then. The cache is recalculated immediately if there is no queue or
+// it is full. Call this only when the mutex is held.
+func (f *fragment) enqueueCacheRecalculation() {
+	if f.cacheDirty {
+		return
+	}
+	if f.snapshotQueue != nil {
+		select {
+		case f.snapshotQueue <- f:
+			f.cacheDirty = true
+			f.cacheRecalculated = make(chan struct{})
+			f.stats.Gauge("cacheDirty", 1, 1.0)
+			return
+		default:
+		}
+	}
+	f.cache.Recalculate()
+}
+
+// recalculateDirtyCache recalculates the rankings of the cache if they are
+// out of date. Call this only when the mutex is held.
+func (f *fragment) recalculateDirtyCache() {
+	if !f.cacheDirty {
+		return
+	}
+	f.cache.Recalculate()
+	f.cacheRecalculatedNow()
+}
+
+// cacheRecalculatedNow marks the rankings of the cache as up to date, and
+// wakes up the queries waiting for them. Call this only when the mutex is
+// held.
+func (f *fragment) cacheRecalculatedNow() {
+	if !f.cacheDirty {
+		return
+	}
+	f.cacheDirty = false
+	close(f.cacheRecalculated)
+	f.stats.Gauge("cacheDirty", 0, 1.0)
+}
+
+// awaitCacheRecalculation waits up to wait for a pending recalculation of
+// the cache, then recalculates it if it is still out of date. Call this only
+// when the mutex is held.
+func (f *fragment) awaitCacheRecalculation(wait time.Duration) {
+	if !f.cacheDirty {
+		return
+	}
+	if wait > 0 {
+		ch := f.cacheRecalculated
+		timer := time.NewTimer(wait)
+		f.mu.Unlock()
+		select {
+		case <-ch:
+		case <-timer.C:
+		}
+		timer.Stop()
+		f.mu.Lock()
+	}
+	if f.cacheDirty {
+		f.stats.Count("cacheRecalculateOnRead", 1, 1.0)
+		f.recalculateDirtyCache()
+	}
+}
+
 // Open opens the underlying storage.
 func (f *fragment) Open() error {
 	f.mu.Lock()
@@ -250,6 +388,14 @@ func (f *fragment) Open() error {
 			return errors.Wrap(err, "opening storage")
 		}
 
+		// Read the keys of the containers which are rebuilt from the child
+		// fragments once they are open.
+		keys, err := f.readSharedKeys()
+		if err != nil {
+			return errors.Wrap(err, "reading shared keys")
+		}
+		f.sharedKeys, f.sharedPending = keys, len(keys) > 0
+
 		// Fill cache with rows persisted to disk.
 		f.Logger.Debugf("open cache for index/field/view/fragment: %s/%s/%s/%d", f.index, f.field, f.view, f.shard)
 		if err := f.openCache(); err != nil {
@@ -260,13 +406,14 @@ func (f *fragment) Open() error {
 		f.checksums = make(map[int][]byte)
 
 		// Read last bit to determine max row.
-		f.maxRowID = f.storage.Max() / ShardWidth
+		f.maxRowID = f.storage.Max() / f.shardWidth
 		f.stats.Gauge("rows", float64(f.maxRowID), 1.0)
 		return nil
 	}(); err != nil {
 		f.close()
 		return err
 	}
+	f.closed = false
 
 	f.Logger.Debugf("successfully opened index/field/view/fragment: %s/%s/%s/%d", f.index, f.field, f.view, f.shard)
 	return nil
@@ -351,7 +498,11 @@ func (f *fragment) openStorage(unmarshalData bool) error {
 	fi, err := f.file.Stat()
 	if err != nil {
 		return errors.Wrap(err, "statting file before")
-	} else if fi.Size() == 0 {
+	}
+	if f.caughtUpAt.IsZero() {
+		f.caughtUpAt = fi.ModTime()
+	}
+	if fi.Size() == 0 {
 		bi := bufio.NewWriter(f.file)
 		var err error
 		if _, err = f.storage.WriteTo(bi); err != nil {
@@ -367,19 +518,13 @@ func (f *fragment) openStorage(unmarshalData bool) error {
 		f.rowCache = &simpleCache{make(map[uint64]*Row)}
 	} else {
 		// Mmap the underlying file so it can be zero copied.
-		data, err = syswrap.Mmap(int(f.file.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
-		if err == syswrap.ErrMaxMapCountReached {
-			f.Logger.Debugf("maximum number of maps reached, reading file instead")
-			if unmarshalData {
-				data, err = ioutil.ReadAll(file)
-				if err != nil {
-					return errors.Wrap(err, "failure file readall")
-				}
-			}
-		} else if err != nil {
-			return errors.Wrap(err, "mmap failed")
-		} else {
+		var mapped bool
+		if data, mapped, err = mapStorageFile(f.file, fi.Size(), unmarshalData); err != nil {
+			return err
+		} else if mapped {
 			newStorageData = data
+		} else {
+			f.Logger.Debugf("maximum number of maps reached, reading file instead")
 		}
 	}
 
@@ -393,7 +538,7 @@ func (f *fragment) openStorage(unmarshalData bool) error {
 		// early... this is what defer is for.
 		if oldStorageData != nil {
 			defer func() {
-				unmapErr := syswrap.Munmap(oldStorageData)
+				unmapErr := f.unmapStorage(oldStorageData)
 				if unmapErr != nil {
 					f.Logger.Printf("unmap of old storage failed: %s", err)
 				}
@@ -426,7 +571,7 @@ func (f *fragment) openStorage(unmarshalData bool) error {
 		var mappedAny bool
 		mappedAny, lastError = f.storage.RemapRoaringStorage(newStorageData)
 		if oldStorageData != nil {
-			unmapErr := syswrap.Munmap(oldStorageData)
+			unmapErr := f.unmapStorage(oldStorageData)
 			if unmapErr != nil {
 				f.Logger.Printf("unmap of old storage failed: %s", err)
 			}
@@ -491,7 +636,7 @@ func (f *fragment) openCache() error {
 	// Read in all rows by ID.
 	// This will cause them to be added to the cache.
 	for _, id := range pb.IDs {
-		n := f.storage.CountRange(id*ShardWidth, (id+1)*ShardWidth)
+		n := f.storage.CountRange(id*f.shardWidth, (id+1)*f.shardWidth)
 		f.cache.BulkAdd(id, n)
 	}
 	f.cache.Invalidate()
@@ -509,6 +654,24 @@ func (f *fragment) Close() error {
 	return f.close()
 }
 
+// discard closes the fragment before its files are deleted. Queries which
+// still hold the fragment read it as empty, rather than from the unmapped
+// storage.
+func (f *fragment) discard() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for f.snapshotting {
+		f.snapshotCond.Wait()
+	}
+	if err := f.close(); err != nil {
+		return err
+	}
+	f.storage = roaring.NewBitmap()
+	f.rowCache = &simpleCache{make(map[uint64]*Row)}
+	f.unprotectedRebuildCache()
+	return nil
+}
+
 // awaitSnapshot lets us delay until the snapshot gets written, preventing tests
 // from misleadingly showing amazingly fast performance because the snapshots they
 // trigger haven't happened yet.
@@ -543,10 +706,21 @@ func (f *fragment) close() error {
 
 	// Remove checksums.
 	f.checksums = nil
+	f.closed = true
 
 	return nil
 }
 
+// unmapStorage unmaps storage data, unless read snapshots still use it, in
+// which case it is unmapped when they are released. unprotected.
+func (f *fragment) unmapStorage(data []byte) error {
+	if f.readSnapshots > 0 {
+		f.unmapQueue = append(f.unmapQueue, data)
+		return nil
+	}
+	return syswrap.Munmap(data)
+}
+
 // safeClose is unprotected.
 func (f *fragment) safeClose() error {
 	// Flush file, unlock & close.
@@ -567,6 +741,25 @@ func (f *fragment) safeClose() error {
 	return nil
 }
 
+// mapStorageFile returns the first size bytes of a fragment storage file,
+// memory mapped read-only if possible. If the maximum number of maps has been
+// reached, the file is read instead if read is true, and mapped is false.
+func mapStorageFile(file *os.File, size int64, read bool) (data []byte, mapped bool, err error) {
+	data, err = syswrap.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err == syswrap.ErrMaxMapCountReached {
+		if !read {
+			return nil, false, nil
+		}
+		if data, err = ioutil.ReadAll(file); err != nil {
+			return nil, false, errors.Wrap(err, "failure file readall")
+		}
+		return data, false, nil
+	} else if err != nil {
+		return nil, false, errors.Wrap(err, "mmap failed")
+	}
+	return data, true, nil
+}
+
 // closeStorage attempts to close storage, including unmapping the old
 // storage if includeMap is true. This would normally make sense if you're
 // expecting to be done using the fragment, or to reload it. But it's also
@@ -580,7 +773,7 @@ func (f *fragment) closeStorage(includeMap bool) error {
 
 	// Unmap the file.
 	if includeMap && f.storageData != nil {
-		if err := syswrap.Munmap(f.storageData); err != nil {
+		if err := f.unmapStorage(f.storageData); err != nil {
 			return fmt.Errorf("munmap: %s", err)
 		}
 		f.storageData = nil
@@ -605,6 +798,14 @@ func (f *fragment) row(rowID uint64) *Row {
 	return f.unprotectedRow(rowID)
 }
 
+// rowCount returns the number of columns of a row, which is counted from the
+// containers of the row without reading it.
+func (f *fragment) rowCount(rowID uint64) uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+}
+
 // unprotectedRow returns a row from the row cache if available or from storage
 // (updating the cache).
 func (f *fragment) unprotectedRow(rowID uint64) *Row {
@@ -618,6 +819,62 @@ func (f *fragment) unprotectedRow(rowID uint64) *Row {
 	return row
 }
 
+// readSnapshot returns a copy of the fragment which serves reads from the
+// storage as of now, while writes continue on f, and the estimated number of
+// bytes the copy uses: the copy shares the containers of f, and each
+// container which is written while it is held is copied. The number of
+// containers written is estimated from the bits changed in the last second.
+// The copy must be released with releaseReadSnapshot.
+func (f *fragment) readSnapshot() (*fragment, int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// The copy shares the cache, so its rankings must be current.
+	f.recalculateDirtyCache()
+
+	other := &fragment{
+		index:        f.index,
+		field:        f.field,
+		view:         f.view,
+		shard:        f.shard,
+		shardWidth:   f.shardWidth,
+		flags:        f.flags,
+		storage:      f.storage.Snapshot(),
+		CacheType:    f.CacheType,
+		cache:        &lockedCache{mu: &f.mu, cache: f.cache},
+		CacheSize:    f.CacheSize,
+		rowCache:     &simpleCache{make(map[uint64]*Row)},
+		MaxOpN:       f.MaxOpN,
+		Logger:       f.Logger,
+		RowAttrStore: f.RowAttrStore,
+		stats:        f.stats,
+	}
+	other.snapshotCond = sync.Cond{L: &other.mu}
+	f.readSnapshots++
+
+	size := int64(other.storage.Size())
+	if n := int64(f.writes.last(time.Now())) * maxContainerSize; n < size {
+		size = n
+	}
+	return other, size + int64(other.storage.Containers.Size())*snapshotContainerOverhead
+}
+
+// releaseReadSnapshot releases a copy returned by readSnapshot, and unmaps
+// the old storage data once no copy uses it.
+func (f *fragment) releaseReadSnapshot() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.readSnapshots--; f.readSnapshots > 0 {
+		return
+	}
+	for _, data := range f.unmapQueue {
+		if err := syswrap.Munmap(data); err != nil {
+			f.Logger.Printf("unmap of old storage failed: %s", err)
+		}
+	}
+	f.unmapQueue = nil
+}
+
 // rowFromStorage clones a row data out of fragment storage and returns it as a
 // Row object.
 func (f *fragment) rowFromStorage(rowID uint64) *Row {
@@ -628,20 +885,78 @@ func (f *fragment) rowFromStorage(rowID uint64) *Row {
 	// containers which will use copy-on-write semantics. The actual bitmap
 	// and Containers object are new and not shared, but the containers are
 	// shared.
-	data := f.storage.OffsetRange(f.shard*ShardWidth, rowID*ShardWidth, (rowID+1)*ShardWidth)
-
-	row := &Row{
-		segments: []rowSegment{{
+	//
+	// Row segments are always labeled using the package-level ShardWidth so
+	// that rows from indexes with different shard widths can be combined.
+	start := f.shard * f.shardWidth
+	if f.shardWidth <= ShardWidth {
+		data := f.storage.OffsetRange(start, rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+		row := &Row{
+			segments: []rowSegment{{
+				data:     data,
+				shard:    start / ShardWidth,
+				writable: true,
+			}},
+		}
+		row.invalidateCount()
+		return row
+	}
+
+	// Split wide shards into one segment per package-level shard.
+	row := &Row{}
+	for off := uint64(0); off < f.shardWidth; off += ShardWidth {
+		data := f.storage.OffsetRange(start+off, rowID*f.shardWidth+off, rowID*f.shardWidth+off+ShardWidth)
+		if !data.Any() {
+			continue
+		}
+		row.segments = append(row.segments, rowSegment{
 			data:     data,
-			shard:    f.shard,
+			shard:    (start + off) / ShardWidth,
 			writable: true,
-		}},
+		})
 	}
 	row.invalidateCount()
+	return row
+}
+
+// unionRows returns the union of the rows rowIDs. The containers of the rows
+// are combined in a single pass, without reading or caching each row.
+func (f *fragment) unionRows(rowIDs []uint64) *Row {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
+	width := f.shardWidth
+	if width > ShardWidth {
+		width = ShardWidth
+	}
+	start := f.shard * f.shardWidth
+	row := &Row{}
+	ranges := make([]*roaring.Bitmap, len(rowIDs))
+	for off := uint64(0); off < f.shardWidth; off += width {
+		for i, rowID := range rowIDs {
+			ranges[i] = f.storage.OffsetRange(start+off, rowID*f.shardWidth+off, rowID*f.shardWidth+off+width)
+		}
+		data := roaring.NewSliceBitmap()
+		data.UnionInPlace(ranges...)
+		if f.shardWidth > ShardWidth && !data.Any() {
+			continue
+		}
+		row.segments = append(row.segments, rowSegment{
+			data:     data,
+			shard:    (start + off) / ShardWidth,
+			writable: true,
+		})
+	}
+	row.invalidateCount()
 	return row
 }
 
+// containerExponent returns the power of 2 of the number of containers in a
+// row of the fragment.
+func (f *fragment) containerExponent() uint64 {
+	return uint64(bits.TrailingZeros64(f.shardWidth)) - 16
+}
+
 // setBit sets a bit for a given column & row within the fragment.
 // This updates both the on-disk storage and the in-cache bitmap.
 func (f *fragment) setBit(rowID, columnID uint64) (changed bool, err error) {
@@ -706,7 +1021,7 @@ func (f *fragment) unprotectedSetBit(rowID, columnID uint64) (changed bool, err
 	// If we're using a cache, update it. Otherwise skip the
 	// possibly-expensive count operation.
 	if f.CacheType != CacheTypeNone {
-		n := f.storage.CountRange(rowID*ShardWidth, (rowID+1)*ShardWidth)
+		n := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
 		f.cache.Add(rowID, n)
 	}
 	// Drop the rowCache entry; it's wrong, and we don't want to force
@@ -716,12 +1031,54 @@ func (f *fragment) unprotectedSetBit(rowID, columnID uint64) (changed bool, err
 	f.stats.Count("setBit", 1, 0.001)
 
 	// Update row count if they have increased.
+	f.updateMaxRowID(rowID)
+
+	f.recordChange(rowID, ChangeOpSet, 1)
+
+	return changed, nil
+}
+
+// recordChange records a change of a row in the change stream of the field,
+// if it tracks changes.
+func (f *fragment) recordChange(rowID uint64, op string, delta int64) {
+	if f.changes != nil {
+		f.changes.record(f.view, f.shard, rowID, op, delta)
+	}
+}
+
+// updateMaxRowID raises the fragment's max row ID watermark to rowID. The
+// watermark is never lowered when bits are cleared, so it may over-report
+// until the fragment is reopened.
+func (f *fragment) updateMaxRowID(rowID uint64) {
 	if rowID > f.maxRowID {
 		f.maxRowID = rowID
 		f.stats.Gauge("rows", float64(f.maxRowID), 1.0)
 	}
+}
 
-	return changed, nil
+// rowWatermark returns the highest row ID which has been set in the fragment.
+func (f *fragment) rowWatermark() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.maxRowID
+}
+
+// caughtUpAsOf returns the time as of which the fragment is known to hold
+// the writes applied to the other replicas.
+func (f *fragment) caughtUpAsOf() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.caughtUpAt
+}
+
+// setCaughtUp records that the fragment holds the writes applied to the
+// other replicas as of t.
+func (f *fragment) setCaughtUp(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t.After(f.caughtUpAt) {
+		f.caughtUpAt = t
+	}
 }
 
 // clearBit clears a bit for a given column & row within the fragment.
@@ -768,7 +1125,7 @@ func (f *fragment) unprotectedClearBit(rowID, columnID uint64) (changed bool, er
 	// If we're using a cache, update it. Otherwise skip the
 	// possibly-expensive count operation.
 	if f.CacheType != CacheTypeNone {
-		n := f.storage.CountRange(rowID*ShardWidth, (rowID+1)*ShardWidth)
+		n := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
 		f.cache.Add(rowID, n)
 	}
 	// Drop the rowCache entry; it's wrong, and we don't want to force
@@ -777,6 +1134,8 @@ func (f *fragment) unprotectedClearBit(rowID, columnID uint64) (changed bool, er
 
 	f.stats.Count("clearBit", 1, 1.0)
 
+	f.recordChange(rowID, ChangeOpClear, -1)
+
 	return changed, nil
 }
 
@@ -802,30 +1161,45 @@ func (f *fragment) unprotectedSetRow(row *Row, rowID uint64) (changed bool, err
 	// For now we will assume changed is always true.
 	changed = true
 
+	// Count the bits of the row before it is replaced, if the change is
+	// recorded.
+	var before uint64
+	if f.changes != nil {
+		before = f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+	}
+
 	// First container of the row in storage.
-	headContainerKey := rowID << shardVsContainerExponent
+	exp := f.containerExponent()
+	headContainerKey := rowID << exp
 
 	// Remove every existing container in the row.
-	for i := uint64(0); i < (1 << shardVsContainerExponent); i++ {
+	for i := uint64(0); i < (1 << exp); i++ {
 		f.storage.Containers.Remove(headContainerKey + i)
 	}
 
-	// From the given row, get the rowSegment for this shard.
-	seg := row.segment(f.shard)
-	if seg == nil {
-		return changed, nil
+	// Put each container from the row segments covering this shard into
+	// fragment storage.
+	firstKey := (f.shard * f.shardWidth) >> 16
+	lastKey := firstKey + (1 << exp)
+	var set bool
+	for _, seg := range row.segments {
+		citer, _ := seg.data.Containers.Iterator(firstKey)
+		for citer.Next() {
+			k, c := citer.Value()
+			if k >= lastKey {
+				break
+			}
+			f.storage.Containers.Put(headContainerKey+(k-firstKey), c)
+			set = true
+		}
 	}
-
-	// Put each container from rowSegment to fragment storage.
-	citer, _ := seg.data.Containers.Iterator(f.shard << shardVsContainerExponent)
-	for citer.Next() {
-		k, c := citer.Value()
-		f.storage.Containers.Put(headContainerKey+(k%(1<<shardVsContainerExponent)), c)
+	if set {
+		f.updateMaxRowID(rowID)
 	}
 
 	// Update the row in cache.
 	if f.CacheType != CacheTypeNone {
-		n := f.storage.CountRange(rowID*ShardWidth, (rowID+1)*ShardWidth)
+		n := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
 		f.cache.BulkAdd(rowID, n)
 	}
 
@@ -836,6 +1210,11 @@ func (f *fragment) unprotectedSetRow(row *Row, rowID uint64) (changed bool, err
 	f.enqueueSnapshot()
 	f.stats.Count("setRow", 1, 1.0)
 
+	if f.changes != nil {
+		after := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+		f.recordChange(rowID, ChangeOpSetRow, int64(after)-int64(before))
+	}
+
 	return changed, nil
 }
 
@@ -857,11 +1236,19 @@ func (f *fragment) clearRow(rowID uint64) (bool, error) {
 func (f *fragment) unprotectedClearRow(rowID uint64) (changed bool, err error) {
 	changed = false
 
+	// Count the bits of the row before it is cleared, if the change is
+	// recorded.
+	var before uint64
+	if f.changes != nil {
+		before = f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+	}
+
 	// First container of the row in storage.
-	headContainerKey := rowID << shardVsContainerExponent
+	exp := f.containerExponent()
+	headContainerKey := rowID << exp
 
 	// Remove every container in the row.
-	for i := uint64(0); i < (1 << shardVsContainerExponent); i++ {
+	for i := uint64(0); i < (1 << exp); i++ {
 		k := headContainerKey + i
 		// Technically we could bypass the Get() call and only
 		// call Remove(), but the Get() gives us the ability
@@ -881,9 +1268,56 @@ func (f *fragment) unprotectedClearRow(rowID uint64) (changed bool, err error) {
 
 	f.stats.Count("clearRow", 1, 1.0)
 
+	if changed {
+		f.recordChange(rowID, ChangeOpClearRow, -int64(before))
+	}
+
 	return changed, nil
 }
 
+// clearColumn clears every bit of a column within the fragment and returns
+// the number of bits which were cleared. There is no index from columns to
+// rows, so every row with a container covering the column is checked.
+func (f *fragment) clearColumn(columnID uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	mustClose, err := f.reopen()
+	if err != nil {
+		return 0, errors.Wrap(err, "reopening")
+	}
+	if mustClose {
+		defer f.safeClose()
+	}
+
+	// Only visit the containers which hold the column.
+	exp := f.containerExponent()
+	colKey := (columnID % f.shardWidth) >> 16
+	rowIDs := f.unprotectedRows(0, func(rowID, key uint64, c *roaring.Container) (bool, bool) {
+		return key&((1<<exp)-1) == colKey, false
+	})
+
+	var clear []uint64
+	rowSet := make(map[uint64]struct{})
+	for _, rowID := range rowIDs {
+		pos, err := f.pos(rowID, columnID)
+		if err != nil {
+			return 0, errors.Wrap(err, "getting bit pos")
+		}
+		if f.storage.Contains(pos) {
+			clear = append(clear, pos)
+			rowSet[rowID] = struct{}{}
+		}
+	}
+	if len(clear) == 0 {
+		return 0, nil
+	}
+	if err := f.importPositions(nil, clear, rowSet); err != nil {
+		return 0, errors.Wrap(err, "clearing positions")
+	}
+	f.stats.Count("clearColumn", 1, 1.0)
+	return uint64(len(clear)), nil
+}
+
 func (f *fragment) bit(rowID, columnID uint64) (bool, error) {
 	pos, err := f.pos(rowID, columnID)
 	if err != nil {
@@ -892,11 +1326,33 @@ func (f *fragment) bit(rowID, columnID uint64) (bool, error) {
 	return f.storage.Contains(pos), nil
 }
 
+// protectedBit returns whether a bit is set, acquiring the fragment lock.
+func (f *fragment) protectedBit(rowID, columnID uint64) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.bit(rowID, columnID)
+}
+
+// mutexValue returns the row set for a column in a mutex or bool fragment.
+func (f *fragment) mutexValue(columnID uint64) (rowID uint64, found bool, err error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.mutexVector == nil {
+		return 0, false, nil
+	}
+	return f.mutexVector.Get(columnID)
+}
+
 // value uses a column of bits to read a multi-bit value.
 func (f *fragment) value(columnID uint64, bitDepth uint) (value int64, exists bool, err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	return f.unprotectedValue(columnID, bitDepth)
+}
 
+// unprotectedValue uses a column of bits to read a multi-bit value. f.mu must
+// be held.
+func (f *fragment) unprotectedValue(columnID uint64, bitDepth uint) (value int64, exists bool, err error) {
 	// If existence bit is unset then ignore remaining bits.
 	if v, err := f.bit(bsiExistsBit, columnID); err != nil {
 		return 0, false, errors.Wrap(err, "getting existence bit")
@@ -977,6 +1433,11 @@ func (f *fragment) positionsForValue(columnID uint64, bitDepth uint, value int64
 func (f *fragment) setValueBase(columnID uint64, bitDepth uint, value int64, clear bool) (changed bool, err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	return f.unprotectedSetValueBase(columnID, bitDepth, value, clear)
+}
+
+// unprotectedSetValueBase sets or clears a multi-bit value. f.mu must be held.
+func (f *fragment) unprotectedSetValueBase(columnID uint64, bitDepth uint, value int64, clear bool) (changed bool, err error) {
 	mustClose, err := f.reopen()
 	if err != nil {
 		return false, errors.Wrap(err, "reopening")
@@ -1040,6 +1501,48 @@ func (f *fragment) setValueBase(columnID uint64, bitDepth uint, value int64, cle
 	return changed, nil
 }
 
+// incrementValue adds delta to a multi-bit value and returns the new value.
+// A column without a value is incremented from zero, which is zeroValue
+// relative to the base of the field. The read and the write happen under
+// the fragment lock so that concurrent increments are serialized. If the new
+// value is outside of min and max then it is clamped if clamp is true, or an
+// error is returned otherwise.
+func (f *fragment) incrementValue(columnID uint64, bitDepth uint, delta, zeroValue, min, max int64, clamp bool) (value int64, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, exists, err := f.unprotectedValue(columnID, bitDepth)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting value")
+	} else if !exists {
+		value = zeroValue
+	}
+
+	// Detect overflow of the addition before comparing against the range.
+	tooHigh := delta > 0 && value > math.MaxInt64-delta
+	tooLow := delta < 0 && value < math.MinInt64-delta
+	if !tooHigh && !tooLow {
+		value += delta
+		tooHigh, tooLow = value > max, value < min
+	}
+	if tooHigh {
+		if !clamp {
+			return 0, ErrBSIGroupValueTooHigh
+		}
+		value = max
+	} else if tooLow {
+		if !clamp {
+			return 0, ErrBSIGroupValueTooLow
+		}
+		value = min
+	}
+
+	if _, err := f.unprotectedSetValueBase(columnID, bitDepth, value, false); err != nil {
+		return 0, errors.Wrap(err, "setting value")
+	}
+	return value, nil
+}
+
 // importSetValue is a more efficient SetValue just for imports.
 func (f *fragment) importSetValue(columnID uint64, bitDepth uint, value int64, clear bool) (changed int, err error) { // nolint: unparam
 	// Convert value to an unsigned representation.
@@ -1538,11 +2041,11 @@ func (f *fragment) rangeBetweenUnsigned(filter *Row, bitDepth uint, predicateMin
 // pos translates the row ID and column ID into a position in the storage bitmap.
 func (f *fragment) pos(rowID, columnID uint64) (uint64, error) {
 	// Return an error if the column ID is out of the range of the fragment's shard.
-	minColumnID := f.shard * ShardWidth
-	if columnID < minColumnID || columnID >= minColumnID+ShardWidth {
+	minColumnID := f.shard * f.shardWidth
+	if columnID < minColumnID || columnID >= minColumnID+f.shardWidth {
 		return 0, errors.Errorf("column:%d out of bounds", columnID)
 	}
-	return pos(rowID, columnID), nil
+	return pos(rowID, columnID, f.shardWidth), nil
 }
 
 // forEachBit executes fn for every bit set in the fragment.
@@ -1559,7 +2062,7 @@ func (f *fragment) forEachBit(fn func(rowID, columnID uint64) error) error {
 		}
 
 		// Invoke caller's function.
-		err = fn(i/ShardWidth, (f.shard*ShardWidth)+(i%ShardWidth))
+		err = fn(i/f.shardWidth, (f.shard*f.shardWidth)+(i%f.shardWidth))
 	})
 	return err
 }
@@ -1569,7 +2072,7 @@ func (f *fragment) forEachBit(fn func(rowID, columnID uint64) error) error {
 // If opt.FilterValues exist then the row attribute specified by field is matched.
 func (f *fragment) top(opt topOptions) ([]Pair, error) {
 	// Retrieve pairs. If no row ids specified then return from cache.
-	pairs := f.topBitmapPairs(opt.RowIDs)
+	pairs := f.topBitmapPairs(opt.RowIDs, opt.CacheWait)
 
 	// If row ids are provided, we don't want to truncate the result set
 	if len(opt.RowIDs) > 0 {
@@ -1701,7 +2204,7 @@ func (f *fragment) top(opt topOptions) ([]Pair, error) {
 	return r, nil
 }
 
-func (f *fragment) topBitmapPairs(rowIDs []uint64) []bitmapPair {
+func (f *fragment) topBitmapPairs(rowIDs []uint64, cacheWait time.Duration) []bitmapPair {
 	// Don't retrieve from storage if CacheTypeNone.
 	if f.CacheType == CacheTypeNone {
 		return f.cache.Top()
@@ -1710,6 +2213,7 @@ func (f *fragment) topBitmapPairs(rowIDs []uint64) []bitmapPair {
 	if len(rowIDs) == 0 {
 		f.mu.Lock()
 		defer f.mu.Unlock()
+		f.awaitCacheRecalculation(cacheWait)
 		f.cache.Invalidate()
 		return f.cache.Top()
 	}
@@ -1755,6 +2259,10 @@ type topOptions struct {
 	FilterName        string
 	FilterValues      []interface{}
 	TanimotoThreshold uint64
+
+	// How long to wait for a pending background recalculation of the cache
+	// before recalculating it.
+	CacheWait time.Duration
 }
 
 // Checksum returns a checksum for the entire fragment.
@@ -1793,16 +2301,16 @@ func (f *fragment) Blocks() []FragmentBlock {
 	if eof {
 		return nil
 	}
-	blockID := int(v / (HashBlockSize * ShardWidth))
+	blockID := int(v / (HashBlockSize * f.shardWidth))
 	for {
 		// Check for multiple block checksums in a row.
 		if n := f.readContiguousChecksums(&a, blockID); n > 0 {
-			itr.Seek(uint64(blockID+n) * HashBlockSize * ShardWidth)
+			itr.Seek(uint64(blockID+n) * HashBlockSize * f.shardWidth)
 			v, eof = itr.Next()
 			if eof {
 				break
 			}
-			blockID = int(v / (HashBlockSize * ShardWidth))
+			blockID = int(v / (HashBlockSize * f.shardWidth))
 			continue
 		}
 
@@ -1813,7 +2321,7 @@ func (f *fragment) Blocks() []FragmentBlock {
 		// Read all values for the block.
 		for ; ; v, eof = itr.Next() {
 			// Once we hit the next block, save the value for the next iteration.
-			blockID = int(v / (HashBlockSize * ShardWidth))
+			blockID = int(v / (HashBlockSize * f.shardWidth))
 			if blockID != h.blockID || eof {
 				break
 			}
@@ -1859,13 +2367,38 @@ func (f *fragment) readContiguousChecksums(a *[]FragmentBlock, blockID int) (n i
 func (f *fragment) blockData(id int) (rowIDs, columnIDs []uint64) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	f.storage.ForEachRange(uint64(id)*HashBlockSize*ShardWidth, (uint64(id)+1)*HashBlockSize*ShardWidth, func(i uint64) {
-		rowIDs = append(rowIDs, i/ShardWidth)
-		columnIDs = append(columnIDs, i%ShardWidth)
+	f.storage.ForEachRange(uint64(id)*HashBlockSize*f.shardWidth, (uint64(id)+1)*HashBlockSize*f.shardWidth, func(i uint64) {
+		rowIDs = append(rowIDs, i/f.shardWidth)
+		columnIDs = append(columnIDs, i%f.shardWidth)
 	})
 	return rowIDs, columnIDs
 }
 
+// blockDataPage returns at most limit row/column pairs of a block, starting at
+// the fragment position start. If the block contains more pairs, next is the
+// position of the first pair not returned. Otherwise next is zero.
+func (f *fragment) blockDataPage(id int, start uint64, limit int) (rowIDs, columnIDs []uint64, next uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	min := uint64(id) * HashBlockSize * f.shardWidth
+	max := min + HashBlockSize*f.shardWidth
+	if start < min {
+		start = min
+	}
+
+	itr := f.storage.Iterator()
+	itr.Seek(start)
+	for v, eof := itr.Next(); !eof && v < max; v, eof = itr.Next() {
+		if len(rowIDs) == limit {
+			return rowIDs, columnIDs, v
+		}
+		rowIDs = append(rowIDs, v/f.shardWidth)
+		columnIDs = append(columnIDs, v%f.shardWidth)
+	}
+	return rowIDs, columnIDs, 0
+}
+
 // mergeBlock compares the block's bits and computes a diff with another set of block bits.
 // The state of a bit is determined by consensus from all blocks being considered.
 //
@@ -1880,6 +2413,12 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 		}
 	}
 
+	release, err := f.holdSharing()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -1889,13 +2428,13 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 
 	// Limit upper row/column pair.
 	maxRowID := (uint64(id+1) * HashBlockSize) - 1
-	maxColumnID := uint64(ShardWidth) - 1
+	maxColumnID := f.shardWidth - 1
 
 	// Create buffered iterator for local block.
 	itrs := make([]*bufIterator, 1, len(data)+1)
 	itrs[0] = newBufIterator(
 		newLimitIterator(
-			newRoaringIterator(f.storage.Iterator()), maxRowID, maxColumnID,
+			newRoaringIterator(f.storage.Iterator(), f.shardWidth), maxRowID, maxColumnID,
 		),
 	)
 
@@ -1977,14 +2516,14 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 
 	// Set local bits.
 	for i := range sets[0].columnIDs {
-		if _, err := f.unprotectedSetBit(sets[0].rowIDs[i], (f.shard*ShardWidth)+sets[0].columnIDs[i]); err != nil {
+		if _, err := f.unprotectedSetBit(sets[0].rowIDs[i], (f.shard*f.shardWidth)+sets[0].columnIDs[i]); err != nil {
 			return nil, nil, errors.Wrap(err, "setting")
 		}
 	}
 
 	// Clear local bits.
 	for i := range clears[0].columnIDs {
-		if _, err := f.unprotectedClearBit(clears[0].rowIDs[i], (f.shard*ShardWidth)+clears[0].columnIDs[i]); err != nil {
+		if _, err := f.unprotectedClearBit(clears[0].rowIDs[i], (f.shard*f.shardWidth)+clears[0].columnIDs[i]); err != nil {
 			return nil, nil, errors.Wrap(err, "clearing")
 		}
 	}
@@ -1992,6 +2531,27 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 	return sets[1:], clears[1:], nil
 }
 
+// importOnce calls fn to import data into the fragment unless an import with
+// the same operation ID has already been applied, in which case the import
+// is a no-op. Imports without an operation ID are always applied.
+func (f *fragment) importOnce(opID string, fn func() error) error {
+	if opID == "" {
+		return fn()
+	}
+
+	f.opMu.Lock()
+	defer f.opMu.Unlock()
+	if f.opIDs.contains(opID) {
+		f.stats.Count("deduplicatedOps", 1, 1.0)
+		return nil
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	f.opIDs.add(opID)
+	return nil
+}
+
 // bulkImport bulk imports a set of bits and then snapshots the storage.
 // The cache is updated to reflect the new data.
 func (f *fragment) bulkImport(rowIDs, columnIDs []uint64, options *ImportOptions) error {
@@ -2003,6 +2563,14 @@ func (f *fragment) bulkImport(rowIDs, columnIDs []uint64, options *ImportOptions
 	if f.mutexVector != nil && !options.Clear {
 		return f.bulkImportMutex(rowIDs, columnIDs)
 	}
+	if options.Clear {
+		// A clear with a timestamp only applies to the views of its time.
+		release, err := f.holdSharing()
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
 	return f.bulkImportStandard(rowIDs, columnIDs, options)
 }
 
@@ -2067,6 +2635,7 @@ func (f *fragment) importPositions(set, clear []uint64, rowSet map[uint64]struct
 		}
 		f.stats.Count("ImportedN", int64(changedN), 1)
 		f.incrementOpN(changedN)
+		f.updateMaxRowID(f.storage.Max() / f.shardWidth)
 	}
 
 	if len(clear) > 0 {
@@ -2085,7 +2654,7 @@ func (f *fragment) importPositions(set, clear []uint64, rowSet map[uint64]struct
 		delete(f.checksums, int(rowID/HashBlockSize))
 
 		if f.CacheType != CacheTypeNone {
-			n := f.storage.CountRange(rowID*ShardWidth, (rowID+1)*ShardWidth)
+			n := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
 			f.cache.BulkAdd(rowID, n)
 		}
 
@@ -2093,7 +2662,18 @@ func (f *fragment) importPositions(set, clear []uint64, rowSet map[uint64]struct
 	}
 
 	if f.CacheType != CacheTypeNone {
-		f.cache.Recalculate()
+		f.enqueueCacheRecalculation()
+	}
+
+	if f.changes != nil {
+		deltas := make(map[uint64]int64, len(rowSet))
+		for _, pos := range set {
+			deltas[pos/f.shardWidth]++
+		}
+		for _, pos := range clear {
+			deltas[pos/f.shardWidth]--
+		}
+		f.changes.recordRows(f.view, f.shard, ChangeOpImport, deltas)
 	}
 
 	return nil
@@ -2253,9 +2833,14 @@ func (f *fragment) importValue(columnIDs []uint64, values []int64, bitDepth uint
 // https://github.com/RoaringBitmap/RoaringFormatSpec or from pilosa's version
 // of the roaring format. The cache is updated to reflect the new data.
 func (f *fragment) importRoaring(ctx context.Context, data []byte, clear bool) error {
-	rowSize := uint64(1 << shardVsContainerExponent)
+	rowSize := uint64(1) << f.containerExponent()
 	span, ctx := tracing.StartSpanFromContext(ctx, "fragment.importRoaring")
 	defer span.Finish()
+	release, err := f.holdSharing()
+	if err != nil {
+		return err
+	}
+	defer release()
 	span, ctx = tracing.StartSpanFromContext(ctx, "importRoaring.AcquireFragmentLock")
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -2282,12 +2867,29 @@ func (f *fragment) importRoaring(ctx context.Context, data []byte, clear bool) e
 	}
 	// we only set this if we need to update the cache
 	if anyChanged {
-		f.cache.Recalculate()
+		f.enqueueCacheRecalculation()
+	}
+	if !clear && changed > 0 {
+		f.updateMaxRowID(f.storage.Max() / f.shardWidth)
 	}
 
 	span, _ = tracing.StartSpanFromContext(ctx, "importRoaring.incrementOpN")
 	f.incrementOpN(changed)
 	span.Finish()
+
+	if f.changes != nil && changed > 0 {
+		deltas := make(map[uint64]int64, len(rowSet))
+		for rowID, n := range rowSet {
+			if n == 0 {
+				continue
+			} else if clear {
+				deltas[rowID] = -int64(n)
+			} else {
+				deltas[rowID] = int64(n)
+			}
+		}
+		f.changes.recordRows(f.view, f.shard, ChangeOpImport, deltas)
+	}
 	return nil
 }
 
@@ -2299,8 +2901,11 @@ func (f *fragment) incrementOpN(changed int) {
 	}
 	f.opN += changed
 	f.ops++
+	f.writes.add(time.Now(), changed)
 	if f.opN > f.MaxOpN {
 		f.enqueueSnapshot()
+	} else if f.compactionDue() {
+		f.enqueueCompaction()
 	}
 }
 
@@ -2309,9 +2914,10 @@ func (f *fragment) incrementOpN(changed int) {
 // things from the queue. You probably don't want to do this; use
 // enqueueSnapshot/awaitSnapshot.
 func (f *fragment) Snapshot() error {
+	children := f.childFragments()
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	return f.snapshot()
+	return f.snapshotSharing(children)
 }
 
 func track(start time.Time, message string, stats stats.StatsClient, logger logger.Logger) {
@@ -2323,9 +2929,29 @@ func track(start time.Time, message string, stats stats.StatsClient, logger logg
 // protectedSnapshot grabs the lock and unconditionally calls snapshot(). If
 // fromQueue is true, the snapshotting state is also cleared.
 func (f *fragment) protectedSnapshot(fromQueue bool) error {
+	children := f.childFragments()
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	err := f.snapshot()
+	if fromQueue {
+		// Fragments are also queued to recalculate their caches, which may
+		// be all this one was queued for.
+		f.recalculateDirtyCache()
+		// Or to compact their op logs, which a snapshot makes moot. The op
+		// log of a fragment with shared containers is snapshotted instead,
+		// since a compaction reloads its storage from the data file.
+		if f.compactRequested {
+			f.compactRequested = false
+			if !f.snapshotting && len(f.sharedKeys) == 0 {
+				_, err := f.unprotectedCompactOps(f.MaxOpRedundancy)
+				return err
+			}
+			f.snapshotting = true
+		}
+		if !f.snapshotting {
+			return nil
+		}
+	}
+	err := f.snapshotSharing(children)
 	if fromQueue {
 		f.snapshotting = false
 	}
@@ -2333,15 +2959,50 @@ func (f *fragment) protectedSnapshot(fromQueue bool) error {
 }
 
 // snapshot does the actual snapshot operation. it does not check or care
-// about f.snapshotting.
+// about f.snapshotting. Every container is written to the data file.
 func (f *fragment) snapshot() error {
+	return f.snapshotSharing(nil)
+}
+
+// snapshotSharing is snapshot for a fragment of a day view, which leaves
+// the containers it shares with children, the fragments of its hour views,
+// out of the data file.
+func (f *fragment) snapshotSharing(children []*fragment) error {
 	f.totalOpN += int64(f.opN)
 	f.totalOps += int64(f.ops)
 	f.snapshotsTaken++
+	f.compactCheckOps = 0
+
+	// The keys of the shared containers are written before the data file,
+	// with those shared by the previous data file, so that they list those
+	// left out of either.
+	keys, size := f.sharedContainers(children)
+	if len(keys) > 0 || len(f.sharedKeys) > 0 {
+		prev := f.sharedKeys
+		if err := f.writeSharedKeys(mergeSharedKeys(prev, keys)); err != nil {
+			return err
+		}
+		f.sharedKeys = keys
+		if _, err := unprotectedWriteToFragment(f, f.storage); err != nil {
+			f.sharedKeys = mergeSharedKeys(prev, keys)
+			return err
+		}
+		f.sharedBytes = size
+		return f.writeSharedKeys(keys)
+	}
 	_, err := unprotectedWriteToFragment(f, f.storage)
 	return err
 }
 
+// childFragments returns the fragments of the hour views of a fragment of
+// a day view of a field which shares its time views.
+func (f *fragment) childFragments() []*fragment {
+	if f.sharedChildren == nil {
+		return nil
+	}
+	return f.sharedChildren()
+}
+
 // unprotectedWriteToFragment writes the fragment f with bm as the data. It is unprotected, and
 // f.mu must be locked when calling it.
 func unprotectedWriteToFragment(f *fragment, bm *roaring.Bitmap) (n int64, err error) { // nolint: interfacer
@@ -2359,7 +3020,7 @@ func unprotectedWriteToFragment(f *fragment, bm *roaring.Bitmap) (n int64, err e
 
 	// Write storage to snapshot.
 	bw := bufio.NewWriter(file)
-	if n, err = bm.WriteTo(bw); err != nil {
+	if n, err = f.unprotectedFileBitmap(bm).WriteTo(bw); err != nil {
 		return n, fmt.Errorf("snapshot write to: %s", err)
 	}
 
@@ -2396,9 +3057,39 @@ func unprotectedWriteToFragment(f *fragment, bm *roaring.Bitmap) (n int64, err e
 func (f *fragment) RecalculateCache() {
 	f.mu.Lock()
 	f.cache.Recalculate()
+	f.cacheRecalculatedNow()
 	f.mu.Unlock()
 }
 
+// rebuildCache replaces the cache with one built from the rows of the
+// storage, for caches which were lost or are out of date, and writes it to
+// disk.
+func (f *fragment) rebuildCache() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.unprotectedRebuildCache()
+	f.cacheRecalculatedNow()
+	return f.flushCache()
+}
+
+// unprotectedRebuildCache replaces the cache with one built from the rows of
+// the storage.
+func (f *fragment) unprotectedRebuildCache() {
+	switch f.CacheType {
+	case CacheTypeRanked:
+		f.cache = NewRankCache(f.CacheSize)
+	case CacheTypeLRU:
+		f.cache = newLRUCache(f.CacheSize)
+	default:
+		f.cache = globalNopCache
+		return
+	}
+	for _, rowID := range f.unprotectedRows(0) {
+		f.cache.BulkAdd(rowID, f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth))
+	}
+	f.cache.Recalculate()
+}
+
 // FlushCache writes the cache data to disk.
 func (f *fragment) FlushCache() error {
 	f.mu.Lock()
@@ -2459,12 +3150,22 @@ func (f *fragment) writeStorageToArchive(tw *tar.Writer) error {
 	defer file.Close()
 
 	// Retrieve the current file size under lock so we don't read
-	// while an operation is appending to the end.
+	// while an operation is appending to the end. The data file of a
+	// fragment with shared containers doesn't hold them, so its storage is
+	// written instead.
 	var sz int64
+	var shared *bytes.Buffer
 	if err := func() error {
 		f.mu.Lock()
 		defer f.mu.Unlock()
 
+		if len(f.sharedKeys) > 0 {
+			shared = &bytes.Buffer{}
+			_, err := f.storage.WriteTo(shared)
+			sz = int64(shared.Len())
+			return errors.Wrap(err, "writing shared storage")
+		}
+
 		fi, err := file.Stat()
 		if err != nil {
 			return errors.Wrap(err, "statting")
@@ -2486,6 +3187,11 @@ func (f *fragment) writeStorageToArchive(tw *tar.Writer) error {
 		return errors.Wrap(err, "writing header")
 	}
 
+	if shared != nil {
+		_, err := shared.WriteTo(tw)
+		return errors.Wrap(err, "copying shared storage")
+	}
+
 	// Copy the file up to the last known size.
 	// This is done outside the lock because the storage format is append-only.
 	if _, err := io.CopyN(tw, file, sz); err != nil {
@@ -2525,6 +3231,12 @@ func (f *fragment) writeCacheToArchive(tw *tar.Writer) error {
 
 // ReadFrom reads a data file from r and loads it into the fragment.
 func (f *fragment) ReadFrom(r io.Reader) (n int64, err error) {
+	release, err := f.holdSharing()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -2544,6 +3256,11 @@ func (f *fragment) ReadFrom(r io.Reader) (n int64, err error) {
 			if err := f.readStorageFromArchive(tr); err != nil {
 				return 0, errors.Wrap(err, "reading storage")
 			}
+			// The archive holds every container.
+			f.sharedKeys, f.sharedBytes, f.sharedPending = nil, 0, false
+			if err := f.writeSharedKeys(nil); err != nil {
+				return 0, err
+			}
 		case "cache":
 			if err := f.readCacheFromArchive(tr); err != nil {
 				return 0, errors.Wrap(err, "reading cache")
@@ -2607,7 +3324,7 @@ func (f *fragment) readCacheFromArchive(r io.Reader) error {
 
 func (f *fragment) minRowID() (uint64, bool) {
 	min, ok := f.storage.Min()
-	return min / ShardWidth, ok
+	return min / f.shardWidth, ok
 }
 
 // rowFilter is a function signature for controlling iteration over containers
@@ -2631,10 +3348,10 @@ func filterWithLimit(limit uint64) rowFilter {
 	}
 }
 
-func filterColumn(col uint64) rowFilter {
+func filterColumn(col, shardWidth uint64) rowFilter {
 	return func(rowID, key uint64, c *roaring.Container) (include, done bool) {
-		colID := col % ShardWidth
-		colKey := ((rowID * ShardWidth) + colID) >> 16
+		colID := col % shardWidth
+		colKey := ((rowID * shardWidth) + colID) >> 16
 		colVal := uint16(colID & 0xFFFF) // columnID within the container
 		return colKey == key && c.Contains(colVal), false
 	}
@@ -2685,7 +3402,8 @@ func (f *fragment) rows(start uint64, filters ...rowFilter) []uint64 {
 
 // unprotectedRows calls rows without grabbing the mutex.
 func (f *fragment) unprotectedRows(start uint64, filters ...rowFilter) []uint64 {
-	startKey := rowToKey(start)
+	startKey := rowToKey(start, f.shardWidth)
+	exp := f.containerExponent()
 	i, _ := f.storage.Containers.Iterator(startKey)
 	rows := make([]uint64, 0)
 	var lastRow uint64 = math.MaxUint64
@@ -2695,7 +3413,7 @@ func (f *fragment) unprotectedRows(start uint64, filters ...rowFilter) []uint64
 		key, c := i.Value()
 
 		// virtual row for the current container
-		vRow := key >> shardVsContainerExponent
+		vRow := key >> exp
 
 		// skip dups
 		if vRow == lastRow {
@@ -2739,11 +3457,11 @@ func upgradeRoaringBSIv2(f *fragment, bitDepth uint) (string, error) {
 		defer f.mu.Unlock()
 
 		f.storage.ForEach(func(i uint64) {
-			rowID, columnID := i/ShardWidth, (f.shard*ShardWidth)+(i%ShardWidth)
+			rowID, columnID := i/f.shardWidth, (f.shard*f.shardWidth)+(i%f.shardWidth)
 			if rowID == uint64(bitDepth) {
-				_, _ = other.Add(pos(bsiExistsBit, columnID)) // move exists bit to beginning
+				_, _ = other.Add(pos(bsiExistsBit, columnID, f.shardWidth)) // move exists bit to beginning
 			} else {
-				_, _ = other.Add(pos(rowID+bsiOffsetBit, columnID)) // move other bits up
+				_, _ = other.Add(pos(rowID+bsiOffsetBit, columnID, f.shardWidth)) // move other bits up
 			}
 		})
 	}()
@@ -2989,7 +3707,7 @@ func (s *fragmentSyncer) syncBlock(id int) error {
 
 		// Handle Sets.
 		if len(set.columnIDs) > 0 {
-			setData, err := bitsToRoaringData(set)
+			setData, err := bitsToRoaringData(set, f.shardWidth)
 			if err != nil {
 				return errors.Wrap(err, "converting bits to roaring data (set)")
 			}
@@ -3006,7 +3724,7 @@ func (s *fragmentSyncer) syncBlock(id int) error {
 
 		// Handle Clears.
 		if len(clear.columnIDs) > 0 {
-			clearData, err := bitsToRoaringData(clear)
+			clearData, err := bitsToRoaringData(clear, f.shardWidth)
 			if err != nil {
 				return errors.Wrap(err, "converting bits to roaring data (clear)")
 			}
@@ -3043,10 +3761,10 @@ func cleanViewName(v string) string {
 
 // bitsToRoaringData converts a pairSet into a roaring.Bitmap
 // which represents the data within a single shard.
-func bitsToRoaringData(ps pairSet) ([]byte, error) {
+func bitsToRoaringData(ps pairSet, shardWidth uint64) ([]byte, error) {
 	bmp := roaring.NewBitmap()
 	for j := 0; j < len(ps.columnIDs); j++ {
-		bmp.DirectAdd(ps.rowIDs[j]*ShardWidth + (ps.columnIDs[j] % ShardWidth))
+		bmp.DirectAdd(ps.rowIDs[j]*shardWidth + (ps.columnIDs[j] % shardWidth))
 	}
 
 	var buf bytes.Buffer
@@ -3087,8 +3805,8 @@ func byteSlicesEqual(a [][]byte) bool {
 }
 
 // pos returns the row position of a row/column pair.
-func pos(rowID, columnID uint64) uint64 {
-	return (rowID * ShardWidth) + (columnID % ShardWidth)
+func pos(rowID, columnID, shardWidth uint64) uint64 {
+	return (rowID * shardWidth) + (columnID % shardWidth)
 }
 
 // vector stores the mapping of colID to rowID.
@@ -3115,7 +3833,7 @@ func newRowsVector(f *fragment) *rowsVector {
 // otherwise it returns false. Ensure that you already
 // have the mutex before calling this.
 func (v *rowsVector) Get(colID uint64) (uint64, bool, error) {
-	rows := v.f.unprotectedRows(0, filterColumn(colID))
+	rows := v.f.unprotectedRows(0, filterColumn(colID, v.f.shardWidth))
 	if len(rows) > 1 {
 		return 0, false, errors.New("found multiple row values for column")
 	} else if len(rows) == 1 {
@@ -3127,8 +3845,8 @@ func (v *rowsVector) Get(colID uint64) (uint64, bool, error) {
 // rowToKey converts a Pilosa row ID to the key of the container which starts
 // that row in the bitmap which represents this entire fragment. A fragment is
 // all the rows within a shard within a field concatenated together.
-func rowToKey(rowID uint64) (key uint64) {
-	return rowID * (ShardWidth / containerWidth)
+func rowToKey(rowID, shardWidth uint64) (key uint64) {
+	return rowID * (shardWidth / containerWidth)
 }
 
 // boolVector implements the vector interface by looking
@@ -3149,7 +3867,7 @@ func newBoolVector(f *fragment) *boolVector {
 // otherwise it returns false. Ensure that you already
 // have the fragment mutex before calling this.
 func (v *boolVector) Get(colID uint64) (uint64, bool, error) {
-	rows := v.f.unprotectedRows(0, filterColumn(colID))
+	rows := v.f.unprotectedRows(0, filterColumn(colID, v.f.shardWidth))
 	if len(rows) > 1 {
 		return 0, false, errors.New("found multiple row values for column")
 	} else if len(rows) == 1 {
@@ -29,6 +29,7 @@ import (
 	"sync/atomic"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -170,6 +171,38 @@ func TestFragment_ClearRow(t *testing.T) {
 	}
 }
 
+// Ensure a fragment can clear a column from every row.
+func TestFragment_ClearColumn(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	for _, rowID := range []uint64{1, 2, 1000} {
+		if _, err := f.setBit(rowID, 65537); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := f.setBit(1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := f.clearColumn(65537); err != nil {
+		t.Fatal(err)
+	} else if n != 3 {
+		t.Fatalf("unexpected number of cleared bits: %d", n)
+	} else if n, err := f.clearColumn(65537); err != nil || n != 0 {
+		t.Fatalf("unexpected result of clearing again: %d, %v", n, err)
+	}
+
+	// Close and reopen the fragment & verify the data.
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if cols := f.row(1).Columns(); !reflect.DeepEqual(cols, []uint64{1}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if n := f.row(1000).Count(); n != 0 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+}
+
 // Ensure a fragment can set a row.
 func TestFragment_SetRow(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 7, "")
@@ -370,6 +403,101 @@ func TestFragment_SetValue(t *testing.T) {
 }
 
 // Ensure a fragment can sum values.
+func TestFragment_IncrementValue(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		f := mustOpenFragment("i", "f", viewStandard, 0, "")
+		defer f.Clean(t)
+
+		// A column without a value starts from zero.
+		for _, tt := range []struct {
+			delta int64
+			exp   int64
+		}{
+			{delta: 5, exp: 5},
+			{delta: -12, exp: -7},
+			{delta: 0, exp: -7},
+			{delta: 100, exp: 93},
+		} {
+			if value, err := f.incrementValue(100, 16, tt.delta, 0, -1000, 1000, false); err != nil {
+				t.Fatal(err)
+			} else if value != tt.exp {
+				t.Fatalf("unexpected value after %d: %d != %d", tt.delta, value, tt.exp)
+			}
+		}
+		if value, exists, err := f.value(100, 16); err != nil {
+			t.Fatal(err)
+		} else if !exists || value != 93 {
+			t.Fatalf("unexpected value: %d, %v", value, exists)
+		}
+
+		// The zero value is relative to the base of the field.
+		if value, err := f.incrementValue(200, 16, 3, -10, -1000, 1000, false); err != nil {
+			t.Fatal(err)
+		} else if value != -7 {
+			t.Fatalf("unexpected value: %d", value)
+		}
+	})
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		f := mustOpenFragment("i", "f", viewStandard, 0, "")
+		defer f.Clean(t)
+
+		if _, err := f.setValue(100, 16, 10); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.incrementValue(100, 16, 91, 0, -100, 100, false); err != ErrBSIGroupValueTooHigh {
+			t.Fatalf("unexpected error: %v", err)
+		} else if _, err := f.incrementValue(100, 16, -111, 0, -100, 100, false); err != ErrBSIGroupValueTooLow {
+			t.Fatalf("unexpected error: %v", err)
+		} else if _, err := f.incrementValue(100, 16, math.MaxInt64, 0, -100, 100, false); err != ErrBSIGroupValueTooHigh {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// A failed increment doesn't change the value.
+		if value, _, err := f.value(100, 16); err != nil {
+			t.Fatal(err)
+		} else if value != 10 {
+			t.Fatalf("unexpected value: %d", value)
+		}
+
+		// Clamping limits the value to the range instead.
+		if value, err := f.incrementValue(100, 16, math.MaxInt64, 0, -100, 100, true); err != nil {
+			t.Fatal(err)
+		} else if value != 100 {
+			t.Fatalf("unexpected value: %d", value)
+		} else if value, err := f.incrementValue(100, 16, -500, 0, -100, 100, true); err != nil {
+			t.Fatal(err)
+		} else if value != -100 {
+			t.Fatalf("unexpected value: %d", value)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		f := mustOpenFragment("i", "f", viewStandard, 0, "")
+		defer f.Clean(t)
+
+		var eg errgroup.Group
+		for i := 0; i < 20; i++ {
+			eg.Go(func() error {
+				for j := 0; j < 10; j++ {
+					if _, err := f.incrementValue(100, 16, 1, 0, 0, 1000, false); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			t.Fatal(err)
+		}
+		if value, _, err := f.value(100, 16); err != nil {
+			t.Fatal(err)
+		} else if value != 200 {
+			t.Fatalf("unexpected value: %d", value)
+		}
+	})
+}
+
 func TestFragment_Sum(t *testing.T) {
 	const bitDepth = 16
 
@@ -926,6 +1054,192 @@ func TestFragment_Snapshot(t *testing.T) {
 	}
 }
 
+// Ensure a read snapshot of a fragment is unaffected by later writes, and
+// keeps the storage it was taken from mapped until it is released.
+func TestFragment_ReadSnapshot(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	if _, err := f.setBit(1000, 1); err != nil {
+		t.Fatal(err)
+	} else if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	other, size := f.readSnapshot()
+	if size <= 0 {
+		t.Fatalf("unexpected size: %d", size)
+	}
+	if _, err := f.setBit(1000, 2); err != nil {
+		t.Fatal(err)
+	} else if err := f.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if n := other.row(1000).Count(); n != 1 {
+		t.Fatalf("unexpected snapshot count: %d", n)
+	} else if n := f.row(1000).Count(); n != 2 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+
+	// The write is accounted for in the size of the next snapshot.
+	another, anotherSize := f.readSnapshot()
+	if anotherSize <= size {
+		t.Fatalf("unexpected size: %d <= %d", anotherSize, size)
+	} else if n := another.row(1000).Count(); n != 2 {
+		t.Fatalf("unexpected snapshot count: %d", n)
+	}
+	f.releaseReadSnapshot()
+
+	f.mu.Lock()
+	n := len(f.unmapQueue)
+	f.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("unexpected unmap queue length: %d", n)
+	}
+	f.releaseReadSnapshot()
+	if f.readSnapshots != 0 || f.unmapQueue != nil {
+		t.Fatalf("unexpected state: snapshots=%d, queue=%d", f.readSnapshots, len(f.unmapQueue))
+	}
+}
+
+// Ensure a fragment can be inspected while open and from its file.
+func TestFragment_Inspect(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	for _, pos := range [][2]uint64{{0, 1}, {0, 2}, {1, 3}, {HashBlockSize + 1, 4}} {
+		if _, err := f.setBit(pos[0], pos[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opt := FragmentInspectOptions{Rows: []uint64{0}, Blocks: []int{1}}
+	info, err := f.inspect(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []FragmentRowInfo{{ID: 0, Count: 2}, {ID: 1, Count: 1}, {ID: HashBlockSize + 1, Count: 1}}
+	if !reflect.DeepEqual(info.Rows, exp) {
+		t.Fatalf("unexpected rows: %v", info.Rows)
+	} else if !reflect.DeepEqual(info.Blocks, f.Blocks()) {
+		t.Fatalf("unexpected blocks: %v, expected %v", info.Blocks, f.Blocks())
+	} else if info.Containers != 3 || info.ContainerTypes["array"] != 3 || info.OpN != 4 {
+		t.Fatalf("unexpected info: %+v", info)
+	} else if !reflect.DeepEqual(info.RowData, []FragmentRowData{{ID: 0, Columns: []uint64{1, 2}}}) {
+		t.Fatalf("unexpected row data: %v", info.RowData)
+	} else if !reflect.DeepEqual(info.BlockData, []FragmentBlockData{{ID: 1, RowIDs: []uint64{HashBlockSize + 1}, ColumnIDs: []uint64{4}}}) {
+		t.Fatalf("unexpected block data: %v", info.BlockData)
+	}
+
+	// The file of the open fragment can be inspected, and is left unchanged.
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := InspectFragmentFile(f.path, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Path, other.Size = info.Path, info.Size
+	if !reflect.DeepEqual(other, info) {
+		t.Fatalf("unexpected file info: %+v, expected %+v", other, info)
+	}
+	if after, err := os.Stat(f.path); err != nil {
+		t.Fatal(err)
+	} else if after.Size() != fi.Size() || after.ModTime() != fi.ModTime() {
+		t.Fatal("file changed by inspection")
+	}
+}
+
+// Ensure the op log of a fragment can be compacted to the net effect of its
+// ops without rewriting its snapshot.
+func TestFragment_CompactOps(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+	f.MaxOpRedundancy = 0
+
+	f.mustSetBits(1, 1, 2, 3)
+	if err := f.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Set and clear the same bits repeatedly.
+	for i := 0; i < 50; i++ {
+		if _, err := f.setBit(2, 10); err != nil {
+			t.Fatal(err)
+		} else if _, err := f.clearBit(2, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.mustSetBits(2, 20)
+	if _, err := f.clearBit(1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := f.CompactOps()
+	if err != nil {
+		t.Fatal(err)
+	} else if fc.OpsBefore != 102 || fc.OpNBefore != 102 || fc.OpsAfter != 2 || fc.OpNAfter != 2 {
+		t.Fatalf("unexpected compaction: %+v", fc)
+	}
+	if after, err := os.Stat(f.path); err != nil {
+		t.Fatal(err)
+	} else if after.Size() >= fi.Size()+102*13 {
+		t.Fatalf("op log wasn't compacted: %d bytes", after.Size()-fi.Size())
+	}
+	info, err := f.inspect(FragmentInspectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	} else if info.Ops != 2 || info.OpN != 2 || info.Compactions != 1 || !reflect.DeepEqual(info.LastCompaction, fc) {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+
+	// Writes after the compaction are appended to the compacted op log.
+	f.mustSetBits(3, 30)
+
+	check := func() {
+		t.Helper()
+		if cols := f.row(1).Columns(); !reflect.DeepEqual(cols, []uint64{1, 3}) {
+			t.Fatalf("unexpected row 1: %v", cols)
+		} else if cols := f.row(2).Columns(); !reflect.DeepEqual(cols, []uint64{20}) {
+			t.Fatalf("unexpected row 2: %v", cols)
+		} else if cols := f.row(3).Columns(); !reflect.DeepEqual(cols, []uint64{30}) {
+			t.Fatalf("unexpected row 3: %v", cols)
+		}
+	}
+	check()
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	check()
+	if ops, opN := f.storage.Ops(); ops != 3 || opN != 3 {
+		t.Fatalf("unexpected op counts after reopening: %d/%d", ops, opN)
+	}
+
+	// Redundant ops trigger a compaction.
+	f.MaxOpN, f.MaxOpRedundancy = 100, 0.5
+	for i := 0; i < 5; i++ {
+		if _, err := f.setBit(4, 40); err != nil {
+			t.Fatal(err)
+		} else if _, err := f.clearBit(4, 40); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		f.mu.RLock()
+		compactions, ops := f.compactions, f.ops
+		f.mu.RUnlock()
+		if compactions == 2 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("op log wasn't compacted: %d ops", ops)
+		}
+	}
+}
+
 // Ensure a fragment can iterate over all bits in order.
 func TestFragment_ForEachBit(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, "")
@@ -977,6 +1291,53 @@ func TestFragment_Top(t *testing.T) {
 	}
 }
 
+// Ensure the cache is recalculated in the background after an import, and
+// that TopN waits for it or recalculates it rather than using stale ranks.
+func TestFragment_TopN_CacheRecalculation(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	// Queue recalculations without running them.
+	close(f.snapshotQueue)
+	queue := make(chan *fragment, 1)
+	f.snapshotQueue = queue
+
+	f.mustSetBits(100, 1)
+	f.mustSetBits(101, 1, 2)
+	f.RecalculateCache()
+
+	if err := f.bulkImport([]uint64{100, 100, 100}, []uint64{3, 4, 5}, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	} else if info, err := f.inspect(FragmentInspectOptions{}); err != nil {
+		t.Fatal(err)
+	} else if !info.CacheDirty {
+		t.Fatal("expected cache to be dirty after import")
+	} else if pairs := f.cache.Top(); pairs[0].ID != 101 {
+		t.Fatalf("expected rankings from before the import: %v", pairs)
+	}
+
+	// Nothing recalculates the cache, so TopN does once it stops waiting.
+	if pairs, err := f.top(topOptions{N: 1, CacheWait: time.Millisecond}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(pairs, []Pair{{ID: 100, Count: 4}}) {
+		t.Fatalf("unexpected pairs: %v", pairs)
+	} else if f.cacheDirty {
+		t.Fatal("expected cache to be recalculated")
+	}
+	<-queue
+
+	// A worker recalculates the cache while TopN waits.
+	if err := f.bulkImport([]uint64{101, 101, 101}, []uint64{3, 4, 5}, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	go snapshotQueueWorker(queue, f.Logger)
+	if pairs, err := f.top(topOptions{N: 1, CacheWait: time.Minute}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(pairs, []Pair{{ID: 101, Count: 5}}) {
+		t.Fatalf("unexpected pairs: %v", pairs)
+	}
+}
+
 // Ensure a fragment can filter rows when retrieving the top n rows.
 func TestFragment_Top_Filter(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
@@ -1460,6 +1821,66 @@ func BenchmarkFragment_IntersectionCount(b *testing.B) {
 	}
 }
 
+// Ensure the union of a list of rows is read in a single pass.
+func TestFragment_UnionRows(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	if err := f.bulkImport([]uint64{1, 1, 2, 3, 5}, []uint64{1, 70000, 2, 3, ShardWidth - 1}, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.unionRows([]uint64{1, 2, 4, 5}).Columns(); !reflect.DeepEqual(got, []uint64{1, 2, 70000, ShardWidth - 1}) {
+		t.Fatalf("unexpected columns: %v", got)
+	} else if n := f.unionRows([]uint64{1, 2}).Count(); n != 3 {
+		t.Fatalf("unexpected count: %d", n)
+	} else if n := f.unionRows(nil).Count(); n != 0 {
+		t.Fatalf("unexpected count of no rows: %d", n)
+	}
+}
+
+// BenchmarkFragment_UnionRows compares reading the union of 500 rows in a
+// single pass, as Row(f=[...]) does, with reading and combining each row, as
+// Union(Row(f=1), Row(f=2), ...) did.
+func BenchmarkFragment_UnionRows(b *testing.B) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(b)
+
+	const rows = 500
+	var rowIDs, colIDs []uint64
+	for i := 0; i < 200000; i++ {
+		rowIDs = append(rowIDs, uint64(rand.Intn(rows)))
+		colIDs = append(colIDs, uint64(rand.Intn(ShardWidth)))
+	}
+	if err := f.bulkImport(rowIDs, colIDs, &ImportOptions{}); err != nil {
+		b.Fatal(err)
+	}
+	list := make([]uint64, rows)
+	for i := range list {
+		list[i] = uint64(i)
+	}
+
+	b.Run("Union", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			row := f.row(list[0])
+			for _, rowID := range list[1:] {
+				row = row.Union(f.row(rowID))
+			}
+			if row.Count() == 0 {
+				b.Fatal("unexpected empty union")
+			}
+		}
+	})
+	b.Run("UnionRows", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if f.unionRows(list).Count() == 0 {
+				b.Fatal("unexpected empty union")
+			}
+		}
+	})
+}
+
 func TestFragment_Tanimoto(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
 	defer f.Clean(t)
@@ -2653,7 +3074,7 @@ func TestFragment_RowsIteration(t *testing.T) {
 			t.Fatalf("Do not match %v %v", expectedAll, ids)
 		}
 
-		ids = f.rows(0, filterColumn(1))
+		ids = f.rows(0, filterColumn(1, ShardWidth))
 		if !reflect.DeepEqual(expectedOdd, ids) {
 			t.Fatalf("Do not match %v %v", expectedOdd, ids)
 		}
@@ -2677,7 +3098,7 @@ func TestFragment_RowsIteration(t *testing.T) {
 			t.Fatalf("Do not match %v %v", expected, ids)
 		}
 
-		ids = f.rows(0, filterColumn(66000))
+		ids = f.rows(0, filterColumn(66000, ShardWidth))
 		if !reflect.DeepEqual(expected, ids) {
 			t.Fatalf("Do not match %v %v", expected, ids)
 		}
@@ -2699,7 +3120,7 @@ func TestFragment_RowsIteration(t *testing.T) {
 				if !reflect.DeepEqual(expectedRows, ids) {
 					t.Fatalf("Do not match %v %v", expectedRows, ids)
 				}
-				ids = f.rows(0, filterColumn(c))
+				ids = f.rows(0, filterColumn(c, ShardWidth))
 				if !reflect.DeepEqual(expectedRows, ids) {
 					t.Fatalf("Do not match %v %v", expectedRows, ids)
 				}
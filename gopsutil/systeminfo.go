@@ -15,13 +15,17 @@
 package gopsutil
 
 import (
+	"os"
 	"runtime"
 	"strings"
 
 	"github.com/pilosa/pilosa/v2"
 	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/host"
+	"github.com/shirou/gopsutil/load"
 	"github.com/shirou/gopsutil/mem"
+	"github.com/shirou/gopsutil/process"
 )
 
 var _ pilosa.SystemInfo = NewSystemInfo()
@@ -235,6 +239,38 @@ func (s *systemInfo) CPUCores() (physical, logical int, err error) {
 	return s.cpuPhysicalCores, s.cpuLogicalCores, nil
 }
 
+// DiskFree returns the number of bytes available on the file system which
+// contains path.
+func (s *systemInfo) DiskFree(path string) (uint64, error) {
+	usage, err := disk.Usage(path)
+	if err != nil {
+		return 0, err
+	}
+	return usage.Free, nil
+}
+
+// MemRSS returns the resident set size of this process in bytes.
+func (s *systemInfo) MemRSS() (uint64, error) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return 0, err
+	}
+	memInfo, err := proc.MemoryInfo()
+	if err != nil {
+		return 0, err
+	}
+	return memInfo.RSS, nil
+}
+
+// LoadAverage returns the system load average over the last minute.
+func (s *systemInfo) LoadAverage() (float64, error) {
+	avg, err := load.Avg()
+	if err != nil {
+		return 0, err
+	}
+	return avg.Load1, nil
+}
+
 // NewSystemInfo is a constructor for the gopsutil implementation of SystemInfo.
 func NewSystemInfo() *systemInfo {
 	return &systemInfo{}
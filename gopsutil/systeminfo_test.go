@@ -15,6 +15,7 @@
 package gopsutil_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/pilosa/pilosa/v2"
@@ -68,4 +69,18 @@ func TestSystemInfo(t *testing.T) {
 	if cpuArch == "" {
 		t.Fatalf("Error getting CPU arch.")
 	}
+
+	diskfree, err := systemInfo.DiskFree(os.TempDir())
+	if err != nil || diskfree == 0 {
+		t.Fatalf("Error getting diskfree. (diskfree: %v, error: %v)", diskfree, err)
+	}
+
+	memrss, err := systemInfo.MemRSS()
+	if err != nil || memrss == 0 {
+		t.Fatalf("Error getting memrss. (memrss: %v, error: %v)", memrss, err)
+	}
+
+	if _, err := systemInfo.LoadAverage(); err != nil {
+		t.Fatalf("Error getting load average. (error: %v)", err)
+	}
 }
@@ -16,6 +16,7 @@ package pilosa
 
 import (
 	"encoding/json"
+	"time"
 )
 
 // QueryRequest represent a request to process a query.
@@ -42,6 +43,32 @@ type QueryRequest struct {
 	// If true, indicates that query is part of a larger distributed query.
 	// If false, this request is on the originating node.
 	Remote bool
+
+	// Session of the query. Results stored in a session can be referenced
+	// by later queries in the same session with Handle().
+	Session string
+
+	// Store the result of the query under this handle in the session.
+	StoreAs string
+
+	// Read the data as of the start of the query, without observing writes
+	// applied while it runs.
+	Snapshot bool
+
+	// Return the columns of row results as serialized roaring bitmaps.
+	Roaring bool
+
+	// Read fields which retain snapshots as of their newest snapshot taken
+	// at or before this time, if set.
+	AsOf time.Time
+
+	// Return an estimate of the size of the result of each call instead of
+	// the result.
+	Estimate bool
+
+	// Allow reads to be served by replicas which are catching up with the
+	// other replicas, if their data was caught up at most this long ago.
+	MaxStaleness time.Duration
 }
 
 // QueryResponse represent a response from a processed query.
@@ -55,6 +82,19 @@ type QueryResponse struct {
 
 	// Error during parsing or execution.
 	Err error
+
+	// Shards the query was executed against. Only set for queries which
+	// operate on shards.
+	Shards []uint64
+
+	// Shards which were read from a replica catching up with the other
+	// replicas, and so may be stale. Only set for queries with a maximum
+	// staleness.
+	StaleShards []uint64
+
+	// Encode the columns of row results as serialized roaring bitmaps, which
+	// are base64-encoded in JSON.
+	Roaring bool
 }
 
 // MarshalJSON marshals QueryResponse into a JSON-encoded byte slice
@@ -65,15 +105,53 @@ func (resp *QueryResponse) MarshalJSON() ([]byte, error) {
 		}{Err: resp.Err.Error()})
 	}
 
+	results := resp.Results
+	if resp.Roaring {
+		results = make([]interface{}, len(resp.Results))
+		for i, result := range resp.Results {
+			if row, ok := result.(*Row); ok && row != nil {
+				result = roaringRow{row}
+			}
+			results[i] = result
+		}
+	}
+
 	return json.Marshal(struct {
 		Results        []interface{}    `json:"results"`
 		ColumnAttrSets []*ColumnAttrSet `json:"columnAttrs,omitempty"`
+		Shards         []uint64         `json:"shards,omitempty"`
+		StaleShards    []uint64         `json:"staleShards,omitempty"`
 	}{
-		Results:        resp.Results,
+		Results:        results,
 		ColumnAttrSets: resp.ColumnAttrSets,
+		Shards:         resp.Shards,
+		StaleShards:    resp.StaleShards,
 	})
 }
 
+// roaringRow is a row result which is encoded to JSON with its columns as a
+// base64-encoded roaring bitmap.
+type roaringRow struct {
+	*Row
+}
+
+// MarshalJSON returns a JSON-encoded byte slice of r.
+func (r roaringRow) MarshalJSON() ([]byte, error) {
+	data, err := r.MarshalRoaring()
+	if err != nil {
+		return nil, err
+	}
+	o := struct {
+		Attrs   map[string]interface{} `json:"attrs"`
+		Roaring []byte                 `json:"roaring"`
+		Keys    []string               `json:"keys,omitempty"`
+	}{Attrs: r.Attrs, Roaring: data, Keys: r.Keys}
+	if o.Attrs == nil {
+		o.Attrs = make(map[string]interface{})
+	}
+	return json.Marshal(&o)
+}
+
 // Handler is the interface for the data handler, a wrapper around
 // Pilosa's data store.
 type Handler interface {
@@ -123,6 +201,10 @@ type ImportRequest struct {
 type ImportRoaringRequest struct {
 	Clear bool
 	Views map[string][]byte
+
+	// OperationID identifies the import so that it is only applied once
+	// to each fragment when it is retried.
+	OperationID string
 }
 
 // ImportResponse is the structured response of an import.
@@ -138,13 +220,29 @@ type BlockDataRequest struct {
 	View  string
 	Shard uint64
 	Block uint64
+
+	// Continuation resumes a previous request which returned a
+	// Continuation. Limit bounds the number of pairs returned. Both are
+	// only used by the public endpoint.
+	Continuation uint64
+	Limit        uint64
 }
 
 // BlockDataResponse is the structured response of a block
-// data request.
+// data request. Column IDs are relative to the start of the shard.
 type BlockDataResponse struct {
-	RowIDs    []uint64
-	ColumnIDs []uint64
+	RowIDs    []uint64 `json:"rowIDs"`
+	ColumnIDs []uint64 `json:"columnIDs"`
+
+	// Continuation is non-zero if the block contains more pairs than were
+	// returned. It should be passed in the next request for the block.
+	Continuation uint64 `json:"continuation,omitempty"`
+}
+
+// FragmentBlocksResponse is the structured response of a fragment
+// blocks request.
+type FragmentBlocksResponse struct {
+	Blocks []FragmentBlock `json:"blocks"`
 }
 
 // TranslateKeysRequest describes the structure of a request
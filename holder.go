@@ -22,6 +22,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -30,6 +31,7 @@ import (
 	"github.com/pilosa/pilosa/v2/logger"
 	"github.com/pilosa/pilosa/v2/roaring"
 	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pilosa/pilosa/v2/syswrap"
 	"github.com/pilosa/pilosa/v2/tracing"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
@@ -42,6 +44,11 @@ const (
 	// fileLimit is the maximum open file limit (ulimit -n) to automatically set.
 	fileLimit = 262144 // (512^2)
 
+	// fileLimitReserve is the number of open files reserved for attribute and
+	// translation stores, network connections, and the runtime on top of the
+	// fragment files.
+	fileLimitReserve = 1024
+
 	// existenceFieldName is the name of the internal field used to store existence values.
 	existenceFieldName = "_exists"
 )
@@ -53,6 +60,10 @@ type Holder struct {
 	// Indexes by name.
 	indexes map[string]*Index
 
+	// Signaled, with mu held, when an index being deleted is removed from
+	// indexes.
+	indexDeleted *sync.Cond
+
 	// opened channel is closed once Open() completes.
 	opened lockedChan
 
@@ -77,6 +88,13 @@ type Holder struct {
 
 	snapshotQueue chan *fragment
 
+	// Bounds the operation IDs recorded by each fragment and for
+	// transactional writes.
+	opIDOptions operationIDOptions
+
+	// Bounds the change streams of fields which track changes.
+	changeOptions changeStreamOptions
+
 	// Manages replication from the primary node.
 	primaryTranslateNode     *Node
 	translateStoreReplicator *holderTranslateStoreReplicator
@@ -84,6 +102,24 @@ type Holder struct {
 	// Instantiates new translation stores for indexes & fields.
 	OpenTranslateStore  OpenTranslateStoreFunc  // local store
 	OpenTranslateReader OpenTranslateReaderFunc // replication
+
+	// Schema generation, incremented on every index or field mutation.
+	generationMu sync.Mutex
+	generation   uint64
+
+	// Reads and raises the open file limit.
+	fileLimits fileLimiter
+
+	// Deleted indexes are kept in the trash for trashRetention, or removed
+	// immediately if it is zero. trashMu guards the trash directory, and is
+	// acquired after mu.
+	trashMu        sync.Mutex
+	trashRetention time.Duration
+
+	// Interval between the retained snapshots of fields which retain
+	// snapshots, and the fragments of retained snapshots loaded by queries.
+	retainedSnapshotInterval time.Duration
+	retainedFragments        *retainedFragmentCache
 }
 
 // lockedChan looks a little ridiculous admittedly, but exists for good reason.
@@ -114,7 +150,7 @@ func (lc *lockedChan) Recv() {
 
 // NewHolder returns a new instance of Holder.
 func NewHolder() *Holder {
-	return &Holder{
+	h := &Holder{
 		indexes: make(map[string]*Index),
 		closing: make(chan struct{}),
 
@@ -126,11 +162,21 @@ func NewHolder() *Holder {
 		NewAttrStore: newNopAttrStore,
 
 		cacheFlushInterval: defaultCacheFlushInterval,
+		opIDOptions:        defaultOperationIDOptions(),
+		changeOptions:      defaultChangeStreamOptions(),
+		trashRetention:     defaultTrashRetention,
+
+		retainedSnapshotInterval: defaultRetainedSnapshotInterval,
+		retainedFragments:        newRetainedFragmentCache(defaultRetainedFragmentCacheSize),
 
 		Logger: logger.NopLogger,
 
 		OpenTranslateStore: OpenInMemTranslateStore,
+
+		fileLimits: rlimitFileLimiter{},
 	}
+	h.indexDeleted = sync.NewCond(&h.mu)
+	return h
 }
 
 // Open initializes the root data directory for the holder.
@@ -138,13 +184,16 @@ func (h *Holder) Open() error {
 	// Reset closing in case Holder is being reopened.
 	h.closing = make(chan struct{})
 
-	h.setFileLimit()
-
 	h.Logger.Printf("open holder path: %s", h.Path)
 	if err := os.MkdirAll(h.Path, 0777); err != nil {
 		return errors.Wrap(err, "creating directory")
 	}
 
+	// Make sure every fragment can be kept open before opening any of them.
+	if err := h.checkFileLimit(); err != nil {
+		return err
+	}
+
 	// Open path to read all index directories.
 	f, err := os.Open(h.Path)
 	if err != nil {
@@ -157,6 +206,10 @@ func (h *Holder) Open() error {
 		return errors.Wrap(err, "reading directory")
 	}
 
+	if err := h.loadSchemaGeneration(); err != nil {
+		return errors.Wrap(err, "loading schema generation")
+	}
+
 	// Run snapshots asynchronously. The snapshotQueue will have a background
 	// task associated with it which flushes it and waits until this channel
 	// is closed, so we should always close this channel when done.
@@ -195,6 +248,18 @@ func (h *Holder) Open() error {
 	h.wg.Add(1)
 	go func() { defer h.wg.Done(); h.monitorCacheFlush() }()
 
+	// Periodically purge expired trash entries.
+	if h.trashRetention > 0 {
+		h.wg.Add(1)
+		go func() { defer h.wg.Done(); h.monitorTrash() }()
+	}
+
+	// Periodically retain snapshots of fields which retain snapshots.
+	if h.retainedSnapshotInterval > 0 {
+		h.wg.Add(1)
+		go func() { defer h.wg.Done(); h.monitorRetainedSnapshots() }()
+	}
+
 	h.Stats.Open()
 
 	h.opened.Close()
@@ -210,6 +275,10 @@ func (h *Holder) Close() error {
 	h.wg.Wait()
 
 	for _, index := range h.indexes {
+		// An index being deleted is closed once its handles are released.
+		if index.isDeleting() {
+			continue
+		}
 		if err := index.Close(); err != nil {
 			return errors.Wrap(err, "closing index")
 		}
@@ -263,7 +332,7 @@ func (h *Holder) HasData() (bool, error) {
 	}
 
 	for _, fi := range fis {
-		if !fi.IsDir() {
+		if !fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
 			continue
 		}
 		return true, nil
@@ -284,9 +353,9 @@ func (h *Holder) availableShardsByIndex() map[string]*roaring.Bitmap {
 func (h *Holder) Schema() []*IndexInfo {
 	var a []*IndexInfo
 	for _, index := range h.Indexes() {
-		di := &IndexInfo{Name: index.Name()}
+		di := &IndexInfo{Name: index.Name(), Options: index.Options(), ShardWidth: index.ShardWidth(), IngestMappings: index.IngestMappings()}
 		for _, field := range index.Fields() {
-			fi := &FieldInfo{Name: field.Name(), Options: field.Options()}
+			fi := &FieldInfo{Name: field.Name(), Options: field.Options(), MaxRowID: field.MaxRowID()}
 			for _, view := range field.views() {
 				fi.Views = append(fi.Views, &ViewInfo{Name: view.name})
 			}
@@ -305,15 +374,16 @@ func (h *Holder) limitedSchema() []*IndexInfo {
 	var a []*IndexInfo
 	for _, index := range h.Indexes() {
 		di := &IndexInfo{
-			Name:       index.Name(),
-			Options:    index.Options(),
-			ShardWidth: ShardWidth,
+			Name:           index.Name(),
+			Options:        index.Options(),
+			ShardWidth:     index.ShardWidth(),
+			IngestMappings: index.IngestMappings(),
 		}
 		for _, field := range index.Fields() {
 			if strings.HasPrefix(field.name, "_") {
 				continue
 			}
-			fi := &FieldInfo{Name: field.Name(), Options: field.Options()}
+			fi := &FieldInfo{Name: field.Name(), Options: field.Options(), MaxRowID: field.MaxRowID()}
 			di.Fields = append(di.Fields, fi)
 		}
 		sort.Sort(fieldInfoSlice(di.Fields))
@@ -323,13 +393,28 @@ func (h *Holder) limitedSchema() []*IndexInfo {
 	return a
 }
 
-// applySchema applies an internal Schema to Holder.
+// applySchema applies an internal Schema to Holder. Nothing is applied if
+// the schema is incompatible with the existing indexes and fields.
 func (h *Holder) applySchema(schema *Schema) error {
+	if err := h.checkSchema(schema); err != nil {
+		return err
+	}
+
 	// Create indexes that don't exist.
 	for _, index := range schema.Indexes {
-		idx, err := h.CreateIndexIfNotExists(index.Name, index.Options)
+		opt := index.Options
+		if opt.ShardWidth == 0 && index.ShardWidth != ShardWidth {
+			opt.ShardWidth = index.ShardWidth
+		}
+		idx, err := h.CreateIndexIfNotExists(index.Name, opt)
 		if err != nil {
 			return errors.Wrap(err, "creating index")
+		} else if err := idx.SetReadOnly(opt.ReadOnly); err != nil {
+			return errors.Wrap(err, "setting read-only")
+		} else if err := idx.SetQuota(opt.Quota); err != nil {
+			return errors.Wrap(err, "setting quota")
+		} else if err := idx.SetDefaultFieldOptions(opt.DefaultFieldOptions); err != nil {
+			return errors.Wrap(err, "setting default field options")
 		}
 		// Create fields that don't exist.
 		for _, f := range index.Fields {
@@ -337,6 +422,7 @@ func (h *Holder) applySchema(schema *Schema) error {
 			if err != nil {
 				return errors.Wrap(err, "creating field")
 			}
+			field.AddRemoteMaxRowID(f.MaxRowID)
 			// Create views that don't exist.
 			for _, v := range f.Views {
 				_, err := field.createViewIfNotExists(v.Name)
@@ -345,27 +431,138 @@ func (h *Holder) applySchema(schema *Schema) error {
 				}
 			}
 		}
+		// Create ingest mappings that don't exist.
+		for _, m := range index.IngestMappings {
+			if err := idx.createIngestMappingIfNotExists(m); err != nil {
+				return errors.Wrap(err, "creating ingest mapping")
+			}
+		}
+	}
+	return nil
+}
+
+// checkSchema returns a conflict error if an index or field of schema
+// exists with a different shard width, type or key setting, which cannot be
+// changed by applying the schema.
+func (h *Holder) checkSchema(schema *Schema) error {
+	for _, ii := range schema.Indexes {
+		idx := h.Index(ii.Name)
+		if idx == nil {
+			continue
+		}
+		width := ii.Options.ShardWidth
+		if width == 0 {
+			width = ii.ShardWidth
+		}
+		if width != 0 && width != idx.ShardWidth() {
+			return newConflictError(errors.Wrapf(ErrShardWidthMismatch, "index %s", ii.Name))
+		}
+		for _, fi := range ii.Fields {
+			f := idx.Field(fi.Name)
+			if f == nil {
+				continue
+			}
+			if typ := fi.Options.Type; typ != "" && typ != f.Type() {
+				return newConflictError(errors.Errorf("field %s/%s exists with type %s, not %s", ii.Name, fi.Name, f.Type(), typ))
+			} else if fi.Options.Keys != f.keys() {
+				return newConflictError(errors.Errorf("field %s/%s exists with keys %v, not %v", ii.Name, fi.Name, f.keys(), fi.Options.Keys))
+			}
+		}
 	}
 	return nil
 }
 
+// matchesSchema returns true if the holder has exactly the indexes and
+// fields of schema. Internal fields, which schemas leave out, are ignored.
+func (h *Holder) matchesSchema(schema *Schema) bool {
+	indexes := h.Indexes()
+	if len(indexes) != len(schema.Indexes) {
+		return false
+	}
+	for _, ii := range schema.Indexes {
+		idx := h.Index(ii.Name)
+		if idx == nil {
+			return false
+		}
+		n := 0
+		for _, f := range idx.Fields() {
+			if !strings.HasPrefix(f.Name(), "_") {
+				n++
+			}
+		}
+		if n != len(ii.Fields) {
+			return false
+		}
+		for _, fi := range ii.Fields {
+			if idx.Field(fi.Name) == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // IndexPath returns the path where a given index is stored.
 func (h *Holder) IndexPath(name string) string { return filepath.Join(h.Path, name) }
 
-// Index returns the index by name.
+// Index returns the index by name. The index may be closed at any time by
+// DeleteIndex: use AcquireIndex to operate on its data.
 func (h *Holder) Index(name string) *Index {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 	return h.index(name)
 }
 
-func (h *Holder) index(name string) *Index { return h.indexes[name] }
+// index returns the index by name, unless it is being deleted.
+func (h *Holder) index(name string) *Index {
+	if index := h.indexes[name]; index != nil && !index.isDeleting() {
+		return index
+	}
+	return nil
+}
+
+// IndexHandle is an index acquired by AcquireIndex. The index isn't closed by
+// DeleteIndex until the handle is closed.
+type IndexHandle struct {
+	*Index
+	once sync.Once
+}
+
+// Close releases the handle. It doesn't close the index, and can be called
+// more than once.
+func (h *IndexHandle) Close() {
+	h.once.Do(h.Index.release)
+}
+
+// AcquireIndex returns a handle to the index by name, which must be closed
+// once the caller is done with the index. An index being deleted can't be
+// acquired, and is only closed once all of its handles are closed.
+func (h *Holder) AcquireIndex(name string) (*IndexHandle, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	index := h.index(name)
+	if index == nil || !index.acquire() {
+		return nil, ErrIndexNotFound
+	}
+	return &IndexHandle{Index: index}, nil
+}
+
+// waitIndexDeleted waits until the index by name, if it is being deleted, is
+// removed from the holder. mu must be held for writing.
+func (h *Holder) waitIndexDeleted(name string) {
+	for index := h.indexes[name]; index != nil && index.isDeleting(); index = h.indexes[name] {
+		h.indexDeleted.Wait()
+	}
+}
 
 // Indexes returns a list of all indexes in the holder.
 func (h *Holder) Indexes() []*Index {
 	h.mu.RLock()
 	a := make([]*Index, 0, len(h.indexes))
 	for _, index := range h.indexes {
+		if index.isDeleting() {
+			continue
+		}
 		a = append(a, index)
 	}
 	h.mu.RUnlock()
@@ -379,6 +576,7 @@ func (h *Holder) Indexes() []*Index {
 func (h *Holder) CreateIndex(name string, opt IndexOptions) (*Index, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.waitIndexDeleted(name)
 
 	// Ensure index doesn't already exist.
 	if h.index(name) != nil {
@@ -392,9 +590,13 @@ func (h *Holder) CreateIndex(name string, opt IndexOptions) (*Index, error) {
 func (h *Holder) CreateIndexIfNotExists(name string, opt IndexOptions) (*Index, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.waitIndexDeleted(name)
 
 	// Return index if it exists.
 	if index := h.index(name); index != nil {
+		if opt.ShardWidth != 0 && opt.ShardWidth != index.ShardWidth() {
+			return nil, newConflictError(ErrShardWidthMismatch)
+		}
 		return index, nil
 	}
 
@@ -405,6 +607,13 @@ func (h *Holder) createIndex(name string, opt IndexOptions) (*Index, error) {
 	if name == "" {
 		return nil, errors.New("index name required")
 	}
+	if err := validateShardWidth(opt.ShardWidth); err != nil {
+		return nil, NewBadRequestError(err)
+	} else if opt.Quota < 0 {
+		return nil, NewBadRequestError(ErrInvalidQuota)
+	} else if err := opt.DefaultFieldOptions.validate(); err != nil {
+		return nil, NewBadRequestError(err)
+	}
 
 	// Otherwise create a new index.
 	index, err := h.newIndex(h.IndexPath(name), name)
@@ -414,6 +623,13 @@ func (h *Holder) createIndex(name string, opt IndexOptions) (*Index, error) {
 
 	index.keys = opt.Keys
 	index.trackExistence = opt.TrackExistence
+	if opt.ShardWidth != 0 {
+		index.shardWidth = opt.ShardWidth
+	}
+	index.readOnly = opt.ReadOnly
+	index.shardWindow = opt.ShardWindow
+	index.quota = opt.Quota
+	index.defaultFieldOptions = opt.DefaultFieldOptions.clone()
 
 	if err = index.Open(); err != nil {
 		return nil, errors.Wrap(err, "opening")
@@ -424,6 +640,7 @@ func (h *Holder) createIndex(name string, opt IndexOptions) (*Index, error) {
 
 	// Update options.
 	h.indexes[index.Name()] = index
+	h.bumpSchemaGeneration()
 
 	// Restart replication.
 	go h.refreshTranslateStoreReplicator()
@@ -442,35 +659,68 @@ func (h *Holder) newIndex(path, name string) (*Index, error) {
 	index.newAttrStore = h.NewAttrStore
 	index.columnAttrs = h.NewAttrStore(filepath.Join(index.path, ".data"))
 	index.snapshotQueue = h.snapshotQueue
+	index.opIDOptions = h.opIDOptions
+	index.changeOptions = h.changeOptions
 	index.holder = h
 	index.OpenTranslateStore = h.OpenTranslateStore
 	return index, nil
 }
 
-// DeleteIndex removes an index from the holder.
+// DeleteIndex removes an index from the holder. The index is moved into the
+// trash, from which it can be restored until it expires, unless the trash is
+// disabled.
 func (h *Holder) DeleteIndex(name string) error {
+	return h.deleteIndex(name, h.trashRetention <= 0)
+}
+
+// PurgeIndex removes an index from the holder and deletes its files,
+// bypassing the trash.
+func (h *Holder) PurgeIndex(name string) error {
+	return h.deleteIndex(name, true)
+}
+
+func (h *Holder) deleteIndex(name string, purge bool) error {
 	h.mu.Lock()
-	defer h.mu.Unlock()
 
 	// Confirm index exists.
 	index := h.index(name)
 	if index == nil {
+		h.mu.Unlock()
 		return newNotFoundError(ErrIndexNotFound)
 	}
 
+	// Hide the index, and wait for the operations in flight on it to release
+	// their handles. Creating an index of the same name waits until the
+	// index is removed.
+	drained := index.markDeleting()
+	h.mu.Unlock()
+	<-drained
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Remove reference, even if the index can't be closed or its files
+	// can't be removed.
+	defer func() {
+		delete(h.indexes, name)
+		h.bumpSchemaGeneration()
+		h.indexDeleted.Broadcast()
+	}()
+
 	// Close index.
 	if err := index.Close(); err != nil {
 		return errors.Wrap(err, "closing")
 	}
 
-	// Delete index directory.
-	if err := os.RemoveAll(h.IndexPath(name)); err != nil {
-		return errors.Wrap(err, "removing directory")
+	// Delete index directory, or move it into the trash.
+	if purge {
+		if err := os.RemoveAll(h.IndexPath(name)); err != nil {
+			return errors.Wrap(err, "removing directory")
+		}
+	} else if err := h.moveToTrash(name); err != nil {
+		return errors.Wrap(err, "moving to trash")
 	}
 
-	// Remove reference.
-	delete(h.indexes, name)
-
 	return nil
 }
 
@@ -547,51 +797,160 @@ func (h *Holder) recalculateCaches() {
 	}
 }
 
-// setFileLimit attempts to set the open file limit to the FileLimit constant defined above.
-func (h *Holder) setFileLimit() {
-	oldLimit := &syscall.Rlimit{}
-	newLimit := &syscall.Rlimit{}
+// fileLimiter reads and sets the process's open file limit. Tests replace it
+// to simulate a low limit.
+type fileLimiter interface {
+	// FileLimit returns the soft and hard open file limits.
+	FileLimit() (cur, max uint64, err error)
+	// SetFileLimit sets the soft and hard open file limits.
+	SetFileLimit(cur, max uint64) error
+}
+
+// rlimitFileLimiter is the fileLimiter backed by RLIMIT_NOFILE.
+type rlimitFileLimiter struct{}
+
+func (rlimitFileLimiter) FileLimit() (cur, max uint64, err error) {
+	var rlimit syscall.Rlimit
+	err = syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit)
+	return rlimit.Cur, rlimit.Max, err
+}
+
+func (rlimitFileLimiter) SetFileLimit(cur, max uint64) error {
+	return syscall.Setrlimit(syscall.RLIMIT_NOFILE, &syscall.Rlimit{Cur: cur, Max: max})
+}
+
+// checkFileLimit makes sure that every fragment file in the holder can be
+// kept open, raising the open file limit if necessary. If the number of open
+// fragment files is capped below the number of fragments by max-file-count,
+// only the cap has to fit and it is lowered below the limit instead. This is
+// called before anything is opened so that the holder fails with a clear
+// error rather than "too many open files" halfway through opening.
+func (h *Holder) checkFileLimit() error {
+	n, err := countFragmentFiles(h.Path)
+	if err != nil {
+		return errors.Wrap(err, "counting fragments")
+	}
+	fragments := uint64(n)
+	required := fragments + fileLimitReserve
 
-	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, oldLimit); err != nil {
+	want := required
+	if want < fileLimit {
+		want = fileLimit
+	}
+	cur, max, err := h.setFileLimit(want)
+	if err != nil {
 		h.Logger.Printf("ERROR checking open file limit: %s", err)
-		return
+		return nil
 	}
-	// If the soft limit is lower than the FileLimit constant, we will try to change it.
-	if oldLimit.Cur < fileLimit {
-		newLimit.Cur = fileLimit
-		// If the hard limit is not high enough, we will try to change it too.
-		if oldLimit.Max < fileLimit {
-			newLimit.Max = fileLimit
-		} else {
-			newLimit.Max = oldLimit.Max
+	if cur < fileLimit {
+		h.Logger.Printf("WARNING: Tried to set open file limit to %d, but it is %d. You may consider running \"sudo ulimit -n %d\" before starting Pilosa to avoid \"too many open files\" error. See https://www.pilosa.com/docs/latest/administration/#open-file-limits for more information.", fileLimit, cur, fileLimit)
+	}
+	if maxMaps := syswrap.MaxMapCount(); fragments > maxMaps {
+		h.Logger.Printf("%d fragments exceed max-map-count of %d, the remaining fragments will be read into memory", fragments, maxMaps)
+	}
+	if cur >= required {
+		return nil
+	}
+
+	if maxFiles := syswrap.MaxFileCount(); maxFiles < fragments && cur > fileLimitReserve {
+		if maxFiles+fileLimitReserve > cur {
+			syswrap.SetMaxFileCount(cur - fileLimitReserve)
+			h.Logger.Printf("lowering max-file-count from %d to %d to stay below the open file limit of %d", maxFiles, cur-fileLimitReserve, cur)
 		}
+		return nil
+	}
+	return errors.Wrapf(ErrFileLimitTooLow, "%d fragments require %d open files but the limit is %d (hard limit %d), raise it with \"ulimit -n\" or set max-file-count below the number of fragments", fragments, required, cur, max)
+}
 
-		// Try to set the limit
-		if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, newLimit); err != nil {
-			// If we just tried to change the hard limit and failed, we probably don't have permission. Let's try again without setting the hard limit.
-			if newLimit.Max > oldLimit.Max {
-				newLimit.Max = oldLimit.Max
-				// Obviously the hard limit cannot be higher than the soft limit.
-				if newLimit.Cur >= newLimit.Max {
-					newLimit.Cur = newLimit.Max
-				}
-				// Try setting again with lowered Max (hard limit)
-				if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, newLimit); err != nil {
-					h.Logger.Printf("ERROR setting open file limit: %s", err)
-				}
-				// If we weren't trying to change the hard limit, let the user know something is wrong.
-			} else {
+// setFileLimit attempts to raise the open file limit to want, raising the hard
+// limit too if permitted. It returns the resulting soft and hard limits.
+func (h *Holder) setFileLimit(want uint64) (cur, max uint64, err error) {
+	cur, max, err = h.fileLimits.FileLimit()
+	if err != nil || cur >= want {
+		return cur, max, err
+	}
+
+	// If the hard limit is not high enough, we will try to change it too.
+	newMax := max
+	if newMax < want {
+		newMax = want
+	}
+	if err := h.fileLimits.SetFileLimit(want, newMax); err != nil {
+		// If we just tried to change the hard limit and failed, we probably
+		// don't have permission. Let's try again without setting the hard limit.
+		if newMax > max {
+			newCur := want
+			if newCur > max {
+				newCur = max
+			}
+			if err := h.fileLimits.SetFileLimit(newCur, max); err != nil {
 				h.Logger.Printf("ERROR setting open file limit: %s", err)
 			}
+		} else {
+			h.Logger.Printf("ERROR setting open file limit: %s", err)
+		}
+	}
+
+	// Check the limit after setting it. OS may not obey Setrlimit call.
+	return h.fileLimits.FileLimit()
+}
+
+// countFragmentFiles returns the number of fragment files in the holder
+// directory at path.
+func countFragmentFiles(path string) (int, error) {
+	matches, err := filepath.Glob(filepath.Join(path, "*", "*", "views", "*", "fragments", "*"))
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, match := range matches {
+		// Skip hidden index directories.
+		rel, err := filepath.Rel(path, match)
+		if err != nil || strings.HasPrefix(rel, ".") {
+			continue
+		}
+		if _, err := strconv.ParseUint(filepath.Base(match), 10, 64); err == nil {
+			n++
 		}
+	}
+	return n, nil
+}
 
-		// Check the limit after setting it. OS may not obey Setrlimit call.
-		if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, oldLimit); err != nil {
-			h.Logger.Printf("ERROR checking open file limit: %s", err)
-		} else {
-			if oldLimit.Cur < fileLimit {
-				h.Logger.Printf("WARNING: Tried to set open file limit to %d, but it is %d. You may consider running \"sudo ulimit -n %d\" before starting Pilosa to avoid \"too many open files\" error. See https://www.pilosa.com/docs/latest/administration/#open-file-limits for more information.", fileLimit, oldLimit.Cur, fileLimit)
-			}
+// ResourceUsage describes how many of the file descriptors and mmaps
+// available to a node are in use.
+type ResourceUsage struct {
+	// Fragment files currently open and the cap set by max-file-count.
+	OpenFiles    uint64 `json:"openFiles"`
+	MaxFileCount uint64 `json:"maxFileCount"`
+
+	// Soft open file limit of the process.
+	FileLimit uint64 `json:"fileLimit"`
+
+	// Active mmaps and the cap set by max-map-count.
+	Mmaps       uint64 `json:"mmaps"`
+	MaxMapCount uint64 `json:"maxMapCount"`
+}
+
+// resourceUsage returns the current open file and mmap usage.
+func (h *Holder) resourceUsage() ResourceUsage {
+	usage := ResourceUsage{
+		OpenFiles:    syswrap.FileCount(),
+		MaxFileCount: syswrap.MaxFileCount(),
+		Mmaps:        syswrap.MapCount(),
+		MaxMapCount:  syswrap.MaxMapCount(),
+	}
+	if cur, _, err := h.fileLimits.FileLimit(); err == nil {
+		usage.FileLimit = cur
+	}
+	return usage
+}
+
+// updateDiskUsage measures the disk space used by every index, which
+// determines whether their quotas are exceeded.
+func (h *Holder) updateDiskUsage() {
+	for _, idx := range h.Indexes() {
+		if err := idx.updateDiskUsage(); err != nil {
+			h.Logger.Printf("index %s: %v", idx.Name(), err)
 		}
 	}
 }
@@ -618,6 +977,64 @@ func (h *Holder) loadNodeID() (string, error) {
 	return nodeID, nil
 }
 
+// SchemaGeneration returns the schema generation of the holder. It increases
+// every time an index or field is created or deleted.
+func (h *Holder) SchemaGeneration() uint64 {
+	h.generationMu.Lock()
+	defer h.generationMu.Unlock()
+	return h.generation
+}
+
+// bumpSchemaGeneration increments the schema generation.
+func (h *Holder) bumpSchemaGeneration() {
+	h.generationMu.Lock()
+	defer h.generationMu.Unlock()
+	h.generation++
+	if err := h.saveSchemaGeneration(); err != nil {
+		h.Logger.Printf("saving schema generation: %s", err)
+	}
+}
+
+// adoptSchemaGeneration sets the schema generation to gen so that a node
+// which synced its schema from the coordinator reports the same generation,
+// regardless of how many local bumps applying the schema caused.
+func (h *Holder) adoptSchemaGeneration(gen uint64) {
+	h.generationMu.Lock()
+	defer h.generationMu.Unlock()
+	if gen == h.generation {
+		return
+	}
+	h.generation = gen
+	if err := h.saveSchemaGeneration(); err != nil {
+		h.Logger.Printf("saving schema generation: %s", err)
+	}
+}
+
+// loadSchemaGeneration reads the schema generation from $DATA_DIR/.generation.
+func (h *Holder) loadSchemaGeneration() error {
+	h.generationMu.Lock()
+	defer h.generationMu.Unlock()
+
+	buf, err := ioutil.ReadFile(filepath.Join(h.Path, ".generation"))
+	if os.IsNotExist(err) {
+		h.generation = 0
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "reading file")
+	}
+	h.generation, err = strconv.ParseUint(strings.TrimSpace(string(buf)), 10, 64)
+	return errors.Wrap(err, "parsing generation")
+}
+
+// saveSchemaGeneration writes the schema generation to disk. The generation
+// lock must be held.
+func (h *Holder) saveSchemaGeneration() error {
+	if h.Path == "" {
+		return nil
+	}
+	return ioutil.WriteFile(filepath.Join(h.Path, ".generation"), []byte(strconv.FormatUint(h.generation, 10)), 0666)
+}
+
 // Log startup time and version to $DATA_DIR/.startup.log
 func (h *Holder) logStartup() error {
 	time, err := time.Now().MarshalText()
@@ -919,55 +1336,71 @@ func (s *holderSyncer) SyncHolder() error {
 			return nil
 		}
 
-		// Sync index column attributes.
-		if err := s.syncIndex(di.Name); err != nil {
-			return fmt.Errorf("index sync error: index=%s, err=%s", di.Name, err)
+		if err := s.syncIndexData(di); err != nil {
+			return err
+		}
+		s.Stats.Histogram("syncIndex", float64(time.Since(ti)), 1.0)
+		ti = time.Now() // reset ti
+	}
+
+	return nil
+}
+
+// syncIndexData synchronizes the attributes and fragments of an index. The
+// index isn't closed by its deletion until it is synchronized.
+func (s *holderSyncer) syncIndexData(di *IndexInfo) error {
+	idx, err := s.Holder.AcquireIndex(di.Name)
+	if err != nil {
+		// The index was deleted.
+		return nil
+	}
+	defer idx.Close()
+
+	// Sync index column attributes.
+	if err := s.syncIndex(di.Name); err != nil {
+		return fmt.Errorf("index sync error: index=%s, err=%s", di.Name, err)
+	}
+
+	tf := time.Now()
+	for _, fi := range di.Fields {
+		// Verify syncer has not closed.
+		if s.IsClosing() {
+			return nil
+		}
+
+		// Sync field row attributes.
+		if err := s.syncField(di.Name, fi.Name); err != nil {
+			return fmt.Errorf("field sync error: index=%s, field=%s, err=%s", di.Name, fi.Name, err)
 		}
 
-		tf := time.Now()
-		for _, fi := range di.Fields {
+		for _, vi := range fi.Views {
 			// Verify syncer has not closed.
 			if s.IsClosing() {
 				return nil
 			}
 
-			// Sync field row attributes.
-			if err := s.syncField(di.Name, fi.Name); err != nil {
-				return fmt.Errorf("field sync error: index=%s, field=%s, err=%s", di.Name, fi.Name, err)
-			}
+			itr := idx.AvailableShards().Iterator()
+			itr.Seek(0)
+			for shard, eof := itr.Next(); !eof; shard, eof = itr.Next() {
+				// Ignore shards that this host doesn't own.
+				if !s.Cluster.ownsShard(s.Node.ID, di.Name, shard) {
+					continue
+				}
 
-			for _, vi := range fi.Views {
 				// Verify syncer has not closed.
 				if s.IsClosing() {
 					return nil
 				}
 
-				itr := s.Holder.Index(di.Name).AvailableShards().Iterator()
-				itr.Seek(0)
-				for shard, eof := itr.Next(); !eof; shard, eof = itr.Next() {
-					// Ignore shards that this host doesn't own.
-					if !s.Cluster.ownsShard(s.Node.ID, di.Name, shard) {
-						continue
-					}
-
-					// Verify syncer has not closed.
-					if s.IsClosing() {
-						return nil
-					}
-
-					// Sync fragment if own it.
-					if err := s.syncFragment(di.Name, fi.Name, vi.Name, shard); err != nil {
-						return fmt.Errorf("fragment sync error: index=%s, field=%s, view=%s, shard=%d, err=%s", di.Name, fi.Name, vi.Name, shard, err)
-					}
+				// Sync fragment if own it.
+				if err := s.syncFragment(di.Name, fi.Name, vi.Name, shard); err != nil {
+					return fmt.Errorf("fragment sync error: index=%s, field=%s, view=%s, shard=%d, err=%s", di.Name, fi.Name, vi.Name, shard, err)
 				}
 			}
-			s.Stats.Histogram("syncField", float64(time.Since(tf)), 1.0)
-			tf = time.Now() // reset tf
 		}
-		s.Stats.Histogram("syncIndex", float64(time.Since(ti)), 1.0)
-		ti = time.Now() // reset ti
+		s.Stats.Histogram("syncField", float64(time.Since(tf)), 1.0)
+		tf = time.Now() // reset tf
 	}
-
 	return nil
 }
 
@@ -1000,7 +1433,7 @@ func (s *holderSyncer) syncIndex(index string) error {
 		} else if len(m) == 0 {
 			continue
 		}
-		s.Stats.CountWithCustomTags("ColumnAttrDiff", int64(len(m)), 1.0, []string{indexTag, node.ID})
+		s.Stats.CountWithCustomTags("ColumnAttrDiff", int64(len(m)), 1.0, []string{indexTag, "node:" + node.ID})
 
 		// Update local copy.
 		if err := idx.ColumnAttrStore().SetBulkAttrs(m); err != nil {
@@ -1049,7 +1482,7 @@ func (s *holderSyncer) syncField(index, name string) error {
 		} else if len(m) == 0 {
 			continue
 		}
-		s.Stats.CountWithCustomTags("RowAttrDiff", int64(len(m)), 1.0, []string{indexTag, fieldTag, node.ID})
+		s.Stats.CountWithCustomTags("RowAttrDiff", int64(len(m)), 1.0, []string{indexTag, fieldTag, "node:" + node.ID})
 
 		// Update local copy.
 		if err := f.RowAttrStore().SetBulkAttrs(m); err != nil {
@@ -1086,7 +1519,9 @@ func (s *holderSyncer) syncFragment(index, field, view string, shard uint64) err
 		return errors.Wrap(err, "creating fragment")
 	}
 
-	// Sync fragments together.
+	// Sync fragments together. Once synced, the fragment holds the writes
+	// applied to the other replicas before the sync started.
+	start := time.Now()
 	fs := fragmentSyncer{
 		Fragment: frag,
 		Node:     s.Node,
@@ -1095,6 +1530,8 @@ func (s *holderSyncer) syncFragment(index, field, view string, shard uint64) err
 	}
 	if err := fs.syncFragment(); err != nil {
 		return errors.Wrap(err, "syncing fragment")
+	} else if !s.IsClosing() {
+		frag.setCaughtUp(start)
 	}
 
 	return nil
@@ -20,9 +20,13 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/pilosa/pilosa/v2/roaring"
+	"github.com/pilosa/pilosa/v2/syswrap"
+	"github.com/pkg/errors"
 )
 
 type tHolder struct {
@@ -321,3 +325,126 @@ func TestHolderCleaner_Reopen(t *testing.T) {
 		t.Fatalf("couldn't close holder: %v", err)
 	}
 }
+
+// mockFileLimiter is a fileLimiter which raises the soft limit only up to a
+// fixed hard limit.
+type mockFileLimiter struct {
+	cur, max uint64
+}
+
+func (m *mockFileLimiter) FileLimit() (cur, max uint64, err error) {
+	return m.cur, m.max, nil
+}
+
+func (m *mockFileLimiter) SetFileLimit(cur, max uint64) error {
+	if max > m.max {
+		return syscall.EPERM
+	}
+	m.cur = cur
+	return nil
+}
+
+func TestHolder_FileLimit(t *testing.T) {
+	// newFragmentHolder returns the path of a closed holder with 10 fragments.
+	newFragmentHolder := func(t *testing.T) string {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		for shard := uint64(0); shard < 10; shard++ {
+			h.SetBit("i", "f", 1, shard*ShardWidth)
+		}
+		if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return h.Path
+	}
+	openHolder := func(path string, limits *mockFileLimiter) (*Holder, error) {
+		h := NewHolder()
+		h.Path = path
+		h.fileLimits = limits
+		return h, h.Open()
+	}
+
+	t.Run("TooLow", func(t *testing.T) {
+		path := newFragmentHolder(t)
+		defer os.RemoveAll(path)
+
+		limits := &mockFileLimiter{cur: 1030, max: 1030}
+		h, err := openHolder(path, limits)
+		if errors.Cause(err) != ErrFileLimitTooLow {
+			t.Fatalf("expected file limit error, got: %v", err)
+		} else if !strings.Contains(err.Error(), "10 fragments require 1034 open files but the limit is 1030") {
+			t.Fatalf("unexpected error message: %v", err)
+		} else if h.Index("i") != nil {
+			t.Fatal("expected no index to be opened")
+		}
+	})
+
+	t.Run("Raised", func(t *testing.T) {
+		path := newFragmentHolder(t)
+		defer os.RemoveAll(path)
+
+		limits := &mockFileLimiter{cur: 1030, max: 2 * fileLimit}
+		h, err := openHolder(path, limits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		if limits.cur != fileLimit {
+			t.Fatalf("expected limit to be raised to %d, got %d", fileLimit, limits.cur)
+		} else if h.resourceUsage().FileLimit != fileLimit {
+			t.Fatalf("unexpected resource usage: %+v", h.resourceUsage())
+		}
+	})
+
+	t.Run("MaxFileCount", func(t *testing.T) {
+		path := newFragmentHolder(t)
+		defer os.RemoveAll(path)
+
+		defer syswrap.SetMaxFileCount(syswrap.MaxFileCount())
+		syswrap.SetMaxFileCount(5)
+
+		h, err := openHolder(path, &mockFileLimiter{cur: 1027, max: 1027})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		if n := syswrap.MaxFileCount(); n != 3 {
+			t.Fatalf("expected max file count to be lowered to 3, got %d", n)
+		}
+	})
+}
+
+func TestHolder_PurgeExpiredTrash(t *testing.T) {
+	h := newHolder()
+	h.trashRetention = time.Hour
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	for _, name := range []string{"i0", "i1"} {
+		if _, err := h.CreateIndex(name, IndexOptions{}); err != nil {
+			t.Fatal(err)
+		} else if err := h.DeleteIndex(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(h.trashPath("i0"), trashDeletedFile), []byte(time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339Nano)), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	h.purgeExpiredTrash(time.Now())
+	entries, err := h.Trash()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(entries) != 1 || entries[0].Name != "i1" {
+		t.Fatalf("unexpected trash: %v", entries)
+	} else if !entries[0].PurgeAt.Equal(entries[0].DeletedAt.Add(time.Hour)) {
+		t.Fatalf("unexpected purge time: %v", entries[0])
+	}
+	if _, err := os.Stat(h.trashPath("i0")); !os.IsNotExist(err) {
+		t.Fatalf("expected expired entry to be removed: %v", err)
+	}
+}
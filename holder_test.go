@@ -17,6 +17,9 @@ package pilosa_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -271,6 +274,163 @@ func TestHolder_HasData(t *testing.T) {
 	})
 }
 
+// Ensure an index's shard width is persisted and cannot be changed.
+func TestHolder_ShardWidth(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	const width = 1 << 16
+	idx := hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{ShardWidth: width})
+	if _, err := idx.CreateField("f"); err != nil {
+		t.Fatal(err)
+	}
+	cols := []uint64{1, width + 1, 3*width + 2, ShardWidth + 3}
+	hldr.MustSetBits("i", "f", 10, cols...)
+
+	verify := func() {
+		t.Helper()
+		idx := hldr.Index("i")
+		if idx.ShardWidth() != width {
+			t.Fatalf("unexpected shard width: %d", idx.ShardWidth())
+		}
+		if shards := idx.AvailableShards().Slice(); !reflect.DeepEqual(shards, []uint64{0, 1, 3, ShardWidth / width}) {
+			t.Fatalf("unexpected shards: %v", shards)
+		}
+		if columns := hldr.ReadRow("i", "f", 10).Columns(); !reflect.DeepEqual(columns, cols) {
+			t.Fatalf("unexpected columns: %v", columns)
+		}
+	}
+	verify()
+
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	verify()
+
+	// Recreating with the same or an unset width is allowed.
+	if _, err := hldr.CreateIndexIfNotExists("i", pilosa.IndexOptions{ShardWidth: width}); err != nil {
+		t.Fatal(err)
+	} else if _, err := hldr.CreateIndexIfNotExists("i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the width is not.
+	if _, err := hldr.CreateIndexIfNotExists("i", pilosa.IndexOptions{ShardWidth: 2 * width}); !isConflictError(err) {
+		t.Fatalf("expected shard width mismatch, got: %v", err)
+	}
+
+	for _, w := range []uint64{width / 2, width + 1, 1 << 33} {
+		if _, err := hldr.CreateIndex("j", pilosa.IndexOptions{ShardWidth: w}); !isBadRequestError(err) {
+			t.Fatalf("width %d: expected invalid shard width, got: %v", w, err)
+		}
+	}
+}
+
+// Ensure the schema generation increases on every schema mutation and
+// survives a reopen.
+func TestHolder_SchemaGeneration(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	gen := hldr.SchemaGeneration()
+	expectIncrease := func(desc string) {
+		t.Helper()
+		if g := hldr.SchemaGeneration(); g <= gen {
+			t.Fatalf("%s: generation did not increase: %d <= %d", desc, g, gen)
+		} else {
+			gen = g
+		}
+	}
+
+	idx := hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{})
+	expectIncrease("create index")
+	if _, err := idx.CreateField("f"); err != nil {
+		t.Fatal(err)
+	}
+	expectIncrease("create field")
+	if _, err := idx.CreateFieldIfNotExists("f"); err != nil {
+		t.Fatal(err)
+	} else if g := hldr.SchemaGeneration(); g != gen {
+		t.Fatalf("generation changed without a mutation: %d != %d", g, gen)
+	}
+	if err := idx.DeleteField("f"); err != nil {
+		t.Fatal(err)
+	}
+	expectIncrease("delete field")
+	if err := hldr.DeleteIndex("i"); err != nil {
+		t.Fatal(err)
+	}
+	expectIncrease("delete index")
+
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if g := hldr.SchemaGeneration(); g != gen {
+		t.Fatalf("unexpected generation after reopen: %d != %d", g, gen)
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "Update the golden files of the tests.")
+
+// Ensure the schema of a holder is encoded to the same bytes regardless of
+// the order in which its indexes, fields and views were created.
+func TestHolder_SchemaGolden(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	for _, name := range []string{"i2", "i0", "i1"} {
+		idx := hldr.MustCreateIndexIfNotExists(name, pilosa.IndexOptions{TrackExistence: true})
+		if _, err := idx.CreateField("t", pilosa.OptFieldTypeTime("YMD")); err != nil {
+			t.Fatal(err)
+		} else if _, err := idx.CreateField("m", pilosa.OptFieldTypeMutex(pilosa.CacheTypeNone, 0)); err != nil {
+			t.Fatal(err)
+		} else if _, err := idx.CreateField("k", pilosa.OptFieldKeys()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, ts := range []string{"2019-03-02T10:00", "2018-12-31T23:00", "2019-03-01T00:00"} {
+		tm, err := time.Parse("2006-01-02T15:04", ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := hldr.Field("i1", "t").SetBit(1, 1, &tm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf, err := json.MarshalIndent(hldr.Schema(), "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = append(buf, '\n')
+	golden := filepath.Join("testdata", "schema.golden")
+	if *updateGolden {
+		if err := ioutil.WriteFile(golden, buf, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if exp, err := ioutil.ReadFile(golden); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(buf, exp) {
+		t.Fatalf("unexpected schema:\n%s\nexpected:\n%s", buf, exp)
+	}
+
+	// The views are loaded from disk in a different order.
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if other, err := json.MarshalIndent(hldr.Schema(), "", "\t"); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(append(other, '\n'), buf) {
+		t.Fatalf("unexpected schema after reopen:\n%s", other)
+	}
+}
+
 // Ensure holder can delete an index and its underlying files.
 func TestHolder_DeleteIndex(t *testing.T) {
 	hldr := test.MustOpenHolder()
@@ -298,6 +458,101 @@ func TestHolder_DeleteIndex(t *testing.T) {
 	}
 }
 
+// Ensure an index isn't closed by its deletion until its handles are closed,
+// and that it can't be acquired or recreated meanwhile.
+func TestHolder_AcquireIndex(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	hldr.SetBit("i", "f", 100, 200)
+	idx, err := hldr.AcquireIndex("i")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := make(chan error)
+	go func() { deleted <- hldr.DeleteIndex("i") }()
+
+	// Wait for the deletion to hide the index.
+	for hldr.Index("i") != nil {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := hldr.AcquireIndex("i"); err != pilosa.ErrIndexNotFound {
+		t.Fatalf("unexpected error acquiring deleted index: %v", err)
+	}
+	created := make(chan error)
+	go func() {
+		_, err := hldr.CreateIndex("i", pilosa.IndexOptions{})
+		created <- err
+	}()
+
+	// The index is still open through the handle.
+	select {
+	case err := <-deleted:
+		t.Fatalf("index deleted with a handle in use: %v", err)
+	case err := <-created:
+		t.Fatalf("index created while deleted: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if row, err := idx.Field("f").Row(100); err != nil {
+		t.Fatal(err)
+	} else if n := row.Count(); n != 1 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+
+	idx.Close()
+	idx.Close()
+	if err := <-deleted; err != nil {
+		t.Fatal(err)
+	} else if err := <-created; err != nil {
+		t.Fatal(err)
+	} else if idx := hldr.Index("i"); idx == nil || idx.Field("f") != nil {
+		t.Fatalf("expected new empty index: %v", idx)
+	}
+}
+
+// Ensure a deleted index is kept in the trash and can be restored.
+func TestHolder_RestoreIndex(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	hldr.SetBit("i", "f", 100, 200)
+	if err := hldr.DeleteIndex("i"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the trashed index isn't opened with the holder.
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if hldr.Index("i") != nil {
+		t.Fatal("expected deleted index to stay closed")
+	}
+	if entries, err := hldr.Trash(); err != nil {
+		t.Fatal(err)
+	} else if len(entries) != 1 || entries[0].Name != "i" || entries[0].DeletedAt.IsZero() {
+		t.Fatalf("unexpected trash: %v", entries)
+	}
+
+	if _, err := hldr.RestoreIndex("i"); err != nil {
+		t.Fatal(err)
+	} else if cols := hldr.Row("i", "f", 100).Columns(); !reflect.DeepEqual(cols, []uint64{200}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if _, err := hldr.RestoreIndex("i"); err == nil {
+		t.Fatal("expected error restoring existing index")
+	}
+
+	// Ensure a purged index bypasses the trash.
+	if err := hldr.PurgeIndex("i"); err != nil {
+		t.Fatal(err)
+	} else if entries, err := hldr.Trash(); err != nil {
+		t.Fatal(err)
+	} else if len(entries) != 0 {
+		t.Fatalf("unexpected trash: %v", entries)
+	}
+}
+
 // Ensure holder can sync with a remote holder.
 func TestHolderSyncer_SyncHolder(t *testing.T) {
 	c := test.MustNewCluster(t, 2)
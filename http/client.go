@@ -26,6 +26,7 @@ import (
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/pilosa/pilosa/v2"
 	"github.com/pilosa/pilosa/v2/encoding/proto"
@@ -103,11 +104,16 @@ func (c *InternalClient) maxShardByIndex(ctx context.Context) (map[string]uint64
 
 // Schema returns all index and field schema information.
 func (c *InternalClient) Schema(ctx context.Context) ([]*pilosa.IndexInfo, error) {
-	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.Schema")
+	return c.SchemaNode(ctx, c.defaultURI)
+}
+
+// SchemaNode returns all index and field schema information from a specific node.
+func (c *InternalClient) SchemaNode(ctx context.Context, uri *pilosa.URI) ([]*pilosa.IndexInfo, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.SchemaNode")
 	defer span.Finish()
 
 	// Execute request against the host.
-	u := c.defaultURI.Path("/schema")
+	u := uri.Path("/schema")
 
 	// Build request.
 	req, err := http.NewRequest("GET", u, nil)
@@ -132,6 +138,238 @@ func (c *InternalClient) Schema(ctx context.Context) ([]*pilosa.IndexInfo, error
 	return rsp.Indexes, nil
 }
 
+// NodeSettings returns the values of the settings which can be overridden for
+// the whole cluster on a node, by name.
+func (c *InternalClient) NodeSettings(ctx context.Context, uri *pilosa.URI) (map[string]*pilosa.NodeSetting, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.NodeSettings")
+	defer span.Finish()
+
+	req, err := http.NewRequest("GET", uri.Path("/internal/config"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var settings map[string]*pilosa.NodeSetting
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("json decode: %s", err)
+	}
+	return settings, nil
+}
+
+// SchemaGeneration returns the schema generation of a node.
+func (c *InternalClient) SchemaGeneration(ctx context.Context, uri *pilosa.URI) (uint64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.SchemaGeneration")
+	defer span.Finish()
+
+	req, err := http.NewRequest("GET", uri.Path("/internal/schema/generation"), nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var rsp struct {
+		Generation uint64 `json:"generation"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return 0, fmt.Errorf("json decode: %s", err)
+	}
+	return rsp.Generation, nil
+}
+
+// WarmJob returns the part of a warm job run by a node, or nil if the node
+// doesn't have the job.
+func (c *InternalClient) WarmJob(ctx context.Context, uri *pilosa.URI, id string) (*pilosa.WarmJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.WarmJob")
+	defer span.Finish()
+
+	req, err := http.NewRequest("GET", uri.Path(fmt.Sprintf("/jobs/%s?local=true", id)), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var job pilosa.WarmJob
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("json decode: %s", err)
+	}
+	return &job, nil
+}
+
+// FieldChanges returns up to limit changes of a field on the node at uri
+// which follow the sequence number since, without waiting for changes.
+func (c *InternalClient) FieldChanges(ctx context.Context, uri *pilosa.URI, index, field string, since uint64, limit int) (*pilosa.FieldChanges, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FieldChanges")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/changes", index, field))
+	u.RawQuery = url.Values{
+		"since": {strconv.FormatUint(since, 10)},
+		"limit": {strconv.Itoa(limit)},
+	}.Encode()
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var changes pilosa.FieldChanges
+	if err := json.NewDecoder(resp.Body).Decode(&changes); err != nil {
+		return nil, fmt.Errorf("json decode: %s", err)
+	}
+	return &changes, nil
+}
+
+// DeleteIndex deletes an index on the default node. An index which doesn't
+// exist is ignored.
+func (c *InternalClient) DeleteIndex(ctx context.Context, index string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.DeleteIndex")
+	defer span.Finish()
+
+	return c.delete(ctx, fmt.Sprintf("/index/%s", index))
+}
+
+// DeleteField deletes a field on the default node. A field which doesn't
+// exist is ignored.
+func (c *InternalClient) DeleteField(ctx context.Context, index, field string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.DeleteField")
+	defer span.Finish()
+
+	return c.delete(ctx, fmt.Sprintf("/index/%s/field/%s", index, field))
+}
+
+// delete sends a DELETE request for path to the default node.
+func (c *InternalClient) delete(ctx context.Context, path string) error {
+	req, err := http.NewRequest("DELETE", c.defaultURI.Path(path), nil)
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ExportSchema returns the full schema of the default node and its schema
+// generation.
+func (c *InternalClient) ExportSchema(ctx context.Context) (*pilosa.Schema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ExportSchema")
+	defer span.Finish()
+
+	req, err := http.NewRequest("GET", c.defaultURI.Path("/schema/export"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/x-protobuf")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading")
+	}
+	schema := &pilosa.Schema{}
+	if err := c.serializer.Unmarshal(body, schema); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling schema")
+	}
+	return schema, nil
+}
+
+// RebuildExistence rebuilds the existence of the columns of an index on every
+// node from its fields, and returns the number of columns of the index.
+func (c *InternalClient) RebuildExistence(ctx context.Context, index string) (uint64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.RebuildExistence")
+	defer span.Finish()
+
+	req, err := http.NewRequest("POST", c.defaultURI.Path(fmt.Sprintf("/index/%s/existence/rebuild", index)), nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var rsp struct {
+		Columns uint64 `json:"columns"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return 0, errors.Wrap(err, "decoding response")
+	}
+	return rsp.Columns, nil
+}
+
+// ProvisionSchema applies a schema from ExportSchema to the default node
+// only.
+func (c *InternalClient) ProvisionSchema(ctx context.Context, s *pilosa.Schema) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ProvisionSchema")
+	defer span.Finish()
+
+	buf, err := c.serializer.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "marshalling schema")
+	}
+	req, err := http.NewRequest("POST", c.defaultURI.Path("/schema/apply"), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(resp.Body.Close(), "closing response body")
+}
+
 func (c *InternalClient) PostSchema(ctx context.Context, uri *pilosa.URI, s *pilosa.Schema, remote bool) error {
 	u := uri.Path(fmt.Sprintf("/schema?remote=%v", remote))
 	buf, err := json.Marshal(s)
@@ -226,6 +464,42 @@ func (c *InternalClient) FragmentNodes(ctx context.Context, index string, shard
 	return a, nil
 }
 
+// OwnersForShards returns the owners of shards of an index in the order in
+// which they should be read, with the generation of the topology they were
+// computed from. Clients which route the queries of a shard to the same node
+// can cache it until the generation changes.
+func (c *InternalClient) OwnersForShards(ctx context.Context, index string, shards []uint64) (*pilosa.RoutingTable, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.OwnersForShards")
+	defer span.Finish()
+
+	a := make([]string, len(shards))
+	for i, shard := range shards {
+		a[i] = strconv.FormatUint(shard, 10)
+	}
+	u := uriPathToURL(c.defaultURI, "/internal/routing")
+	u.RawQuery = (url.Values{"index": {index}, "shards": {strings.Join(a, ",")}}).Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var table pilosa.RoutingTable
+	if err := json.NewDecoder(resp.Body).Decode(&table); err != nil {
+		return nil, fmt.Errorf("json decode: %s", err)
+	}
+	return &table, nil
+}
+
 // Nodes returns a list of all nodes.
 func (c *InternalClient) Nodes(ctx context.Context) ([]*pilosa.Node, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.Nodes")
@@ -462,14 +736,33 @@ func (c *InternalClient) marshalImportPayload(index, field string, shard uint64,
 	return buf, nil
 }
 
-// importNode sends a pre-marshaled import request to a node.
+// importNode sends a pre-marshaled import request to a node. The request is
+// strictly routed: if the node doesn't own the shard, the import is sent to
+// the nodes which it lists as owners instead.
 func (c *InternalClient) importNode(ctx context.Context, node *pilosa.Node, index, field string, buf []byte, opts *pilosa.ImportOptions) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.importNode")
 	defer span.Finish()
 
+	owners, err := c.importURI(ctx, &node.URI, index, field, buf, opts)
+	if err != nil || len(owners) == 0 {
+		return err
+	}
+	for _, uri := range owners {
+		if other, err := c.importURI(ctx, uri, index, field, buf, opts); err != nil {
+			return errors.Wrapf(err, "importing to owner %s", uri)
+		} else if len(other) > 0 {
+			return errors.Errorf("owner %s does not own shard", uri)
+		}
+	}
+	return nil
+}
+
+// importURI sends a pre-marshaled import request to the node at uri. If the
+// node doesn't own the shard, the owners it lists are returned.
+func (c *InternalClient) importURI(ctx context.Context, uri *pilosa.URI, index, field string, buf []byte, opts *pilosa.ImportOptions) ([]*pilosa.URI, error) {
 	// Create URL & HTTP request.
 	path := fmt.Sprintf("/index/%s/field/%s/import", index, field)
-	u := nodePathToURL(node, path)
+	u := uriPathToURL(uri, path)
 
 	vals := url.Values{}
 	if opts.Clear {
@@ -478,38 +771,66 @@ func (c *InternalClient) importNode(ctx context.Context, node *pilosa.Node, inde
 	if opts.IgnoreKeyCheck {
 		vals.Set("ignoreKeyCheck", "true")
 	}
+	if opts.OperationID != "" {
+		vals.Set("operationID", opts.OperationID)
+	}
+	if opts.DropTimestamps {
+		vals.Set("dropTimestamps", "true")
+	}
 	url := fmt.Sprintf("%s?%s", u.String(), vals.Encode())
 
 	req, err := http.NewRequest("POST", url, bytes.NewReader(buf))
 	if err != nil {
-		return errors.Wrap(err, "creating request")
+		return nil, errors.Wrap(err, "creating request")
 	}
 	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
 	req.Header.Set("Content-Type", "application/x-protobuf")
 	req.Header.Set("Accept", "application/x-protobuf")
 	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set(HeaderRouting, "strict")
 
 	// Execute request against the host.
 	resp, err := c.executeRequest(req.WithContext(ctx))
-	if err != nil {
-		return err
+	if resp != nil && resp.StatusCode == http.StatusMisdirectedRequest {
+		return shardOwnerURIs(resp.Header.Get(HeaderShardOwners))
+	} else if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Read body and unmarshal response.
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "reading")
+		return nil, errors.Wrap(err, "reading")
 	}
 
 	var isresp pilosa.ImportResponse
 	if err := c.serializer.Unmarshal(body, &isresp); err != nil {
-		return fmt.Errorf("unmarshal import response: %s", err)
+		return nil, fmt.Errorf("unmarshal import response: %s", err)
 	} else if s := isresp.Err; s != "" {
-		return errors.New(s)
+		return nil, errors.New(s)
 	}
 
-	return nil
+	return nil, nil
+}
+
+// shardOwnerURIs parses the shard owners header of a response.
+func shardOwnerURIs(header string) ([]*pilosa.URI, error) {
+	var uris []*pilosa.URI
+	for _, s := range strings.Split(header, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		uri, err := pilosa.NewURIFromAddress(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing shard owner")
+		}
+		uris = append(uris, uri)
+	}
+	if len(uris) == 0 {
+		return nil, errors.New("misdirected request without shard owners")
+	}
+	return uris, nil
 }
 
 // ImportValue bulk imports field values for a single shard to a host.
@@ -799,8 +1120,12 @@ func (c *InternalClient) CreateFieldWithOptions(ctx context.Context, index, fiel
 		fieldOpt.CacheType = &opt.CacheType
 		fieldOpt.CacheSize = &opt.CacheSize
 	} else if fieldOpt.Type == "int" {
-		fieldOpt.Min = &opt.Min
-		fieldOpt.Max = &opt.Max
+		min := json.Number(pilosa.FormatDecimal(opt.Min, opt.Scale))
+		max := json.Number(pilosa.FormatDecimal(opt.Max, opt.Scale))
+		fieldOpt.Min, fieldOpt.Max = &min, &max
+		if opt.Scale != 0 {
+			fieldOpt.Scale = &opt.Scale
+		}
 	} else if fieldOpt.Type == "time" {
 		fieldOpt.TimeQuantum = &opt.TimeQuantum
 	}
@@ -930,6 +1255,91 @@ func (c *InternalClient) BlockData(ctx context.Context, uri *pilosa.URI, index,
 	return rsp.RowIDs, rsp.ColumnIDs, nil
 }
 
+// FragmentBlockChecksums returns a list of block checksums for a fragment on a
+// host using the public fragment blocks endpoint. Returns
+// pilosa.ErrFragmentNotFound if the host does not have the fragment.
+func (c *InternalClient) FragmentBlockChecksums(ctx context.Context, uri *pilosa.URI, index, field, view string, shard uint64) ([]pilosa.FragmentBlock, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FragmentBlockChecksums")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, "/fragment/blocks")
+	u.RawQuery = url.Values{
+		"index": {index},
+		"field": {field},
+		"view":  {view},
+		"shard": {strconv.FormatUint(shard, 10)},
+	}.Encode()
+
+	var rsp pilosa.FragmentBlocksResponse
+	if err := c.getProtobuf(ctx, u.String(), &rsp); err != nil {
+		return nil, err
+	}
+	return rsp.Blocks, nil
+}
+
+// FragmentBlockPairs returns a page of row/column pairs for a block using the
+// public fragment block data endpoint. Returns pilosa.ErrFragmentNotFound if
+// the host does not have the fragment.
+func (c *InternalClient) FragmentBlockPairs(ctx context.Context, uri *pilosa.URI, req *pilosa.BlockDataRequest) (*pilosa.BlockDataResponse, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FragmentBlockPairs")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, "/fragment/block/data")
+	q := url.Values{
+		"index": {req.Index},
+		"field": {req.Field},
+		"view":  {req.View},
+		"shard": {strconv.FormatUint(req.Shard, 10)},
+		"block": {strconv.FormatUint(req.Block, 10)},
+	}
+	if req.Continuation != 0 {
+		q.Set("continuation", strconv.FormatUint(req.Continuation, 10))
+	}
+	if req.Limit != 0 {
+		q.Set("limit", strconv.FormatUint(req.Limit, 10))
+	}
+	u.RawQuery = q.Encode()
+
+	var rsp pilosa.BlockDataResponse
+	if err := c.getProtobuf(ctx, u.String(), &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+// getProtobuf executes a GET request for a protobuf response and decodes the
+// response into msg.
+func (c *InternalClient) getProtobuf(ctx context.Context, u string, msg pilosa.Message) error {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/x-protobuf")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return pilosa.ErrFragmentNotFound
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if body, err := ioutil.ReadAll(resp.Body); err != nil {
+		return errors.Wrap(err, "reading")
+	} else if err := c.serializer.Unmarshal(body, msg); err != nil {
+		return errors.Wrap(err, "unmarshalling")
+	}
+	return nil
+}
+
 // ColumnAttrDiff returns data from differing blocks on a remote host.
 func (c *InternalClient) ColumnAttrDiff(ctx context.Context, uri *pilosa.URI, index string, blks []pilosa.AttrBlock) (map[uint64]map[string]interface{}, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ColumnAttrDiff")
@@ -1162,9 +1572,15 @@ func (p Bits) Timestamps() []int64 {
 
 // GroupByShard returns a map of bits by shard.
 func (p Bits) GroupByShard() map[uint64][]pilosa.Bit {
+	return p.GroupByShardWidth(pilosa.ShardWidth)
+}
+
+// GroupByShardWidth returns a map of bits by shard for an index with the
+// given shard width.
+func (p Bits) GroupByShardWidth(shardWidth uint64) map[uint64][]pilosa.Bit {
 	m := make(map[uint64][]pilosa.Bit)
 	for _, bit := range p {
-		shard := bit.ColumnID / pilosa.ShardWidth
+		shard := bit.ColumnID / shardWidth
 		m[shard] = append(m[shard], bit)
 	}
 
@@ -1231,9 +1647,15 @@ func (p FieldValues) Values() []int64 {
 
 // GroupByShard returns a map of field values by shard.
 func (p FieldValues) GroupByShard() map[uint64][]pilosa.FieldValue {
+	return p.GroupByShardWidth(pilosa.ShardWidth)
+}
+
+// GroupByShardWidth returns a map of field values by shard for an index with
+// the given shard width.
+func (p FieldValues) GroupByShardWidth(shardWidth uint64) map[uint64][]pilosa.FieldValue {
 	m := make(map[uint64][]pilosa.FieldValue)
 	for _, val := range p {
-		shard := val.ColumnID / pilosa.ShardWidth
+		shard := val.ColumnID / shardWidth
 		m[shard] = append(m[shard], val)
 	}
 
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	gohttp "net/http"
 	"reflect"
@@ -843,6 +844,152 @@ func TestClient_ImportIDs(t *testing.T) {
 	})
 }
 
+// Ensure single-shard writes to a node which doesn't own the shard are
+// forwarded, or refused with the owners when strictly routed.
+func TestClient_Routing(t *testing.T) {
+	cluster := test.MustRunCluster(t, 2)
+	defer cluster.Close()
+	ctx := context.Background()
+	c := MustNewClient(cluster[0].URL(), http.GetHTTPClient(nil))
+
+	nodes, err := c.Nodes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var coordinator string
+	for _, node := range nodes {
+		if node.IsCoordinator {
+			coordinator = node.ID
+		}
+	}
+
+	// Find an index whose shard 0 isn't owned by the coordinator.
+	var index string
+	var owner *pilosa.Node
+	for i := 0; owner == nil; i++ {
+		index = fmt.Sprintf("i%d", i)
+		owners, err := cluster[0].API.ShardNodes(ctx, index, 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if owners[0].ID != coordinator {
+			owner = owners[0]
+		}
+	}
+	var coord *test.Command
+	for _, cmd := range cluster {
+		if cmd.API.Node().ID == coordinator {
+			coord = cmd
+		}
+	}
+	cluster.CreateField(t, index, pilosa.IndexOptions{}, "f")
+
+	// The routing table lists the same owners as the fragment nodes, on
+	// every node.
+	table, err := c.OwnersForShards(ctx, index, []uint64{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(table.Shards) != 2 {
+		t.Fatalf("unexpected shards: %+v", table.Shards)
+	}
+	for _, s := range table.Shards {
+		nodes, err := c.FragmentNodes(ctx, index, s.Shard)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(nodes) != len(s.Owners) || nodes[0].URI.String() != s.Owners[0] {
+			t.Fatalf("unexpected owners of shard %d: %v, nodes %v", s.Shard, s.Owners, nodes)
+		}
+	}
+	other, err := MustNewClient(cluster[1].URL(), http.GetHTTPClient(nil)).OwnersForShards(ctx, index, []uint64{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(other, table) {
+		t.Fatalf("unexpected routing table: %+v, want %+v", other, table)
+	}
+
+	query := func(strict bool, pql string) *gohttp.Response {
+		t.Helper()
+		req, err := gohttp.NewRequest("POST", coord.URL()+"/index/"+index+"/query", bytes.NewBufferString(pql))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strict {
+			req.Header.Set(http.HeaderRouting, "strict")
+		}
+		resp, err := gohttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := query(true, "Set(1, f=1)"); resp.StatusCode != gohttp.StatusMisdirectedRequest {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	} else if s := resp.Header.Get(http.HeaderShardOwners); s != owner.URI.String() {
+		t.Fatalf("unexpected owners: %q", s)
+	}
+	if resp := query(false, "Set(1, f=1)"); resp.StatusCode != gohttp.StatusOK {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	} else if s := resp.Header.Get(http.HeaderForwardedTo); s != owner.URI.String() {
+		t.Fatalf("unexpected forwarded header: %q", s)
+	}
+	if resp := query(true, "Count(Row(f=1))"); resp.StatusCode != gohttp.StatusOK {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	} else if s := resp.Header.Get(http.HeaderForwardedTo); s != "" {
+		t.Fatalf("unexpected forwarded header: %q", s)
+	}
+
+	// The client follows the owners given by the coordinator.
+	if err := c.ImportK(ctx, index, "f", []pilosa.Bit{{RowID: 2, ColumnID: 1}, {RowID: 2, ColumnID: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	res := cluster.Query(t, index, "Count(Row(f=1)) Count(Row(f=2))")
+	if res.Results[0].(uint64) != 1 || res.Results[1].(uint64) != 2 {
+		t.Fatalf("unexpected results: %v", res.Results)
+	}
+}
+
+// Ensure row results can be returned as roaring bitmaps.
+func TestClient_QueryRoaring(t *testing.T) {
+	cluster := test.MustRunCluster(t, 1)
+	defer cluster.Close()
+	cluster.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	cluster.Query(t, "i", fmt.Sprintf("Set(1, f=1) Set(%d, f=1) Set(2, f=2)", 3*pilosa.ShardWidth+2))
+
+	c := MustNewClient(cluster[0].URL(), http.GetHTTPClient(nil))
+	resp, err := c.Query(context.Background(), "i", &pilosa.QueryRequest{Query: "Row(f=1) Count(Row(f=2))", Roaring: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if cols := resp.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, []uint64{1, 3*pilosa.ShardWidth + 2}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if n := resp.Results[1].(uint64); n != 1 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+
+	// JSON responses carry the bitmap base64-encoded.
+	hresp, err := gohttp.Post(cluster[0].URL()+"/index/i/query?roaring=true", "text/plain", bytes.NewBufferString("Row(f=1)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hresp.Body.Close()
+	var body struct {
+		Results []struct {
+			Columns []uint64 `json:"columns"`
+			Roaring []byte   `json:"roaring"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(hresp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	} else if len(body.Results[0].Columns) != 0 {
+		t.Fatalf("unexpected columns: %v", body.Results[0].Columns)
+	}
+	if r, err := pilosa.NewRowFromRoaring(body.Results[0].Roaring); err != nil {
+		t.Fatal(err)
+	} else if cols := r.Columns(); !reflect.DeepEqual(cols, []uint64{1, 3*pilosa.ShardWidth + 2}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+}
+
 // Ensure client can bulk import value data.
 func TestClient_ImportValue(t *testing.T) {
 	cluster := test.MustRunCluster(t, 1)
@@ -15,8 +15,10 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/csv"
 	"encoding/json"
 	"expvar"
 	"fmt"
@@ -29,6 +31,7 @@ import (
 	"net/url"
 	"reflect"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -40,6 +43,8 @@ import (
 	"github.com/pilosa/pilosa/v2/tracing"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 // Handler represents an HTTP handler.
@@ -57,6 +62,11 @@ type Handler struct {
 
 	closeTimeout time.Duration
 
+	http2 HTTP2Options
+
+	// conns tracks the connections of the listener when HTTP/2 is enabled.
+	conns *connListener
+
 	server *http.Server
 }
 
@@ -120,6 +130,15 @@ func OptHandlerCloseTimeout(d time.Duration) handlerOption {
 	}
 }
 
+// OptHandlerHTTP2 serves HTTP/2 requests, including HTTP/2 requests without
+// TLS (h2c), if it is enabled.
+func OptHandlerHTTP2(opt HTTP2Options) handlerOption {
+	return func(h *Handler) error {
+		h.http2 = opt
+		return nil
+	}
+}
+
 // NewHandler returns a new instance of Handler with a default logger.
 func NewHandler(opts ...handlerOption) (*Handler, error) {
 	handler := &Handler{
@@ -145,6 +164,17 @@ func NewHandler(opts ...handlerOption) (*Handler, error) {
 	}
 
 	handler.server = &http.Server{Handler: handler}
+	if handler.http2.Enabled {
+		// Configuring the server for HTTP/2 also closes the h2c connections
+		// gracefully on shutdown.
+		h2s := &http2.Server{MaxConcurrentStreams: uint32(handler.http2.withDefaults().MaxStreamsPerConn)}
+		if err := http2.ConfigureServer(handler.server, h2s); err != nil {
+			return nil, errors.Wrap(err, "configuring HTTP/2")
+		}
+		handler.server.Handler = h2c.NewHandler(handler, h2s)
+		handler.conns = newConnListener(handler.ln)
+		handler.ln = handler.conns
+	}
 
 	return handler, nil
 }
@@ -167,12 +197,19 @@ func (h *Handler) Close() error {
 	if err != nil {
 		err = h.server.Close()
 	}
+	// h2c connections are hijacked from the server, which doesn't close
+	// them.
+	if h.conns != nil {
+		h.conns.closeConns()
+	}
 	return errors.Wrap(err, "shutdown/close http server")
 }
 
 func (h *Handler) populateValidators() {
 	h.validators = map[string]*queryValidationSpec{}
 	h.validators["Home"] = queryValidationSpecRequired()
+	h.validators["GetClusterConfig"] = queryValidationSpecRequired()
+	h.validators["PatchClusterConfig"] = queryValidationSpecRequired()
 	h.validators["PostClusterResizeAbort"] = queryValidationSpecRequired()
 	h.validators["PostClusterResizeRemoveNode"] = queryValidationSpecRequired()
 	h.validators["PostClusterResizeSetCoordinator"] = queryValidationSpecRequired()
@@ -180,28 +217,62 @@ func (h *Handler) populateValidators() {
 	h.validators["GetIndexes"] = queryValidationSpecRequired()
 	h.validators["GetIndex"] = queryValidationSpecRequired()
 	h.validators["PostIndex"] = queryValidationSpecRequired()
-	h.validators["DeleteIndex"] = queryValidationSpecRequired()
+	h.validators["DeleteIndex"] = queryValidationSpecRequired().Optional("purge")
+	h.validators["GetTrash"] = queryValidationSpecRequired()
+	h.validators["PostTrashRestore"] = queryValidationSpecRequired()
+	h.validators["DeleteTrash"] = queryValidationSpecRequired()
+	h.validators["PatchIndex"] = queryValidationSpecRequired()
+	h.validators["GetFieldSnapshots"] = queryValidationSpecRequired()
+	h.validators["GetFieldChanges"] = queryValidationSpecRequired().Optional("since", "limit", "wait")
+	h.validators["GetFieldViews"] = queryValidationSpecRequired()
+	h.validators["PostView"] = queryValidationSpecRequired()
+	h.validators["DeleteView"] = queryValidationSpecRequired().Optional("force")
 	h.validators["GetTranslateData"] = queryValidationSpecRequired("offset")
 	h.validators["PostTranslateKeys"] = queryValidationSpecRequired()
 	h.validators["PostField"] = queryValidationSpecRequired()
+	h.validators["GetIngestMapping"] = queryValidationSpecRequired()
+	h.validators["PostIngestMapping"] = queryValidationSpecRequired()
+	h.validators["DeleteIngestMapping"] = queryValidationSpecRequired()
+	h.validators["PostInput"] = queryValidationSpecRequired()
+	h.validators["PostDeleteColumns"] = queryValidationSpecRequired()
+	h.validators["PostWarmJob"] = queryValidationSpecRequired()
+	h.validators["GetJob"] = queryValidationSpecRequired().Optional("local")
+	h.validators["PostJobResume"] = queryValidationSpecRequired()
 	h.validators["DeleteField"] = queryValidationSpecRequired()
-	h.validators["PostImport"] = queryValidationSpecRequired().Optional("clear", "ignoreKeyCheck")
+	h.validators["PostTransaction"] = queryValidationSpecRequired().Optional("operationID")
+	h.validators["PostImport"] = queryValidationSpecRequired().Optional("clear", "ignoreKeyCheck", "operationID", "dropTimestamps")
 	h.validators["PostImportRoaring"] = queryValidationSpecRequired().Optional("remote", "clear")
-	h.validators["PostQuery"] = queryValidationSpecRequired().Optional("shards", "columnAttrs", "excludeRowAttrs", "excludeColumns")
+	h.validators["PostQuery"] = queryValidationSpecRequired().Optional("shards", "columnAttrs", "excludeRowAttrs", "excludeColumns", "session", "storeAs", "snapshot", "asOf", "roaring", "estimate", "maxStaleness")
 	h.validators["GetInfo"] = queryValidationSpecRequired()
 	h.validators["RecalculateCaches"] = queryValidationSpecRequired()
+	h.validators["PostExistenceRebuild"] = queryValidationSpecRequired()
+	h.validators["DeleteSession"] = queryValidationSpecRequired()
 	h.validators["GetSchema"] = queryValidationSpecRequired()
 	h.validators["PostSchema"] = queryValidationSpecRequired().Optional("remote")
+	h.validators["GetSchemaExport"] = queryValidationSpecRequired()
+	h.validators["PostSchemaApply"] = queryValidationSpecRequired()
+	h.validators["GetSchemaGeneration"] = queryValidationSpecRequired()
 	h.validators["GetStatus"] = queryValidationSpecRequired()
+	h.validators["GetStatsHistory"] = queryValidationSpecRequired().Optional("index", "window")
+	h.validators["GetReplication"] = queryValidationSpecRequired()
+	h.validators["GetReadyz"] = queryValidationSpecRequired()
+	h.validators["PostMaintenance"] = queryValidationSpecRequired()
+	h.validators["DeleteMaintenance"] = queryValidationSpecRequired()
 	h.validators["GetVersion"] = queryValidationSpecRequired()
 	h.validators["PostClusterMessage"] = queryValidationSpecRequired()
+	h.validators["GetPublicFragmentBlocks"] = queryValidationSpecRequired("index", "field", "view", "shard")
+	h.validators["GetPublicFragmentBlockData"] = queryValidationSpecRequired("index", "field", "view", "shard", "block").Optional("continuation", "limit")
 	h.validators["GetFragmentBlockData"] = queryValidationSpecRequired()
 	h.validators["GetFragmentBlocks"] = queryValidationSpecRequired("index", "field", "view", "shard")
 	h.validators["GetFragmentData"] = queryValidationSpecRequired("index", "field", "view", "shard")
+	h.validators["PostFragmentCompact"] = queryValidationSpecRequired("index", "field", "view", "shard")
+	h.validators["GetFragmentInspect"] = queryValidationSpecRequired("index", "field", "view", "shard").Optional("rows", "blocks")
 	h.validators["GetFragmentNodes"] = queryValidationSpecRequired("shard", "index")
+	h.validators["GetRouting"] = queryValidationSpecRequired("index").Optional("shard", "shards")
 	h.validators["PostIndexAttrDiff"] = queryValidationSpecRequired()
 	h.validators["PostFieldAttrDiff"] = queryValidationSpecRequired()
 	h.validators["GetNodes"] = queryValidationSpecRequired()
+	h.validators["GetNodeConfig"] = queryValidationSpecRequired()
 	h.validators["GetShardMax"] = queryValidationSpecRequired()
 }
 
@@ -235,6 +306,19 @@ func (h *Handler) extractTracing(next http.Handler) http.Handler {
 	})
 }
 
+// extractAuditInfo identifies the client of a request in the audit log by
+// the common name of its TLS client certificate, if any, and its address.
+func (h *Handler) extractAuditInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var principal string
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			principal = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		ctx := pilosa.WithAuditInfo(r.Context(), principal, r.RemoteAddr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func (h *Handler) collectStats(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t := time.Now()
@@ -274,6 +358,8 @@ func (h *Handler) collectStats(next http.Handler) http.Handler {
 func newRouter(handler *Handler) *mux.Router {
 	router := mux.NewRouter()
 	router.HandleFunc("/", handler.handleHome).Methods("GET").Name("Home")
+	router.HandleFunc("/cluster/config", handler.handleGetClusterConfig).Methods("GET").Name("GetClusterConfig")
+	router.HandleFunc("/cluster/config", handler.handlePatchClusterConfig).Methods("PATCH").Name("PatchClusterConfig")
 	router.HandleFunc("/cluster/resize/abort", handler.handlePostClusterResizeAbort).Methods("POST").Name("PostClusterResizeAbort")
 	router.HandleFunc("/cluster/resize/remove-node", handler.handlePostClusterResizeRemoveNode).Methods("POST").Name("PostClusterResizeRemoveNode")
 	router.HandleFunc("/cluster/resize/set-coordinator", handler.handlePostClusterResizeSetCoordinator).Methods("POST").Name("PostClusterResizeSetCoordinator")
@@ -281,44 +367,81 @@ func newRouter(handler *Handler) *mux.Router {
 	router.Handle("/debug/vars", expvar.Handler()).Methods("GET")
 	router.Handle("/metrics", promhttp.Handler())
 	router.HandleFunc("/export", handler.handleGetExport).Methods("GET").Name("GetExport")
+	router.HandleFunc("/fragment/blocks", handler.handleGetPublicFragmentBlocks).Methods("GET").Name("GetPublicFragmentBlocks")
+	router.HandleFunc("/fragment/block/data", handler.handleGetPublicFragmentBlockData).Methods("GET").Name("GetPublicFragmentBlockData")
 	router.HandleFunc("/index", handler.handleGetIndexes).Methods("GET").Name("GetIndexes")
 	router.HandleFunc("/index", handler.handlePostIndex).Methods("POST").Name("PostIndex")
 	router.HandleFunc("/index/", handler.handlePostIndex).Methods("POST").Name("PostIndex")
 	router.HandleFunc("/index/{index}", handler.handleGetIndex).Methods("GET").Name("GetIndex")
 	router.HandleFunc("/index/{index}", handler.handlePostIndex).Methods("POST").Name("PostIndex")
 	router.HandleFunc("/index/{index}", handler.handleDeleteIndex).Methods("DELETE").Name("DeleteIndex")
+	router.HandleFunc("/index/{index}", handler.handlePatchIndex).Methods("PATCH").Name("PatchIndex")
 	//router.HandleFunc("/index/{index}/field", handler.handleGetFields).Methods("GET") // Not implemented.
+	router.HandleFunc("/index/{index}/field/{field}", handler.handleGetField).Methods("GET").Name("GetField")
 	router.HandleFunc("/index/{index}/field/{field}", handler.handlePostField).Methods("POST").Name("PostField")
 	router.HandleFunc("/index/{index}/field", handler.handlePostField).Methods("POST").Name("PostField")
 	router.HandleFunc("/index/{index}/field/", handler.handlePostField).Methods("POST").Name("PostField")
 	router.HandleFunc("/index/{index}/field/{field}", handler.handleDeleteField).Methods("DELETE").Name("DeleteField")
+	router.HandleFunc("/index/{index}/field/{field}", handler.handlePatchField).Methods("PATCH").Name("PatchField")
+	router.HandleFunc("/index/{index}/field/{field}/snapshots", handler.handleGetFieldSnapshots).Methods("GET").Name("GetFieldSnapshots")
+	router.HandleFunc("/index/{index}/field/{field}/changes", handler.handleGetFieldChanges).Methods("GET").Name("GetFieldChanges")
+	router.HandleFunc("/index/{index}/field/{field}/views", handler.handleGetFieldViews).Methods("GET").Name("GetFieldViews")
+	router.HandleFunc("/index/{index}/field/{field}/view/{view}", handler.handlePostView).Methods("POST").Name("PostView")
+	router.HandleFunc("/index/{index}/field/{field}/view/{view}", handler.handleDeleteView).Methods("DELETE").Name("DeleteView")
 	router.HandleFunc("/index/{index}/field/{field}/import", handler.handlePostImport).Methods("POST").Name("PostImport")
 	router.HandleFunc("/index/{index}/field/{field}/import-roaring/{shard}", handler.handlePostImportRoaring).Methods("POST").Name("PostImportRoaring")
+	router.HandleFunc("/index/{index}/ingest-mapping/{mapping}", handler.handleGetIngestMapping).Methods("GET").Name("GetIngestMapping")
+	router.HandleFunc("/index/{index}/ingest-mapping/{mapping}", handler.handlePostIngestMapping).Methods("POST").Name("PostIngestMapping")
+	router.HandleFunc("/index/{index}/ingest-mapping/{mapping}", handler.handleDeleteIngestMapping).Methods("DELETE").Name("DeleteIngestMapping")
+	router.HandleFunc("/index/{index}/delete-columns", handler.handlePostDeleteColumns).Methods("POST").Name("PostDeleteColumns")
+	router.HandleFunc("/index/{index}/input/{mapping}", handler.handlePostInput).Methods("POST").Name("PostInput")
 	router.HandleFunc("/index/{index}/query", handler.handlePostQuery).Methods("POST").Name("PostQuery")
+	router.HandleFunc("/index/{index}/transaction", handler.handlePostTransaction).Methods("POST").Name("PostTransaction")
 	router.HandleFunc("/info", handler.handleGetInfo).Methods("GET").Name("GetInfo")
+	router.HandleFunc("/maintenance", handler.handlePostMaintenance).Methods("POST").Name("PostMaintenance")
+	router.HandleFunc("/maintenance", handler.handleDeleteMaintenance).Methods("DELETE").Name("DeleteMaintenance")
+	router.HandleFunc("/jobs/warm", handler.handlePostWarmJob).Methods("POST").Name("PostWarmJob")
+	router.HandleFunc("/jobs/{id}", handler.handleGetJob).Methods("GET").Name("GetJob")
+	router.HandleFunc("/jobs/{id}/resume", handler.handlePostJobResume).Methods("POST").Name("PostJobResume")
+	router.HandleFunc("/index/{index}/existence/rebuild", handler.handlePostExistenceRebuild).Methods("POST").Name("PostExistenceRebuild")
+	router.HandleFunc("/readyz", handler.handleGetReadyz).Methods("GET").Name("GetReadyz")
 	router.HandleFunc("/recalculate-caches", handler.handleRecalculateCaches).Methods("POST").Name("RecalculateCaches")
 	router.HandleFunc("/schema", handler.handleGetSchema).Methods("GET").Name("GetSchema")
 	router.HandleFunc("/schema", handler.handlePostSchema).Methods("POST").Name("PostSchema")
+	router.HandleFunc("/schema/apply", handler.handlePostSchemaApply).Methods("POST").Name("PostSchemaApply")
+	router.HandleFunc("/schema/export", handler.handleGetSchemaExport).Methods("GET").Name("GetSchemaExport")
+	router.HandleFunc("/sessions/{session}", handler.handleDeleteSession).Methods("DELETE").Name("DeleteSession")
+	router.HandleFunc("/trash", handler.handleGetTrash).Methods("GET").Name("GetTrash")
+	router.HandleFunc("/trash/{index}", handler.handleDeleteTrash).Methods("DELETE").Name("DeleteTrash")
+	router.HandleFunc("/trash/{index}/restore", handler.handlePostTrashRestore).Methods("POST").Name("PostTrashRestore")
+	router.HandleFunc("/stats/history", handler.handleGetStatsHistory).Methods("GET").Name("GetStatsHistory")
+	router.HandleFunc("/replication", handler.handleGetReplication).Methods("GET").Name("GetReplication")
 	router.HandleFunc("/status", handler.handleGetStatus).Methods("GET").Name("GetStatus")
 	router.HandleFunc("/version", handler.handleGetVersion).Methods("GET").Name("GetVersion")
 
 	// /internal endpoints are for internal use only; they may change at any time.
 	// DO NOT rely on these for external applications!
 	router.HandleFunc("/internal/cluster/message", handler.handlePostClusterMessage).Methods("POST").Name("PostClusterMessage")
+	router.HandleFunc("/internal/config", handler.handleGetNodeConfig).Methods("GET").Name("GetNodeConfig")
 	router.HandleFunc("/internal/fragment/block/data", handler.handleGetFragmentBlockData).Methods("GET").Name("GetFragmentBlockData")
 	router.HandleFunc("/internal/fragment/blocks", handler.handleGetFragmentBlocks).Methods("GET").Name("GetFragmentBlocks")
+	router.HandleFunc("/internal/fragment/compact", handler.handlePostFragmentCompact).Methods("POST").Name("PostFragmentCompact")
 	router.HandleFunc("/internal/fragment/data", handler.handleGetFragmentData).Methods("GET").Name("GetFragmentData")
+	router.HandleFunc("/internal/fragment/inspect", handler.handleGetFragmentInspect).Methods("GET").Name("GetFragmentInspect")
 	router.HandleFunc("/internal/fragment/nodes", handler.handleGetFragmentNodes).Methods("GET").Name("GetFragmentNodes")
+	router.HandleFunc("/internal/routing", handler.handleGetRouting).Methods("GET").Name("GetRouting")
 	router.HandleFunc("/internal/index/{index}/attr/diff", handler.handlePostIndexAttrDiff).Methods("POST").Name("PostIndexAttrDiff")
 	router.HandleFunc("/internal/translate/data", handler.handlePostTranslateData).Methods("POST").Name("PostTranslateData")
 	router.HandleFunc("/internal/translate/keys", handler.handlePostTranslateKeys).Methods("POST").Name("PostTranslateKeys")
 	router.HandleFunc("/internal/index/{index}/field/{field}/attr/diff", handler.handlePostFieldAttrDiff).Methods("POST").Name("PostFieldAttrDiff")
 	router.HandleFunc("/internal/index/{index}/field/{field}/remote-available-shards/{shardID}", handler.handleDeleteRemoteAvailableShard).Methods("DELETE")
 	router.HandleFunc("/internal/nodes", handler.handleGetNodes).Methods("GET").Name("GetNodes")
+	router.HandleFunc("/internal/schema/generation", handler.handleGetSchemaGeneration).Methods("GET").Name("GetSchemaGeneration")
 	router.HandleFunc("/internal/shards/max", handler.handleGetShardsMax).Methods("GET").Name("GetShardsMax") // TODO: deprecate, but it's being used by the client
 
 	router.Use(handler.queryArgValidator)
 	router.Use(handler.extractTracing)
+	router.Use(handler.extractAuditInfo)
 	router.Use(handler.collectStats)
 	return router
 }
@@ -364,7 +487,11 @@ func (r *successResponse) check(err error) (statusCode int) {
 	case pilosa.NotFoundError:
 		statusCode = http.StatusNotFound
 	default:
-		statusCode = http.StatusInternalServerError
+		if cause == pilosa.ErrQuotaExceeded {
+			statusCode = http.StatusInsufficientStorage
+		} else {
+			statusCode = http.StatusInternalServerError
+		}
 	}
 
 	r.Success = false
@@ -424,6 +551,26 @@ func validHeaderAcceptJSON(header http.Header) bool {
 	return true
 }
 
+// setSchemaGenerationHeader returns the schema generation to the client as an
+// ETag which can be used in an If-Match header of a later schema mutation.
+func setSchemaGenerationHeader(w http.ResponseWriter, generation uint64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatUint(generation, 10)))
+}
+
+// schemaPreconditionContext returns the request context with the schema
+// generation from the If-Match header, if any, as a precondition.
+func schemaPreconditionContext(r *http.Request) (context.Context, error) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return r.Context(), nil
+	}
+	generation, err := strconv.ParseUint(strings.Trim(v, `"`), 10, 64)
+	if err != nil {
+		return nil, pilosa.NewBadRequestError(errors.Errorf("invalid If-Match header: %s", v))
+	}
+	return pilosa.WithSchemaGeneration(r.Context(), generation), nil
+}
+
 // handleGetSchema handles GET /schema requests.
 func (h *Handler) handleGetSchema(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -431,7 +578,9 @@ func (h *Handler) handleGetSchema(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	generation := h.api.SchemaGeneration(r.Context())
 	schema := h.api.Schema(r.Context())
+	setSchemaGenerationHeader(w, generation)
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{"indexes": schema}); err != nil { // TODO: use pilosa.Schema instead of map[string]interface{} here?
 		h.logger.Printf("write schema response error: %s", err)
 	}
@@ -458,22 +607,139 @@ func (h *Handler) handlePostSchema(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleGetSchemaExport handles GET /schema/export requests.
+func (h *Handler) handleGetSchemaExport(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/x-protobuf" {
+		http.Error(w, "Accept header must be application/x-protobuf", http.StatusNotAcceptable)
+		return
+	}
+
+	schema, err := h.api.ExportSchema(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	buf, err := h.api.Serializer.Marshal(schema)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("marshalling schema: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	setSchemaGenerationHeader(w, schema.Generation)
+	if _, err := w.Write(buf); err != nil {
+		h.logger.Printf("write schema export response error: %s", err)
+	}
+}
+
+// handlePostSchemaApply handles POST /schema/apply requests, which apply an
+// exported schema to the local node only.
+func (h *Handler) handlePostSchemaApply(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	} else if r.Header.Get("Content-Type") != "application/x-protobuf" {
+		http.Error(w, "Unsupported media type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// The schema of a node is otherwise only changed through the cluster,
+	// so over TLS the client must present a verified certificate.
+	if r.TLS != nil && len(r.TLS.VerifiedChains) == 0 {
+		http.Error(w, "client certificate required", http.StatusForbidden)
+		return
+	}
+
+	resp := successResponse{h: h}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	schema := &pilosa.Schema{}
+	if err := h.api.Serializer.Unmarshal(body, schema); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding schema")))
+		return
+	}
+	resp.write(w, h.api.ProvisionSchema(r.Context(), schema))
+}
+
+// handleGetSchemaGeneration handles GET /internal/schema/generation requests.
+func (h *Handler) handleGetSchemaGeneration(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	generation := h.api.SchemaGeneration(r.Context())
+	if err := json.NewEncoder(w).Encode(map[string]uint64{"generation": generation}); err != nil {
+		h.logger.Printf("json write error: %s", err)
+	}
+}
+
 // handleGetStatus handles GET /status requests.
 func (h *Handler) handleGetStatus(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
 		return
 	}
+	// Sort the nodes so that the response is stable for a given cluster.
+	nodes := h.api.Hosts(r.Context())
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].ID != nodes[j].ID {
+			return nodes[i].ID < nodes[j].ID
+		}
+		return nodes[i].URI.String() < nodes[j].URI.String()
+	})
 	status := getStatusResponse{
-		State:   h.api.State(),
-		Nodes:   h.api.Hosts(r.Context()),
-		LocalID: h.api.Node().ID,
+		State:     h.api.State(),
+		Nodes:     nodes,
+		LocalID:   h.api.Node().ID,
+		Resources: h.api.ResourceUsage(),
+		Admission: h.api.AdmissionStatus(),
+
+		ProtocolVersion: h.api.ProtocolVersion(),
 	}
 	if err := json.NewEncoder(w).Encode(status); err != nil {
 		h.logger.Printf("write status response error: %s", err)
 	}
 }
 
+// handleGetReadyz handles GET /readyz requests. It responds with 503 while
+// the node shouldn't receive traffic, so load balancers route around it.
+func (h *Handler) handleGetReadyz(w http.ResponseWriter, r *http.Request) {
+	readiness := h.api.Readiness()
+	w.Header().Set("Content-Type", "application/json")
+	if !readiness.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(readiness); err != nil {
+		h.logger.Printf("write readyz response error: %s", err)
+	}
+}
+
+// handlePostMaintenance handles POST /maintenance requests.
+func (h *Handler) handlePostMaintenance(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	resp := successResponse{h: h}
+	err := h.api.SetMaintenance(r.Context(), true)
+	resp.write(w, err)
+}
+
+// handleDeleteMaintenance handles DELETE /maintenance requests.
+func (h *Handler) handleDeleteMaintenance(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	resp := successResponse{h: h}
+	err := h.api.SetMaintenance(r.Context(), false)
+	resp.write(w, err)
+}
+
 func (h *Handler) handleGetInfo(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
@@ -490,9 +756,13 @@ type getSchemaResponse struct {
 }
 
 type getStatusResponse struct {
-	State   string         `json:"state"`
-	Nodes   []*pilosa.Node `json:"nodes"`
-	LocalID string         `json:"localID"`
+	State     string                 `json:"state"`
+	Nodes     []*pilosa.Node         `json:"nodes"`
+	LocalID   string                 `json:"localID"`
+	Resources pilosa.ResourceUsage   `json:"resources"`
+	Admission pilosa.AdmissionStatus `json:"admission"`
+
+	ProtocolVersion uint32 `json:"protocolVersion"`
 }
 
 // handlePostQuery handles /query requests.
@@ -510,11 +780,24 @@ func (h *Handler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 	// TODO: Remove
 	req.Index = mux.Vars(r)["index"]
 
-	resp, err := h.api.Query(r.Context(), req)
+	ctx, routing := requestRouting(r)
+	resp, err := h.api.Query(ctx, req)
+	writeRoutingHeaders(w, routing, err)
 	if err != nil {
 		switch errors.Cause(err) {
+		case pilosa.ErrClusterDoesNotOwnShard:
+			w.WriteHeader(http.StatusMisdirectedRequest)
 		case pilosa.ErrTooManyWrites:
 			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		case pilosa.ErrIndexReadOnly:
+			w.WriteHeader(http.StatusConflict)
+		case pilosa.ErrQuotaExceeded:
+			w.WriteHeader(http.StatusInsufficientStorage)
+		case pilosa.ErrResultHandleNotFound:
+			w.WriteHeader(http.StatusNotFound)
+		case pilosa.ErrMemoryPressure, pilosa.ErrSnapshotTimeout:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
 		case pilosa.ErrTranslateStoreReadOnly:
 			u := h.api.PrimaryReplicaNodeURL()
 			u.Path, u.RawQuery = r.URL.Path, r.URL.RawQuery
@@ -548,188 +831,652 @@ func (h *Handler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleGetShardsMax handles GET /internal/shards/max requests.
-func (h *Handler) handleGetShardsMax(w http.ResponseWriter, r *http.Request) {
+// handlePostTransaction handles POST /index/{index}/transaction requests.
+func (h *Handler) handlePostTransaction(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
 		return
 	}
-	if err := json.NewEncoder(w).Encode(getShardsMaxResponse{
-		Standard: h.api.MaxShards(r.Context()),
-	}); err != nil {
-		h.logger.Printf("write shards-max response error: %s", err)
-	}
-}
+	indexName := mux.Vars(r)["index"]
 
-type getShardsMaxResponse struct {
-	Standard map[string]uint64 `json:"standard"`
-}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "reading body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
-// handleGetIndexes handles GET /index request.
-func (h *Handler) handleGetIndexes(w http.ResponseWriter, r *http.Request) {
-	h.handleGetSchema(w, r)
+	resp := successResponse{h: h}
+	err = h.api.Transaction(r.Context(), indexName, string(body), r.URL.Query().Get("operationID"))
+	resp.write(w, err)
 }
 
-// handleGetIndex handles GET /index/<indexname> requests.
-func (h *Handler) handleGetIndex(w http.ResponseWriter, r *http.Request) {
+// handleGetIngestMapping handles GET /index/{index}/ingest-mapping/{mapping} requests.
+func (h *Handler) handleGetIngestMapping(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
 		return
 	}
 	indexName := mux.Vars(r)["index"]
-	for _, idx := range h.api.Schema(r.Context()) {
-		if idx.Name == indexName {
-			if err := json.NewEncoder(w).Encode(idx); err != nil {
-				h.logger.Printf("write response error: %s", err)
-			}
-			return
-		}
-	}
-	http.Error(w, fmt.Sprintf("Index %s Not Found", indexName), http.StatusNotFound)
-}
-
-type postIndexRequest struct {
-	Options pilosa.IndexOptions `json:"options"`
-}
-
-//_postIndexRequest is necessary to avoid recursion while decoding.
-type _postIndexRequest postIndexRequest
-
-// Custom Unmarshal JSON to validate request body when creating a new index.
-func (p *postIndexRequest) UnmarshalJSON(b []byte) error {
-
-	// m is an overflow map used to capture additional, unexpected keys.
-	m := make(map[string]interface{})
-	if err := json.Unmarshal(b, &m); err != nil {
-		return errors.Wrap(err, "unmarshalling unexpected values")
-	}
+	name := mux.Vars(r)["mapping"]
 
-	validIndexOptions := getValidOptions(pilosa.IndexOptions{})
-	err := validateOptions(m, validIndexOptions)
+	m, err := h.api.IngestMapping(r.Context(), indexName, name)
 	if err != nil {
-		return err
-	}
-	// Unmarshal expected values.
-	_p := _postIndexRequest{
-		Options: pilosa.IndexOptions{
-			Keys:           false,
-			TrackExistence: true,
-		},
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
 	}
-	if err := json.Unmarshal(b, &_p); err != nil {
-		return errors.Wrap(err, "unmarshalling expected values")
+	if err := json.NewEncoder(w).Encode(m); err != nil {
+		h.logger.Printf("write response error: %s", err)
 	}
-
-	p.Options = _p.Options
-
-	return nil
 }
 
-func getValidOptions(option interface{}) []string {
-	validOptions := []string{}
-	val := reflect.ValueOf(option)
-	for i := 0; i < val.Type().NumField(); i++ {
-		jsonTag := val.Type().Field(i).Tag.Get("json")
-		s := strings.Split(jsonTag, ",")
-		validOptions = append(validOptions, s[0])
+// handlePostIngestMapping handles POST /index/{index}/ingest-mapping/{mapping} requests.
+func (h *Handler) handlePostIngestMapping(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
 	}
-	return validOptions
-}
+	indexName := mux.Vars(r)["index"]
+	name := mux.Vars(r)["mapping"]
 
-// Raise errors for any unknown key
-func validateOptions(data map[string]interface{}, validIndexOptions []string) error {
-	for k, v := range data {
-		switch k {
-		case "options":
-			options, ok := v.(map[string]interface{})
-			if !ok {
-				return errors.New("options is not map[string]interface{}")
-			}
-			for kk, vv := range options {
-				if !foundItem(validIndexOptions, kk) {
-					return fmt.Errorf("unknown key: %v:%v", kk, vv)
-				}
-			}
-		default:
-			return fmt.Errorf("unknown key: %v:%v", k, v)
-		}
+	resp := successResponse{h: h}
+	var m pilosa.IngestMapping
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding request")))
+		return
+	} else if m.Name != "" && m.Name != name {
+		resp.write(w, pilosa.NewBadRequestError(errors.Errorf("mapping name %q does not match %q", m.Name, name)))
+		return
 	}
-	return nil
-}
+	m.Name = name
 
-func foundItem(items []string, item string) bool {
-	for _, i := range items {
-		if item == i {
-			return true
-		}
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
 	}
-	return false
+	err = h.api.CreateIngestMapping(ctx, indexName, &m)
+	resp.write(w, err)
 }
 
-// handleDeleteIndex handles DELETE /index request.
-func (h *Handler) handleDeleteIndex(w http.ResponseWriter, r *http.Request) {
+// handleDeleteIngestMapping handles DELETE /index/{index}/ingest-mapping/{mapping} requests.
+func (h *Handler) handleDeleteIngestMapping(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
 		return
 	}
-
 	indexName := mux.Vars(r)["index"]
+	name := mux.Vars(r)["mapping"]
 
 	resp := successResponse{h: h}
-	err := h.api.DeleteIndex(r.Context(), indexName)
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	err = h.api.DeleteIngestMapping(ctx, indexName, name)
 	resp.write(w, err)
 }
 
-// handlePostIndex handles POST /index request.
-func (h *Handler) handlePostIndex(w http.ResponseWriter, r *http.Request) {
+// handlePostInput handles POST /index/{index}/input/{mapping} requests. The
+// body is a JSON array of records, which are converted according to the
+// ingest mapping and imported.
+func (h *Handler) handlePostInput(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
 		return
 	}
-	indexName, ok := mux.Vars(r)["index"]
-	if !ok {
-		http.Error(w, "index name is required", http.StatusBadRequest)
-		return
-	}
+	indexName := mux.Vars(r)["index"]
+	name := mux.Vars(r)["mapping"]
 
 	resp := successResponse{h: h}
-
-	// Decode request.
-	req := postIndexRequest{
-		Options: pilosa.IndexOptions{
-			Keys:           false,
-			TrackExistence: true,
-		},
-	}
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil && err != io.EOF {
-		resp.write(w, err)
+	var records []map[string]interface{}
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&records); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding records")))
 		return
 	}
-	_, err = h.api.CreateIndex(r.Context(), indexName, req.Options)
 
+	err := h.api.Ingest(r.Context(), indexName, name, records)
 	resp.write(w, err)
 }
 
-// handlePostIndexAttrDiff handles POST /internal/index/attr/diff requests.
-func (h *Handler) handlePostIndexAttrDiff(w http.ResponseWriter, r *http.Request) {
+// handlePostDeleteColumns handles POST /index/{index}/delete-columns
+// requests, which start a job deleting columns. The body is a JSON object
+// with "columnIDs" or "columnKeys", or CSV with one or more columns per line.
+func (h *Handler) handlePostDeleteColumns(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
 		return
 	}
 	indexName := mux.Vars(r)["index"]
 
-	// Decode request.
-	var req postIndexAttrDiffRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	resp := successResponse{h: h}
+	var req postDeleteColumnsRequest
+	if r.Header.Get("Content-Type") == "text/csv" {
+		index, err := h.api.Index(r.Context(), indexName)
+		if err != nil {
+			resp.write(w, err)
+			return
+		}
+		keys := index.Keys()
+		index.Close()
+		if req, err = readDeleteColumnsCSV(r.Body, keys); err != nil {
+			resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "reading columns")))
+			return
+		}
+	} else {
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding request")))
+			return
+		}
 	}
 
-	attrs, err := h.api.IndexAttrDiff(r.Context(), indexName, req.Blocks)
+	job, err := h.api.CreateDeleteJob(r.Context(), indexName, req.ColumnIDs, req.ColumnKeys)
+	if errors.Cause(err) == pilosa.ErrNodeNotCoordinator {
+		err = pilosa.NewBadRequestError(err)
+	}
 	if err != nil {
-		if errors.Cause(err) == pilosa.ErrIndexNotFound {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		resp.write(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+type postDeleteColumnsRequest struct {
+	ColumnIDs  []uint64 `json:"columnIDs"`
+	ColumnKeys []string `json:"columnKeys"`
+}
+
+// readDeleteColumnsCSV reads the columns of a delete job from CSV, as keys if
+// the index uses keys.
+func readDeleteColumnsCSV(r io.Reader, keys bool) (req postDeleteColumnsRequest, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return req, nil
+		} else if err != nil {
+			return req, err
+		}
+		for _, v := range record {
+			if v == "" {
+				continue
+			} else if keys {
+				req.ColumnKeys = append(req.ColumnKeys, v)
+				continue
+			}
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return req, errors.Errorf("invalid column ID: %q", v)
+			}
+			req.ColumnIDs = append(req.ColumnIDs, id)
+		}
+	}
+}
+
+// handlePostWarmJob handles POST /jobs/warm requests.
+func (h *Handler) handlePostWarmJob(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	resp := successResponse{h: h}
+	// An empty body warms every index.
+	var req postWarmJobRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding request")))
+		return
+	}
+
+	job, err := h.api.CreateWarmJob(r.Context(), req.Indexes)
+	if errors.Cause(err) == pilosa.ErrNodeNotCoordinator {
+		err = pilosa.NewBadRequestError(err)
+	}
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+type postWarmJobRequest struct {
+	Indexes []string `json:"indexes"`
+}
+
+// handleGetJob handles GET /jobs/{id} requests.
+func (h *Handler) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	var job interface{}
+	id := mux.Vars(r)["id"]
+	job, err := h.api.DeleteJob(r.Context(), id)
+	if _, ok := err.(pilosa.NotFoundError); ok {
+		job, err = h.api.WarmJob(r.Context(), id, r.URL.Query().Get("local") == "true")
+	}
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// handlePostJobResume handles POST /jobs/{id}/resume requests.
+func (h *Handler) handlePostJobResume(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	var job interface{}
+	id := mux.Vars(r)["id"]
+	job, err := h.api.ResumeDeleteJob(r.Context(), id)
+	if _, ok := err.(pilosa.NotFoundError); ok {
+		job, err = h.api.ResumeWarmJob(r.Context(), id)
+	}
+	if errors.Cause(err) == pilosa.ErrNodeNotCoordinator {
+		err = pilosa.NewBadRequestError(err)
+	}
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// handleGetShardsMax handles GET /internal/shards/max requests.
+func (h *Handler) handleGetShardsMax(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getShardsMaxResponse{
+		Standard: h.api.MaxShards(r.Context()),
+	}); err != nil {
+		h.logger.Printf("write shards-max response error: %s", err)
+	}
+}
+
+type getShardsMaxResponse struct {
+	Standard map[string]uint64 `json:"standard"`
+}
+
+// handleGetIndexes handles GET /index request.
+func (h *Handler) handleGetIndexes(w http.ResponseWriter, r *http.Request) {
+	h.handleGetSchema(w, r)
+}
+
+// handleGetIndex handles GET /index/<indexname> requests.
+func (h *Handler) handleGetIndex(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	generation := h.api.SchemaGeneration(r.Context())
+	for _, idx := range h.api.Schema(r.Context()) {
+		if idx.Name == indexName {
+			setSchemaGenerationHeader(w, generation)
+			if err := json.NewEncoder(w).Encode(idx); err != nil {
+				h.logger.Printf("write response error: %s", err)
+			}
+			return
+		}
+	}
+	http.Error(w, fmt.Sprintf("Index %s Not Found", indexName), http.StatusNotFound)
+}
+
+// handleGetField handles GET /index/<indexname>/field/<fieldname> requests.
+func (h *Handler) handleGetField(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+	generation := h.api.SchemaGeneration(r.Context())
+	for _, idx := range h.api.Schema(r.Context()) {
+		if idx.Name != indexName {
+			continue
+		}
+		for _, fld := range idx.Fields {
+			if fld.Name == fieldName {
+				setSchemaGenerationHeader(w, generation)
+				if err := json.NewEncoder(w).Encode(fld); err != nil {
+					h.logger.Printf("write response error: %s", err)
+				}
+				return
+			}
+		}
+	}
+	http.Error(w, fmt.Sprintf("Field %s/%s Not Found", indexName, fieldName), http.StatusNotFound)
+}
+
+type postIndexRequest struct {
+	Options pilosa.IndexOptions `json:"options"`
+}
+
+// _postIndexRequest is necessary to avoid recursion while decoding.
+type _postIndexRequest postIndexRequest
+
+// Custom Unmarshal JSON to validate request body when creating a new index.
+func (p *postIndexRequest) UnmarshalJSON(b []byte) error {
+
+	// m is an overflow map used to capture additional, unexpected keys.
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return errors.Wrap(err, "unmarshalling unexpected values")
+	}
+
+	validIndexOptions := getValidOptions(pilosa.IndexOptions{})
+	err := validateOptions(m, validIndexOptions)
+	if err != nil {
+		return err
+	}
+	// Unmarshal expected values.
+	_p := _postIndexRequest{
+		Options: pilosa.IndexOptions{
+			Keys:           false,
+			TrackExistence: true,
+		},
+	}
+	if err := json.Unmarshal(b, &_p); err != nil {
+		return errors.Wrap(err, "unmarshalling expected values")
+	}
+
+	p.Options = _p.Options
+
+	return nil
+}
+
+func getValidOptions(option interface{}) []string {
+	validOptions := []string{}
+	val := reflect.ValueOf(option)
+	for i := 0; i < val.Type().NumField(); i++ {
+		jsonTag := val.Type().Field(i).Tag.Get("json")
+		s := strings.Split(jsonTag, ",")
+		validOptions = append(validOptions, s[0])
+	}
+	return validOptions
+}
+
+// Raise errors for any unknown key
+func validateOptions(data map[string]interface{}, validIndexOptions []string) error {
+	for k, v := range data {
+		switch k {
+		case "options":
+			options, ok := v.(map[string]interface{})
+			if !ok {
+				return errors.New("options is not map[string]interface{}")
+			}
+			for kk, vv := range options {
+				if !foundItem(validIndexOptions, kk) {
+					return fmt.Errorf("unknown key: %v:%v", kk, vv)
+				}
+			}
+		default:
+			return fmt.Errorf("unknown key: %v:%v", k, v)
+		}
+	}
+	return nil
+}
+
+func foundItem(items []string, item string) bool {
+	for _, i := range items {
+		if item == i {
+			return true
+		}
+	}
+	return false
+}
+
+// handleDeleteIndex handles DELETE /index request.
+func (h *Handler) handleDeleteIndex(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName := mux.Vars(r)["index"]
+
+	resp := successResponse{h: h}
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	if r.URL.Query().Get("purge") == "true" {
+		err = h.api.PurgeIndex(ctx, indexName)
+	} else {
+		err = h.api.DeleteIndex(ctx, indexName)
+	}
+	resp.write(w, err)
+}
+
+type getTrashResponse struct {
+	Indexes []pilosa.TrashEntry `json:"indexes"`
+}
+
+// handleGetTrash handles GET /trash requests.
+func (h *Handler) handleGetTrash(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	entries, err := h.api.Trash(r.Context())
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getTrashResponse{Indexes: entries}); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// handlePostTrashRestore handles POST /trash/{index}/restore requests.
+func (h *Handler) handlePostTrashRestore(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	resp := successResponse{h: h}
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	err = h.api.RestoreIndex(ctx, mux.Vars(r)["index"])
+	resp.write(w, err)
+}
+
+// handleDeleteTrash handles DELETE /trash/{index} requests.
+func (h *Handler) handleDeleteTrash(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	resp := successResponse{h: h}
+	err := h.api.PurgeTrash(r.Context(), mux.Vars(r)["index"])
+	resp.write(w, err)
+}
+
+// handleDeleteSession handles DELETE /sessions/{session} requests.
+func (h *Handler) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	resp := successResponse{h: h}
+	err := h.api.DeleteSession(r.Context(), mux.Vars(r)["session"])
+	resp.write(w, err)
+}
+
+// handlePostIndex handles POST /index request.
+func (h *Handler) handlePostIndex(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName, ok := mux.Vars(r)["index"]
+	if !ok {
+		http.Error(w, "index name is required", http.StatusBadRequest)
+		return
+	}
+
+	resp := successResponse{h: h}
+
+	// Decode request.
+	req := postIndexRequest{
+		Options: pilosa.IndexOptions{
+			Keys:           false,
+			TrackExistence: true,
+		},
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && err != io.EOF {
+		resp.write(w, err)
+		return
+	}
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	_, err = h.api.CreateIndex(ctx, indexName, req.Options)
+
+	resp.write(w, err)
+}
+
+// patchIndexRequest contains the index options which can be changed after
+// the index is created.
+type patchIndexRequest struct {
+	Options struct {
+		ReadOnly *bool  `json:"readOnly"`
+		Quota    *int64 `json:"quota"`
+
+		// DefaultFieldOptions is kept raw so that null, which removes
+		// the defaults, can be told apart from a missing value.
+		DefaultFieldOptions json.RawMessage `json:"defaultFieldOptions"`
+	} `json:"options"`
+}
+
+// handlePatchIndex handles PATCH /index/<indexname> requests.
+func (h *Handler) handlePatchIndex(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	resp := successResponse{h: h}
+
+	// Decode request, rejecting options which cannot be changed.
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(body, &m); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding request")))
+		return
+	} else if err := validateOptions(m, []string{"readOnly", "quota", "defaultFieldOptions"}); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+	var req patchIndexRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding request")))
+		return
+	} else if req.Options.ReadOnly == nil && req.Options.Quota == nil && req.Options.DefaultFieldOptions == nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.New("no options to update")))
+		return
+	} else if req.Options.Quota != nil && *req.Options.Quota < 0 {
+		resp.write(w, pilosa.NewBadRequestError(pilosa.ErrInvalidQuota))
+		return
+	}
+	var defaultFieldOptions *pilosa.DefaultFieldOptions
+	if req.Options.DefaultFieldOptions != nil {
+		dec := json.NewDecoder(bytes.NewReader(req.Options.DefaultFieldOptions))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&defaultFieldOptions); err != nil {
+			resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding default field options")))
+			return
+		}
+	}
+
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	if req.Options.ReadOnly != nil {
+		if err := h.api.SetIndexReadOnly(ctx, indexName, *req.Options.ReadOnly); err != nil {
+			resp.write(w, err)
+			return
+		}
+	}
+	if req.Options.Quota != nil {
+		if err := h.api.SetIndexQuota(ctx, indexName, *req.Options.Quota); err != nil {
+			resp.write(w, err)
+			return
+		}
+	}
+	if req.Options.DefaultFieldOptions != nil {
+		err = h.api.SetIndexDefaultFieldOptions(ctx, indexName, defaultFieldOptions)
+	}
+	resp.write(w, err)
+}
+
+// handlePostIndexAttrDiff handles POST /internal/index/attr/diff requests.
+func (h *Handler) handlePostIndexAttrDiff(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	// Decode request.
+	var req postIndexAttrDiffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attrs, err := h.api.IndexAttrDiff(r.Context(), indexName, req.Blocks)
+	if err != nil {
+		if errors.Cause(err) == pilosa.ErrIndexNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -793,21 +1540,28 @@ func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 	var fos []pilosa.FieldOption
 	switch req.Options.Type {
 	case pilosa.FieldTypeSet:
-		fos = append(fos, pilosa.OptFieldTypeSet(*req.Options.CacheType, *req.Options.CacheSize))
+		fos = append(fos, pilosa.OptFieldTypeSet(req.Options.cacheType(), req.Options.cacheSize()))
 	case pilosa.FieldTypeInt:
-		if req.Options.Min == nil {
-			min := int64(math.MinInt64)
-			req.Options.Min = &min
+		min, max, err := req.Options.intRange()
+		if err != nil {
+			resp.write(w, err)
+			return
+		}
+		fos = append(fos, pilosa.OptFieldTypeInt(min, max))
+		if req.Options.ClampIncrements {
+			fos = append(fos, pilosa.OptFieldClampIncrements())
 		}
-		if req.Options.Max == nil {
-			max := int64(math.MaxInt64)
-			req.Options.Max = &max
+		if req.Options.Scale != nil {
+			fos = append(fos, pilosa.OptFieldScale(*req.Options.Scale))
 		}
-		fos = append(fos, pilosa.OptFieldTypeInt(*req.Options.Min, *req.Options.Max))
 	case pilosa.FieldTypeTime:
-		fos = append(fos, pilosa.OptFieldTypeTime(*req.Options.TimeQuantum, req.Options.NoStandardView))
+		var q pilosa.TimeQuantum
+		if req.Options.TimeQuantum != nil {
+			q = *req.Options.TimeQuantum
+		}
+		fos = append(fos, pilosa.OptFieldTypeTime(q, req.Options.NoStandardView))
 	case pilosa.FieldTypeMutex:
-		fos = append(fos, pilosa.OptFieldTypeMutex(*req.Options.CacheType, *req.Options.CacheSize))
+		fos = append(fos, pilosa.OptFieldTypeMutex(req.Options.cacheType(), req.Options.cacheSize()))
 	case pilosa.FieldTypeBool:
 		fos = append(fos, pilosa.OptFieldTypeBool())
 	}
@@ -816,51 +1570,123 @@ func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 			fos = append(fos, pilosa.OptFieldKeys())
 		}
 	}
+	if req.Options.SnapshotRetention != nil {
+		d, _ := time.ParseDuration(*req.Options.SnapshotRetention)
+		fos = append(fos, pilosa.OptFieldSnapshotRetention(d))
+	}
+	if req.Options.TrackChanges {
+		fos = append(fos, pilosa.OptFieldTrackChanges())
+	}
+	if req.Options.ShareTimeViews {
+		fos = append(fos, pilosa.OptFieldShareTimeViews())
+	}
 
-	_, err = h.api.CreateField(r.Context(), indexName, fieldName, fos...)
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	field, err := h.api.CreateField(ctx, indexName, fieldName, fos...)
 	if _, ok := err.(pilosa.BadRequestError); ok {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
+	} else if err != nil {
+		resp.write(w, err)
+		return
+	}
+
+	opt := field.Options()
+	if err := json.NewEncoder(w).Encode(postFieldResponse{Success: true, Options: &opt}); err != nil {
+		h.logger.Printf("response encoding error: %s", err)
 	}
-	resp.write(w, err)
 }
 
 type postFieldRequest struct {
 	Options fieldOptions `json:"options"`
 }
 
+// postFieldResponse is the response to a created field. Options are the
+// effective options of the field, including those inherited from the
+// default field options of the index.
+type postFieldResponse struct {
+	Success bool                 `json:"success"`
+	Options *pilosa.FieldOptions `json:"options"`
+}
+
 // fieldOptions tracks pilosa.FieldOptions. It is made up of pointers to values,
 // and used for input validation.
 type fieldOptions struct {
 	Type           string              `json:"type,omitempty"`
 	CacheType      *string             `json:"cacheType,omitempty"`
 	CacheSize      *uint32             `json:"cacheSize,omitempty"`
-	Min            *int64              `json:"min,omitempty"`
-	Max            *int64              `json:"max,omitempty"`
+	Min            *json.Number        `json:"min,omitempty"`
+	Max            *json.Number        `json:"max,omitempty"`
 	TimeQuantum    *pilosa.TimeQuantum `json:"timeQuantum,omitempty"`
 	Keys           *bool               `json:"keys,omitempty"`
 	NoStandardView bool                `json:"noStandardView,omitempty"`
+
+	ClampIncrements bool   `json:"clampIncrements,omitempty"`
+	Scale           *int64 `json:"scale,omitempty"`
+
+	// SnapshotRetention is a duration, such as "720h".
+	SnapshotRetention *string `json:"snapshotRetention,omitempty"`
+
+	TrackChanges bool `json:"trackChanges,omitempty"`
+
+	// ShareTimeViews requires a time quantum with day and hour units, which
+	// may be inherited from the index.
+	ShareTimeViews bool `json:"shareTimeViews,omitempty"`
 }
 
-func (o *fieldOptions) validate() error {
-	// Pointers to default values.
-	defaultCacheType := pilosa.DefaultCacheType
-	defaultCacheSize := uint32(pilosa.DefaultCacheSize)
+// intRange returns the min and max of an int field in units of 10^-scale.
+// Missing bounds default to the full int64 range.
+func (o *fieldOptions) intRange() (min, max int64, err error) {
+	var scale int64
+	if o.Scale != nil {
+		scale = *o.Scale
+	}
+
+	min, max = math.MinInt64, math.MaxInt64
+	if o.Min != nil {
+		if min, err = pilosa.ParseDecimal(string(*o.Min), scale); err != nil {
+			return 0, 0, pilosa.NewBadRequestError(errors.Wrap(err, "parsing min"))
+		}
+	}
+	if o.Max != nil {
+		if max, err = pilosa.ParseDecimal(string(*o.Max), scale); err != nil {
+			return 0, 0, pilosa.NewBadRequestError(errors.Wrap(err, "parsing max"))
+		}
+	}
+	return min, max, nil
+}
+
+// cacheType returns the cache type of the options, or an empty string if it
+// isn't given, so that the field inherits the default of its index.
+func (o *fieldOptions) cacheType() string {
+	if o.CacheType == nil {
+		return ""
+	}
+	return *o.CacheType
+}
+
+// cacheSize returns the cache size of the options, or zero if it isn't given.
+func (o *fieldOptions) cacheSize() uint32 {
+	if o.CacheSize == nil {
+		return 0
+	}
+	return *o.CacheSize
+}
 
+// validate returns an error if options are given which don't apply to the
+// field type. Options which aren't given are left unset: the field inherits
+// them from the default field options of its index, or the defaults of its
+// field type.
+func (o *fieldOptions) validate() error {
 	switch o.Type {
 	case pilosa.FieldTypeSet, "":
-		// Because FieldTypeSet is the default, its arguments are
-		// not required. Instead, the defaults are applied whenever
-		// a value does not exist.
 		if o.Type == "" {
 			o.Type = pilosa.FieldTypeSet
 		}
-		if o.CacheType == nil {
-			o.CacheType = &defaultCacheType
-		}
-		if o.CacheSize == nil {
-			o.CacheSize = &defaultCacheSize
-		}
 		if o.Min != nil {
 			return pilosa.NewBadRequestError(errors.New("min does not apply to field type set"))
 		} else if o.Max != nil {
@@ -885,16 +1711,8 @@ func (o *fieldOptions) validate() error {
 			return pilosa.NewBadRequestError(errors.New("min does not apply to field type time"))
 		} else if o.Max != nil {
 			return pilosa.NewBadRequestError(errors.New("max does not apply to field type time"))
-		} else if o.TimeQuantum == nil {
-			return pilosa.NewBadRequestError(errors.New("timeQuantum is required for field type time"))
 		}
 	case pilosa.FieldTypeMutex:
-		if o.CacheType == nil {
-			o.CacheType = &defaultCacheType
-		}
-		if o.CacheSize == nil {
-			o.CacheSize = &defaultCacheSize
-		}
 		if o.Min != nil {
 			return pilosa.NewBadRequestError(errors.New("min does not apply to field type mutex"))
 		} else if o.Max != nil {
@@ -919,22 +1737,294 @@ func (o *fieldOptions) validate() error {
 	default:
 		return errors.Errorf("invalid field type: %s", o.Type)
 	}
-	return nil
-}
+	if o.ClampIncrements && o.Type != pilosa.FieldTypeInt {
+		return pilosa.NewBadRequestError(errors.Errorf("clampIncrements does not apply to field type %s", o.Type))
+	} else if o.Scale != nil && o.Type != pilosa.FieldTypeInt {
+		return pilosa.NewBadRequestError(errors.Errorf("scale does not apply to field type %s", o.Type))
+	} else if o.Scale != nil && (*o.Scale < 0 || *o.Scale > 18) {
+		return pilosa.NewBadRequestError(pilosa.ErrInvalidScale)
+	} else if o.TrackChanges && o.Type == pilosa.FieldTypeInt {
+		return pilosa.NewBadRequestError(errors.New("trackChanges does not apply to field type int"))
+	} else if o.ShareTimeViews && o.Type != pilosa.FieldTypeTime {
+		return pilosa.NewBadRequestError(errors.Errorf("shareTimeViews does not apply to field type %s", o.Type))
+	}
+	if o.SnapshotRetention != nil {
+		if d, err := time.ParseDuration(*o.SnapshotRetention); err != nil {
+			return pilosa.NewBadRequestError(errors.Wrap(err, "parsing snapshotRetention"))
+		} else if d < 0 {
+			return pilosa.NewBadRequestError(errors.New("snapshotRetention must not be negative"))
+		}
+	}
+	return nil
+}
+
+// handleDeleteField handles DELETE /field request.
+func (h *Handler) handleDeleteField(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	resp := successResponse{h: h}
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	err = h.api.DeleteField(ctx, indexName, fieldName)
+	resp.write(w, err)
+}
+
+// patchFieldRequest contains the field options which can be changed after
+// the field is created.
+type patchFieldRequest struct {
+	Options struct {
+		TimeQuantum *pilosa.TimeQuantum `json:"timeQuantum"`
+	} `json:"options"`
+}
+
+// patchFieldResponse is the response to a successful field update. Views for
+// added time units only contain data written after the update, and range
+// queries only use them from their next period on.
+type patchFieldResponse struct {
+	Success    bool   `json:"success"`
+	AddedUnits string `json:"addedUnits,omitempty"`
+	Warning    string `json:"warning,omitempty"`
+}
+
+// handleGetFieldSnapshots handles GET /index/{index}/field/{field}/snapshots
+// requests.
+func (h *Handler) handleGetFieldSnapshots(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	info, err := h.api.RetainedSnapshots(r.Context(), mux.Vars(r)["index"], mux.Vars(r)["field"])
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// getFieldViewsResponse is the response to a request for the views of a
+// field on a node.
+type getFieldViewsResponse struct {
+	Node  string             `json:"node"`
+	Views []*pilosa.ViewInfo `json:"views"`
+}
+
+// handleGetFieldViews handles GET /index/{index}/field/{field}/views
+// requests.
+func (h *Handler) handleGetFieldViews(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	views, err := h.api.FieldViews(r.Context(), mux.Vars(r)["index"], mux.Vars(r)["field"])
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	resp := getFieldViewsResponse{Node: h.api.Node().ID, Views: views}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// handlePostView handles POST /index/{index}/field/{field}/view/{view}
+// requests.
+func (h *Handler) handlePostView(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	resp := successResponse{h: h}
+	err := h.api.CreateView(r.Context(), vars["index"], vars["field"], vars["view"])
+	resp.write(w, err)
+}
+
+// handleDeleteView handles DELETE /index/{index}/field/{field}/view/{view}
+// requests.
+func (h *Handler) handleDeleteView(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	resp := successResponse{h: h}
+	err := h.api.DeleteView(r.Context(), vars["index"], vars["field"], vars["view"], r.URL.Query().Get("force") == "true")
+	resp.write(w, err)
+}
+
+// maxChangesWait bounds how long a request for the changes of a field waits
+// for one.
+const maxChangesWait = time.Minute
+
+// handleGetFieldChanges handles GET /index/{index}/field/{field}/changes
+// requests.
+func (h *Handler) handleGetFieldChanges(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	q := r.URL.Query()
+	var since uint64
+	if s := q.Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+	}
+	var limit int
+	if s := q.Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	var wait time.Duration
+	if s := q.Get("wait"); s != "" {
+		var err error
+		if wait, err = time.ParseDuration(s); err != nil || wait < 0 {
+			http.Error(w, "invalid wait", http.StatusBadRequest)
+			return
+		}
+		if wait > maxChangesWait {
+			wait = maxChangesWait
+		}
+	}
+
+	changes, err := h.api.FieldChanges(r.Context(), mux.Vars(r)["index"], mux.Vars(r)["field"], since, limit, wait)
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(changes); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// handleGetReplication handles GET /replication requests, returning the
+// state of the replication of the cluster to another cluster.
+func (h *Handler) handleGetReplication(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	st, err := h.api.ReplicationStatus(r.Context())
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(st); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// defaultStatsHistoryWindow is the window of a request for the stats history
+// which doesn't set one.
+const defaultStatsHistoryWindow = time.Hour
+
+// handleGetStatsHistory handles GET /stats/history requests, returning the
+// per-minute query and write statistics of the indexes on this node.
+func (h *Handler) handleGetStatsHistory(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	q := r.URL.Query()
+	window := defaultStatsHistoryWindow
+	if s := q.Get("window"); s != "" {
+		var err error
+		if window, err = time.ParseDuration(s); err != nil || window <= 0 {
+			http.Error(w, "invalid window", http.StatusBadRequest)
+			return
+		}
+	}
+
+	history, err := h.api.StatsHistory(r.Context(), q.Get("index"), window)
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
+// handlePatchField handles PATCH /index/{index}/field/{field} requests.
+func (h *Handler) handlePatchField(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	resp := successResponse{h: h}
+
+	// Decode request, rejecting options which cannot be changed.
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(body, &m); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding request")))
+		return
+	} else if err := validateOptions(m, []string{"timeQuantum"}); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+	var req patchFieldRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding request")))
+		return
+	} else if req.Options.TimeQuantum == nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.New("no options to update")))
+		return
+	}
 
-// handleDeleteField handles DELETE /field request.
-func (h *Handler) handleDeleteField(w http.ResponseWriter, r *http.Request) {
-	if !validHeaderAcceptJSON(r.Header) {
-		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+	ctx, err := schemaPreconditionContext(r)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	added, err := h.api.SetFieldTimeQuantum(ctx, indexName, fieldName, *req.Options.TimeQuantum)
+	if err != nil {
+		resp.write(w, err)
 		return
 	}
 
-	indexName := mux.Vars(r)["index"]
-	fieldName := mux.Vars(r)["field"]
-
-	resp := successResponse{h: h}
-	err := h.api.DeleteField(r.Context(), indexName, fieldName)
-	resp.write(w, err)
+	presp := patchFieldResponse{Success: true, AddedUnits: string(added)}
+	if added != "" {
+		presp.Warning = "views for added time units only contain data written after this change, so range queries only use them from their next period on"
+	}
+	if err := json.NewEncoder(w).Encode(presp); err != nil {
+		h.logger.Printf("error encoding response: %v", err)
+	}
 }
 
 // handleDeleteRemoteAvailableShard handles DELETE /field/{field}/available-shards/{shardID} request.
@@ -1034,17 +2124,40 @@ func (h *Handler) readURLQueryRequest(r *http.Request) (*pilosa.QueryRequest, er
 	query := string(buf)
 
 	// Parse list of shards.
-	shards, err := parseUint64Slice(q.Get("shards"))
+	shards, err := parseShards(q.Get("shards"))
 	if err != nil {
 		return nil, errors.New("invalid shard argument")
 	}
 
+	// Parse the time as of which the query reads.
+	var asOf time.Time
+	if v := q.Get("asOf"); v != "" {
+		if asOf, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, errors.New("invalid asOf argument")
+		}
+	}
+
+	// Parse the staleness which the query accepts.
+	var maxStaleness time.Duration
+	if v := q.Get("maxStaleness"); v != "" {
+		if maxStaleness, err = time.ParseDuration(v); err != nil || maxStaleness < 0 {
+			return nil, errors.New("invalid maxStaleness argument")
+		}
+	}
+
 	return &pilosa.QueryRequest{
 		Query:           query,
 		Shards:          shards,
 		ColumnAttrs:     q.Get("columnAttrs") == "true",
 		ExcludeRowAttrs: q.Get("excludeRowAttrs") == "true",
 		ExcludeColumns:  q.Get("excludeColumns") == "true",
+		Session:         q.Get("session"),
+		StoreAs:         q.Get("storeAs"),
+		Snapshot:        q.Get("snapshot") == "true",
+		AsOf:            asOf,
+		Roaring:         q.Get("roaring") == "true",
+		Estimate:        q.Get("estimate") == "true",
+		MaxStaleness:    maxStaleness,
 	}, nil
 }
 
@@ -1073,6 +2186,68 @@ func (h *Handler) writeJSONQueryResponse(w io.Writer, resp *pilosa.QueryResponse
 	return json.NewEncoder(w).Encode(resp)
 }
 
+// Headers for the routing of single-shard writes. A node which doesn't own
+// the shard of a request with the routing header set to "strict" refuses it
+// with http.StatusMisdirectedRequest, and lists the nodes which own the shard
+// in the shard owners header. Other requests are forwarded to the owners,
+// which are listed in the forwarded header.
+const (
+	HeaderRouting     = "X-Pilosa-Routing"
+	HeaderShardOwners = "X-Pilosa-Shard-Owners"
+	HeaderForwardedTo = "X-Pilosa-Forwarded-To"
+)
+
+// requestRouting returns the routing requested by r, and a copy of the
+// context of r which carries it.
+func requestRouting(r *http.Request) (context.Context, *pilosa.Routing) {
+	routing := &pilosa.Routing{Strict: r.Header.Get(HeaderRouting) == "strict"}
+	return pilosa.WithRouting(r.Context(), routing), routing
+}
+
+// writeRoutingHeaders sets the headers which report how a request with the
+// given routing and error was routed.
+func writeRoutingHeaders(w http.ResponseWriter, routing *pilosa.Routing, err error) {
+	if e := shardOwnerError(err); e != nil {
+		w.Header().Set(HeaderShardOwners, nodeURIs(e.Owners))
+	} else if err == nil && len(routing.ForwardedTo) > 0 {
+		w.Header().Set(HeaderForwardedTo, nodeURIs(routing.ForwardedTo))
+	}
+}
+
+// shardOwnerStatus returns the status code of a request with the given
+// routing for a shard which the node doesn't own.
+func shardOwnerStatus(routing *pilosa.Routing) int {
+	if routing.Strict {
+		return http.StatusMisdirectedRequest
+	}
+	return http.StatusPreconditionFailed
+}
+
+// shardOwnerError returns the ShardOwnerError among the causes of err, if
+// there is one.
+func shardOwnerError(err error) *pilosa.ShardOwnerError {
+	for err != nil {
+		if e, ok := err.(*pilosa.ShardOwnerError); ok {
+			return e
+		}
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			return nil
+		}
+		err = c.Cause()
+	}
+	return nil
+}
+
+// nodeURIs returns the comma-separated URIs of nodes.
+func nodeURIs(nodes []*pilosa.Node) string {
+	uris := make([]string, len(nodes))
+	for i, node := range nodes {
+		uris[i] = node.URI.String()
+	}
+	return strings.Join(uris, ",")
+}
+
 // handlePostImport handles /import requests.
 func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 	// Verify that request is only communicating over protobufs.
@@ -1094,6 +2269,8 @@ func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 	opts := []pilosa.ImportOption{
 		pilosa.OptImportOptionsClear(doClear),
 		pilosa.OptImportOptionsIgnoreKeyCheck(doIgnoreKeyCheck),
+		pilosa.OptImportOptionsOperationID(q.Get("operationID")),
+		pilosa.OptImportOptionsDropTimestamps(q.Get("dropTimestamps") == "true"),
 	}
 
 	// Get index and field type to determine how to handle the
@@ -1119,6 +2296,7 @@ func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Unmarshal request based on field type.
+	ctx, routing := requestRouting(r)
 	if field.Type() == pilosa.FieldTypeInt {
 		// Field type: Int
 		// Marshal into request object.
@@ -1128,10 +2306,20 @@ func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := h.api.ImportValue(r.Context(), req, opts...); err != nil {
+		err := h.api.ImportValue(ctx, req, opts...)
+		writeRoutingHeaders(w, routing, err)
+		if err != nil {
+			if _, ok := errors.Cause(err).(pilosa.BadRequestError); ok {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			switch errors.Cause(err) {
 			case pilosa.ErrClusterDoesNotOwnShard:
-				http.Error(w, err.Error(), http.StatusPreconditionFailed)
+				http.Error(w, err.Error(), shardOwnerStatus(routing))
+			case pilosa.ErrIndexReadOnly:
+				http.Error(w, err.Error(), http.StatusConflict)
+			case pilosa.ErrQuotaExceeded:
+				http.Error(w, err.Error(), http.StatusInsufficientStorage)
 			default:
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 			}
@@ -1146,10 +2334,20 @@ func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := h.api.Import(r.Context(), req, opts...); err != nil {
+		err := h.api.Import(ctx, req, opts...)
+		writeRoutingHeaders(w, routing, err)
+		if err != nil {
+			if _, ok := errors.Cause(err).(pilosa.BadRequestError); ok {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			switch errors.Cause(err) {
 			case pilosa.ErrClusterDoesNotOwnShard:
-				http.Error(w, err.Error(), http.StatusPreconditionFailed)
+				http.Error(w, err.Error(), shardOwnerStatus(routing))
+			case pilosa.ErrIndexReadOnly:
+				http.Error(w, err.Error(), http.StatusConflict)
+			case pilosa.ErrQuotaExceeded:
+				http.Error(w, err.Error(), http.StatusInsufficientStorage)
 			default:
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 			}
@@ -1198,6 +2396,9 @@ func (h *Handler) handleGetExportCSV(w http.ResponseWriter, r *http.Request) {
 			break
 		case pilosa.ErrClusterDoesNotOwnShard:
 			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		case pilosa.ErrMemoryPressure:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -1234,6 +2435,47 @@ func (h *Handler) handleGetFragmentNodes(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// handleGetRouting handles /internal/routing requests. The shards are
+// given either as a single shard or as a list of shards and ranges of
+// shards.
+func (h *Handler) handleGetRouting(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	q := r.URL.Query()
+
+	var shards []uint64
+	if s := q.Get("shard"); s != "" {
+		shard, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			http.Error(w, "shard should be an unsigned integer", http.StatusBadRequest)
+			return
+		}
+		shards = append(shards, shard)
+	}
+	list, err := parseShards(q.Get("shards"))
+	if err != nil {
+		http.Error(w, "invalid shards argument", http.StatusBadRequest)
+		return
+	}
+	shards = append(shards, list...)
+	if len(shards) == 0 {
+		http.Error(w, "shard or shards required", http.StatusBadRequest)
+		return
+	}
+
+	table, err := h.api.RoutingTable(r.Context(), q.Get("index"), shards)
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(table); err != nil {
+		h.logger.Printf("json write error: %s", err)
+	}
+}
+
 // handleGetNodes handles /internal/nodes requests.
 func (h *Handler) handleGetNodes(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -1250,6 +2492,102 @@ func (h *Handler) handleGetNodes(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleGetNodeConfig handles GET /internal/config requests.
+func (h *Handler) handleGetNodeConfig(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(h.api.NodeSettings()); err != nil {
+		h.logger.Printf("json write error: %s", err)
+	}
+}
+
+// fragmentParams reads the fragment identified by the index, field, view and
+// shard URL arguments of r.
+func fragmentParams(r *http.Request) (index, field, view string, shard uint64, err error) {
+	q := r.URL.Query()
+	shard, err = strconv.ParseUint(q.Get("shard"), 10, 64)
+	if err != nil {
+		return "", "", "", 0, errors.New("invalid shard argument")
+	}
+	return q.Get("index"), q.Get("field"), q.Get("view"), shard, nil
+}
+
+// writeFragmentResponse writes msg to w as JSON, or as protobuf if JSON is not
+// acceptable to the client. Errors are written with a matching status code.
+func (h *Handler) writeFragmentResponse(w http.ResponseWriter, r *http.Request, msg pilosa.Message, err error) {
+	if err != nil {
+		switch errors.Cause(err) {
+		case pilosa.ErrFragmentNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if !validHeaderAcceptJSON(r.Header) {
+		buf, err := h.api.Serializer.Marshal(msg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/protobuf")
+		if _, err := w.Write(buf); err != nil {
+			h.logger.Printf("writing fragment response: %v", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(msg); err != nil {
+		h.logger.Printf("writing fragment response: %v", err)
+	}
+}
+
+// handleGetPublicFragmentBlocks handles GET /fragment/blocks requests.
+func (h *Handler) handleGetPublicFragmentBlocks(w http.ResponseWriter, r *http.Request) {
+	index, field, view, shard, err := fragmentParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	blocks, err := h.api.FragmentBlocks(r.Context(), index, field, view, shard)
+	h.writeFragmentResponse(w, r, &pilosa.FragmentBlocksResponse{Blocks: blocks}, err)
+}
+
+// handleGetPublicFragmentBlockData handles GET /fragment/block/data requests.
+func (h *Handler) handleGetPublicFragmentBlockData(w http.ResponseWriter, r *http.Request) {
+	index, field, view, shard, err := fragmentParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &pilosa.BlockDataRequest{Index: index, Field: field, View: view, Shard: shard}
+
+	q := r.URL.Query()
+	if req.Block, err = strconv.ParseUint(q.Get("block"), 10, 64); err != nil {
+		http.Error(w, "invalid block argument", http.StatusBadRequest)
+		return
+	}
+	if s := q.Get("continuation"); s != "" {
+		if req.Continuation, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "invalid continuation argument", http.StatusBadRequest)
+			return
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		if req.Limit, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "invalid limit argument", http.StatusBadRequest)
+			return
+		}
+	}
+
+	resp, err := h.api.FragmentBlockPairs(r.Context(), req)
+	h.writeFragmentResponse(w, r, resp, err)
+}
+
 // handleGetFragmentBlockData handles GET /internal/fragment/block/data requests.
 func (h *Handler) handleGetFragmentBlockData(w http.ResponseWriter, r *http.Request) {
 	buf, err := h.api.FragmentBlockData(r.Context(), r.Body)
@@ -1309,6 +2647,90 @@ type getFragmentBlocksResponse struct {
 	Blocks []pilosa.FragmentBlock `json:"blocks"`
 }
 
+// handleGetFragmentInspect handles GET /internal/fragment/inspect requests.
+func (h *Handler) handleGetFragmentInspect(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	q := r.URL.Query()
+	shard, err := strconv.ParseUint(q.Get("shard"), 10, 64)
+	if err != nil {
+		http.Error(w, "shard required", http.StatusBadRequest)
+		return
+	}
+
+	// Read the optional comma-separated rows and blocks to dump.
+	var rows []uint64
+	var blocks []int
+	for _, s := range strings.Split(q.Get("rows"), ",") {
+		if s == "" {
+			continue
+		}
+		rowID, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid row: "+s, http.StatusBadRequest)
+			return
+		}
+		rows = append(rows, rowID)
+	}
+	for _, s := range strings.Split(q.Get("blocks"), ",") {
+		if s == "" {
+			continue
+		}
+		id, err := strconv.Atoi(s)
+		if err != nil || id < 0 {
+			http.Error(w, "invalid block: "+s, http.StatusBadRequest)
+			return
+		}
+		blocks = append(blocks, id)
+	}
+
+	info, err := h.api.FragmentInspect(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"), shard, rows, blocks)
+	if err != nil {
+		if errors.Cause(err) == pilosa.ErrFragmentNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		h.logger.Printf("fragment inspect response encoding error: %s", err)
+	}
+}
+
+// handlePostFragmentCompact handles POST /internal/fragment/compact requests.
+func (h *Handler) handlePostFragmentCompact(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	q := r.URL.Query()
+	shard, err := strconv.ParseUint(q.Get("shard"), 10, 64)
+	if err != nil {
+		http.Error(w, "shard required", http.StatusBadRequest)
+		return
+	}
+
+	fc, err := h.api.CompactFragment(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"), shard)
+	if err != nil {
+		if errors.Cause(err) == pilosa.ErrFragmentNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(fc); err != nil {
+		h.logger.Printf("fragment compact response encoding error: %s", err)
+	}
+}
+
 // handleGetFragmentData handles GET /internal/fragment/data requests.
 func (h *Handler) handleGetFragmentData(w http.ResponseWriter, r *http.Request) {
 	// Read shard parameter.
@@ -1353,8 +2775,14 @@ const (
 	QueryResultTypeUint64
 )
 
-// parseUint64Slice returns a slice of uint64s from a comma-delimited string.
-func parseUint64Slice(s string) ([]uint64, error) {
+// maxShards is the maximum number of shards the shards argument may expand
+// to, across all of its elements.
+const maxShards = 1 << 20
+
+// parseShards returns a slice of shards from a comma-delimited string. Each
+// element is either a single shard or an inclusive range such as "1000-1024".
+// An error is returned if the list expands to more than maxShards shards.
+func parseShards(s string) ([]uint64, error) {
 	var a []uint64
 	for _, str := range strings.Split(s, ",") {
 		// Ignore blanks.
@@ -1362,12 +2790,36 @@ func parseUint64Slice(s string) ([]uint64, error) {
 			continue
 		}
 
-		// Parse number.
-		num, err := strconv.ParseUint(str, 10, 64)
+		// Parse a single shard.
+		i := strings.IndexByte(str, '-')
+		if i < 0 {
+			num, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "parsing int")
+			} else if len(a) >= maxShards {
+				return nil, errors.Errorf("shards exceed %d", maxShards)
+			}
+			a = append(a, num)
+			continue
+		}
+
+		// Parse a range of shards.
+		lo, err := strconv.ParseUint(str[:i], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing range start")
+		}
+		hi, err := strconv.ParseUint(str[i+1:], 10, 64)
 		if err != nil {
-			return nil, errors.Wrap(err, "parsing int")
+			return nil, errors.Wrap(err, "parsing range end")
+		}
+		if lo > hi {
+			return nil, errors.Errorf("invalid range %q", str)
+		} else if hi-lo >= uint64(maxShards-len(a)) {
+			return nil, errors.Errorf("range %q: shards exceed %d", str, maxShards)
+		}
+		for num := lo; num <= hi; num++ {
+			a = append(a, num)
 		}
-		a = append(a, num)
 	}
 	return a, nil
 }
@@ -1484,6 +2936,84 @@ type clusterResizeAbortResponse struct {
 	Info string `json:"info"`
 }
 
+// handleGetClusterConfig handles GET /cluster/config requests.
+func (h *Handler) handleGetClusterConfig(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	config, err := h.api.ClusterConfig(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(config); err != nil {
+		h.logger.Printf("response encoding error: %s", err)
+	}
+}
+
+// handlePatchClusterConfig handles PATCH /cluster/config requests.
+func (h *Handler) handlePatchClusterConfig(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	var req patchClusterConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "decoding request: "+err.Error(), http.StatusBadRequest)
+		return
+	} else if len(req.Settings) == 0 {
+		http.Error(w, "no settings to update", http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.api.UpdateClusterConfig(r.Context(), req.Settings)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := errors.Cause(err).(pilosa.BadRequestError); ok || errors.Cause(err) == pilosa.ErrNodeNotCoordinator {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(config); err != nil {
+		h.logger.Printf("response encoding error: %s", err)
+	}
+}
+
+type patchClusterConfigRequest struct {
+	// Values of settings by name. Null removes the cluster-level value.
+	Settings map[string]*string `json:"settings"`
+}
+
+// existenceRebuildResponse is the response to a rebuild of the existence of
+// the columns of an index.
+type existenceRebuildResponse struct {
+	Success bool   `json:"success"`
+	Columns uint64 `json:"columns"`
+}
+
+// handlePostExistenceRebuild handles POST /index/{index}/existence/rebuild
+// requests.
+func (h *Handler) handlePostExistenceRebuild(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	n, err := h.api.RebuildExistence(r.Context(), mux.Vars(r)["index"])
+	if err != nil {
+		resp := successResponse{h: h}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(existenceRebuildResponse{Success: true, Columns: n}); err != nil {
+		h.logger.Printf("write response error: %s", err)
+	}
+}
+
 func (h *Handler) handleRecalculateCaches(w http.ResponseWriter, r *http.Request) {
 	err := h.api.RecalculateCaches(r.Context())
 	if err != nil {
@@ -1600,6 +3130,12 @@ func (s queryValidationSpec) validate(query url.Values) error {
 }
 
 func GetHTTPClient(t *tls.Config) *http.Client {
+	return &http.Client{Transport: newHTTP1Transport(t)}
+}
+
+// newHTTP1Transport returns the transport of clients which send requests
+// over HTTP/1.1.
+func newHTTP1Transport(t *tls.Config) *http.Transport {
 	transport := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
@@ -1616,7 +3152,7 @@ func GetHTTPClient(t *tls.Config) *http.Client {
 	if t != nil {
 		transport.TLSClientConfig = t
 	}
-	return &http.Client{Transport: transport}
+	return transport
 }
 
 // handlPostRoaringImport
@@ -1670,11 +3206,19 @@ func (h *Handler) handlePostImportRoaring(w http.ResponseWriter, r *http.Request
 
 	resp := &pilosa.ImportResponse{}
 	// TODO give meaningful stats for import
+	ctx, routing := requestRouting(r)
 	err = h.api.ImportRoaring(ctx, indexName, fieldName, shard, remote, req)
+	writeRoutingHeaders(w, routing, err)
 	if err != nil {
 		resp.Err = err.Error()
 		if _, ok := err.(pilosa.BadRequestError); ok {
 			w.WriteHeader(http.StatusBadRequest)
+		} else if errors.Cause(err) == pilosa.ErrClusterDoesNotOwnShard {
+			w.WriteHeader(shardOwnerStatus(routing))
+		} else if errors.Cause(err) == pilosa.ErrIndexReadOnly {
+			w.WriteHeader(http.StatusConflict)
+		} else if errors.Cause(err) == pilosa.ErrQuotaExceeded {
+			w.WriteHeader(http.StatusInsufficientStorage)
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -103,10 +103,15 @@ func int64Ptr(i int64) *int64 {
 	return &i
 }
 
+func numberPtr(s string) *json.Number {
+	n := json.Number(s)
+	return &n
+}
+
 // Test fieldOption validation.
 func TestFieldOptionValidation(t *testing.T) {
 	timeQuantum := pilosa.TimeQuantum("YMD")
-	defaultCacheSize := uint32(pilosa.DefaultCacheSize)
+	cacheSize := uint32(1000)
 	tests := []struct {
 		json     string
 		expected postFieldRequest
@@ -114,19 +119,18 @@ func TestFieldOptionValidation(t *testing.T) {
 	}{
 		// FieldType: Set
 		{json: `{"options": {}}`, expected: postFieldRequest{Options: fieldOptions{
-			Type:      pilosa.FieldTypeSet,
-			CacheType: stringPtr(pilosa.DefaultCacheType),
-			CacheSize: &defaultCacheSize,
+			Type: pilosa.FieldTypeSet,
 		}}},
 		{json: `{"options": {"type": "set"}}`, expected: postFieldRequest{Options: fieldOptions{
-			Type:      pilosa.FieldTypeSet,
-			CacheType: stringPtr(pilosa.DefaultCacheType),
-			CacheSize: &defaultCacheSize,
+			Type: pilosa.FieldTypeSet,
 		}}},
 		{json: `{"options": {"type": "set", "cacheType": "lru"}}`, expected: postFieldRequest{Options: fieldOptions{
 			Type:      pilosa.FieldTypeSet,
 			CacheType: stringPtr("lru"),
-			CacheSize: &defaultCacheSize,
+		}}},
+		{json: `{"options": {"type": "set", "cacheSize": 1000}}`, expected: postFieldRequest{Options: fieldOptions{
+			Type:      pilosa.FieldTypeSet,
+			CacheSize: &cacheSize,
 		}}},
 		{json: `{"options": {"type": "set", "min": 0}}`, err: "min does not apply to field type set"},
 		{json: `{"options": {"type": "set", "max": 100}}`, err: "max does not apply to field type set"},
@@ -137,15 +141,26 @@ func TestFieldOptionValidation(t *testing.T) {
 		{json: `{"options": {"type": "int", "min": 0}}`, err: "max is required for field type int"},
 		{json: `{"options": {"type": "int", "min": 0, "max": 1000}}`, expected: postFieldRequest{Options: fieldOptions{
 			Type: pilosa.FieldTypeInt,
-			Min:  int64Ptr(0),
-			Max:  int64Ptr(1000),
+			Min:  numberPtr("0"),
+			Max:  numberPtr("1000"),
 		}}},
+		{json: `{"options": {"type": "int", "min": 0, "max": 1000.5, "scale": 2}}`, expected: postFieldRequest{Options: fieldOptions{
+			Type:  pilosa.FieldTypeInt,
+			Min:   numberPtr("0"),
+			Max:   numberPtr("1000.5"),
+			Scale: int64Ptr(2),
+		}}},
+		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "scale": 19}}`, err: pilosa.ErrInvalidScale.Error()},
+		{json: `{"options": {"type": "set", "scale": 2}}`, err: "scale does not apply to field type set"},
 		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "cacheType": "ranked"}}`, err: "cacheType does not apply to field type int"},
 		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "cacheSize": 1000}}`, err: "cacheSize does not apply to field type int"},
 		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "timeQuantum": "YMD"}}`, err: "timeQuantum does not apply to field type int"},
 
 		// FieldType: Time
-		{json: `{"options": {"type": "time"}}`, err: "timeQuantum is required for field type time"},
+		// The time quantum may be inherited from the index.
+		{json: `{"options": {"type": "time"}}`, expected: postFieldRequest{Options: fieldOptions{
+			Type: pilosa.FieldTypeTime,
+		}}},
 		{json: `{"options": {"type": "time", "timeQuantum": "YMD"}}`, expected: postFieldRequest{Options: fieldOptions{
 			Type:        pilosa.FieldTypeTime,
 			TimeQuantum: &timeQuantum,
@@ -178,3 +193,71 @@ func TestFieldOptionValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestParseShards(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected []uint64
+		err      string
+	}{
+		{s: "", expected: nil},
+		{s: "0,2,1", expected: []uint64{0, 2, 1}},
+		{s: "1000-1003,7", expected: []uint64{1000, 1001, 1002, 1003, 7}},
+		{s: "5-5", expected: []uint64{5}},
+		{s: "a", err: "parsing int"},
+		{s: "1-b", err: "parsing range end"},
+		{s: "3-1", err: `invalid range "3-1"`},
+		{s: "0-18446744073709551615", err: "exceed"},
+		{s: "0-1048575", expected: makeShards(0, 1<<20)},
+		{s: "0-1048575,7", err: "shards exceed"},
+		{s: "0-1000000,0-1000000", err: `range "0-1000000": shards exceed`},
+	}
+	for _, test := range tests {
+		actual, err := parseShards(test.s)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%q: expected error: %v, but got: %v", test.s, test.err, err)
+			}
+		} else if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.s, err)
+		} else if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%q: expected: %v, but got: %v", test.s, test.expected, actual)
+		}
+	}
+}
+
+// makeShards returns the shards from lo up to, but not including, hi.
+func makeShards(lo, hi uint64) []uint64 {
+	a := make([]uint64, 0, hi-lo)
+	for num := lo; num < hi; num++ {
+		a = append(a, num)
+	}
+	return a
+}
+
+func TestReadDeleteColumnsCSV(t *testing.T) {
+	tests := []struct {
+		s        string
+		keys     bool
+		expected postDeleteColumnsRequest
+		err      string
+	}{
+		{s: "", expected: postDeleteColumnsRequest{}},
+		{s: "1,2\n3\n\n 4,\n", expected: postDeleteColumnsRequest{ColumnIDs: []uint64{1, 2, 3, 4}}},
+		{s: "a, b\nc\n", keys: true, expected: postDeleteColumnsRequest{ColumnKeys: []string{"a", "b", "c"}}},
+		{s: "1\na\n", err: `invalid column ID: "a"`},
+		{s: "1\n-2\n", err: `invalid column ID: "-2"`},
+	}
+	for _, test := range tests {
+		actual, err := readDeleteColumnsCSV(strings.NewReader(test.s), test.keys)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%q: expected error: %v, but got: %v", test.s, test.err, err)
+			}
+		} else if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.s, err)
+		} else if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%q: expected: %+v, but got: %+v", test.s, test.expected, actual)
+		}
+	}
+}
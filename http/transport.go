@@ -0,0 +1,491 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// defaultHTTP2MaxConnsPerPeer is the default number of HTTP/2
+	// connections opened to each peer.
+	defaultHTTP2MaxConnsPerPeer = 2
+
+	// defaultHTTP2MaxStreamsPerConn is the default number of concurrent
+	// requests on each HTTP/2 connection.
+	defaultHTTP2MaxStreamsPerConn = 100
+
+	// http2IdleConnTimeout is how long an HTTP/2 connection without requests
+	// is kept open, as for HTTP/1.1 connections.
+	http2IdleConnTimeout = 90 * time.Second
+
+	// http2FallbackPeriod is how long requests to a peer which doesn't
+	// support HTTP/2 use HTTP/1.1 before HTTP/2 is tried again.
+	http2FallbackPeriod = 5 * time.Minute
+)
+
+// errPeerHTTP1 is returned when a peer doesn't support HTTP/2.
+var errPeerHTTP1 = errors.New("peer doesn't support HTTP/2")
+
+// HTTP2Options configures the HTTP/2 connections between nodes.
+type HTTP2Options struct {
+	// Enabled sends requests to other nodes over HTTP/2, without TLS (h2c)
+	// for http URIs, and serves HTTP/2 requests.
+	Enabled bool
+
+	// MaxConnsPerPeer is the number of connections opened to each peer.
+	MaxConnsPerPeer int
+
+	// MaxStreamsPerConn is the number of concurrent requests on each
+	// connection. It is also advertised to clients by the server.
+	MaxStreamsPerConn int
+
+	// PingInterval is the interval between pings of each connection. A
+	// connection whose ping isn't answered within PingTimeout is closed.
+	PingInterval time.Duration
+	PingTimeout  time.Duration
+
+	// Stats receives the peerConnections and peerStreams gauges, tagged
+	// with the address of each peer.
+	Stats stats.StatsClient
+}
+
+// withDefaults returns the options with defaults set.
+func (o HTTP2Options) withDefaults() HTTP2Options {
+	if o.MaxConnsPerPeer <= 0 {
+		o.MaxConnsPerPeer = defaultHTTP2MaxConnsPerPeer
+	}
+	if o.MaxStreamsPerConn <= 0 {
+		o.MaxStreamsPerConn = defaultHTTP2MaxStreamsPerConn
+	}
+	if o.PingInterval <= 0 {
+		o.PingInterval = 15 * time.Second
+	}
+	if o.PingTimeout <= 0 {
+		o.PingTimeout = 5 * time.Second
+	}
+	if o.Stats == nil {
+		o.Stats = stats.NopStatsClient
+	}
+	return o
+}
+
+// GetInternalHTTPClient returns a client for requests between nodes. When
+// HTTP/2 is enabled, requests to each peer are multiplexed over a few
+// connections, and sent over HTTP/1.1 to peers which don't support HTTP/2.
+func GetInternalHTTPClient(t *tls.Config, opt HTTP2Options) *http.Client {
+	if !opt.Enabled {
+		return GetHTTPClient(t)
+	}
+	return &http.Client{Transport: newPeerTransport(t, opt)}
+}
+
+// peerTransport is an http.RoundTripper which sends requests over a pool of
+// HTTP/2 connections per peer.
+type peerTransport struct {
+	opt    HTTP2Options
+	tls    *tls.Config
+	dialer *net.Dialer
+	http1  *http.Transport
+	http2  *http2.Transport
+
+	mu    sync.Mutex
+	peers map[string]*peer
+}
+
+// peer is the pool of connections to a node.
+type peer struct {
+	addr    string
+	stats   stats.StatsClient
+	conns   []*peerConn
+	dialing int
+
+	// http1Until is the time until which requests use HTTP/1.1.
+	http1Until time.Time
+
+	// changed is closed, and replaced, when a stream is released or a
+	// connection is added or removed.
+	changed chan struct{}
+}
+
+// peerConn is an HTTP/2 connection to a peer.
+type peerConn struct {
+	cc       *http2.ClientConn
+	streams  int
+	lastUsed time.Time
+}
+
+func newPeerTransport(t *tls.Config, opt HTTP2Options) *peerTransport {
+	opt = opt.withDefaults()
+	return &peerTransport{
+		opt: opt,
+		tls: t,
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+		http1: newHTTP1Transport(t),
+		http2: &http2.Transport{
+			AllowHTTP:       true,
+			TLSClientConfig: t,
+		},
+		peers: make(map[string]*peer),
+	}
+}
+
+// RoundTrip sends a request over an HTTP/2 connection to its host, or over
+// HTTP/1.1 if the host doesn't support HTTP/2.
+func (t *peerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return t.http1.RoundTrip(req)
+	}
+	addr := canonicalAddr(req.URL.Scheme, req.URL.Host)
+
+	p, pc, err := t.acquire(req.Context(), req.URL.Scheme, addr)
+	if err == errPeerHTTP1 {
+		return t.http1.RoundTrip(req)
+	} else if err != nil {
+		return nil, err
+	}
+
+	resp, err := pc.cc.RoundTrip(req)
+	if err != nil {
+		t.release(p, pc)
+		if !pc.cc.CanTakeNewRequest() {
+			t.remove(p, pc)
+		}
+		return nil, err
+	}
+	resp.Body = &streamBody{ReadCloser: resp.Body, release: func() { t.release(p, pc) }}
+	return resp, nil
+}
+
+// CloseIdleConnections closes the connections which have no request.
+func (t *peerTransport) CloseIdleConnections() {
+	t.mu.Lock()
+	var idle []*peerConn
+	for _, p := range t.peers {
+		conns := p.conns[:0]
+		for _, pc := range p.conns {
+			if pc.streams == 0 {
+				idle = append(idle, pc)
+			} else {
+				conns = append(conns, pc)
+			}
+		}
+		p.conns = conns
+		t.updateStats(p)
+		p.notify()
+	}
+	t.mu.Unlock()
+
+	for _, pc := range idle {
+		pc.cc.Close()
+	}
+	t.http1.CloseIdleConnections()
+}
+
+// acquire returns a connection to addr which can take a request, dialing
+// one if every connection is busy, or waiting for a stream if no more
+// connections can be opened.
+func (t *peerTransport) acquire(ctx context.Context, scheme, addr string) (*peer, *peerConn, error) {
+	for {
+		t.mu.Lock()
+		p := t.peers[addr]
+		if p == nil {
+			p = &peer{
+				addr:    addr,
+				stats:   t.opt.Stats.WithTags("peer:" + addr),
+				changed: make(chan struct{}),
+			}
+			t.peers[addr] = p
+		}
+		if time.Now().Before(p.http1Until) {
+			t.mu.Unlock()
+			return nil, nil, errPeerHTTP1
+		}
+
+		// Use the connection with the fewest requests. Connections which
+		// were closed, or received a GOAWAY, are dropped from the pool and
+		// closed by their keepalive.
+		var best *peerConn
+		conns := p.conns[:0]
+		for _, pc := range p.conns {
+			if !pc.cc.CanTakeNewRequest() {
+				continue
+			}
+			conns = append(conns, pc)
+			if pc.streams < t.opt.MaxStreamsPerConn && (best == nil || pc.streams < best.streams) {
+				best = pc
+			}
+		}
+		p.conns = conns
+		if best != nil {
+			best.streams++
+			best.lastUsed = time.Now()
+			t.updateStats(p)
+			t.mu.Unlock()
+			return p, best, nil
+		}
+
+		if len(p.conns)+p.dialing < t.opt.MaxConnsPerPeer {
+			p.dialing++
+			t.mu.Unlock()
+			pc, err := t.dial(ctx, scheme, addr)
+
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			p.dialing--
+			p.notify()
+			if err == errPeerHTTP1 {
+				p.http1Until = time.Now().Add(http2FallbackPeriod)
+				return nil, nil, err
+			} else if err != nil {
+				return nil, nil, err
+			}
+			pc.streams, pc.lastUsed = 1, time.Now()
+			p.conns = append(p.conns, pc)
+			t.updateStats(p)
+			go t.keepalive(p, pc)
+			return p, pc, nil
+		}
+
+		// Wait for a stream or a connection to be released.
+		changed := p.changed
+		t.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// dial opens an HTTP/2 connection to addr. It returns errPeerHTTP1 if the
+// peer doesn't negotiate HTTP/2 over TLS, or doesn't answer a ping over h2c.
+func (t *peerTransport) dial(ctx context.Context, scheme, addr string) (*peerConn, error) {
+	conn, err := t.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme == "https" {
+		cfg := &tls.Config{}
+		if t.tls != nil {
+			cfg = t.tls.Clone()
+		}
+		cfg.NextProtos = []string{http2.NextProtoTLS, "http/1.1"}
+		if cfg.ServerName == "" {
+			cfg.ServerName, _, _ = net.SplitHostPort(addr)
+		}
+		tlsConn := tls.Client(conn, cfg)
+		deadline := time.Now().Add(10 * time.Second)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		if err := tlsConn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		} else if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		} else if err := tlsConn.SetDeadline(time.Time{}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if tlsConn.ConnectionState().NegotiatedProtocol != http2.NextProtoTLS {
+			tlsConn.Close()
+			return nil, errPeerHTTP1
+		}
+		conn = tlsConn
+	}
+
+	cc, err := t.http2.NewClientConn(conn)
+	if err != nil {
+		conn.Close()
+		return nil, errPeerHTTP1
+	}
+
+	// A peer which only speaks HTTP/1.1 doesn't answer the connection
+	// preface with HTTP/2 frames, so the ping fails.
+	pingCtx, cancel := context.WithTimeout(ctx, t.opt.PingTimeout)
+	defer cancel()
+	if err := cc.Ping(pingCtx); err != nil {
+		cc.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errPeerHTTP1
+	}
+	return &peerConn{cc: cc}, nil
+}
+
+// keepalive pings a connection until it fails to answer, or is idle for
+// too long, and then closes it.
+func (t *peerTransport) keepalive(p *peer, pc *peerConn) {
+	ticker := time.NewTicker(t.opt.PingInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		t.mu.Lock()
+		idle := pc.streams == 0 && time.Since(pc.lastUsed) > http2IdleConnTimeout
+		t.mu.Unlock()
+		if idle || !pc.cc.CanTakeNewRequest() {
+			t.remove(p, pc)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), t.opt.PingTimeout)
+		err := pc.cc.Ping(ctx)
+		cancel()
+		if err != nil {
+			t.remove(p, pc)
+			return
+		}
+	}
+}
+
+// release releases a stream of a connection.
+func (t *peerTransport) release(p *peer, pc *peerConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pc.streams--
+	pc.lastUsed = time.Now()
+	t.updateStats(p)
+	p.notify()
+}
+
+// remove closes a connection and removes it from the pool of its peer.
+func (t *peerTransport) remove(p *peer, pc *peerConn) {
+	t.mu.Lock()
+	for i := range p.conns {
+		if p.conns[i] == pc {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			break
+		}
+	}
+	t.updateStats(p)
+	p.notify()
+	t.mu.Unlock()
+
+	pc.cc.Close()
+}
+
+// updateStats reports the number of connections and streams of a peer.
+// The transport lock must be held.
+func (t *peerTransport) updateStats(p *peer) {
+	var streams int
+	for _, pc := range p.conns {
+		streams += pc.streams
+	}
+	p.stats.Gauge("peerConnections", float64(len(p.conns)), 1.0)
+	p.stats.Gauge("peerStreams", float64(streams), 1.0)
+}
+
+// notify wakes up the requests waiting for a connection of the peer.
+func (p *peer) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// streamBody releases the stream of a response when the body is closed.
+type streamBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close closes the body and releases its stream.
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// canonicalAddr returns the host and port of a URL host, with the default
+// port of the scheme if it has none.
+func canonicalAddr(scheme, host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	if scheme == "https" {
+		return net.JoinHostPort(host, "443")
+	}
+	return net.JoinHostPort(host, "80")
+}
+
+// connListener is a listener which tracks its open connections, so that
+// connections hijacked by h2c, which the server doesn't close on shutdown,
+// can be closed.
+type connListener struct {
+	net.Listener
+
+	mu    sync.Mutex
+	conns map[*trackedConn]struct{}
+}
+
+func newConnListener(ln net.Listener) *connListener {
+	return &connListener{Listener: ln, conns: make(map[*trackedConn]struct{})}
+}
+
+// Accept waits for and returns the next connection.
+func (l *connListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	c := &trackedConn{Conn: conn, l: l}
+	l.mu.Lock()
+	l.conns[c] = struct{}{}
+	l.mu.Unlock()
+	return c, nil
+}
+
+// closeConns closes the open connections.
+func (l *connListener) closeConns() {
+	l.mu.Lock()
+	conns := make([]*trackedConn, 0, len(l.conns))
+	for c := range l.conns {
+		conns = append(conns, c)
+	}
+	l.mu.Unlock()
+
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+// trackedConn is a connection of a connListener.
+type trackedConn struct {
+	net.Conn
+	l *connListener
+}
+
+// Close closes the connection and stops tracking it.
+func (c *trackedConn) Close() error {
+	c.l.mu.Lock()
+	delete(c.l.conns, c)
+	c.l.mu.Unlock()
+	return c.Conn.Close()
+}
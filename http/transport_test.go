@@ -0,0 +1,195 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	gohttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/http"
+	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pilosa/pilosa/v2/test"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// gaugeRecorder is a stats client which records the largest value of each
+// gauge.
+type gaugeRecorder struct {
+	stats.StatsClient
+	mu  sync.Mutex
+	max map[string]float64
+}
+
+func (r *gaugeRecorder) WithTags(tags ...string) stats.StatsClient { return r }
+
+func (r *gaugeRecorder) Gauge(name string, value float64, rate float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if value > r.max[name] {
+		r.max[name] = value
+	}
+}
+
+// Ensure internal requests use HTTP/2 with a node, and multiplex concurrent
+// requests over a limited number of connections.
+func TestInternalHTTPClient_HTTP2(t *testing.T) {
+	t.Run("Node", func(t *testing.T) {
+		cluster := test.MustRunCluster(t, 1)
+		defer cluster.Close()
+
+		client := http.GetInternalHTTPClient(nil, http.HTTP2Options{Enabled: true})
+		resp, err := client.Get(cluster[0].URL() + "/version")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != gohttp.StatusOK || resp.ProtoMajor != 2 {
+			t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Proto)
+		}
+	})
+
+	t.Run("Multiplex", func(t *testing.T) {
+		release := make(chan struct{})
+		srv := httptest.NewServer(h2c.NewHandler(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			<-release
+			fmt.Fprint(w, r.Proto)
+		}), &http2.Server{}))
+		defer srv.Close()
+
+		rec := &gaugeRecorder{StatsClient: stats.NopStatsClient, max: map[string]float64{}}
+		client := http.GetInternalHTTPClient(nil, http.HTTP2Options{
+			Enabled:           true,
+			MaxConnsPerPeer:   2,
+			MaxStreamsPerConn: 5,
+			Stats:             rec,
+		})
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 30)
+		for i := 0; i < 30; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.Get(srv.URL)
+				if err != nil {
+					errs <- err
+					return
+				}
+				defer resp.Body.Close()
+				if body, err := ioutil.ReadAll(resp.Body); err != nil {
+					errs <- err
+				} else if string(body) != "HTTP/2.0" {
+					errs <- fmt.Errorf("unexpected protocol: %s", body)
+				}
+			}()
+		}
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatal(err)
+		}
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		if conns, streams := rec.max["peerConnections"], rec.max["peerStreams"]; conns != 2 || streams != 10 {
+			t.Fatalf("unexpected connections %v, streams %v", conns, streams)
+		}
+	})
+
+	// A server which only supports HTTP/1.1, such as an older node, is sent
+	// HTTP/1.1 requests.
+	t.Run("FallbackHTTP1", func(t *testing.T) {
+		srv := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+			fmt.Fprint(w, r.Proto)
+		}))
+		defer srv.Close()
+
+		client := http.GetInternalHTTPClient(nil, http.HTTP2Options{Enabled: true, PingTimeout: time.Second})
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			} else if string(body) != "HTTP/1.1" {
+				t.Fatalf("unexpected protocol: %s", body)
+			}
+		}
+	})
+}
+
+// BenchmarkFanOut measures the latency of queries of a 16-node cluster,
+// which are sent to every node, over HTTP/1.1 and HTTP/2.
+func BenchmarkFanOut(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("HTTP2=%v", enabled), func(b *testing.B) {
+			cluster := test.MustNewCluster(b, 16)
+			for _, c := range cluster {
+				c.Config.HTTP2.Enabled = enabled
+			}
+			if err := cluster.Start(); err != nil {
+				b.Fatal(err)
+			}
+			defer cluster.Close()
+
+			cluster.CreateField(b, "i", pilosa.IndexOptions{}, "f")
+			var bits [][2]uint64
+			for shard := uint64(0); shard < 64; shard++ {
+				for row := uint64(0); row < 4; row++ {
+					bits = append(bits, [2]uint64{row, shard*pilosa.ShardWidth + row})
+				}
+			}
+			cluster.ImportBits(b, "i", "f", bits)
+
+			var mu sync.Mutex
+			var latencies []time.Duration
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					start := time.Now()
+					if _, err := cluster[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))"}); err != nil {
+						b.Error(err)
+						return
+					}
+					elapsed := time.Since(start)
+					mu.Lock()
+					latencies = append(latencies, elapsed)
+					mu.Unlock()
+				}
+			})
+			b.StopTimer()
+
+			sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+			if len(latencies) > 0 {
+				p99 := latencies[len(latencies)*99/100]
+				b.ReportMetric(float64(p99.Microseconds()), "p99-µs")
+			}
+		})
+	}
+}
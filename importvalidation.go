@@ -0,0 +1,183 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxInvalidImportRecords is the number of offending records listed by an
+// ImportValidationError.
+const maxInvalidImportRecords = 10
+
+// ImportValidationError is returned for an import with records which can't
+// be imported into a field, or would be written to views which no query
+// reads. It lists the first offending records.
+type ImportValidationError struct {
+	Index  string
+	Field  string
+	Reason string
+
+	// Invalid is the number of offending records, and Records describes
+	// the first of them.
+	Invalid int
+	Records []string
+}
+
+// Error returns the reason and the first offending records.
+func (e *ImportValidationError) Error() string {
+	s := fmt.Sprintf("invalid import into field %s/%s: %s: %d invalid records: %s", e.Index, e.Field, e.Reason, e.Invalid, strings.Join(e.Records, ", "))
+	if e.Invalid > len(e.Records) {
+		s += ", ..."
+	}
+	return s
+}
+
+// importValidationError returns an ImportValidationError for the records
+// of an import of n records for which invalid returns true, or nil if there
+// are none. describe describes the record at a position.
+func (f *Field) importValidationError(reason string, n int, invalid func(i int) bool, describe func(i int) string) *ImportValidationError {
+	var e *ImportValidationError
+	for i := 0; i < n; i++ {
+		if !invalid(i) {
+			continue
+		}
+		if e == nil {
+			e = &ImportValidationError{Index: f.index, Field: f.name, Reason: reason}
+		}
+		e.Invalid++
+		if len(e.Records) < maxInvalidImportRecords {
+			e.Records = append(e.Records, describe(i))
+		}
+	}
+	return e
+}
+
+// importCountError returns an ImportValidationError for an import of rows
+// or values and columns of different lengths, or nil if they match. The
+// records past the shorter list are the offending ones.
+func (f *Field) importCountError(what string, n, columns int, hint string) error {
+	if n == columns {
+		return nil
+	}
+	reason := fmt.Sprintf("%d %s for %d columns", n, what, columns)
+	if hint != "" {
+		reason += "; " + hint
+	}
+	min, max := n, columns
+	if min > max {
+		min, max = max, min
+	}
+	return NewBadRequestError(f.importValidationError(reason, max, func(i int) bool { return i >= min }, func(i int) string {
+		return fmt.Sprintf("record %d", i)
+	}))
+}
+
+// validateImport checks that the bits of an import can be imported into the
+// field, and that each is written to a view which queries read. It returns
+// the timestamps to import, which are dropped if the field has no time
+// quantum and the options allow it.
+func (f *Field) validateImport(rowIDs, columnIDs []uint64, timestamps []*time.Time, options *ImportOptions) ([]*time.Time, error) {
+	if err := f.importCountError("rows", len(rowIDs), len(columnIDs), ""); err != nil {
+		return nil, err
+	} else if len(timestamps) > 0 {
+		if err := f.importCountError("timestamps", len(timestamps), len(columnIDs), ""); err != nil {
+			return nil, err
+		}
+	}
+
+	describe := func(i int) string {
+		if len(timestamps) > i && timestamps[i] != nil {
+			return fmt.Sprintf("record %d (row %d, column %d, timestamp %s)", i, rowIDs[i], columnIDs[i], timestamps[i].Format(time.RFC3339))
+		}
+		return fmt.Sprintf("record %d (row %d, column %d)", i, rowIDs[i], columnIDs[i])
+	}
+	reject := func(reason string, invalid func(i int) bool) error {
+		if e := f.importValidationError(reason, len(rowIDs), invalid, describe); e != nil {
+			return NewBadRequestError(e)
+		}
+		return nil
+	}
+	timestamped := func(i int) bool { return len(timestamps) > i && timestamps[i] != nil }
+
+	switch f.Type() {
+	case FieldTypeInt:
+		// Bits would be written to the standard view, which queries of
+		// int fields don't read.
+		return nil, reject("int fields only import values", func(i int) bool { return true })
+	case FieldTypeBool:
+		if err := reject("bool field imports only support values 0 and 1", func(i int) bool { return rowIDs[i] > 1 }); err != nil {
+			return nil, err
+		}
+	}
+
+	if hasTime(timestamps) {
+		if f.TimeQuantum() == "" {
+			if options.DropTimestamps {
+				return nil, nil
+			}
+			return nil, reject("time quantum not set in field, so timestamps would be ignored; they can be dropped explicitly with dropTimestamps", timestamped)
+		} else if options.Clear {
+			return nil, reject("import clear is not supported with timestamps", timestamped)
+		}
+	}
+
+	// Bits without timestamps are written to the standard view, which
+	// queries don't read if the field has none.
+	if f.options.NoStandardView && !options.Clear {
+		e := f.importValidationError("field has no standard view, so bits without timestamps are never read", len(rowIDs), func(i int) bool { return !timestamped(i) }, describe)
+		if e != nil && !options.allowStandardView {
+			return nil, NewBadRequestError(e)
+		} else if e != nil {
+			f.logger.Printf("importing bits which are never read: %s", e)
+		}
+	}
+	return timestamps, nil
+}
+
+// validateImportValues checks that the values of an import can be imported
+// into the field.
+func (f *Field) validateImportValues(columnIDs []uint64, values []int64) error {
+	if typ := f.Type(); typ != FieldTypeInt {
+		e := f.importValidationError(fmt.Sprintf("%s fields only import bits", typ), len(columnIDs), func(i int) bool { return true }, func(i int) string {
+			return fmt.Sprintf("record %d (column %d)", i, columnIDs[i])
+		})
+		if e != nil {
+			return NewBadRequestError(e)
+		}
+		return nil
+	}
+
+	// A bit import sent to an int field has no values.
+	if err := f.importCountError("values", len(values), len(columnIDs), "int fields only import values"); err != nil {
+		return err
+	}
+
+	bsig := f.bsiGroup(f.name)
+	if bsig == nil {
+		return ErrBSIGroupNotFound
+	}
+	e := f.importValidationError(fmt.Sprintf("values out of range [%d, %d]", bsig.Min, bsig.Max), len(values), func(i int) bool {
+		return values[i] < bsig.Min || values[i] > bsig.Max
+	}, func(i int) string {
+		return fmt.Sprintf("record %d (column %d, value %d)", i, columnIDs[i], values[i])
+	})
+	if e != nil {
+		return NewBadRequestError(e)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestField_ImportValidation(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := h.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := idx.CreateField("set")
+	if err != nil {
+		t.Fatal(err)
+	}
+	noStandard, err := idx.CreateField("nostd", OptFieldTypeTime("YMD", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := idx.CreateField("value", OptFieldTypeInt(0, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// validationError returns the ImportValidationError of a rejected
+	// import.
+	validationError := func(t *testing.T, err error) *ImportValidationError {
+		t.Helper()
+		if _, ok := errors.Cause(err).(BadRequestError); !ok {
+			t.Fatalf("expected bad request, got %v", err)
+		}
+		e, ok := errors.Cause(err).(BadRequestError).error.(*ImportValidationError)
+		if !ok {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+		return e
+	}
+
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("TimestampsWithoutTimeQuantum", func(t *testing.T) {
+		err := set.Import([]uint64{1, 2, 3}, []uint64{1, 2, 3}, []*time.Time{nil, &ts, &ts})
+		e := validationError(t, err)
+		if e.Invalid != 2 || !reflect.DeepEqual(e.Records, []string{
+			"record 1 (row 2, column 2, timestamp 2020-01-01T00:00:00Z)",
+			"record 2 (row 3, column 3, timestamp 2020-01-01T00:00:00Z)",
+		}) || !strings.Contains(err.Error(), "time quantum not set in field") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Timestamps are dropped on request.
+		if err := set.Import([]uint64{1, 2, 3}, []uint64{1, 2, 3}, []*time.Time{nil, &ts, &ts}, OptImportOptionsDropTimestamps(true)); err != nil {
+			t.Fatal(err)
+		} else if row, err := set.Row(3); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(row.Columns(), []uint64{3}) {
+			t.Fatalf("unexpected columns: %v", row.Columns())
+		}
+	})
+
+	t.Run("NoStandardView", func(t *testing.T) {
+		err := noStandard.Import([]uint64{1, 1}, []uint64{1, 2}, []*time.Time{&ts, nil})
+		if e := validationError(t, err); e.Invalid != 1 || e.Records[0] != "record 1 (row 1, column 2)" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Lenient imports are written with a warning.
+		if err := noStandard.Import([]uint64{1, 1}, []uint64{1, 2}, []*time.Time{&ts, nil}, optImportOptionsAllowStandardView(true)); err != nil {
+			t.Fatal(err)
+		} else if noStandard.view(viewStandard) == nil {
+			t.Fatal("expected standard view")
+		}
+	})
+
+	t.Run("BitsIntoIntField", func(t *testing.T) {
+		err := value.Import([]uint64{1}, []uint64{1}, nil)
+		if e := validationError(t, err); e.Invalid != 1 || value.view(viewStandard) != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ValuesIntoSetField", func(t *testing.T) {
+		err := set.importValue([]uint64{1, 2}, []int64{5, 6}, &ImportOptions{})
+		if e := validationError(t, err); e.Invalid != 2 {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("MissingValues", func(t *testing.T) {
+		err := value.importValue([]uint64{1, 2, 3}, []int64{5}, &ImportOptions{})
+		if e := validationError(t, err); e.Invalid != 2 || !reflect.DeepEqual(e.Records, []string{"record 1", "record 2"}) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ValuesOutOfRange", func(t *testing.T) {
+		columnIDs := make([]uint64, 20)
+		values := make([]int64, 20)
+		for i := range values {
+			columnIDs[i] = uint64(i)
+			values[i] = int64(90 + i)
+		}
+		err := value.importValue(columnIDs, values, &ImportOptions{})
+		e := validationError(t, err)
+		if e.Invalid != 9 || len(e.Records) != 9 || e.Records[0] != "record 11 (column 11, value 101)" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Only the first records are listed.
+		for i := range values {
+			values[i] = -1
+		}
+		err = value.importValue(columnIDs, values, &ImportOptions{})
+		if e := validationError(t, err); e.Invalid != 20 || len(e.Records) != maxInvalidImportRecords || !strings.HasSuffix(err.Error(), ", ...") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
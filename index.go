@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -35,11 +36,31 @@ import (
 
 // Index represents a container for fields.
 type Index struct {
+	// Bytes used by the index on disk when it was last measured, and 1 if
+	// that exceeded the quota. Accessed atomically.
+	diskUsage int64
+	overQuota int32
+
 	mu   sync.RWMutex
 	path string
 	name string
 	keys bool // use string keys
 
+	// Number of columns in each shard of the index.
+	shardWidth uint64
+
+	// Rejects writes while set.
+	readOnly bool
+
+	// Number of most recent shards queried by default.
+	shardWindow uint64
+
+	// Disk quota in bytes on each node, or zero for no quota.
+	quota int64
+
+	// Options inherited by created fields, or nil for none.
+	defaultFieldOptions *DefaultFieldOptions
+
 	// Existence tracking.
 	trackExistence bool
 	existenceFld   *Field
@@ -47,6 +68,9 @@ type Index struct {
 	// Fields by name.
 	fields map[string]*Field
 
+	// Ingest mappings by name.
+	ingestMappingsByName map[string]*IngestMapping
+
 	newAttrStore func(string) AttrStore
 
 	// Column attribute storage and cache.
@@ -59,12 +83,22 @@ type Index struct {
 
 	logger        logger.Logger
 	snapshotQueue chan *fragment
+	opIDOptions   operationIDOptions
+	changeOptions changeStreamOptions
 
 	// Used for notifying holder when a field is added.
 	holder *Holder
 
 	// Instantiates new translation stores for fields.
 	OpenTranslateStore OpenTranslateStoreFunc
+
+	// Number of handles to the index in use, and whether the index is being
+	// deleted. Once deleting is set, no new handle is acquired, and drained
+	// is closed when the last handle is released.
+	refMu    sync.Mutex
+	refs     int
+	deleting bool
+	drained  chan struct{}
 }
 
 // NewIndex returns a new instance of Index.
@@ -79,6 +113,8 @@ func NewIndex(path, name string) (*Index, error) {
 		name:   name,
 		fields: make(map[string]*Field),
 
+		ingestMappingsByName: make(map[string]*IngestMapping),
+
 		newAttrStore: newNopAttrStore,
 		columnAttrs:  nopStore,
 
@@ -86,6 +122,9 @@ func NewIndex(path, name string) (*Index, error) {
 		Stats:          stats.NopStatsClient,
 		logger:         logger.NopLogger,
 		trackExistence: true,
+		shardWidth:     ShardWidth,
+		opIDOptions:    defaultOperationIDOptions(),
+		changeOptions:  defaultChangeStreamOptions(),
 
 		OpenTranslateStore: OpenInMemTranslateStore,
 	}, nil
@@ -100,6 +139,211 @@ func (i *Index) Path() string { return i.path }
 // Keys returns true if the index uses string keys.
 func (i *Index) Keys() bool { return i.keys }
 
+// ShardWidth returns the number of columns in each shard of the index.
+func (i *Index) ShardWidth() uint64 { return i.shardWidth }
+
+// ReadOnly returns true if writes to the index are rejected.
+func (i *Index) ReadOnly() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.readOnly
+}
+
+// SetReadOnly sets whether writes to the index are rejected. The flag is
+// persisted in the index meta file.
+func (i *Index) SetReadOnly(readOnly bool) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.readOnly == readOnly {
+		return nil
+	}
+	i.readOnly = readOnly
+	if err := i.saveMeta(); err != nil {
+		i.readOnly = !readOnly
+		return errors.Wrap(err, "saving meta")
+	}
+	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
+	}
+	return nil
+}
+
+// Quota returns the disk quota of the index in bytes, or zero if the index
+// has no quota.
+func (i *Index) Quota() int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.quota
+}
+
+// SetQuota sets the disk quota of the index in bytes; zero removes the
+// quota. The quota is persisted in the index meta file, and applies to the
+// data of the index on each node separately.
+func (i *Index) SetQuota(quota int64) error {
+	if quota < 0 {
+		return NewBadRequestError(ErrInvalidQuota)
+	}
+	if err := func() error {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+
+		if i.quota == quota {
+			return nil
+		}
+		prev := i.quota
+		i.quota = quota
+		if err := i.saveMeta(); err != nil {
+			i.quota = prev
+			return errors.Wrap(err, "saving meta")
+		}
+		if i.holder != nil {
+			i.holder.bumpSchemaGeneration()
+		}
+		return nil
+	}(); err != nil {
+		return err
+	}
+
+	// Check the new quota right away rather than at the next measurement.
+	return i.updateDiskUsage()
+}
+
+// DefaultFieldOptions returns the options which fields created in the index
+// inherit, or nil if there are none.
+func (i *Index) DefaultFieldOptions() *DefaultFieldOptions {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.defaultFieldOptions.clone()
+}
+
+// SetDefaultFieldOptions sets the options which fields created in the index
+// inherit; nil removes them. Fields which already exist are unchanged.
+func (i *Index) SetDefaultFieldOptions(d *DefaultFieldOptions) error {
+	if err := d.validate(); err != nil {
+		return NewBadRequestError(err)
+	}
+	d = d.clone()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	prev := i.defaultFieldOptions
+	if (prev == nil && d == nil) || (prev != nil && d != nil && *prev == *d) {
+		return nil
+	}
+	i.defaultFieldOptions = d
+	if err := i.saveMeta(); err != nil {
+		i.defaultFieldOptions = prev
+		return errors.Wrap(err, "saving meta")
+	}
+	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
+	}
+	return nil
+}
+
+// DiskUsage returns the number of bytes used by the index on disk on this
+// node, as of the last measurement.
+func (i *Index) DiskUsage() int64 {
+	return atomic.LoadInt64(&i.diskUsage)
+}
+
+// QuotaExceeded returns true if the index uses more disk space on this node
+// than its quota. Writes which add data to the index are rejected with
+// ErrQuotaExceeded while it does; reads and clears are still allowed.
+func (i *Index) QuotaExceeded() bool {
+	quota := i.Quota()
+	return quota > 0 && i.DiskUsage() > quota
+}
+
+// updateDiskUsage measures the disk space used by the index and reports it
+// in the stats, along with whether the quota is exceeded. Exceeding the
+// quota, or getting back under it, is logged.
+func (i *Index) updateDiskUsage() error {
+	n, err := dirSize(i.path)
+	if err != nil {
+		return errors.Wrap(err, "measuring disk usage")
+	}
+	atomic.StoreInt64(&i.diskUsage, n)
+	i.Stats.Gauge("diskUsage", float64(n), 1.0)
+
+	// The retained snapshots of a field are part of the disk usage of the
+	// index, and are also reported per field.
+	for _, f := range i.Fields() {
+		if f.Options().SnapshotRetention <= 0 {
+			continue
+		}
+		n, err := dirSize(f.retainedSnapshotsPath())
+		if err != nil {
+			return errors.Wrap(err, "measuring retained snapshots")
+		}
+		f.Stats.Gauge("retainedSnapshotsDiskUsage", float64(n), 1.0)
+	}
+
+	var over int32
+	if i.QuotaExceeded() {
+		over = 1
+	}
+	i.Stats.Gauge("quotaExceeded", float64(over), 1.0)
+	if atomic.SwapInt32(&i.overQuota, over) != over {
+		if over == 1 {
+			i.Stats.Count("quotaExceededEvent", 1, 1.0)
+			i.logger.Printf("index %s exceeds its disk quota, rejecting writes: %d of %d bytes", i.name, n, i.Quota())
+		} else {
+			i.logger.Printf("index %s is within its disk quota, accepting writes: %d of %d bytes", i.name, n, i.Quota())
+		}
+	}
+	return nil
+}
+
+// dirSize returns the total size of the files under path. Files which are
+// removed while it runs are skipped.
+func dirSize(path string) (int64, error) {
+	var n int64
+	err := filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			n += fi.Size()
+		}
+		return nil
+	})
+	return n, err
+}
+
+// setFieldTimeQuantum changes the time quantum of a time field at t. Existing
+// views are left as they are, so views for added units only receive writes
+// from t on, and range queries only use them from then.
+func (i *Index) setFieldTimeQuantum(name string, q TimeQuantum, t time.Time) error {
+	f := i.Field(name)
+	if f == nil {
+		return newNotFoundError(ErrFieldNotFound)
+	} else if f.Type() != FieldTypeTime {
+		return NewBadRequestError(errors.New("time quantum can only be changed on time fields"))
+	} else if !q.Valid() {
+		return NewBadRequestError(ErrInvalidTimeQuantum)
+	}
+
+	if f.TimeQuantum() == q {
+		return nil
+	}
+	if err := f.setTimeQuantum(q, t); err != nil {
+		return errors.Wrap(err, "setting time quantum")
+	}
+	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
+	}
+	return nil
+}
+
+// ShardWindow returns the number of most recent shards queried when a
+// query does not specify its shards. Zero means all shards are queried.
+func (i *Index) ShardWindow() uint64 { return i.shardWindow }
+
 // ColumnAttrStore returns the storage for column attributes.
 func (i *Index) ColumnAttrStore() AttrStore { return i.columnAttrs }
 
@@ -114,10 +358,21 @@ func (i *Index) Options() IndexOptions {
 }
 
 func (i *Index) options() IndexOptions {
-	return IndexOptions{
+	opt := IndexOptions{
 		Keys:           i.keys,
 		TrackExistence: i.trackExistence,
+		ReadOnly:       i.readOnly,
+		ShardWindow:    i.shardWindow,
+		Quota:          i.quota,
+
+		DefaultFieldOptions: i.defaultFieldOptions.clone(),
+	}
+	// The default width is left unset so that the options of
+	// existing indexes are unchanged.
+	if i.shardWidth != ShardWidth {
+		opt.ShardWidth = i.shardWidth
 	}
+	return opt
 }
 
 // Open opens and initializes the index.
@@ -240,6 +495,20 @@ func (i *Index) loadMeta() error {
 	// Copy metadata fields.
 	i.keys = pb.Keys
 	i.trackExistence = pb.TrackExistence
+	if pb.ShardWidth != 0 {
+		i.shardWidth = pb.ShardWidth
+	}
+	i.readOnly = pb.ReadOnly
+	i.shardWindow = pb.ShardWindow
+	i.quota = pb.Quota
+	i.defaultFieldOptions = (&DefaultFieldOptions{
+		CacheType:   pb.DefaultCacheType,
+		CacheSize:   pb.DefaultCacheSize,
+		TimeQuantum: TimeQuantum(pb.DefaultTimeQuantum),
+	}).clone()
+	for _, m := range decodeIngestMappings(pb.IngestMappings) {
+		i.ingestMappingsByName[m.Name] = m
+	}
 
 	return nil
 }
@@ -247,10 +516,21 @@ func (i *Index) loadMeta() error {
 // saveMeta writes meta data for the index.
 func (i *Index) saveMeta() error {
 	// Marshal metadata.
-	buf, err := proto.Marshal(&internal.IndexMeta{
+	pb := &internal.IndexMeta{
 		Keys:           i.keys,
 		TrackExistence: i.trackExistence,
-	})
+		ShardWidth:     i.shardWidth,
+		ReadOnly:       i.readOnly,
+		ShardWindow:    i.shardWindow,
+		Quota:          i.quota,
+		IngestMappings: encodeIngestMappings(i.ingestMappings()),
+	}
+	if d := i.defaultFieldOptions; d != nil {
+		pb.DefaultCacheType = d.CacheType
+		pb.DefaultCacheSize = d.CacheSize
+		pb.DefaultTimeQuantum = string(d.TimeQuantum)
+	}
+	buf, err := proto.Marshal(pb)
 	if err != nil {
 		return errors.Wrap(err, "marshalling")
 	}
@@ -288,6 +568,51 @@ func (i *Index) Close() error {
 	return nil
 }
 
+// acquire adds a handle to the index. It returns false if the index is being
+// deleted.
+func (i *Index) acquire() bool {
+	i.refMu.Lock()
+	defer i.refMu.Unlock()
+	if i.deleting {
+		return false
+	}
+	i.refs++
+	return true
+}
+
+// release removes a handle added by acquire.
+func (i *Index) release() {
+	i.refMu.Lock()
+	defer i.refMu.Unlock()
+	i.refs--
+	if i.refs == 0 && i.drained != nil {
+		close(i.drained)
+		i.drained = nil
+	}
+}
+
+// isDeleting returns true if the index is being deleted.
+func (i *Index) isDeleting() bool {
+	i.refMu.Lock()
+	defer i.refMu.Unlock()
+	return i.deleting
+}
+
+// markDeleting refuses new handles to the index, and returns a channel which
+// is closed once the handles in use are released.
+func (i *Index) markDeleting() <-chan struct{} {
+	i.refMu.Lock()
+	defer i.refMu.Unlock()
+	i.deleting = true
+	ch := make(chan struct{})
+	if i.refs == 0 {
+		close(ch)
+	} else {
+		i.drained = ch
+	}
+	return ch
+}
+
 // AvailableShards returns a bitmap of all shards with data in the index.
 func (i *Index) AvailableShards() *roaring.Bitmap {
 	if i == nil {
@@ -370,6 +695,7 @@ func (i *Index) CreateField(name string, opts ...FieldOption) (*Field, error) {
 			return nil, errors.Wrap(err, "applying option")
 		}
 	}
+	i.defaultFieldOptions.apply(&fo)
 
 	return i.createField(name, fo)
 }
@@ -397,6 +723,7 @@ func (i *Index) CreateFieldIfNotExists(name string, opts ...FieldOption) (*Field
 			return nil, errors.Wrap(err, "applying option")
 		}
 	}
+	i.defaultFieldOptions.apply(&fo)
 
 	return i.createField(name, fo)
 }
@@ -418,6 +745,8 @@ func (i *Index) createField(name string, opt FieldOptions) (*Field, error) {
 		return nil, errors.New("field name required")
 	} else if opt.CacheType != "" && !isValidCacheType(opt.CacheType) {
 		return nil, ErrInvalidCacheType
+	} else if opt.Scale != 0 && opt.Type != FieldTypeInt {
+		return nil, errors.New("scale only applies to int fields")
 	}
 
 	// Initialize field.
@@ -447,6 +776,7 @@ func (i *Index) createField(name string, opt FieldOptions) (*Field, error) {
 
 	// Update replication, if needed.
 	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
 		go i.holder.refreshTranslateStoreReplicator()
 	}
 
@@ -461,8 +791,11 @@ func (i *Index) newField(path, name string) (*Field, error) {
 	f.logger = i.logger
 	f.Stats = i.Stats
 	f.broadcaster = i.broadcaster
+	f.shardWidth = i.shardWidth
 	f.rowAttrStore = i.newAttrStore(filepath.Join(f.path, ".data"))
 	f.snapshotQueue = i.snapshotQueue
+	f.opIDOptions = i.opIDOptions
+	f.changeOptions = i.changeOptions
 	f.OpenTranslateStore = i.OpenTranslateStore
 	return f, nil
 }
@@ -502,10 +835,127 @@ func (i *Index) DeleteField(name string) error {
 
 	// Remove reference.
 	delete(i.fields, name)
+	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
+	}
+
+	return nil
+}
+
+// IngestMapping returns the ingest mapping with the given name, or nil if
+// it doesn't exist.
+func (i *Index) IngestMapping(name string) *IngestMapping {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.ingestMappingsByName[name]
+}
+
+// IngestMappings returns the ingest mappings of the index, sorted by name.
+func (i *Index) IngestMappings() []*IngestMapping {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.ingestMappings()
+}
 
+func (i *Index) ingestMappings() []*IngestMapping {
+	a := make([]*IngestMapping, 0, len(i.ingestMappingsByName))
+	for _, m := range i.ingestMappingsByName {
+		a = append(a, m)
+	}
+	sort.Slice(a, func(x, y int) bool { return a[x].Name < a[y].Name })
+	return a
+}
+
+// CreateIngestMapping validates m against the fields of the index and adds
+// it to the index.
+func (i *Index) CreateIngestMapping(m *IngestMapping) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.ingestMappingsByName[m.Name] != nil {
+		return newConflictError(ErrIngestMappingExists)
+	}
+	return i.createIngestMapping(m)
+}
+
+// createIngestMappingIfNotExists adds m to the index unless a mapping with
+// the same name exists.
+func (i *Index) createIngestMappingIfNotExists(m *IngestMapping) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.ingestMappingsByName[m.Name] != nil {
+		return nil
+	}
+	return i.createIngestMapping(m)
+}
+
+func (i *Index) createIngestMapping(m *IngestMapping) error {
+	if err := m.validate(i.fieldOptions()); err != nil {
+		return NewBadRequestError(errors.Wrap(err, "validating ingest mapping"))
+	}
+
+	i.ingestMappingsByName[m.Name] = m
+	if err := i.saveMeta(); err != nil {
+		delete(i.ingestMappingsByName, m.Name)
+		return errors.Wrap(err, "saving meta")
+	}
+	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
+	}
 	return nil
 }
 
+// DeleteIngestMapping removes an ingest mapping from the index.
+func (i *Index) DeleteIngestMapping(name string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	m := i.ingestMappingsByName[name]
+	if m == nil {
+		return newNotFoundError(ErrIngestMappingNotFound)
+	}
+	delete(i.ingestMappingsByName, name)
+	if err := i.saveMeta(); err != nil {
+		i.ingestMappingsByName[name] = m
+		return errors.Wrap(err, "saving meta")
+	}
+	if i.holder != nil {
+		i.holder.bumpSchemaGeneration()
+	}
+	return nil
+}
+
+// transformIngest converts records according to the named ingest mapping.
+func (i *Index) transformIngest(name string, records []map[string]interface{}) (*ingestBatch, error) {
+	i.mu.RLock()
+	m := i.ingestMappingsByName[name]
+	fields := i.fieldOptions()
+	i.mu.RUnlock()
+
+	if m == nil {
+		return nil, newNotFoundError(ErrIngestMappingNotFound)
+	}
+	batch, err := m.transform(i.keys, fields, records)
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+	return batch, nil
+}
+
+// fieldOptions returns the options of the fields of the index by name,
+// excluding the existence field.
+func (i *Index) fieldOptions() map[string]FieldOptions {
+	m := make(map[string]FieldOptions, len(i.fields))
+	for name, f := range i.fields {
+		if name == existenceFieldName {
+			continue
+		}
+		m[name] = f.Options()
+	}
+	return m
+}
+
 type indexSlice []*Index
 
 func (p indexSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
@@ -514,10 +964,11 @@ func (p indexSlice) Less(i, j int) bool { return p[i].Name() < p[j].Name() }
 
 // IndexInfo represents schema information for an index.
 type IndexInfo struct {
-	Name       string       `json:"name"`
-	Options    IndexOptions `json:"options"`
-	Fields     []*FieldInfo `json:"fields"`
-	ShardWidth uint64       `json:"shardWidth"`
+	Name           string           `json:"name"`
+	Options        IndexOptions     `json:"options"`
+	Fields         []*FieldInfo     `json:"fields"`
+	ShardWidth     uint64           `json:"shardWidth"`
+	IngestMappings []*IngestMapping `json:"ingestMappings,omitempty"`
 }
 
 type indexInfoSlice []*IndexInfo
@@ -530,6 +981,93 @@ func (p indexInfoSlice) Less(i, j int) bool { return p[i].Name < p[j].Name }
 type IndexOptions struct {
 	Keys           bool `json:"keys"`
 	TrackExistence bool `json:"trackExistence"`
+
+	// ShardWidth is the number of columns in each shard of the index. If
+	// zero, the package-level ShardWidth is used.
+	ShardWidth uint64 `json:"shardWidth,omitempty"`
+
+	// ReadOnly rejects queries and imports which write to the index.
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// ShardWindow limits queries which do not specify their shards to
+	// the given number of most recent shards. If zero, all shards are
+	// queried.
+	ShardWindow uint64 `json:"shardWindow,omitempty"`
+
+	// Quota is the disk space in bytes which the index may use on each
+	// node before writes which add data to it are rejected. If zero, the
+	// index has no quota.
+	Quota int64 `json:"quota,omitempty"`
+
+	// DefaultFieldOptions are the options which fields created in the
+	// index inherit when they aren't given explicitly.
+	DefaultFieldOptions *DefaultFieldOptions `json:"defaultFieldOptions,omitempty"`
+}
+
+// DefaultFieldOptions are the options which fields created in an index
+// inherit when they aren't given explicitly. Changing them doesn't change
+// the fields which already exist.
+type DefaultFieldOptions struct {
+	// CacheType and CacheSize apply to set and mutex fields.
+	CacheType string `json:"cacheType,omitempty"`
+	CacheSize uint32 `json:"cacheSize,omitempty"`
+
+	// TimeQuantum applies to time fields.
+	TimeQuantum TimeQuantum `json:"timeQuantum,omitempty"`
+}
+
+// validate returns an error if d holds an invalid cache type or time quantum.
+func (d *DefaultFieldOptions) validate() error {
+	if d == nil {
+		return nil
+	} else if d.CacheType != "" && !isValidCacheType(d.CacheType) {
+		return ErrInvalidCacheType
+	} else if !d.TimeQuantum.Valid() {
+		return ErrInvalidTimeQuantum
+	}
+	return nil
+}
+
+// clone returns a copy of d, or nil if d sets no option.
+func (d *DefaultFieldOptions) clone() *DefaultFieldOptions {
+	if d == nil || *d == (DefaultFieldOptions{}) {
+		return nil
+	}
+	other := *d
+	return &other
+}
+
+// apply sets the options of fo which apply to its field type and aren't
+// set to the defaults.
+func (d *DefaultFieldOptions) apply(fo *FieldOptions) {
+	if d == nil {
+		return
+	}
+	switch fo.Type {
+	case FieldTypeSet, FieldTypeMutex, "":
+		if fo.CacheType == "" {
+			fo.CacheType = d.CacheType
+		}
+		if fo.CacheSize == 0 && fo.CacheType != CacheTypeNone {
+			fo.CacheSize = d.CacheSize
+		}
+	case FieldTypeTime:
+		if fo.TimeQuantum == "" {
+			fo.TimeQuantum = d.TimeQuantum
+		}
+	}
+}
+
+// validateShardWidth returns an error if w cannot be used as the shard width
+// of an index. A zero width is valid and means the default should be used.
+func validateShardWidth(w uint64) error {
+	if w == 0 {
+		return nil
+	}
+	if w < 1<<16 || w > 1<<32 || w&(w-1) != 0 {
+		return ErrInvalidShardWidth
+	}
+	return nil
 }
 
 // hasTime returns true if a contains a non-nil time.
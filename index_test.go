@@ -211,6 +211,107 @@ func TestIndex_DeleteField(t *testing.T) {
 	}
 }
 
+// Ensure the read-only flag of an index is reported and persisted.
+func TestIndex_ReadOnly(t *testing.T) {
+	index := test.MustOpenIndex()
+	defer index.Close()
+
+	if index.ReadOnly() {
+		t.Fatal("expected new index to be writable")
+	} else if err := index.SetReadOnly(true); err != nil {
+		t.Fatal(err)
+	} else if !index.Options().ReadOnly {
+		t.Fatal("expected read-only option")
+	}
+
+	if err := index.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if !index.ReadOnly() {
+		t.Fatal("expected index to be read-only after reopen")
+	}
+
+	if err := index.SetReadOnly(false); err != nil {
+		t.Fatal(err)
+	} else if err := index.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if index.ReadOnly() {
+		t.Fatal("expected index to be writable after reopen")
+	}
+}
+
+// Ensure the quota of an index is persisted and compared with its disk usage.
+func TestIndex_Quota(t *testing.T) {
+	index := test.MustOpenIndex()
+	defer index.Close()
+
+	if _, err := index.CreateField("f"); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.SetQuota(-1); !isBadRequestError(err) {
+		t.Fatalf("expected bad request error, got %v", err)
+	} else if err := index.SetQuota(1); err != nil {
+		t.Fatal(err)
+	} else if index.DiskUsage() <= 1 {
+		t.Fatalf("unexpected disk usage: %d", index.DiskUsage())
+	} else if !index.QuotaExceeded() {
+		t.Fatal("expected quota to be exceeded")
+	}
+
+	if err := index.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if index.Quota() != 1 || index.Options().Quota != 1 {
+		t.Fatalf("unexpected quota after reopen: %d", index.Quota())
+	}
+
+	if err := index.SetQuota(0); err != nil {
+		t.Fatal(err)
+	} else if index.QuotaExceeded() {
+		t.Fatal("expected no quota")
+	}
+}
+
+// Ensure the default field options of an index are persisted and inherited
+// by the fields created afterwards.
+func TestIndex_DefaultFieldOptions(t *testing.T) {
+	index := test.MustOpenIndex()
+	defer index.Close()
+
+	if _, err := index.CreateField("before"); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.SetDefaultFieldOptions(&pilosa.DefaultFieldOptions{CacheType: "nope"}); !isBadRequestError(err) {
+		t.Fatalf("expected bad request error, got %v", err)
+	} else if err := index.SetDefaultFieldOptions(&pilosa.DefaultFieldOptions{CacheType: pilosa.CacheTypeLRU, CacheSize: 10, TimeQuantum: "YM"}); err != nil {
+		t.Fatal(err)
+	} else if err := index.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if d := index.Options().DefaultFieldOptions; d == nil || *d != (pilosa.DefaultFieldOptions{CacheType: pilosa.CacheTypeLRU, CacheSize: 10, TimeQuantum: "YM"}) {
+		t.Fatalf("unexpected default field options after reopen: %+v", d)
+	}
+
+	if f, err := index.CreateField("s"); err != nil {
+		t.Fatal(err)
+	} else if opt := f.Options(); opt.CacheType != pilosa.CacheTypeLRU || opt.CacheSize != 10 {
+		t.Fatalf("unexpected cache: %s %d", opt.CacheType, opt.CacheSize)
+	} else if f, err := index.CreateField("m", pilosa.OptFieldTypeMutex(pilosa.CacheTypeNone, 0)); err != nil {
+		t.Fatal(err)
+	} else if opt := f.Options(); opt.CacheType != pilosa.CacheTypeNone || opt.CacheSize != 0 {
+		t.Fatalf("unexpected cache: %s %d", opt.CacheType, opt.CacheSize)
+	} else if f, err := index.CreateField("t", pilosa.OptFieldTypeTime("")); err != nil {
+		t.Fatal(err)
+	} else if f.TimeQuantum() != "YM" {
+		t.Fatalf("unexpected time quantum: %s", f.TimeQuantum())
+	} else if opt := index.Field("before").Options(); opt.CacheType != pilosa.DefaultCacheType || opt.CacheSize != pilosa.DefaultCacheSize {
+		t.Fatalf("unexpected cache of existing field: %s %d", opt.CacheType, opt.CacheSize)
+	}
+
+	if err := index.SetDefaultFieldOptions(nil); err != nil {
+		t.Fatal(err)
+	} else if index.Options().DefaultFieldOptions != nil {
+		t.Fatal("expected no default field options")
+	}
+}
+
 // Ensure index can validate its name.
 func TestIndex_InvalidName(t *testing.T) {
 	path, err := ioutil.TempDir("", "pilosa-index-")
@@ -231,3 +332,13 @@ func isNotFoundError(err error) bool {
 	_, ok := root.(pilosa.NotFoundError)
 	return ok
 }
+
+func isConflictError(err error) bool {
+	_, ok := errors.Cause(err).(pilosa.ConflictError)
+	return ok
+}
+
+func isBadRequestError(err error) bool {
+	_, ok := errors.Cause(err).(pilosa.BadRequestError)
+	return ok
+}
@@ -0,0 +1,513 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/internal"
+	"github.com/pkg/errors"
+)
+
+// Ingest action types.
+const (
+	// IngestActionRowPerValue sets the row of the target field which
+	// corresponds to the value of the input field.
+	IngestActionRowPerValue = "row-per-value"
+
+	// IngestActionBoolean sets a single row of the target field when the
+	// value of the input field is true. Bool fields set the row for false
+	// as well.
+	IngestActionBoolean = "boolean"
+
+	// IngestActionValue sets the value of the input field in an int field.
+	IngestActionValue = "value"
+
+	// IngestActionTimestamp uses the value of the input field as the
+	// timestamp of the bits set in time fields by the same record.
+	IngestActionTimestamp = "timestamp"
+)
+
+// IngestTimestampUnix is the timestamp format of input fields which hold
+// seconds since the Unix epoch.
+const IngestTimestampUnix = "unix"
+
+// IngestMapping describes how input records are converted to bits and
+// values of the fields of an index.
+type IngestMapping struct {
+	Name   string         `json:"name"`
+	Fields []*IngestField `json:"fields"`
+}
+
+// IngestField describes a field of the input records. Exactly one field of
+// a mapping is the primary key, which holds the column ID or key of the
+// record.
+type IngestField struct {
+	Name       string          `json:"name"`
+	PrimaryKey bool            `json:"primaryKey,omitempty"`
+	Actions    []*IngestAction `json:"actions,omitempty"`
+}
+
+// IngestAction describes how the value of an input field is written.
+type IngestAction struct {
+	// Field is the name of the target field. Unused by timestamp actions.
+	Field string `json:"field,omitempty"`
+
+	// Type is one of the IngestAction constants.
+	Type string `json:"type"`
+
+	// ValueMap maps string values to row IDs for row-per-value actions.
+	ValueMap map[string]uint64 `json:"valueMap,omitempty"`
+
+	// RowID is the row set by boolean actions.
+	RowID uint64 `json:"rowID,omitempty"`
+
+	// Format is the layout of timestamps, or IngestTimestampUnix. Defaults
+	// to RFC 3339.
+	Format string `json:"format,omitempty"`
+}
+
+// CreateIngestMappingMessage is an internal message indicating ingest
+// mapping creation.
+type CreateIngestMappingMessage struct {
+	Index   string
+	Mapping *IngestMapping
+}
+
+// DeleteIngestMappingMessage is an internal message indicating ingest
+// mapping deletion.
+type DeleteIngestMappingMessage struct {
+	Index string
+	Name  string
+}
+
+// ingestBatch holds the bits and values of a batch of records by field.
+type ingestBatch struct {
+	bits   map[string][]Bit
+	values map[string][]FieldValue
+}
+
+// validate ensures that m is well-formed and that its actions match the
+// options of the fields of an index.
+func (m *IngestMapping) validate(fields map[string]FieldOptions) error {
+	if err := validateName(m.Name); err != nil {
+		return err
+	}
+
+	var primaryKeys, timestamps int
+	names := make(map[string]struct{}, len(m.Fields))
+	for _, f := range m.Fields {
+		if f.Name == "" {
+			return errors.New("input field name required")
+		} else if _, ok := names[f.Name]; ok {
+			return errors.Errorf("duplicate input field %q", f.Name)
+		}
+		names[f.Name] = struct{}{}
+		if f.PrimaryKey {
+			primaryKeys++
+		}
+
+		for _, a := range f.Actions {
+			if a.Type == IngestActionTimestamp {
+				if a.Field != "" {
+					return errors.Errorf("input field %q: timestamp action cannot have a field", f.Name)
+				}
+				timestamps++
+				continue
+			}
+
+			opt, ok := fields[a.Field]
+			if a.Field == "" {
+				return errors.Errorf("input field %q: %s action requires a field", f.Name, a.Type)
+			} else if !ok {
+				return errors.Wrapf(ErrFieldNotFound, "input field %q: %q", f.Name, a.Field)
+			}
+			if err := a.validate(opt); err != nil {
+				return errors.Wrapf(err, "input field %q", f.Name)
+			}
+		}
+	}
+	if primaryKeys != 1 {
+		return errors.New("exactly one input field must be the primary key")
+	} else if timestamps > 1 {
+		return errors.New("at most one input field may be the timestamp")
+	}
+	return nil
+}
+
+// validate ensures that a matches the options of its target field.
+func (a *IngestAction) validate(opt FieldOptions) error {
+	switch a.Type {
+	case IngestActionRowPerValue:
+		switch opt.Type {
+		case FieldTypeSet, FieldTypeMutex, FieldTypeTime:
+		default:
+			return errors.Errorf("%s action not supported by %s field %q", a.Type, opt.Type, a.Field)
+		}
+		if opt.Keys && len(a.ValueMap) > 0 {
+			return errors.Errorf("value map cannot be used with keyed field %q", a.Field)
+		}
+	case IngestActionBoolean:
+		switch opt.Type {
+		case FieldTypeSet, FieldTypeMutex, FieldTypeTime, FieldTypeBool:
+		default:
+			return errors.Errorf("%s action not supported by %s field %q", a.Type, opt.Type, a.Field)
+		}
+		if opt.Keys {
+			return errors.Errorf("%s action not supported by keyed field %q", a.Type, a.Field)
+		}
+	case IngestActionValue:
+		if opt.Type != FieldTypeInt {
+			return errors.Errorf("%s action not supported by %s field %q", a.Type, opt.Type, a.Field)
+		}
+	default:
+		return errors.Errorf("invalid action type %q", a.Type)
+	}
+	if len(a.ValueMap) > 0 && a.Type != IngestActionRowPerValue {
+		return errors.Errorf("value map not supported by %s action", a.Type)
+	}
+	return nil
+}
+
+// transform converts records to the bits and values described by m. keys
+// is true if the index uses column keys. Each record maps input field names
+// to values as decoded from JSON, with numbers either as json.Number or
+// float64.
+func (m *IngestMapping) transform(keys bool, fields map[string]FieldOptions, records []map[string]interface{}) (*ingestBatch, error) {
+	if err := m.validate(fields); err != nil {
+		return nil, errors.Wrap(err, "validating mapping")
+	}
+
+	var primaryKey *IngestField
+	inputs := make(map[string]*IngestField, len(m.Fields))
+	for _, f := range m.Fields {
+		inputs[f.Name] = f
+		if f.PrimaryKey {
+			primaryKey = f
+		}
+	}
+
+	batch := &ingestBatch{
+		bits:   make(map[string][]Bit),
+		values: make(map[string][]FieldValue),
+	}
+	for i, record := range records {
+		// Reject fields which aren't part of the mapping. Sorting keeps the
+		// reported field stable when a record has several.
+		names := make([]string, 0, len(record))
+		for name := range record {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, ok := inputs[name]; !ok {
+				return nil, ingestError(i, name, errors.New("not in mapping"))
+			}
+		}
+
+		// Determine the column of the record.
+		var bit Bit
+		switch v := record[primaryKey.Name].(type) {
+		case nil:
+			return nil, ingestError(i, primaryKey.Name, errors.New("primary key required"))
+		case string:
+			if !keys {
+				return nil, ingestError(i, primaryKey.Name, errors.New("column keys not supported by index"))
+			}
+			bit.ColumnKey = v
+		default:
+			if keys {
+				return nil, ingestError(i, primaryKey.Name, errors.New("index requires column keys"))
+			}
+			id, err := ingestUint(v)
+			if err != nil {
+				return nil, ingestError(i, primaryKey.Name, err)
+			}
+			bit.ColumnID = id
+		}
+
+		// Determine the timestamp of the record.
+		var timestamp int64
+		for _, f := range m.Fields {
+			for _, a := range f.Actions {
+				if a.Type != IngestActionTimestamp || record[f.Name] == nil {
+					continue
+				}
+				t, err := ingestTime(record[f.Name], a.Format)
+				if err != nil {
+					return nil, ingestError(i, f.Name, err)
+				}
+				timestamp = t.UnixNano()
+			}
+		}
+
+		for _, f := range m.Fields {
+			v := record[f.Name]
+			if v == nil {
+				continue
+			}
+			for _, a := range f.Actions {
+				if a.Type == IngestActionTimestamp {
+					continue
+				}
+				opt := fields[a.Field]
+
+				if a.Type == IngestActionValue {
+					n, err := ingestValue(v, opt.Scale)
+					if err != nil {
+						return nil, ingestError(i, f.Name, err)
+					} else if n < opt.Min || n > opt.Max {
+						return nil, ingestError(i, f.Name, errors.Errorf("value %s out of range [%s, %s] of field %q",
+							FormatDecimal(n, opt.Scale), FormatDecimal(opt.Min, opt.Scale), FormatDecimal(opt.Max, opt.Scale), a.Field))
+					}
+					batch.values[a.Field] = append(batch.values[a.Field], FieldValue{ColumnID: bit.ColumnID, ColumnKey: bit.ColumnKey, Value: n})
+					continue
+				}
+
+				b := bit
+				if opt.Type == FieldTypeTime {
+					b.Timestamp = timestamp
+				}
+				switch a.Type {
+				case IngestActionRowPerValue:
+					if err := a.setRow(&b, v, opt); err != nil {
+						return nil, ingestError(i, f.Name, err)
+					}
+				case IngestActionBoolean:
+					set, ok := v.(bool)
+					if !ok {
+						return nil, ingestError(i, f.Name, errors.Errorf("expected boolean, got %v", v))
+					}
+					if opt.Type == FieldTypeBool {
+						b.RowID = falseRowID
+						if set {
+							b.RowID = trueRowID
+						}
+					} else if !set {
+						continue
+					} else {
+						b.RowID = a.RowID
+					}
+				}
+				batch.bits[a.Field] = append(batch.bits[a.Field], b)
+			}
+		}
+	}
+	return batch, nil
+}
+
+// setRow sets the row of b for the value v of a row-per-value action.
+func (a *IngestAction) setRow(b *Bit, v interface{}, opt FieldOptions) error {
+	if s, ok := v.(string); ok {
+		if opt.Keys {
+			b.RowKey = s
+			return nil
+		} else if id, ok := a.ValueMap[s]; ok {
+			b.RowID = id
+			return nil
+		} else if len(a.ValueMap) > 0 {
+			return errors.Errorf("value %q not in value map", s)
+		}
+		return errors.Errorf("string value %q requires a value map or keyed field", s)
+	} else if opt.Keys {
+		return errors.Errorf("keyed field %q requires string values", a.Field)
+	}
+	id, err := ingestUint(v)
+	if err != nil {
+		return err
+	}
+	b.RowID = id
+	return nil
+}
+
+// bitsByShard groups bits by the shard of their column.
+func bitsByShard(bits []Bit, shardWidth uint64) map[uint64][]Bit {
+	m := make(map[uint64][]Bit)
+	for _, bit := range bits {
+		shard := bit.ColumnID / shardWidth
+		m[shard] = append(m[shard], bit)
+	}
+	return m
+}
+
+// valuesByShard groups values by the shard of their column.
+func valuesByShard(vals []FieldValue, shardWidth uint64) map[uint64][]FieldValue {
+	m := make(map[uint64][]FieldValue)
+	for _, val := range vals {
+		shard := val.ColumnID / shardWidth
+		m[shard] = append(m[shard], val)
+	}
+	return m
+}
+
+// ingestError returns err annotated with the record index and input field.
+func ingestError(record int, field string, err error) error {
+	return errors.Wrapf(err, "record %d: field %q", record, field)
+}
+
+// ingestUint returns v as an unsigned integer.
+func ingestUint(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		n, err := strconv.ParseUint(string(v), 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("expected unsigned integer, got %s", v)
+		}
+		return n, nil
+	case float64:
+		if v < 0 || v != math.Trunc(v) || v >= math.MaxUint64 {
+			return 0, errors.Errorf("expected unsigned integer, got %v", v)
+		}
+		return uint64(v), nil
+	}
+	return 0, errors.Errorf("expected unsigned integer, got %v", v)
+}
+
+// ingestInt returns v as a signed integer.
+func ingestInt(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return 0, errors.Errorf("expected integer, got %s", v)
+		}
+		return n, nil
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, errors.Errorf("expected integer, got %v", v)
+		}
+		return int64(v), nil
+	}
+	return 0, errors.Errorf("expected integer, got %v", v)
+}
+
+// ingestValue returns v as a value of an int field with the given scale.
+func ingestValue(v interface{}, scale int64) (int64, error) {
+	if scale == 0 {
+		return ingestInt(v)
+	}
+	n, err := scaledValue(v, scale)
+	if err != nil {
+		return 0, errors.Errorf("expected decimal with at most %d decimal places, got %v", scale, v)
+	}
+	return n, nil
+}
+
+// ingestTime returns v as a time in the given format.
+func ingestTime(v interface{}, format string) (time.Time, error) {
+	if format == IngestTimestampUnix {
+		n, err := ingestInt(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, errors.Errorf("expected timestamp, got %v", v)
+	}
+	if format == "" {
+		format = time.RFC3339
+	}
+	t, err := time.Parse(format, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing timestamp: %v", err)
+	}
+	return t, nil
+}
+
+func encodeIngestMappings(a []*IngestMapping) []*internal.IngestMapping {
+	other := make([]*internal.IngestMapping, len(a))
+	for i := range a {
+		other[i] = encodeIngestMapping(a[i])
+	}
+	return other
+}
+
+func encodeIngestMapping(m *IngestMapping) *internal.IngestMapping {
+	pb := &internal.IngestMapping{
+		Name:   m.Name,
+		Fields: make([]*internal.IngestField, len(m.Fields)),
+	}
+	for i, f := range m.Fields {
+		pf := &internal.IngestField{
+			Name:       f.Name,
+			PrimaryKey: f.PrimaryKey,
+			Actions:    make([]*internal.IngestAction, len(f.Actions)),
+		}
+		for j, a := range f.Actions {
+			pa := &internal.IngestAction{
+				Field:  a.Field,
+				Type:   a.Type,
+				RowID:  a.RowID,
+				Format: a.Format,
+			}
+			for k := range a.ValueMap {
+				pa.ValueKeys = append(pa.ValueKeys, k)
+			}
+			sort.Strings(pa.ValueKeys)
+			for _, k := range pa.ValueKeys {
+				pa.ValueIDs = append(pa.ValueIDs, a.ValueMap[k])
+			}
+			pf.Actions[j] = pa
+		}
+		pb.Fields[i] = pf
+	}
+	return pb
+}
+
+func decodeIngestMappings(a []*internal.IngestMapping) []*IngestMapping {
+	other := make([]*IngestMapping, len(a))
+	for i := range a {
+		other[i] = decodeIngestMapping(a[i])
+	}
+	return other
+}
+
+func decodeIngestMapping(pb *internal.IngestMapping) *IngestMapping {
+	m := &IngestMapping{
+		Name:   pb.Name,
+		Fields: make([]*IngestField, len(pb.Fields)),
+	}
+	for i, pf := range pb.Fields {
+		f := &IngestField{
+			Name:       pf.Name,
+			PrimaryKey: pf.PrimaryKey,
+		}
+		for _, pa := range pf.Actions {
+			a := &IngestAction{
+				Field:  pa.Field,
+				Type:   pa.Type,
+				RowID:  pa.RowID,
+				Format: pa.Format,
+			}
+			if len(pa.ValueKeys) > 0 {
+				a.ValueMap = make(map[string]uint64, len(pa.ValueKeys))
+				for j, k := range pa.ValueKeys {
+					a.ValueMap[k] = pa.ValueIDs[j]
+				}
+			}
+			f.Actions = append(f.Actions, a)
+		}
+		m.Fields[i] = f
+	}
+	return m
+}
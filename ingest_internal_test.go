@@ -0,0 +1,316 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ingestTestFields are the target fields used by ingest mapping tests.
+var ingestTestFields = map[string]FieldOptions{
+	"color":   {Type: FieldTypeSet},
+	"tag":     {Type: FieldTypeSet, Keys: true},
+	"state":   {Type: FieldTypeMutex},
+	"active":  {Type: FieldTypeBool},
+	"flags":   {Type: FieldTypeSet},
+	"age":     {Type: FieldTypeInt, Min: 0, Max: 150},
+	"visited": {Type: FieldTypeTime, TimeQuantum: "YMD"},
+}
+
+// newIngestTestMapping returns a mapping using every action type.
+func newIngestTestMapping() *IngestMapping {
+	return &IngestMapping{
+		Name: "m",
+		Fields: []*IngestField{
+			{Name: "id", PrimaryKey: true},
+			{Name: "color", Actions: []*IngestAction{{Field: "color", Type: IngestActionRowPerValue, ValueMap: map[string]uint64{"red": 1, "blue": 2}}}},
+			{Name: "tag", Actions: []*IngestAction{{Field: "tag", Type: IngestActionRowPerValue}}},
+			{Name: "state", Actions: []*IngestAction{{Field: "state", Type: IngestActionRowPerValue}}},
+			{Name: "active", Actions: []*IngestAction{
+				{Field: "active", Type: IngestActionBoolean},
+				{Field: "flags", Type: IngestActionBoolean, RowID: 7},
+			}},
+			{Name: "age", Actions: []*IngestAction{{Field: "age", Type: IngestActionValue}}},
+			{Name: "page", Actions: []*IngestAction{{Field: "visited", Type: IngestActionRowPerValue}}},
+			{Name: "ts", Actions: []*IngestAction{{Type: IngestActionTimestamp}}},
+		},
+	}
+}
+
+// decodeIngestRecords decodes records as the HTTP handler does.
+func decodeIngestRecords(t *testing.T, s string) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&records); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestIngestMapping_Validate(t *testing.T) {
+	if err := newIngestTestMapping().validate(ingestTestFields); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		modify func(m *IngestMapping)
+		err    string
+	}{
+		{
+			name:   "Name",
+			modify: func(m *IngestMapping) { m.Name = "Bad Name" },
+			err:    "invalid index or field name",
+		},
+		{
+			name:   "NoPrimaryKey",
+			modify: func(m *IngestMapping) { m.Fields[0].PrimaryKey = false },
+			err:    "exactly one input field must be the primary key",
+		},
+		{
+			name:   "TwoPrimaryKeys",
+			modify: func(m *IngestMapping) { m.Fields[1].PrimaryKey = true },
+			err:    "exactly one input field must be the primary key",
+		},
+		{
+			name:   "DuplicateInput",
+			modify: func(m *IngestMapping) { m.Fields[2].Name = "color" },
+			err:    `duplicate input field "color"`,
+		},
+		{
+			name:   "EmptyInput",
+			modify: func(m *IngestMapping) { m.Fields[2].Name = "" },
+			err:    "input field name required",
+		},
+		{
+			name:   "FieldNotFound",
+			modify: func(m *IngestMapping) { m.Fields[1].Actions[0].Field = "nope" },
+			err:    `input field "color": "nope": field not found`,
+		},
+		{
+			name:   "FieldRequired",
+			modify: func(m *IngestMapping) { m.Fields[1].Actions[0].Field = "" },
+			err:    `input field "color": row-per-value action requires a field`,
+		},
+		{
+			name:   "InvalidType",
+			modify: func(m *IngestMapping) { m.Fields[1].Actions[0].Type = "bogus" },
+			err:    `invalid action type "bogus"`,
+		},
+		{
+			name:   "RowPerValueInt",
+			modify: func(m *IngestMapping) { m.Fields[1].Actions[0].Field = "age" },
+			err:    `row-per-value action not supported by int field "age"`,
+		},
+		{
+			name:   "ValueSet",
+			modify: func(m *IngestMapping) { m.Fields[5].Actions[0].Field = "color" },
+			err:    `value action not supported by set field "color"`,
+		},
+		{
+			name:   "BooleanKeys",
+			modify: func(m *IngestMapping) { m.Fields[4].Actions[1].Field = "tag" },
+			err:    `boolean action not supported by keyed field "tag"`,
+		},
+		{
+			name:   "ValueMapKeys",
+			modify: func(m *IngestMapping) { m.Fields[2].Actions[0].ValueMap = map[string]uint64{"a": 1} },
+			err:    `value map cannot be used with keyed field "tag"`,
+		},
+		{
+			name:   "ValueMapBoolean",
+			modify: func(m *IngestMapping) { m.Fields[4].Actions[1].ValueMap = map[string]uint64{"a": 1} },
+			err:    "value map not supported by boolean action",
+		},
+		{
+			name:   "TimestampField",
+			modify: func(m *IngestMapping) { m.Fields[7].Actions[0].Field = "visited" },
+			err:    `input field "ts": timestamp action cannot have a field`,
+		},
+		{
+			name: "TwoTimestamps",
+			modify: func(m *IngestMapping) {
+				m.Fields = append(m.Fields, &IngestField{Name: "ts2", Actions: []*IngestAction{{Type: IngestActionTimestamp}}})
+			},
+			err: "at most one input field may be the timestamp",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newIngestTestMapping()
+			tt.modify(m)
+			if err := m.validate(ingestTestFields); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestIngestMapping_Transform(t *testing.T) {
+	t.Run("Actions", func(t *testing.T) {
+		records := decodeIngestRecords(t, `[
+			{"id": 1, "color": "red", "tag": "x", "state": 3, "active": true, "age": 30, "page": 4, "ts": "2019-03-04T05:06:07Z"},
+			{"id": 18446744073709551615, "color": "blue", "active": false, "age": null},
+			{"id": 2}
+		]`)
+		batch, err := newIngestTestMapping().transform(false, ingestTestFields, records)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ts := time.Date(2019, 3, 4, 5, 6, 7, 0, time.UTC).UnixNano()
+		exp := map[string][]Bit{
+			"color":   {{RowID: 1, ColumnID: 1}, {RowID: 2, ColumnID: math.MaxUint64}},
+			"tag":     {{RowKey: "x", ColumnID: 1}},
+			"state":   {{RowID: 3, ColumnID: 1}},
+			"active":  {{RowID: trueRowID, ColumnID: 1}, {RowID: falseRowID, ColumnID: math.MaxUint64}},
+			"flags":   {{RowID: 7, ColumnID: 1}},
+			"visited": {{RowID: 4, ColumnID: 1, Timestamp: ts}},
+		}
+		if !reflect.DeepEqual(batch.bits, exp) {
+			t.Fatalf("unexpected bits:\n%+v\nexpected:\n%+v", batch.bits, exp)
+		}
+		if exp := map[string][]FieldValue{"age": {{ColumnID: 1, Value: 30}}}; !reflect.DeepEqual(batch.values, exp) {
+			t.Fatalf("unexpected values: %+v", batch.values)
+		}
+	})
+
+	t.Run("ColumnKeys", func(t *testing.T) {
+		records := decodeIngestRecords(t, `[{"id": "a", "color": "red", "age": 5}]`)
+		batch, err := newIngestTestMapping().transform(true, ingestTestFields, records)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := []Bit{{RowID: 1, ColumnKey: "a"}}; !reflect.DeepEqual(batch.bits["color"], exp) {
+			t.Fatalf("unexpected bits: %+v", batch.bits["color"])
+		} else if exp := []FieldValue{{ColumnKey: "a", Value: 5}}; !reflect.DeepEqual(batch.values["age"], exp) {
+			t.Fatalf("unexpected values: %+v", batch.values["age"])
+		}
+	})
+
+	t.Run("TimestampFormat", func(t *testing.T) {
+		for _, tt := range []struct {
+			format string
+			value  interface{}
+		}{
+			{format: IngestTimestampUnix, value: json.Number("1551675967")},
+			{format: "2006-01-02 15:04:05", value: "2019-03-04 05:06:07"},
+		} {
+			m := newIngestTestMapping()
+			m.Fields[7].Actions[0].Format = tt.format
+			batch, err := m.transform(false, ingestTestFields, []map[string]interface{}{{"id": 1.0, "page": 1.0, "ts": tt.value}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			exp := time.Date(2019, 3, 4, 5, 6, 7, 0, time.UTC).UnixNano()
+			if ts := batch.bits["visited"][0].Timestamp; ts != exp {
+				t.Fatalf("format %q: unexpected timestamp %d, expected %d", tt.format, ts, exp)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			name    string
+			keys    bool
+			records string
+			err     string
+		}{
+			{name: "UnknownField", records: `[{"id": 1}, {"id": 2, "nope": 1}]`, err: `record 1: field "nope": not in mapping`},
+			{name: "PrimaryKeyMissing", records: `[{"color": "red"}]`, err: `record 0: field "id": primary key required`},
+			{name: "PrimaryKeyNegative", records: `[{"id": -1}]`, err: `record 0: field "id": expected unsigned integer, got -1`},
+			{name: "PrimaryKeyFloat", records: `[{"id": 1.5}]`, err: `record 0: field "id": expected unsigned integer, got 1.5`},
+			{name: "PrimaryKeyString", records: `[{"id": "a"}]`, err: `record 0: field "id": column keys not supported by index`},
+			{name: "PrimaryKeyID", keys: true, records: `[{"id": 1}]`, err: `record 0: field "id": index requires column keys`},
+			{name: "ValueMapMissing", records: `[{"id": 1, "color": "green"}]`, err: `record 0: field "color": value "green" not in value map`},
+			{name: "StringWithoutMap", records: `[{"id": 1, "state": "on"}]`, err: `record 0: field "state": string value "on" requires a value map or keyed field`},
+			{name: "KeyedFieldID", records: `[{"id": 1, "tag": 1}]`, err: `record 0: field "tag": keyed field "tag" requires string values`},
+			{name: "Boolean", records: `[{"id": 1, "active": "yes"}]`, err: `record 0: field "active": expected boolean, got yes`},
+			{name: "Value", records: `[{"id": 1}, {"id": 2}, {"id": 3, "age": "old"}]`, err: `record 2: field "age": expected integer, got old`},
+			{name: "ValueRange", records: `[{"id": 1, "age": 200}]`, err: `record 0: field "age": value 200 out of range [0, 150] of field "age"`},
+			{name: "Timestamp", records: `[{"id": 1, "ts": "yesterday"}]`, err: `record 0: field "ts": parsing timestamp`},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := newIngestTestMapping().transform(tt.keys, ingestTestFields, decodeIngestRecords(t, tt.records))
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+			})
+		}
+	})
+
+	t.Run("FieldDeleted", func(t *testing.T) {
+		fields := make(map[string]FieldOptions)
+		for name, opt := range ingestTestFields {
+			if name != "age" {
+				fields[name] = opt
+			}
+		}
+		if _, err := newIngestTestMapping().transform(false, fields, nil); err == nil || !strings.Contains(err.Error(), "field not found") {
+			t.Fatalf("expected field not found, got %v", err)
+		}
+	})
+}
+
+// Ensure ingest mappings are persisted in the index meta file.
+func TestIndex_IngestMapping(t *testing.T) {
+	index := mustOpenIndex(IndexOptions{})
+	defer index.Close()
+
+	for name, opt := range ingestTestFields {
+		if _, err := index.createField(name, opt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := newIngestTestMapping()
+	if err := index.CreateIngestMapping(m); err != nil {
+		t.Fatal(err)
+	} else if err := index.CreateIngestMapping(m); err == nil {
+		t.Fatal("expected error creating existing mapping")
+	} else if err := index.CreateIngestMapping(&IngestMapping{Name: "bad"}); err == nil {
+		t.Fatal("expected error creating invalid mapping")
+	}
+
+	// Open a second instance to read the mapping from disk.
+	other, err := NewIndex(index.Path(), index.Name())
+	if err != nil {
+		t.Fatal(err)
+	} else if err := other.loadMeta(); err != nil {
+		t.Fatal(err)
+	} else if om := other.IngestMapping("m"); !reflect.DeepEqual(om, m) {
+		t.Fatalf("unexpected mapping after reload:\n%+v\nexpected:\n%+v", om, m)
+	}
+
+	if err := index.DeleteIngestMapping("m"); err != nil {
+		t.Fatal(err)
+	} else if err := index.DeleteIngestMapping("m"); err == nil {
+		t.Fatal("expected error deleting missing mapping")
+	}
+	other, err = NewIndex(index.Path(), index.Name())
+	if err != nil {
+		t.Fatal(err)
+	} else if err := other.loadMeta(); err != nil {
+		t.Fatal(err)
+	} else if ms := other.IngestMappings(); len(ms) != 0 {
+		t.Fatalf("unexpected mappings after delete: %+v", ms)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"math/bits"
+	"os"
+
+	"github.com/pilosa/pilosa/v2/roaring"
+	"github.com/pilosa/pilosa/v2/syswrap"
+	"github.com/pkg/errors"
+)
+
+// FragmentInspectOptions selects what is reported by a fragment inspection
+// in addition to its summary.
+type FragmentInspectOptions struct {
+	// Shard width of the index of the fragment. Defaults to ShardWidth.
+	ShardWidth uint64
+
+	// Rows to dump the columns of.
+	Rows []uint64
+
+	// Blocks to dump the row/column pairs of.
+	Blocks []int
+}
+
+// FragmentInfo describes the storage of a fragment.
+type FragmentInfo struct {
+	Path           string              `json:"path"`
+	Size           int64               `json:"size"`
+	Flags          byte                `json:"flags"`
+	ShardWidth     uint64              `json:"shardWidth"`
+	Containers     int                 `json:"containers"`
+	ContainerTypes map[string]int      `json:"containerTypes"`
+	OpN            int                 `json:"opN"`
+	Ops            int                 `json:"ops"`
+	CacheDirty     bool                `json:"cacheDirty"`
+	Compactions    int                 `json:"compactions"`
+	LastCompaction *FragmentCompaction `json:"lastCompaction,omitempty"`
+
+	// SharedContainers is the number of containers of a fragment of a day
+	// view which are left out of its data file because they are shared
+	// with the fragments of its hour views, and SharedBytes the number of
+	// bytes which they would take in it.
+	SharedContainers int   `json:"sharedContainers,omitempty"`
+	SharedBytes      int64 `json:"sharedBytes,omitempty"`
+
+	Rows      []FragmentRowInfo   `json:"rows"`
+	Blocks    []FragmentBlock     `json:"blocks"`
+	RowData   []FragmentRowData   `json:"rowData,omitempty"`
+	BlockData []FragmentBlockData `json:"blockData,omitempty"`
+}
+
+// FragmentRowInfo is the number of columns set in a row of a fragment.
+type FragmentRowInfo struct {
+	ID    uint64 `json:"id"`
+	Count uint64 `json:"count"`
+}
+
+// FragmentRowData holds the columns set in a row of a fragment.
+type FragmentRowData struct {
+	ID      uint64   `json:"id"`
+	Columns []uint64 `json:"columns"`
+}
+
+// FragmentBlockData holds the row/column pairs of a block of a fragment.
+type FragmentBlockData struct {
+	ID        int      `json:"id"`
+	RowIDs    []uint64 `json:"rowIDs"`
+	ColumnIDs []uint64 `json:"columnIDs"`
+}
+
+// InspectFragmentFile inspects the fragment storage file at path. The file is
+// only read: it isn't locked, and its op log isn't replayed into a snapshot,
+// so the file of a running server can be inspected.
+func InspectFragmentFile(path string, opt FragmentInspectOptions) (*FragmentInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening file")
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return nil, errors.Wrap(err, "statting file")
+	}
+
+	storage := roaring.NewFileBitmap()
+	if fi.Size() > 0 {
+		data, mapped, err := mapStorageFile(file, fi.Size(), true)
+		if err != nil {
+			return nil, err
+		} else if mapped {
+			defer func() { _ = syswrap.Munmap(data) }()
+		}
+		storage.PreferMapping(mapped)
+		if err := storage.UnmarshalBinary(data); err != nil {
+			return nil, errors.Wrap(err, "unmarshaling storage")
+		}
+	}
+
+	info := inspectStorage(storage, opt)
+	info.Path, info.Size = path, fi.Size()
+	keys, err := readSharedKeys(path + sharedExt)
+	if err != nil {
+		return nil, err
+	}
+	info.SharedContainers = len(keys)
+	return info, nil
+}
+
+// inspect inspects the storage of the fragment. It only takes a read lock,
+// so it doesn't block queries.
+func (f *fragment) inspect(opt FragmentInspectOptions) (*FragmentInfo, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.storage == nil {
+		return nil, ErrFragmentNotFound
+	}
+
+	opt.ShardWidth = f.shardWidth
+	info := inspectStorage(f.storage, opt)
+	info.Path = f.path
+	info.CacheDirty = f.cacheDirty
+	info.Compactions = f.compactions
+	info.LastCompaction = f.lastCompaction
+	info.SharedContainers = len(f.sharedKeys)
+	info.SharedBytes = f.sharedBytes
+	if fi, err := os.Stat(f.path); err == nil {
+		info.Size = fi.Size()
+	}
+	return info, nil
+}
+
+// inspectStorage returns the description of the storage bitmap of a
+// fragment, without modifying it.
+func inspectStorage(storage *roaring.Bitmap, opt FragmentInspectOptions) *FragmentInfo {
+	shardWidth := opt.ShardWidth
+	if shardWidth == 0 {
+		shardWidth = ShardWidth
+	}
+	rowShift := uint(bits.TrailingZeros64(shardWidth)) - 16
+
+	bi := storage.Info()
+	info := &FragmentInfo{
+		Flags:          storage.Flags,
+		ShardWidth:     shardWidth,
+		Containers:     len(bi.Containers),
+		ContainerTypes: make(map[string]int),
+		OpN:            bi.OpN,
+		Ops:            bi.Ops,
+		Rows:           []FragmentRowInfo{},
+		Blocks:         []FragmentBlock{},
+	}
+
+	// Count containers by type and columns by row.
+	for _, ci := range bi.Containers {
+		info.ContainerTypes[ci.Type]++
+		if ci.N == 0 {
+			continue
+		}
+		rowID := ci.Key >> rowShift
+		if n := len(info.Rows); n > 0 && info.Rows[n-1].ID == rowID {
+			info.Rows[n-1].Count += uint64(ci.N)
+		} else {
+			info.Rows = append(info.Rows, FragmentRowInfo{ID: rowID, Count: uint64(ci.N)})
+		}
+	}
+
+	// Compute the checksum of each block the same way fragment.Blocks does.
+	blockWidth := HashBlockSize * shardWidth
+	h := newBlockHasher()
+	itr := storage.Iterator()
+	itr.Seek(0)
+	v, eof := itr.Next()
+	for !eof {
+		h.blockID = int(v / blockWidth)
+		h.Reset()
+		for ; !eof && int(v/blockWidth) == h.blockID; v, eof = itr.Next() {
+			h.WriteValue(v)
+		}
+		info.Blocks = append(info.Blocks, FragmentBlock{ID: h.blockID, Checksum: h.Sum()})
+	}
+
+	for _, rowID := range opt.Rows {
+		data := FragmentRowData{ID: rowID, Columns: []uint64{}}
+		storage.ForEachRange(rowID*shardWidth, (rowID+1)*shardWidth, func(v uint64) {
+			data.Columns = append(data.Columns, v%shardWidth)
+		})
+		info.RowData = append(info.RowData, data)
+	}
+	for _, id := range opt.Blocks {
+		data := FragmentBlockData{ID: id, RowIDs: []uint64{}, ColumnIDs: []uint64{}}
+		storage.ForEachRange(uint64(id)*blockWidth, uint64(id+1)*blockWidth, func(v uint64) {
+			data.RowIDs = append(data.RowIDs, v/shardWidth)
+			data.ColumnIDs = append(data.ColumnIDs, v%shardWidth)
+		})
+		info.BlockData = append(info.BlockData, data)
+	}
+	return info
+}
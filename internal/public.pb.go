@@ -2,30 +2,32 @@
 // source: public.proto
 
 /*
-	Package internal is a generated protocol buffer package.
-
-	It is generated from these files:
-		public.proto
-
-	It has these top-level messages:
-		Row
-		RowIdentifiers
-		Pair
-		FieldRow
-		GroupCount
-		ValCount
-		ColumnAttrSet
-		Attr
-		AttrMap
-		QueryRequest
-		QueryResponse
-		QueryResult
-		ImportRequest
-		ImportValueRequest
-		TranslateKeysRequest
-		TranslateKeysResponse
-		ImportRoaringRequestView
-		ImportRoaringRequest
+Package internal is a generated protocol buffer package.
+
+It is generated from these files:
+
+	public.proto
+
+It has these top-level messages:
+
+	Row
+	RowIdentifiers
+	Pair
+	FieldRow
+	GroupCount
+	ValCount
+	ColumnAttrSet
+	Attr
+	AttrMap
+	QueryRequest
+	QueryResponse
+	QueryResult
+	ImportRequest
+	ImportValueRequest
+	TranslateKeysRequest
+	TranslateKeysResponse
+	ImportRoaringRequestView
+	ImportRoaringRequest
 */
 package internal
 
@@ -52,6 +54,7 @@ type Row struct {
 	Columns []uint64 `protobuf:"varint,1,rep,packed,name=Columns" json:"Columns,omitempty"`
 	Keys    []string `protobuf:"bytes,3,rep,name=Keys" json:"Keys,omitempty"`
 	Attrs   []*Attr  `protobuf:"bytes,2,rep,name=Attrs" json:"Attrs,omitempty"`
+	Roaring []byte   `protobuf:"bytes,4,opt,name=Roaring,proto3" json:"Roaring,omitempty"`
 }
 
 func (m *Row) Reset()                    { *m = Row{} }
@@ -80,6 +83,13 @@ func (m *Row) GetAttrs() []*Attr {
 	return nil
 }
 
+func (m *Row) GetRoaring() []byte {
+	if m != nil {
+		return m.Roaring
+	}
+	return nil
+}
+
 type RowIdentifiers struct {
 	Rows []uint64 `protobuf:"varint,1,rep,packed,name=Rows" json:"Rows,omitempty"`
 	Keys []string `protobuf:"bytes,2,rep,name=Keys" json:"Keys,omitempty"`
@@ -195,6 +205,7 @@ func (m *GroupCount) GetCount() uint64 {
 type ValCount struct {
 	Val   int64 `protobuf:"varint,1,opt,name=Val,proto3" json:"Val,omitempty"`
 	Count int64 `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
+	Scale int64 `protobuf:"varint,3,opt,name=Scale,proto3" json:"Scale,omitempty"`
 }
 
 func (m *ValCount) Reset()                    { *m = ValCount{} }
@@ -216,6 +227,13 @@ func (m *ValCount) GetCount() int64 {
 	return 0
 }
 
+func (m *ValCount) GetScale() int64 {
+	if m != nil {
+		return m.Scale
+	}
+	return 0
+}
+
 type ColumnAttrSet struct {
 	ID    uint64  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	Key   string  `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
@@ -327,6 +345,13 @@ type QueryRequest struct {
 	Remote          bool     `protobuf:"varint,5,opt,name=Remote,proto3" json:"Remote,omitempty"`
 	ExcludeRowAttrs bool     `protobuf:"varint,6,opt,name=ExcludeRowAttrs,proto3" json:"ExcludeRowAttrs,omitempty"`
 	ExcludeColumns  bool     `protobuf:"varint,7,opt,name=ExcludeColumns,proto3" json:"ExcludeColumns,omitempty"`
+	Session         string   `protobuf:"bytes,8,opt,name=Session,proto3" json:"Session,omitempty"`
+	StoreAs         string   `protobuf:"bytes,9,opt,name=StoreAs,proto3" json:"StoreAs,omitempty"`
+	Snapshot        bool     `protobuf:"varint,10,opt,name=Snapshot,proto3" json:"Snapshot,omitempty"`
+	Roaring         bool     `protobuf:"varint,11,opt,name=Roaring,proto3" json:"Roaring,omitempty"`
+	AsOf            int64    `protobuf:"varint,12,opt,name=AsOf,proto3" json:"AsOf,omitempty"`
+	Estimate        bool     `protobuf:"varint,13,opt,name=Estimate,proto3" json:"Estimate,omitempty"`
+	MaxStaleness    int64    `protobuf:"varint,14,opt,name=MaxStaleness,proto3" json:"MaxStaleness,omitempty"`
 }
 
 func (m *QueryRequest) Reset()                    { *m = QueryRequest{} }
@@ -376,10 +401,61 @@ func (m *QueryRequest) GetExcludeColumns() bool {
 	return false
 }
 
+func (m *QueryRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetStoreAs() string {
+	if m != nil {
+		return m.StoreAs
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetSnapshot() bool {
+	if m != nil {
+		return m.Snapshot
+	}
+	return false
+}
+
+func (m *QueryRequest) GetRoaring() bool {
+	if m != nil {
+		return m.Roaring
+	}
+	return false
+}
+
+func (m *QueryRequest) GetAsOf() int64 {
+	if m != nil {
+		return m.AsOf
+	}
+	return 0
+}
+
+func (m *QueryRequest) GetEstimate() bool {
+	if m != nil {
+		return m.Estimate
+	}
+	return false
+}
+
+func (m *QueryRequest) GetMaxStaleness() int64 {
+	if m != nil {
+		return m.MaxStaleness
+	}
+	return 0
+}
+
 type QueryResponse struct {
 	Err            string           `protobuf:"bytes,1,opt,name=Err,proto3" json:"Err,omitempty"`
 	Results        []*QueryResult   `protobuf:"bytes,2,rep,name=Results" json:"Results,omitempty"`
 	ColumnAttrSets []*ColumnAttrSet `protobuf:"bytes,3,rep,name=ColumnAttrSets" json:"ColumnAttrSets,omitempty"`
+	Shards         []uint64         `protobuf:"varint,4,rep,packed,name=Shards" json:"Shards,omitempty"`
+	StaleShards    []uint64         `protobuf:"varint,5,rep,packed,name=StaleShards" json:"StaleShards,omitempty"`
 }
 
 func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
@@ -408,6 +484,20 @@ func (m *QueryResponse) GetColumnAttrSets() []*ColumnAttrSet {
 	return nil
 }
 
+func (m *QueryResponse) GetShards() []uint64 {
+	if m != nil {
+		return m.Shards
+	}
+	return nil
+}
+
+func (m *QueryResponse) GetStaleShards() []uint64 {
+	if m != nil {
+		return m.StaleShards
+	}
+	return nil
+}
+
 type QueryResult struct {
 	Type           uint32          `protobuf:"varint,6,opt,name=Type,proto3" json:"Type,omitempty"`
 	Row            *Row            `protobuf:"bytes,1,opt,name=Row" json:"Row,omitempty"`
@@ -689,8 +779,9 @@ func (m *ImportRoaringRequestView) GetData() []byte {
 }
 
 type ImportRoaringRequest struct {
-	Clear bool                        `protobuf:"varint,1,opt,name=Clear,proto3" json:"Clear,omitempty"`
-	Views []*ImportRoaringRequestView `protobuf:"bytes,2,rep,name=views" json:"views,omitempty"`
+	Clear       bool                        `protobuf:"varint,1,opt,name=Clear,proto3" json:"Clear,omitempty"`
+	Views       []*ImportRoaringRequestView `protobuf:"bytes,2,rep,name=views" json:"views,omitempty"`
+	OperationID string                      `protobuf:"bytes,3,opt,name=OperationID,proto3" json:"OperationID,omitempty"`
 }
 
 func (m *ImportRoaringRequest) Reset()                    { *m = ImportRoaringRequest{} }
@@ -712,6 +803,13 @@ func (m *ImportRoaringRequest) GetViews() []*ImportRoaringRequestView {
 	return nil
 }
 
+func (m *ImportRoaringRequest) GetOperationID() string {
+	if m != nil {
+		return m.OperationID
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Row)(nil), "internal.Row")
 	proto.RegisterType((*RowIdentifiers)(nil), "internal.RowIdentifiers")
@@ -791,6 +889,12 @@ func (m *Row) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
+	if len(m.Roaring) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.Roaring)))
+		i += copy(dAtA[i:], m.Roaring)
+	}
 	return i, nil
 }
 
@@ -973,6 +1077,11 @@ func (m *ValCount) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintPublic(dAtA, i, uint64(m.Count))
 	}
+	if m.Scale != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(m.Scale))
+	}
 	return i, nil
 }
 
@@ -1181,6 +1290,58 @@ func (m *QueryRequest) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
+	if len(m.Session) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.Session)))
+		i += copy(dAtA[i:], m.Session)
+	}
+	if len(m.StoreAs) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.StoreAs)))
+		i += copy(dAtA[i:], m.StoreAs)
+	}
+	if m.Snapshot {
+		dAtA[i] = 0x50
+		i++
+		if m.Snapshot {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Roaring {
+		dAtA[i] = 0x58
+		i++
+		if m.Roaring {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.AsOf != 0 {
+		dAtA[i] = 0x60
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(m.AsOf))
+	}
+	if m.Estimate {
+		dAtA[i] = 0x68
+		i++
+		if m.Estimate {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MaxStaleness != 0 {
+		dAtA[i] = 0x70
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(m.MaxStaleness))
+	}
 	return i, nil
 }
 
@@ -1229,6 +1390,40 @@ func (m *QueryResponse) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.Shards) > 0 {
+		dAtA25 := make([]byte, len(m.Shards)*10)
+		var j24 int
+		for _, num := range m.Shards {
+			for num >= 1<<7 {
+				dAtA25[j24] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j24++
+			}
+			dAtA25[j24] = uint8(num)
+			j24++
+		}
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(j24))
+		i += copy(dAtA[i:], dAtA25[:j24])
+	}
+	if len(m.StaleShards) > 0 {
+		dAtA27 := make([]byte, len(m.StaleShards)*10)
+		var j26 int
+		for _, num := range m.StaleShards {
+			for num >= 1<<7 {
+				dAtA27[j26] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j26++
+			}
+			dAtA27[j26] = uint8(num)
+			j26++
+		}
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(j26))
+		i += copy(dAtA[i:], dAtA27[:j26])
+	}
 	return i, nil
 }
 
@@ -1690,6 +1885,12 @@ func (m *ImportRoaringRequest) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.OperationID) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.OperationID)))
+		i += copy(dAtA[i:], m.OperationID)
+	}
 	return i, nil
 }
 
@@ -1724,6 +1925,10 @@ func (m *Row) Size() (n int) {
 			n += 1 + l + sovPublic(uint64(l))
 		}
 	}
+	l = len(m.Roaring)
+	if l > 0 {
+		n += 1 + l + sovPublic(uint64(l))
+	}
 	return n
 }
 
@@ -1803,6 +2008,9 @@ func (m *ValCount) Size() (n int) {
 	if m.Count != 0 {
 		n += 1 + sovPublic(uint64(m.Count))
 	}
+	if m.Scale != 0 {
+		n += 1 + sovPublic(uint64(m.Scale))
+	}
 	return n
 }
 
@@ -1889,6 +2097,29 @@ func (m *QueryRequest) Size() (n int) {
 	if m.ExcludeColumns {
 		n += 2
 	}
+	l = len(m.Session)
+	if l > 0 {
+		n += 1 + l + sovPublic(uint64(l))
+	}
+	l = len(m.StoreAs)
+	if l > 0 {
+		n += 1 + l + sovPublic(uint64(l))
+	}
+	if m.Snapshot {
+		n += 2
+	}
+	if m.Roaring {
+		n += 2
+	}
+	if m.AsOf != 0 {
+		n += 1 + sovPublic(uint64(m.AsOf))
+	}
+	if m.Estimate {
+		n += 2
+	}
+	if m.MaxStaleness != 0 {
+		n += 1 + sovPublic(uint64(m.MaxStaleness))
+	}
 	return n
 }
 
@@ -1911,6 +2142,20 @@ func (m *QueryResponse) Size() (n int) {
 			n += 1 + l + sovPublic(uint64(l))
 		}
 	}
+	if len(m.Shards) > 0 {
+		l = 0
+		for _, e := range m.Shards {
+			l += sovPublic(uint64(e))
+		}
+		n += 1 + sovPublic(uint64(l)) + l
+	}
+	if len(m.StaleShards) > 0 {
+		l = 0
+		for _, e := range m.StaleShards {
+			l += sovPublic(uint64(e))
+		}
+		n += 1 + sovPublic(uint64(l)) + l
+	}
 	return n
 }
 
@@ -2106,6 +2351,10 @@ func (m *ImportRoaringRequest) Size() (n int) {
 			n += 1 + l + sovPublic(uint64(l))
 		}
 	}
+	l = len(m.OperationID)
+	if l > 0 {
+		n += 1 + l + sovPublic(uint64(l))
+	}
 	return n
 }
 
@@ -2273,6 +2522,37 @@ func (m *Row) Unmarshal(dAtA []byte) error {
 			}
 			m.Keys = append(m.Keys, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Roaring", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Roaring = append(m.Roaring[:0], dAtA[iNdEx:postIndex]...)
+			if m.Roaring == nil {
+				m.Roaring = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPublic(dAtA[iNdEx:])
@@ -2846,6 +3126,25 @@ func (m *ValCount) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scale", wireType)
+			}
+			m.Scale = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Scale |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPublic(dAtA[iNdEx:])
@@ -3454,6 +3753,162 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.ExcludeColumns = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Session", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Session = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreAs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StoreAs = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Snapshot", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Snapshot = bool(v != 0)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Roaring", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Roaring = bool(v != 0)
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AsOf", wireType)
+			}
+			m.AsOf = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AsOf |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Estimate", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Estimate = bool(v != 0)
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxStaleness", wireType)
+			}
+			m.MaxStaleness = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxStaleness |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPublic(dAtA[iNdEx:])
@@ -3595,6 +4050,130 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPublic
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Shards = append(m.Shards, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPublic
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthPublic
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPublic
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Shards = append(m.Shards, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shards", wireType)
+			}
+		case 5:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPublic
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.StaleShards = append(m.StaleShards, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPublic
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthPublic
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPublic
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.StaleShards = append(m.StaleShards, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field StaleShards", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPublic(dAtA[iNdEx:])
@@ -5037,6 +5616,35 @@ func (m *ImportRoaringRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPublic(dAtA[iNdEx:])
@@ -5166,60 +5774,67 @@ var (
 func init() { proto.RegisterFile("public.proto", fileDescriptorPublic) }
 
 var fileDescriptorPublic = []byte{
-	// 880 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xcd, 0x8e, 0x1b, 0x45,
-	0x10, 0xa6, 0x3d, 0x63, 0x7b, 0x5c, 0x5e, 0x9b, 0xa8, 0xe5, 0x84, 0x11, 0x8a, 0x8c, 0x35, 0x42,
-	0x68, 0xb8, 0x6c, 0x24, 0x23, 0xa1, 0x9c, 0xf8, 0xd9, 0x78, 0x83, 0xac, 0xc0, 0x0a, 0x6a, 0x57,
-	0x46, 0x1c, 0x3b, 0x71, 0x27, 0x19, 0x69, 0x3c, 0x6d, 0x66, 0x7a, 0x70, 0xf6, 0x39, 0xb8, 0xf0,
-	0x08, 0x1c, 0x78, 0x90, 0x1c, 0x11, 0x4f, 0x00, 0xcb, 0x8b, 0xa0, 0xae, 0x9e, 0xde, 0x1e, 0x7b,
-	0x97, 0x08, 0xa1, 0xdc, 0xea, 0xab, 0xea, 0xaa, 0xa9, 0xaf, 0xfe, 0x6c, 0x38, 0xda, 0xd6, 0x4f,
-	0xf3, 0xec, 0xd9, 0xf1, 0xb6, 0x54, 0x5a, 0xf1, 0x28, 0x2b, 0xb4, 0x2c, 0x0b, 0x91, 0x27, 0x3f,
-	0x40, 0x80, 0x6a, 0xc7, 0x63, 0xe8, 0x3f, 0x52, 0x79, 0xbd, 0x29, 0xaa, 0x98, 0xcd, 0x82, 0x34,
-	0x44, 0x07, 0xf9, 0x87, 0xd0, 0xfd, 0x52, 0xeb, 0xb2, 0x8a, 0x3b, 0xb3, 0x20, 0x1d, 0xce, 0xc7,
-	0xc7, 0xce, 0xf5, 0xd8, 0xa8, 0xd1, 0x1a, 0x39, 0x87, 0xf0, 0x89, 0xbc, 0xac, 0xe2, 0x60, 0x16,
-	0xa4, 0x03, 0x24, 0x39, 0x79, 0x08, 0x63, 0x54, 0xbb, 0xe5, 0x5a, 0x16, 0x3a, 0x7b, 0x9e, 0x49,
-	0xfb, 0x0a, 0xd5, 0xce, 0x7d, 0x82, 0xe4, 0x6b, 0xcf, 0x4e, 0xcb, 0xf3, 0x33, 0x08, 0xbf, 0x15,
-	0x59, 0xc9, 0xc7, 0xd0, 0x59, 0x2e, 0x62, 0x36, 0x63, 0x69, 0x88, 0x9d, 0xe5, 0x82, 0x4f, 0xa0,
-	0xfb, 0x48, 0xd5, 0x85, 0x8e, 0x3b, 0xa4, 0xb2, 0x80, 0xdf, 0x81, 0xe0, 0x89, 0xbc, 0x8c, 0x83,
-	0x19, 0x4b, 0x07, 0x68, 0xc4, 0xe4, 0x0c, 0xa2, 0xc7, 0x99, 0xcc, 0xd7, 0x86, 0xd9, 0x04, 0xba,
-	0x24, 0x53, 0x98, 0x01, 0x5a, 0x60, 0xb4, 0x26, 0xb7, 0x85, 0x8b, 0x44, 0x80, 0xdf, 0x83, 0x1e,
-	0xaa, 0x9d, 0x0f, 0xd6, 0xa0, 0xe4, 0x6b, 0x80, 0xaf, 0x4a, 0x55, 0x6f, 0xed, 0xf7, 0x52, 0xe8,
-	0x12, 0x22, 0x1a, 0xc3, 0x39, 0xf7, 0x15, 0x71, 0x1f, 0x45, 0xfb, 0xe0, 0xf6, 0x7c, 0x93, 0x39,
-	0x44, 0x2b, 0x91, 0x5f, 0xe7, 0xbe, 0x12, 0x39, 0xe5, 0x16, 0xa0, 0x11, 0xf7, 0x7d, 0x02, 0xe7,
-	0xf3, 0x3d, 0x8c, 0x6c, 0x43, 0x4c, 0xb9, 0xcf, 0xa5, 0xbe, 0x51, 0x9a, 0xff, 0xd6, 0xa6, 0x9b,
-	0xa5, 0xfa, 0x95, 0x41, 0x68, 0x6c, 0xce, 0xc4, 0xae, 0x4d, 0xa6, 0x33, 0x17, 0x97, 0x5b, 0xd9,
-	0x24, 0x4f, 0x32, 0x9f, 0xc1, 0xf0, 0x5c, 0x97, 0x59, 0xf1, 0x62, 0x25, 0xf2, 0x5a, 0x36, 0x81,
-	0xda, 0x2a, 0xfe, 0x3e, 0x44, 0xcb, 0x42, 0x5b, 0x73, 0x48, 0x14, 0xae, 0x31, 0xbf, 0x0f, 0x83,
-	0x13, 0xa5, 0x72, 0x6b, 0xec, 0xce, 0x58, 0x1a, 0xa1, 0x57, 0xf0, 0x29, 0xc0, 0xe3, 0x5c, 0x89,
-	0xc6, 0xb7, 0x37, 0x63, 0x29, 0xc3, 0x96, 0x26, 0x79, 0x00, 0x7d, 0x93, 0xe9, 0x37, 0x62, 0xeb,
-	0xd9, 0xb2, 0x37, 0xb0, 0x4d, 0x5e, 0x33, 0x38, 0xfa, 0xae, 0x96, 0xe5, 0x25, 0xca, 0x1f, 0x6b,
-	0x59, 0x69, 0x53, 0x5b, 0xc2, 0x6e, 0x16, 0x08, 0x98, 0xae, 0x9f, 0xbf, 0x14, 0xe5, 0xda, 0xd6,
-	0x2e, 0xc4, 0x06, 0x19, 0xae, 0xbe, 0xe6, 0x15, 0x71, 0x8d, 0xb0, 0xad, 0xa2, 0x79, 0x91, 0x1b,
-	0xa5, 0x1d, 0x99, 0x06, 0xf1, 0x14, 0xde, 0x3d, 0x7d, 0xf5, 0x2c, 0xaf, 0xd7, 0x12, 0xd5, 0xce,
-	0x7a, 0xf7, 0xe8, 0xc1, 0xa1, 0x9a, 0x7f, 0x04, 0xe3, 0x46, 0xe5, 0xd6, 0xaf, 0x4f, 0x0f, 0x0f,
-	0xb4, 0xc9, 0xcf, 0x0c, 0x46, 0x0d, 0x95, 0x6a, 0xab, 0x8a, 0x4a, 0x9a, 0x7e, 0x9d, 0x96, 0xa5,
-	0xeb, 0xd7, 0x69, 0x59, 0xf2, 0x07, 0xd0, 0x47, 0x59, 0xd5, 0xb9, 0x76, 0x43, 0x70, 0xd7, 0x97,
-	0xc5, 0xf9, 0xd6, 0xb9, 0x46, 0xf7, 0x8a, 0x7f, 0x0e, 0xe3, 0xbd, 0xa1, 0xb2, 0xeb, 0x3b, 0x9c,
-	0xbf, 0xe7, 0xfd, 0xf6, 0xec, 0x78, 0xf0, 0x3c, 0xf9, 0xa3, 0x03, 0xc3, 0x56, 0x64, 0xfe, 0x01,
-	0x1d, 0x13, 0xca, 0x69, 0x38, 0x1f, 0xf9, 0x28, 0x66, 0x25, 0xe8, 0xcc, 0x1c, 0x01, 0x3b, 0x6b,
-	0xe6, 0x89, 0x9d, 0x99, 0x2e, 0x9a, 0x35, 0x77, 0x9f, 0x6d, 0x75, 0xd1, 0xa8, 0xd1, 0x1a, 0xe9,
-	0x34, 0xbd, 0x14, 0xc5, 0x0b, 0xb9, 0xa6, 0x79, 0x8a, 0xd0, 0x41, 0x7e, 0xec, 0x17, 0x89, 0x1a,
-	0xb0, 0xb7, 0x8b, 0xce, 0x82, 0x7e, 0xd9, 0xdc, 0x40, 0x9b, 0x5e, 0x8c, 0x9a, 0x81, 0xb6, 0x2b,
-	0xbf, 0x5c, 0x98, 0xc2, 0x53, 0xf3, 0x2d, 0xe2, 0x9f, 0xc2, 0xd0, 0xaf, 0x7c, 0x15, 0x47, 0x94,
-	0xe1, 0xc4, 0x87, 0xf7, 0x46, 0x6c, 0x3f, 0xe4, 0x5f, 0x1c, 0x1e, 0xbd, 0x78, 0x40, 0x99, 0xc5,
-	0x7b, 0xd5, 0x68, 0xd9, 0xf1, 0xe0, 0x7d, 0xf2, 0x17, 0x83, 0xd1, 0x72, 0xb3, 0x55, 0xa5, 0x6e,
-	0x8d, 0xed, 0xb2, 0x58, 0xcb, 0x57, 0x6e, 0x6c, 0x09, 0xf8, 0xc3, 0xd6, 0x39, 0x38, 0x6c, 0x34,
-	0xbe, 0x34, 0xae, 0x21, 0x5a, 0xd0, 0x62, 0x19, 0xee, 0xb1, 0xbc, 0x0f, 0x03, 0xdb, 0x52, 0x63,
-	0xea, 0x92, 0xc9, 0x2b, 0xcc, 0x42, 0x5e, 0x64, 0x1b, 0x59, 0x69, 0xb1, 0xd9, 0x9a, 0x09, 0x0e,
-	0xd2, 0x00, 0x5b, 0x1a, 0xd3, 0x19, 0x7b, 0x20, 0x6d, 0xf1, 0x06, 0xe8, 0xa0, 0xf1, 0xb4, 0x61,
-	0xc8, 0x18, 0x91, 0xb1, 0xa5, 0x49, 0x7e, 0x63, 0xc0, 0x2d, 0x47, 0x5a, 0xed, 0xb7, 0x47, 0xf4,
-	0xcd, 0x84, 0xee, 0x41, 0x8f, 0xbe, 0xe7, 0xc8, 0x34, 0xe8, 0x20, 0xdd, 0xfe, 0x8d, 0x74, 0x57,
-	0x30, 0xb9, 0x28, 0x45, 0x51, 0xe5, 0x42, 0x4b, 0xa3, 0xf8, 0x3f, 0xf9, 0xde, 0xf6, 0x0b, 0xf9,
-	0x31, 0xdc, 0x3d, 0x88, 0xeb, 0x97, 0xdb, 0x10, 0x08, 0x88, 0x80, 0x11, 0x93, 0x13, 0x88, 0x9b,
-	0xa1, 0x50, 0xc2, 0x1c, 0xdb, 0x26, 0x85, 0x55, 0x26, 0x77, 0x26, 0xf4, 0x99, 0xd8, 0xc8, 0x26,
-	0x0b, 0x92, 0x8d, 0x6e, 0x21, 0xb4, 0xa0, 0x1c, 0x8e, 0x90, 0xe4, 0xe4, 0x39, 0x4c, 0x6e, 0x8b,
-	0x41, 0x3f, 0x39, 0xb9, 0x14, 0xf6, 0x98, 0x44, 0x68, 0x01, 0x7f, 0x08, 0xdd, 0x9f, 0x32, 0xb9,
-	0x73, 0xc7, 0x24, 0xf1, 0x03, 0xfc, 0x6f, 0x89, 0xa0, 0x75, 0x38, 0xb9, 0xf3, 0xfa, 0x6a, 0xca,
-	0x7e, 0xbf, 0x9a, 0xb2, 0x3f, 0xaf, 0xa6, 0xec, 0x97, 0xbf, 0xa7, 0xef, 0x3c, 0xed, 0xd1, 0xdf,
-	0x8e, 0x4f, 0xfe, 0x09, 0x00, 0x00, 0xff, 0xff, 0x9f, 0x87, 0xba, 0x9b, 0x86, 0x08, 0x00, 0x00,
+	// 990 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xdd, 0xae, 0xdb, 0x44,
+	0x10, 0x96, 0x63, 0x27, 0x71, 0x26, 0x3f, 0x54, 0xab, 0xb4, 0x58, 0xa8, 0x82, 0xc8, 0x42, 0xc8,
+	0xdc, 0x9c, 0x4a, 0x41, 0x42, 0xbd, 0x02, 0xda, 0xe6, 0x14, 0xac, 0xd2, 0x53, 0x98, 0x1c, 0x85,
+	0xeb, 0xed, 0xc9, 0xb6, 0xc7, 0x92, 0xe3, 0x35, 0xde, 0x35, 0x39, 0xe7, 0x05, 0x10, 0x8f, 0xc2,
+	0x05, 0xaf, 0xc2, 0x0d, 0x6f, 0xc0, 0x9b, 0xa0, 0x9d, 0xf5, 0xc6, 0x4e, 0x68, 0xab, 0x0a, 0x71,
+	0x37, 0xdf, 0xcc, 0xec, 0xec, 0x37, 0x9e, 0x9f, 0x35, 0x4c, 0xca, 0xfa, 0x65, 0x9e, 0x5d, 0x9d,
+	0x95, 0x95, 0xd4, 0x92, 0x85, 0x59, 0xa1, 0x45, 0x55, 0xf0, 0x3c, 0x56, 0xe0, 0xa3, 0xdc, 0xb3,
+	0x08, 0x86, 0x4f, 0x64, 0x5e, 0xef, 0x0a, 0x15, 0x79, 0x0b, 0x3f, 0x09, 0xd0, 0x41, 0xc6, 0x20,
+	0x78, 0x26, 0x6e, 0x55, 0xe4, 0x2f, 0xfc, 0x64, 0x84, 0x24, 0xb3, 0x4f, 0xa1, 0xff, 0x48, 0xeb,
+	0x4a, 0x45, 0xbd, 0x85, 0x9f, 0x8c, 0x97, 0xb3, 0x33, 0x17, 0xee, 0xcc, 0xa8, 0xd1, 0x1a, 0x4d,
+	0x4c, 0x94, 0xbc, 0xca, 0x8a, 0xd7, 0x51, 0xb0, 0xf0, 0x92, 0x09, 0x3a, 0x18, 0x3f, 0x84, 0x19,
+	0xca, 0x7d, 0xba, 0x15, 0x85, 0xce, 0x5e, 0x65, 0xa2, 0xa2, 0x5b, 0x50, 0xee, 0xdd, 0xe5, 0x24,
+	0x1f, 0x6e, 0xee, 0xb5, 0x37, 0xc7, 0x5f, 0x41, 0xf0, 0x03, 0xcf, 0x2a, 0x36, 0x83, 0x5e, 0xba,
+	0x8a, 0xbc, 0x85, 0x97, 0x04, 0xd8, 0x4b, 0x57, 0xec, 0x0e, 0xf8, 0xcf, 0xc4, 0x6d, 0xe4, 0x2f,
+	0xbc, 0x64, 0x84, 0x46, 0x64, 0x73, 0xe8, 0x3f, 0x91, 0x75, 0xa1, 0xa3, 0x1e, 0x39, 0x59, 0x10,
+	0x5f, 0x40, 0xf8, 0x34, 0x13, 0xf9, 0xd6, 0xe4, 0x3c, 0x87, 0x3e, 0xc9, 0x14, 0x66, 0x84, 0x16,
+	0x18, 0xad, 0xe1, 0xb6, 0x72, 0xe7, 0x08, 0xb0, 0x7b, 0x30, 0x40, 0xb9, 0x6f, 0xaf, 0x68, 0x50,
+	0xfc, 0x3d, 0xc0, 0xb7, 0x95, 0xac, 0x4b, 0x8a, 0xce, 0x12, 0xe8, 0x13, 0xa2, 0x34, 0xc6, 0x4b,
+	0xd6, 0x7e, 0x17, 0x77, 0x29, 0x5a, 0x87, 0xb7, 0xb0, 0xfb, 0x0e, 0xc2, 0x0d, 0xcf, 0x6d, 0xac,
+	0x3b, 0xe0, 0x6f, 0x78, 0x4e, 0xdc, 0x7c, 0x34, 0xe2, 0xf1, 0x19, 0xbf, 0x39, 0x63, 0xb4, 0xeb,
+	0x2b, 0x9e, 0x0b, 0x22, 0xe6, 0xa3, 0x05, 0xf1, 0x4f, 0x30, 0xb5, 0x05, 0x34, 0xa5, 0x58, 0x0b,
+	0xfd, 0x1e, 0x1f, 0xec, 0xbd, 0x8a, 0x1a, 0xff, 0xee, 0x41, 0x60, 0x24, 0x17, 0xc0, 0x6b, 0x03,
+	0x30, 0x08, 0x2e, 0x6f, 0x4b, 0xd1, 0xa4, 0x44, 0x32, 0x5b, 0xc0, 0x78, 0xad, 0x4d, 0xcd, 0x37,
+	0x3c, 0xaf, 0x45, 0x73, 0x5d, 0x57, 0xc5, 0x3e, 0x82, 0x30, 0x2d, 0xb4, 0x35, 0x07, 0x94, 0xc2,
+	0x01, 0xb3, 0xfb, 0x30, 0x7a, 0x2c, 0x65, 0x6e, 0x8d, 0xfd, 0x85, 0x97, 0x84, 0xd8, 0x2a, 0xd8,
+	0xc7, 0x00, 0x4f, 0x73, 0xc9, 0x9b, 0xb3, 0x83, 0x85, 0x97, 0x78, 0xd8, 0xd1, 0xc4, 0x0f, 0x60,
+	0x68, 0x98, 0x3e, 0xe7, 0x65, 0x9b, 0x9b, 0xf7, 0xae, 0xdc, 0x7e, 0xf5, 0x61, 0xf2, 0x63, 0x2d,
+	0xaa, 0x5b, 0x14, 0x3f, 0xd7, 0x42, 0xd1, 0xb7, 0x25, 0xec, 0x3a, 0x84, 0x80, 0xe9, 0x85, 0xf5,
+	0x35, 0xaf, 0xb6, 0xf6, 0x4b, 0x05, 0xd8, 0x20, 0x93, 0x6b, 0xfb, 0xcd, 0x15, 0xe5, 0x1a, 0x62,
+	0x57, 0x65, 0x4e, 0xa2, 0xd8, 0x49, 0xed, 0x92, 0x69, 0x10, 0x4b, 0xe0, 0x83, 0xf3, 0x9b, 0xab,
+	0xbc, 0xde, 0x0a, 0x94, 0x7b, 0x7b, 0x7a, 0x40, 0x0e, 0xa7, 0x6a, 0xf6, 0x19, 0xcc, 0x1a, 0x95,
+	0x1b, 0xd7, 0x21, 0x39, 0x9e, 0x68, 0xcd, 0xec, 0xad, 0x85, 0x52, 0x99, 0x2c, 0xa2, 0x90, 0xb8,
+	0x3b, 0x48, 0x16, 0x2d, 0x2b, 0xf1, 0x48, 0x45, 0xa3, 0xc6, 0x62, 0xa1, 0xa9, 0xc4, 0xba, 0xe0,
+	0xa5, 0xba, 0x96, 0x3a, 0x02, 0x8a, 0x7a, 0xc0, 0xdd, 0x59, 0x1e, 0x93, 0xc9, 0x41, 0x53, 0xf5,
+	0x47, 0xea, 0xc5, 0xab, 0x68, 0x42, 0xb5, 0x23, 0xd9, 0x44, 0x3a, 0x57, 0x3a, 0xdb, 0x71, 0x2d,
+	0xa2, 0xa9, 0x8d, 0xe4, 0x30, 0x8b, 0x61, 0xf2, 0x9c, 0xdf, 0xac, 0x35, 0xcf, 0x45, 0x21, 0x94,
+	0x8a, 0x66, 0x74, 0xee, 0x48, 0x17, 0xff, 0xe9, 0xc1, 0xb4, 0x29, 0x84, 0x2a, 0x65, 0xa1, 0x84,
+	0xe9, 0xb6, 0xf3, 0xaa, 0x72, 0xdd, 0x76, 0x5e, 0x55, 0xec, 0x01, 0x0c, 0x51, 0xa8, 0x3a, 0xd7,
+	0xae, 0x61, 0xef, 0xb6, 0x45, 0x75, 0x67, 0xeb, 0x5c, 0xa3, 0xf3, 0x62, 0x5f, 0xc3, 0xec, 0x68,
+	0x24, 0xec, 0x4a, 0x1b, 0x2f, 0x3f, 0x6c, 0xcf, 0x1d, 0xd9, 0xf1, 0xc4, 0xbd, 0x53, 0xf7, 0xe0,
+	0xb4, 0xee, 0x44, 0xbd, 0x31, 0xf6, 0xc9, 0xd8, 0x55, 0xc5, 0x7f, 0xf5, 0x60, 0xdc, 0xe1, 0x74,
+	0x98, 0x14, 0x53, 0xe4, 0x69, 0x33, 0x29, 0x9f, 0xd0, 0x22, 0xa6, 0x0c, 0xc7, 0xcb, 0x69, 0xcb,
+	0xc9, 0x2c, 0x0d, 0x63, 0x61, 0x13, 0xf0, 0x2e, 0x9a, 0xd9, 0xf2, 0x2e, 0x4c, 0x47, 0x9b, 0x45,
+	0xe8, 0x92, 0xe8, 0x74, 0xb4, 0x51, 0xa3, 0x35, 0xd2, 0x5a, 0xbf, 0xe6, 0xc5, 0x6b, 0xb1, 0xa5,
+	0xd9, 0x0a, 0xd1, 0x41, 0x76, 0xd6, 0xae, 0x1a, 0x6a, 0xc6, 0xa3, 0x6d, 0xe5, 0x2c, 0x78, 0xf0,
+	0x69, 0x16, 0x60, 0xba, 0x32, 0x0d, 0x47, 0xc9, 0x5b, 0xc4, 0xbe, 0x84, 0x71, 0xbb, 0x00, 0x55,
+	0x14, 0x12, 0x9b, 0x79, 0x1b, 0xaa, 0x35, 0x62, 0xd7, 0x91, 0x7d, 0x73, 0xfa, 0x04, 0x50, 0x37,
+	0x8e, 0x97, 0xd1, 0x51, 0xe6, 0x1d, 0x3b, 0x9e, 0xf8, 0xc7, 0x7f, 0x7b, 0x30, 0x4d, 0x77, 0xa5,
+	0xac, 0x74, 0x67, 0x5c, 0xd3, 0x62, 0x2b, 0x6e, 0xdc, 0xb8, 0x12, 0x68, 0xd7, 0x7c, 0xef, 0x64,
+	0xcd, 0x53, 0x71, 0x68, 0x4c, 0x03, 0xb4, 0xa0, 0x93, 0x65, 0x70, 0x94, 0xe5, 0x7d, 0x18, 0xd9,
+	0x66, 0x48, 0x57, 0xae, 0xc0, 0xad, 0xc2, 0x0e, 0xc7, 0x9e, 0xde, 0xaa, 0x21, 0xbd, 0x55, 0x0e,
+	0x9a, 0x15, 0x65, 0xdd, 0xc8, 0x18, 0x92, 0xb1, 0xa3, 0x31, 0xf6, 0xcb, 0x6c, 0x27, 0x94, 0xe6,
+	0xbb, 0xd2, 0xcc, 0xbc, 0x9f, 0xf8, 0xd8, 0xd1, 0xc4, 0x7f, 0x78, 0xc0, 0x6c, 0x8e, 0xb4, 0xd2,
+	0xfe, 0xbf, 0x44, 0xdf, 0x9d, 0xd0, 0x31, 0xed, 0xe1, 0xbf, 0x68, 0xdf, 0x83, 0x01, 0xf1, 0x71,
+	0x94, 0x1b, 0x14, 0x6f, 0x60, 0x7e, 0x59, 0xf1, 0x42, 0xe5, 0x5c, 0x0b, 0xe3, 0xf8, 0x5f, 0xf8,
+	0xbe, 0xe1, 0x7f, 0x23, 0xfe, 0x1c, 0xee, 0x9e, 0xc4, 0x6d, 0xd7, 0x42, 0xba, 0xb2, 0xbe, 0x01,
+	0x1a, 0x31, 0x7e, 0x0c, 0x51, 0xd3, 0x14, 0x76, 0x3f, 0x35, 0x14, 0x36, 0x99, 0xd8, 0x9b, 0xd0,
+	0x17, 0x7c, 0x27, 0x1a, 0x16, 0x24, 0x1b, 0xdd, 0x8a, 0x6b, 0x4e, 0x1c, 0x26, 0x48, 0x72, 0xfc,
+	0x9b, 0x07, 0xf3, 0x37, 0x05, 0xa1, 0x17, 0x38, 0x17, 0xdc, 0xee, 0xa1, 0x10, 0x2d, 0x60, 0x0f,
+	0xa1, 0xff, 0x4b, 0x26, 0xf6, 0x6e, 0x0f, 0xc5, 0x6d, 0x07, 0xbf, 0x8d, 0x09, 0xda, 0x03, 0x66,
+	0x73, 0xbc, 0x28, 0x45, 0xc5, 0x75, 0x26, 0x8b, 0x74, 0xe5, 0x5e, 0xc7, 0x8e, 0xea, 0xe5, 0x80,
+	0xfe, 0xd7, 0xbe, 0xf8, 0x67, 0x00, 0xd7, 0xdd, 0x1f, 0x00, 0xbf, 0x09, 0x00, 0x00,
 }
@@ -173,22 +173,23 @@ func (itr *sliceIterator) Next() (rowID, columnID uint64, eof bool) {
 
 // roaringIterator converts a roaring.Iterator to output column/row pairs.
 type roaringIterator struct {
-	itr *roaring.Iterator
+	itr        *roaring.Iterator
+	shardWidth uint64
 }
 
 // newRoaringIterator returns a new iterator wrapping itr.
-func newRoaringIterator(itr *roaring.Iterator) *roaringIterator {
-	return &roaringIterator{itr: itr}
+func newRoaringIterator(itr *roaring.Iterator, shardWidth uint64) *roaringIterator {
+	return &roaringIterator{itr: itr, shardWidth: shardWidth}
 }
 
 // Seek moves the cursor to a pair matching bseek/pseek.
 // If the pair is not found then it moves to the next pair.
 func (itr *roaringIterator) Seek(bseek, pseek uint64) {
-	itr.itr.Seek((bseek * ShardWidth) + pseek)
+	itr.itr.Seek((bseek * itr.shardWidth) + pseek)
 }
 
 // Next returns the next column/row ID pair.
 func (itr *roaringIterator) Next() (rowID, columnID uint64, eof bool) {
 	v, eof := itr.itr.Next()
-	return v / ShardWidth, v % ShardWidth, eof
+	return v / itr.shardWidth, v % itr.shardWidth, eof
 }
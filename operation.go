@@ -0,0 +1,133 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"time"
+)
+
+const (
+	// defaultOperationIDMax is the number of operation IDs recorded by each
+	// fragment.
+	defaultOperationIDMax = 1000
+
+	// defaultOperationIDTTL is how long an operation ID is recorded after the
+	// operation was applied.
+	defaultOperationIDTTL = 10 * time.Minute
+)
+
+// operationIDOptions bounds the operation IDs recorded to make retried
+// writes idempotent.
+type operationIDOptions struct {
+	// Maximum number of IDs recorded. Zero disables deduplication.
+	max int
+
+	// How long an ID is recorded. Zero keeps IDs until they are evicted
+	// by newer ones.
+	ttl time.Duration
+}
+
+// defaultOperationIDOptions returns the default operationIDOptions.
+func defaultOperationIDOptions() operationIDOptions {
+	return operationIDOptions{
+		max: defaultOperationIDMax,
+		ttl: defaultOperationIDTTL,
+	}
+}
+
+// operationIDs records the client-supplied IDs of recently applied write
+// operations so that an operation which is retried after it was applied,
+// for example because the response timed out, is not applied twice. IDs
+// are only held in memory, so deduplication is best-effort across restarts.
+//
+// operationIDs is not safe for concurrent use.
+type operationIDs struct {
+	opt operationIDOptions
+
+	// Times at which IDs were applied, and the IDs in the order they were
+	// applied.
+	applied map[string]time.Time
+	order   []string
+
+	now func() time.Time
+}
+
+// newOperationIDs returns a new instance of operationIDs.
+func newOperationIDs(opt operationIDOptions) *operationIDs {
+	return &operationIDs{
+		opt: opt,
+		now: time.Now,
+	}
+}
+
+// contains returns true if the operation with the given ID was applied.
+func (o *operationIDs) contains(id string) bool {
+	o.expire()
+	_, ok := o.applied[id]
+	return ok
+}
+
+// add records that the operation with the given ID was applied, evicting the
+// oldest ID if the maximum is exceeded.
+func (o *operationIDs) add(id string) {
+	if o.opt.max <= 0 {
+		return
+	} else if o.contains(id) {
+		return
+	}
+
+	if o.applied == nil {
+		o.applied = make(map[string]time.Time)
+	}
+	o.applied[id] = o.now()
+	o.order = append(o.order, id)
+	for len(o.order) > o.opt.max {
+		o.evict()
+	}
+}
+
+// remove forgets the operation with the given ID, which was reverted.
+func (o *operationIDs) remove(id string) {
+	if _, ok := o.applied[id]; !ok {
+		return
+	}
+	delete(o.applied, id)
+	for i := range o.order {
+		if o.order[i] == id {
+			o.order = append(o.order[:i], o.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// expire forgets IDs which were applied longer than the TTL ago.
+func (o *operationIDs) expire() {
+	if o.opt.ttl <= 0 {
+		return
+	}
+	cutoff := o.now().Add(-o.opt.ttl)
+	for len(o.order) > 0 && o.applied[o.order[0]].Before(cutoff) {
+		o.evict()
+	}
+}
+
+// evict forgets the oldest ID.
+func (o *operationIDs) evict() {
+	delete(o.applied, o.order[0])
+	o.order = o.order[1:]
+	if len(o.order) == 0 {
+		o.order = nil
+	}
+}
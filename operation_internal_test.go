@@ -0,0 +1,119 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOperationIDs(t *testing.T) {
+	t.Run("Max", func(t *testing.T) {
+		o := newOperationIDs(operationIDOptions{max: 2})
+		o.add("a")
+		o.add("b")
+		o.add("a")
+		if !o.contains("a") || !o.contains("b") {
+			t.Fatal("expected a and b")
+		}
+
+		// The oldest ID is evicted.
+		o.add("c")
+		if o.contains("a") {
+			t.Fatal("expected a to be evicted")
+		} else if !o.contains("b") || !o.contains("c") {
+			t.Fatal("expected b and c")
+		}
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		now := time.Now()
+		o := newOperationIDs(operationIDOptions{max: 10, ttl: time.Minute})
+		o.now = func() time.Time { return now }
+
+		o.add("a")
+		now = now.Add(30 * time.Second)
+		o.add("b")
+		now = now.Add(40 * time.Second)
+		if o.contains("a") {
+			t.Fatal("expected a to expire")
+		} else if !o.contains("b") {
+			t.Fatal("expected b")
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		o := newOperationIDs(operationIDOptions{max: 10})
+		o.add("a")
+		o.add("b")
+		o.remove("a")
+		o.remove("x")
+		if o.contains("a") {
+			t.Fatal("expected a to be removed")
+		} else if !o.contains("b") {
+			t.Fatal("expected b")
+		} else if len(o.order) != 1 {
+			t.Fatalf("unexpected order: %v", o.order)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		o := newOperationIDs(operationIDOptions{})
+		o.add("a")
+		if o.contains("a") {
+			t.Fatal("expected deduplication to be disabled")
+		}
+	})
+}
+
+func TestFragment_ImportOnce(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	calls := 0
+	fn := func() error {
+		calls++
+		return f.bulkImport([]uint64{1}, []uint64{2}, &ImportOptions{})
+	}
+	for i := 0; i < 2; i++ {
+		if err := f.importOnce("a", fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("unexpected calls: %d", calls)
+	}
+
+	// Failed imports are not recorded.
+	errImport := errors.New("import failed")
+	if err := f.importOnce("b", func() error { return errImport }); err != errImport {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := f.importOnce("b", fn); err != nil {
+		t.Fatal(err)
+	} else if calls != 2 {
+		t.Fatalf("unexpected calls: %d", calls)
+	}
+
+	// Imports without an operation ID are always applied.
+	for i := 0; i < 2; i++ {
+		if err := f.importOnce("", fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 4 {
+		t.Fatalf("unexpected calls: %d", calls)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pilosa/pilosa/v2/pql"
+)
+
+// optimizeQuery validates the calls of a query and rewrites them into
+// equivalent calls which are cheaper to execute:
+//
+//   - nested Union, Intersect and Xor calls are flattened,
+//   - set operations with a single argument are replaced by the argument,
+//   - empty operands are folded: Union() is the empty row, so it is dropped
+//     from Union and Xor, and makes Intersect empty,
+//   - Difference(X, X) and Difference(Union(), ...) are empty,
+//   - duplicate arguments of Union and Intersect are removed,
+//   - a Union of rows of the same field, such as Union(Row(f=1), Row(f=2)),
+//     is replaced by a Row() call of the list of rows, Row(f=[1,2]), which
+//     reads them in a single pass,
+//   - the arguments of Union, Intersect and Xor are sorted, so that
+//     equivalent queries are written the same way.
+//
+// Errors which would only be found while executing, such as counting a call
+// which doesn't return a row, are returned before anything is executed.
+// Operands which are folded away are never executed, so they are resolved
+// with validate first, which returns the error executing them would return,
+// such as a missing field.
+func optimizeQuery(q *pql.Query, validate func(c *pql.Call) error) error {
+	if validate == nil {
+		validate = func(*pql.Call) error { return nil }
+	}
+	for i, c := range q.Calls {
+		if err := checkSampleCalls(c); err != nil {
+			return NewBadRequestError(err)
+		} else if err := validateOptimizedCall(c); err != nil {
+			return NewBadRequestError(err)
+		}
+		other, err := optimizeTopLevelCall(c, validate)
+		if err != nil {
+			return err
+		}
+		q.Calls[i] = other
+	}
+	return nil
+}
+
+// validateOptimizedCall returns the errors which optimizing c could hide,
+// such as an invalid operand of an Intersect which is folded away.
+func validateOptimizedCall(c *pql.Call) error {
+	switch c.Name {
+	case "Count":
+		// Count() without an input counts the columns of the index.
+		if len(c.Children) > 1 {
+			return fmt.Errorf("%s() only accepts a single bitmap input", c.Name)
+		}
+	case "Not", "Shift":
+		if len(c.Children) != 1 {
+			return fmt.Errorf("%s() only accepts a single bitmap input", c.Name)
+		}
+	case "Intersect", "Difference":
+		if len(c.Children) == 0 {
+			return fmt.Errorf("empty %s query is currently not supported", c.Name)
+		}
+	case "Sample":
+		if len(c.Children) != 1 {
+			return fmt.Errorf("%s() only accepts a single input", c.Name)
+		} else if child := c.Children[0]; child.Name != "Count" && child.Name != "TopN" && !isBitmapCall(child) {
+			return fmt.Errorf("%s() argument must return a row, or be Count() or TopN(), got %s()", c.Name, child.Name)
+		}
+	}
+
+	switch c.Name {
+	case "Count", "Not", "Shift", "Union", "Intersect", "Difference", "Xor":
+		for _, child := range c.Children {
+			// The placement of Sample() is checked before.
+			if child.Name == "Sample" {
+				continue
+			} else if !isBitmapCall(child) {
+				return fmt.Errorf("%s() argument must return a row, got %s()", c.Name, child.Name)
+			}
+		}
+	}
+
+	for _, child := range c.Children {
+		if err := validateOptimizedCall(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optimizeTopLevelCall optimizes a call whose result is returned to the
+// client. Rows returned by a top-level Row() call carry the attributes of
+// the row, so a set operation which is reduced to a Row() call is kept
+// wrapped in a Union.
+func optimizeTopLevelCall(c *pql.Call, validate func(c *pql.Call) error) (*pql.Call, error) {
+	if c.Name == "Options" {
+		for i, child := range c.Children {
+			other, err := optimizeTopLevelCall(child, validate)
+			if err != nil {
+				return nil, err
+			}
+			c.Children[i] = other
+		}
+		return c, nil
+	}
+
+	name := c.Name
+	c, err := optimizeCall(c, validate)
+	if err != nil {
+		return nil, err
+	} else if c.Name == "Row" && name != "Row" && !c.HasConditionArg() {
+		return &pql.Call{Name: "Union", Children: []*pql.Call{c}}, nil
+	}
+	return c, nil
+}
+
+// optimizeCall optimizes the children of c, then c itself. The operands
+// which are folded away are validated with validate.
+func optimizeCall(c *pql.Call, validate func(c *pql.Call) error) (*pql.Call, error) {
+	for i, child := range c.Children {
+		other, err := optimizeCall(child, validate)
+		if err != nil {
+			return nil, err
+		}
+		c.Children[i] = other
+	}
+
+	// Set operations with arguments, such as a shard restriction, are
+	// left as they are.
+	if len(c.Args) > 0 {
+		return c, nil
+	}
+
+	switch c.Name {
+	case "Union", "Xor":
+		c.Children = flattenCalls(c.Name, c.Children)
+		children := c.Children[:0]
+		for _, child := range c.Children {
+			if !isEmptyCall(child) {
+				children = append(children, child)
+			}
+		}
+		c.Children = children
+		if c.Name == "Union" {
+			c.Children = uniqueCalls(c.Children)
+			if row := unionRowsCall(c.Children); row != nil {
+				return row, nil
+			}
+		}
+		sortCalls(c.Children)
+
+	case "Intersect":
+		c.Children = flattenCalls(c.Name, c.Children)
+		for _, child := range c.Children {
+			if isEmptyCall(child) {
+				return foldEmptyCall(c.Children, validate)
+			}
+		}
+		c.Children = uniqueCalls(c.Children)
+		sortCalls(c.Children)
+
+	case "Difference":
+		if len(c.Children) == 0 {
+			return c, nil
+		} else if isEmptyCall(c.Children[0]) {
+			return foldEmptyCall(c.Children, validate)
+		}
+		first := c.Children[0].String()
+		children := c.Children[:1]
+		for _, child := range c.Children[1:] {
+			if child.String() == first {
+				return foldEmptyCall(c.Children, validate)
+			} else if !isEmptyCall(child) {
+				children = append(children, child)
+			}
+		}
+		c.Children = children
+
+	default:
+		return c, nil
+	}
+
+	if len(c.Children) == 1 {
+		return c.Children[0], nil
+	}
+	return c, nil
+}
+
+// foldEmptyCall returns a call which always returns an empty row, in place
+// of a set operation of operands which is known to be empty, once the
+// operands are validated.
+func foldEmptyCall(operands []*pql.Call, validate func(c *pql.Call) error) (*pql.Call, error) {
+	for _, c := range operands {
+		if err := validate(c); err != nil {
+			return nil, err
+		}
+	}
+	return newEmptyCall(), nil
+}
+
+// unionRowsCall returns a Row() call of the list of the rows of calls, if
+// there are several and every call is a Row() call of the same field and
+// time range, or nil otherwise.
+func unionRowsCall(calls []*pql.Call) *pql.Call {
+	if len(calls) < 2 {
+		return nil
+	}
+	var fieldName string
+	var rowIDs []uint64
+	for i, c := range calls {
+		if c.Name != "Row" || len(c.Children) > 0 || c.HasConditionArg() {
+			return nil
+		}
+		name, err := c.FieldArg()
+		if err != nil {
+			return nil
+		}
+		for k := range c.Args {
+			if k != name && k != "from" && k != "to" {
+				return nil
+			}
+		}
+		if i == 0 {
+			fieldName = name
+		} else if name != fieldName ||
+			fmt.Sprint(c.Args["from"]) != fmt.Sprint(calls[0].Args["from"]) ||
+			fmt.Sprint(c.Args["to"]) != fmt.Sprint(calls[0].Args["to"]) {
+			return nil
+		}
+		ids, err := rowIDsArg(c, name)
+		if err != nil {
+			return nil
+		}
+		rowIDs = append(rowIDs, ids...)
+	}
+
+	sort.Slice(rowIDs, func(i, j int) bool { return rowIDs[i] < rowIDs[j] })
+	unique := rowIDs[:0]
+	for i, id := range rowIDs {
+		if i == 0 || id != rowIDs[i-1] {
+			unique = append(unique, id)
+		}
+	}
+	row := &pql.Call{Name: "Row", Args: pql.CopyArgs(calls[0].Args)}
+	row.Args[fieldName] = unique
+	return row
+}
+
+// flattenCalls replaces the calls named name in calls by their children.
+func flattenCalls(name string, calls []*pql.Call) []*pql.Call {
+	flattened := make([]*pql.Call, 0, len(calls))
+	for _, c := range calls {
+		if c.Name == name && len(c.Args) == 0 && len(c.Children) > 0 {
+			flattened = append(flattened, c.Children...)
+		} else {
+			flattened = append(flattened, c)
+		}
+	}
+	return flattened
+}
+
+// uniqueCalls removes the calls which are written the same as an earlier call.
+func uniqueCalls(calls []*pql.Call) []*pql.Call {
+	seen := make(map[string]struct{}, len(calls))
+	unique := calls[:0]
+	for _, c := range calls {
+		s := c.String()
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		unique = append(unique, c)
+	}
+	return unique
+}
+
+// sortCalls sorts calls by their string representation.
+func sortCalls(calls []*pql.Call) {
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].String() < calls[j].String() })
+}
+
+// isEmptyCall returns true if c is Union() or Xor(), which always return an
+// empty row.
+func isEmptyCall(c *pql.Call) bool {
+	return (c.Name == "Union" || c.Name == "Xor") && len(c.Children) == 0 && len(c.Args) == 0
+}
+
+// newEmptyCall returns a call which always returns an empty row.
+func newEmptyCall() *pql.Call {
+	return &pql.Call{Name: "Union"}
+}
@@ -0,0 +1,226 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pkg/errors"
+)
+
+func TestOptimizeQuery(t *testing.T) {
+	tests := []struct {
+		pql  string
+		want string
+	}{
+		{pql: `Count(Intersect(Row(f=1)))`, want: `Count(Row(f=1))`},
+		{pql: `Count(Union(Union(Row(g=2), Row(f=1)), Row(h=3)))`, want: `Count(Union(Row(f=1), Row(g=2), Row(h=3)))`},
+		{pql: `Count(Intersect(Row(f=2), Intersect(Row(f=1), Row(f=3))))`, want: `Count(Intersect(Row(f=1), Row(f=2), Row(f=3)))`},
+		{pql: `Count(Xor(Row(f=2), Xor(Row(f=1), Row(f=1))))`, want: `Count(Xor(Row(f=1), Row(f=1), Row(f=2)))`},
+		{pql: `Count(Intersect(Row(f=1), Union()))`, want: `Count(Union())`},
+		{pql: `Count(Union(Row(f=1), Union(), Xor()))`, want: `Count(Row(f=1))`},
+		{pql: `Count(Union(Row(f=1), Row(f=1)))`, want: `Count(Row(f=1))`},
+		{pql: `Count(Difference(Row(f=1), Row(f=1)))`, want: `Count(Union())`},
+		{pql: `Count(Difference(Union(), Row(f=1)))`, want: `Count(Union())`},
+		{pql: `Count(Difference(Row(f=1), Union(), Row(f=2)))`, want: `Count(Difference(Row(f=1), Row(f=2)))`},
+		{pql: `Count(Difference(Row(f=2), Row(f=1)))`, want: `Count(Difference(Row(f=2), Row(f=1)))`},
+		{pql: `Count(Not(Intersect(Row(f=1))))`, want: `Count(Not(Row(f=1)))`},
+		{pql: `Union(Row(f=1), Row(f=3), shards=[1])`, want: `Union(Row(f=1), Row(f=3), shards=[1])`},
+
+		// A Union of rows of the same field reads them in a single pass.
+		{pql: `Count(Union(Union(Row(f=2), Row(f=1)), Row(f=3)))`, want: `Count(Row(f=[1,2,3]))`},
+		{pql: `Count(Union(Row(f=[3, 1]), Row(f=2), Row(f=1)))`, want: `Count(Row(f=[1,2,3]))`},
+		{pql: `Count(Union(Row(f=1, from=2010-01-01T00:00), Row(f=2, from=2010-01-01T00:00)))`, want: `Count(Row(f=[1,2], from="2010-01-01T00:00"))`},
+		{pql: `Count(Union(Row(f=1, from=2010-01-01T00:00), Row(f=2)))`, want: `Count(Union(Row(f=1, from="2010-01-01T00:00"), Row(f=2)))`},
+		{pql: `Count(Union(Row(f=1), Row(g=2)))`, want: `Count(Union(Row(f=1), Row(g=2)))`},
+		{pql: `Count(Union(Row(f=1), Row(f > 2)))`, want: `Count(Union(Row(f > 2), Row(f=1)))`},
+		{pql: `Union(Row(f=1), Row(f=2))`, want: `Union(Row(f=[1,2]))`},
+
+		// Top-level Row() calls return the attributes of the row, so a set
+		// operation reduced to a Row() call is kept wrapped.
+		{pql: `Intersect(Row(f=1))`, want: `Union(Row(f=1))`},
+		{pql: `Options(Intersect(Row(f=1), Row(f=1)), excludeColumns=true)`, want: `Options(Union(Row(f=1)), excludeColumns=true)`},
+		{pql: `Intersect(Row(f > 1))`, want: `Row(f > 1)`},
+		{pql: `Row(f=1)`, want: `Row(f=1)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pql, func(t *testing.T) {
+			q, err := pql.ParseString(tt.pql)
+			if err != nil {
+				t.Fatal(err)
+			} else if err := optimizeQuery(q, nil); err != nil {
+				t.Fatal(err)
+			} else if got := q.String(); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptimizeQuery_Errors(t *testing.T) {
+	tests := []struct {
+		pql string
+		err string
+	}{
+		{pql: `Count(Count(Row(f=1)))`, err: "Count() argument must return a row, got Count()"},
+		{pql: `Count(Row(f=1), Row(f=2))`, err: "Count() only accepts a single bitmap input"},
+		{pql: `Count(Intersect(Row(f=1), TopN(f)))`, err: "Intersect() argument must return a row, got TopN()"},
+		{pql: `Count(Intersect(Union(), Difference()))`, err: "empty Difference query is currently not supported"},
+		{pql: `Not(Union(), Union())`, err: "Not() only accepts a single bitmap input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pql, func(t *testing.T) {
+			q, err := pql.ParseString(tt.pql)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = optimizeQuery(q, nil)
+			if _, ok := err.(BadRequestError); !ok {
+				t.Fatalf("expected bad request error, got %#v", err)
+			} else if !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
+
+// Ensure optimized queries return the same results as the original queries
+// on random data, and the same errors when an operand is invalid.
+func TestOptimizeQuery_Equivalence(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := h.CreateIndex("i", IndexOptions{TrackExistence: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rnd := rand.New(rand.NewSource(11))
+	for _, name := range []string{"f", "g"} {
+		f, err := idx.CreateField(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2000; i++ {
+			col := uint64(rnd.Intn(2 * ShardWidth))
+			if _, err := f.SetBit(uint64(rnd.Intn(4)), col, nil); err != nil {
+				t.Fatal(err)
+			} else if _, err := idx.existenceField().SetBit(0, col, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	e := newExecutor()
+	defer e.Close()
+	e.Holder = h.Holder
+	e.Cluster = NewTestCluster(1)
+	e.Node = e.Cluster.Node
+
+	validate := func(c *pql.Call) error { return e.validateBitmapCall("i", c) }
+	queries := []string{
+		`Count(Difference(Row(nosuch=1), Row(nosuch=1)))`,
+		`Count(Intersect(Row(f=1), Row(nosuch=1), Union()))`,
+		`Count(Difference(Union(), Row(f=1, from="bad")))`,
+		`Union(Row(f=1), Xor(), Row(f > 1))`,
+	}
+	for i := 0; i < 500; i++ {
+		s := randomOptimizerCall(rnd, 4).String()
+		if rnd.Intn(2) == 0 {
+			s = "Count(" + s + ")"
+		}
+		queries = append(queries, s)
+	}
+
+	for _, s := range queries {
+		original, err := pql.ParseString(s)
+		if err != nil {
+			t.Fatalf("parsing %s: %s", s, err)
+		}
+		want, wantErr := e.execute(context.Background(), "i", original, nil, &execOptions{})
+
+		var got []interface{}
+		optimized, _ := pql.ParseString(s)
+		err = optimizeQuery(optimized, validate)
+		if err == nil {
+			got, err = e.execute(context.Background(), "i", optimized, nil, &execOptions{})
+		}
+		if wantErr != nil || err != nil {
+			if wantErr == nil || err == nil || errors.Cause(err).Error() != errors.Cause(wantErr).Error() {
+				t.Fatalf("%s optimized to %s: got error %v, want %v", s, optimized, err, wantErr)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(optimizerResult(got[0]), optimizerResult(want[0])) {
+			t.Fatalf("%s optimized to %s: got %v, want %v", s, optimized, optimizerResult(got[0]), optimizerResult(want[0]))
+		}
+	}
+}
+
+// randomOptimizerCall returns a random bitmap call, whose operands are
+// sometimes repeated or empty, so that the optimizer has work to do.
+func randomOptimizerCall(rnd *rand.Rand, depth int) *pql.Call {
+	if depth == 0 || rnd.Intn(4) == 0 {
+		switch rnd.Intn(6) {
+		case 0:
+			return &pql.Call{Name: "Union"}
+		case 1:
+			return &pql.Call{Name: "Xor"}
+		case 2:
+			// An operand which is invalid, but only sometimes evaluated
+			// when the others are empty.
+			if rnd.Intn(8) == 0 {
+				return &pql.Call{Name: "Row", Args: map[string]interface{}{"nosuch": int64(1)}}
+			}
+			fallthrough
+		default:
+			field := []string{"f", "g"}[rnd.Intn(2)]
+			return &pql.Call{Name: "Row", Args: map[string]interface{}{field: int64(rnd.Intn(4))}}
+		}
+	}
+
+	name := []string{"Union", "Intersect", "Xor", "Difference", "Not"}[rnd.Intn(5)]
+	n := 1
+	if name != "Not" {
+		n += rnd.Intn(3)
+	}
+	c := &pql.Call{Name: name}
+	for i := 0; i < n; i++ {
+		if i > 0 && rnd.Intn(4) == 0 {
+			c.Children = append(c.Children, c.Children[rnd.Intn(i)].Clone())
+			continue
+		}
+		c.Children = append(c.Children, randomOptimizerCall(rnd, depth-1))
+	}
+	return c
+}
+
+// optimizerResult returns a comparable form of a query result.
+func optimizerResult(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *Row:
+		return fmt.Sprint(v.Columns())
+	default:
+		return v
+	}
+}
@@ -16,7 +16,9 @@ package pilosa
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -29,11 +31,56 @@ var (
 	ErrIndexExists   = errors.New("index already exists")
 	ErrIndexNotFound = errors.New("index not found")
 
+	// ErrTrashEntryNotFound is returned when restoring or purging a deleted
+	// index which isn't in the trash.
+	ErrTrashEntryNotFound = errors.New("trash entry not found")
+
+	// ErrInvalidShardWidth is returned when an index is created with a shard
+	// width which is not a power of 2 between 2^16 and 2^32.
+	ErrInvalidShardWidth = errors.New("invalid shard width, must be a power of 2 between 2^16 and 2^32")
+	// ErrInvalidQuota is returned when an index is given a negative disk
+	// quota.
+	ErrInvalidQuota = errors.New("invalid quota, must not be negative")
+	// ErrShardWidthMismatch is returned when the shard width of an existing
+	// index would be changed.
+	ErrShardWidthMismatch = errors.New("shard width of existing index cannot be changed")
+	// ErrSchemaGenerationMismatch is returned when a schema mutation is made
+	// with a generation precondition which no longer matches.
+	ErrSchemaGenerationMismatch = errors.New("schema generation mismatch")
+	// ErrIndexReadOnly is returned when writing to an index which has been
+	// marked read-only.
+	ErrIndexReadOnly = errors.New("index is read-only")
+	// ErrQuotaExceeded is returned when writing to an index which uses more
+	// disk space on the node than its quota.
+	ErrQuotaExceeded = errors.New("index disk quota exceeded")
+	// ErrMemoryPressure is returned when a query is rejected because the
+	// node is using too much memory. The query can be retried later.
+	ErrMemoryPressure = errors.New("node is under memory pressure, retry later")
+
+	// ErrFileLimitTooLow is returned when a holder has more fragments than
+	// the process is allowed to keep open.
+	ErrFileLimitTooLow = errors.New("open file limit too low")
+
 	// ErrFieldRequired is returned when no field is specified.
 	ErrFieldRequired = errors.New("field required")
 	ErrFieldExists   = errors.New("field already exists")
 	ErrFieldNotFound = errors.New("field not found")
 
+	// ErrIngestMappingExists is returned when creating an ingest mapping
+	// whose name is already used by the index.
+	ErrIngestMappingExists = errors.New("ingest mapping already exists")
+	// ErrIngestMappingNotFound is returned when an ingest mapping does not
+	// exist.
+	ErrIngestMappingNotFound = errors.New("ingest mapping not found")
+
+	// ErrDeleteJobNotFound is returned when a delete job does not exist on
+	// the node.
+	ErrDeleteJobNotFound = errors.New("delete job not found")
+
+	// ErrWarmJobNotFound is returned when a warm job does not exist on any
+	// node.
+	ErrWarmJobNotFound = errors.New("warm job not found")
+
 	ErrBSIGroupNotFound         = errors.New("bsigroup not found")
 	ErrBSIGroupExists           = errors.New("bsigroup already exists")
 	ErrBSIGroupNameRequired     = errors.New("bsigroup name required")
@@ -45,7 +92,18 @@ var (
 	ErrInvalidRangeOperation    = errors.New("invalid range operation")
 	ErrInvalidBetweenValue      = errors.New("invalid value for between operation")
 
+	// ErrInvalidScale is returned when an int field is created with a scale
+	// outside of the supported range.
+	ErrInvalidScale = errors.New("invalid scale, must be between 0 and 18")
+	// ErrDecimalScale is returned when a value has more decimal places than
+	// the scale of its field.
+	ErrDecimalScale = errors.New("value has more decimal places than the field scale")
+	// ErrSharedTimeViewsQuantum is returned when a field which shares the
+	// containers of its time views doesn't have both day and hour views.
+	ErrSharedTimeViewsQuantum = errors.New("sharing time views requires a time field with a quantum with day and hour units")
+
 	ErrInvalidView      = errors.New("invalid view")
+	ErrViewExists       = errors.New("view already exists")
 	ErrInvalidCacheType = errors.New("invalid cache type")
 
 	ErrName  = errors.New("invalid index or field name, must match [a-z][a-z0-9_-]* and contain at most 64 characters")
@@ -58,6 +116,31 @@ var (
 	ErrQueryTimeout     = errors.New("query timeout")
 	ErrTooManyWrites    = errors.New("too many write commands")
 
+	// ErrShardOutOfRange is returned when a query requests a shard beyond
+	// the max shard of the index.
+	ErrShardOutOfRange = errors.New("shard out of range")
+
+	// ErrResultHandleNotFound is returned when a query references a result
+	// handle which is not stored in its session.
+	ErrResultHandleNotFound = errors.New("result handle not found")
+	// ErrResultHandleMemory is returned when storing a query result would
+	// exceed the memory limit for stored results.
+	ErrResultHandleMemory = errors.New("result handle memory limit exceeded")
+
+	// ErrSnapshotMemory is returned when the snapshot of a snapshot read, or
+	// of one of its fragments, would exceed the memory limit for snapshots.
+	ErrSnapshotMemory = errors.New("snapshot memory limit exceeded")
+	// ErrSnapshotTimeout is returned when a snapshot read can't take its
+	// snapshot because the writes being applied don't finish in time.
+	ErrSnapshotTimeout = errors.New("snapshot timed out waiting for writes, retry later")
+
+	// ErrTransactionNotFound is returned when a node receives a commit for a
+	// transaction it has not prepared.
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionTimeout is returned when a node can't commit a
+	// transaction because the queries running on it don't finish in time.
+	ErrTransactionTimeout = errors.New("transaction timed out waiting for queries")
+
 	// TODO(2.0) poorly named - used when a *node* doesn't own a shard. Probably
 	// we won't need this error at all by 2.0 though.
 	ErrClusterDoesNotOwnShard = errors.New("node does not own shard")
@@ -66,6 +149,10 @@ var (
 	ErrNodeNotCoordinator = errors.New("node is not the coordinator")
 	ErrResizeNotRunning   = errors.New("no resize job currently running")
 
+	// ErrProtocolVersion is returned when a node can't join the cluster
+	// because it doesn't speak a protocol version the cluster accepts.
+	ErrProtocolVersion = errors.New("incompatible protocol version")
+
 	ErrNotImplemented            = errors.New("not implemented")
 	ErrFieldsArgumentRequired    = errors.New("fields argument required")
 	ErrExpectedFieldListArgument = errors.New("expected field list argument")
@@ -117,6 +204,29 @@ func newNotFoundError(err error) NotFoundError {
 	return NotFoundError{err}
 }
 
+// ShardOwnerError is returned for a request for a shard which the local node
+// doesn't own. It lists the nodes which do, so that the request can be sent
+// to one of them instead. Its cause is ErrClusterDoesNotOwnShard.
+type ShardOwnerError struct {
+	Index  string
+	Shard  uint64
+	Owners []*Node
+}
+
+// Error implements the error interface.
+func (e *ShardOwnerError) Error() string {
+	uris := make([]string, len(e.Owners))
+	for i, node := range e.Owners {
+		uris[i] = node.URI.String()
+	}
+	return fmt.Sprintf("%s: shard %d of index %s is owned by %s", ErrClusterDoesNotOwnShard, e.Shard, e.Index, strings.Join(uris, ", "))
+}
+
+// Cause returns ErrClusterDoesNotOwnShard.
+func (e *ShardOwnerError) Cause() error {
+	return ErrClusterDoesNotOwnShard
+}
+
 // Regular expression to validate index and field names.
 var nameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
 
@@ -235,14 +235,33 @@ func (q *Query) addBTWN() {
 func (q *Query) WriteCallN() int {
 	var n int
 	for _, call := range q.Calls {
-		switch call.Name {
-		case "Set", "Clear", "SetRowAttrs", "SetColumnAttrs":
+		if isWriteCall(call.Name) {
 			n++
 		}
 	}
 	return n
 }
 
+// WriteCalls returns the number of mutating calls in the query by call name.
+func (q *Query) WriteCalls() map[string]int {
+	m := make(map[string]int)
+	for _, call := range q.Calls {
+		if isWriteCall(call.Name) {
+			m[call.Name]++
+		}
+	}
+	return m
+}
+
+// isWriteCall returns true if calls with the given name mutate data.
+func isWriteCall(name string) bool {
+	switch name {
+	case "Set", "Clear", "ClearRow", "Store", "SetRowAttrs", "SetColumnAttrs", "IncrementFieldValue", "DeleteColumn":
+		return true
+	}
+	return false
+}
+
 // String returns a string representation of the query.
 func (q *Query) String() string {
 	a := make([]string, len(q.Calls))
@@ -352,8 +371,9 @@ func (c *Call) IntArg(key string) (int64, bool, error) {
 
 // UintSliceArg reads the value at key from call.Args as a slice of uint64. If
 // the key is not in Call.Args, the value of the returned bool will be false,
-// and the error will be nil. If the value is a slice of int64 it will convert
-// it to []uint64. Otherwise, if it is not a []uint64 it will return an error.
+// and the error will be nil. If the value is a slice of int64, or a parsed
+// list of integers, it will convert it to []uint64. Otherwise, if it is not
+// a []uint64 it will return an error.
 func (c *Call) UintSliceArg(key string) ([]uint64, bool, error) {
 	val, ok := c.Args[key]
 	if !ok {
@@ -369,11 +389,39 @@ func (c *Call) UintSliceArg(key string) ([]uint64, bool, error) {
 			ret[i] = uint64(v)
 		}
 		return ret, true, nil
+	case []interface{}:
+		// A list argument, such as ids=[1, 2, 3], as parsed.
+		ret := make([]uint64, len(tval))
+		for i, v := range tval {
+			switch tv := v.(type) {
+			case int64:
+				if tv < 0 {
+					return nil, true, fmt.Errorf("values for '%s' must be positive, but got %v", key, tv)
+				}
+				ret[i] = uint64(tv)
+			case uint64:
+				ret[i] = tv
+			default:
+				return nil, true, fmt.Errorf("unexpected value type %T in UintSliceArg, val %v", tv, tv)
+			}
+		}
+		return ret, true, nil
 	default:
 		return nil, true, fmt.Errorf("unexpected type %T in UintSliceArg, val %v", tval, tval)
 	}
 }
 
+// IsListArg returns true if the value at key in Call.Args is a list, such as
+// f=[1, 2, 3].
+func (c *Call) IsListArg(key string) bool {
+	switch c.Args[key].(type) {
+	case []interface{}, []uint64, []int64:
+		return true
+	default:
+		return false
+	}
+}
+
 // CallArg is for reading the value at key from call.Args as a Call. If the
 // key is not in Call.Args, the value of the returned value will be nil, and
 // the error will be nil. An error is returned if the value is not a Call.
@@ -477,6 +525,11 @@ func (c *Call) HasConditionArg() bool {
 	return false
 }
 
+// IsWrite returns true if the call mutates data.
+func (c *Call) IsWrite() bool {
+	return isWriteCall(c.Name)
+}
+
 // Condition represents an operation & value.
 // When used in an argument map it represents a binary expression.
 type Condition struct {
@@ -524,8 +577,12 @@ func formatValue(v interface{}) string {
 		return joinUint64Slice(v)
 	case time.Time:
 		return fmt.Sprintf("\"%s\"", v.Format(timeFormat))
+	case float64:
+		return formatFloat(v)
 	case *Condition:
 		return v.String()
+	case nil:
+		return "null"
 	default:
 		return fmt.Sprintf("%v", v)
 	}
@@ -546,6 +603,8 @@ func joinInterfaceSlice(a []interface{}) string {
 		switch v := a[i].(type) {
 		case string:
 			other[i] = fmt.Sprintf("%q", v)
+		case float64:
+			other[i] = formatFloat(v)
 		default:
 			other[i] = fmt.Sprintf("%v", v)
 		}
@@ -553,6 +612,16 @@ func joinInterfaceSlice(a []interface{}) string {
 	return "[" + strings.Join(other, ",") + "]"
 }
 
+// formatFloat formats v as a decimal literal. Unlike %v it never uses an
+// exponent, which the parser does not accept.
+func formatFloat(v float64) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
 func joinUint64Slice(a []uint64) string {
 	other := make([]string, len(a))
 	for i := range a {
@@ -41,6 +41,27 @@ func TestCall_String(t *testing.T) {
 			t.Fatalf("unexpected string: %s", s)
 		}
 	})
+	t.Run("Decimals", func(t *testing.T) {
+		c := &pql.Call{
+			Name: "Row",
+			Args: map[string]interface{}{
+				"a": &pql.Condition{Op: pql.GT, Value: 0.00001},
+				"b": &pql.Condition{Op: pql.BETWEEN, Value: []interface{}{2.0, 19.99}},
+			},
+		}
+		if s := c.String(); s != `Row(a > 0.00001, b >< [2.0,19.99])` {
+			t.Fatalf("unexpected string: %s", s)
+		}
+	})
+	t.Run("Null", func(t *testing.T) {
+		c := &pql.Call{
+			Name: "Row",
+			Args: map[string]interface{}{"a": &pql.Condition{Op: pql.NEQ, Value: nil}},
+		}
+		if s := c.String(); s != `Row(a != null)` {
+			t.Fatalf("unexpected string: %s", s)
+		}
+	})
 }
 
 // Ensure condition can handle values for BETWEEN operator.
@@ -67,3 +88,27 @@ func TestCondition_Value(t *testing.T) {
 		}
 	})
 }
+
+// Ensure a parsed list argument can be read as a slice of uint64.
+func TestCall_UintSliceArg(t *testing.T) {
+	q, err := pql.ParseString(`Row(f=[1, 2, 3])`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := q.Calls[0]
+	if !c.IsListArg("f") {
+		t.Fatal("expected list argument")
+	} else if v, ok, err := c.UintSliceArg("f"); err != nil || !ok {
+		t.Fatalf("unexpected ok: %v, err: %v", ok, err)
+	} else if !reflect.DeepEqual(v, []uint64{1, 2, 3}) {
+		t.Fatalf("unexpected values: %v", v)
+	} else if s := c.String(); s != `Row(f=[1,2,3])` {
+		t.Fatalf("unexpected string: %s", s)
+	}
+
+	if q, err = pql.ParseString(`Row(f=[1, -2])`); err != nil {
+		t.Fatal(err)
+	} else if _, _, err := q.Calls[0].UintSliceArg("f"); err == nil {
+		t.Fatal("expected error for negative value")
+	}
+}
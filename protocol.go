@@ -0,0 +1,161 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pkg/errors"
+)
+
+// The messages between the nodes of a cluster are versioned, so that nodes of
+// different releases can run in the same cluster during a rolling upgrade.
+// Each node advertises the range of protocol versions it speaks in its Node,
+// which is sent with NodeJoin events and cluster statuses. The effective
+// version of the cluster is the lowest maximum version of its nodes, and
+// messages whose encoding changed are produced with the encoding of the
+// effective version.
+//
+// Version 1 is the protocol of nodes which don't advertise a version. Since
+// version 2, the sources of a resize instruction list the shards of each view,
+// the message types from messageTypeTransaction on are sent between nodes, and
+// queries forwarded to other nodes may read a list of rows with Row(f=[...]).
+const (
+	// MinProtocolVersion is the oldest protocol version the node speaks.
+	MinProtocolVersion = 1
+
+	// ProtocolVersion is the newest protocol version the node speaks.
+	ProtocolVersion = 2
+)
+
+// minProtocolVersion returns the oldest protocol version the node speaks.
+func (n *Node) minProtocolVersion() uint32 {
+	if n.MinProtocolVersion == 0 {
+		return 1
+	}
+	return n.MinProtocolVersion
+}
+
+// maxProtocolVersion returns the newest protocol version the node speaks.
+func (n *Node) maxProtocolVersion() uint32 {
+	if n.MaxProtocolVersion == 0 {
+		return 1
+	}
+	return n.MaxProtocolVersion
+}
+
+// protocolVersion returns the effective protocol version of nodes: the
+// newest version they all speak.
+func protocolVersion(nodes []*Node) uint32 {
+	v := uint32(ProtocolVersion)
+	for _, n := range nodes {
+		if max := n.maxProtocolVersion(); max < v {
+			v = max
+		}
+	}
+	return v
+}
+
+// ProtocolVersion returns the effective protocol version of the cluster.
+func (c *cluster) ProtocolVersion() uint32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return protocolVersion(c.nodes)
+}
+
+// unprotectedCheckProtocolVersion returns an error if node can't join the
+// cluster because the protocol versions it speaks are below the configured
+// minimum, or don't include the effective version of the other nodes.
+func (c *cluster) unprotectedCheckProtocolVersion(node *Node) error {
+	if max := node.maxProtocolVersion(); max < c.minProtocolVersion {
+		return errors.Wrapf(ErrProtocolVersion, "node %s speaks protocol versions up to %d, the cluster requires at least %d", node.ID, max, c.minProtocolVersion)
+	}
+	var others []*Node
+	for _, n := range c.nodes {
+		if n.ID != node.ID {
+			others = append(others, n)
+		}
+	}
+	if min, v := node.minProtocolVersion(), protocolVersion(others); min > v {
+		return errors.Wrapf(ErrProtocolVersion, "node %s speaks protocol versions from %d, the cluster speaks version %d", node.ID, min, v)
+	}
+	return nil
+}
+
+// messageProtocolVersion returns the oldest protocol version which has the
+// message type of m. Nodes of version 1 don't know the types added since.
+func messageProtocolVersion(m Message) uint32 {
+	if getMessageType(m) >= messageTypeTransaction {
+		return 2
+	}
+	return 1
+}
+
+// checkMessageProtocolVersion returns an error if one of nodes doesn't speak
+// the protocol version of the message type of m.
+func checkMessageProtocolVersion(m Message, nodes ...*Node) error {
+	v := messageProtocolVersion(m)
+	for _, n := range nodes {
+		if max := n.maxProtocolVersion(); max < v {
+			return errors.Wrapf(ErrProtocolVersion, "node %s speaks protocol versions up to %d, %T requires %d", n.ID, max, m, v)
+		}
+	}
+	return nil
+}
+
+// downgradeQuery returns q written for nodes which speak protocol version v.
+// Before version 2, a Row() call of a list of rows is written as the Union of
+// the Row() calls of each row.
+func downgradeQuery(q *pql.Query, v uint32) *pql.Query {
+	if v >= 2 {
+		return q
+	}
+	other := &pql.Query{Calls: make([]*pql.Call, len(q.Calls))}
+	for i, c := range q.Calls {
+		other.Calls[i] = downgradeCall(c.Clone())
+	}
+	return other
+}
+
+// downgradeCall rewrites the Row() calls of a list of rows of c, and of its
+// children, into the Union of the Row() calls of each row.
+func downgradeCall(c *pql.Call) *pql.Call {
+	for i, child := range c.Children {
+		c.Children[i] = downgradeCall(child)
+	}
+	for k, v := range c.Args {
+		if child, ok := v.(*pql.Call); ok {
+			c.Args[k] = downgradeCall(child)
+		}
+	}
+
+	if c.Name != "Row" && c.Name != "Range" {
+		return c
+	}
+	fieldName, err := c.FieldArg()
+	if err != nil || !c.IsListArg(fieldName) {
+		return c
+	}
+	rowIDs, _, err := c.UintSliceArg(fieldName)
+	if err != nil {
+		return c
+	}
+	union := &pql.Call{Name: "Union"}
+	for _, id := range rowIDs {
+		row := &pql.Call{Name: c.Name, Args: pql.CopyArgs(c.Args)}
+		row.Args[fieldName] = id
+		union.Children = append(union.Children, row)
+	}
+	return union
+}
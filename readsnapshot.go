@@ -0,0 +1,298 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultSnapshotReadMaxMemory is the number of bytes the snapshots of
+	// all running snapshot reads may use on a node.
+	defaultSnapshotReadMaxMemory = 256 << 20
+
+	// defaultSnapshotReadMaxFragmentMemory is the number of bytes the
+	// snapshot of a single fragment may use.
+	defaultSnapshotReadMaxFragmentMemory = 32 << 20
+
+	// defaultSnapshotReadTimeout is how long a snapshot read waits for the
+	// writes being applied on a node to take its snapshot.
+	defaultSnapshotReadTimeout = 10 * time.Second
+
+	// snapshotContainerOverhead approximates the memory used by each
+	// container of a fragment snapshot.
+	snapshotContainerOverhead = 64
+
+	// maxContainerSize is the number of bytes of a bitmap container, which
+	// is the largest a container copied on write may be.
+	maxContainerSize = 8192
+)
+
+// SnapshotReadOptions holds options for queries which read a snapshot of
+// the data.
+type SnapshotReadOptions struct {
+	// Number of bytes the snapshots of all running queries may use on a
+	// node. Zero is unlimited.
+	MaxMemory int64
+
+	// Number of bytes the snapshot of a single fragment may use. Fragments
+	// with a high write rate exceed it, since every container written while
+	// a snapshot is held is copied. Zero is unlimited.
+	MaxFragmentMemory int64
+
+	// How long a query waits for the writes and imports being applied on a
+	// node to take its snapshot. Zero waits indefinitely.
+	Timeout time.Duration
+}
+
+// readSnapshots accounts for the memory used by the snapshots of the
+// queries running on a node.
+type readSnapshots struct {
+	mu   sync.Mutex
+	size int64
+
+	maxSize         int64
+	maxFragmentSize int64
+	timeout         time.Duration
+
+	stats stats.StatsClient
+}
+
+// newReadSnapshots returns a new instance of readSnapshots.
+func newReadSnapshots(opt SnapshotReadOptions) *readSnapshots {
+	return &readSnapshots{
+		maxSize:         opt.MaxMemory,
+		maxFragmentSize: opt.MaxFragmentMemory,
+		timeout:         opt.Timeout,
+		stats:           stats.NopStatsClient,
+	}
+}
+
+// Size returns the number of bytes used by the snapshots of running queries.
+func (s *readSnapshots) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// acquire takes a snapshot of the fragments of the given shards of idx, or
+// of all of its fragments if shards is empty. The caller must make sure no
+// write is applied meanwhile, and release the snapshot once the query is
+// done.
+func (s *readSnapshots) acquire(idx *Index, shards []uint64) (*readSnapshot, error) {
+	snap := &readSnapshot{
+		s:         s,
+		fragments: make(map[fragmentKey]*fragment),
+		live:      make(map[fragmentKey]*fragment),
+	}
+	for _, field := range idx.Fields() {
+		for _, view := range field.views() {
+			frags := view.allFragments()
+			if len(shards) > 0 {
+				frags = frags[:0]
+				for _, shard := range shards {
+					if f := view.Fragment(shard); f != nil {
+						frags = append(frags, f)
+					}
+				}
+			}
+			for _, f := range frags {
+				shard := f.shard
+				key := fragmentKey{field: field.Name(), view: view.name, shard: shard}
+				other, size := f.readSnapshot()
+				snap.fragments[key], snap.live[key] = other, f
+				snap.size += size
+
+				if s.maxFragmentSize > 0 && size > s.maxFragmentSize {
+					snap.release()
+					s.stats.Count("snapshotReadRefused", 1, 1.0)
+					return nil, errors.Wrapf(ErrSnapshotMemory, "fragment %s/%s/%s/%d requires %d bytes at its write rate, limit is %d", idx.Name(), key.field, key.view, shard, size, s.maxFragmentSize)
+				}
+			}
+		}
+	}
+
+	s.mu.Lock()
+	inUse := s.size
+	ok := s.maxSize <= 0 || s.size+snap.size <= s.maxSize
+	if ok {
+		s.size += snap.size
+	}
+	s.mu.Unlock()
+	if !ok {
+		snap.release()
+		s.stats.Count("snapshotReadRefused", 1, 1.0)
+		return nil, errors.Wrapf(ErrSnapshotMemory, "snapshot requires %d bytes, %d of %d in use", snap.size, inUse, s.maxSize)
+	}
+	snap.acquired = true
+	s.stats.Count("snapshotRead", 1, 1.0)
+	return snap, nil
+}
+
+// fragmentKey identifies a fragment of an index.
+type fragmentKey struct {
+	field string
+	view  string
+	shard uint64
+}
+
+// readSnapshot holds copies of the fragments of an index as of the start of
+// a query.
+type readSnapshot struct {
+	s         *readSnapshots
+	size      int64
+	acquired  bool
+	fragments map[fragmentKey]*fragment
+	live      map[fragmentKey]*fragment
+}
+
+// fragment returns the snapshot of a fragment, which is nil if it didn't
+// exist when the snapshot was taken. A nil snapshot returns the live
+// fragment from h.
+func (r *readSnapshot) fragment(h *Holder, index, field, view string, shard uint64) *fragment {
+	if r == nil {
+		return h.fragment(index, field, view, shard)
+	}
+	return r.fragments[fragmentKey{field: field, view: view, shard: shard}]
+}
+
+// release releases the fragment copies and their memory.
+func (r *readSnapshot) release() {
+	for _, f := range r.live {
+		f.releaseReadSnapshot()
+	}
+	r.fragments, r.live = nil, nil
+	if r.acquired {
+		r.s.mu.Lock()
+		r.s.size -= r.size
+		r.s.mu.Unlock()
+	}
+}
+
+// querySnapshotKey is the context key for the snapshot read by a query.
+type querySnapshotKey struct{}
+
+// withQuerySnapshot returns ctx carrying the snapshot read by a query.
+func withQuerySnapshot(ctx context.Context, snap *readSnapshot) context.Context {
+	return context.WithValue(ctx, querySnapshotKey{}, snap)
+}
+
+// querySnapshot returns the snapshot carried by ctx, or nil if the query
+// reads live data.
+func querySnapshot(ctx context.Context) *readSnapshot {
+	snap, _ := ctx.Value(querySnapshotKey{}).(*readSnapshot)
+	return snap
+}
+
+// writeRate counts the bits changed in the current and the previous second.
+type writeRate struct {
+	second int64
+	cur    int
+	prev   int
+}
+
+// add records n bits changed at t.
+func (r *writeRate) add(t time.Time, n int) {
+	r.roll(t)
+	r.cur += n
+}
+
+// last returns the number of bits changed in the second before t.
+func (r *writeRate) last(t time.Time) int {
+	r.roll(t)
+	if r.cur > r.prev {
+		return r.cur
+	}
+	return r.prev
+}
+
+func (r *writeRate) roll(t time.Time) {
+	second := t.Unix()
+	switch second {
+	case r.second:
+		return
+	case r.second + 1:
+		r.prev = r.cur
+	default:
+		r.prev = 0
+	}
+	r.second, r.cur = second, 0
+}
+
+// lockedCache shares the cache of a fragment with its read snapshots,
+// guarding it with the lock of the fragment.
+type lockedCache struct {
+	mu    *sync.RWMutex
+	cache cache
+}
+
+func (c *lockedCache) Add(id uint64, n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Add(id, n)
+}
+
+func (c *lockedCache) BulkAdd(id uint64, n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.BulkAdd(id, n)
+}
+
+func (c *lockedCache) Get(id uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Get(id)
+}
+
+func (c *lockedCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Len()
+}
+
+func (c *lockedCache) IDs() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.IDs()
+}
+
+func (c *lockedCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Invalidate()
+}
+
+func (c *lockedCache) Recalculate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Recalculate()
+}
+
+func (c *lockedCache) Top() []bitmapPair {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Top()
+}
+
+func (c *lockedCache) SetStats(s stats.StatsClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.SetStats(s)
+}
@@ -0,0 +1,272 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pkg/errors"
+)
+
+const (
+	// readVerifyConcurrency is the number of verifications of shard reads
+	// which run at the same time on a node. Sampled reads are not verified
+	// while it is reached.
+	readVerifyConcurrency = 4
+
+	// readVerifyTimeout bounds how long a verification waits for replicas.
+	readVerifyTimeout = time.Minute
+)
+
+// ReadVerifyOptions configures the verification of the reads of queries
+// against a second replica.
+type ReadVerifyOptions struct {
+	// Rate is the fraction of the shards read by queries which are verified,
+	// between 0 (disabled) and 1.
+	Rate float64
+
+	// Repair synchronizes the fragments read from a shard whose replicas
+	// returned different results.
+	Repair bool
+}
+
+// SyncShardMessage is an internal message asking a node which owns a shard
+// to synchronize the fragments of fields in the shard with their replicas.
+type SyncShardMessage struct {
+	Index  string
+	Fields []string
+	Shard  uint64
+}
+
+// readVerifier verifies a sample of the shard reads of queries by running
+// them on two replicas and comparing the results, in the background.
+type readVerifier struct {
+	opt ReadVerifyOptions
+
+	// Tokens of the verifications running.
+	running chan struct{}
+
+	// Synchronizes the fragments of fields in a shard with their replicas,
+	// asking owner to if this node doesn't own the shard.
+	repair func(index string, fields []string, shard uint64, owner *Node) error
+}
+
+func newReadVerifier(opt ReadVerifyOptions) *readVerifier {
+	return &readVerifier{
+		opt:     opt,
+		running: make(chan struct{}, readVerifyConcurrency),
+	}
+}
+
+// verifiableCall returns true if the results of c can be compared between
+// replicas: counts and rows which don't depend on state kept by a single
+// node.
+func verifiableCall(c *pql.Call) bool {
+	if c.Name != "Count" && !isBitmapCall(c) {
+		return false
+	}
+	ok := true
+	walkCalls(c, func(c *pql.Call) {
+		if c.Name == "Handle" {
+			ok = false
+		}
+	})
+	return ok
+}
+
+// walkCalls calls fn on c and each of its descendants.
+func walkCalls(c *pql.Call, fn func(c *pql.Call)) {
+	fn(c)
+	for _, child := range c.Children {
+		walkCalls(child, fn)
+	}
+}
+
+// callFields returns the sorted names of the fields read by c.
+func callFields(c *pql.Call) []string {
+	m := make(map[string]struct{})
+	walkCalls(c, func(c *pql.Call) {
+		if name, ok := c.Args["_field"].(string); ok {
+			m[name] = struct{}{}
+		} else if name, ok := c.Args["field"].(string); ok {
+			m[name] = struct{}{}
+		} else if c.Name == "Row" || c.Name == "Range" {
+			if name, err := c.FieldArg(); err == nil {
+				m[name] = struct{}{}
+			}
+		}
+	})
+	fields := make([]string, 0, len(m))
+	for name := range m {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// verifyReads starts the verification of a sample of the shards read by a
+// call received from a client. Verifications run in the background, so they
+// don't affect the latency or the results of the query.
+func (e *executor) verifyReads(index string, shards []uint64, c *pql.Call, opt *execOptions) {
+	v := e.verifier
+	if v.opt.Rate <= 0 || opt.Remote || opt.Session != "" || opt.StoreAs != "" || !verifiableCall(c) {
+		return
+	}
+
+	for _, shard := range shards {
+		if rand.Float64() >= v.opt.Rate {
+			continue
+		}
+		select {
+		case v.running <- struct{}{}:
+		default:
+			e.Holder.Stats.Count("readVerifySkipped", 1, 1.0)
+			continue
+		}
+
+		vopt := &execOptions{Remote: true, Snapshot: opt.Snapshot, AsOf: opt.AsOf}
+		go func(shard uint64, c *pql.Call) {
+			defer func() { <-v.running }()
+			if err := e.verifyRead(index, shard, c, vopt); err != nil {
+				e.Holder.Logger.Debugf("verifying read of shard %d of index %s: %s", shard, index, err)
+			}
+		}(shard, c.Clone())
+	}
+}
+
+// verifyRead runs a call on a shard on two replicas and compares the
+// results. A mismatch is read again, as a write may have been applied to
+// one replica but not yet to the other, then reported and optionally
+// repaired.
+func (e *executor) verifyRead(index string, shard uint64, c *pql.Call, opt *execOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), readVerifyTimeout)
+	defer cancel()
+
+	nodes := e.Cluster.preferredShardNodes(index, shard)
+	if len(nodes) < 2 {
+		return nil
+	}
+	rand.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+	nodes = nodes[:2]
+
+	var results [2]interface{}
+	for attempt := 0; attempt < 2; attempt++ {
+		errs := make(chan error, len(nodes))
+		for i, node := range nodes {
+			go func(i int, node *Node) {
+				var err error
+				results[i], err = e.verifyExec(ctx, node, index, c, shard, opt)
+				errs <- errors.Wrapf(err, "node %s", node.ID)
+			}(i, node)
+		}
+		for range nodes {
+			if err := <-errs; err != nil {
+				return err
+			}
+		}
+		if resultsEqual(results[0], results[1]) {
+			e.Holder.Stats.Count("readVerified", 1, 1.0)
+			return nil
+		}
+	}
+
+	fields := callFields(c)
+	e.Holder.Stats.CountWithCustomTags("readVerifyMismatch", 1, 1.0, []string{"index:" + index, fmt.Sprintf("shard:%d", shard)})
+	e.Holder.Logger.Printf("replicas returned different results: index=%s shard=%d fields=%v query=%s node %s=%s node %s=%s",
+		index, shard, fields, c, nodes[0].ID, describeResult(results[0]), nodes[1].ID, describeResult(results[1]))
+
+	if !e.verifier.opt.Repair || e.verifier.repair == nil || len(fields) == 0 {
+		return nil
+	}
+	e.Holder.Stats.CountWithCustomTags("readRepair", 1, 1.0, []string{"index:" + index})
+	return errors.Wrap(e.verifier.repair(index, fields, shard, nodes[0]), "repairing")
+}
+
+// verifyExec runs a call on a single shard on a node.
+func (e *executor) verifyExec(ctx context.Context, node *Node, index string, c *pql.Call, shard uint64, opt *execOptions) (interface{}, error) {
+	if node.ID == e.Node.ID {
+		return e.executeCall(ctx, index, c.Clone(), []uint64{shard}, opt)
+	}
+	results, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, []uint64{shard}, opt)
+	if err != nil {
+		return nil, err
+	} else if len(results) == 0 {
+		return nil, errors.New("no results")
+	}
+	return results[0], nil
+}
+
+// resultsEqual returns true if two results of a verifiable call are equal.
+func resultsEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case *Row:
+		b, ok := b.(*Row)
+		if !ok {
+			return false
+		}
+		if a == nil || b == nil {
+			return a == b || (a == nil && !b.Any()) || (b == nil && !a.Any())
+		}
+		return !a.Xor(b).Any()
+	default:
+		return a == b
+	}
+}
+
+// describeResult returns a short description of a result of a verifiable
+// call for logging.
+func describeResult(v interface{}) string {
+	if r, ok := v.(*Row); ok {
+		if r == nil {
+			return "row(0)"
+		}
+		return fmt.Sprintf("row(%d)", r.Count())
+	}
+	return fmt.Sprint(v)
+}
+
+// repairShard synchronizes the fragments of fields in a shard with their
+// replicas. A node which doesn't own the shard asks owner to.
+func (s *Server) repairShard(index string, fields []string, shard uint64, owner *Node) error {
+	if !s.cluster.ownsShard(s.nodeID, index, shard) {
+		return s.cluster.sendTo(owner, &SyncShardMessage{Index: index, Fields: fields, Shard: shard})
+	}
+	return s.syncShard(index, fields, shard)
+}
+
+// syncShard synchronizes the local fragments of fields in a shard with
+// their replicas.
+func (s *Server) syncShard(index string, fields []string, shard uint64) error {
+	for _, name := range fields {
+		f := s.holder.Field(index, name)
+		if f == nil {
+			continue
+		}
+		for _, v := range f.views() {
+			if v.Fragment(shard) == nil {
+				continue
+			}
+			if err := s.syncer.syncFragment(index, name, v.name, shard); err != nil {
+				return errors.Wrapf(err, "syncing fragment %s/%s/%s/%d", index, name, v.name, shard)
+			}
+		}
+	}
+	return nil
+}
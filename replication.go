@@ -0,0 +1,697 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/logger"
+	"github.com/pilosa/pilosa/v2/roaring"
+	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultReplicationInterval is the default time between the passes
+	// of replication.
+	defaultReplicationInterval = time.Second
+
+	// defaultReplicationBatchSize is the default number of fragments
+	// shipped by a pass of replication.
+	defaultReplicationBatchSize = 100
+
+	// defaultReplicationReconcileInterval is the default time between the
+	// comparisons of the fragments of the fields which don't track changes.
+	defaultReplicationReconcileInterval = 10 * time.Minute
+
+	// replicationChangesLimit bounds the number of changes read from the
+	// change stream of a field on a node by a pass of replication.
+	replicationChangesLimit = 10000
+)
+
+// ErrReplicationDisabled is returned when the replication status is
+// requested from a server which doesn't replicate to another cluster.
+var ErrReplicationDisabled = errors.New("replication is not enabled")
+
+// ReplicationOptions configures the asynchronous replication of a cluster,
+// the primary, to another cluster, usually for disaster recovery. The
+// coordinator of the primary ships the schema and the changed fragments to
+// the other cluster, whose indexes are made read-only.
+type ReplicationOptions struct {
+	// Target is the URI of a node of the cluster replicated to.
+	Target *URI
+
+	// Client sends requests to the cluster replicated to. Its default
+	// node must be Target. Replication is disabled if it's nil.
+	Client InternalClient
+
+	// Interval is the time between the passes of replication.
+	Interval time.Duration
+
+	// BatchSize is the maximum number of fragments shipped by a pass.
+	BatchSize int
+
+	// ReconcileInterval is the time between the comparisons of every
+	// fragment of the fields which don't track changes, since there are no
+	// changes to replicate them from.
+	ReconcileInterval time.Duration
+}
+
+// ReplicationStatus is the state of the replication of a cluster to
+// another cluster, as known by the coordinator which replicates it.
+type ReplicationStatus struct {
+	Target string `json:"target"`
+
+	// Active is true on the node which replicates the cluster, its
+	// coordinator.
+	Active bool `json:"active"`
+
+	// Bootstrapped is true once every fragment which existed when the
+	// replication started was copied to the other cluster.
+	Bootstrapped bool `json:"bootstrapped"`
+
+	// Pending is the number of fragments waiting to be shipped, and Lag
+	// the time, in seconds, since the oldest of their changes.
+	Pending int     `json:"pending"`
+	Lag     float64 `json:"lag"`
+
+	// Fragments are the high-water marks of the fragments shipped.
+	Fragments []ReplicationMark `json:"fragments"`
+
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ReplicationMark is the replication high-water mark of a fragment: every
+// change of the fragment made before the mark was shipped to the other
+// cluster.
+type ReplicationMark struct {
+	Index string    `json:"index"`
+	Field string    `json:"field"`
+	View  string    `json:"view"`
+	Shard uint64    `json:"shard"`
+	Mark  time.Time `json:"mark"`
+
+	// PendingSince is the time of the oldest change of the fragment which
+	// wasn't shipped yet, if any.
+	PendingSince *time.Time `json:"pendingSince,omitempty"`
+}
+
+// replicationKey identifies a fragment in the cluster.
+type replicationKey struct {
+	index string
+	field string
+	view  string
+	shard uint64
+}
+
+// replicationCursor identifies the change stream of a field on a node.
+type replicationCursor struct {
+	node  string
+	index string
+	field string
+}
+
+// replicationPending is a fragment waiting to be shipped.
+type replicationPending struct {
+	since     time.Time
+	bootstrap bool
+}
+
+// replicator ships the schema and the fragments of the cluster to another
+// cluster. It runs on every node, but only the coordinator replicates; a
+// node which becomes coordinator starts by comparing every fragment.
+//
+// Changed fragments are found by tailing the change streams of the fields
+// of every node, and fragments of fields which don't track changes are
+// compared periodically. A fragment is shipped by comparing the checksums
+// of its blocks on a primary owner with those on each owner in the other
+// cluster, and by importing the bits which differ into those owners, so the
+// primary always wins. Fragments are shipped one at a time, each with its
+// bits as of when it was read, so changes are applied in order.
+type replicator struct {
+	opt     ReplicationOptions
+	holder  *Holder
+	cluster *cluster
+	client  InternalClient
+	logger  logger.Logger
+	stats   stats.StatsClient
+
+	mu sync.Mutex
+
+	// Whether this node replicates, and whether it copied the fragments
+	// which existed when it started.
+	active       bool
+	bootstrapped bool
+
+	// Schema generation last shipped, and time of the last comparison of
+	// the fields which don't track changes.
+	generation    uint64
+	lastReconcile time.Time
+
+	cursors map[replicationCursor]uint64
+	pending map[replicationKey]replicationPending
+	marks   map[replicationKey]time.Time
+	lastErr error
+}
+
+func newReplicator(opt ReplicationOptions) *replicator {
+	if opt.Interval <= 0 {
+		opt.Interval = defaultReplicationInterval
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = defaultReplicationBatchSize
+	}
+	if opt.ReconcileInterval <= 0 {
+		opt.ReconcileInterval = defaultReplicationReconcileInterval
+	}
+	return &replicator{
+		opt:     opt,
+		logger:  logger.NopLogger,
+		stats:   stats.NopStatsClient,
+		cursors: make(map[replicationCursor]uint64),
+		pending: make(map[replicationKey]replicationPending),
+		marks:   make(map[replicationKey]time.Time),
+	}
+}
+
+// enabled returns true if the replicator has a cluster to replicate to.
+func (r *replicator) enabled() bool {
+	return r.opt.Client != nil && r.opt.Target != nil
+}
+
+// run replicates the cluster each interval while this node is its
+// coordinator, until closing is closed.
+func (r *replicator) run(closing <-chan struct{}) {
+	if !r.enabled() {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-closing
+		cancel()
+	}()
+
+	ticker := time.NewTicker(r.opt.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case <-ticker.C:
+		}
+
+		if !r.cluster.isCoordinator() {
+			r.reset()
+			continue
+		} else if r.cluster.State() != ClusterStateNormal {
+			continue
+		}
+		if err := r.pass(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Printf("replication to %s: %v", r.opt.Target, err)
+			r.setErr(err)
+		}
+	}
+}
+
+// reset forgets the state of the replication, when this node stops being
+// the coordinator. It starts over if it becomes the coordinator again.
+func (r *replicator) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.active {
+		return
+	}
+	r.active, r.bootstrapped = false, false
+	r.generation, r.lastReconcile = 0, time.Time{}
+	r.cursors = make(map[replicationCursor]uint64)
+	r.pending = make(map[replicationKey]replicationPending)
+	r.lastErr = nil
+}
+
+func (r *replicator) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastErr = err
+}
+
+// pass ships the schema if it changed, collects the changed fragments, and
+// ships up to a batch of them.
+func (r *replicator) pass(ctx context.Context) error {
+	r.mu.Lock()
+	start := !r.active
+	r.active = true
+	r.mu.Unlock()
+
+	if err := r.replicateSchema(ctx); err != nil {
+		return errors.Wrap(err, "replicating schema")
+	}
+
+	now := time.Now()
+	if start {
+		// Every fragment is compared when the replication starts; the
+		// change streams are then read from their start, since the
+		// changes before the comparison are harmless to ship again.
+		r.logger.Printf("bootstrapping replication to %s", r.opt.Target)
+		r.markAll(now, true, func(f *Field) bool { return true })
+		r.mu.Lock()
+		r.lastReconcile = now
+		r.mu.Unlock()
+	} else if now.Sub(r.lastReconcileTime()) >= r.opt.ReconcileInterval {
+		r.markAll(now, false, func(f *Field) bool { return f.changes == nil })
+		r.mu.Lock()
+		r.lastReconcile = now
+		r.mu.Unlock()
+	}
+
+	if err := r.tail(ctx); err != nil {
+		return errors.Wrap(err, "reading changes")
+	}
+	return r.ship(ctx)
+}
+
+func (r *replicator) lastReconcileTime() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastReconcile
+}
+
+// replicateSchema ships the schema of the cluster, with every index made
+// read-only, if it changed since it was last shipped. Indexes and fields
+// which no longer exist are deleted from the other cluster.
+func (r *replicator) replicateSchema(ctx context.Context) error {
+	generation := r.holder.SchemaGeneration()
+	r.mu.Lock()
+	shipped := r.active && r.generation == generation && generation != 0
+	r.mu.Unlock()
+	if shipped {
+		return nil
+	}
+
+	indexes := r.holder.limitedSchema()
+	for _, ii := range indexes {
+		ii.Options.ReadOnly = true
+	}
+	if err := r.opt.Client.PostSchema(ctx, r.opt.Target, &Schema{Indexes: indexes}, false); err != nil {
+		return errors.Wrap(err, "posting schema")
+	}
+
+	target, err := r.opt.Client.SchemaNode(ctx, r.opt.Target)
+	if err != nil {
+		return errors.Wrap(err, "getting schema")
+	}
+	for _, ii := range target {
+		idx := r.holder.Index(ii.Name)
+		if idx == nil {
+			if err := r.opt.Client.DeleteIndex(ctx, ii.Name); err != nil {
+				return errors.Wrapf(err, "deleting index %s", ii.Name)
+			}
+			continue
+		}
+		for _, fi := range ii.Fields {
+			if idx.Field(fi.Name) == nil {
+				if err := r.opt.Client.DeleteField(ctx, ii.Name, fi.Name); err != nil {
+					return errors.Wrapf(err, "deleting field %s/%s", ii.Name, fi.Name)
+				}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.generation = generation
+	for k := range r.marks {
+		if r.holder.Field(k.index, k.field) == nil {
+			delete(r.marks, k)
+		}
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// replicatedFields returns the fields whose fragments are replicated. Int
+// and decimal fields aren't, since their views can't be imported as bits.
+func (r *replicator) replicatedFields() []*Field {
+	var fields []*Field
+	for _, idx := range r.holder.Indexes() {
+		for _, f := range idx.Fields() {
+			switch f.Type() {
+			case FieldTypeSet, FieldTypeTime, FieldTypeMutex, FieldTypeBool:
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// markAll marks every fragment of the fields matched by fn as pending.
+func (r *replicator) markAll(since time.Time, bootstrap bool, fn func(f *Field) bool) {
+	for _, f := range r.replicatedFields() {
+		if fn(f) {
+			r.markField(f, since, bootstrap)
+		}
+	}
+}
+
+// markField marks every fragment of f in the cluster as pending.
+func (r *replicator) markField(f *Field, since time.Time, bootstrap bool) {
+	shards := f.AvailableShards().Slice()
+	for _, v := range f.views() {
+		for _, shard := range shards {
+			r.markPending(replicationKey{index: f.index, field: f.name, view: v.name, shard: shard}, since, bootstrap)
+		}
+	}
+}
+
+// markPending marks a fragment as pending, keeping the time of its oldest
+// change which wasn't shipped.
+func (r *replicator) markPending(k replicationKey, since time.Time, bootstrap bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pending[k]
+	if !ok || since.Before(p.since) {
+		p.since = since
+	}
+	p.bootstrap = p.bootstrap || bootstrap
+	r.pending[k] = p
+}
+
+// tail reads the changes of the fields which track changes on every node,
+// and marks the fragments they changed as pending. A gap in the changes
+// marks every fragment of the field.
+func (r *replicator) tail(ctx context.Context) error {
+	nodes := r.cluster.Nodes()
+	for _, f := range r.replicatedFields() {
+		if f.changes == nil {
+			continue
+		}
+		for _, node := range nodes {
+			cur := replicationCursor{node: node.ID, index: f.index, field: f.name}
+			r.mu.Lock()
+			since := r.cursors[cur]
+			r.mu.Unlock()
+
+			var changes []FieldChange
+			var next uint64
+			var gap bool
+			if node.ID == r.cluster.Node.ID {
+				changes, next, gap, _ = f.changes.read(since, replicationChangesLimit)
+			} else {
+				fc, err := r.client.FieldChanges(ctx, &node.URI, f.index, f.name, since, replicationChangesLimit)
+				if err != nil {
+					return errors.Wrapf(err, "reading changes of %s/%s on node %s", f.index, f.name, node.ID)
+				} else if fc == nil {
+					continue
+				}
+				changes, next, gap = fc.Changes, fc.Next, fc.Gap
+			}
+
+			if gap {
+				r.markField(f, time.Now(), false)
+			}
+			for _, c := range changes {
+				r.markPending(replicationKey{index: f.index, field: f.name, view: c.View, shard: c.Shard}, c.Time, false)
+			}
+			r.mu.Lock()
+			r.cursors[cur] = next
+			r.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// ship ships up to a batch of the pending fragments, oldest changes first.
+func (r *replicator) ship(ctx context.Context) error {
+	r.mu.Lock()
+	keys := make([]replicationKey, 0, len(r.pending))
+	since := make(map[replicationKey]time.Time, len(r.pending))
+	for k, p := range r.pending {
+		keys = append(keys, k)
+		since[k] = p.since
+	}
+	r.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		si, sj := since[keys[i]], since[keys[j]]
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		return replicationKeyLess(keys[i], keys[j])
+	})
+	if len(keys) > r.opt.BatchSize {
+		keys = keys[:r.opt.BatchSize]
+	}
+
+	var shipped int
+	var lastErr error
+	for _, k := range keys {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		mark := time.Now().UTC()
+		r.mu.Lock()
+		p := r.pending[k]
+		delete(r.pending, k)
+		r.mu.Unlock()
+
+		if err := r.replicateFragment(ctx, k); err != nil {
+			lastErr = errors.Wrapf(err, "shipping %s/%s/%s/%d", k.index, k.field, k.view, k.shard)
+			r.markPending(k, p.since, p.bootstrap)
+			continue
+		}
+		shipped++
+		if r.holder.Field(k.index, k.field) != nil {
+			r.mu.Lock()
+			r.marks[k] = mark
+			r.mu.Unlock()
+		}
+	}
+
+	r.mu.Lock()
+	if !r.bootstrapped {
+		r.bootstrapped = true
+		for _, p := range r.pending {
+			if p.bootstrap {
+				r.bootstrapped = false
+				break
+			}
+		}
+		if r.bootstrapped {
+			r.logger.Printf("bootstrapped replication to %s", r.opt.Target)
+		}
+	}
+	r.lastErr = lastErr
+	r.mu.Unlock()
+
+	r.stats.Count("replicationFragments", int64(shipped), 1.0)
+	if st := r.status(); st != nil {
+		r.stats.Gauge("replicationLag", st.Lag, 1.0)
+		r.stats.Gauge("replicationPending", float64(st.Pending), 1.0)
+	}
+	return lastErr
+}
+
+// replicateFragment makes a fragment on each of its owners in the other
+// cluster equal to the fragment on one of its owners in this cluster.
+func (r *replicator) replicateFragment(ctx context.Context, k replicationKey) error {
+	idx := r.holder.Index(k.index)
+	if idx == nil || idx.Field(k.field) == nil {
+		// Deleted since it changed; the schema deletes it.
+		return nil
+	}
+	shardWidth := idx.ShardWidth()
+
+	// Read the checksums of the blocks of a primary owner.
+	var src *Node
+	var srcBlocks []FragmentBlock
+	var err error
+	for _, node := range r.cluster.shardNodes(k.index, k.shard) {
+		srcBlocks, err = r.client.FragmentBlocks(ctx, &node.URI, k.index, k.field, k.view, k.shard)
+		if err == ErrFragmentNotFound {
+			srcBlocks, err = nil, nil
+		}
+		if err == nil {
+			src = node
+			break
+		}
+	}
+	if src == nil {
+		return errors.Wrap(err, "reading blocks")
+	}
+
+	dsts, err := r.opt.Client.FragmentNodes(ctx, k.index, k.shard)
+	if err != nil {
+		return errors.Wrap(err, "getting owners")
+	}
+	for _, dst := range dsts {
+		dstBlocks, err := r.opt.Client.FragmentBlocks(ctx, &dst.URI, k.index, k.field, k.view, k.shard)
+		if err != nil && err != ErrFragmentNotFound {
+			return errors.Wrapf(err, "reading blocks of %s", dst.ID)
+		}
+
+		set, clear := roaring.NewBitmap(), roaring.NewBitmap()
+		for _, id := range differentBlocks(srcBlocks, dstBlocks) {
+			want, err := r.blockPositions(ctx, r.client, &src.URI, k, id, srcBlocks, shardWidth)
+			if err != nil {
+				return errors.Wrap(err, "reading block")
+			}
+			have, err := r.blockPositions(ctx, r.opt.Client, &dst.URI, k, id, dstBlocks, shardWidth)
+			if err != nil {
+				return errors.Wrapf(err, "reading block of %s", dst.ID)
+			}
+			// The bits are added again, since a difference may keep empty
+			// containers, which the import can't read.
+			set.DirectAddN(want.Difference(have).Slice()...)
+			clear.DirectAddN(have.Difference(want).Slice()...)
+		}
+
+		// Clear before setting, so that a mutex never has two rows.
+		for _, op := range []struct {
+			clear bool
+			bits  *roaring.Bitmap
+		}{{true, clear}, {false, set}} {
+			if !op.bits.Any() {
+				continue
+			}
+			var buf bytes.Buffer
+			if _, err := op.bits.WriteTo(&buf); err != nil {
+				return errors.Wrap(err, "encoding bits")
+			}
+			req := &ImportRoaringRequest{
+				Clear: op.clear,
+				Views: map[string][]byte{importRoaringViewName(k.view): buf.Bytes()},
+			}
+			if err := r.opt.Client.ImportRoaring(ctx, &dst.URI, k.index, k.field, k.shard, true, req); err != nil {
+				return errors.Wrapf(err, "importing to %s", dst.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// blockPositions returns the positions of the bits of a block of a
+// fragment, relative to its shard, read from the node at uri. Blocks which
+// aren't in blocks are empty.
+func (r *replicator) blockPositions(ctx context.Context, client InternalClient, uri *URI, k replicationKey, id int, blocks []FragmentBlock, shardWidth uint64) (*roaring.Bitmap, error) {
+	b := roaring.NewBitmap()
+	i := sort.Search(len(blocks), func(i int) bool { return blocks[i].ID >= id })
+	if i == len(blocks) || blocks[i].ID != id {
+		return b, nil
+	}
+	rowIDs, columnIDs, err := client.BlockData(ctx, uri, k.index, k.field, k.view, k.shard, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rowIDs {
+		b.DirectAdd(rowIDs[i]*shardWidth + columnIDs[i]%shardWidth)
+	}
+	return b, nil
+}
+
+// differentBlocks returns the IDs of the blocks which are in only one of
+// the sorted lists a and b, or whose checksums differ.
+func differentBlocks(a, b []FragmentBlock) []int {
+	var ids []int
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i].ID < b[j].ID):
+			ids = append(ids, a[i].ID)
+			i++
+		case i == len(a) || b[j].ID < a[i].ID:
+			ids = append(ids, b[j].ID)
+			j++
+		default:
+			if !bytes.Equal(a[i].Checksum, b[j].Checksum) {
+				ids = append(ids, a[i].ID)
+			}
+			i++
+			j++
+		}
+	}
+	return ids
+}
+
+// importRoaringViewName returns the name by which a roaring import refers
+// to a view.
+func importRoaringViewName(view string) string {
+	if view == viewStandard {
+		return ""
+	}
+	return strings.TrimPrefix(view, viewStandard+"_")
+}
+
+func replicationKeyLess(a, b replicationKey) bool {
+	if a.index != b.index {
+		return a.index < b.index
+	} else if a.field != b.field {
+		return a.field < b.field
+	} else if a.view != b.view {
+		return a.view < b.view
+	}
+	return a.shard < b.shard
+}
+
+// status returns the state of the replication, or nil if it's disabled.
+func (r *replicator) status() *ReplicationStatus {
+	if !r.enabled() {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st := &ReplicationStatus{
+		Target:       r.opt.Target.String(),
+		Active:       r.active,
+		Bootstrapped: r.bootstrapped,
+		Pending:      len(r.pending),
+		Fragments:    []ReplicationMark{},
+	}
+	if r.lastErr != nil {
+		st.LastError = r.lastErr.Error()
+	}
+
+	keys := make([]replicationKey, 0, len(r.marks)+len(r.pending))
+	for k := range r.marks {
+		keys = append(keys, k)
+	}
+	var oldest time.Time
+	for k, p := range r.pending {
+		if _, ok := r.marks[k]; !ok {
+			keys = append(keys, k)
+		}
+		if oldest.IsZero() || p.since.Before(oldest) {
+			oldest = p.since
+		}
+	}
+	if !oldest.IsZero() {
+		st.Lag = time.Since(oldest).Seconds()
+	}
+	sort.Slice(keys, func(i, j int) bool { return replicationKeyLess(keys[i], keys[j]) })
+
+	for _, k := range keys {
+		m := ReplicationMark{Index: k.index, Field: k.field, View: k.view, Shard: k.shard, Mark: r.marks[k]}
+		if p, ok := r.pending[k]; ok {
+			since := p.since.UTC()
+			m.PendingSince = &since
+		}
+		st.Fragments = append(st.Fragments, m)
+	}
+	return st
+}
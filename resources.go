@@ -0,0 +1,196 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultResourceInterval is the default interval at which a node samples
+// its resources.
+const defaultResourceInterval = 30 * time.Second
+
+// NodeResources is a sample of the resources of a node. Samples are
+// replaced rather than modified, so they can be shared between copies of a
+// node.
+type NodeResources struct {
+	// Bytes available on the file system of the data directory, and bytes
+	// used by the data directory.
+	DiskFree uint64 `json:"diskFree"`
+	DataSize uint64 `json:"dataSize"`
+
+	// Resident set size of the process in bytes.
+	MemRSS uint64 `json:"memRSS"`
+
+	// System load average over the last minute.
+	Load float64 `json:"load"`
+
+	// Number of fragments open in the holder.
+	Fragments uint64 `json:"fragments"`
+
+	SampledAt time.Time `json:"sampledAt"`
+}
+
+// newerResources returns the most recent of two samples, either of which
+// may be nil.
+func newerResources(a, b *NodeResources) *NodeResources {
+	if a == nil || (b != nil && b.SampledAt.After(a.SampledAt)) {
+		return b
+	}
+	return a
+}
+
+// NodeResourcesMessage is an internal message sent by a node to the
+// coordinator with a sample of its resources.
+type NodeResourcesMessage struct {
+	NodeID    string
+	Resources *NodeResources
+}
+
+// sampleResources returns a sample of the resources of this node. Resources
+// which can't be measured are left zero.
+func (s *Server) sampleResources() *NodeResources {
+	r := &NodeResources{SampledAt: time.Now().UTC()}
+	var err error
+	if r.DiskFree, err = s.systemInfo.DiskFree(s.holder.Path); err != nil {
+		s.logger.Debugf("sampling free disk: %s", err)
+	}
+	if size, err := dirSize(s.holder.Path); err != nil {
+		s.logger.Debugf("sampling data size: %s", err)
+	} else {
+		r.DataSize = uint64(size)
+	}
+	if r.MemRSS, err = s.systemInfo.MemRSS(); err != nil {
+		s.logger.Debugf("sampling memory: %s", err)
+	}
+	if r.Load, err = s.systemInfo.LoadAverage(); err != nil {
+		s.logger.Debugf("sampling load: %s", err)
+	}
+	r.Fragments = s.holder.fragmentCount()
+	return r
+}
+
+// monitorResources periodically samples the resources of this node and
+// reports them to the coordinator.
+func (s *Server) monitorResources() {
+	if s.resourceInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.resourceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.cluster.setResources(s.sampleResources()); err != nil {
+			s.logger.Printf("reporting resources: %s", err)
+		}
+	}
+}
+
+// fragmentCount returns the number of fragments open in the holder.
+func (h *Holder) fragmentCount() uint64 {
+	var n uint64
+	for _, index := range h.Indexes() {
+		for _, field := range index.Fields() {
+			for _, view := range field.views() {
+				n += uint64(len(view.allFragments()))
+			}
+		}
+	}
+	return n
+}
+
+// setResources sets the resources of this node and sends them to the
+// coordinator, which includes them in the cluster status.
+func (c *cluster) setResources(r *NodeResources) error {
+	c.mu.Lock()
+	c.Node.Resources = r
+	c.unprotectedSetNodeResources(c.Node.ID, r)
+	var coord *Node
+	if !c.Static && !c.unprotectedIsCoordinator() {
+		coord = c.unprotectedCoordinatorNode()
+	}
+	c.mu.Unlock()
+
+	if coord == nil {
+		return nil
+	}
+	return c.sendTo(coord, &NodeResourcesMessage{NodeID: c.Node.ID, Resources: r})
+}
+
+// receiveResources sets the resources of another node.
+func (c *cluster) receiveResources(nodeID string, r *NodeResources) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nodeID == c.Node.ID {
+		return
+	}
+	c.unprotectedSetNodeResources(nodeID, r)
+}
+
+// unprotectedSetNodeResources sets the resources of a node in the cluster's
+// node list, unless they are older than the current sample.
+func (c *cluster) unprotectedSetNodeResources(nodeID string, r *NodeResources) {
+	for _, n := range c.nodes {
+		if n.ID == nodeID {
+			n.Resources = newerResources(n.Resources, r)
+		}
+	}
+}
+
+// unprotectedCheckJoinDisk returns an error if checkJoinDisk is set and the
+// free disk of a joining node is below the estimated size of the fragments
+// it would receive. Nodes which haven't reported their resources are
+// accepted.
+func (c *cluster) unprotectedCheckJoinDisk(node *Node, sources []*ResizeSource) error {
+	if !c.checkJoinDisk || node.Resources == nil || len(sources) == 0 {
+		return nil
+	}
+
+	var incoming uint64
+	for _, src := range sources {
+		incoming += c.unprotectedFragmentSize(src.Node)
+	}
+	if incoming > node.Resources.DiskFree {
+		return fmt.Errorf("node %s has %d bytes of free disk, but would receive about %d bytes in %d fragments", node.ID, node.Resources.DiskFree, incoming, len(sources))
+	}
+	return nil
+}
+
+// unprotectedFragmentSize returns the estimated size of a fragment of a
+// node, which is the average size of its fragments, or of the fragments of
+// the cluster if the node hasn't reported its resources.
+func (c *cluster) unprotectedFragmentSize(node *Node) uint64 {
+	if r := node.Resources; r != nil && r.Fragments > 0 {
+		return r.DataSize / r.Fragments
+	}
+	var size, n uint64
+	for _, other := range c.nodes {
+		if r := other.Resources; r != nil {
+			size += r.DataSize
+			n += r.Fragments
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return size / n
+}
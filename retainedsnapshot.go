@@ -0,0 +1,392 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/lru"
+	"github.com/pilosa/pilosa/v2/roaring"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultRetainedSnapshotInterval is the default interval between the
+	// retained snapshots of a field.
+	defaultRetainedSnapshotInterval = 24 * time.Hour
+
+	// defaultRetainedFragmentCacheSize is the default number of fragments
+	// of retained snapshots kept loaded for queries.
+	defaultRetainedFragmentCacheSize = 64
+
+	// retainedSnapshotCheckInterval is the interval at which fields are
+	// checked for snapshots to retain or purge.
+	retainedSnapshotCheckInterval = time.Minute
+
+	// retainedSnapshotsDir is the directory of a field which holds its
+	// retained snapshots, one directory per snapshot.
+	retainedSnapshotsDir = "snapshots"
+
+	// retainedSnapshotTimeFormat is the format of the directory name of a
+	// retained snapshot.
+	retainedSnapshotTimeFormat = "20060102T150405.000000000Z"
+)
+
+// RetainedSnapshot describes a retained snapshot of the fragments of a field
+// on a node.
+type RetainedSnapshot struct {
+	Time time.Time `json:"time"`
+	Size int64     `json:"size"`
+}
+
+// RetainedSnapshotsInfo describes the retained snapshots of a field on a node,
+// and the disk space they use in addition to the live data of the field.
+type RetainedSnapshotsInfo struct {
+	Retention string             `json:"retention"`
+	Snapshots []RetainedSnapshot `json:"snapshots"`
+	Size      int64              `json:"size"`
+	LiveSize  int64              `json:"liveSize"`
+}
+
+// retainedSnapshotsPath returns the directory of the retained snapshots of
+// the field.
+func (f *Field) retainedSnapshotsPath() string {
+	return filepath.Join(f.path, retainedSnapshotsDir)
+}
+
+// retainedSnapshotPath returns the directory of the retained snapshot taken
+// at t.
+func (f *Field) retainedSnapshotPath(t time.Time) string {
+	return filepath.Join(f.retainedSnapshotsPath(), t.UTC().Format(retainedSnapshotTimeFormat))
+}
+
+// RetainedSnapshots returns the retained snapshots of the field, oldest
+// first, and their disk usage.
+func (f *Field) RetainedSnapshots() (*RetainedSnapshotsInfo, error) {
+	f.retainedMu.Lock()
+	defer f.retainedMu.Unlock()
+
+	times, err := f.listRetainedSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &RetainedSnapshotsInfo{Snapshots: make([]RetainedSnapshot, 0, len(times))}
+	if d := f.Options().SnapshotRetention; d > 0 {
+		info.Retention = d.String()
+	}
+	for _, t := range times {
+		n, err := dirSize(f.retainedSnapshotPath(t))
+		if err != nil {
+			return nil, errors.Wrap(err, "measuring snapshot")
+		}
+		info.Snapshots = append(info.Snapshots, RetainedSnapshot{Time: t, Size: n})
+		info.Size += n
+	}
+	if info.LiveSize, err = dirSize(filepath.Join(f.path, "views")); err != nil {
+		return nil, errors.Wrap(err, "measuring views")
+	}
+	return info, nil
+}
+
+// listRetainedSnapshots returns the times of the retained snapshots of the
+// field, oldest first. The directory is only read the first time.
+func (f *Field) listRetainedSnapshots() ([]time.Time, error) {
+	if f.retainedListed {
+		return f.retainedTimes, nil
+	}
+
+	fis, err := ioutil.ReadDir(f.retainedSnapshotsPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "reading snapshots")
+	}
+	var times []time.Time
+	for _, fi := range fis {
+		// Snapshots which were being written when the node stopped have a
+		// name which doesn't parse.
+		t, err := time.Parse(retainedSnapshotTimeFormat, fi.Name())
+		if err != nil || !fi.IsDir() {
+			continue
+		}
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	f.retainedTimes, f.retainedListed = times, true
+	return times, nil
+}
+
+// retainedSnapshotAt returns the time of the newest retained snapshot taken
+// at or before t.
+func (f *Field) retainedSnapshotAt(t time.Time) (time.Time, bool) {
+	f.retainedMu.Lock()
+	defer f.retainedMu.Unlock()
+
+	times, err := f.listRetainedSnapshots()
+	if err != nil {
+		f.logger.Printf("listing retained snapshots of field %s: %s", f.name, err)
+		return time.Time{}, false
+	}
+	i := sort.Search(len(times), func(i int) bool { return times[i].After(t) })
+	if i == 0 {
+		return time.Time{}, false
+	}
+	return times[i-1], true
+}
+
+// retainSnapshot writes a snapshot of every fragment of the field taken at
+// now, which is retained until it is purged.
+func (f *Field) retainSnapshot(now time.Time) error {
+	f.retainedMu.Lock()
+	defer f.retainedMu.Unlock()
+
+	times, err := f.listRetainedSnapshots()
+	if err != nil {
+		return err
+	}
+
+	// The snapshot is written to a temporary directory, so that a snapshot
+	// which is interrupted is never read.
+	path := f.retainedSnapshotPath(now)
+	tempPath := path + tempExt
+	if err := os.RemoveAll(tempPath); err != nil {
+		return errors.Wrap(err, "removing temporary directory")
+	}
+	for _, view := range f.views() {
+		for _, frag := range view.allFragments() {
+			if err := frag.writeRetainedSnapshot(filepath.Join(tempPath, view.name, strconv.FormatUint(frag.shard, 10))); err != nil {
+				return errors.Wrapf(err, "writing fragment %s/%d", view.name, frag.shard)
+			}
+		}
+	}
+	if err := os.MkdirAll(tempPath, 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	} else if err := os.Rename(tempPath, path); err != nil {
+		return errors.Wrap(err, "renaming directory")
+	}
+
+	f.retainedTimes = append(times, now.UTC().Round(0))
+	return nil
+}
+
+// purgeRetainedSnapshots removes the retained snapshots taken before
+// now minus the retention of the field, and returns how many were removed.
+func (f *Field) purgeRetainedSnapshots(now time.Time) (int, error) {
+	f.retainedMu.Lock()
+	defer f.retainedMu.Unlock()
+
+	times, err := f.listRetainedSnapshots()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := now.Add(-f.Options().SnapshotRetention)
+	n := 0
+	for ; n < len(times) && times[n].Before(cutoff); n++ {
+		if err := os.RemoveAll(f.retainedSnapshotPath(times[n])); err != nil {
+			f.retainedTimes = times[n:]
+			return n, errors.Wrap(err, "removing snapshot")
+		}
+	}
+	f.retainedTimes = times[n:]
+	return n, nil
+}
+
+// writeRetainedSnapshot writes the storage of the fragment to a new file at
+// path. Writes are only blocked while the storage is copied.
+func (f *fragment) writeRetainedSnapshot(path string) error {
+	other, _ := f.readSnapshot()
+	defer f.releaseReadSnapshot()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating file")
+	}
+	defer file.Close()
+
+	bw := bufio.NewWriter(file)
+	if _, err := other.storage.WriteTo(bw); err != nil {
+		return errors.Wrap(err, "writing storage")
+	} else if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "flushing")
+	} else if err := file.Sync(); err != nil {
+		return errors.Wrap(err, "syncing")
+	}
+	return file.Close()
+}
+
+// openRetainedFragment reads the fragment of a retained snapshot at path
+// into memory. The fragment is read-only: it has no op log, and it is not
+// snapshotted. It returns nil if the fragment didn't exist when the snapshot
+// was taken.
+func (v *view) openRetainedFragment(path string, shard uint64) (*fragment, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "reading file")
+	}
+
+	frag := v.newFragment(path, shard)
+	frag.snapshotQueue = nil
+	frag.RowAttrStore = v.rowAttrStore
+	frag.rowCache = &simpleCache{make(map[uint64]*Row)}
+	frag.storage = roaring.NewFileBitmap()
+	if err := frag.storage.UnmarshalBinary(data); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling storage")
+	}
+
+	// The cache isn't retained, so it is rebuilt from the storage.
+	frag.unprotectedRebuildCache()
+	return frag, nil
+}
+
+// retainedFragmentCache keeps the most recently read fragments of retained
+// snapshots loaded.
+type retainedFragmentCache struct {
+	mu    sync.Mutex
+	cache *lru.Cache
+}
+
+func newRetainedFragmentCache(size int) *retainedFragmentCache {
+	return &retainedFragmentCache{cache: lru.New(size)}
+}
+
+// fragment returns the fragment of a retained snapshot at path, loading it
+// with open if it isn't loaded.
+func (c *retainedFragmentCache) fragment(path string, open func() (*fragment, error)) (*fragment, error) {
+	c.mu.Lock()
+	v, ok := c.cache.Get(path)
+	c.mu.Unlock()
+	if ok {
+		return v.(*fragment), nil
+	}
+
+	// Fragments are loaded without holding the lock, so the same fragment
+	// may be loaded twice by concurrent queries.
+	frag, err := open()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.cache.Add(path, frag)
+	c.mu.Unlock()
+	return frag, nil
+}
+
+// retainedFragment returns the fragment read by a query as of asOf: the
+// fragment of the newest snapshot retained at or before asOf, or the live
+// fragment if the field doesn't retain snapshots. It returns nil if the
+// field has no snapshot that old, or if loading the snapshot fails.
+func (h *Holder) retainedFragment(index, field, view string, shard uint64, asOf time.Time) *fragment {
+	f := h.Field(index, field)
+	if f == nil {
+		return nil
+	} else if f.Options().SnapshotRetention <= 0 {
+		return h.fragment(index, field, view, shard)
+	}
+
+	t, ok := f.retainedSnapshotAt(asOf)
+	if !ok {
+		return nil
+	}
+	v := f.view(view)
+	if v == nil {
+		return nil
+	}
+	path := filepath.Join(f.retainedSnapshotPath(t), view, strconv.FormatUint(shard, 10))
+	frag, err := h.retainedFragments.fragment(path, func() (*fragment, error) {
+		h.Stats.Count("retainedSnapshotLoad", 1, 1.0)
+		return v.openRetainedFragment(path, shard)
+	})
+	if err != nil {
+		h.Logger.Printf("ERROR loading retained snapshot %s: %s", path, err)
+		return nil
+	}
+	return frag
+}
+
+// monitorRetainedSnapshots periodically retains a snapshot of the fields
+// which retain snapshots, and purges their expired snapshots.
+func (h *Holder) monitorRetainedSnapshots() {
+	ticker := time.NewTicker(retainedSnapshotCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.closing:
+			return
+		case <-ticker.C:
+			h.retainSnapshots(time.Now())
+		}
+	}
+}
+
+// retainSnapshots retains a snapshot of every field which retains snapshots
+// and whose newest snapshot is older than the snapshot interval, then purges
+// the expired snapshots of those fields.
+func (h *Holder) retainSnapshots(now time.Time) {
+	for _, idx := range h.Indexes() {
+		for _, f := range idx.Fields() {
+			if f.Options().SnapshotRetention <= 0 {
+				continue
+			}
+
+			f.retainedMu.Lock()
+			times, err := f.listRetainedSnapshots()
+			f.retainedMu.Unlock()
+			if err != nil {
+				h.Logger.Printf("ERROR listing retained snapshots of %s/%s: %s", idx.Name(), f.Name(), err)
+				continue
+			}
+			if len(times) == 0 || now.Sub(times[len(times)-1]) >= h.retainedSnapshotInterval {
+				if err := f.retainSnapshot(now); err != nil {
+					h.Logger.Printf("ERROR retaining snapshot of %s/%s: %s", idx.Name(), f.Name(), err)
+				} else {
+					h.Stats.Count("retainedSnapshot", 1, 1.0)
+				}
+			}
+
+			n, err := f.purgeRetainedSnapshots(now)
+			if err != nil {
+				h.Logger.Printf("ERROR purging retained snapshots of %s/%s: %s", idx.Name(), f.Name(), err)
+			}
+			h.Stats.Count("retainedSnapshotPurge", int64(n), 1.0)
+		}
+	}
+}
+
+// queryAsOfKey is the context key for the time as of which a query reads.
+type queryAsOfKey struct{}
+
+// withQueryAsOf returns ctx carrying the time as of which a query reads.
+func withQueryAsOf(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, queryAsOfKey{}, t)
+}
+
+// queryAsOf returns the time as of which the query of ctx reads, if any.
+func queryAsOf(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(queryAsOfKey{}).(time.Time)
+	return t, ok
+}
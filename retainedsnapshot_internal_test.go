@@ -0,0 +1,149 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+)
+
+// Ensure queries as of a past time read the newest retained snapshot taken
+// at or before that time, and that expired snapshots are purged.
+func TestRetainedSnapshots(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := h.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := idx.CreateField("f", OptFieldSnapshotRetention(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := idx.CreateField("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := newExecutor()
+	defer e.Close()
+	e.Holder = h.Holder
+	e.Cluster = NewTestCluster(1)
+	e.Node = e.Cluster.Node
+
+	columns := func(pqlStr string, asOf time.Time) []uint64 {
+		t.Helper()
+		q, err := pql.ParseString(pqlStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := e.Execute(context.Background(), "i", q, nil, &execOptions{AsOf: asOf})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Results[0].(*Row).Columns()
+	}
+
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(24 * time.Hour)
+	for _, col := range []uint64{1, ShardWidth + 2} {
+		if _, err := f.SetBit(10, col, nil); err != nil {
+			t.Fatal(err)
+		} else if _, err := g.SetBit(10, col, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.retainSnapshots(t0)
+
+	// The snapshot interval hasn't elapsed.
+	if _, err := f.SetBit(10, 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	h.retainSnapshots(t0.Add(time.Hour))
+	if info, err := f.RetainedSnapshots(); err != nil {
+		t.Fatal(err)
+	} else if len(info.Snapshots) != 1 || !info.Snapshots[0].Time.Equal(t0) || info.Size == 0 {
+		t.Fatalf("unexpected snapshots: %+v", info)
+	}
+
+	h.retainSnapshots(t1)
+	if _, err := f.ClearBit(10, 1); err != nil {
+		t.Fatal(err)
+	} else if _, err := g.ClearBit(10, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := columns("Row(f=10)", time.Time{}), []uint64{3, ShardWidth + 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("live: got %v, want %v", got, want)
+	}
+	if got, want := columns("Row(f=10)", t0.Add(time.Hour)), []uint64{1, ShardWidth + 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("as of t0: got %v, want %v", got, want)
+	}
+	if got, want := columns("Row(f=10)", t1.Add(time.Hour)), []uint64{1, 3, ShardWidth + 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("as of t1: got %v, want %v", got, want)
+	}
+	if got := columns("Row(f=10)", t0.Add(-time.Hour)); len(got) != 0 {
+		t.Fatalf("before first snapshot: got %v", got)
+	}
+
+	// Fields without retained snapshots are read live.
+	if got, want := columns("Row(g=10)", t0), []uint64{ShardWidth + 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("field without snapshots: got %v, want %v", got, want)
+	}
+
+	// Writes can't read as of a past time.
+	q, _ := pql.ParseString("Set(4, f=10)")
+	if _, err := e.Execute(context.Background(), "i", q, nil, &execOptions{AsOf: t1}); err == nil {
+		t.Fatal("expected error for write as of a past time")
+	} else if _, ok := err.(BadRequestError); !ok {
+		t.Fatalf("expected bad request error, got %#v", err)
+	}
+
+	// The snapshot of t0 expires after the retention.
+	h.retainSnapshots(t0.Add(49 * time.Hour))
+	info, err := f.RetainedSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var times []time.Time
+	for _, s := range info.Snapshots {
+		times = append(times, s.Time)
+	}
+	if want := []time.Time{t1, t0.Add(49 * time.Hour)}; !reflect.DeepEqual(times, want) {
+		t.Fatalf("snapshots after purge: got %v, want %v", times, want)
+	} else if info.Retention != "48h0m0s" {
+		t.Fatalf("unexpected retention: %s", info.Retention)
+	}
+
+	// Snapshots are listed again when the holder is reopened.
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := h.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	f = h.Field("i", "f")
+	if f.Options().SnapshotRetention != 48*time.Hour {
+		t.Fatalf("unexpected retention after reopen: %s", f.Options().SnapshotRetention)
+	} else if at, ok := f.retainedSnapshotAt(t1.Add(time.Hour)); !ok || !at.Equal(t1) {
+		t.Fatalf("unexpected snapshot after reopen: %v, %v", at, ok)
+	}
+}
@@ -29,6 +29,8 @@ type bTreeContainers struct {
 func newBTreeContainers() *bTreeContainers {
 	return &bTreeContainers{
 		tree: treeNew(),
+		// Nothing is cached yet, not even key 0.
+		lastKey: ^uint64(0),
 	}
 }
 
@@ -218,6 +220,11 @@ func (btc *bTreeContainers) Repair() {
 // (new-container, write). If write is true, the container is used to
 // replace the given container.
 func (btc *bTreeContainers) Update(key uint64, fn func(*Container, bool) (*Container, bool)) {
+	// The container may be replaced, so don't keep it cached.
+	if key == btc.lastKey {
+		btc.lastKey = ^uint64(0)
+		btc.lastContainer = nil
+	}
 	btc.tree.Put(key, fn)
 }
 
@@ -225,6 +232,8 @@ func (btc *bTreeContainers) Update(key uint64, fn func(*Container, bool) (*Conta
 // (new-container, write). If write is true, the container is used to
 // replace the given container.
 func (btc *bTreeContainers) UpdateEvery(fn func(uint64, *Container, bool) (*Container, bool)) {
+	btc.lastKey = ^uint64(0)
+	btc.lastContainer = nil
 	e, _ := btc.tree.Seek(0)
 	// currently not handling the error from this, but in practice it has
 	// to be io.EOF.
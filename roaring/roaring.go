@@ -16,6 +16,7 @@
 package roaring
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"hash/fnv"
@@ -223,6 +224,35 @@ func (b *Bitmap) Freeze() *Bitmap {
 	return other
 }
 
+// Snapshot returns a shallow copy of the bitmap which keeps its contents as
+// of now while b is modified. Like Freeze, it shares the containers of b,
+// which are frozen so that writes to b copy them. Mapped containers are not
+// frozen, which would copy their data, but get a new container referring to
+// the same mapped data, so the caller must keep that data mapped as long as
+// the snapshot is used.
+func (b *Bitmap) Snapshot() *Bitmap {
+	if b == nil {
+		return nil
+	}
+
+	other := NewSliceBitmap()
+	citer, _ := b.Containers.Iterator(0)
+	for citer.Next() {
+		k, c := citer.Value()
+		if c.Mapped() && !c.frozen() {
+			shared := *c
+			if c.pointer == &c.data[0] {
+				shared.pointer = &shared.data[0]
+			}
+			c = &shared
+		} else {
+			c = c.Freeze()
+		}
+		other.Containers.Put(k, c)
+	}
+	return other
+}
+
 // Add adds values to the bitmap. TODO(2.0) deprecate - use the more general
 // AddN (though be aware that it modifies 'a' in place).
 func (b *Bitmap) Add(a ...uint64) (changed bool, err error) {
@@ -591,6 +621,77 @@ func (b *Bitmap) IntersectionCount(other *Bitmap) uint64 {
 	return n
 }
 
+// UnionCount returns the number of set bits that would result in a union
+// between b and others. Containers present in a single bitmap are counted
+// directly and overlapping containers are combined in a reusable buffer, so
+// no result containers are allocated.
+func (b *Bitmap) UnionCount(others ...*Bitmap) uint64 {
+	bitmaps := append([]*Bitmap{b}, others...)
+	iters := make([]ContainerIterator, len(bitmaps))
+	keys := make([]uint64, len(bitmaps))
+	containers := make([]*Container, len(bitmaps))
+	for i, bm := range bitmaps {
+		iters[i], _ = bm.Containers.Iterator(0)
+		if iters[i].Next() {
+			keys[i], containers[i] = iters[i].Value()
+		}
+	}
+
+	var n uint64
+	var buf []uint64
+	matched := make([]*Container, 0, len(bitmaps))
+	for {
+		// Find the lowest key among the remaining containers.
+		var key uint64
+		found := false
+		for i, c := range containers {
+			if c != nil && (!found || keys[i] < key) {
+				key, found = keys[i], true
+			}
+		}
+		if !found {
+			return n
+		}
+
+		// Collect the containers with that key and advance their iterators.
+		matched = matched[:0]
+		for i, c := range containers {
+			if c == nil || keys[i] != key {
+				continue
+			}
+			matched = append(matched, c)
+			containers[i] = nil
+			if iters[i].Next() {
+				keys[i], containers[i] = iters[i].Value()
+			}
+		}
+
+		switch len(matched) {
+		case 1:
+			n += uint64(matched[0].N())
+		case 2:
+			n += uint64(matched[0].N() + matched[1].N() - intersectionCount(matched[0], matched[1]))
+		default:
+			if buf == nil {
+				buf = make([]uint64, bitmapN)
+			}
+			n += unionCount(buf, matched)
+		}
+	}
+}
+
+// DifferenceCount returns the number of set bits that would result in the
+// difference of b and other, without allocating the result.
+func (b *Bitmap) DifferenceCount(other *Bitmap) uint64 {
+	return b.Count() - b.IntersectionCount(other)
+}
+
+// XorCount returns the number of set bits that would result in the
+// symmetric difference of b and other, without allocating the result.
+func (b *Bitmap) XorCount(other *Bitmap) uint64 {
+	return b.Count() + other.Count() - 2*b.IntersectionCount(other)
+}
+
 // Intersect returns the intersection of b and other.
 func (b *Bitmap) Intersect(other *Bitmap) *Bitmap {
 	output := NewBitmap()
@@ -1642,6 +1743,90 @@ func (b *Bitmap) SetOps(ops int, opN int) {
 	b.ops, b.opN = ops, opN
 }
 
+// OpLogCompaction is the net effect of the ops logged after the snapshot in
+// a file in Pilosa's roaring format.
+type OpLogCompaction struct {
+	// Number of ops logged and their total bit count.
+	Ops int
+	OpN int
+
+	// Number of ops with the net effect and their total bit count.
+	CompactedOps int
+	CompactedOpN int
+
+	snapshot []byte
+	add      *Bitmap
+	remove   *Bitmap
+}
+
+// CompactOpLog returns the net effect of the ops logged after the snapshot in
+// data, which is in Pilosa's roaring format. Ops which set bits which are
+// cleared by later ops, or clear bits which are set by later ops, have no
+// net effect, and neither have ops which set bits already set, or clear bits
+// already clear.
+func CompactOpLog(data []byte) (*OpLogCompaction, error) {
+	if len(data) < 2 || uint32(binary.LittleEndian.Uint16(data[0:2])) != MagicNumber {
+		return nil, errors.New("op logs are only supported in pilosa roaring format")
+	}
+	snapshot := NewFileBitmap()
+	opsOffset, err := snapshot.unmarshalPilosaRoaringContainers(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling snapshot")
+	}
+	current := snapshot.Clone()
+	if err := current.applyOps(data[opsOffset:]); err != nil {
+		return nil, errors.Wrap(err, "applying ops")
+	}
+
+	c := &OpLogCompaction{
+		Ops:      current.ops,
+		OpN:      current.opN,
+		snapshot: data[:opsOffset],
+		add:      current.Difference(snapshot),
+		remove:   snapshot.Difference(current),
+	}
+	for _, b := range []*Bitmap{c.add, c.remove} {
+		// Empty containers left by the differences aren't encoded
+		// consistently, so drop them.
+		b.removeEmptyContainers()
+		if n := int(b.Count()); n > 0 {
+			c.CompactedOps++
+			c.CompactedOpN += n
+		}
+	}
+	return c, nil
+}
+
+// WriteTo writes the snapshot followed by an op log with the net effect of
+// the ops to w. The snapshot is written unchanged.
+func (c *OpLogCompaction) WriteTo(w io.Writer) (n int64, err error) {
+	nn, err := w.Write(c.snapshot)
+	n += int64(nn)
+	if err != nil {
+		return n, err
+	}
+	for _, o := range []struct {
+		typ opType
+		b   *Bitmap
+	}{{opTypeAddRoaring, c.add}, {opTypeRemoveRoaring, c.remove}} {
+		count := o.b.Count()
+		if count == 0 {
+			continue
+		}
+		var buf bytes.Buffer
+		if _, err := o.b.WriteTo(&buf); err != nil {
+			return n, errors.Wrap(err, "encoding bits")
+		}
+		opr := op{typ: o.typ, opN: int(count), roaring: buf.Bytes()}
+		nn, err := opr.WriteTo(w)
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
 // Info returns stats for the bitmap.
 func (b *Bitmap) Info() bitmapInfo {
 	info := bitmapInfo{
@@ -2819,6 +3004,43 @@ func (c *Container) Clone() (out *Container) {
 	return out
 }
 
+// UnionContainers returns a container holding the values of every container
+// of containers, which aren't modified. Nil containers are empty. The result
+// may be one of containers, so it must be cloned before it's modified.
+func UnionContainers(containers ...*Container) *Container {
+	var out *Container
+	for _, c := range containers {
+		if c.N() == 0 {
+			continue
+		} else if out == nil {
+			out = c
+		} else {
+			out = union(out, c)
+		}
+	}
+	return out
+}
+
+// ContainersEqual reports whether a and b hold the same values, whatever
+// their types. Nil containers are empty.
+func ContainersEqual(a, b *Container) bool {
+	if a.N() != b.N() {
+		return false
+	} else if a.N() == 0 {
+		return true
+	}
+	return union(a, b).N() == a.N()
+}
+
+// EncodedSize returns the number of bytes which c takes in a bitmap
+// written by WriteTo, including its header.
+func (c *Container) EncodedSize() int {
+	if c == nil {
+		return 0
+	}
+	return 16 + c.size()
+}
+
 // WriteTo writes c to w.
 func (c *Container) WriteTo(w io.Writer) (n int64, err error) {
 	if c == nil {
@@ -3068,6 +3290,38 @@ func intersectionCount(a, b *Container) int32 {
 	}
 }
 
+// unionCount returns the number of bits set in the union of containers,
+// using buf as scratch space. buf must have a length of bitmapN.
+func unionCount(buf []uint64, containers []*Container) uint64 {
+	for i := range buf {
+		buf[i] = 0
+	}
+	for _, c := range containers {
+		if c.N() == maxContainerVal+1 {
+			return maxContainerVal + 1
+		}
+		if c.isArray() {
+			for _, v := range c.array() {
+				buf[v>>6] |= uint64(1) << (v % 64)
+			}
+		} else if c.isRun() {
+			for _, run := range c.runs() {
+				bitmapSetRangeIgnoreN(buf, uint64(run.start), uint64(run.last)+1)
+			}
+		} else {
+			for i, v := range c.bitmap() {
+				buf[i] |= v
+			}
+		}
+	}
+
+	var n uint64
+	for _, v := range buf {
+		n += popcount(v)
+	}
+	return n
+}
+
 func intersectionCountArrayArray(a, b *Container) (n int32) {
 	statsHit("intersectionCount/ArrayArray")
 	ca, cb := a.array(), b.array()
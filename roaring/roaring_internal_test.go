@@ -3931,3 +3931,49 @@ func TestBitmapAny(t *testing.T) {
 		t.Error("shouldn't be any left")
 	}
 }
+
+func TestBitmapSnapshot(t *testing.T) {
+	b := NewBitmap(1, 2, 1<<16+1)
+	mapped := b.Containers.Get(1)
+	mapped.setMapped(true)
+
+	snap := b.Snapshot()
+	if c := snap.Containers.Get(0); c != b.Containers.Get(0) || !c.frozen() {
+		t.Fatalf("expected shared frozen container, got %v", c)
+	} else if c := snap.Containers.Get(1); c == mapped || !c.Mapped() || c.frozen() {
+		t.Fatalf("expected distinct mapped container, got %v", c)
+	}
+
+	if _, err := b.Add(3, 1<<16+2); err != nil {
+		t.Fatal(err)
+	} else if _, err := b.Remove(1, 1<<16+1); err != nil {
+		t.Fatal(err)
+	}
+	if got := snap.Slice(); !reflect.DeepEqual(got, []uint64{1, 2, 1<<16 + 1}) {
+		t.Fatalf("unexpected snapshot: %v", got)
+	} else if got := b.Slice(); !reflect.DeepEqual(got, []uint64{2, 3, 1<<16 + 2}) {
+		t.Fatalf("unexpected bitmap: %v", got)
+	}
+}
+
+func TestUnionContainers(t *testing.T) {
+	a := NewContainerArray([]uint16{1, 3, 5})
+	b := NewContainerRun([]interval16{{start: 3, last: 6}})
+	c := NewContainerArray([]uint16{1, 3, 4, 5, 6})
+
+	if UnionContainers() != nil || UnionContainers(nil, NewContainerArray(nil)) != nil {
+		t.Fatal("expected nil union of empty containers")
+	} else if u := UnionContainers(a, nil); !ContainersEqual(u, a) {
+		t.Fatalf("unexpected union: %d values", u.N())
+	} else if u := UnionContainers(a, b); !ContainersEqual(u, c) {
+		t.Fatalf("unexpected union: %d values", u.N())
+	}
+
+	if !ContainersEqual(b, NewContainerArray([]uint16{3, 4, 5, 6})) {
+		t.Fatal("expected run and array containers with the same values to be equal")
+	} else if ContainersEqual(a, NewContainerArray([]uint16{1, 3, 6})) {
+		t.Fatal("expected containers with different values not to be equal")
+	} else if !ContainersEqual(nil, NewContainerArray(nil)) {
+		t.Fatal("expected empty containers to be equal")
+	}
+}
@@ -1089,6 +1089,47 @@ func TestBitmap_IntersectionCount_Mixed(t *testing.T) {
 	}
 }
 
+func TestBitmap_UnionCount_Mixed(t *testing.T) {
+	data := getBenchData(t)
+	full := roaring.NewFileBitmap()
+	for i := uint64(0); i <= MaxContainerVal; i++ {
+		_, _ = full.Add(i)
+	}
+	full.Optimize()
+	bms := []*roaring.Bitmap{testBM(), data.a1, data.a2, data.b, data.r1, data.r2, full, roaring.NewFileBitmap()}
+
+	// Check every combination of the sample bitmaps.
+	for mask := 1; mask < 1<<uint(len(bms)); mask++ {
+		var set []*roaring.Bitmap
+		for i, bm := range bms {
+			if mask&(1<<uint(i)) != 0 {
+				set = append(set, bm)
+			}
+		}
+		exp := set[0]
+		for _, bm := range set[1:] {
+			exp = exp.Union(bm)
+		}
+		if n := set[0].UnionCount(set[1:]...); n != exp.Count() {
+			t.Fatalf("mask %b: unexpected n: %d != %d", mask, n, exp.Count())
+		}
+	}
+}
+
+func TestBitmap_DifferenceCount_XorCount(t *testing.T) {
+	data := getBenchData(t)
+	bms := []*roaring.Bitmap{testBM(), data.a1, data.a2, data.b, data.r1, data.r2, roaring.NewFileBitmap()}
+	for i, x := range bms {
+		for j, y := range bms {
+			if n, exp := x.DifferenceCount(y), x.Difference(y).Count(); n != exp {
+				t.Fatalf("%d-%d: unexpected difference count: %d != %d", i, j, n, exp)
+			} else if n, exp := x.XorCount(y), x.Xor(y).Count(); n != exp {
+				t.Fatalf("%d^%d: unexpected xor count: %d != %d", i, j, n, exp)
+			}
+		}
+	}
+}
+
 func TestBitmap_Shift(t *testing.T) {
 	var max uint64 = math.MaxUint64
 	bm1 := roaring.NewFileBitmap(0, 1, 2, 3, 4, 5, 6, 7, 9, 10, 65536, max)
@@ -1274,6 +1315,88 @@ func testBitmapMarshalQuick(t *testing.T, n int, min, max uint64, sorted bool) {
 	}
 }
 
+// Ensure an op log can be compacted to the net effect of its ops.
+func TestCompactOpLog(t *testing.T) {
+	bm := roaring.NewFileBitmap(1, 2, 3, 100000)
+	var buf bytes.Buffer
+	if _, err := bm.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	snapshotN := buf.Len()
+	bm.OpWriter = &buf
+
+	// Set and clear the same bits repeatedly, set bits already set, and
+	// clear bits already clear.
+	for i := 0; i < 100; i++ {
+		if _, err := bm.Add(10, 200000); err != nil {
+			t.Fatal(err)
+		} else if _, err := bm.Remove(10, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := bm.Add(3); err != nil {
+		t.Fatal(err)
+	} else if _, err := bm.Remove(5); err != nil {
+		t.Fatal(err)
+	} else if _, err := bm.AddN(7, 8, 9); err != nil {
+		t.Fatal(err)
+	}
+	// Empty a container and then add to it again.
+	if _, err := bm.Add(300000); err != nil {
+		t.Fatal(err)
+	} else if _, err := bm.Remove(300000); err != nil {
+		t.Fatal(err)
+	} else if _, err := bm.Add(300001); err != nil {
+		t.Fatal(err)
+	}
+	ops, opN := bm.Ops()
+
+	c, err := roaring.CompactOpLog(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	} else if c.Ops != ops || c.OpN != opN {
+		t.Fatalf("unexpected op counts: %d/%d, expected %d/%d", c.Ops, c.OpN, ops, opN)
+	} else if c.CompactedOps != 2 || c.CompactedOpN != 6 {
+		t.Fatalf("unexpected compacted op counts: %d/%d", c.CompactedOps, c.CompactedOpN)
+	}
+
+	var compacted bytes.Buffer
+	if n, err := c.WriteTo(&compacted); err != nil {
+		t.Fatal(err)
+	} else if n != int64(compacted.Len()) {
+		t.Fatalf("size mismatch: %d != %d", n, compacted.Len())
+	} else if !bytes.Equal(compacted.Bytes()[:snapshotN], buf.Bytes()[:snapshotN]) {
+		t.Fatal("snapshot was rewritten")
+	}
+
+	bm2 := roaring.NewFileBitmap()
+	if err := bm2.UnmarshalBinary(compacted.Bytes()); err != nil {
+		t.Fatal(err)
+	} else if exp, got := bm.Slice(), bm2.Slice(); !reflect.DeepEqual(exp, got) {
+		t.Fatalf("mismatch: exp=%v got=%v", exp, got)
+	} else if ops, opN := bm2.Ops(); ops != 2 || opN != 6 {
+		t.Fatalf("unexpected op counts after compaction: %d/%d", ops, opN)
+	}
+
+	// An op log with no net effect is compacted away.
+	buf.Reset()
+	bm = roaring.NewFileBitmap(1)
+	if _, err := bm.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	bm.OpWriter = &buf
+	if _, err := bm.Add(2); err != nil {
+		t.Fatal(err)
+	} else if _, err := bm.Remove(2); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := roaring.CompactOpLog(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	} else if c.Ops != 2 || c.CompactedOps != 0 || c.CompactedOpN != 0 {
+		t.Fatalf("unexpected op counts: %+v", c)
+	}
+}
+
 // Ensure iterator can iterate over all the values on the bitmap.
 // TODO duplicate for all container types
 func TestIterator(t *testing.T) {
@@ -1583,6 +1706,32 @@ func BenchmarkBitmap_IntersectionCount_ArrayBitmap(b *testing.B) {
 	}
 }
 
+// BenchmarkBitmap_UnionCount_Wide compares counting a wide union with and
+// without materializing it.
+func BenchmarkBitmap_UnionCount_Wide(b *testing.B) {
+	const width, containers = 64, 16
+	bms := make([]*roaring.Bitmap, width)
+	for i := range bms {
+		bms[i] = roaring.NewFileBitmap()
+		for j := 0; j < containers*1000; j++ {
+			_, _ = bms[i].Add(uint64(rand.Intn(containers << 16)))
+		}
+	}
+
+	b.Run("Union", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			bms[0].Union(bms[1:]...).Count()
+		}
+	})
+	b.Run("UnionCount", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			bms[0].UnionCount(bms[1:]...)
+		}
+	})
+}
+
 const (
 	NumRows         = uint64(10000)
 	NumColums       = uint64(16)
@@ -30,7 +30,7 @@ func (b *Bitmap) UnmarshalBinary(data []byte) error {
 		return nil
 	}
 	statsHit("Bitmap/UnmarshalBinary")
-	b.opN = 0 // reset opN since we're reading new data.
+	b.ops, b.opN = 0, 0 // reset op counts since we're reading new data.
 	fileMagic := uint32(binary.LittleEndian.Uint16(data[0:2]))
 	if fileMagic == MagicNumber { // if pilosa roaring
 		return errors.Wrap(b.unmarshalPilosaRoaring(data), "unmarshaling as pilosa roaring")
@@ -129,8 +129,19 @@ func readWithRuns(b *Bitmap, data []byte, pos int, keyN uint32) error {
 }
 
 func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
+	opsOffset, err := b.unmarshalPilosaRoaringContainers(data)
+	if err != nil {
+		return err
+	}
+	return b.applyOps(data[opsOffset:])
+}
+
+// unmarshalPilosaRoaringContainers decodes the containers of data, which is
+// in Pilosa's roaring format, into b, without applying the ops logged after
+// them. Returns the offset of the ops.
+func (b *Bitmap) unmarshalPilosaRoaringContainers(data []byte) (opsOffset int64, err error) {
 	if len(data) < headerBaseSize {
-		return errors.New("data too small")
+		return 0, errors.New("data too small")
 	}
 
 	// Verify the first two bytes are a valid MagicNumber, and second two bytes match current storageVersion.
@@ -138,17 +149,17 @@ func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
 	fileVersion := uint32(data[2])
 	b.Flags = data[3]
 	if fileMagic != MagicNumber {
-		return fmt.Errorf("invalid roaring file, magic number %v is incorrect", fileMagic)
+		return 0, fmt.Errorf("invalid roaring file, magic number %v is incorrect", fileMagic)
 	}
 
 	if fileVersion != storageVersion {
-		return fmt.Errorf("wrong roaring version, file is v%d, server requires v%d", fileVersion, storageVersion)
+		return 0, fmt.Errorf("wrong roaring version, file is v%d, server requires v%d", fileVersion, storageVersion)
 	}
 
 	// Read key count in bytes sizeof(cookie)+sizeof(flag):(sizeof(cookie)+sizeof(uint32)).
 	keyN := binary.LittleEndian.Uint32(data[3+1 : 8])
 	if int64(len(data)) < headerBaseSize+int64(keyN)*12 {
-		return fmt.Errorf("insufficient data for header + offsets: key-cardinality not provided for %d containers", keyN)
+		return 0, fmt.Errorf("insufficient data for header + offsets: key-cardinality not provided for %d containers", keyN)
 	}
 
 	headerSize := headerBaseSize
@@ -161,7 +172,7 @@ func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
 			int(binary.LittleEndian.Uint16(buf[10:12]))+1,
 			true)
 	}
-	opsOffset := int64(headerSize) + int64(keyN)*12
+	opsOffset = int64(headerSize) + int64(keyN)*12
 
 	// Read container offsets and attach data.
 	citer, _ := b.Containers.Iterator(0)
@@ -178,7 +189,7 @@ func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
 		offset := int64(offset32) + cycleOffset
 		// Verify the offset is within the bounds of the input data.
 		if offset >= int64(len(data)) {
-			return fmt.Errorf("offset out of bounds: off=%d, len=%d", offset, len(data))
+			return 0, fmt.Errorf("offset out of bounds: off=%d, len=%d", offset, len(data))
 		}
 
 		// Map byte slice directly to the container data.
@@ -203,9 +214,12 @@ func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
 		}
 	}
 
-	// Read ops log until the end of the file.
-	buf := data[opsOffset:]
+	return opsOffset, nil
+}
 
+// applyOps applies the ops of an ops log, buf, to b, and counts them.
+func (b *Bitmap) applyOps(buf []byte) error {
+	// Read ops log until the end of the file.
 	for {
 		// Exit when there are no more ops to parse.
 		if len(buf) == 0 {
@@ -221,7 +235,6 @@ func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
 		// Increase the op count.
 		b.ops++
 		b.opN += opr.count()
-		opsOffset += int64(opr.size())
 		// Move the buffer forward.
 		buf = buf[opr.size():]
 	}
@@ -0,0 +1,95 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// RoutingTable is the owners of shards of an index, in the order in which they
+// should be read.
+type RoutingTable struct {
+	// Generation identifies the topology the table was computed from. It
+	// is the same on every node of the cluster for the same topology and
+	// changes whenever the routing of shards may change: when nodes join or
+	// leave the cluster, go down, go into maintenance mode or catch up with
+	// the other replicas. It doesn't increase monotonically.
+	Generation uint64 `json:"generation"`
+
+	Shards []ShardRoute `json:"shards"`
+}
+
+// ShardRoute is the owners of a shard, preferred first.
+type ShardRoute struct {
+	Shard  uint64   `json:"shard"`
+	Owners []string `json:"owners"`
+}
+
+// routingTable returns the owners of shards of an index, as preferredShardNodes
+// orders them, with the generation of the topology. Safe for concurrent use.
+func (c *cluster) routingTable(index string, shards []uint64) *RoutingTable {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r := &RoutingTable{
+		Generation: c.unprotectedRoutingGeneration(),
+		Shards:     make([]ShardRoute, 0, len(shards)),
+	}
+	for _, shard := range shards {
+		nodes := c.unprotectedPreferredShardNodes(index, shard, false)
+		owners := make([]string, len(nodes))
+		for i, n := range nodes {
+			owners[i] = n.URI.String()
+		}
+		r.Shards = append(r.Shards, ShardRoute{Shard: shard, Owners: owners})
+	}
+	return r
+}
+
+// unprotectedRoutingGeneration returns a fingerprint of everything which
+// determines the owners of shards and their order.
+func (c *cluster) unprotectedRoutingGeneration() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	writeInt := func(v int) {
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		_, _ = h.Write(buf[:])
+	}
+	writeString := func(s string) {
+		writeInt(len(s))
+		_, _ = h.Write([]byte(s))
+	}
+	writeBool := func(v bool) {
+		if v {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+	}
+
+	writeString(c.state)
+	writeInt(c.ReplicaN)
+	writeInt(c.partitionN)
+	writeInt(len(c.nodes))
+	for _, n := range c.nodes {
+		writeString(n.ID)
+		writeString(n.URI.String())
+		writeBool(n.Maintenance)
+		writeBool(n.State == nodeStateDown)
+		writeBool(n.CatchingUp)
+	}
+	return h.Sum64()
+}
@@ -15,6 +15,7 @@
 package pilosa
 
 import (
+	"bytes"
 	"encoding/json"
 	"sort"
 
@@ -69,9 +70,13 @@ func (r *Row) Merge(other *Row) {
 
 	itr := newMergeSegmentIterator(r.segments, other.segments)
 	for s0, s1 := itr.next(); s0 != nil || s1 != nil; s0, s1 = itr.next() {
-		// Use the other row's data if segment is missing.
+		// Use the other row's data if segment is missing. The data may be
+		// shared, such as with the row cache of a fragment, so it is copied
+		// before anything is merged into it.
 		if s0 == nil {
-			segments = append(segments, *s1)
+			s := *s1
+			s.writable = false
+			segments = append(segments, s)
 			continue
 		} else if s1 == nil {
 			segments = append(segments, *s0)
@@ -87,6 +92,33 @@ func (r *Row) Merge(other *Row) {
 	r.invalidateCount()
 }
 
+// clone returns a heap allocated copy of the columns of r.
+func (r *Row) clone() *Row {
+	other := &Row{segments: make([]rowSegment, len(r.segments))}
+	for i, s := range r.segments {
+		other.segments[i] = rowSegment{
+			shard:    s.shard,
+			data:     s.data.Clone(),
+			writable: true,
+			n:        s.n,
+		}
+	}
+	return other
+}
+
+// rowSegmentOverhead approximates the memory used by a row segment in
+// addition to its bitmap containers.
+const rowSegmentOverhead = 64
+
+// size returns the approximate number of bytes used by r.
+func (r *Row) size() int64 {
+	var n int64
+	for _, s := range r.segments {
+		n += int64(s.data.Size()) + rowSegmentOverhead
+	}
+	return n
+}
+
 // intersectionCount returns the number of intersections between r and other.
 func (r *Row) intersectionCount(other *Row) uint64 {
 	var n uint64
@@ -103,6 +135,74 @@ func (r *Row) intersectionCount(other *Row) uint64 {
 	return n
 }
 
+// unionCount returns the number of columns in the union of r and others
+// without materializing the union.
+func (r *Row) unionCount(others ...*Row) uint64 {
+	rows := append([]*Row{r}, others...)
+	pos := make([]int, len(rows))
+	datas := make([]*roaring.Bitmap, 0, len(rows))
+
+	var n uint64
+	for {
+		// Find the lowest shard among the remaining segments.
+		var shard uint64
+		found := false
+		for i, row := range rows {
+			if pos[i] < len(row.segments) && (!found || row.segments[pos[i]].shard < shard) {
+				shard, found = row.segments[pos[i]].shard, true
+			}
+		}
+		if !found {
+			return n
+		}
+
+		datas = datas[:0]
+		for i, row := range rows {
+			if pos[i] < len(row.segments) && row.segments[pos[i]].shard == shard {
+				datas = append(datas, row.segments[pos[i]].data)
+				pos[i]++
+			}
+		}
+		n += datas[0].UnionCount(datas[1:]...)
+	}
+}
+
+// differenceCount returns the number of columns in r but not in other
+// without materializing the difference.
+func (r *Row) differenceCount(other *Row) uint64 {
+	var n uint64
+
+	itr := newMergeSegmentIterator(r.segments, other.segments)
+	for s0, s1 := itr.next(); s0 != nil || s1 != nil; s0, s1 = itr.next() {
+		if s0 == nil {
+			continue
+		} else if s1 == nil {
+			n += s0.data.Count()
+			continue
+		}
+		n += s0.data.DifferenceCount(s1.data)
+	}
+	return n
+}
+
+// xorCount returns the number of columns in exactly one of r and other
+// without materializing the symmetric difference.
+func (r *Row) xorCount(other *Row) uint64 {
+	var n uint64
+
+	itr := newMergeSegmentIterator(r.segments, other.segments)
+	for s0, s1 := itr.next(); s0 != nil || s1 != nil; s0, s1 = itr.next() {
+		if s0 == nil {
+			n += s1.data.Count()
+		} else if s1 == nil {
+			n += s0.data.Count()
+		} else {
+			n += s0.data.XorCount(s1.data)
+		}
+	}
+	return n
+}
+
 // Intersect returns the itersection of r and other.
 func (r *Row) Intersect(other *Row) *Row {
 	var segments []rowSegment
@@ -317,6 +417,49 @@ func (r *Row) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&o)
 }
 
+// MarshalRoaring returns the columns of r serialized as a single roaring
+// bitmap, which is usually much smaller than the list of columns.
+func (r *Row) MarshalRoaring() ([]byte, error) {
+	b := roaring.NewSliceBitmap()
+	for _, s := range r.segments {
+		b.UnionInPlace(s.data)
+	}
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		return nil, errors.Wrap(err, "writing bitmap")
+	}
+	return buf.Bytes(), nil
+}
+
+// NewRowFromRoaring returns a new row with the columns of a serialized
+// roaring bitmap, such as one returned by MarshalRoaring. The row may
+// reference data.
+func NewRowFromRoaring(data []byte) (*Row, error) {
+	b := roaring.NewSliceBitmap()
+	if err := b.UnmarshalBinary(data); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling bitmap")
+	}
+
+	// Split the bitmap into one segment per shard.
+	r := &Row{}
+	itr, _ := b.Containers.Iterator(0)
+	for itr.Next() {
+		key, _ := itr.Value()
+		shard := key >> shardVsContainerExponent
+		if n := len(r.segments); n > 0 && r.segments[n-1].shard == shard {
+			continue
+		}
+		start := shard * ShardWidth
+		r.segments = append(r.segments, rowSegment{
+			data:     b.OffsetRange(start, start, start+ShardWidth),
+			shard:    shard,
+			writable: true,
+		})
+	}
+	r.invalidateCount()
+	return r, nil
+}
+
 // Columns returns the columns in r as a slice of ints.
 func (r *Row) Columns() []uint64 {
 	a := make([]uint64, 0, r.Count())
@@ -54,6 +54,20 @@ func TestRow_Merge(t *testing.T) {
 	}
 }
 
+// Ensure merging rows leaves the merged rows unchanged, since they may be
+// shared, such as with the row cache of a fragment.
+func TestRow_Merge_Shared(t *testing.T) {
+	r1, r2 := pilosa.NewRow(1), pilosa.NewRow(2)
+	r := pilosa.NewRow()
+	r.Merge(r1)
+	r.Merge(r2)
+	if columns := r.Columns(); !reflect.DeepEqual(columns, []uint64{1, 2}) {
+		t.Fatalf("unexpected columns: %v", columns)
+	} else if columns := r1.Columns(); !reflect.DeepEqual(columns, []uint64{1}) {
+		t.Fatalf("merged row changed: %v", columns)
+	}
+}
+
 // Ensure a row can Xor'ed
 func TestRow_Xor(t *testing.T) {
 	r1 := pilosa.NewRow(0, 1, ShardWidth)
@@ -125,3 +139,37 @@ func TestRow_IsEmpty(t *testing.T) {
 	}
 
 }
+
+// Ensure a row can be encoded as a roaring bitmap and decoded again.
+func TestRow_Roaring(t *testing.T) {
+	for _, columns := range [][]uint64{
+		{},
+		{1, 2, 66000},
+		{0, ShardWidth - 1, ShardWidth, 3*ShardWidth + 5},
+	} {
+		data, err := pilosa.NewRow(columns...).MarshalRoaring()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := pilosa.NewRowFromRoaring(data)
+		if err != nil {
+			t.Fatal(err)
+		} else if got := r.Columns(); !reflect.DeepEqual(got, columns) {
+			t.Fatalf("unexpected columns: %v, expected %v", got, columns)
+		} else if n := r.Count(); n != uint64(len(columns)) {
+			t.Fatalf("unexpected count: %d", n)
+		}
+
+		// Writing to the decoded row doesn't change the encoded data.
+		r.SetBit(7)
+		if r, err := pilosa.NewRowFromRoaring(data); err != nil {
+			t.Fatal(err)
+		} else if got := r.Columns(); !reflect.DeepEqual(got, columns) {
+			t.Fatalf("unexpected columns after write: %v", got)
+		}
+	}
+
+	if _, err := pilosa.NewRowFromRoaring([]byte{1, 2, 3, 4, 5, 6, 7, 8}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pilosa/pilosa/v2/tracing"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// defaultSampleFraction is the fraction of the shards sampled by
+	// Sample() when it isn't given one.
+	defaultSampleFraction = 0.01
+
+	// sampleConcurrency bounds the number of sampled shards read at once.
+	sampleConcurrency = 16
+)
+
+// SampleResult is the result of a Sample() call: counts extrapolated from
+// a deterministic pseudo-random subset of the shards of the index. It is
+// labeled as an estimate in responses.
+type SampleResult struct {
+	// Count is the estimated count of a Count() or bitmap call.
+	Count uint64
+
+	// Pairs are the rows of a TopN() call found in the sampled shards,
+	// with their estimated counts.
+	Pairs []Pair
+
+	// Shards is the number of shards sampled, out of TotalShards.
+	Shards      uint64
+	TotalShards uint64
+
+	Fraction float64
+	Seed     int64
+
+	// Variance is the sample variance, across the sampled shards, of the
+	// count of each shard. For TopN(), the count of a shard is the sum of
+	// the counts of the pairs in the shard.
+	Variance float64
+
+	// StdErr is the estimated standard error of the extrapolated count, or
+	// of the sum of the extrapolated counts of the pairs.
+	StdErr float64
+}
+
+// MarshalJSON returns a JSON-encoded byte slice of r, which is labeled as
+// an estimate.
+func (r *SampleResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Estimate    bool    `json:"estimate"`
+		Count       uint64  `json:"count"`
+		Pairs       []Pair  `json:"pairs,omitempty"`
+		Shards      uint64  `json:"shards"`
+		TotalShards uint64  `json:"totalShards"`
+		Fraction    float64 `json:"fraction"`
+		Seed        int64   `json:"seed"`
+		Variance    float64 `json:"variance"`
+		StdErr      float64 `json:"stdErr"`
+	}{
+		Estimate:    true,
+		Count:       r.Count,
+		Pairs:       r.Pairs,
+		Shards:      r.Shards,
+		TotalShards: r.TotalShards,
+		Fraction:    r.Fraction,
+		Seed:        r.Seed,
+		Variance:    r.Variance,
+		StdErr:      r.StdErr,
+	})
+}
+
+// sampleCall returns the Sample() call of c and the call it samples, if c
+// is a Sample() call, or a Count() or TopN() call of a Sample() call. The
+// sampled call of Sample() of a bitmap call is a Count() of the bitmap
+// call.
+func sampleCall(c *pql.Call) (sample, sampled *pql.Call) {
+	switch c.Name {
+	case "Sample":
+		if len(c.Children) != 1 {
+			return c, nil
+		}
+		switch child := c.Children[0]; child.Name {
+		case "Count", "TopN":
+			return c, child
+		default:
+			return c, &pql.Call{Name: "Count", Children: []*pql.Call{child}}
+		}
+	case "Count", "TopN":
+		if len(c.Children) != 1 || c.Children[0].Name != "Sample" {
+			return nil, nil
+		}
+		sample = c.Children[0]
+		if len(sample.Children) != 1 {
+			return sample, nil
+		}
+		sampled = c.Clone()
+		sampled.Children = []*pql.Call{sample.Children[0]}
+		return sample, sampled
+	}
+	return nil, nil
+}
+
+// checkSampleCalls returns an error if c has a Sample() call which isn't
+// the outermost call, or the input of an outermost Count() or TopN().
+func checkSampleCalls(c *pql.Call) error {
+	if c.Name == "Options" && len(c.Children) == 1 {
+		return checkSampleCalls(c.Children[0])
+	}
+	parent := c
+	if sample, _ := sampleCall(c); sample != nil {
+		parent = sample
+	}
+	return checkNoSampleCalls(parent)
+}
+
+// checkNoSampleCalls returns an error if a descendant of c is a Sample()
+// call.
+func checkNoSampleCalls(c *pql.Call) error {
+	for _, child := range c.Children {
+		if child.Name == "Sample" {
+			return errors.Errorf("Sample() cannot be used inside %s(), only as the outermost call or the input of Count() or TopN()", c.Name)
+		} else if err := checkNoSampleCalls(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sampleShards returns the shards of shards which are sampled for fraction
+// and seed. A shard is sampled if its hash with the seed, as a fraction of
+// the range of the hash, is below fraction, so whether it's sampled doesn't
+// depend on the other shards. At least one shard is sampled.
+func sampleShards(shards []uint64, fraction float64, seed int64) []uint64 {
+	var sampled []uint64
+	var min, minHash uint64
+	for i, shard := range shards {
+		h := splitmix64(splitmix64(uint64(seed)) ^ shard)
+		if float64(h>>11)/(1<<53) < fraction {
+			sampled = append(sampled, shard)
+		}
+		if i == 0 || h < minHash {
+			min, minHash = shard, h
+		}
+	}
+	if len(sampled) == 0 && len(shards) > 0 {
+		sampled = []uint64{min}
+	}
+	return sampled
+}
+
+// splitmix64 returns a hash of x.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// executeSample executes a Sample() call, or a Count() or TopN() call of a
+// Sample() call, on the sampled shards of shards, and extrapolates the
+// counts to every shard.
+func (e *executor) executeSample(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions) (*SampleResult, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSample")
+	defer span.Finish()
+
+	sample, sampled := sampleCall(c)
+	if sampled == nil {
+		return nil, errors.New("Sample() requires a single input call")
+	}
+
+	fraction := defaultSampleFraction
+	switch v := sample.Args["fraction"].(type) {
+	case nil:
+	case float64:
+		fraction = v
+	case int64:
+		fraction = float64(v)
+	default:
+		return nil, errors.Errorf("Sample(): invalid fraction: %v", v)
+	}
+	if fraction <= 0 || fraction > 1 {
+		return nil, errors.Errorf("Sample(): fraction must be greater than 0 and at most 1: %v", fraction)
+	}
+	seed, _, err := sample.IntArg("seed")
+	if err != nil {
+		return nil, errors.Wrap(err, "Sample()")
+	}
+
+	sampledShards := sampleShards(shards, fraction, seed)
+	res := &SampleResult{
+		Shards:      uint64(len(sampledShards)),
+		TotalShards: uint64(len(shards)),
+		Fraction:    fraction,
+		Seed:        seed,
+	}
+	if len(sampledShards) == 0 {
+		return res, nil
+	}
+	scale := float64(len(shards)) / float64(len(sampledShards))
+
+	var counts []float64
+	if sampled.Name == "TopN" {
+		// The candidates are those of the sampled shards, and the counts of
+		// each shard are read again for the variance.
+		pairs, err := e.executeTopN(ctx, index, sampled, sampledShards, opt)
+		if err != nil {
+			return nil, err
+		}
+		res.Pairs = make([]Pair, len(pairs))
+		for i, p := range pairs {
+			res.Pairs[i] = Pair{ID: p.ID, Key: p.Key, Count: uint64(math.Round(float64(p.Count) * scale))}
+		}
+		// Ties are ordered by ID, so that samples are reproducible.
+		sort.SliceStable(res.Pairs, func(i, j int) bool {
+			if res.Pairs[i].Count != res.Pairs[j].Count {
+				return res.Pairs[i].Count > res.Pairs[j].Count
+			}
+			return res.Pairs[i].ID < res.Pairs[j].ID
+		})
+		if len(pairs) == 0 {
+			counts = make([]float64, len(sampledShards))
+		} else {
+			other := sampled.Clone()
+			ids := Pairs(pairs).Keys()
+			sort.Sort(uint64Slice(ids))
+			other.Args["ids"] = ids
+			delete(other.Args, "n")
+			delete(other.Args, "threshold")
+			counts, err = e.sampleShardCounts(ctx, sampledShards, func(shard uint64) (uint64, error) {
+				pairs, err := e.executeTopN(ctx, index, other, []uint64{shard}, opt)
+				var n uint64
+				for _, p := range pairs {
+					n += p.Count
+				}
+				return n, err
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		counts, err = e.sampleShardCounts(ctx, sampledShards, func(shard uint64) (uint64, error) {
+			return e.executeCount(ctx, index, sampled, []uint64{shard}, opt)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var sum float64
+	for _, n := range counts {
+		sum += n
+	}
+	if sampled.Name != "TopN" {
+		res.Count = uint64(math.Round(sum * scale))
+	}
+	if n := float64(len(counts)); n > 1 {
+		mean := sum / n
+		for _, x := range counts {
+			res.Variance += (x - mean) * (x - mean)
+		}
+		res.Variance /= n - 1
+		// The standard error of the extrapolated sum, with the finite
+		// population correction.
+		N := float64(len(shards))
+		res.StdErr = N * math.Sqrt(res.Variance/n*(1-n/N))
+	}
+	return res, nil
+}
+
+// sampleShardCounts returns the count of each shard of shards, computed by
+// fn.
+func (e *executor) sampleShardCounts(ctx context.Context, shards []uint64, fn func(shard uint64) (uint64, error)) ([]float64, error) {
+	counts := make([]float64, len(shards))
+	sem := make(chan struct{}, sampleConcurrency)
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, shard := range shards {
+		i, shard := i, shard
+		eg.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+			n, err := fn(shard)
+			counts[i] = float64(n)
+			return err
+		})
+	}
+	return counts, eg.Wait()
+}
@@ -37,6 +37,10 @@ import (
 // Default server settings.
 const (
 	defaultDiagnosticServer = "https://diagnostics.pilosa.com/v0/diagnostics"
+
+	// diskUsageInterval is how often the disk usage of indexes is measured
+	// to enforce their quotas.
+	diskUsageInterval = 10 * time.Second
 )
 
 // Ensure Server implements interfaces.
@@ -54,6 +58,8 @@ type Server struct { // nolint: maligned
 	diagnostics      *diagnosticsCollector
 	executor         *executor
 	executorPoolSize int
+	resultHandleTTL  time.Duration
+	resultHandleMem  int64
 	hosts            []string
 	clusterDisabled  bool
 	serializer       Serializer
@@ -65,15 +71,61 @@ type Server struct { // nolint: maligned
 
 	nodeID              string
 	uri                 URI
-	antiEntropyInterval time.Duration
 	metricInterval      time.Duration
 	diagnosticInterval  time.Duration
+	resourceInterval    time.Duration
+	statsHistoryOptions statsHistoryOptions
+	statsHistory        *statsHistory
 	maxWritesPerRequest int
+	strictImports       bool
 	isCoordinator       bool
 	syncer              holderSyncer
 
 	defaultClient InternalClient
 	dataDir       string
+
+	// Anti-entropy interval, which can be changed while the server is open.
+	// Open starts monitoring, which keeps running once the interval was
+	// non-zero; changes of the interval are signaled on antiEntropyReset.
+	antiEntropyMu       sync.Mutex
+	antiEntropyInterval time.Duration
+	antiEntropyOpen     bool
+	antiEntropyRunning  bool
+	antiEntropyReset    chan struct{}
+
+	// Cluster-level values of settings.
+	settings *settingsStore
+
+	// Audit log of write operations.
+	auditOptions AuditOptions
+	audit        *auditLog
+
+	// Asynchronous deletions of columns.
+	deleteJobOptions DeleteJobOptions
+	deleteJobs       *deleteJobs
+	warmJobOptions   WarmJobOptions
+	warmJobs         *warmJobs
+
+	// Queries run when the server opens.
+	warmupOptions WarmupOptions
+	warmup        *warmup
+
+	// Asynchronous replication to another cluster.
+	replicationOptions ReplicationOptions
+	replicator         *replicator
+
+	// Memory limits of snapshot reads.
+	snapshotReadOptions SnapshotReadOptions
+
+	// How long TopN() waits for the background recalculation of a cache.
+	topNCacheWait time.Duration
+
+	// Verification of the reads of queries against a second replica.
+	readVerifyOptions ReadVerifyOptions
+
+	// Memory above which expensive queries, or all queries, are rejected.
+	admissionHighMemory     int64
+	admissionCriticalMemory int64
 }
 
 // Holder returns the holder for server.
@@ -135,7 +187,7 @@ func OptServerAntiEntropyInterval(interval time.Duration) ServerOption {
 // used to set long query duration.
 func OptServerLongQueryTime(dur time.Duration) ServerOption {
 	return func(s *Server) error {
-		s.cluster.longQueryTime = dur
+		s.cluster.setLongQueryTime(dur)
 		return nil
 	}
 }
@@ -149,6 +201,17 @@ func OptServerMaxWritesPerRequest(n int) ServerOption {
 	}
 }
 
+// OptServerStrictImports is a functional option on Server used to set
+// whether imports of bits without timestamps into time fields without a
+// standard view, where no query reads them, are rejected. Otherwise they are
+// imported with a warning.
+func OptServerStrictImports(strict bool) ServerOption {
+	return func(s *Server) error {
+		s.strictImports = strict
+		return nil
+	}
+}
+
 // OptServerMetricInterval is a functional option on Server
 // used to set the interval between metric samples.
 func OptServerMetricInterval(dur time.Duration) ServerOption {
@@ -193,6 +256,148 @@ func OptServerExecutorPoolSize(size int) ServerOption {
 	}
 }
 
+// OptServerResultHandles is a functional option on Server used to set how
+// long the stored results of a query session are kept, and the number of
+// bytes of stored results a node keeps across all sessions.
+func OptServerResultHandles(ttl time.Duration, maxMemory int64) ServerOption {
+	return func(s *Server) error {
+		s.resultHandleTTL = ttl
+		s.resultHandleMem = maxMemory
+		return nil
+	}
+}
+
+// OptServerOperationIDs is a functional option on Server used to set the
+// number of operation IDs of applied imports recorded by each fragment, and
+// for how long they are recorded. Zero max disables deduplication.
+func OptServerOperationIDs(max int, ttl time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.holder.opIDOptions = operationIDOptions{max: max, ttl: ttl}
+		return nil
+	}
+}
+
+// OptServerChangeStreams is a functional option on Server used to set the
+// number of changes buffered by the change stream of each field which tracks
+// changes, and for how long they are buffered. Zero retention keeps changes
+// until they are evicted by newer ones.
+func OptServerChangeStreams(size int, retention time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.holder.changeOptions = changeStreamOptions{size: size, retention: retention}
+		return nil
+	}
+}
+
+// OptServerAuditLog is a functional option on Server used to configure the
+// audit log of write operations.
+func OptServerAuditLog(opt AuditOptions) ServerOption {
+	return func(s *Server) error {
+		s.auditOptions = opt
+		return nil
+	}
+}
+
+// OptServerDeleteJobs is a functional option on Server used to configure the
+// asynchronous deletions of columns.
+func OptServerDeleteJobs(opt DeleteJobOptions) ServerOption {
+	return func(s *Server) error {
+		s.deleteJobOptions = opt
+		return nil
+	}
+}
+
+// OptServerWarmJobs is a functional option on Server used to configure the
+// rebuilds of the structures derived from the data of indexes.
+func OptServerWarmJobs(opt WarmJobOptions) ServerOption {
+	return func(s *Server) error {
+		s.warmJobOptions = opt
+		return nil
+	}
+}
+
+// OptServerWarmup is a functional option on Server used to set the queries
+// which the server runs against its own fragments when it opens, before it
+// reports that it's ready.
+func OptServerWarmup(opt WarmupOptions) ServerOption {
+	return func(s *Server) error {
+		s.warmupOptions = opt
+		return nil
+	}
+}
+
+// OptServerReplication is a functional option on Server used to replicate
+// the cluster asynchronously to another cluster, from its coordinator.
+func OptServerReplication(opt ReplicationOptions) ServerOption {
+	return func(s *Server) error {
+		s.replicationOptions = opt
+		return nil
+	}
+}
+
+// OptServerTrashRetention is a functional option on Server used to set the
+// duration for which deleted indexes are kept in the trash. Zero deletes
+// indexes immediately.
+func OptServerTrashRetention(d time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.holder.trashRetention = d
+		return nil
+	}
+}
+
+// OptServerTopNCacheWait is a functional option on Server used to set how
+// long TopN() waits for the background recalculation of the cache of a
+// fragment after an import, before recalculating it itself. Zero never waits.
+func OptServerTopNCacheWait(d time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.topNCacheWait = d
+		return nil
+	}
+}
+
+// OptServerReadVerification is a functional option on Server used to verify
+// a sample of the shard reads of queries against a second replica, and to
+// repair the fragments of shards whose replicas differ.
+func OptServerReadVerification(opt ReadVerifyOptions) ServerOption {
+	return func(s *Server) error {
+		s.readVerifyOptions = opt
+		return nil
+	}
+}
+
+// OptServerAdmission is a functional option on Server used to set the memory
+// of the process above which new expensive queries are rejected, and above
+// which all new queries are rejected. Zero disables a limit.
+func OptServerAdmission(highMemory, criticalMemory int64) ServerOption {
+	return func(s *Server) error {
+		s.admissionHighMemory = highMemory
+		s.admissionCriticalMemory = criticalMemory
+		return nil
+	}
+}
+
+// OptServerRetainedSnapshots is a functional option on Server used to set the
+// interval between two retained snapshots of the fields which retain
+// snapshots, and the number of fragments of retained snapshots kept loaded.
+func OptServerRetainedSnapshots(interval time.Duration, cacheSize int) ServerOption {
+	return func(s *Server) error {
+		if cacheSize <= 0 {
+			return errors.New("retained snapshot cache size must be positive")
+		}
+		s.holder.retainedSnapshotInterval = interval
+		s.holder.retainedFragments = newRetainedFragmentCache(cacheSize)
+		return nil
+	}
+}
+
+// OptServerSnapshotReads is a functional option on Server used to set the
+// memory limits and timeout of queries which read a snapshot of the data.
+func OptServerSnapshotReads(opt SnapshotReadOptions) ServerOption {
+	return func(s *Server) error {
+		s.snapshotReadOptions = opt
+		return nil
+	}
+}
+
 // OptServerPrimaryTranslateStore has been deprecated.
 func OptServerPrimaryTranslateStore(store TranslateStore) ServerOption {
 	return func(s *Server) error {
@@ -256,6 +461,74 @@ func OptServerIsCoordinator(is bool) ServerOption {
 	}
 }
 
+// OptServerColdStartQuorum is a functional option on Server used to set the
+// fraction of the persisted topology which must be running for a restarted
+// cluster to become NORMAL. Zero waits for every node.
+func OptServerColdStartQuorum(q float64) ServerOption {
+	return func(s *Server) error {
+		if q < 0 || q > 1 {
+			return errors.Errorf("cold start quorum must be between 0 and 1: %v", q)
+		}
+		s.cluster.coldStartQuorum = q
+		return nil
+	}
+}
+
+// OptServerForceSingleNode is a functional option on Server used to start
+// the node as a single-node cluster, ignoring the persisted topology.
+func OptServerForceSingleNode(force bool) ServerOption {
+	return func(s *Server) error {
+		s.cluster.forceSingleNode = force
+		return nil
+	}
+}
+
+// OptServerResourceInterval is a functional option on Server used to set
+// the interval at which the node samples its resources and reports them to
+// the coordinator. Zero disables sampling.
+func OptServerResourceInterval(interval time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.resourceInterval = interval
+		return nil
+	}
+}
+
+// OptServerCheckJoinDisk is a functional option on Server used to refuse to
+// add a node to the cluster if its free disk is below the estimated size of
+// the fragments it would receive.
+func OptServerCheckJoinDisk(check bool) ServerOption {
+	return func(s *Server) error {
+		s.cluster.checkJoinDisk = check
+		return nil
+	}
+}
+
+// OptServerMinProtocolVersion is a functional option on Server used to
+// refuse to add a node to the cluster if it doesn't speak at least protocol
+// version v. Zero accepts any node.
+func OptServerMinProtocolVersion(v uint32) ServerOption {
+	return func(s *Server) error {
+		if v > ProtocolVersion {
+			return errors.Errorf("minimum protocol version %d is above the protocol version %d of the node", v, ProtocolVersion)
+		}
+		s.cluster.minProtocolVersion = v
+		return nil
+	}
+}
+
+// OptServerStatsHistory is a functional option on Server used to set how
+// long the per-minute query and write statistics of each index are kept,
+// and for how many indexes. A zero retention disables the history.
+func OptServerStatsHistory(retention time.Duration, maxIndexes int) ServerOption {
+	return func(s *Server) error {
+		s.statsHistoryOptions = statsHistoryOptions{
+			retention:  retention,
+			maxIndexes: maxIndexes,
+		}
+		return nil
+	}
+}
+
 // OptServerNodeID is a functional option on Server
 // used to set the server node ID.
 func OptServerNodeID(nodeID string) ServerOption {
@@ -306,8 +579,22 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		gcNotifier: NopGCNotifier,
 
 		antiEntropyInterval: time.Minute * 10,
+		antiEntropyReset:    make(chan struct{}, 1),
 		metricInterval:      0,
 		diagnosticInterval:  0,
+		resourceInterval:    defaultResourceInterval,
+		statsHistoryOptions: statsHistoryOptions{
+			retention:  defaultStatsHistoryRetention,
+			maxIndexes: defaultStatsHistoryMaxIndexes,
+		},
+		strictImports: true,
+
+		snapshotReadOptions: SnapshotReadOptions{
+			MaxMemory:         defaultSnapshotReadMaxMemory,
+			MaxFragmentMemory: defaultSnapshotReadMaxFragmentMemory,
+			Timeout:           defaultSnapshotReadTimeout,
+		},
+		topNCacheWait: defaultTopNCacheWait,
 
 		logger: logger.NopLogger,
 	}
@@ -322,20 +609,25 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		}
 	}
 
-	// set up executor after server opts have been processed
-	executorOpts := []executorOption{optExecutorInternalQueryClient(s.defaultClient)}
-	if s.executorPoolSize > 0 {
-		executorOpts = append(executorOpts, optExecutorWorkerPoolSize(s.executorPoolSize))
-	}
-	s.executor = newExecutor(executorOpts...)
-
-	// s.holder.translateFile.logger = s.logger
-
 	path, err := expandDirName(s.dataDir)
 	if err != nil {
 		return nil, err
 	}
 
+	// Cluster-level values of settings override the server options. Settings
+	// which require a restart are applied before the components which read
+	// them are created.
+	s.audit = newAuditLog(s.auditOptions, path)
+	s.audit.logger = s.logger
+
+	s.settings = newSettingsStore(path)
+	if err := s.settings.load(); err != nil {
+		return nil, errors.Wrap(err, "loading settings")
+	}
+	s.applySettings(true)
+
+	// s.holder.translateFile.logger = s.logger
+
 	s.holder.Path = path
 	// s.holder.translateFile.Path = filepath.Join(path, ".keys")
 	s.holder.Logger = s.logger
@@ -357,6 +649,9 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		URI:           s.uri,
 		IsCoordinator: s.cluster.Coordinator == s.nodeID,
 		State:         nodeStateDown,
+
+		MinProtocolVersion: MinProtocolVersion,
+		MaxProtocolVersion: ProtocolVersion,
 	}
 	s.cluster.Node = node
 	if s.clusterDisabled {
@@ -369,19 +664,78 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 	// Append the NodeID tag to stats.
 	s.holder.Stats = s.holder.Stats.WithTags(fmt.Sprintf("NodeID:%s", s.nodeID))
 
-	s.executor.Holder = s.holder
-	s.executor.Node = node
-	s.executor.Cluster = s.cluster
-	s.executor.MaxWritesPerRequest = s.maxWritesPerRequest
+	// Keep the recent statistics of each index for the stats history.
+	if s.statsHistoryOptions.retention > 0 {
+		s.statsHistory = newStatsHistory(s.statsHistoryOptions)
+		s.statsHistory.logger = s.logger
+		s.holder.Stats = stats.MultiStatsClient{s.holder.Stats, s.statsHistory.client()}
+	}
+
+	// Set up the executor after the server options, the holder and the
+	// cluster, the same way as an executor embedded in another program.
+	executorOpts := []ExecutorOption{
+		OptExecutorInternalQueryClient(s.defaultClient),
+		OptExecutorMaxWritesPerRequest(s.maxWritesPerRequest),
+		optExecutorSnapshotReads(s.snapshotReadOptions),
+		optExecutorTopNCacheWait(s.topNCacheWait),
+		optExecutorReadVerification(s.readVerifyOptions),
+	}
+	if s.executorPoolSize > 0 {
+		executorOpts = append(executorOpts, OptExecutorWorkerPoolSize(s.executorPoolSize))
+	}
+	if s.resultHandleTTL > 0 {
+		executorOpts = append(executorOpts, optExecutorResultStore(s.resultHandleTTL, s.resultHandleMem))
+	}
+	x, err := NewExecutor(s.holder, s.Cluster(), executorOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating executor")
+	}
+	s.executor = x.executor
+	s.executor.admission.setHighMemory(s.admissionHighMemory)
+	s.executor.admission.setCriticalMemory(s.admissionCriticalMemory)
+	s.executor.audit = s.audit
+	s.executor.verifier.repair = s.repairShard
+	s.executor.snapshots.stats = s.holder.Stats
+	s.deleteJobs = newDeleteJobs(s.deleteJobOptions, path)
+	s.deleteJobs.deleteColumns = s.executor.deleteColumns
+	s.deleteJobs.logger = s.logger
+	s.deleteJobs.stats = s.holder.Stats
+	s.warmJobs = newWarmJobs(s.warmJobOptions, path, s.holder)
+	s.warmJobs.logger = s.logger
+	s.warmJobs.stats = s.holder.Stats
+	s.warmup = newWarmup(s.warmupOptions)
+	s.warmup.executor = s.executor
+	s.warmup.logger = s.logger
+	s.warmup.stats = s.holder.Stats
+	s.replicator = newReplicator(s.replicationOptions)
+	s.replicator.holder = s.holder
+	s.replicator.cluster = s.cluster
+	s.replicator.client = s.defaultClient
+	s.replicator.logger = s.logger
+	s.replicator.stats = s.holder.Stats
 	s.cluster.broadcaster = s
 	s.cluster.maxWritesPerRequest = s.maxWritesPerRequest
 	s.holder.broadcaster = s
+	s.applySettings(false)
 
 	err = s.cluster.setup()
 	if err != nil {
 		return nil, errors.Wrap(err, "setting up cluster")
 	}
 
+	// Sample the resources before the node joins the cluster, so that the
+	// coordinator can check them before adding the node.
+	if s.resourceInterval > 0 {
+		s.cluster.Node.Resources = s.sampleResources()
+	}
+
+	// A node may have missed writes while it was away, so it is catching
+	// up with the other replicas until anti-entropy has synced it. It is
+	// marked before joining so that the other nodes learn it on join.
+	if s.antiEntropyInterval > 0 && s.cluster.ReplicaN > 1 {
+		s.cluster.startCatchingUp()
+	}
+
 	return s, nil
 }
 
@@ -443,14 +797,31 @@ func (s *Server) Open() error {
 	// buffered channel.
 	s.cluster.listenForJoins()
 
+	// Contact nodes of the persisted topology which haven't joined yet.
+	s.cluster.listenForColdStart()
+
 	s.syncer.Holder = s.holder
 	s.syncer.Node = s.cluster.Node
 	s.syncer.Cluster = s.cluster
 	s.syncer.Closing = s.closing
 	s.syncer.Stats = s.holder.Stats.WithTags("HolderSyncer")
 
+	// Resume the unfinished delete and warm jobs.
+	if err := s.deleteJobs.open(); err != nil {
+		return errors.Wrap(err, "opening delete jobs")
+	} else if err := s.warmJobs.open(); err != nil {
+		return errors.Wrap(err, "opening warm jobs")
+	}
+
+	// Warm up the caches of the holder before the node becomes ready.
+	s.warmup.start()
+
 	// Start background monitoring.
-	s.wg.Add(3)
+	s.wg.Add(7)
+	go func() { defer s.wg.Done(); s.audit.run(s.closing) }()
+	go func() { defer s.wg.Done(); s.replicator.run(s.closing) }()
+	go func() { defer s.wg.Done(); s.monitorResources() }()
+	go func() { defer s.wg.Done(); s.monitorDiskUsage() }()
 	go func() { defer s.wg.Done(); s.monitorAntiEntropy() }()
 	go func() { defer s.wg.Done(); s.monitorRuntime() }()
 	go func() { defer s.wg.Done(); s.monitorDiagnostics() }()
@@ -460,6 +831,16 @@ func (s *Server) Open() error {
 
 // Close closes the server and waits for it to shutdown.
 func (s *Server) Close() error {
+	// Stop delete jobs before the executor which runs them.
+	if s.deleteJobs != nil {
+		s.deleteJobs.close()
+	}
+	if s.warmJobs != nil {
+		s.warmJobs.close()
+	}
+	if s.warmup != nil {
+		s.warmup.close()
+	}
 	errE := s.executor.Close()
 
 	// Notify goroutines to stop.
@@ -502,25 +883,64 @@ func (s *Server) loadNodeID() string {
 	return nodeID
 }
 
+// Cluster returns the cluster of the server, on which an executor can
+// execute queries.
+func (s *Server) Cluster() *Cluster {
+	return &Cluster{cluster: s.cluster}
+}
+
 // NodeID returns the server's node id.
 func (s *Server) NodeID() string { return s.nodeID }
 
 // SyncData manually invokes the anti entropy process which makes sure that this
 // node has the data from all replicas across the cluster.
 func (s *Server) SyncData() error {
-	return errors.Wrap(s.syncer.SyncHolder(), "syncing holder")
+	if err := s.syncer.SyncHolder(); err != nil {
+		return errors.Wrap(err, "syncing holder")
+	}
+	return errors.Wrap(s.cluster.setCatchingUp(false), "setting caught up")
 }
 
 func (s *Server) monitorAntiEntropy() {
+	s.antiEntropyMu.Lock()
+	s.antiEntropyOpen = true
 	if s.antiEntropyInterval == 0 || s.cluster.ReplicaN <= 1 {
+		s.antiEntropyMu.Unlock()
 		return // anti entropy disabled
 	}
-	s.cluster.initializeAntiEntropy()
+	s.antiEntropyRunning = true
+	s.antiEntropyMu.Unlock()
 
-	ticker := time.NewTicker(s.antiEntropyInterval)
-	defer ticker.Stop()
+	s.runAntiEntropy()
+}
 
-	s.logger.Printf("holder sync monitor initializing (%s interval)", s.antiEntropyInterval)
+// runAntiEntropy syncs the holder at the anti-entropy interval until the
+// server is closed. A zero interval pauses syncing.
+func (s *Server) runAntiEntropy() {
+	s.cluster.initializeAntiEntropy()
+
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	reset := func() {
+		if ticker != nil {
+			ticker.Stop()
+			ticker, tick = nil, nil
+		}
+		interval := s.AntiEntropyInterval()
+		if interval == 0 {
+			s.logger.Printf("holder sync monitor paused")
+			return
+		}
+		ticker = time.NewTicker(interval)
+		tick = ticker.C
+		s.logger.Printf("holder sync monitor initializing (%s interval)", interval)
+	}
+	reset()
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
 
 	// Initialize syncer with local holder and remote client.
 	for {
@@ -528,9 +948,12 @@ func (s *Server) monitorAntiEntropy() {
 		select {
 		case <-s.closing:
 			return
+		case <-s.antiEntropyReset:
+			reset()
+			continue
 		case <-s.cluster.abortAntiEntropyCh: // receive here so we don't block resizing
 			continue
-		case <-ticker.C:
+		case <-tick:
 			s.holder.Stats.Count("AntiEntropy", 1, 1.0)
 		}
 		t := time.Now()
@@ -539,6 +962,10 @@ func (s *Server) monitorAntiEntropy() {
 			// the cluster sets its state to resizing and *then* sends to
 			// abortAntiEntropyCh before starting to resize
 		}
+		if s.cluster.inMaintenance() {
+			s.logger.Printf("holder sync skipped: node in maintenance")
+			continue
+		}
 		// Sync holders.
 		s.logger.Printf("holder sync beginning")
 		if err := s.syncer.SyncHolder(); err != nil {
@@ -550,13 +977,16 @@ func (s *Server) monitorAntiEntropy() {
 		s.logger.Printf("holder sync complete")
 		dif := time.Since(t)
 		s.holder.Stats.Histogram("AntiEntropyDuration", float64(dif), 1.0)
+		if err := s.cluster.setCatchingUp(false); err != nil {
+			s.logger.Printf("setting caught up: %s", err)
+		}
 
 		// Drain tick channel since we just finished anti-entropy. If the AE
 		// process took a long time, we don't want them to pile up on each
 		// other.
 		for {
 			select {
-			case <-ticker.C:
+			case <-tick:
 				continue
 			default:
 			}
@@ -565,6 +995,41 @@ func (s *Server) monitorAntiEntropy() {
 	}
 }
 
+// AntiEntropyInterval returns the interval at which the holder is synced
+// with the other replicas. Zero means anti-entropy is disabled.
+func (s *Server) AntiEntropyInterval() time.Duration {
+	s.antiEntropyMu.Lock()
+	defer s.antiEntropyMu.Unlock()
+	return s.antiEntropyInterval
+}
+
+// setAntiEntropyInterval changes the anti-entropy interval of an open server.
+// Anti-entropy is started if it was disabled, and paused if the interval is
+// zero.
+func (s *Server) setAntiEntropyInterval(interval time.Duration) {
+	s.antiEntropyMu.Lock()
+	defer s.antiEntropyMu.Unlock()
+	s.antiEntropyInterval = interval
+	if s.antiEntropyRunning {
+		select {
+		case s.antiEntropyReset <- struct{}{}:
+		default:
+		}
+		return
+	} else if !s.antiEntropyOpen || interval == 0 || s.cluster.ReplicaN <= 1 {
+		return
+	}
+
+	select {
+	case <-s.closing:
+		return
+	default:
+	}
+	s.antiEntropyRunning = true
+	s.wg.Add(1)
+	go func() { defer s.wg.Done(); s.runAntiEntropy() }()
+}
+
 // receiveMessage represents an implementation of BroadcastHandler.
 func (s *Server) receiveMessage(m Message) error {
 	switch obj := m.(type) {
@@ -583,21 +1048,122 @@ func (s *Server) receiveMessage(m Message) error {
 			return err
 		}
 	case *DeleteIndexMessage:
-		if err := s.holder.DeleteIndex(obj.Index); err != nil {
+		if obj.Purge {
+			if err := s.holder.PurgeIndex(obj.Index); err != nil {
+				return err
+			}
+		} else if err := s.holder.DeleteIndex(obj.Index); err != nil {
+			return err
+		}
+	case *RestoreIndexMessage:
+		if _, err := s.holder.RestoreIndex(obj.Index); err != nil {
+			return err
+		}
+	case *PurgeTrashMessage:
+		// The entry may already have expired on this node.
+		if err := s.holder.PurgeTrash(obj.Index); err != nil {
+			if _, ok := err.(NotFoundError); !ok {
+				return err
+			}
+		}
+	case *RebuildExistenceMessage:
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
+		if _, err := idx.rebuildExistence(context.Background()); err != nil {
+			return err
+		}
+	case *WarmJobMessage:
+		// A job which is running already is left to finish.
+		if _, err := s.warmJobs.create(obj.ID, obj.Indexes, obj.Created); err != nil {
+			if _, ok := err.(ConflictError); !ok {
+				return err
+			}
+		}
+	case *MaintenanceMessage:
+		s.cluster.receiveMaintenance(obj.NodeID, obj.Maintenance)
+	case *NodeResourcesMessage:
+		s.cluster.receiveResources(obj.NodeID, obj.Resources)
+	case *CatchingUpMessage:
+		s.cluster.receiveCatchingUp(obj.NodeID, obj.CatchingUp)
+	case *SyncShardMessage:
+		if err := s.syncShard(obj.Index, obj.Fields, obj.Shard); err != nil {
+			return err
+		}
+	case *SetIndexReadOnlyMessage:
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
+		if err := idx.SetReadOnly(obj.ReadOnly); err != nil {
+			return err
+		}
+	case *SetIndexQuotaMessage:
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
+		if err := idx.SetQuota(obj.Quota); err != nil {
+			return err
+		}
+	case *SetIndexDefaultFieldOptionsMessage:
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
+		if err := idx.SetDefaultFieldOptions(obj.Options); err != nil {
+			return err
+		}
+	case *SetFieldTimeQuantumMessage:
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
+		if err := idx.setFieldTimeQuantum(obj.Field, obj.TimeQuantum, obj.Time); err != nil {
+			return err
+		}
+	case *DeleteSessionMessage:
+		s.executor.results.release(obj.Session)
+	case *CreateIngestMappingMessage:
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
+		if err := idx.createIngestMappingIfNotExists(obj.Mapping); err != nil {
+			return err
+		}
+	case *DeleteIngestMappingMessage:
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
+		if err := idx.DeleteIngestMapping(obj.Name); err != nil {
 			return err
 		}
 	case *CreateFieldMessage:
-		idx := s.holder.Index(obj.Index)
-		if idx == nil {
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
 			return fmt.Errorf("local index not found: %s", obj.Index)
 		}
+		defer idx.Close()
 		opt := obj.Meta
-		_, err := idx.createFieldIfNotExists(obj.Field, *opt)
-		if err != nil {
+		if _, err := idx.createFieldIfNotExists(obj.Field, *opt); err != nil {
 			return err
 		}
 	case *DeleteFieldMessage:
-		idx := s.holder.Index(obj.Index)
+		idx, err := s.holder.AcquireIndex(obj.Index)
+		if err != nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		defer idx.Close()
 		if err := idx.DeleteField(obj.Field); err != nil {
 			return err
 		}
@@ -620,8 +1186,8 @@ func (s *Server) receiveMessage(m Message) error {
 		if f == nil {
 			return fmt.Errorf("local field not found: %s", obj.Field)
 		}
-		err := f.deleteView(obj.View)
-		if err != nil {
+		// The view may not have been created on this node.
+		if err := f.deleteView(obj.View); err != nil && err != ErrInvalidView {
 			return err
 		}
 	case *ClusterStatus:
@@ -655,13 +1221,34 @@ func (s *Server) receiveMessage(m Message) error {
 		if err != nil {
 			return errors.Wrapf(err, "cluster receiving NodeEvent %v", obj)
 		}
+		if obj.Event == NodeJoin && s.cluster.isCoordinator() && obj.Node.ID != s.nodeID {
+			s.sendSettings(obj.Node)
+		}
+	case *ClusterSettingsMessage:
+		if err := s.updateSettings(obj.Settings); err != nil {
+			return errors.Wrap(err, "updating cluster settings")
+		}
 	case *NodeStatus:
 		s.handleRemoteStatus(obj)
+	case *TransactionMessage:
+		if err := s.executor.receiveTransaction(obj); err != nil {
+			return errors.Wrapf(err, "receiving transaction %s", obj.ID)
+		}
 	}
 
 	return nil
 }
 
+// sendSettings sends the cluster-level values of settings to a node which
+// joined the cluster, which may have missed changes while it was down.
+// Failures are logged since they must not fail the join.
+func (s *Server) sendSettings(node *Node) {
+	msg := &ClusterSettingsMessage{Settings: s.settings.Overrides()}
+	if err := s.SendTo(node, msg); err != nil {
+		s.logger.Printf("sending cluster settings to node %s: %v", node.ID, err)
+	}
+}
+
 // SendSync represents an implementation of Broadcaster.
 func (s *Server) SendSync(m Message) error {
 	var eg errgroup.Group
@@ -671,13 +1258,21 @@ func (s *Server) SendSync(m Message) error {
 	}
 	msg = append([]byte{getMessageType(m)}, msg...)
 
+	// Don't forward the message to ourselves.
+	var nodes []*Node
 	for _, node := range s.cluster.Nodes() {
-		node := node
-		// Don't forward the message to ourselves.
-		if s.uri == node.URI {
-			continue
+		if s.uri != node.URI {
+			nodes = append(nodes, node)
 		}
+	}
 
+	// Nodes of an older protocol version can't read newer message types.
+	if err := checkMessageProtocolVersion(m, nodes...); err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		node := node
 		eg.Go(func() error {
 			return s.defaultClient.SendMessage(context.Background(), &node.URI, msg)
 		})
@@ -693,6 +1288,9 @@ func (s *Server) SendAsync(m Message) error {
 
 // SendTo represents an implementation of Broadcaster.
 func (s *Server) SendTo(to *Node, m Message) error {
+	if err := checkMessageProtocolVersion(m, to); err != nil {
+		return err
+	}
 	msg, err := s.serializer.Marshal(m)
 	if err != nil {
 		return fmt.Errorf("marshaling message: %v", err)
@@ -750,6 +1348,7 @@ func (s *Server) mergeRemoteStatus(ns *NodeStatus) error {
 			if err := f.AddRemoteAvailableShards(fs.AvailableShards); err != nil {
 				return errors.Wrap(err, "adding remote available shards")
 			}
+			f.AddRemoteMaxRowID(fs.MaxRowID)
 		}
 	}
 
@@ -785,6 +1384,7 @@ func (s *Server) monitorDiagnostics() {
 		s.diagnostics.Set("GoRoutines", runtime.NumGoroutine())
 		s.diagnostics.EnrichWithMemoryInfo()
 		s.diagnostics.EnrichWithSchemaProperties()
+		s.diagnostics.EnrichWithStatsHistory()
 		err = s.diagnostics.CheckVersion()
 		if err != nil {
 			s.logger.Printf("can't check version: %v", err)
@@ -809,6 +1409,23 @@ func (s *Server) monitorDiagnostics() {
 	}
 }
 
+// monitorDiskUsage periodically measures the disk space used by each index
+// to enforce index quotas.
+func (s *Server) monitorDiskUsage() {
+	ticker := time.NewTicker(diskUsageInterval)
+	defer ticker.Stop()
+
+	for {
+		s.holder.updateDiskUsage()
+
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // monitorRuntime periodically polls the Go runtime metrics.
 func (s *Server) monitorRuntime() {
 	// Disable metrics when poll interval is zero.
@@ -28,6 +28,7 @@ import (
 
 	"github.com/pilosa/pilosa/v2"
 	"github.com/pilosa/pilosa/v2/test"
+	"github.com/pilosa/pilosa/v2/toml"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -208,7 +209,7 @@ func TestClusterResize_AddNode(t *testing.T) {
 		}
 
 		// exp is the expected result for the Row queries that follow.
-		exp := `{"results":[{"attrs":{},"columns":[1,1300000]}]}` + "\n"
+		exp := `{"results":[{"attrs":{},"columns":[1,1300000]}],"shards":[0,1]}` + "\n"
 
 		// Verify the data exists on the single node.
 		if res, err := m0.Query("i", "", `Row(f=1)`); err != nil {
@@ -271,7 +272,7 @@ func TestClusterResize_AddNode(t *testing.T) {
 		}
 
 		// exp is the expected result for the Row queries that follow.
-		exp := `{"results":[{"attrs":{},"columns":[1,2400000]}]}` + "\n"
+		exp := `{"results":[{"attrs":{},"columns":[1,2400000]}],"shards":[0,2]}` + "\n"
 
 		// Verify the data exists on the single node.
 		if res, err := m0.Query("i", "", `Row(f=1)`); err != nil {
@@ -381,7 +382,7 @@ func TestClusterResize_AddNodeConcurrentIndex(t *testing.T) {
 		}
 
 		// exp is the expected result for the Row queries that follow.
-		exp := `{"results":[{"attrs":{},"columns":[1,1300000]}]}` + "\n"
+		exp := `{"results":[{"attrs":{},"columns":[1,1300000]}],"shards":[0,1]}` + "\n"
 
 		// Verify the data exists on the single node.
 		if res, err := m0.Query("i", "", `Row(f=1)`); err != nil {
@@ -449,7 +450,7 @@ func TestClusterResize_AddNodeConcurrentIndex(t *testing.T) {
 		}
 
 		// exp is the expected result for the Row queries that follow.
-		exp := `{"results":[{"attrs":{},"columns":[1,2400000]}]}` + "\n"
+		exp := `{"results":[{"attrs":{},"columns":[1,2400000]}],"shards":[0,2]}` + "\n"
 
 		// Verify the data exists on the single node.
 		if res, err := m0.Query("i", "", `Row(f=1)`); err != nil {
@@ -551,6 +552,364 @@ func TestCluster_GossipMembership(t *testing.T) {
 	})
 }
 
+// Ensure every node reports its resources to the coordinator.
+func TestCluster_NodeResources(t *testing.T) {
+	cluster := test.MustNewCluster(t, 3)
+	for _, c := range cluster {
+		c.Config.Cluster.ResourceInterval = toml.Duration(50 * time.Millisecond)
+	}
+	if err := cluster.Start(); err != nil {
+		t.Fatalf("starting cluster: %v", err)
+	}
+	defer cluster.Close()
+	waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+
+	cluster.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	var q strings.Builder
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&q, "Set(%d, f=1)", i*pilosa.ShardWidth)
+	}
+	cluster.Query(t, "i", q.String())
+
+	start := time.Now()
+	if err := test.RetryUntil(5*time.Second, func() error {
+		hosts := cluster[0].API.Hosts(context.Background())
+		if len(hosts) != 3 {
+			return fmt.Errorf("unexpected hosts: %v", hosts)
+		}
+		for _, node := range hosts {
+			if r := node.Resources; r == nil {
+				return fmt.Errorf("no resources for %s", node.ID)
+			} else if r.SampledAt.Before(start) || r.Fragments == 0 || r.DataSize == 0 || r.DiskFree == 0 || r.MemRSS == 0 {
+				return fmt.Errorf("unexpected resources for %s: %+v", node.ID, r)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCluster_ReadVerification(t *testing.T) {
+	cluster := test.MustNewCluster(t, 2)
+	for _, c := range cluster {
+		c.Config.Cluster.ReplicaN = 2
+		c.Config.ReadVerification.Rate = 1
+		c.Config.ReadVerification.Repair = true
+	}
+	if err := cluster.Start(); err != nil {
+		t.Fatalf("starting cluster: %v", err)
+	}
+	defer cluster.Close()
+	waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+
+	cluster.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	cluster.CreateField(t, "i", pilosa.IndexOptions{}, "g")
+	cluster.Query(t, "i", "Set(1, f=1) Set(2, f=1) Set(1, g=1)")
+
+	// Set a bit on a single replica.
+	if _, err := cluster[1].Server.Holder().Field("i", "f").SetBit(1, 3, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verified reads don't change the results of queries, which are read
+	// from a single replica, until the shard is repaired.
+	resp := cluster.Query(t, "i", "Count(Intersect(Row(f=1), Row(g=1)))")
+	if n := resp.Results[0].(uint64); n != 1 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+	cluster.Query(t, "i", "Count(Row(f=1))")
+
+	if err := test.RetryUntil(5*time.Second, func() error {
+		for i, c := range cluster {
+			row, err := c.Server.Holder().Field("i", "f").Row(1)
+			if err != nil {
+				return err
+			} else if n := row.Count(); n != 3 {
+				return fmt.Errorf("node %d has %d bits", i, n)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure reads avoid a replica catching up with the other replicas, unless
+// they accept its staleness.
+func TestCluster_StaleReads(t *testing.T) {
+	cluster := test.MustNewCluster(t, 2)
+	for _, c := range cluster {
+		c.Config.Cluster.ReplicaN = 2
+	}
+	if err := cluster.Start(); err != nil {
+		t.Fatalf("starting cluster: %v", err)
+	}
+	defer cluster.Close()
+	waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+	ctx := context.Background()
+
+	// Both nodes are catching up until they are synced. Find a shard
+	// whose primary is the second node.
+	node1 := cluster[1].API.Node().ID
+	for _, n := range cluster[0].API.Hosts(ctx) {
+		if !n.CatchingUp {
+			t.Fatalf("expected node to be catching up: %s", n)
+		}
+	}
+	var shard uint64
+	for ; shard < 4; shard++ {
+		if nodes, err := cluster[0].API.ShardNodes(ctx, "i", shard); err != nil {
+			t.Fatal(err)
+		} else if nodes[0].ID == node1 {
+			break
+		}
+	}
+
+	cluster.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	cluster.Query(t, "i", fmt.Sprintf("Set(%d, f=1) Set(%d, f=1)", shard*pilosa.ShardWidth+1, shard*pilosa.ShardWidth+2))
+
+	// Sync the first node, then remove a bit from the second node only.
+	if err := cluster[0].Server.SyncData(); err != nil {
+		t.Fatal(err)
+	}
+	if err := test.RetryUntil(5*time.Second, func() error {
+		for _, n := range cluster[1].API.Hosts(ctx) {
+			if n.CatchingUp != (n.ID == node1) {
+				return fmt.Errorf("unexpected catching up: %s", n)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	hldr := test.Holder{Holder: cluster[1].Server.Holder()}
+	hldr.ClearBit("i", "f", 1, shard*pilosa.ShardWidth+2)
+
+	query := func(maxStaleness time.Duration) pilosa.QueryResponse {
+		t.Helper()
+		resp, err := cluster[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))", MaxStaleness: maxStaleness})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// By default, the replica catching up is avoided.
+	if resp := query(0); resp.Results[0].(uint64) != 2 || len(resp.StaleShards) != 0 {
+		t.Fatalf("unexpected response: results=%v stale=%v", resp.Results, resp.StaleShards)
+	}
+
+	// A read accepting stale data is served by the primary, and its shard
+	// is reported as stale.
+	if resp := query(time.Hour); resp.Results[0].(uint64) != 1 || !reflect.DeepEqual(resp.StaleShards, []uint64{shard}) {
+		t.Fatalf("unexpected response: results=%v stale=%v", resp.Results, resp.StaleShards)
+	}
+
+	// A replica staler than a read accepts refuses it.
+	time.Sleep(time.Millisecond)
+	if resp := query(time.Nanosecond); resp.Results[0].(uint64) != 2 || len(resp.StaleShards) != 0 {
+		t.Fatalf("unexpected response: results=%v stale=%v", resp.Results, resp.StaleShards)
+	}
+}
+
+func TestCluster_FullRestart(t *testing.T) {
+	t.Run("ColdStartQuorum", func(t *testing.T) {
+		cluster := test.MustNewCluster(t, 4)
+		for _, c := range cluster {
+			c.Config.Cluster.ColdStartQuorum = 0.75
+		}
+		if err := cluster.Start(); err != nil {
+			t.Fatalf("starting cluster: %v", err)
+		}
+		defer cluster.Close()
+		waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+
+		cluster.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+		var q strings.Builder
+		for i := 0; i < 20; i++ {
+			fmt.Fprintf(&q, "Set(%d, f=1)", i*pilosa.ShardWidth)
+		}
+		cluster.Query(t, "i", q.String())
+
+		// Stop every node, starting with the coordinator so that no node
+		// leaves are processed.
+		for i := range cluster {
+			if err := cluster.StopNode(i); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Start the coordinator without gossip seeds together with two other
+		// nodes. The coordinator finds them through its persisted cluster
+		// status and three of four nodes satisfy the quorum.
+		if err := cluster.StartNodes(0, 1, 2); err != nil {
+			t.Fatal(err)
+		}
+		waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+		if hosts := cluster[0].API.Hosts(context.Background()); len(hosts) != 3 {
+			t.Fatalf("unexpected hosts: %v", hosts)
+		}
+
+		// The last node rejoins without a resize.
+		if err := cluster.StartNode(3); err != nil {
+			t.Fatal(err)
+		}
+		waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+		if err := test.RetryUntil(5*time.Second, func() error {
+			if hosts := cluster[0].API.Hosts(context.Background()); len(hosts) != 4 {
+				return fmt.Errorf("unexpected hosts: %v", hosts)
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+
+		if res := cluster.Query(t, "i", "Count(Row(f=1))").Results[0]; res != uint64(20) {
+			t.Fatalf("unexpected count after restart: %v", res)
+		}
+	})
+
+	t.Run("ForceSingleNode", func(t *testing.T) {
+		cluster := test.MustRunCluster(t, 2)
+		defer cluster.Close()
+		waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+
+		for i := range cluster {
+			if err := cluster.StopNode(i); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		cluster[0].Config.Cluster.ForceSingleNode = true
+		if err := cluster.StartNode(0); err != nil {
+			t.Fatal(err)
+		}
+		waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+		if hosts := cluster[0].API.Hosts(context.Background()); len(hosts) != 1 {
+			t.Fatalf("unexpected hosts: %v", hosts)
+		}
+	})
+}
+
+func TestCluster_Config(t *testing.T) {
+	cluster := test.MustRunCluster(t, 3)
+	defer cluster.Close()
+	waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+	coordinator := cluster[0]
+
+	patch := func(m *test.Command, body string) *pilosa.ClusterConfig {
+		t.Helper()
+		resp := test.MustDo("PATCH", m.URL()+"/cluster/config", body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: %d, body: %s", resp.StatusCode, resp.Body)
+		}
+		var config pilosa.ClusterConfig
+		if err := json.Unmarshal([]byte(resp.Body), &config); err != nil {
+			t.Fatal(err)
+		}
+		return &config
+	}
+
+	// Dynamic settings apply on every node, while settings which require a
+	// restart are pending.
+	config := patch(coordinator, `{"settings": {"cluster.long-query-time": "1m", "operation-ids.max": "20"}}`)
+	for _, setting := range config.Settings {
+		switch setting.Name {
+		case "cluster.long-query-time":
+			if setting.RestartRequired || setting.Value == nil || *setting.Value != "1m0s" {
+				t.Fatalf("unexpected setting: %+v", setting)
+			}
+			for _, node := range setting.Nodes {
+				if node.Value != "1m0s" || !node.Overridden || node.PendingRestart {
+					t.Fatalf("unexpected node setting: %+v", node)
+				}
+			}
+		case "operation-ids.max":
+			if !setting.RestartRequired || setting.Value == nil || *setting.Value != "20" {
+				t.Fatalf("unexpected setting: %+v", setting)
+			}
+			for _, node := range setting.Nodes {
+				if node.Value != node.Default || !node.Overridden || !node.PendingRestart {
+					t.Fatalf("unexpected node setting: %+v", node)
+				}
+			}
+		}
+		if len(setting.Nodes) != len(cluster) {
+			t.Fatalf("unexpected nodes: %+v", setting.Nodes)
+		}
+	}
+	for i, m := range cluster {
+		if d := m.API.LongQueryTime(); d != time.Minute {
+			t.Fatalf("unexpected long query time on node %d: %s", i, d)
+		}
+	}
+
+	// Invalid updates are rejected, as are updates which aren't sent to the
+	// coordinator.
+	for _, body := range []string{
+		`{}`,
+		`{"settings": {"unknown": "1"}}`,
+		`{"settings": {"max-writes-per-request": "-1"}}`,
+		`{"settings": {"anti-entropy.interval": "soon"}}`,
+	} {
+		if resp := test.MustDo("PATCH", coordinator.URL()+"/cluster/config", body); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("unexpected status for %s: %d, body: %s", body, resp.StatusCode, resp.Body)
+		}
+	}
+	resp := test.MustDo("PATCH", cluster[1].URL()+"/cluster/config", `{"settings": {"max-writes-per-request": "10"}}`)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(resp.Body, pilosa.ErrNodeNotCoordinator.Error()) {
+		t.Fatalf("unexpected response: %d, body: %s", resp.StatusCode, resp.Body)
+	}
+
+	// A node which is down while a setting is changed receives it when it
+	// rejoins, and applies the settings which required a restart.
+	if err := cluster.StopNode(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := test.RetryUntil(10*time.Second, func() error {
+		if hosts := coordinator.API.Hosts(context.Background()); len(hosts) != 2 {
+			return fmt.Errorf("unexpected hosts: %v", hosts)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	patch(coordinator, `{"settings": {"max-writes-per-request": "10"}}`)
+	if err := cluster.StartNode(2); err != nil {
+		t.Fatal(err)
+	}
+	waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+	if err := test.RetryUntil(5*time.Second, func() error {
+		settings := cluster[2].API.NodeSettings()
+		if s := settings["max-writes-per-request"]; s.Value != "10" {
+			return fmt.Errorf("unexpected max writes: %+v", s)
+		} else if s := settings["operation-ids.max"]; s.Value != "20" || s.PendingRestart {
+			return fmt.Errorf("unexpected operation ID max: %+v", s)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing a cluster-level value reverts to the configuration of each
+	// node.
+	config = patch(coordinator, `{"settings": {"cluster.long-query-time": null}}`)
+	for _, setting := range config.Settings {
+		if setting.Name != "cluster.long-query-time" {
+			continue
+		} else if setting.Value != nil {
+			t.Fatalf("unexpected setting: %+v", setting)
+		}
+		for _, node := range setting.Nodes {
+			if node.Value != node.Default || node.Overridden {
+				t.Fatalf("unexpected node setting: %+v", node)
+			}
+		}
+	}
+}
+
 func TestClusterResize_RemoveNode(t *testing.T) {
 	cluster := test.MustRunCluster(t, 3)
 	defer cluster.Close()
@@ -63,6 +63,11 @@ type Config struct {
 	// SetRowAttrs & SetColumnAttrs.
 	MaxWritesPerRequest int `toml:"max-writes-per-request"`
 
+	// StrictImports rejects imports of bits without timestamps into time
+	// fields without a standard view, where no query reads them, instead of
+	// importing them with a warning.
+	StrictImports bool `toml:"strict-imports"`
+
 	// LogPath configures where Pilosa will write logs.
 	LogPath string `toml:"log-path"`
 
@@ -87,6 +92,11 @@ type Config struct {
 	// lots of fragments.
 	MaxFileCount uint64 `toml:"max-file-count"`
 
+	// TopNCacheWait is how long TopN() waits for the cache of a fragment,
+	// which is recalculated in the background after an import, before
+	// recalculating it itself. Zero recalculates it immediately.
+	TopNCacheWait toml.Duration `toml:"topn-cache-wait"`
+
 	// TLS
 	TLS TLSConfig `toml:"tls"`
 
@@ -112,6 +122,23 @@ type Config struct {
 		Hosts       []string `toml:"hosts"`
 		// TODO(2.0) move this out of cluster. (why is it here??)
 		LongQueryTime toml.Duration `toml:"long-query-time"`
+		// ColdStartQuorum is the fraction of the persisted node list which
+		// must be running for the coordinator to bring a restarted cluster
+		// to NORMAL. Zero waits for every node.
+		ColdStartQuorum float64 `toml:"cold-start-quorum"`
+		// ForceSingleNode starts the node as a single-node cluster, ignoring
+		// the persisted node list. Intended for lab use.
+		ForceSingleNode bool `toml:"force-single-node"`
+		// ResourceInterval is how often each node samples its disk, memory
+		// and load, and reports them to the coordinator. Zero disables
+		// sampling.
+		ResourceInterval toml.Duration `toml:"resource-interval"`
+		// CheckJoinDisk refuses to add a node whose free disk is below the
+		// estimated size of the fragments it would receive.
+		CheckJoinDisk bool `toml:"check-join-disk"`
+		// MinProtocolVersion refuses to add a node which doesn't speak at
+		// least this protocol version. Zero accepts any node.
+		MinProtocolVersion uint32 `toml:"min-protocol-version"`
 	} `toml:"cluster"`
 
 	// Gossip config is based around memberlist.Config.
@@ -123,15 +150,199 @@ type Config struct {
 		PrimaryURL string `toml:"primary-url"`
 	} `toml:"translation"`
 
+	// ResultHandles configures the results which queries store for later
+	// queries in the same session.
+	ResultHandles struct {
+		// TTL is how long the results of a session are kept after the
+		// session was last used.
+		TTL toml.Duration `toml:"ttl"`
+		// MaxMemory is the number of bytes of stored results a node keeps
+		// across all sessions. Zero is unlimited.
+		MaxMemory int64 `toml:"max-memory"`
+	} `toml:"result-handles"`
+
+	// OperationIDs configures the IDs of recently applied imports and
+	// transactional writes which are recorded so that retried writes are
+	// only applied once.
+	OperationIDs struct {
+		// Max is the number of IDs recorded by each fragment. Zero disables
+		// deduplication.
+		Max int `toml:"max"`
+		// TTL is how long an ID is recorded after it was applied.
+		TTL toml.Duration `toml:"ttl"`
+	} `toml:"operation-ids"`
+
+	// ChangeStreams configures the changes recorded by each node for fields
+	// which track changes.
+	ChangeStreams struct {
+		// BufferSize is the number of changes buffered for each field.
+		BufferSize int `toml:"buffer-size"`
+		// Retention is how long a change is buffered. Zero keeps changes
+		// until they are evicted by newer ones.
+		Retention toml.Duration `toml:"retention"`
+	} `toml:"change-streams"`
+
+	// Audit configures the audit log of write operations.
+	Audit struct {
+		// Enabled turns on recording of write operations.
+		Enabled bool `toml:"enabled"`
+		// Path of the audit log file. Defaults to audit.log in the data
+		// directory.
+		Path string `toml:"path"`
+		// MaxSize is the size in bytes at which the file is rotated.
+		MaxSize int64 `toml:"max-size"`
+		// MaxBackups is the number of rotated files kept.
+		MaxBackups int `toml:"max-backups"`
+		// Webhook is a URL to which records are also posted, if set.
+		Webhook string `toml:"webhook"`
+	} `toml:"audit"`
+
+	// DeleteJobs configures the asynchronous deletions of columns.
+	DeleteJobs struct {
+		// Concurrency is the number of chunks of columns deleted at the same
+		// time.
+		Concurrency int `toml:"concurrency"`
+		// Rate is the largest number of columns deleted per second. Zero is
+		// unlimited.
+		Rate int `toml:"rate"`
+	} `toml:"delete-jobs"`
+
+	// WarmJobs configures the rebuilds of the structures derived from the
+	// data of indexes.
+	WarmJobs struct {
+		// Concurrency is the number of fragments warmed at the same time.
+		Concurrency int `toml:"concurrency"`
+		// Rate is the largest number of bytes of fragments read per second.
+		// Zero is unlimited.
+		Rate int `toml:"rate"`
+	} `toml:"warm-jobs"`
+
+	// Warmup configures the queries which the node runs against its own
+	// fragments when it starts, before it reports that it's ready.
+	Warmup struct {
+		// Queries are read queries, each prefixed with the name of its
+		// index and a colon, such as "events:TopN(browser)".
+		Queries []string `toml:"queries"`
+		// TopNFields are fields, as index:field, whose ranked caches are
+		// read with TopN() on every shard.
+		TopNFields []string `toml:"topn-fields"`
+		// Timeout is the longest time for which the node isn't ready while
+		// it warms up. The remaining queries then run in the background.
+		// Zero waits for every query.
+		Timeout toml.Duration `toml:"timeout"`
+	} `toml:"warmup"`
+
+	// Replication configures the asynchronous replication of the cluster
+	// to another cluster, such as a disaster recovery cluster, whose
+	// indexes are made read-only.
+	Replication struct {
+		// Target is the address of a node of the cluster replicated to.
+		// Replication is disabled if it's empty.
+		Target string `toml:"target"`
+		// Interval is the time between the passes of replication.
+		Interval toml.Duration `toml:"interval"`
+		// BatchSize is the maximum number of fragments shipped by a pass.
+		BatchSize int `toml:"batch-size"`
+		// ReconcileInterval is the time between the comparisons of every
+		// fragment of the fields which don't track changes.
+		ReconcileInterval toml.Duration `toml:"reconcile-interval"`
+	} `toml:"replication"`
+
+	// HTTP2 configures the HTTP/2 connections between nodes.
+	HTTP2 struct {
+		// Enabled sends requests to other nodes over HTTP/2, without TLS
+		// (h2c) for http URIs. Nodes which don't support HTTP/2 are sent
+		// HTTP/1.1 requests.
+		Enabled bool `toml:"enabled"`
+		// MaxConnsPerPeer is the number of connections opened to each node.
+		MaxConnsPerPeer int `toml:"max-conns-per-peer"`
+		// MaxStreamsPerConn is the number of concurrent requests on each
+		// connection.
+		MaxStreamsPerConn int `toml:"max-streams-per-conn"`
+		// PingInterval is the interval between pings of each connection.
+		PingInterval toml.Duration `toml:"ping-interval"`
+		// PingTimeout is how long to wait for the answer of a ping before
+		// closing a connection.
+		PingTimeout toml.Duration `toml:"ping-timeout"`
+	} `toml:"http2"`
+
+	// SnapshotReads configures the memory used by queries which read a
+	// snapshot of the data.
+	SnapshotReads struct {
+		// MaxMemory is the number of bytes the snapshots of all running
+		// queries may use. Zero is unlimited.
+		MaxMemory int64 `toml:"max-memory"`
+		// MaxFragmentMemory is the number of bytes the snapshot of a single
+		// fragment may use. Zero is unlimited.
+		MaxFragmentMemory int64 `toml:"max-fragment-memory"`
+		// Timeout is how long a query waits for the writes being applied
+		// to take its snapshot. Zero waits indefinitely.
+		Timeout toml.Duration `toml:"timeout"`
+	} `toml:"snapshot-reads"`
+
+	// Trash configures the retention of deleted indexes.
+	Trash struct {
+		// Retention is how long a deleted index is kept before it is purged.
+		// Zero deletes indexes immediately.
+		Retention toml.Duration `toml:"retention"`
+	} `toml:"trash"`
+
+	// Admission configures the rejection of new queries while the node is
+	// using too much memory.
+	Admission struct {
+		// HighMemory is the number of bytes of memory above which expensive
+		// queries are rejected. Zero is disabled.
+		HighMemory int64 `toml:"high-memory"`
+		// CriticalMemory is the number of bytes of memory above which all
+		// queries are rejected. Zero is disabled.
+		CriticalMemory int64 `toml:"critical-memory"`
+	} `toml:"admission"`
+
+	// RetainedSnapshots configures the snapshots retained by fields which
+	// retain snapshots, for queries as of a past time.
+	RetainedSnapshots struct {
+		// Interval is the interval between two retained snapshots of a field.
+		Interval toml.Duration `toml:"interval"`
+		// CacheSize is the number of fragments of retained snapshots kept
+		// loaded for queries.
+		CacheSize int `toml:"cache-size"`
+	} `toml:"retained-snapshots"`
+
+	// ReadVerification configures the verification of the reads of queries
+	// against a second replica.
+	ReadVerification struct {
+		// Rate is the fraction of the shards read by queries which are
+		// verified. Zero disables verification.
+		Rate float64 `toml:"rate"`
+		// Repair synchronizes the fragments of shards whose replicas
+		// returned different results.
+		Repair bool `toml:"repair"`
+	} `toml:"read-verification"`
+
+	// StatsHistory configures the per-minute query and write statistics
+	// kept by each node for its indexes.
+	StatsHistory struct {
+		// Retention is how long the statistics of each minute are kept.
+		// Zero disables the history.
+		Retention toml.Duration `toml:"retention"`
+		// MaxIndexes is the number of indexes whose statistics are kept.
+		MaxIndexes int `toml:"max-indexes"`
+	} `toml:"stats-history"`
+
 	AntiEntropy struct {
 		Interval toml.Duration `toml:"interval"`
 	} `toml:"anti-entropy"`
 
 	Metric struct {
-		// Service can be statsd, expvar, or none.
+		// Service can be statsd, statsd-plain, prometheus, expvar, or none.
 		Service string `toml:"service"`
 		// Host tells the statsd client where to write.
-		Host         string        `toml:"host"`
+		Host string `toml:"host"`
+		// Prefix is prepended to the name of every metric, with a dot.
+		Prefix string `toml:"prefix"`
+		// Tags are added to every metric, in key:value form, such as the
+		// name of the cluster and the environment.
+		Tags         []string      `toml:"tags"`
 		PollInterval toml.Duration `toml:"poll-interval"`
 		// Diagnostics toggles sending some limited diagnostic information to
 		// Pilosa's developers.
@@ -162,6 +373,8 @@ func NewConfig() *Config {
 		DataDir:             "~/.pilosa",
 		Bind:                ":10101",
 		MaxWritesPerRequest: 5000,
+		StrictImports:       true,
+		TopNCacheWait:       toml.Duration(time.Second),
 
 		// We default these Max File/Map counts very high. This is basically a
 		// backwards compatibility thing where we don't want to cause different
@@ -182,6 +395,7 @@ func NewConfig() *Config {
 	c.Cluster.ReplicaN = 1
 	c.Cluster.Hosts = []string{}
 	c.Cluster.LongQueryTime = toml.Duration(time.Minute)
+	c.Cluster.ResourceInterval = toml.Duration(30 * time.Second)
 
 	// Gossip config.
 	c.Gossip.Port = "14000"
@@ -194,6 +408,56 @@ func NewConfig() *Config {
 	c.Gossip.Nodes = 3
 	c.Gossip.ToTheDeadTime = toml.Duration(30 * time.Second)
 
+	// ResultHandles config.
+	c.ResultHandles.TTL = toml.Duration(10 * time.Minute)
+	c.ResultHandles.MaxMemory = 256 << 20
+
+	// OperationIDs config.
+	c.OperationIDs.Max = 1000
+	c.OperationIDs.TTL = toml.Duration(10 * time.Minute)
+
+	// ChangeStreams config.
+	c.ChangeStreams.BufferSize = 10000
+	c.ChangeStreams.Retention = toml.Duration(time.Hour)
+
+	// Audit config.
+	c.Audit.MaxSize = 100 << 20
+	c.Audit.MaxBackups = 5
+
+	// DeleteJobs config.
+	c.DeleteJobs.Concurrency = 4
+
+	// WarmJobs config.
+	c.WarmJobs.Concurrency = 2
+
+	// Replication config.
+	c.Replication.Interval = toml.Duration(time.Second)
+	c.Replication.BatchSize = 100
+	c.Replication.ReconcileInterval = toml.Duration(10 * time.Minute)
+
+	// HTTP2 config.
+	c.HTTP2.Enabled = true
+	c.HTTP2.MaxConnsPerPeer = 2
+	c.HTTP2.MaxStreamsPerConn = 100
+	c.HTTP2.PingInterval = toml.Duration(15 * time.Second)
+	c.HTTP2.PingTimeout = toml.Duration(5 * time.Second)
+
+	// SnapshotReads config.
+	c.SnapshotReads.MaxMemory = 256 << 20
+	c.SnapshotReads.MaxFragmentMemory = 32 << 20
+	c.SnapshotReads.Timeout = toml.Duration(10 * time.Second)
+
+	// Trash config.
+	c.Trash.Retention = toml.Duration(24 * time.Hour)
+
+	// RetainedSnapshots config.
+	c.RetainedSnapshots.Interval = toml.Duration(24 * time.Hour)
+	c.RetainedSnapshots.CacheSize = 64
+
+	// StatsHistory config.
+	c.StatsHistory.Retention = toml.Duration(6 * time.Hour)
+	c.StatsHistory.MaxIndexes = 100
+
 	// AntiEntropy config.
 	c.AntiEntropy.Interval = toml.Duration(10 * time.Minute)
 
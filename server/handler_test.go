@@ -63,6 +63,7 @@ func TestHandler_PostSchemaCluster(t *testing.T) {
 			if idx.Name() != "blah" {
 				t.Fatalf("index did not get set, got %v", idx.Name())
 			}
+			idx.Close()
 
 			fld, err := cmd.API.Field(context.Background(), "blah", "f1")
 			if err != nil {
@@ -124,6 +125,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		if idx.Name() != "blah" {
 			t.Fatalf("index did not get set, got %v", idx.Name())
 		}
+		idx.Close()
 
 		fld, err := cmd.API.Field(context.Background(), "blah", "f1")
 		if err != nil {
@@ -202,6 +204,341 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Field", func(t *testing.T) {
+		idx := hldr.MustCreateIndexIfNotExists("maxrow", pilosa.IndexOptions{})
+		if f, err := idx.CreateFieldIfNotExists("f", pilosa.OptFieldTypeDefault()); err != nil {
+			t.Fatal(err)
+		} else if _, err := f.SetBit(9, 1, nil); err != nil {
+			t.Fatal(err)
+		} else if _, err := f.SetBit(3, pilosa.ShardWidth+1, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/maxrow/field/f", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+		if body, target := w.Body.String(), `{"name":"f","options":{"type":"set","cacheType":"ranked","cacheSize":50000,"keys":false},"maxRowID":9}
+`; body != target {
+			t.Fatalf("%s != %s", target, body)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/maxrow/field/nope", nil))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+
+		if err := holder.DeleteIndex("maxrow"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("SchemaGeneration", func(t *testing.T) {
+		etag := func() string {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/schema", nil))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d", w.Code)
+			} else if w.Header().Get("ETag") == "" {
+				t.Fatal("expected ETag header")
+			}
+			return w.Header().Get("ETag")
+		}
+		do := func(method, path, ifMatch, body string) int {
+			w := httptest.NewRecorder()
+			req := test.MustNewHTTPRequest(method, path, strings.NewReader(body))
+			req.Header.Set("If-Match", ifMatch)
+			h.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		stale := etag()
+		if code := do("POST", "/index/gen", stale, ""); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code creating index: %d", code)
+		}
+		if code := do("POST", "/index/gen/field/f", stale, ""); code != gohttp.StatusConflict {
+			t.Fatalf("unexpected status code with stale If-Match: %d", code)
+		} else if code := do("POST", "/index/gen/field/f", "bad", ""); code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code with invalid If-Match: %d", code)
+		} else if code := do("POST", "/index/gen/field/f", etag(), ""); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code creating field: %d", code)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/gen/field/f", nil))
+		if got := w.Header().Get("ETag"); got != etag() {
+			t.Fatalf("unexpected field ETag: %s", got)
+		}
+
+		if code := do("DELETE", "/index/gen", stale, ""); code != gohttp.StatusConflict {
+			t.Fatalf("unexpected status code with stale If-Match: %d", code)
+		} else if code := do("DELETE", "/index/gen", etag(), ""); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code deleting index: %d", code)
+		}
+	})
+
+	t.Run("PatchIndex", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("ro", pilosa.IndexOptions{})
+		defer func() {
+			if err := holder.DeleteIndex("ro"); err != nil {
+				t.Fatal(err)
+			}
+		}()
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/ro", body: `{"options":{"readOnly":true}}`, code: gohttp.StatusOK},
+			{path: "/index/ro", body: `{"options":{"keys":true}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/ro", body: `{"options":{}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/nope", body: `{"options":{"readOnly":true}}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("PATCH %s %s: unexpected status code: %d", tt.path, tt.body, w.Code)
+			}
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/ro", nil))
+		if !strings.Contains(w.Body.String(), `"readOnly":true`) {
+			t.Fatalf("expected read-only option in schema: %s", w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ro/query", strings.NewReader("Set(1, f=1)")))
+		if w.Code != gohttp.StatusConflict {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+	})
+
+	t.Run("PatchIndexQuota", func(t *testing.T) {
+		idx := hldr.MustCreateIndexIfNotExists("quota", pilosa.IndexOptions{})
+		defer func() {
+			if err := holder.DeleteIndex("quota"); err != nil {
+				t.Fatal(err)
+			}
+		}()
+		if _, err := idx.CreateField("f"); err != nil {
+			t.Fatal(err)
+		}
+
+		do := func(method, path, body string) int {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest(method, path, strings.NewReader(body)))
+			return w.Code
+		}
+		for _, tt := range []struct {
+			body string
+			code int
+		}{
+			{body: `{"options":{"quota":1}}`, code: gohttp.StatusOK},
+			{body: `{"options":{"quota":-1}}`, code: gohttp.StatusBadRequest},
+			{body: `{"options":{"quota":"1"}}`, code: gohttp.StatusBadRequest},
+		} {
+			if code := do("PATCH", "/index/quota", tt.body); code != tt.code {
+				t.Fatalf("PATCH %s: unexpected status code: %d", tt.body, code)
+			}
+		}
+
+		if code := do("POST", "/index/quota/query", "Set(1, f=1)"); code != gohttp.StatusInsufficientStorage {
+			t.Fatalf("unexpected status code: %d", code)
+		} else if code := do("POST", "/index/quota/query", "Clear(1, f=1)"); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", code)
+		} else if code := do("PATCH", "/index/quota", `{"options":{"quota":0}}`); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", code)
+		} else if code := do("POST", "/index/quota/query", "Set(1, f=1)"); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", code)
+		}
+	})
+
+	t.Run("DefaultFieldOptions", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("dfo", pilosa.IndexOptions{})
+		defer func() {
+			if err := holder.DeleteIndex("dfo"); err != nil {
+				t.Fatal(err)
+			}
+		}()
+
+		do := func(method, path, body string) (int, string) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest(method, path, strings.NewReader(body)))
+			return w.Code, w.Body.String()
+		}
+
+		// Without defaults, time fields need a time quantum.
+		if code, _ := do("POST", "/index/dfo/field/t0", `{"options":{"type":"time"}}`); code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d", code)
+		}
+		for _, body := range []string{
+			`{"options":{"defaultFieldOptions":{"cacheType":"nope"}}}`,
+			`{"options":{"defaultFieldOptions":{"timeQuantum":"WY"}}}`,
+			`{"options":{"defaultFieldOptions":{"unknown":1}}}`,
+		} {
+			if code, _ := do("PATCH", "/index/dfo", body); code != gohttp.StatusBadRequest {
+				t.Fatalf("PATCH %s: unexpected status code: %d", body, code)
+			}
+		}
+		if code, body := do("PATCH", "/index/dfo", `{"options":{"defaultFieldOptions":{"cacheType":"lru","cacheSize":100,"timeQuantum":"YMD"}}}`); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", code, body)
+		}
+		if _, body := do("GET", "/schema", ""); !strings.Contains(body, `"defaultFieldOptions":{"cacheType":"lru","cacheSize":100,"timeQuantum":"YMD"}`) {
+			t.Fatalf("expected default field options in schema: %s", body)
+		}
+
+		// Fields inherit the options which aren't given.
+		for _, tt := range []struct {
+			field string
+			body  string
+			exp   string
+		}{
+			{field: "s", body: ``, exp: `"options":{"type":"set","cacheType":"lru","cacheSize":100,"keys":false}`},
+			{field: "s2", body: `{"options":{"cacheSize":10}}`, exp: `"options":{"type":"set","cacheType":"lru","cacheSize":10,"keys":false}`},
+			{field: "m", body: `{"options":{"type":"mutex","cacheType":"ranked"}}`, exp: `"options":{"type":"mutex","cacheType":"ranked","cacheSize":100,"keys":false}`},
+			{field: "t", body: `{"options":{"type":"time"}}`, exp: `"timeQuantum":"YMD"`},
+			{field: "t2", body: `{"options":{"type":"time","timeQuantum":"Y"}}`, exp: `"timeQuantum":"Y"`},
+		} {
+			if code, body := do("POST", "/index/dfo/field/"+tt.field, tt.body); code != gohttp.StatusOK {
+				t.Fatalf("field %s: unexpected status code: %d %s", tt.field, code, body)
+			} else if !strings.Contains(body, tt.exp) {
+				t.Fatalf("field %s: unexpected body: %s", tt.field, body)
+			}
+		}
+
+		// Removing the defaults doesn't change existing fields.
+		if code, _ := do("PATCH", "/index/dfo", `{"options":{"defaultFieldOptions":null}}`); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", code)
+		} else if _, body := do("GET", "/schema", ""); strings.Contains(body, `"defaultFieldOptions"`) {
+			t.Fatalf("unexpected default field options in schema: %s", body)
+		} else if opt := hldr.Index("dfo").Field("s").Options(); opt.CacheType != "lru" || opt.CacheSize != 100 {
+			t.Fatalf("unexpected cache of existing field: %s %d", opt.CacheType, opt.CacheSize)
+		} else if _, body := do("POST", "/index/dfo/field/s3", ""); !strings.Contains(body, `"cacheType":"ranked","cacheSize":50000`) {
+			t.Fatalf("unexpected body: %s", body)
+		}
+	})
+
+	t.Run("PatchField", func(t *testing.T) {
+		idx := hldr.MustCreateIndexIfNotExists("tq", pilosa.IndexOptions{})
+		defer func() {
+			if err := holder.DeleteIndex("tq"); err != nil {
+				t.Fatal(err)
+			}
+		}()
+		if _, err := idx.CreateField("t", pilosa.OptFieldTypeTime("YMD")); err != nil {
+			t.Fatal(err)
+		} else if _, err := idx.CreateField("s"); err != nil {
+			t.Fatal(err)
+		}
+
+		query := func(q string) string {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/tq/query", strings.NewReader(q)))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+			}
+			return w.Body.String()
+		}
+		query("Set(1, t=1, 2019-01-21T00:00)")
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/tq/field/s", body: `{"options":{"timeQuantum":"YMWD"}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/tq/field/t", body: `{"options":{"timeQuantum":"WY"}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/tq/field/t", body: `{"options":{"keys":true}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/tq/field/nope", body: `{"options":{"timeQuantum":"YMWD"}}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("PATCH %s %s: unexpected status code: %d", tt.path, tt.body, w.Code)
+			}
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", "/index/tq/field/t", strings.NewReader(`{"options":{"timeQuantum":"YMWD"}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if body := w.Body.String(); !strings.Contains(body, `"addedUnits":"W"`) || !strings.Contains(body, `"warning":`) {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		// Only writes after the change populate the week views, so range
+		// queries over earlier weeks still read the day views.
+		query("Set(2, t=1, 2019-01-30T00:00)")
+		if body := query("Range(t=1, 2019-01-21T00:00, 2019-01-28T00:00)"); !strings.Contains(body, `"columns":[1]`) {
+			t.Fatalf("unexpected result for week before change: %s", body)
+		} else if body := query("Range(t=1, 2019-01-28T00:00, 2019-02-04T00:00)"); !strings.Contains(body, `"columns":[2]`) {
+			t.Fatalf("unexpected result for week after change: %s", body)
+		} else if body := query("Range(t=1, 2019-01-01T00:00, 2019-03-01T00:00)"); !strings.Contains(body, `"columns":[1,2]`) {
+			t.Fatalf("unexpected result for weeks around change: %s", body)
+		}
+	})
+
+	t.Run("IngestMapping", func(t *testing.T) {
+		idx := hldr.MustCreateIndexIfNotExists("ing", pilosa.IndexOptions{})
+		defer func() {
+			if err := holder.DeleteIndex("ing"); err != nil {
+				t.Fatal(err)
+			}
+		}()
+		if _, err := idx.CreateField("color"); err != nil {
+			t.Fatal(err)
+		}
+
+		do := func(method, path, body string) (int, string) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest(method, path, strings.NewReader(body)))
+			return w.Code, w.Body.String()
+		}
+		mapping := `{"fields":[{"name":"id","primaryKey":true},{"name":"color","actions":[{"field":"color","type":"row-per-value"}]}]}`
+
+		for _, tt := range []struct {
+			method string
+			path   string
+			body   string
+			code   int
+		}{
+			{method: "POST", path: "/index/ing/ingest-mapping/m", body: `{"fields":[{"name":"id"}]}`, code: gohttp.StatusBadRequest},
+			{method: "POST", path: "/index/ing/ingest-mapping/m", body: `{"name":"other","fields":[]}`, code: gohttp.StatusBadRequest},
+			{method: "POST", path: "/index/ing/ingest-mapping/m", body: `{"bogus":1}`, code: gohttp.StatusBadRequest},
+			{method: "POST", path: "/index/nope/ingest-mapping/m", body: mapping, code: gohttp.StatusNotFound},
+			{method: "POST", path: "/index/ing/ingest-mapping/m", body: mapping, code: gohttp.StatusOK},
+			{method: "POST", path: "/index/ing/ingest-mapping/m", body: mapping, code: gohttp.StatusConflict},
+			{method: "GET", path: "/index/ing/ingest-mapping/m", code: gohttp.StatusOK},
+			{method: "GET", path: "/index/ing/ingest-mapping/nope", code: gohttp.StatusNotFound},
+			{method: "POST", path: "/index/ing/input/m", body: `[{"id":1,"color":3},{"id":2,"color":"x"}]`, code: gohttp.StatusBadRequest},
+			{method: "POST", path: "/index/ing/input/m", body: `{"id":1}`, code: gohttp.StatusBadRequest},
+			{method: "POST", path: "/index/ing/input/m", body: `[{"id":1,"color":3},{"id":2000000,"color":3}]`, code: gohttp.StatusOK},
+			{method: "POST", path: "/index/ing/input/nope", body: `[]`, code: gohttp.StatusNotFound},
+		} {
+			if code, body := do(tt.method, tt.path, tt.body); code != tt.code {
+				t.Fatalf("%s %s %s: unexpected status code: %d %s", tt.method, tt.path, tt.body, code, body)
+			}
+		}
+
+		if _, body := do("POST", "/index/ing/input/m", `[{"id":1,"color":3},{"id":2,"color":"x"}]`); !strings.Contains(body, `record 1: field \"color\"`) {
+			t.Fatalf("unexpected error body: %s", body)
+		} else if _, body := do("POST", "/index/ing/query", "Row(color=3)"); !strings.Contains(body, `"columns":[1,2000000]`) {
+			t.Fatalf("unexpected query result: %s", body)
+		} else if _, body := do("GET", "/schema", ""); !strings.Contains(body, `"ingestMappings":[{"name":"m"`) {
+			t.Fatalf("unexpected schema: %s", body)
+		}
+
+		if code, _ := do("DELETE", "/index/ing/ingest-mapping/m", ""); code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", code)
+		} else if code, _ := do("DELETE", "/index/ing/ingest-mapping/m", ""); code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d", code)
+		}
+	})
+
 	t.Run("ImportRoaring", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		roaringData, _ := hex.DecodeString("3B3001000100000900010000000100010009000100")
@@ -271,6 +608,9 @@ func TestHandler_Endpoints(t *testing.T) {
 		if len(ret["nodes"].([]interface{})) != 1 {
 			t.Fatalf("wrong length nodes list: %#v", ret)
 		}
+		if resources, ok := ret["resources"].(map[string]interface{}); !ok || resources["fileLimit"].(float64) == 0 {
+			t.Fatalf("missing resources from /status: %#v", ret)
+		}
 	})
 
 	t.Run("Abort no resize job", func(t *testing.T) {
@@ -308,11 +648,99 @@ func TestHandler_Endpoints(t *testing.T) {
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=0,1", strings.NewReader("Count(Row(f0=30))")))
 		if w.Code != gohttp.StatusOK {
 			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
-		} else if body := w.Body.String(); body != `{"results":[2]}`+"\n" {
+		} else if body := w.Body.String(); body != `{"results":[2],"shards":[0,1]}`+"\n" {
+			t.Fatalf("unexpected body: %q", body)
+		}
+	})
+
+	t.Run("Shards range args", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=0-1,3", strings.NewReader("Count(Row(f0=30))")))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"results":[3],"shards":[0,1,3]}`+"\n" {
+			t.Fatalf("unexpected body: %q", body)
+		}
+	})
+
+	t.Run("Shards out of range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=2-4", strings.NewReader("Count(Row(f0=30))")))
+		if w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); !strings.Contains(body, "shard 4 exceeds max shard 3") {
 			t.Fatalf("unexpected body: %q", body)
 		}
 	})
 
+	t.Run("Public fragment blocks", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/fragment/blocks?index=i0&field=f0&view=standard&shard=1", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+		var blocks pilosa.FragmentBlocksResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &blocks); err != nil {
+			t.Fatal(err)
+		} else if len(blocks.Blocks) != 1 || blocks.Blocks[0].ID != 0 || len(blocks.Blocks[0].Checksum) == 0 {
+			t.Fatalf("unexpected blocks: %+v", blocks)
+		}
+
+		// The protobuf response contains the same blocks.
+		r := test.MustNewHTTPRequest("GET", "/fragment/blocks?index=i0&field=f0&view=standard&shard=1", nil)
+		r.Header.Set("Accept", "application/x-protobuf")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		var pbBlocks pilosa.FragmentBlocksResponse
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		} else if err := cmd.API.Serializer.Unmarshal(w.Body.Bytes(), &pbBlocks); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(pbBlocks, blocks) {
+			t.Fatalf("unexpected protobuf blocks: %+v", pbBlocks)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/fragment/blocks?index=i0&field=f0&view=standard&shard=2", nil))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Public fragment block data", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/fragment/block/data?index=i0&field=f0&view=standard&shard=1&block=0&limit=1", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+		var resp pilosa.BlockDataResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(resp.RowIDs, []uint64{30}) || !reflect.DeepEqual(resp.ColumnIDs, []uint64{1}) || resp.Continuation == 0 {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+
+		// Read the rest of the block as protobuf.
+		r := test.MustNewHTTPRequest("GET", fmt.Sprintf("/fragment/block/data?index=i0&field=f0&view=standard&shard=1&block=0&continuation=%d", resp.Continuation), nil)
+		r.Header.Set("Accept", "application/x-protobuf")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		resp = pilosa.BlockDataResponse{}
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		} else if err := cmd.API.Serializer.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(resp.RowIDs, []uint64{30}) || !reflect.DeepEqual(resp.ColumnIDs, []uint64{2}) || resp.Continuation != 0 {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/fragment/block/data?index=i0&field=f0&view=standard&shard=1", nil))
+		if w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+	})
+
 	t.Run("Shards args protobuf", func(t *testing.T) {
 		// Generate request body.
 		reqBody, err := cmd.API.Serializer.Marshal(&pilosa.QueryRequest{
@@ -332,7 +760,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		h.ServeHTTP(w, req)
 		if w.Code != gohttp.StatusOK {
 			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if body := w.Body.String(); body != `{"results":[2]}`+"\n" {
+		} else if body := w.Body.String(); body != `{"results":[2],"shards":[0,1]}`+"\n" {
 			t.Fatalf("unexpected body: %q", body)
 		} else if w.Header().Get("Content-Type") != "application/json" {
 			t.Fatalf("unexpected header: %q", w.Header().Get("Content-Type"))
@@ -350,6 +778,16 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Query too many shards", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=0-1000000,0-1000000", strings.NewReader("Count(Row(f0=30))")))
+		if w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if body := w.Body.String(); body != `{"error":"invalid shard argument"}`+"\n" {
+			t.Fatalf("unexpected body: %q", body)
+		}
+	})
+
 	t.Run("Query params err", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=0,1&db=sample", strings.NewReader("Count(Row(f0=30))")))
@@ -360,6 +798,30 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Sessions", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?session=s0&storeAs=h", strings.NewReader("Row(f0=30)")))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?session=s0", strings.NewReader(`Count(Handle(name="h"))`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("DELETE", "/sessions/s0", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?session=s0", strings.NewReader(`Count(Handle(name="h"))`)))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+	})
+
 	t.Run("Uint64 protobuf", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		r := test.MustNewHTTPRequest("POST", "/index/i0/query", strings.NewReader("Count(Row(f0=30))"))
@@ -384,7 +846,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query", strings.NewReader("Row(f0=30)")))
 		if w.Code != gohttp.StatusOK {
 			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if body := w.Body.String(); body != fmt.Sprintf(`{"results":[{"attrs":{},"columns":[%d,%d,%d]}]}`, pilosa.ShardWidth+1, pilosa.ShardWidth+2, 3*pilosa.ShardWidth+4)+"\n" {
+		} else if body := w.Body.String(); body != fmt.Sprintf(`{"results":[{"attrs":{},"columns":[%d,%d,%d]}],"shards":[0,1,3]}`, pilosa.ShardWidth+1, pilosa.ShardWidth+2, 3*pilosa.ShardWidth+4)+"\n" {
 			t.Fatalf("unexpected body: %s", body)
 		}
 	})
@@ -401,7 +863,7 @@ func TestHandler_Endpoints(t *testing.T) {
 	t.Run("ColumnAttrs_JSON", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?columnAttrs=true", strings.NewReader("Row(f0=30)")))
-		exp := fmt.Sprintf(`{"results":[{"attrs":{"a":"b","c":1,"d":true},"columns":[%[1]d,%[2]d,%[3]d]}],"columnAttrs":[{"id":%[1]d,"attrs":{"x":"y"}},{"id":%[2]d,"attrs":{"y":123,"z":false}}]}`, pilosa.ShardWidth+1, pilosa.ShardWidth+2, 3*pilosa.ShardWidth+4) + "\n"
+		exp := fmt.Sprintf(`{"results":[{"attrs":{"a":"b","c":1,"d":true},"columns":[%[1]d,%[2]d,%[3]d]}],"columnAttrs":[{"id":%[1]d,"attrs":{"x":"y"}},{"id":%[2]d,"attrs":{"y":123,"z":false}}],"shards":[0,1,3]}`, pilosa.ShardWidth+1, pilosa.ShardWidth+2, 3*pilosa.ShardWidth+4) + "\n"
 		if w.Code != gohttp.StatusOK {
 			t.Fatalf("unexpected status code: %d. body: %s", w.Code, w.Body.String())
 		} else if body := w.Body.String(); body != exp {
@@ -487,7 +949,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query", strings.NewReader(`TopN(f0, n=2)`)))
 		if w.Code != gohttp.StatusOK {
 			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if body := w.Body.String(); body != `{"results":[[{"id":30,"count":3},{"id":31,"count":1}]]}`+"\n" {
+		} else if body := w.Body.String(); body != `{"results":[[{"id":30,"count":3},{"id":31,"count":1}]],"shards":[0,1,3]}`+"\n" {
 			t.Fatalf("unexpected body: %q", body)
 		}
 	})
@@ -631,6 +1093,40 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Query decimal field", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		fieldName := "f-decimal"
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", fmt.Sprintf("/index/i0/field/%s", fieldName),
+			strings.NewReader(`{"options":{"type":"int", "min": -10.5, "max": 1000, "scale": 2}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/schema", strings.NewReader("")))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if !strings.Contains(w.Body.String(), `"min":-10.50,"max":1000.00,"keys":false,"scale":2`) {
+			t.Fatalf("unexpected schema: %s", w.Body.String())
+		}
+		rsp := getSchemaResponse{}
+		if err := json.Unmarshal(w.Body.Bytes(), &rsp); err != nil {
+			t.Fatalf("json decode: %s", err)
+		}
+		field := rsp.findField("i0", fieldName)
+		if field == nil {
+			t.Fatalf("field not found: %s", fieldName)
+		} else if field.Options.Min != -1050 || field.Options.Max != 100000 || field.Options.Scale != 2 {
+			t.Fatalf("unexpected options: %+v", field.Options)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/field/f-decimal-err",
+			strings.NewReader(`{"options":{"type":"int", "min": 0, "max": 10.555, "scale": 2}}`)))
+		if w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+	})
+
 	t.Run("Query int field min > max return 400", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		fieldName := "f-int-ubound-err"
@@ -811,6 +1307,52 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Routing", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/internal/routing?index=i&shards=0,2-3", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		var table pilosa.RoutingTable
+		if err := json.NewDecoder(w.Body).Decode(&table); err != nil {
+			t.Fatal(err)
+		} else if len(table.Shards) != 3 || table.Shards[1].Shard != 2 || table.Shards[2].Shard != 3 {
+			t.Fatalf("unexpected shards: %+v", table.Shards)
+		}
+		for _, s := range table.Shards {
+			if len(s.Owners) != 1 || s.Owners[0] != cmd.API.Node().URI.String() {
+				t.Fatalf("unexpected owners of shard %d: %v", s.Shard, s.Owners)
+			}
+		}
+
+		// A single shard has the same generation.
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/internal/routing?index=i&shard=1", nil))
+		var single pilosa.RoutingTable
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if err := json.NewDecoder(w.Body).Decode(&single); err != nil {
+			t.Fatal(err)
+		} else if single.Generation != table.Generation || len(single.Shards) != 1 || single.Shards[0].Shard != 1 {
+			t.Fatalf("unexpected routing: %+v", single)
+		}
+
+		for path, code := range map[string]int{
+			"/internal/routing?index=i":              gohttp.StatusBadRequest,
+			"/internal/routing?index=i&shard=x":      gohttp.StatusBadRequest,
+			"/internal/routing?shard=0":              gohttp.StatusBadRequest,
+			"/internal/routing?index=nosuch&shard=0": gohttp.StatusNotFound,
+		} {
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", path, nil))
+			if w.Code != code {
+				t.Fatalf("%s: unexpected status code: %d", path, w.Code)
+			}
+		}
+	})
+
 	t.Run("Expvars", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		r := test.MustNewHTTPRequest("GET", "/debug/vars", nil)
@@ -885,7 +1427,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		h.ServeHTTP(w, r)
 		if w.Code != gohttp.StatusOK {
 			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
+		} else if w.Body.String() != `{"success":true,"options":{"type":"set","cacheType":"ranked","cacheSize":50000,"keys":false}}`+"\n" {
 			t.Fatalf("unexpected body: %q", w.Body.String())
 		}
 
@@ -957,7 +1499,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		h.ServeHTTP(w, r)
 		if w.Code != gohttp.StatusOK {
 			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
+		} else if w.Body.String() != `{"success":true,"options":{"type":"set","cacheType":"ranked","cacheSize":50000,"keys":true}}`+"\n" {
 			t.Fatalf("unexpected body: %q", w.Body.String())
 		}
 
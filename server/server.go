@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"fmt"
 	"io"
 	"log"
 	"math/rand"
@@ -31,6 +32,7 @@ import (
 	"os/signal"
 	"runtime"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -269,8 +271,26 @@ func (m *Command) SetupServer() error {
 	if err != nil {
 		return errors.Wrap(err, "new stats client")
 	}
+	statsClient, err = stats.NewPrefixedStatsClient(statsClient, m.Config.Metric.Prefix, m.Config.Metric.Tags)
+	if err != nil {
+		return errors.Wrap(err, "configuring stats client")
+	}
+
+	http2Options := http.HTTP2Options{
+		Enabled:           m.Config.HTTP2.Enabled,
+		MaxConnsPerPeer:   m.Config.HTTP2.MaxConnsPerPeer,
+		MaxStreamsPerConn: m.Config.HTTP2.MaxStreamsPerConn,
+		PingInterval:      time.Duration(m.Config.HTTP2.PingInterval),
+		PingTimeout:       time.Duration(m.Config.HTTP2.PingTimeout),
+		Stats:             statsClient,
+	}
 
-	m.ln, err = getListener(*uri, TLSConfig)
+	listenTLSConfig := TLSConfig
+	if TLSConfig != nil && http2Options.Enabled {
+		listenTLSConfig = TLSConfig.Clone()
+		listenTLSConfig.NextProtos = []string{"h2", "http/1.1"}
+	}
+	m.ln, err = getListener(*uri, listenTLSConfig)
 	if err != nil {
 		return errors.Wrap(err, "getting listener")
 	}
@@ -283,7 +303,7 @@ func (m *Command) SetupServer() error {
 	// Save listenURI for later reference.
 	m.listenURI = uri
 
-	c := http.GetHTTPClient(TLSConfig)
+	c := http.GetInternalHTTPClient(TLSConfig, http2Options)
 
 	// Get advertise address as uri.
 	advertiseURI, err := pilosa.AddressWithDefaults(m.Config.Advertise)
@@ -301,19 +321,70 @@ func (m *Command) SetupServer() error {
 
 	// Set Coordinator.
 	coordinatorOpt := pilosa.OptServerIsCoordinator(false)
-	if m.Config.Cluster.Coordinator || len(m.Config.Gossip.Seeds) == 0 {
+	if m.Config.Cluster.Coordinator || m.Config.Cluster.ForceSingleNode || len(m.Config.Gossip.Seeds) == 0 {
 		coordinatorOpt = pilosa.OptServerIsCoordinator(true)
 	}
 
+	warmupOpt, err := warmupOptions(m.Config)
+	if err != nil {
+		return errors.Wrap(err, "parsing warm-up")
+	}
+	replicationOpt, err := replicationOptions(m.Config)
+	if err != nil {
+		return errors.Wrap(err, "parsing replication")
+	} else if replicationOpt.Target != nil {
+		replicationOpt.Client = http.NewInternalClientFromURI(replicationOpt.Target, c)
+	}
+
 	serverOptions := []pilosa.ServerOption{
 		pilosa.OptServerAntiEntropyInterval(time.Duration(m.Config.AntiEntropy.Interval)),
 		pilosa.OptServerLongQueryTime(time.Duration(m.Config.Cluster.LongQueryTime)),
 		pilosa.OptServerDataDir(m.Config.DataDir),
 		pilosa.OptServerReplicaN(m.Config.Cluster.ReplicaN),
+		pilosa.OptServerColdStartQuorum(m.Config.Cluster.ColdStartQuorum),
+		pilosa.OptServerForceSingleNode(m.Config.Cluster.ForceSingleNode),
+		pilosa.OptServerResourceInterval(time.Duration(m.Config.Cluster.ResourceInterval)),
+		pilosa.OptServerCheckJoinDisk(m.Config.Cluster.CheckJoinDisk),
+		pilosa.OptServerMinProtocolVersion(m.Config.Cluster.MinProtocolVersion),
 		pilosa.OptServerMaxWritesPerRequest(m.Config.MaxWritesPerRequest),
+		pilosa.OptServerStrictImports(m.Config.StrictImports),
 		pilosa.OptServerMetricInterval(time.Duration(m.Config.Metric.PollInterval)),
 		pilosa.OptServerDiagnosticsInterval(diagnosticsInterval),
 		pilosa.OptServerExecutorPoolSize(m.Config.WorkerPoolSize),
+		pilosa.OptServerResultHandles(time.Duration(m.Config.ResultHandles.TTL), m.Config.ResultHandles.MaxMemory),
+		pilosa.OptServerOperationIDs(m.Config.OperationIDs.Max, time.Duration(m.Config.OperationIDs.TTL)),
+		pilosa.OptServerChangeStreams(m.Config.ChangeStreams.BufferSize, time.Duration(m.Config.ChangeStreams.Retention)),
+		pilosa.OptServerAuditLog(pilosa.AuditOptions{
+			Enabled:    m.Config.Audit.Enabled,
+			Path:       m.Config.Audit.Path,
+			MaxSize:    m.Config.Audit.MaxSize,
+			MaxBackups: m.Config.Audit.MaxBackups,
+			Webhook:    m.Config.Audit.Webhook,
+		}),
+		pilosa.OptServerDeleteJobs(pilosa.DeleteJobOptions{
+			Concurrency: m.Config.DeleteJobs.Concurrency,
+			Rate:        m.Config.DeleteJobs.Rate,
+		}),
+		pilosa.OptServerWarmJobs(pilosa.WarmJobOptions{
+			Concurrency: m.Config.WarmJobs.Concurrency,
+			Rate:        m.Config.WarmJobs.Rate,
+		}),
+		pilosa.OptServerWarmup(warmupOpt),
+		pilosa.OptServerReplication(replicationOpt),
+		pilosa.OptServerSnapshotReads(pilosa.SnapshotReadOptions{
+			MaxMemory:         m.Config.SnapshotReads.MaxMemory,
+			MaxFragmentMemory: m.Config.SnapshotReads.MaxFragmentMemory,
+			Timeout:           time.Duration(m.Config.SnapshotReads.Timeout),
+		}),
+		pilosa.OptServerTrashRetention(time.Duration(m.Config.Trash.Retention)),
+		pilosa.OptServerTopNCacheWait(time.Duration(m.Config.TopNCacheWait)),
+		pilosa.OptServerAdmission(m.Config.Admission.HighMemory, m.Config.Admission.CriticalMemory),
+		pilosa.OptServerRetainedSnapshots(time.Duration(m.Config.RetainedSnapshots.Interval), m.Config.RetainedSnapshots.CacheSize),
+		pilosa.OptServerReadVerification(pilosa.ReadVerifyOptions{
+			Rate:   m.Config.ReadVerification.Rate,
+			Repair: m.Config.ReadVerification.Repair,
+		}),
+		pilosa.OptServerStatsHistory(time.Duration(m.Config.StatsHistory.Retention), m.Config.StatsHistory.MaxIndexes),
 		pilosa.OptServerOpenTranslateStore(boltdb.OpenTranslateStore),
 		pilosa.OptServerOpenTranslateReader(http.GetOpenTranslateReaderFunc(c)),
 		pilosa.OptServerLogger(m.logger),
@@ -350,6 +421,7 @@ func (m *Command) SetupServer() error {
 		http.OptHandlerLogger(m.logger),
 		http.OptHandlerListener(m.ln),
 		http.OptHandlerCloseTimeout(m.closeTimeout),
+		http.OptHandlerHTTP2(http2Options),
 	)
 	return errors.Wrap(err, "new handler")
 }
@@ -415,6 +487,46 @@ func (m *Command) Close() error {
 	return errors.Wrap(err, "closing everything")
 }
 
+// warmupOptions returns the warm-up queries of the configuration, with a
+// TopN() query for each of its TopN fields.
+func warmupOptions(c *Config) (pilosa.WarmupOptions, error) {
+	opt := pilosa.WarmupOptions{Timeout: time.Duration(c.Warmup.Timeout)}
+	for _, s := range c.Warmup.Queries {
+		i := strings.Index(s, ":")
+		if i <= 0 {
+			return opt, errors.Errorf("query without an index: %s", s)
+		}
+		opt.Queries = append(opt.Queries, pilosa.WarmupQuery{Index: s[:i], Query: s[i+1:]})
+	}
+	for _, s := range c.Warmup.TopNFields {
+		a := strings.Split(s, ":")
+		if len(a) != 2 || a[0] == "" || a[1] == "" {
+			return opt, errors.Errorf("invalid TopN field, expected index:field: %s", s)
+		}
+		opt.Queries = append(opt.Queries, pilosa.WarmupQuery{Index: a[0], Query: fmt.Sprintf("TopN(%s)", a[1])})
+	}
+	return opt, nil
+}
+
+// replicationOptions returns the replication of the configuration, without
+// the client of the cluster replicated to.
+func replicationOptions(c *Config) (pilosa.ReplicationOptions, error) {
+	opt := pilosa.ReplicationOptions{
+		Interval:          time.Duration(c.Replication.Interval),
+		BatchSize:         c.Replication.BatchSize,
+		ReconcileInterval: time.Duration(c.Replication.ReconcileInterval),
+	}
+	if c.Replication.Target == "" {
+		return opt, nil
+	}
+	target, err := pilosa.NewURIFromAddress(c.Replication.Target)
+	if err != nil {
+		return opt, errors.Wrap(err, "parsing target")
+	}
+	opt.Target = target
+	return opt, nil
+}
+
 // newStatsClient creates a stats client from the config
 func newStatsClient(name string, host string) (stats.StatsClient, error) {
 	switch name {
@@ -422,12 +534,14 @@ func newStatsClient(name string, host string) (stats.StatsClient, error) {
 		return stats.NewExpvarStatsClient(), nil
 	case "statsd":
 		return statsd.NewStatsClient(host)
+	case "statsd-plain":
+		return statsd.NewPlainStatsClient(host)
 	case "prometheus":
 		return prometheus.NewPrometheusClient()
 	case "nop", "none":
 		return stats.NopStatsClient, nil
 	default:
-		return nil, errors.Errorf("'%v' not a valid stats client, choose from [expvar, statsd, none].", name)
+		return nil, errors.Errorf("'%v' not a valid stats client, choose from [expvar, statsd, statsd-plain, prometheus, none].", name)
 	}
 }
 
@@ -22,6 +22,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	gohttp "net/http"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
@@ -84,6 +87,7 @@ func TestMain_Set_Quick(t *testing.T) {
 							"attrs":   map[string]interface{}{},
 						},
 					},
+					"shards": []uint64{0},
 				}) + "\n"
 				if res, err := m.Query("i", "", fmt.Sprintf(`Row(%s=%d)`, field, id)); err != nil {
 					t.Fatal(err)
@@ -107,6 +111,7 @@ func TestMain_Set_Quick(t *testing.T) {
 							"attrs":   map[string]interface{}{},
 						},
 					},
+					"shards": []uint64{0},
 				}) + "\n"
 				if res, err := m.Query("i", "", fmt.Sprintf(`Row(%s=%d)`, field, id)); err != nil {
 					t.Fatal(err)
@@ -168,14 +173,14 @@ func TestMain_SetRowAttrs(t *testing.T) {
 	// Query row x/1.
 	if res, err := m.Query("i", "", `Row(x=1)`); err != nil {
 		t.Fatal(err)
-	} else if res != `{"results":[{"attrs":{"x":100},"columns":[100]}]}`+"\n" {
+	} else if res != `{"results":[{"attrs":{"x":100},"columns":[100]}],"shards":[0]}`+"\n" {
 		t.Fatalf("unexpected result: %s", res)
 	}
 
 	// Query row x/2.
 	if res, err := m.Query("i", "", `Row(x=2)`); err != nil {
 		t.Fatal(err)
-	} else if res != `{"results":[{"attrs":{"x":-200},"columns":[100]}]}`+"\n" {
+	} else if res != `{"results":[{"attrs":{"x":-200},"columns":[100]}],"shards":[0]}`+"\n" {
 		t.Fatalf("unexpected result: %s", res)
 	}
 
@@ -186,19 +191,19 @@ func TestMain_SetRowAttrs(t *testing.T) {
 	// Query rows after reopening.
 	if res, err := m.Query("i", "columnAttrs=true", `Row(x=1)`); err != nil {
 		t.Fatal(err)
-	} else if res != `{"results":[{"attrs":{"x":100},"columns":[100]}]}`+"\n" {
+	} else if res != `{"results":[{"attrs":{"x":100},"columns":[100]}],"shards":[0]}`+"\n" {
 		t.Fatalf("unexpected result(reopen): %s", res)
 	}
 
 	if res, err := m.Query("i", "columnAttrs=true", `Row(neg=3)`); err != nil {
 		t.Fatal(err)
-	} else if res != `{"results":[{"attrs":{"x":-0.44},"columns":[100]}]}`+"\n" {
+	} else if res != `{"results":[{"attrs":{"x":-0.44},"columns":[100]}],"shards":[0]}`+"\n" {
 		t.Fatalf("unexpected result(reopen): %s", res)
 	}
 	// Query row x/2.
 	if res, err := m.Query("i", "", `Row(x=2)`); err != nil {
 		t.Fatal(err)
-	} else if res != `{"results":[{"attrs":{"x":-200},"columns":[100]}]}`+"\n" {
+	} else if res != `{"results":[{"attrs":{"x":-200},"columns":[100]}],"shards":[0]}`+"\n" {
 		t.Fatalf("unexpected result: %s", res)
 	}
 }
@@ -231,7 +236,7 @@ func TestMain_SetColumnAttrs(t *testing.T) {
 	// Query row.
 	if res, err := m.Query("i", "columnAttrs=true", `Row(x=1)`); err != nil {
 		t.Fatal(err)
-	} else if res != `{"results":[{"attrs":{},"columns":[100,101]}],"columnAttrs":[{"id":100,"attrs":{"foo":"bar"}}]}`+"\n" {
+	} else if res != `{"results":[{"attrs":{},"columns":[100,101]}],"columnAttrs":[{"id":100,"attrs":{"foo":"bar"}}],"shards":[0]}`+"\n" {
 		t.Fatalf("unexpected result: %s", res)
 	}
 
@@ -242,7 +247,7 @@ func TestMain_SetColumnAttrs(t *testing.T) {
 	// Query row after reopening.
 	if res, err := m.Query("i", "columnAttrs=true", `Row(x=1)`); err != nil {
 		t.Fatal(err)
-	} else if res != `{"results":[{"attrs":{},"columns":[100,101]}],"columnAttrs":[{"id":100,"attrs":{"foo":"bar"}}]}`+"\n" {
+	} else if res != `{"results":[{"attrs":{},"columns":[100,101]}],"columnAttrs":[{"id":100,"attrs":{"foo":"bar"}}],"shards":[0]}`+"\n" {
 		t.Fatalf("unexpected result(reopen): %s", res)
 	}
 }
@@ -373,7 +378,7 @@ func TestMain_RecalculateHashes(t *testing.T) {
 		t.Fatalf("recalculating caches: %v", err)
 	}
 
-	target := `{"results":[[{"id":7,"count":99},{"id":1,"count":99},{"id":9,"count":99},{"id":5,"count":99},{"id":4,"count":99},{"id":8,"count":99},{"id":2,"count":99},{"id":6,"count":99},{"id":3,"count":99}]]}`
+	target := `{"results":[[{"id":7,"count":99},{"id":1,"count":99},{"id":9,"count":99},{"id":5,"count":99},{"id":4,"count":99},{"id":8,"count":99},{"id":2,"count":99},{"id":6,"count":99},{"id":3,"count":99}]],"shards":[0]}`
 
 	// Run a TopN query on all nodes. The result should be the same as the target.
 	for _, m := range cluster {
@@ -612,6 +617,96 @@ func TestClusteringNodesReplica2(t *testing.T) {
 	}
 }
 
+// Ensure a replica which is stopped and restarted with stale data rejoins
+// the cluster without a resize and converges through anti-entropy.
+func TestClusterNodeRestart_AntiEntropy(t *testing.T) {
+	cluster := test.MustNewCluster(t, 2)
+	for _, c := range cluster {
+		c.Config.Cluster.ReplicaN = 2
+	}
+	if err := cluster.Start(); err != nil {
+		t.Fatalf("starting cluster: %v", err)
+	}
+	defer cluster.Close()
+	waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+
+	gen := &test.Generator{
+		Seed:    1,
+		Index:   "i",
+		Fields:  []test.GeneratorField{{Name: "f", Rows: 10, Density: 0.5}},
+		Columns: pilosa.ShardWidth + 1000,
+	}
+	d := gen.Generate()
+	d.PopulateCluster(t, cluster)
+
+	if err := cluster.StopNode(1); err != nil {
+		t.Fatal(err)
+	}
+	if state := cluster[0].API.State(); state != pilosa.ClusterStateDegraded {
+		t.Fatalf("expected state to be DEGRADED, but got %s", state)
+	}
+
+	// Write to the surviving replica only, so the stopped node's data is stale.
+	hldr0 := test.Holder{Holder: cluster[0].Server.Holder()}
+	hldr0.SetBit("i", "f", 100, 5)
+	hldr0.SetBit("i", "f", 100, pilosa.ShardWidth+5)
+
+	if err := cluster.StartNode(1); err != nil {
+		t.Fatal(err)
+	}
+	waitForClusterState(t, cluster, pilosa.ClusterStateNormal)
+
+	if hosts := cluster[0].API.Hosts(context.Background()); len(hosts) != 2 {
+		t.Fatalf("unexpected hosts: %v", hosts)
+	}
+
+	hldr1 := test.Holder{Holder: cluster[1].Server.Holder()}
+	if n := hldr1.ReadRow("i", "f", 100).Count(); n != 0 {
+		t.Fatalf("expected restarted node to be missing new bits, got %d", n)
+	}
+
+	if err := cluster[1].Server.SyncData(); err != nil {
+		t.Fatalf("syncing data: %v", err)
+	}
+
+	if cols := hldr1.ReadRow("i", "f", 100).Columns(); !reflect.DeepEqual(cols, []uint64{5, pilosa.ShardWidth + 5}) {
+		t.Fatalf("unexpected columns after sync: %v", cols)
+	}
+	for _, rowID := range d.RowIDs("f") {
+		if n, exp := hldr1.ReadRow("i", "f", rowID).Count(), d.Count("f", rowID); n != exp {
+			t.Fatalf("row %d: count=%d, expected %d", rowID, n, exp)
+		}
+	}
+}
+
+// waitForClusterState waits for every running node in the cluster to reach
+// state, failing the test if any node enters RESIZING or the wait times out.
+func waitForClusterState(t *testing.T, cluster test.Cluster, state string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		done := true
+		for i, node := range cluster {
+			if cluster.Stopped(i) {
+				continue
+			}
+			switch node.API.State() {
+			case state:
+			case pilosa.ClusterStateResizing:
+				t.Fatalf("node %d unexpectedly entered RESIZING", i)
+			default:
+				done = false
+			}
+		}
+		if done {
+			return
+		} else if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for cluster state %s", state)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
 func TestRemoveNodeAfterItDies(t *testing.T) {
 	cluster := test.MustNewCluster(t, 3)
 	for _, c := range cluster {
@@ -985,3 +1080,93 @@ func TestClusterExhaustingConnectionsImport(t *testing.T) {
 		t.Fatalf("setting lots of shards: %v", err)
 	}
 }
+
+// Ensure write operations are recorded in the audit log once it is enabled.
+func TestMain_AuditLog(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	m := c[0]
+
+	mustDo := func(method, path, body string) {
+		t.Helper()
+		if resp := test.MustDo(method, m.URL()+path, body); resp.StatusCode != gohttp.StatusOK {
+			t.Fatalf("%s %s: unexpected status: %d, body: %s", method, path, resp.StatusCode, resp.Body)
+		}
+	}
+	mustDo("POST", "/index/before", "")
+	mustDo("PATCH", "/cluster/config", `{"settings": {"audit.enabled": "true"}}`)
+	mustDo("POST", "/index/i", "")
+	mustDo("POST", "/index/i/field/f", "")
+	mustDo("POST", "/index/i/query", "Set(1, f=1) Set(2, f=1) Clear(3, f=1)")
+	mustDo("POST", "/index/i/query", "Row(f=1)")
+	mustDo("PATCH", "/cluster/config", `{"settings": {"audit.enabled": null}}`)
+	mustDo("POST", "/index/i/query", "Set(4, f=1)")
+
+	// Stopping the node writes the buffered records.
+	if err := c.StopNode(0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(m.Config.DataDir)
+	buf, err := ioutil.ReadFile(filepath.Join(m.Config.DataDir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []string
+	for _, line := range strings.Split(strings.TrimSpace(string(buf)), "\n") {
+		var rec pilosa.AuditRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		} else if rec.RemoteAddr == "" {
+			t.Fatalf("expected remote address: %s", line)
+		}
+		ops = append(ops, rec.Operation)
+		if rec.Operation == "query" {
+			if !reflect.DeepEqual(rec.Calls, map[string]int{"Set": 2, "Clear": 1}) || rec.Count != 3 || rec.Index != "i" {
+				t.Fatalf("unexpected query record: %s", line)
+			}
+		}
+	}
+	if exp := []string{"updateClusterConfig", "createIndex", "createField", "query"}; !reflect.DeepEqual(ops, exp) {
+		t.Fatalf("unexpected operations: %v", ops)
+	}
+}
+
+// Ensure queries are rejected with a retryable error while the node is under
+// memory pressure.
+func TestMain_Admission(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	m := c[0]
+
+	mustDo := func(method, path, body string, status int) *gohttp.Response {
+		t.Helper()
+		resp := test.MustDo(method, m.URL()+path, body)
+		if resp.StatusCode != status {
+			t.Fatalf("%s %s: expected status %d, got %d, body: %s", method, path, status, resp.StatusCode, resp.Body)
+		}
+		return resp.Response
+	}
+	mustDo("POST", "/index/i", "", gohttp.StatusOK)
+	mustDo("POST", "/index/i/field/f", "", gohttp.StatusOK)
+
+	// Any process uses more than 1 byte of memory.
+	mustDo("PATCH", "/cluster/config", `{"settings": {"admission.high-memory": "1"}}`, gohttp.StatusOK)
+	mustDo("POST", "/index/i/query", "Set(1, f=1) Count(Row(f=1))", gohttp.StatusOK)
+	if resp := mustDo("POST", "/index/i/query", "TopN(f)", gohttp.StatusServiceUnavailable); resp.Header.Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header")
+	}
+
+	mustDo("PATCH", "/cluster/config", `{"settings": {"admission.critical-memory": "1"}}`, gohttp.StatusOK)
+	mustDo("POST", "/index/i/query", "Count(Row(f=1))", gohttp.StatusServiceUnavailable)
+
+	var status struct {
+		Admission pilosa.AdmissionStatus `json:"admission"`
+	}
+	if err := json.Unmarshal([]byte(test.MustDo("GET", m.URL()+"/status", "").Body), &status); err != nil {
+		t.Fatal(err)
+	} else if status.Admission.Level != "critical" || status.Admission.RejectedExpensive != 1 || status.Admission.RejectedAll != 1 {
+		t.Fatalf("unexpected admission status: %+v", status.Admission)
+	}
+
+	mustDo("PATCH", "/cluster/config", `{"settings": {"admission.high-memory": null, "admission.critical-memory": null}}`, gohttp.StatusOK)
+	mustDo("POST", "/index/i/query", "TopN(f)", gohttp.StatusOK)
+}
@@ -0,0 +1,279 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultResultHandleTTL is how long the stored results of a session are
+	// kept after the session was last used.
+	defaultResultHandleTTL = 10 * time.Minute
+
+	// defaultResultHandleMaxMemory is the number of bytes of stored results
+	// a node keeps across all sessions.
+	defaultResultHandleMaxMemory = 256 << 20
+)
+
+// DeleteSessionMessage is an internal message used to release the stored
+// results of a session on every node.
+type DeleteSessionMessage struct {
+	Session string
+}
+
+// querySessionKey is the context key for the session of a query.
+type querySessionKey struct{}
+
+// withQuerySession returns ctx carrying the session id of a query so that
+// Handle() calls can be evaluated at the shard level.
+func withQuerySession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, querySessionKey{}, session)
+}
+
+// querySession returns the session id carried by ctx.
+func querySession(ctx context.Context) string {
+	session, _ := ctx.Value(querySessionKey{}).(string)
+	return session
+}
+
+// resultStore retains the per-shard results of queries executed with a
+// storeAs handle so that later queries in the same session can reference
+// them with Handle() instead of recomputing them. Each node only keeps the
+// shards it computed itself.
+type resultStore struct {
+	mu       sync.Mutex
+	sessions map[string]*resultSession
+
+	// Sessions expire ttl after they were last used.
+	ttl time.Duration
+
+	// Number of bytes used by all stored results, and its limit.
+	size    int64
+	maxSize int64
+
+	now func() time.Time
+}
+
+// resultSession holds the stored results of a session by handle.
+type resultSession struct {
+	handles map[string]*storedResult
+	expires time.Time
+}
+
+// storedResult holds the rows of a stored result by shard.
+type storedResult struct {
+	session string
+	handle  string
+	index   string
+	rows    map[uint64]*Row
+	size    int64
+}
+
+// newResultStore returns a new instance of resultStore.
+func newResultStore(ttl time.Duration, maxSize int64) *resultStore {
+	return &resultStore{
+		sessions: make(map[string]*resultSession),
+		ttl:      ttl,
+		maxSize:  maxSize,
+		now:      time.Now,
+	}
+}
+
+// Size returns the number of bytes used by stored results.
+func (s *resultStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// begin returns an empty result to be stored under handle in session by a
+// query against index. The rows are stored by the query and the result
+// replaces any previous result of the handle once committed, so that the
+// query may reference the previous result itself.
+func (s *resultStore) begin(session, handle, index string) *storedResult {
+	return &storedResult{
+		session: session,
+		handle:  handle,
+		index:   index,
+		rows:    make(map[uint64]*Row),
+	}
+}
+
+// store adds a copy of the row computed for shard to r.
+func (s *resultStore) store(r *storedResult, shard uint64, row *Row) error {
+	// Stored rows are shared by later queries, which must not modify them.
+	row = row.clone()
+	for i := range row.segments {
+		row.segments[i].writable = false
+	}
+	size := row.size()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	if s.maxSize > 0 && s.size+size > s.maxSize {
+		return errors.Wrapf(ErrResultHandleMemory, "storing %q requires %d more bytes, %d of %d in use", r.handle, size, s.size, s.maxSize)
+	}
+	r.rows[shard] = row
+	r.size += size
+	s.size += size
+	return nil
+}
+
+// commit makes r the result of its handle.
+func (s *resultStore) commit(r *storedResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.session(r.session, true)
+	if prev := sess.handles[r.handle]; prev != nil {
+		s.size -= prev.size
+	}
+	sess.handles[r.handle] = r
+}
+
+// discard releases the memory of r, which was not committed.
+func (s *resultStore) discard(r *storedResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size -= r.size
+}
+
+// check returns an error unless handle is stored in session for index.
+func (s *resultStore) check(session, handle, index string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	var r *storedResult
+	if sess := s.session(session, false); sess != nil {
+		r = sess.handles[handle]
+	}
+	if r == nil || r.index != index {
+		return errors.Wrapf(ErrResultHandleNotFound, "%q", handle)
+	}
+	return nil
+}
+
+// row returns the row stored under handle for shard. Shards which weren't
+// computed by this node for the stored query return an empty row.
+func (s *resultStore) row(session, handle, index string, shard uint64) (*Row, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	var r *storedResult
+	if sess := s.session(session, false); sess != nil {
+		r = sess.handles[handle]
+	}
+	if r == nil {
+		return NewRow(), nil
+	} else if r.index != index {
+		return nil, errors.Wrapf(ErrResultHandleNotFound, "%q", handle)
+	}
+	if row := r.rows[shard]; row != nil {
+		return row, nil
+	}
+	return NewRow(), nil
+}
+
+// release discards all stored results of session.
+func (s *resultStore) release(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess := s.sessions[session]; sess != nil {
+		s.removeSession(session, sess)
+	}
+}
+
+// session returns the session with the given id, creating it if create is
+// true, and extends its expiry. The lock must be held.
+func (s *resultStore) session(id string, create bool) *resultSession {
+	sess := s.sessions[id]
+	if sess == nil {
+		if !create {
+			return nil
+		}
+		sess = &resultSession{handles: make(map[string]*storedResult)}
+		s.sessions[id] = sess
+	}
+	sess.expires = s.now().Add(s.ttl)
+	return sess
+}
+
+// removeExpired discards sessions which haven't been used within the TTL.
+// The lock must be held.
+func (s *resultStore) removeExpired() {
+	now := s.now()
+	for id, sess := range s.sessions {
+		if now.After(sess.expires) {
+			s.removeSession(id, sess)
+		}
+	}
+}
+
+// removeSession discards sess. The lock must be held.
+func (s *resultStore) removeSession(id string, sess *resultSession) {
+	for _, r := range sess.handles {
+		s.size -= r.size
+	}
+	delete(s.sessions, id)
+}
+
+// validateStoreAs ensures that a query executed with a storeAs handle
+// consists of a single call which returns a row.
+func validateStoreAs(q *pql.Query, opt *execOptions) error {
+	if opt.StoreAs == "" {
+		return nil
+	} else if opt.Session == "" {
+		return errors.New("storeAs requires a session")
+	} else if len(q.Calls) != 1 || !isBitmapCall(q.Calls[0]) {
+		return errors.New("storeAs requires a single call which returns a row")
+	}
+	return nil
+}
+
+// checkHandles ensures that every Handle() call in calls references a result
+// stored in the session of the query.
+func (s *resultStore) checkHandles(index string, calls []*pql.Call, opt *execOptions) error {
+	for _, c := range calls {
+		if c.Name == "Handle" {
+			name := callArgString(c, "name")
+			if name == "" {
+				return errors.New("Handle() argument required: name")
+			} else if opt.Session == "" {
+				return errors.New("Handle() requires a session")
+			} else if err := s.check(opt.Session, name, index); err != nil {
+				return err
+			}
+		}
+		if err := s.checkHandles(index, c.Children, opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isBitmapCall returns true if c is executed by executeBitmapCall.
+func isBitmapCall(c *pql.Call) bool {
+	switch c.Name {
+	case "Row", "Range", "Difference", "Intersect", "Union", "Xor", "Not", "Shift", "Handle":
+		return true
+	}
+	return false
+}
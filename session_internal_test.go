@@ -0,0 +1,140 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestResultStore(t *testing.T) {
+	t.Run("Commit", func(t *testing.T) {
+		s := newResultStore(time.Minute, 0)
+		r := s.begin("s", "h", "i")
+		if err := s.store(r, 0, NewRow(1, 2)); err != nil {
+			t.Fatal(err)
+		}
+
+		// The result isn't visible before it is committed.
+		if err := s.check("s", "h", "i"); errors.Cause(err) != ErrResultHandleNotFound {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		s.commit(r)
+		if err := s.check("s", "h", "i"); err != nil {
+			t.Fatal(err)
+		} else if err := s.check("s", "h", "j"); errors.Cause(err) != ErrResultHandleNotFound {
+			t.Fatalf("expected not found for other index, got %v", err)
+		}
+
+		if row, err := s.row("s", "h", "i", 0); err != nil {
+			t.Fatal(err)
+		} else if cols := row.Columns(); !reflect.DeepEqual(cols, []uint64{1, 2}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+		if row, err := s.row("s", "h", "i", 1); err != nil {
+			t.Fatal(err)
+		} else if n := row.Count(); n != 0 {
+			t.Fatalf("unexpected count for missing shard: %d", n)
+		}
+
+		// Committing again replaces the previous result and its memory.
+		size := s.Size()
+		r = s.begin("s", "h", "i")
+		if err := s.store(r, 0, NewRow(3, 4)); err != nil {
+			t.Fatal(err)
+		}
+		s.commit(r)
+		if row, err := s.row("s", "h", "i", 0); err != nil {
+			t.Fatal(err)
+		} else if cols := row.Columns(); !reflect.DeepEqual(cols, []uint64{3, 4}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		} else if s.Size() != size {
+			t.Fatalf("unexpected size: %d != %d", s.Size(), size)
+		}
+
+		s.release("s")
+		if err := s.check("s", "h", "i"); errors.Cause(err) != ErrResultHandleNotFound {
+			t.Fatalf("expected not found after release, got %v", err)
+		} else if s.Size() != 0 {
+			t.Fatalf("unexpected size after release: %d", s.Size())
+		}
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		now := time.Now()
+		s := newResultStore(time.Minute, 0)
+		s.now = func() time.Time { return now }
+
+		r := s.begin("s", "h", "i")
+		if err := s.store(r, 0, NewRow(1)); err != nil {
+			t.Fatal(err)
+		}
+		s.commit(r)
+
+		// Using the session extends its expiry.
+		now = now.Add(50 * time.Second)
+		if err := s.check("s", "h", "i"); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(50 * time.Second)
+		if err := s.check("s", "h", "i"); err != nil {
+			t.Fatal(err)
+		}
+
+		now = now.Add(2 * time.Minute)
+		if err := s.check("s", "h", "i"); errors.Cause(err) != ErrResultHandleNotFound {
+			t.Fatalf("expected not found after expiry, got %v", err)
+		} else if s.Size() != 0 {
+			t.Fatalf("unexpected size after expiry: %d", s.Size())
+		}
+	})
+
+	t.Run("MaxMemory", func(t *testing.T) {
+		row := NewRow(1)
+		s := newResultStore(time.Minute, row.size())
+
+		r := s.begin("s", "h", "i")
+		if err := s.store(r, 0, row); err != nil {
+			t.Fatal(err)
+		} else if err := s.store(r, 1, row); errors.Cause(err) != ErrResultHandleMemory {
+			t.Fatalf("expected memory error, got %v", err)
+		}
+		s.discard(r)
+		if s.Size() != 0 {
+			t.Fatalf("unexpected size after discard: %d", s.Size())
+		}
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		s := newResultStore(time.Minute, 0)
+		orig := NewRow(1)
+		r := s.begin("s", "h", "i")
+		if err := s.store(r, 0, orig); err != nil {
+			t.Fatal(err)
+		}
+		s.commit(r)
+
+		// Modifying the source row doesn't change the stored result.
+		orig.SetBit(2)
+		if row, err := s.row("s", "h", "i", 0); err != nil {
+			t.Fatal(err)
+		} else if cols := row.Columns(); !reflect.DeepEqual(cols, []uint64{1}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+	})
+}
@@ -0,0 +1,411 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pilosa/pilosa/v2/internal"
+	"github.com/pkg/errors"
+)
+
+// clusterSetting is a setting whose value from the configuration of each
+// node can be overridden for the whole cluster.
+type clusterSetting struct {
+	// Name of the setting in the configuration file.
+	name string
+
+	// True if a changed value only takes effect when nodes are restarted.
+	restart bool
+
+	// normalize returns the canonical form of value, such as "10m0s" for
+	// "10m", or an error if value is not valid.
+	normalize func(value string) (string, error)
+
+	// get returns the value in effect on a server, and set changes it.
+	get func(s *Server) string
+	set func(s *Server, value string) error
+}
+
+// clusterSettings are the settings which can be overridden for the whole
+// cluster. Settings which are only read while the server is created require
+// a restart.
+var clusterSettings = []*clusterSetting{
+	durationSetting("anti-entropy.interval", false,
+		func(s *Server) time.Duration { return s.AntiEntropyInterval() },
+		func(s *Server, d time.Duration) { s.setAntiEntropyInterval(d) },
+	),
+	durationSetting("cluster.long-query-time", false,
+		func(s *Server) time.Duration { return s.cluster.LongQueryTime() },
+		func(s *Server, d time.Duration) { s.cluster.setLongQueryTime(d) },
+	),
+	intSetting("max-writes-per-request", false,
+		func(s *Server) int64 { return int64(s.executor.MaxWritesPerRequest()) },
+		func(s *Server, n int64) { s.executor.setMaxWritesPerRequest(int(n)) },
+	),
+	intSetting("admission.high-memory", false,
+		func(s *Server) int64 { return s.executor.admission.HighMemory() },
+		func(s *Server, n int64) { s.executor.admission.setHighMemory(n) },
+	),
+	intSetting("admission.critical-memory", false,
+		func(s *Server) int64 { return s.executor.admission.CriticalMemory() },
+		func(s *Server, n int64) { s.executor.admission.setCriticalMemory(n) },
+	),
+	boolSetting("audit.enabled", false,
+		func(s *Server) bool { return s.audit.Enabled() },
+		func(s *Server, b bool) { s.audit.setEnabled(b) },
+	),
+	intSetting("operation-ids.max", true,
+		func(s *Server) int64 { return int64(s.holder.opIDOptions.max) },
+		func(s *Server, n int64) { s.holder.opIDOptions.max = int(n) },
+	),
+	durationSetting("operation-ids.ttl", true,
+		func(s *Server) time.Duration { return s.holder.opIDOptions.ttl },
+		func(s *Server, d time.Duration) { s.holder.opIDOptions.ttl = d },
+	),
+	durationSetting("result-handles.ttl", true,
+		func(s *Server) time.Duration { return s.resultHandleTTL },
+		func(s *Server, d time.Duration) { s.resultHandleTTL = d },
+	),
+	intSetting("result-handles.max-memory", true,
+		func(s *Server) int64 { return s.resultHandleMem },
+		func(s *Server, n int64) { s.resultHandleMem = n },
+	),
+}
+
+// clusterSettingByName returns the cluster setting with the given name, or
+// nil if there is no such setting.
+func clusterSettingByName(name string) *clusterSetting {
+	for _, cs := range clusterSettings {
+		if cs.name == name {
+			return cs
+		}
+	}
+	return nil
+}
+
+// durationSetting returns a cluster setting with a non-negative duration
+// value, such as "10m".
+func durationSetting(name string, restart bool, get func(*Server) time.Duration, set func(*Server, time.Duration)) *clusterSetting {
+	parse := func(value string) (time.Duration, error) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errors.Errorf("invalid duration: %q", value)
+		} else if d < 0 {
+			return 0, errors.Errorf("duration must not be negative: %s", value)
+		}
+		return d, nil
+	}
+	return &clusterSetting{
+		name:    name,
+		restart: restart,
+		normalize: func(value string) (string, error) {
+			d, err := parse(value)
+			return d.String(), err
+		},
+		get: func(s *Server) string { return get(s).String() },
+		set: func(s *Server, value string) error {
+			d, err := parse(value)
+			if err != nil {
+				return err
+			}
+			set(s, d)
+			return nil
+		},
+	}
+}
+
+// intSetting returns a cluster setting with a non-negative integer value.
+func intSetting(name string, restart bool, get func(*Server) int64, set func(*Server, int64)) *clusterSetting {
+	parse := func(value string) (int64, error) {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid integer: %q", value)
+		} else if n < 0 {
+			return 0, errors.Errorf("integer must not be negative: %d", n)
+		}
+		return n, nil
+	}
+	return &clusterSetting{
+		name:    name,
+		restart: restart,
+		normalize: func(value string) (string, error) {
+			n, err := parse(value)
+			return strconv.FormatInt(n, 10), err
+		},
+		get: func(s *Server) string { return strconv.FormatInt(get(s), 10) },
+		set: func(s *Server, value string) error {
+			n, err := parse(value)
+			if err != nil {
+				return err
+			}
+			set(s, n)
+			return nil
+		},
+	}
+}
+
+// boolSetting returns a cluster setting with a boolean value.
+func boolSetting(name string, restart bool, get func(*Server) bool, set func(*Server, bool)) *clusterSetting {
+	parse := func(value string) (bool, error) {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, errors.Errorf("invalid boolean: %q", value)
+		}
+		return b, nil
+	}
+	return &clusterSetting{
+		name:    name,
+		restart: restart,
+		normalize: func(value string) (string, error) {
+			b, err := parse(value)
+			return strconv.FormatBool(b), err
+		},
+		get: func(s *Server) string { return strconv.FormatBool(get(s)) },
+		set: func(s *Server, value string) error {
+			b, err := parse(value)
+			if err != nil {
+				return err
+			}
+			set(s, b)
+			return nil
+		},
+	}
+}
+
+// ClusterSettingsMessage is an internal message which sets the cluster-level
+// values of settings. It holds every cluster-level value, so settings which
+// are missing revert to the configuration of each node.
+type ClusterSettingsMessage struct {
+	Settings map[string]string
+}
+
+// ClusterConfig describes the settings which can be overridden for the whole
+// cluster.
+type ClusterConfig struct {
+	Settings []*ClusterSetting `json:"settings"`
+}
+
+// ClusterSetting describes a setting which can be overridden for the whole
+// cluster, and its value on each node.
+type ClusterSetting struct {
+	Name string `json:"name"`
+
+	// True if a changed value only takes effect when nodes are restarted.
+	RestartRequired bool `json:"restartRequired"`
+
+	// Cluster-level value, if any, which overrides the configuration of
+	// each node.
+	Value *string `json:"value,omitempty"`
+
+	Nodes []*NodeSetting `json:"nodes"`
+}
+
+// NodeSetting is the value of a setting on a node.
+type NodeSetting struct {
+	NodeID string `json:"id,omitempty"`
+
+	// Value from the configuration of the node.
+	Default string `json:"default"`
+
+	// Value in effect on the node.
+	Value string `json:"value"`
+
+	// True if the node has a cluster-level value for the setting.
+	Overridden bool `json:"overridden"`
+
+	// True if the cluster-level value only takes effect when the node is
+	// restarted.
+	PendingRestart bool `json:"pendingRestart,omitempty"`
+
+	// Error reading the settings of the node.
+	Error string `json:"error,omitempty"`
+}
+
+// settingsStore holds the cluster-level values of settings on a node, and
+// the values from the configuration of the node. Cluster-level values are
+// persisted so that they still apply after a restart.
+type settingsStore struct {
+	mu sync.Mutex
+
+	// Serializes updates of the cluster-level values on the coordinator,
+	// which are read, changed and broadcast.
+	updateMu sync.Mutex
+
+	// Path of the file which stores the cluster-level values. Values are
+	// not persisted if it is empty.
+	path string
+
+	// Values from the configuration of the node, and cluster-level values.
+	defaults  map[string]string
+	overrides map[string]string
+}
+
+// newSettingsStore returns a new instance of settingsStore which persists
+// cluster-level values in the given data directory.
+func newSettingsStore(dir string) *settingsStore {
+	s := &settingsStore{
+		defaults:  make(map[string]string),
+		overrides: make(map[string]string),
+	}
+	if dir != "" {
+		s.path = filepath.Join(dir, ".settings")
+	}
+	return s
+}
+
+// load reads the persisted cluster-level values.
+func (s *settingsStore) load() error {
+	if s.path == "" {
+		return nil
+	}
+	buf, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "reading file")
+	}
+
+	var pb internal.ClusterSettingsMessage
+	if err := proto.Unmarshal(buf, &pb); err != nil {
+		return errors.Wrap(err, "unmarshalling")
+	}
+	for i, name := range pb.Names {
+		if i < len(pb.Values) {
+			s.overrides[name] = pb.Values[i]
+		}
+	}
+	return nil
+}
+
+// save persists the cluster-level values. unprotected.
+func (s *settingsStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	var pb internal.ClusterSettingsMessage
+	for _, cs := range clusterSettings {
+		if v, ok := s.overrides[cs.name]; ok {
+			pb.Names = append(pb.Names, cs.name)
+			pb.Values = append(pb.Values, v)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	}
+	if buf, err := proto.Marshal(&pb); err != nil {
+		return errors.Wrap(err, "marshalling")
+	} else if err := ioutil.WriteFile(s.path, buf, 0666); err != nil {
+		return errors.Wrap(err, "writing file")
+	}
+	return nil
+}
+
+// Overrides returns a copy of the cluster-level values.
+func (s *settingsStore) Overrides() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := make(map[string]string, len(s.overrides))
+	for k, v := range s.overrides {
+		m[k] = v
+	}
+	return m
+}
+
+// applySettings records the values from the configuration of the server for
+// the settings which require a restart, or for the other settings, and then
+// applies their cluster-level values. Settings which require a restart are
+// applied before the components which read them are created, the other
+// settings once the components exist.
+func (s *Server) applySettings(restart bool) {
+	s.settings.mu.Lock()
+	defer s.settings.mu.Unlock()
+	for _, cs := range clusterSettings {
+		if cs.restart != restart {
+			continue
+		}
+		s.settings.defaults[cs.name] = cs.get(s)
+		if v, ok := s.settings.overrides[cs.name]; ok {
+			if err := cs.set(s, v); err != nil {
+				s.logger.Printf("applying cluster setting %s: %v", cs.name, err)
+			}
+		}
+	}
+}
+
+// updateSettings replaces the cluster-level values of settings and applies
+// the settings which don't require a restart. Settings without a
+// cluster-level value revert to the configuration of the server.
+func (s *Server) updateSettings(values map[string]string) error {
+	s.settings.mu.Lock()
+	defer s.settings.mu.Unlock()
+
+	s.settings.overrides = make(map[string]string, len(values))
+	for k, v := range values {
+		s.settings.overrides[k] = v
+	}
+	if err := s.settings.save(); err != nil {
+		return errors.Wrap(err, "saving settings")
+	}
+
+	for _, cs := range clusterSettings {
+		if cs.restart {
+			continue
+		}
+		v, ok := s.settings.overrides[cs.name]
+		if !ok {
+			v = s.settings.defaults[cs.name]
+		}
+		if v == cs.get(s) {
+			continue
+		}
+		if err := cs.set(s, v); err != nil {
+			return errors.Wrapf(err, "applying cluster setting %s", cs.name)
+		}
+	}
+	return nil
+}
+
+// nodeSettings returns the values of the cluster settings on the server, by
+// name.
+func (s *Server) nodeSettings() map[string]*NodeSetting {
+	s.settings.mu.Lock()
+	defer s.settings.mu.Unlock()
+
+	m := make(map[string]*NodeSetting, len(clusterSettings))
+	for _, cs := range clusterSettings {
+		v, ok := s.settings.overrides[cs.name]
+		ns := &NodeSetting{
+			NodeID:     s.nodeID,
+			Default:    s.settings.defaults[cs.name],
+			Value:      cs.get(s),
+			Overridden: ok,
+		}
+		if !ok {
+			v = ns.Default
+		}
+		ns.PendingRestart = cs.restart && v != ns.Value
+		m[cs.name] = ns
+	}
+	return m
+}
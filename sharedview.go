@@ -0,0 +1,311 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pilosa/pilosa/v2/roaring"
+	"github.com/pkg/errors"
+)
+
+// A field which shares its time views writes a bit with a timestamp to both
+// a day view and an hour view, so a container of a fragment of a day view
+// is usually identical to the union of the containers with the same key of
+// the fragments of its 24 hour views for the same shard. A snapshot of the
+// fragment of a day view leaves these containers out of its data file, and
+// lists their keys in a file next to it. When the field is opened, they are
+// rebuilt from the fragments of the hour views.
+//
+// This relies on every write to a fragment of a day or hour view being
+// applied to both, as SetBit(), ClearBit(), ClearRow() and imports of set
+// bits are. Other writes, such as roaring imports into a single view,
+// anti-entropy merges, clearing imports and deleting an hour view, first
+// write the shared containers to the data file of the day fragment, which
+// then doesn't share any until the write is done.
+
+// sharedExt is the file extension for the keys of the containers of a
+// fragment which are shared with the fragments of its child views.
+const sharedExt = ".shared"
+
+// sharesTimeViews returns true if the views of a time field with quantum q
+// can share containers: those of its day views with its hour views.
+func sharesTimeViews(q TimeQuantum) bool {
+	return q.HasDay() && q.HasHour()
+}
+
+// isDayView returns true if name is the name of a day view.
+func isDayView(name string) bool {
+	return strings.HasPrefix(name, viewStandard+"_") && len(name) == len(viewStandard)+9
+}
+
+// isHourView returns true if name is the name of an hour view.
+func isHourView(name string) bool {
+	return strings.HasPrefix(name, viewStandard+"_") && len(name) == len(viewStandard)+11
+}
+
+// sharedChildFragments returns the fragments of the hour views of the day
+// view name for shard.
+func (f *Field) sharedChildFragments(name string, shard uint64) []*fragment {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var a []*fragment
+	for hour := 0; hour < 24; hour++ {
+		if v := f.viewMap[fmt.Sprintf("%s%02d", name, hour)]; v != nil {
+			if frag := v.Fragment(shard); frag != nil {
+				a = append(a, frag)
+			}
+		}
+	}
+	return a
+}
+
+// sharedParentFragment returns the fragment of the day view of the hour view
+// name for shard, or nil if it doesn't exist.
+func (f *Field) sharedParentFragment(name string, shard uint64) *fragment {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.unprotectedSharedParentFragment(name, shard)
+}
+
+// unprotectedSharedParentFragment is sharedParentFragment for when the mutex
+// is already held.
+func (f *Field) unprotectedSharedParentFragment(name string, shard uint64) *fragment {
+	v := f.viewMap[name[:len(name)-2]]
+	if v == nil {
+		return nil
+	}
+	return v.Fragment(shard)
+}
+
+// fillSharedViews rebuilds the shared containers of the fragments of the day
+// views of the field, once its views are open.
+func (f *Field) fillSharedViews() {
+	if !f.options.ShareTimeViews {
+		return
+	}
+	for _, v := range f.views() {
+		if !isDayView(v.name) {
+			continue
+		}
+		for _, frag := range v.allFragments() {
+			frag.fillSharedContainers(f.sharedChildFragments(v.name, frag.shard))
+		}
+	}
+}
+
+// sharedPath returns the path to the keys of the shared containers of the
+// fragment.
+func (f *fragment) sharedPath() string { return f.path + sharedExt }
+
+// holdSharing writes the shared containers of the fragment of the day view
+// of the fragment, or of the fragment itself, to its data file, and keeps it
+// from sharing any until release is called. This is called before writes
+// which may not be applied to both views. Call this only when the mutex
+// isn't held.
+func (f *fragment) holdSharing() (release func(), err error) {
+	p := f
+	if f.sharedParent != nil {
+		p = f.sharedParent()
+	} else if f.sharedChildren == nil {
+		p = nil
+	}
+	if p == nil {
+		return func() {}, nil
+	}
+	return p.holdShared()
+}
+
+// holdShared writes the shared containers of the fragment to its data file,
+// and keeps it from sharing any until release is called.
+func (f *fragment) holdShared() (release func(), err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.sharedKeys) > 0 {
+		if f.closed || f.sharedPending {
+			return nil, errors.Errorf("shared containers of %s/%s/%s/%d are not loaded", f.index, f.field, f.view, f.shard)
+		} else if err := f.snapshot(); err != nil {
+			return nil, errors.Wrap(err, "writing shared containers")
+		}
+	}
+	f.sharedHolds++
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.sharedHolds--
+	}, nil
+}
+
+// sharedContainers returns the keys of the containers of the fragment which
+// are identical to the union of those of children, and their encoded size.
+// Call this only when the mutex is held.
+func (f *fragment) sharedContainers(children []*fragment) (keys []uint64, size int64) {
+	if f.sharedPending {
+		return f.sharedKeys, f.sharedBytes
+	} else if f.sharedHolds > 0 || len(children) == 0 {
+		return nil, 0
+	}
+	for _, child := range children {
+		child.mu.RLock()
+		defer child.mu.RUnlock()
+		if child.closed || child.storage == nil {
+			return nil, 0
+		}
+	}
+
+	var cs []*roaring.Container
+	itr, _ := f.storage.Containers.Iterator(0)
+	for itr.Next() {
+		k, c := itr.Value()
+		if c.N() == 0 {
+			continue
+		}
+		cs = cs[:0]
+		for _, child := range children {
+			cs = append(cs, child.storage.Containers.Get(k))
+		}
+		if roaring.ContainersEqual(c, roaring.UnionContainers(cs...)) {
+			keys = append(keys, k)
+			size += int64(c.EncodedSize())
+		}
+	}
+	return keys, size
+}
+
+// fillSharedContainers rebuilds the shared containers of the fragment from
+// those of children, once they are open.
+func (f *fragment) fillSharedContainers(children []*fragment) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.sharedPending {
+		return
+	}
+	for _, child := range children {
+		child.mu.RLock()
+		defer child.mu.RUnlock()
+	}
+
+	var size int64
+	cs := make([]*roaring.Container, len(children))
+	for _, k := range f.sharedKeys {
+		for i, child := range children {
+			cs[i] = child.storage.Containers.Get(k)
+		}
+		if c := roaring.UnionContainers(cs...); c == nil {
+			f.storage.Containers.Remove(k)
+		} else {
+			f.storage.Containers.Put(k, c.Clone())
+			size += int64(c.EncodedSize())
+		}
+	}
+	f.sharedBytes = size
+	f.sharedPending = false
+
+	f.rowCache = &simpleCache{make(map[uint64]*Row)}
+	f.checksums = make(map[int][]byte)
+	f.maxRowID = f.storage.Max() / f.shardWidth
+}
+
+// unprotectedFileBitmap returns a bitmap of the containers of the
+// storage which are written to the data file: those which aren't shared.
+func (f *fragment) unprotectedFileBitmap(bm *roaring.Bitmap) *roaring.Bitmap {
+	if len(f.sharedKeys) == 0 {
+		return bm
+	}
+	other := roaring.NewFileBitmap()
+	other.Flags = bm.Flags
+	keys := f.sharedKeys
+	itr, _ := bm.Containers.Iterator(0)
+	for itr.Next() {
+		k, c := itr.Value()
+		for len(keys) > 0 && keys[0] < k {
+			keys = keys[1:]
+		}
+		if len(keys) > 0 && keys[0] == k {
+			continue
+		}
+		other.Containers.Put(k, c)
+	}
+	return other
+}
+
+// writeSharedKeys writes keys to the file of the keys of the shared
+// containers, or removes it if there are none.
+func (f *fragment) writeSharedKeys(keys []uint64) error {
+	if len(keys) == 0 {
+		if err := os.Remove(f.sharedPath()); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "removing shared keys")
+		}
+		return nil
+	}
+
+	tempPath := f.sharedPath() + tempExt
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return errors.Wrap(err, "creating shared keys")
+	}
+	defer file.Close()
+	bw := bufio.NewWriter(file)
+	if _, err := roaring.NewBitmap(keys...).WriteTo(bw); err != nil {
+		return errors.Wrap(err, "writing shared keys")
+	} else if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "flushing shared keys")
+	} else if err := file.Close(); err != nil {
+		return errors.Wrap(err, "closing shared keys")
+	}
+	return errors.Wrap(os.Rename(tempPath, f.sharedPath()), "renaming shared keys")
+}
+
+// readSharedKeys returns the keys of the shared containers of the fragment
+// written by its last snapshot.
+func (f *fragment) readSharedKeys() ([]uint64, error) {
+	return readSharedKeys(f.sharedPath())
+}
+
+// readSharedKeys returns the keys in the file of the keys of the shared
+// containers of a fragment at path, or nil if there is none.
+func readSharedKeys(path string) ([]uint64, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "reading shared keys")
+	}
+	b := roaring.NewBitmap()
+	if err := b.UnmarshalBinary(data); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling shared keys")
+	}
+	return b.Slice(), nil
+}
+
+// mergeSharedKeys returns the sorted union of the keys a and b.
+func mergeSharedKeys(a, b []uint64) []uint64 {
+	keys := make([]uint64, 0, len(a)+len(b))
+	keys = append(keys, a...)
+	keys = append(keys, b...)
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	out := keys[:0]
+	for i, k := range keys {
+		if i == 0 || k != keys[i-1] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
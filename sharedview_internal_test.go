@@ -0,0 +1,112 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/roaring"
+)
+
+// Ensure the fragments of the day views of a field which shares its time
+// views leave the containers of their hour views out of their data files,
+// and rebuild them when the field is reopened.
+func TestField_ShareTimeViews(t *testing.T) {
+	index := mustOpenIndex(IndexOptions{})
+	defer index.Close()
+
+	f, err := index.CreateField("f", OptFieldTypeTime("YMDH"), OptFieldShareTimeViews())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cols []uint64
+	for col := uint64(0); col < 100; col++ {
+		ts := time.Date(2010, time.January, 5, 12+int(col%2), 0, 0, 0, time.UTC)
+		if _, err := f.SetBit(1, col, &ts); err != nil {
+			t.Fatal(err)
+		}
+		cols = append(cols, col)
+	}
+
+	day := f.view(viewStandard + "_20100105").Fragment(0)
+	if err := day.Snapshot(); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(day.sharedKeys, []uint64{(1 * ShardWidth) >> 16}) {
+		t.Fatalf("unexpected shared keys: %v", day.sharedKeys)
+	}
+	if info, err := InspectFragmentFile(day.path, FragmentInspectOptions{}); err != nil {
+		t.Fatal(err)
+	} else if info.Containers != 0 || info.SharedContainers != 1 {
+		t.Fatalf("unexpected containers: %d, shared: %d", info.Containers, info.SharedContainers)
+	}
+
+	// The shared containers are rebuilt from the hour views.
+	if err := index.reopen(); err != nil {
+		t.Fatal(err)
+	}
+	f = index.Field("f")
+	day = f.view(viewStandard + "_20100105").Fragment(0)
+	if got := day.row(1).Columns(); !reflect.DeepEqual(got, cols) {
+		t.Fatalf("unexpected columns: %v", got)
+	}
+	if info, err := day.inspect(FragmentInspectOptions{}); err != nil {
+		t.Fatal(err)
+	} else if info.SharedContainers != 1 || info.SharedBytes == 0 {
+		t.Fatalf("unexpected shared containers: %d, bytes: %d", info.SharedContainers, info.SharedBytes)
+	}
+
+	// A roaring import into an hour view writes the shared containers to
+	// the data file of the day view first.
+	hour := f.view(viewStandard + "_2010010512").Fragment(0)
+	var buf bytes.Buffer
+	if _, err := roaring.NewBitmap(1*ShardWidth + 200).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	} else if err := hour.importRoaringT(buf.Bytes(), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(day.sharedPath()); !os.IsNotExist(err) {
+		t.Fatalf("expected shared keys to be removed: %v", err)
+	} else if len(day.sharedKeys) != 0 {
+		t.Fatalf("unexpected shared keys: %v", day.sharedKeys)
+	}
+	if err := index.reopen(); err != nil {
+		t.Fatal(err)
+	}
+	day = index.Field("f").view(viewStandard + "_20100105").Fragment(0)
+	if got := day.row(1).Columns(); !reflect.DeepEqual(got, cols) {
+		t.Fatalf("unexpected columns after import: %v", got)
+	}
+}
+
+// Ensure a field can only share the time views of a quantum with day and
+// hour units.
+func TestField_ShareTimeViews_Quantum(t *testing.T) {
+	index := mustOpenIndex(IndexOptions{})
+	defer index.Close()
+
+	if _, err := index.CreateField("f", OptFieldTypeTime("YMD"), OptFieldShareTimeViews()); err == nil {
+		t.Fatal("expected error")
+	}
+	f, err := index.CreateField("g", OptFieldTypeTime("DH"), OptFieldShareTimeViews())
+	if err != nil {
+		t.Fatal(err)
+	} else if err := f.setTimeQuantum("YMD", time.Time{}); err != ErrSharedTimeViewsQuantum {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
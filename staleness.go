@@ -0,0 +1,108 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pkg/errors"
+)
+
+// errStaleReplica is returned by a replica which is catching up with the
+// other replicas when its data is staler than a read accepts. The read is
+// then retried on the other replicas.
+var errStaleReplica = errors.New("replica is staler than the maximum staleness")
+
+// staleShards is the set of shards of a query which were read from a
+// replica catching up with the other replicas.
+type staleShards struct {
+	mu sync.Mutex
+	m  map[uint64]struct{}
+}
+
+func newStaleShards() *staleShards {
+	return &staleShards{m: make(map[uint64]struct{})}
+}
+
+// add adds shards to the set.
+func (s *staleShards) add(shards []uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, shard := range shards {
+		s.m[shard] = struct{}{}
+	}
+}
+
+// slice returns the shards of the set in order.
+func (s *staleShards) slice() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.m) == 0 {
+		return nil
+	}
+	a := make([]uint64, 0, len(s.m))
+	for shard := range s.m {
+		a = append(a, shard)
+	}
+	sort.Sort(uint64Slice(a))
+	return a
+}
+
+// checkStaleness checks that the local fragments read by c in shards are
+// caught up recently enough for a read which accepts stale data, if this
+// node is catching up with the other replicas. Returns true if the shards
+// may be stale. Fragments which don't exist locally aren't checked.
+func (e *executor) checkStaleness(index string, shards []uint64, c *pql.Call, opt *execOptions) (bool, error) {
+	if opt.MaxStaleness <= 0 || !e.Cluster.isCatchingUp() {
+		return false, nil
+	}
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return false, nil
+	}
+	defer idx.Close()
+
+	var fields []*Field
+	if names := callFields(c); len(names) > 0 {
+		for _, name := range names {
+			if f := idx.Field(name); f != nil {
+				fields = append(fields, f)
+			}
+		}
+	} else {
+		fields = idx.Fields()
+	}
+
+	oldest := time.Now().Add(-opt.MaxStaleness)
+	for _, f := range fields {
+		for _, v := range f.views() {
+			for _, shard := range shards {
+				frag := v.Fragment(shard)
+				if frag == nil {
+					continue
+				}
+				if t := frag.caughtUpAsOf(); t.Before(oldest) {
+					e.Holder.Stats.CountWithCustomTags("staleReadRefused", 1, 1.0, []string{"index:" + index})
+					return false, errors.Wrapf(errStaleReplica, "field %s view %s shard %d caught up as of %s", f.Name(), v.name, shard, t.Format(time.RFC3339))
+				}
+			}
+		}
+	}
+
+	return true, nil
+}
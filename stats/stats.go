@@ -16,6 +16,7 @@ package stats
 
 import (
 	"expvar"
+	"fmt"
 	"sort"
 	"strings"
 	"sync"
@@ -160,6 +161,70 @@ func (c *expvarStatsClient) Open() {}
 // Close no-op.
 func (c *expvarStatsClient) Close() error { return nil }
 
+// prefixedStatsClient prepends a prefix to the name of every metric of a
+// client.
+type prefixedStatsClient struct {
+	StatsClient
+	prefix string
+}
+
+// NewPrefixedStatsClient returns a client which sends the metrics of c with
+// tags added to every metric, and with their names prefixed by prefix and a
+// dot if prefix isn't empty. Clusters which report to the same collector use
+// it to tell their metrics apart, such as with a prefix for the cluster and
+// tags for the cluster and the environment. Tags must be in "key:value" form.
+func NewPrefixedStatsClient(c StatsClient, prefix string, tags []string) (StatsClient, error) {
+	for _, tag := range tags {
+		if i := strings.Index(tag, ":"); i <= 0 || i == len(tag)-1 {
+			return nil, fmt.Errorf("invalid tag %q, expected key:value", tag)
+		}
+	}
+	if len(tags) > 0 {
+		c = c.WithTags(tags...)
+	}
+	if prefix == "" {
+		return c, nil
+	}
+	return &prefixedStatsClient{StatsClient: c, prefix: prefix + "."}, nil
+}
+
+// WithTags returns a new client with additional tags appended, which keeps
+// the prefix.
+func (c *prefixedStatsClient) WithTags(tags ...string) StatsClient {
+	return &prefixedStatsClient{StatsClient: c.StatsClient.WithTags(tags...), prefix: c.prefix}
+}
+
+// Count tracks the number of times something occurs per second.
+func (c *prefixedStatsClient) Count(name string, value int64, rate float64) {
+	c.StatsClient.Count(c.prefix+name, value, rate)
+}
+
+// CountWithCustomTags tracks the number of times something occurs per second
+// with custom tags.
+func (c *prefixedStatsClient) CountWithCustomTags(name string, value int64, rate float64, tags []string) {
+	c.StatsClient.CountWithCustomTags(c.prefix+name, value, rate, tags)
+}
+
+// Gauge sets the value of a metric.
+func (c *prefixedStatsClient) Gauge(name string, value float64, rate float64) {
+	c.StatsClient.Gauge(c.prefix+name, value, rate)
+}
+
+// Histogram tracks statistical distribution of a metric.
+func (c *prefixedStatsClient) Histogram(name string, value float64, rate float64) {
+	c.StatsClient.Histogram(c.prefix+name, value, rate)
+}
+
+// Set tracks number of unique elements.
+func (c *prefixedStatsClient) Set(name string, value string, rate float64) {
+	c.StatsClient.Set(c.prefix+name, value, rate)
+}
+
+// Timing tracks timing information for a metric.
+func (c *prefixedStatsClient) Timing(name string, value time.Duration, rate float64) {
+	c.StatsClient.Timing(c.prefix+name, value, rate)
+}
+
 // MultiStatsClient joins multiple stats clients together.
 type MultiStatsClient []StatsClient
 
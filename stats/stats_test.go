@@ -17,6 +17,8 @@ package stats_test
 import (
 	"context"
 	"net/http/httptest"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -297,29 +299,122 @@ func TestStatsCount_APICalls(t *testing.T) {
 
 }
 
+// Ensure the prefixed client prefixes the name of every metric and adds its
+// tags, including to clients derived with WithTags.
+func TestPrefixedStatsClient(t *testing.T) {
+	var names []string
+	var tags [][]string
+	mock := &MockStats{
+		mockMetric: func(name string, t []string) {
+			names = append(names, name)
+			tags = append(tags, t)
+		},
+	}
+
+	c, err := stats.NewPrefixedStatsClient(mock, "prod", []string{"env:test", "cluster:a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WithTags("index:i").Count("setBit", 1, 1.0)
+	c.CountWithCustomTags("deleteJob", 1, 1.0, []string{"status:failed"})
+	c.Gauge("goroutines", 10, 1.0)
+	c.Timing("http.request", time.Second, 1.0)
+
+	if want := []string{"prod.setBit", "prod.deleteJob", "prod.goroutines", "prod.http.request"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+	want := [][]string{
+		{"cluster:a", "env:test", "index:i"},
+		{"cluster:a", "env:test", "status:failed"},
+		{"cluster:a", "env:test"},
+		{"cluster:a", "env:test"},
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	// Without a prefix, only tags are added.
+	names = nil
+	if c, err := stats.NewPrefixedStatsClient(mock, "", []string{"env:test"}); err != nil {
+		t.Fatal(err)
+	} else if c.Count("setBit", 1, 1.0); !reflect.DeepEqual(names, []string{"setBit"}) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+
+	for _, tag := range []string{"env", ":test", "env:"} {
+		if _, err := stats.NewPrefixedStatsClient(mock, "", []string{tag}); err == nil {
+			t.Fatalf("expected error for tag %q", tag)
+		}
+	}
+}
+
+// Ensure range queries are counted under one name, tagged with their index
+// and type.
+func TestStatsCount_Range(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	hldr := test.Holder{Holder: c[0].Server.Holder()}
+
+	var tags [][]string
+	hldr.Holder.Stats = &MockStats{
+		mockMetric: func(name string, t []string) {
+			if name == "range" {
+				tags = append(tags, t)
+			}
+		},
+	}
+	c.CreateField(t, "d", pilosa.IndexOptions{}, "v", pilosa.OptFieldTypeInt(0, 100))
+	c.CreateField(t, "d", pilosa.IndexOptions{}, "t", pilosa.OptFieldTypeTime("YMD"))
+	c.Query(t, "d", `Set(1, v=10) Set(2, t=1, 2018-01-01T00:00) Set(3, t=1, 2019-01-01T00:00)`)
+	c.Query(t, "d", `Row(v > 5) Row(t=1, from=2018-01-01T00:00, to=2020-01-01T00:00)`)
+
+	if want := [][]string{{"index:d", "type:bsigroup"}, {"index:d", "type:time"}}; !reflect.DeepEqual(tags, want) {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+}
+
 type MockStats struct {
 	mockCount         func(name string, value int64, rate float64)
 	mockCountWithTags func(name string, value int64, rate float64, tags []string)
+
+	// mockMetric is called for every metric, with the tags of the client
+	// and the custom tags of the metric, in sorted order.
+	mockMetric func(name string, tags []string)
+	tags       []string
+}
+
+func (s *MockStats) metric(name string, tags []string) {
+	if s.mockMetric != nil {
+		tags = append(append([]string(nil), s.tags...), tags...)
+		sort.Strings(tags)
+		s.mockMetric(name, tags)
+	}
 }
 
 func (s *MockStats) Count(name string, value int64, rate float64) {
 	if s.mockCount != nil {
 		s.mockCount(name, value, rate)
 	}
+	s.metric(name, nil)
 }
 
 func (s *MockStats) CountWithCustomTags(name string, value int64, rate float64, tags []string) {
 	if s.mockCountWithTags != nil {
 		s.mockCountWithTags(name, value, rate, tags)
 	}
+	s.metric(name, tags)
 }
 
-func (c *MockStats) Tags() []string                                        { return nil }
-func (c *MockStats) WithTags(tags ...string) stats.StatsClient             { return c }
-func (c *MockStats) Gauge(name string, value float64, rate float64)        {}
-func (c *MockStats) Histogram(name string, value float64, rate float64)    {}
-func (c *MockStats) Set(name string, value string, rate float64)           {}
-func (c *MockStats) Timing(name string, value time.Duration, rate float64) {}
+func (c *MockStats) Tags() []string { return c.tags }
+func (c *MockStats) WithTags(tags ...string) stats.StatsClient {
+	other := *c
+	other.tags = append(append([]string(nil), c.tags...), tags...)
+	return &other
+}
+func (c *MockStats) Gauge(name string, value float64, rate float64)        { c.metric(name, nil) }
+func (c *MockStats) Histogram(name string, value float64, rate float64)    { c.metric(name, nil) }
+func (c *MockStats) Set(name string, value string, rate float64)           { c.metric(name, nil) }
+func (c *MockStats) Timing(name string, value time.Duration, rate float64) { c.metric(name, nil) }
 func (c *MockStats) SetLogger(logger logger.Logger)                        {}
 func (c *MockStats) Open()                                                 {}
 func (c *MockStats) Close() error                                          { return nil }
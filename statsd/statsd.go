@@ -16,6 +16,7 @@ package statsd
 
 import (
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
@@ -42,6 +43,10 @@ type statsClient struct {
 	client *statsd.Client
 	tags   []string
 	logger logger.Logger
+
+	// plain is set for servers which don't support tags, in which case tags
+	// are folded into the names of metrics.
+	plain bool
 }
 
 // NewStatsClient returns a new instance of StatsClient.
@@ -57,6 +62,19 @@ func NewStatsClient(host string) (*statsClient, error) {
 	}, nil
 }
 
+// NewPlainStatsClient returns a new instance of StatsClient for plain StatsD
+// servers, which don't support tags. The tags of a metric are folded into its
+// name instead: "pilosa.setBit" with the tags "index:i" and "NodeID:n" is sent
+// as "pilosa.NodeID.n.index.i.setBit".
+func NewPlainStatsClient(host string) (*statsClient, error) {
+	c, err := NewStatsClient(host)
+	if err != nil {
+		return nil, err
+	}
+	c.plain = true
+	return c, nil
+}
+
 // Open no-op
 func (c *statsClient) Open() {}
 
@@ -76,48 +94,83 @@ func (c *statsClient) WithTags(tags ...string) stats.StatsClient {
 		client: c.client,
 		tags:   unionStringSlice(c.tags, tags),
 		logger: c.logger,
+		plain:  c.plain,
+	}
+}
+
+// metric returns the name and tags with which a metric is sent.
+func (c *statsClient) metric(name string, tags []string) (string, []string) {
+	if !c.plain {
+		return prefix + name, tags
 	}
+	return plainName(name, tags), nil
 }
 
+// plainName returns the name of a metric with its tags folded in, in sorted
+// order. The key and value of a tag become dotted parts of the name, and the
+// dots, colons, spaces and slashes in them are replaced.
+func plainName(name string, tags []string) string {
+	tags = append([]string(nil), tags...)
+	sort.Strings(tags)
+
+	var buf strings.Builder
+	buf.WriteString(prefix)
+	for _, tag := range tags {
+		for _, part := range strings.SplitN(tag, ":", 2) {
+			buf.WriteString(plainReplacer.Replace(part))
+			buf.WriteByte('.')
+		}
+	}
+	buf.WriteString(name)
+	return buf.String()
+}
+
+var plainReplacer = strings.NewReplacer(".", "_", ":", "_", " ", "_", "/", "_")
+
 // Count tracks the number of times something occurs per second.
 func (c *statsClient) Count(name string, value int64, rate float64) {
-	if err := c.client.Count(prefix+name, value, c.tags, rate); err != nil {
+	name, tags := c.metric(name, c.tags)
+	if err := c.client.Count(name, value, tags, rate); err != nil {
 		c.logger.Printf("statsd.StatsClient.Count error: %s", err)
 	}
 }
 
 // CountWithCustomTags tracks the number of times something occurs per second with custom tags.
 func (c *statsClient) CountWithCustomTags(name string, value int64, rate float64, t []string) {
-	tags := append(c.tags, t...)
-	if err := c.client.Count(prefix+name, value, tags, rate); err != nil {
+	name, tags := c.metric(name, append(append([]string(nil), c.tags...), t...))
+	if err := c.client.Count(name, value, tags, rate); err != nil {
 		c.logger.Printf("statsd.StatsClient.Count error: %s", err)
 	}
 }
 
 // Gauge sets the value of a metric.
 func (c *statsClient) Gauge(name string, value float64, rate float64) {
-	if err := c.client.Gauge(prefix+name, value, c.tags, rate); err != nil {
+	name, tags := c.metric(name, c.tags)
+	if err := c.client.Gauge(name, value, tags, rate); err != nil {
 		c.logger.Printf("statsd.StatsClient.Gauge error: %s", err)
 	}
 }
 
 // Histogram tracks statistical distribution of a metric.
 func (c *statsClient) Histogram(name string, value float64, rate float64) {
-	if err := c.client.Histogram(prefix+name, value, c.tags, rate); err != nil {
+	name, tags := c.metric(name, c.tags)
+	if err := c.client.Histogram(name, value, tags, rate); err != nil {
 		c.logger.Printf("statsd.StatsClient.Histogram error: %s", err)
 	}
 }
 
 // Set tracks number of unique elements.
 func (c *statsClient) Set(name string, value string, rate float64) {
-	if err := c.client.Set(prefix+name, value, c.tags, rate); err != nil {
+	name, tags := c.metric(name, c.tags)
+	if err := c.client.Set(name, value, tags, rate); err != nil {
 		c.logger.Printf("statsd.StatsClient.Set error: %s", err)
 	}
 }
 
 // Timing tracks timing information for a metric.
 func (c *statsClient) Timing(name string, value time.Duration, rate float64) {
-	if err := c.client.Timing(prefix+name, value, c.tags, rate); err != nil {
+	name, tags := c.metric(name, c.tags)
+	if err := c.client.Timing(name, value, tags, rate); err != nil {
 		c.logger.Printf("statsd.StatsClient.Timing error: %s", err)
 	}
 }
@@ -0,0 +1,42 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatsClient_Metric(t *testing.T) {
+	c, err := NewStatsClient("localhost:19444")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	tags := []string{"index:i", "NodeID:n"}
+	if name, got := c.metric("setBit", tags); name != "pilosa.setBit" || !reflect.DeepEqual(got, tags) {
+		t.Fatalf("unexpected metric: %s %v", name, got)
+	}
+
+	// Plain StatsD folds the tags into the name.
+	c.plain = true
+	if name, got := c.metric("setBit", tags); name != "pilosa.NodeID.n.index.i.setBit" || got != nil {
+		t.Fatalf("unexpected metric: %s %v", name, got)
+	}
+	if name, _ := c.metric("http.request", []string{"path:/index/{index}", "useragent:go 1.0", "slow_query"}); name != "pilosa.path._index_{index}.slow_query.useragent.go_1_0.http.request" {
+		t.Fatalf("unexpected name: %s", name)
+	}
+}
@@ -0,0 +1,440 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/logger"
+	"github.com/pilosa/pilosa/v2/stats"
+)
+
+const (
+	// defaultStatsHistoryRetention is the default duration for which the
+	// per-minute statistics of each index are kept.
+	defaultStatsHistoryRetention = 6 * time.Hour
+
+	// defaultStatsHistoryMaxIndexes is the default number of indexes whose
+	// statistics are kept.
+	defaultStatsHistoryMaxIndexes = 100
+
+	// latencyBuckets is the number of buckets of a latencyHistogram.
+	latencyBuckets = 128
+)
+
+// Names of the metrics which are kept by the stats history, besides the
+// writes in historyWrites.
+const (
+	// statQuery is the timing of a query received from a client.
+	statQuery = "query"
+
+	// statQueryCall counts the top-level calls of a query received from a
+	// client, tagged with the name of the call.
+	statQueryCall = "queryCall"
+
+	// statQueryError counts queries received from a client which failed.
+	statQueryError = "queryError"
+)
+
+// historyWrites are the counts of writes to fragments which are kept by the
+// stats history.
+var historyWrites = map[string]bool{
+	"setBit":      true,
+	"clearBit":    true,
+	"setRow":      true,
+	"clearRow":    true,
+	"clearColumn": true,
+	"ImportedN":   true,
+	"ClearedN":    true,
+}
+
+// statsHistoryOptions configures the stats history of a holder.
+type statsHistoryOptions struct {
+	// How long the statistics of each minute are kept. Zero disables the
+	// history.
+	retention time.Duration
+
+	// Maximum number of indexes whose statistics are kept. Indexes which
+	// are first seen once the limit is reached aren't tracked.
+	maxIndexes int
+}
+
+// IndexStatsHistory is the per-minute statistics of the queries and writes
+// of an index on a node.
+type IndexStatsHistory struct {
+	Index  string              `json:"index"`
+	Points []StatsHistoryPoint `json:"points"`
+}
+
+// StatsHistoryPoint is the statistics of an index during a minute.
+type StatsHistoryPoint struct {
+	Time time.Time `json:"time"`
+
+	// Queries is the number of queries received from clients, Calls the
+	// number of their top-level calls by name, and Errors the number of
+	// queries which failed.
+	Queries uint64            `json:"queries"`
+	Calls   map[string]uint64 `json:"calls,omitempty"`
+	Errors  uint64            `json:"errors"`
+
+	// Writes is the number of writes to the fragments on the node, by
+	// operation.
+	Writes map[string]uint64 `json:"writes,omitempty"`
+
+	// Approximate latency percentiles of the queries in milliseconds.
+	P50 float64 `json:"p50Ms"`
+	P99 float64 `json:"p99Ms"`
+}
+
+// statsHistory keeps the per-minute statistics of each index for a bounded
+// duration. It is fed by the stats client of the holder.
+type statsHistory struct {
+	mu      sync.RWMutex
+	opt     statsHistoryOptions
+	indexes map[string]*indexStats
+	full    bool // set once an index wasn't tracked because of maxIndexes
+
+	now    func() time.Time
+	logger logger.Logger
+}
+
+func newStatsHistory(opt statsHistoryOptions) *statsHistory {
+	return &statsHistory{
+		opt:     opt,
+		indexes: make(map[string]*indexStats),
+		now:     time.Now,
+		logger:  logger.NopLogger,
+	}
+}
+
+// minutes returns the number of minutes kept for each index.
+func (h *statsHistory) minutes() int {
+	return int((h.opt.retention + time.Minute - 1) / time.Minute)
+}
+
+// index returns the statistics of an index, which are created if create is
+// set, unless maxIndexes is reached.
+func (h *statsHistory) index(name string, create bool) *indexStats {
+	h.mu.RLock()
+	s := h.indexes[name]
+	h.mu.RUnlock()
+	if s != nil || !create {
+		return s
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s := h.indexes[name]; s != nil {
+		return s
+	} else if h.opt.maxIndexes > 0 && len(h.indexes) >= h.opt.maxIndexes {
+		if !h.full {
+			h.full = true
+			h.logger.Printf("stats history is full, not tracking index %s: max indexes %d", name, h.opt.maxIndexes)
+		}
+		return nil
+	}
+	s = &indexStats{minutes: make([]minuteStats, h.minutes())}
+	h.indexes[name] = s
+	return s
+}
+
+// record applies fn to the statistics of the current minute of an index.
+func (h *statsHistory) record(index string, fn func(m *minuteStats)) {
+	if s := h.index(index, true); s != nil {
+		s.record(h.now().Unix()/60, fn)
+	}
+}
+
+// history returns the statistics of the minutes of the window ending with
+// the current minute of an index, or of every tracked index if index is
+// empty. The window is limited to the retention of the history.
+func (h *statsHistory) history(index string, window time.Duration) []IndexStatsHistory {
+	if window > h.opt.retention {
+		window = h.opt.retention
+	}
+	n := int64((window + time.Minute - 1) / time.Minute)
+	last := h.now().Unix() / 60
+
+	var names []string
+	if index != "" {
+		names = []string{index}
+	} else {
+		h.mu.RLock()
+		for name := range h.indexes {
+			names = append(names, name)
+		}
+		h.mu.RUnlock()
+		sort.Strings(names)
+	}
+
+	other := make([]IndexStatsHistory, 0, len(names))
+	for _, name := range names {
+		hist := IndexStatsHistory{Index: name, Points: []StatsHistoryPoint{}}
+		s := h.index(name, false)
+		for minute := last - n + 1; minute <= last; minute++ {
+			p := StatsHistoryPoint{Time: time.Unix(minute*60, 0).UTC()}
+			if s != nil {
+				s.point(minute, &p)
+			}
+			hist.Points = append(hist.Points, p)
+		}
+		other = append(other, hist)
+	}
+	return other
+}
+
+// totals returns the statistics of all indexes over the minutes of the
+// window ending with the current minute, summed.
+func (h *statsHistory) totals(window time.Duration) (queries, writes, failed uint64, latency *latencyHistogram) {
+	if window > h.opt.retention {
+		window = h.opt.retention
+	}
+	n := int64((window + time.Minute - 1) / time.Minute)
+	last := h.now().Unix() / 60
+
+	h.mu.RLock()
+	indexes := make([]*indexStats, 0, len(h.indexes))
+	for _, s := range h.indexes {
+		indexes = append(indexes, s)
+	}
+	h.mu.RUnlock()
+
+	latency = &latencyHistogram{}
+	for _, s := range indexes {
+		s.mu.Lock()
+		for minute := last - n + 1; minute <= last; minute++ {
+			m := &s.minutes[minute%int64(len(s.minutes))]
+			if m.minute != minute {
+				continue
+			}
+			for _, c := range m.writes {
+				writes += c
+			}
+			failed += m.errors
+			if m.latency != nil {
+				for i, c := range m.latency {
+					latency[i] += c
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+	return latency.count(), writes, failed, latency
+}
+
+// client returns a stats client which records the metrics kept by the
+// history.
+func (h *statsHistory) client() stats.StatsClient {
+	return &historyStatsClient{history: h}
+}
+
+// indexStats is a ring of the statistics of the last minutes of an index.
+type indexStats struct {
+	mu      sync.Mutex
+	minutes []minuteStats
+}
+
+// record applies fn to the statistics of a minute, which replace the
+// statistics of the minute kept in the same slot.
+func (s *indexStats) record(minute int64, fn func(m *minuteStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := &s.minutes[minute%int64(len(s.minutes))]
+	if m.minute != minute {
+		*m = minuteStats{minute: minute}
+	}
+	fn(m)
+}
+
+// point sets the statistics of a minute on p, if they are kept.
+func (s *indexStats) point(minute int64, p *StatsHistoryPoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := &s.minutes[minute%int64(len(s.minutes))]
+	if m.minute != minute {
+		return
+	}
+	p.Queries = m.latency.count()
+	p.Calls = copyCounts(m.calls)
+	p.Errors = m.errors
+	p.Writes = copyCounts(m.writes)
+	p.P50 = m.latency.quantile(0.5)
+	p.P99 = m.latency.quantile(0.99)
+}
+
+// minuteStats is the statistics of an index during a minute.
+type minuteStats struct {
+	minute  int64 // Unix time in minutes
+	calls   map[string]uint64
+	writes  map[string]uint64
+	errors  uint64
+	latency *latencyHistogram
+}
+
+// add adds n to the count of name in counts, which is allocated if nil.
+func addCount(counts *map[string]uint64, name string, n uint64) {
+	if *counts == nil {
+		*counts = make(map[string]uint64)
+	}
+	(*counts)[name] += n
+}
+
+// copyCounts returns a copy of counts.
+func copyCounts(counts map[string]uint64) map[string]uint64 {
+	if len(counts) == 0 {
+		return nil
+	}
+	other := make(map[string]uint64, len(counts))
+	for k, v := range counts {
+		other[k] = v
+	}
+	return other
+}
+
+// latencyHistogram counts latencies in buckets which grow by a factor of
+// 2^(1/4), so quantiles are accurate to about 20%. Bucket 0 holds latencies
+// below a microsecond, and bucket i latencies below 2^(i/4) microseconds.
+type latencyHistogram [latencyBuckets]uint32
+
+// latencyBucket returns the bucket of a latency.
+func latencyBucket(d time.Duration) int {
+	us := float64(d) / float64(time.Microsecond)
+	if us < 1 {
+		return 0
+	}
+	i := 1 + int(math.Log2(us)*4)
+	if i >= latencyBuckets {
+		i = latencyBuckets - 1
+	}
+	return i
+}
+
+// add adds a latency to the histogram.
+func (h *latencyHistogram) add(d time.Duration) {
+	h[latencyBucket(d)]++
+}
+
+// count returns the number of latencies in the histogram.
+func (h *latencyHistogram) count() uint64 {
+	if h == nil {
+		return 0
+	}
+	var n uint64
+	for _, c := range h {
+		n += uint64(c)
+	}
+	return n
+}
+
+// quantile returns the upper bound in milliseconds of the bucket which
+// holds the q-quantile of the latencies, or zero if there are none.
+func (h *latencyHistogram) quantile(q float64) float64 {
+	n := h.count()
+	if n == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(n)))
+	var seen uint64
+	for i, c := range h {
+		if seen += uint64(c); seen >= rank {
+			return math.Pow(2, float64(i)/4) / 1000
+		}
+	}
+	return 0
+}
+
+// historyStatsClient is a stats client which records the metrics of an
+// index in the stats history. The index is set by the "index:" tag, which
+// the holder adds to the stats client of each index.
+type historyStatsClient struct {
+	history *statsHistory
+	index   string
+	tags    []string
+}
+
+// Tags returns the tags of the client.
+func (c *historyStatsClient) Tags() []string { return c.tags }
+
+// WithTags returns a new client with additional tags appended.
+func (c *historyStatsClient) WithTags(tags ...string) stats.StatsClient {
+	other := &historyStatsClient{history: c.history, index: c.index}
+	other.tags = append(append(other.tags, c.tags...), tags...)
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, "index:") {
+			other.index = strings.TrimPrefix(tag, "index:")
+		}
+	}
+	return other
+}
+
+// Count records writes and query errors.
+func (c *historyStatsClient) Count(name string, value int64, rate float64) {
+	if c.index == "" || value <= 0 {
+		return
+	}
+	if historyWrites[name] {
+		c.history.record(c.index, func(m *minuteStats) { addCount(&m.writes, name, uint64(value)) })
+	} else if name == statQueryError {
+		c.history.record(c.index, func(m *minuteStats) { m.errors += uint64(value) })
+	}
+}
+
+// CountWithCustomTags records the top-level calls of queries.
+func (c *historyStatsClient) CountWithCustomTags(name string, value int64, rate float64, tags []string) {
+	if c.index == "" || value <= 0 || name != statQueryCall {
+		return
+	}
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, "call:") {
+			call := strings.TrimPrefix(tag, "call:")
+			c.history.record(c.index, func(m *minuteStats) { addCount(&m.calls, call, uint64(value)) })
+		}
+	}
+}
+
+// Timing records the latency of queries.
+func (c *historyStatsClient) Timing(name string, value time.Duration, rate float64) {
+	if c.index == "" || name != statQuery {
+		return
+	}
+	c.history.record(c.index, func(m *minuteStats) {
+		if m.latency == nil {
+			m.latency = &latencyHistogram{}
+		}
+		m.latency.add(value)
+	})
+}
+
+// Gauge is a no-op, as gauges aren't kept by the history.
+func (c *historyStatsClient) Gauge(name string, value float64, rate float64) {}
+
+// Histogram is a no-op, as histograms aren't kept by the history.
+func (c *historyStatsClient) Histogram(name string, value float64, rate float64) {}
+
+// Set is a no-op, as sets aren't kept by the history.
+func (c *historyStatsClient) Set(name string, value string, rate float64) {}
+
+// SetLogger is a no-op.
+func (c *historyStatsClient) SetLogger(logger logger.Logger) {}
+
+// Open is a no-op.
+func (c *historyStatsClient) Open() {}
+
+// Close is a no-op.
+func (c *historyStatsClient) Close() error { return nil }
@@ -0,0 +1,151 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatsHistory(t *testing.T) {
+	now := time.Date(2020, 1, 30, 12, 0, 30, 0, time.UTC)
+	newHistory := func(opt statsHistoryOptions) *statsHistory {
+		h := newStatsHistory(opt)
+		h.now = func() time.Time { return now }
+		return h
+	}
+
+	t.Run("Record", func(t *testing.T) {
+		h := newHistory(statsHistoryOptions{retention: 10 * time.Minute})
+		sc := h.client().WithTags("NodeID:n").WithTags("index:i")
+		sc.Timing(statQuery, time.Millisecond, 1.0)
+		sc.CountWithCustomTags(statQueryCall, 1, 1.0, []string{"call:Count"})
+		sc.Count(statQueryError, 1, 1.0)
+		sc.WithTags("field:f", "view:standard", "shard:0").Count("setBit", 1, 0.001)
+		sc.Count("ImportedN", 5, 1.0)
+
+		// Metrics which aren't kept, or aren't tagged with an index.
+		sc.Count("createField", 1, 1.0)
+		sc.Timing("other", time.Second, 1.0)
+		h.client().Timing(statQuery, time.Second, 1.0)
+
+		hist := h.history("i", 2*time.Minute)
+		if len(hist) != 1 || len(hist[0].Points) != 2 {
+			t.Fatalf("unexpected history: %+v", hist)
+		}
+		if p := hist[0].Points[0]; p.Queries != 0 || p.Time != time.Date(2020, 1, 30, 11, 59, 0, 0, time.UTC) {
+			t.Fatalf("unexpected point: %+v", p)
+		}
+		p := hist[0].Points[1]
+		if p.Queries != 1 || p.Errors != 1 || p.Time != time.Date(2020, 1, 30, 12, 0, 0, 0, time.UTC) {
+			t.Fatalf("unexpected point: %+v", p)
+		} else if !reflect.DeepEqual(p.Calls, map[string]uint64{"Count": 1}) {
+			t.Fatalf("unexpected calls: %v", p.Calls)
+		} else if !reflect.DeepEqual(p.Writes, map[string]uint64{"setBit": 1, "ImportedN": 5}) {
+			t.Fatalf("unexpected writes: %v", p.Writes)
+		} else if p.P50 < 1 || p.P50 > 1.2 || p.P99 != p.P50 {
+			t.Fatalf("unexpected latency: p50 %v, p99 %v", p.P50, p.P99)
+		}
+
+		// Unknown indexes have empty points.
+		if hist := h.history("j", time.Minute); len(hist) != 1 || hist[0].Index != "j" || hist[0].Points[0].Queries != 0 {
+			t.Fatalf("unexpected history: %+v", hist)
+		}
+	})
+
+	// Minutes older than the retention are overwritten, and windows are
+	// limited to the retention.
+	t.Run("Retention", func(t *testing.T) {
+		h := newHistory(statsHistoryOptions{retention: 3 * time.Minute})
+		sc := h.client().WithTags("index:i")
+		for i := 0; i < 5; i++ {
+			for j := 0; j <= i; j++ {
+				sc.Timing(statQuery, time.Millisecond, 1.0)
+			}
+			now = now.Add(time.Minute)
+		}
+		now = now.Add(-time.Minute)
+
+		hist := h.history("i", time.Hour)
+		var got []uint64
+		for _, p := range hist[0].Points {
+			got = append(got, p.Queries)
+		}
+		if !reflect.DeepEqual(got, []uint64{3, 4, 5}) {
+			t.Fatalf("unexpected queries: %v", got)
+		}
+
+		// Minutes without activity since the last write are empty.
+		now = now.Add(2 * time.Minute)
+		got = got[:0]
+		for _, p := range h.history("i", time.Hour)[0].Points {
+			got = append(got, p.Queries)
+		}
+		if !reflect.DeepEqual(got, []uint64{5, 0, 0}) {
+			t.Fatalf("unexpected queries: %v", got)
+		}
+	})
+
+	t.Run("MaxIndexes", func(t *testing.T) {
+		h := newHistory(statsHistoryOptions{retention: time.Hour, maxIndexes: 2})
+		for _, index := range []string{"b", "a", "c"} {
+			h.client().WithTags("index:"+index).Timing(statQuery, time.Millisecond, 1.0)
+		}
+		hist := h.history("", time.Minute)
+		if len(hist) != 2 || hist[0].Index != "a" || hist[1].Index != "b" {
+			t.Fatalf("unexpected history: %+v", hist)
+		}
+
+		queries, _, _, _ := h.totals(time.Hour)
+		if queries != 2 {
+			t.Fatalf("unexpected total queries: %d", queries)
+		}
+	})
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	var h latencyHistogram
+	if q := h.quantile(0.5); q != 0 {
+		t.Fatalf("unexpected quantile of empty histogram: %v", q)
+	}
+
+	// 98 latencies of 1ms and 2 of 1s.
+	for i := 0; i < 98; i++ {
+		h.add(time.Millisecond)
+	}
+	h.add(time.Second)
+	h.add(time.Second)
+	for _, tt := range []struct {
+		q, want float64
+	}{
+		{0.5, 1},
+		{0.98, 1},
+		{0.99, 1000},
+	} {
+		// Quantiles are the upper bounds of buckets, at most 2^(1/4) times
+		// the latencies.
+		if got := h.quantile(tt.q); got < tt.want || got > tt.want*math.Pow(2, 0.25) {
+			t.Errorf("quantile %v: got %v, want about %v", tt.q, got, tt.want)
+		}
+	}
+
+	h.add(time.Duration(math.MaxInt64))
+	h.add(0)
+	if h[latencyBuckets-1] != 1 || h[0] != 1 {
+		t.Fatalf("unexpected extreme buckets: %d, %d", h[latencyBuckets-1], h[0])
+	}
+}
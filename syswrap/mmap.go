@@ -40,6 +40,18 @@ func SetMaxMapCount(max uint64) {
 	mu.Unlock()
 }
 
+// MaxMapCount returns the limit on the number of active mmaps.
+func MaxMapCount() uint64 {
+	mu.RLock()
+	defer mu.RUnlock()
+	return maxMapCount
+}
+
+// MapCount returns the number of active mmaps.
+func MapCount() uint64 {
+	return atomic.LoadUint64(&mapCount)
+}
+
 // Mmap increments the global map count, and then calls syscall.Mmap. It
 // decrements the map count and returns an error if the count was over the
 // limit. If syscall.Mmap returns an error it also decrements the count.
@@ -33,6 +33,18 @@ func SetMaxFileCount(max uint64) {
 	fileMu.Unlock()
 }
 
+// MaxFileCount returns the soft limit on the number of open files.
+func MaxFileCount() uint64 {
+	fileMu.RLock()
+	defer fileMu.RUnlock()
+	return maxFileCount
+}
+
+// FileCount returns the number of files currently open through OpenFile.
+func FileCount() uint64 {
+	return atomic.LoadUint64(&fileCount)
+}
+
 // OpenFile passes the arguments along to os.OpenFile while incrementing a
 // counter. If the counter is above the maximum, it returns mustClose true to
 // signal the calling function that it should not keep the file open
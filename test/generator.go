@@ -0,0 +1,223 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pkg/errors"
+)
+
+// Distribution describes how generated bits are spread across the rows of a
+// field.
+type Distribution int
+
+const (
+	// DistributionUniform gives every row the same probability of being set.
+	DistributionUniform Distribution = iota
+
+	// DistributionZipf skews bits towards low row IDs so that a few rows are
+	// very dense and most are sparse.
+	DistributionZipf
+)
+
+// GeneratorField describes the data to generate for a single field.
+type GeneratorField struct {
+	Name string
+
+	// Rows is the number of distinct row IDs, starting at zero.
+	Rows uint64
+
+	// Distribution determines how columns are assigned to rows.
+	Distribution Distribution
+
+	// Density is the fraction of columns in [0, Generator.Columns) which
+	// have a bit set in this field. Values outside of (0, 1] are treated as 1.
+	Density float64
+}
+
+// Generator produces reproducible set-field data for tests and benchmarks.
+// Two generators with the same seed and layout always produce the same bits.
+type Generator struct {
+	Seed   int64
+	Index  string
+	Fields []GeneratorField
+
+	// Columns is the number of columns, starting at zero, which may be set.
+	Columns uint64
+}
+
+// Dataset is the generated data along with its expected query results.
+type Dataset struct {
+	Index string
+
+	// Bits holds the (rowID, columnID) pairs for each field, sorted by
+	// column and then row.
+	Bits map[string][][2]uint64
+
+	// Counts holds the expected Count(Row()) result for every non-empty row
+	// of each field.
+	Counts map[string]map[uint64]uint64
+}
+
+// Generate builds the dataset described by the generator.
+func (g *Generator) Generate() *Dataset {
+	d := &Dataset{
+		Index:  g.Index,
+		Bits:   make(map[string][][2]uint64, len(g.Fields)),
+		Counts: make(map[string]map[uint64]uint64, len(g.Fields)),
+	}
+
+	for i, fld := range g.Fields {
+		// Each field gets its own source so that adding a field to the
+		// layout does not change the data generated for the others.
+		rnd := rand.New(rand.NewSource(g.Seed + int64(i)))
+
+		rows := fld.Rows
+		if rows == 0 {
+			rows = 1
+		}
+		density := fld.Density
+		if density <= 0 || density > 1 {
+			density = 1
+		}
+
+		var zipf *rand.Zipf
+		if fld.Distribution == DistributionZipf && rows > 1 {
+			zipf = rand.NewZipf(rnd, 1.1, 1, rows-1)
+		}
+
+		bits := make([][2]uint64, 0, int(float64(g.Columns)*density))
+		counts := make(map[uint64]uint64)
+		for col := uint64(0); col < g.Columns; col++ {
+			if rnd.Float64() >= density {
+				continue
+			}
+			var row uint64
+			if zipf != nil {
+				row = zipf.Uint64()
+			} else {
+				row = uint64(rnd.Int63n(int64(rows)))
+			}
+			bits = append(bits, [2]uint64{row, col})
+			counts[row]++
+		}
+
+		d.Bits[fld.Name] = bits
+		d.Counts[fld.Name] = counts
+	}
+	return d
+}
+
+// Count returns the expected number of columns set in a row.
+func (d *Dataset) Count(field string, rowID uint64) uint64 {
+	return d.Counts[field][rowID]
+}
+
+// RowIDs returns the sorted IDs of all non-empty rows in a field.
+func (d *Dataset) RowIDs(field string) []uint64 {
+	ids := make([]uint64, 0, len(d.Counts[field]))
+	for id := range d.Counts[field] {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Populate imports the dataset into h, creating the index and fields as
+// needed.
+func (d *Dataset) Populate(h *Holder) error {
+	idx, err := h.Holder.CreateIndexIfNotExists(d.Index, pilosa.IndexOptions{})
+	if err != nil {
+		return errors.Wrap(err, "creating index")
+	}
+	for _, name := range d.fieldNames() {
+		f, err := idx.CreateFieldIfNotExists(name, pilosa.OptFieldTypeDefault())
+		if err != nil {
+			return errors.Wrapf(err, "creating field %s", name)
+		}
+		bits := d.Bits[name]
+		rowIDs := make([]uint64, len(bits))
+		colIDs := make([]uint64, len(bits))
+		for i, bit := range bits {
+			rowIDs[i], colIDs[i] = bit[0], bit[1]
+		}
+		if err := f.Import(rowIDs, colIDs, nil); err != nil {
+			return errors.Wrapf(err, "importing field %s", name)
+		}
+	}
+	return nil
+}
+
+// MustPopulate imports the dataset into h. Panic on error.
+func (d *Dataset) MustPopulate(h *Holder) {
+	if err := d.Populate(h); err != nil {
+		panic(err)
+	}
+}
+
+// PopulateCluster imports the dataset into c through the bulk import API.
+func (d *Dataset) PopulateCluster(t testing.TB, c Cluster) {
+	for _, name := range d.fieldNames() {
+		c.CreateField(t, d.Index, pilosa.IndexOptions{}, name)
+		c.ImportBits(t, d.Index, name, d.Bits[name])
+	}
+}
+
+// MustPopulateDivergent imports the dataset into both a and b, then clears n
+// generated bits from b so that the holders start from a known difference.
+// The cleared bits are chosen deterministically from seed and returned as a
+// map from field name to (rowID, columnID) pairs.
+func (d *Dataset) MustPopulateDivergent(a, b *Holder, n int, seed int64) map[string][][2]uint64 {
+	d.MustPopulate(a)
+	d.MustPopulate(b)
+
+	type fieldBit struct {
+		field string
+		bit   [2]uint64
+	}
+	var all []fieldBit
+	for _, name := range d.fieldNames() {
+		for _, bit := range d.Bits[name] {
+			all = append(all, fieldBit{field: name, bit: bit})
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	rnd.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
+	if n > len(all) {
+		n = len(all)
+	}
+
+	cleared := make(map[string][][2]uint64)
+	for _, fb := range all[:n] {
+		b.ClearBit(d.Index, fb.field, fb.bit[0], fb.bit[1])
+		cleared[fb.field] = append(cleared[fb.field], fb.bit)
+	}
+	return cleared
+}
+
+// fieldNames returns the dataset's field names in sorted order.
+func (d *Dataset) fieldNames() []string {
+	names := make([]string, 0, len(d.Bits))
+	for name := range d.Bits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pilosa/pilosa/v2"
+	"github.com/pilosa/pilosa/v2/test"
+)
+
+func newTestGenerator(seed int64) *test.Generator {
+	return &test.Generator{
+		Seed:  seed,
+		Index: "i",
+		Fields: []test.GeneratorField{
+			{Name: "u", Rows: 10, Distribution: test.DistributionUniform, Density: 0.5},
+			{Name: "z", Rows: 100, Distribution: test.DistributionZipf, Density: 0.2},
+		},
+		Columns: 2*pilosa.ShardWidth + 100,
+	}
+}
+
+func TestGenerator_Deterministic(t *testing.T) {
+	a, b := newTestGenerator(1).Generate(), newTestGenerator(1).Generate()
+	if !reflect.DeepEqual(a.Bits, b.Bits) {
+		t.Fatal("same seed produced different bits")
+	}
+	if c := newTestGenerator(2).Generate(); reflect.DeepEqual(a.Bits, c.Bits) {
+		t.Fatal("different seeds produced identical bits")
+	}
+}
+
+func TestGenerator_Populate(t *testing.T) {
+	d := newTestGenerator(1).Generate()
+
+	h := test.MustOpenHolder()
+	defer h.Close()
+	d.MustPopulate(h)
+
+	for _, field := range []string{"u", "z"} {
+		for _, rowID := range d.RowIDs(field) {
+			if n, exp := h.ReadRow("i", field, rowID).Count(), d.Count(field, rowID); n != exp {
+				t.Fatalf("%s row %d: count=%d, expected %d", field, rowID, n, exp)
+			}
+		}
+	}
+}
+
+func TestGenerator_PopulateDivergent(t *testing.T) {
+	d := newTestGenerator(1).Generate()
+
+	a, b := test.MustOpenHolder(), test.MustOpenHolder()
+	defer a.Close()
+	defer b.Close()
+	cleared := d.MustPopulateDivergent(a, b, 20, 7)
+
+	var n int
+	for field, bits := range cleared {
+		n += len(bits)
+		for _, bit := range bits {
+			if !rowIncludes(a.ReadRow("i", field, bit[0]), bit[1]) {
+				t.Fatalf("expected %s bit %v to be set on untouched holder", field, bit)
+			}
+			if rowIncludes(b.ReadRow("i", field, bit[0]), bit[1]) {
+				t.Fatalf("expected %s bit %v to be cleared on perturbed holder", field, bit)
+			}
+		}
+	}
+	if n != 20 {
+		t.Fatalf("expected 20 cleared bits, got %d", n)
+	}
+}
+
+func TestGenerator_PopulateCluster(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+
+	d := newTestGenerator(3).Generate()
+	d.PopulateCluster(t, c)
+
+	for _, rowID := range d.RowIDs("u") {
+		resp := c.Query(t, "i", fmt.Sprintf("Count(Row(u=%d))", rowID))
+		if n := resp.Results[0].(uint64); n != d.Count("u", rowID) {
+			t.Fatalf("row %d: count=%d, expected %d", rowID, n, d.Count("u", rowID))
+		}
+	}
+}
+
+func rowIncludes(r *pilosa.Row, columnID uint64) bool {
+	for _, col := range r.Columns() {
+		if col == columnID {
+			return true
+		}
+	}
+	return false
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/pilosa/pilosa/v2/http"
 	"github.com/pilosa/pilosa/v2/server"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 )
 
 ////////////////////////////////////////////////////////////////////////////////////
@@ -41,6 +42,10 @@ type Command struct {
 	*server.Command
 
 	commandOptions []server.CommandOption
+
+	// stopped is set when the command has been closed by Cluster.StopNode
+	// and its data directory is being kept for a later restart.
+	stopped bool
 }
 
 func OptAllowedOrigins(origins []string) server.CommandOption {
@@ -253,9 +258,15 @@ func (c Cluster) Query(t testing.TB, index, query string) pilosa.QueryResponse {
 }
 
 func (c Cluster) ImportBits(t testing.TB, index, field string, rowcols [][2]uint64) {
+	idx, err := c[0].API.Index(context.Background(), index)
+	if err != nil {
+		t.Fatalf("getting index: %v", err)
+	}
+	defer idx.Close()
+
 	byShard := make(map[uint64][][2]uint64)
 	for _, rowcol := range rowcols {
-		shard := rowcol[1] / pilosa.ShardWidth
+		shard := rowcol[1] / idx.ShardWidth()
 		byShard[shard] = append(byShard[shard], rowcol)
 	}
 
@@ -273,7 +284,7 @@ func (c Cluster) ImportBits(t testing.TB, index, field string, rowcols [][2]uint
 		// TODO won't be necessary to do all nodes once that works hits
 		for _, node := range nodes {
 			for _, com := range c {
-				if com.API.Node().ID != node.ID {
+				if com.stopped || com.API.Node().ID != node.ID {
 					continue
 				}
 				err := com.API.Import(context.Background(), &pilosa.ImportRequest{
@@ -297,10 +308,12 @@ func (c Cluster) CreateField(t testing.TB, index string, iopts pilosa.IndexOptio
 	if err != nil && !strings.Contains(err.Error(), "index already exists") {
 		t.Fatalf("creating index: %v", err)
 	} else if err != nil { // index exists
-		idx, err = c[0].API.Index(context.Background(), index)
+		h, err := c[0].API.Index(context.Background(), index)
 		if err != nil {
 			t.Fatalf("getting index: %v", err)
 		}
+		idx = h.Index
+		h.Close()
 	}
 	if idx.Options() != iopts {
 		t.Logf("existing index options:\n%v\ndon't match given opts:\n%v\n in pilosa/test.Cluster.CreateField", idx.Options(), iopts)
@@ -332,6 +345,10 @@ func (c Cluster) Start() error {
 // Stop stops a Cluster
 func (c Cluster) Close() error {
 	for i, cc := range c {
+		if cc.stopped {
+			os.RemoveAll(cc.Config.DataDir)
+			continue
+		}
 		if err := cc.Close(); err != nil {
 			return errors.Wrapf(err, "stopping server %d", i)
 		}
@@ -339,6 +356,105 @@ func (c Cluster) Close() error {
 	return nil
 }
 
+// StopNode closes the node at index i without removing its data directory,
+// simulating a crashed node. The node can be brought back with StartNode.
+func (c Cluster) StopNode(i int) error {
+	m := c[i]
+	if m.stopped {
+		return errors.Errorf("node %d is already stopped", i)
+	}
+
+	// Keep the same gossip port so that the restarted node is recognized
+	// by memberlist rather than conflicting with its previous address, and
+	// the same bind address so that other nodes can reach it at the address
+	// in their persisted cluster status.
+	m.Config.Gossip.Port = strconv.Itoa(int(m.GossipTransport().URI.Port))
+	m.Config.Bind = m.URL()
+
+	if err := m.Command.Close(); err != nil {
+		return errors.Wrapf(err, "stopping server %d", i)
+	}
+	m.stopped = true
+	return nil
+}
+
+// StartNode restarts a node previously stopped with StopNode against its
+// existing data directory. The node rejoins through the running nodes and
+// sends a NodeJoin to the coordinator, which decides whether the node can
+// simply resume or whether a resize is required.
+func (c Cluster) StartNode(i int) error {
+	m := c[i]
+	if !m.stopped {
+		return errors.Errorf("node %d is not stopped", i)
+	}
+
+	// Seed gossip from the nodes which are still running.
+	config := m.Command.Config
+	config.Gossip.Seeds = nil
+	for j, cc := range c {
+		if j == i || cc.stopped {
+			continue
+		}
+		config.Gossip.Seeds = append(config.Gossip.Seeds, cc.GossipAddress())
+	}
+
+	m.Command = server.NewCommand(m.Stdin, m.Stdout, m.Stderr, m.commandOptions...)
+	m.Command.Config = config
+	m.stopped = false
+
+	return errors.Wrapf(m.Start(), "starting server %d", i)
+}
+
+// StartNodes concurrently restarts the nodes at indexes, which were previously
+// stopped with StopNode, as after a full-cluster outage. Each node is seeded
+// with the running nodes and the other non-coordinator nodes being started,
+// so a coordinator started with no running nodes has to find the other nodes
+// through its persisted cluster status.
+func (c Cluster) StartNodes(indexes ...int) error {
+	var seeds, peers []string
+	for i, cc := range c {
+		if !cc.stopped {
+			seeds = append(seeds, cc.GossipAddress())
+		}
+		for _, j := range indexes {
+			if i == j && !cc.Config.Cluster.Coordinator {
+				peers = append(peers, cc.GossipAddress())
+			}
+		}
+	}
+
+	var eg errgroup.Group
+	for _, i := range indexes {
+		i, m := i, c[i]
+		if !m.stopped {
+			return errors.Errorf("node %d is not stopped", i)
+		}
+
+		config := m.Command.Config
+		config.Gossip.Seeds = append([]string{}, seeds...)
+		if !config.Cluster.Coordinator {
+			for _, peer := range peers {
+				if peer != m.GossipAddress() {
+					config.Gossip.Seeds = append(config.Gossip.Seeds, peer)
+				}
+			}
+		}
+
+		m.Command = server.NewCommand(m.Stdin, m.Stdout, m.Stderr, m.commandOptions...)
+		m.Command.Config = config
+		m.stopped = false
+		eg.Go(func() error {
+			return errors.Wrapf(m.Start(), "starting server %d", i)
+		})
+	}
+	return eg.Wait()
+}
+
+// Stopped returns true if the node at index i was stopped with StopNode.
+func (c Cluster) Stopped(i int) bool {
+	return c[i].stopped
+}
+
 // MustNewCluster creates a new cluster
 func MustNewCluster(tb testing.TB, size int, opts ...[]server.CommandOption) Cluster {
 	c, err := newCluster(size, opts...)
@@ -34,6 +34,9 @@ func (q TimeQuantum) HasYear() bool { return strings.ContainsRune(string(q), 'Y'
 // HasMonth returns true if the quantum contains a 'M' unit.
 func (q TimeQuantum) HasMonth() bool { return strings.ContainsRune(string(q), 'M') }
 
+// HasWeek returns true if the quantum contains a 'W' unit.
+func (q TimeQuantum) HasWeek() bool { return strings.ContainsRune(string(q), 'W') }
+
 // HasDay returns true if the quantum contains a 'D' unit.
 func (q TimeQuantum) HasDay() bool { return strings.ContainsRune(string(q), 'D') }
 
@@ -49,11 +52,27 @@ func (q TimeQuantum) Valid() bool {
 		"H",
 		"":
 		return true
-	default:
+	}
+
+	// Quanta with a week unit may combine any units, as long as they are
+	// ordered from largest to smallest and not repeated.
+	if !q.HasWeek() {
 		return false
 	}
+	units := timeUnits
+	for _, unit := range q {
+		i := strings.IndexRune(units, unit)
+		if i < 0 {
+			return false
+		}
+		units = units[i+1:]
+	}
+	return true
 }
 
+// timeUnits lists the time quantum units from largest to smallest.
+const timeUnits = "YMWDH"
+
 // The following methods are required to implement pflag Value interface.
 
 // Set sets the time quantum value.
@@ -78,6 +97,9 @@ func viewByTimeUnit(name string, t time.Time, unit rune) string {
 		return fmt.Sprintf("%s_%s", name, t.Format("2006"))
 	case 'M':
 		return fmt.Sprintf("%s_%s", name, t.Format("200601"))
+	case 'W':
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%s_%04dW%02d", name, year, week)
 	case 'D':
 		return fmt.Sprintf("%s_%s", name, t.Format("20060102"))
 	case 'H':
@@ -100,8 +122,28 @@ func viewsByTime(name string, t time.Time, q TimeQuantum) []string { // nolint:
 	return a
 }
 
+// timeViewsKey identifies the hour of a timestamp, which determines the
+// views returned by viewsByTime for every quantum.
+type timeViewsKey struct {
+	year  int
+	month time.Month
+	day   int
+	hour  int
+}
+
+// newTimeViewsKey returns the key of the hour of t.
+func newTimeViewsKey(t time.Time) timeViewsKey {
+	y, m, d := t.Date()
+	return timeViewsKey{year: y, month: m, day: d, hour: t.Hour()}
+}
+
 // viewsByTimeRange returns a list of views to traverse to query a time range.
 func viewsByTimeRange(name string, start, end time.Time, q TimeQuantum) []string { // nolint: unparam
+	// Weeks don't nest within months or years, so they are tiled separately.
+	if q.HasWeek() {
+		return viewsByTimeRangeWeek(name, start, end, q)
+	}
+
 	t := start
 
 	// Save flags for performance.
@@ -175,6 +217,143 @@ func viewsByTimeRange(name string, start, end time.Time, q TimeQuantum) []string
 	return results
 }
 
+// viewsByTimeRangeSince returns a list of views to traverse to query a time
+// range with a quantum whose units may only be used from a start time, as
+// given by since. The range is split at those times and each part is covered
+// with the units which can be used for all of it.
+func viewsByTimeRangeSince(name string, start, end time.Time, q TimeQuantum, since map[string]time.Time) []string {
+	if len(since) == 0 {
+		return viewsByTimeRange(name, start, end, q)
+	}
+
+	bounds := []time.Time{start}
+	for _, t := range since {
+		if t.After(start) && t.Before(end) {
+			bounds = append(bounds, t)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+	bounds = append(bounds, end)
+
+	// A view can be selected for several parts.
+	var results []string
+	seen := make(map[string]struct{})
+	for i := 0; i+1 < len(bounds); i++ {
+		from, to := bounds[i], bounds[i+1]
+		if !from.Before(to) {
+			continue
+		}
+		var units []rune
+		for _, unit := range q {
+			if t, ok := since[string(unit)]; ok && t.After(from) {
+				continue
+			}
+			units = append(units, unit)
+		}
+		if len(units) == 0 {
+			continue
+		}
+		// A part which ends where a unit starts to be used may end within a
+		// period of the remaining units, so it is extended to the end of the
+		// period rather than leaving the period out.
+		if i+2 < len(bounds) {
+			if t := timeUnitSince(to, units[len(units)-1]); t.Before(end) {
+				to = t
+			} else {
+				to = end
+			}
+		}
+		for _, view := range viewsByTimeRange(name, from, to, TimeQuantum(units)) {
+			if _, ok := seen[view]; !ok {
+				seen[view] = struct{}{}
+				results = append(results, view)
+			}
+		}
+	}
+	return results
+}
+
+// timeUnitSince returns the start of the first period of unit which starts at
+// or after t.
+func timeUnitSince(t time.Time, unit rune) time.Time {
+	t = t.UTC()
+	start := unitStart(t, unit)
+	if start.Before(t) {
+		start = unitEnd(start, unit)
+	}
+	return start
+}
+
+// viewsByTimeRangeWeek returns a list of views to traverse to query a time
+// range with a quantum containing a week unit. At each step the largest unit
+// which starts at t and ends within the range is used, unless it would cross
+// the start of a larger unit which fits in the rest of the range. If no unit
+// fits, the smallest unit containing t is used.
+func viewsByTimeRangeWeek(name string, start, end time.Time, q TimeQuantum) []string {
+	var results []string
+	smallest := rune(q[len(q)-1])
+	for t := start; t.Before(end); {
+		unit := smallest
+		for i, u := range q {
+			if !unitStart(t, u).Equal(t) || unitEnd(t, u).After(end) || crossesLargerUnit(t, end, u, string(q[:i])) {
+				continue
+			}
+			unit = u
+			break
+		}
+		results = append(results, viewByTimeUnit(name, t, unit))
+		t = unitEnd(unitStart(t, unit), unit)
+	}
+	return results
+}
+
+// crossesLargerUnit returns true if the unit starting at t contains the start
+// of one of the larger units which fits before end.
+func crossesLargerUnit(t, end time.Time, unit rune, larger string) bool {
+	next := unitEnd(t, unit)
+	for _, u := range larger {
+		boundary := unitEnd(unitStart(t, u), u)
+		if boundary.Before(next) && !unitEnd(boundary, u).After(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// unitStart returns the start of the time unit containing t.
+func unitStart(t time.Time, unit rune) time.Time {
+	switch unit {
+	case 'Y':
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	case 'M':
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case 'W':
+		// ISO weeks start on Monday.
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return day.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case 'D':
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	}
+}
+
+// unitEnd returns the end of the time unit starting at t.
+func unitEnd(t time.Time, unit rune) time.Time {
+	switch unit {
+	case 'Y':
+		return t.AddDate(1, 0, 0)
+	case 'M':
+		return addMonth(t)
+	case 'W':
+		return t.AddDate(0, 0, 7)
+	case 'D':
+		return t.AddDate(0, 0, 1)
+	default:
+		return t.Add(time.Hour)
+	}
+}
+
 // addMonth adds a month similar to time.AddDate(0, 1, 0), but
 // in certain edge cases it doesn't normalize for days late in the month.
 // In the "YM" case where t.Day is greater than 28, there are
@@ -248,6 +427,8 @@ func minMaxViews(views []string, q TimeQuantum) (min string, max string) {
 		chars = 4
 	} else if q.HasMonth() {
 		chars = 6
+	} else if q.HasWeek() {
+		chars = 7
 	} else if q.HasDay() {
 		chars = 8
 	} else if q.HasHour() {
@@ -303,6 +484,17 @@ func timeOfView(v string, adj bool) (time.Time, error) {
 			t = addMonth(t)
 		}
 		return t, nil
+	case 7: // ISO week, e.g. "2019W05"
+		var year, week int
+		if _, err := fmt.Sscanf(timePart, "%4dW%2d", &year, &week); err != nil || week < 1 || week > 53 {
+			break
+		}
+		// January 4th is always in the first week of the ISO year.
+		t := unitStart(time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC), 'W').AddDate(0, 0, (week-1)*7)
+		if adj {
+			t = t.AddDate(0, 0, 7)
+		}
+		return t, nil
 	case 8: // day
 		t, err := time.Parse(layout[:8], timePart)
 		if err != nil {
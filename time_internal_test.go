@@ -36,6 +36,19 @@ func TestParseTimeQuantum(t *testing.T) {
 			t.Fatalf("unexpected error: %s", err)
 		}
 	})
+
+	t.Run("Week", func(t *testing.T) {
+		for _, v := range []string{"W", "YW", "YWD", "MWH", "YMWDH"} {
+			if _, err := parseTimeQuantum(v); err != nil {
+				t.Fatalf("unexpected error for %s: %s", v, err)
+			}
+		}
+		for _, v := range []string{"WY", "WW", "YMDW", "YD", "WX"} {
+			if _, err := parseTimeQuantum(v); err != ErrInvalidTimeQuantum {
+				t.Fatalf("expected error for %s, got: %v", v, err)
+			}
+		}
+	})
 }
 
 // Ensure generated view name can be returned for a given time unit.
@@ -52,6 +65,15 @@ func TestViewByTimeUnit(t *testing.T) {
 			t.Fatalf("unexpected name: %s", s)
 		}
 	})
+	t.Run("W", func(t *testing.T) {
+		// January 2nd, 2000 falls in the last ISO week of 1999.
+		if s := viewByTimeUnit("F", ts, 'W'); s != "F_1999W52" {
+			t.Fatalf("unexpected name: %s", s)
+		}
+		if s := viewByTimeUnit("F", mustParseTime("2018-12-31 00:00"), 'W'); s != "F_2019W01" {
+			t.Fatalf("unexpected name: %s", s)
+		}
+	})
 	t.Run("D", func(t *testing.T) {
 		if s := viewByTimeUnit("F", ts, 'D'); s != "F_20000102" {
 			t.Fatalf("unexpected name: %s", s)
@@ -75,6 +97,13 @@ func TestViewsByTime(t *testing.T) {
 		}
 	})
 
+	t.Run("YWD", func(t *testing.T) {
+		a := viewsByTime("F", ts, mustParseTimeQuantum("YWD"))
+		if !reflect.DeepEqual(a, []string{"F_2000", "F_1999W52", "F_20000102"}) {
+			t.Fatalf("unexpected names: %+v", a)
+		}
+	})
+
 	t.Run("D", func(t *testing.T) {
 		a := viewsByTime("F", ts, mustParseTimeQuantum("D"))
 		if !reflect.DeepEqual(a, []string{"F_20000102"}) {
@@ -163,6 +192,91 @@ func TestViewsByTimeRange(t *testing.T) {
 			t.Fatalf("unexpected fields: %#v", a)
 		}
 	})
+	t.Run("W", func(t *testing.T) {
+		a := viewsByTimeRange("F", mustParseTime("2018-12-31 00:00"), mustParseTime("2019-01-14 00:00"), mustParseTimeQuantum("W"))
+		if !reflect.DeepEqual(a, []string{"F_2019W01", "F_2019W02"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+	t.Run("Wpartial", func(t *testing.T) {
+		a := viewsByTimeRange("F", mustParseTime("2018-12-29 00:00"), mustParseTime("2019-01-02 00:00"), mustParseTimeQuantum("W"))
+		if !reflect.DeepEqual(a, []string{"F_2018W52", "F_2019W01"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+	t.Run("WD", func(t *testing.T) {
+		a := viewsByTimeRange("F", mustParseTime("2018-12-29 00:00"), mustParseTime("2019-01-16 00:00"), mustParseTimeQuantum("WD"))
+		if !reflect.DeepEqual(a, []string{"F_20181229", "F_20181230", "F_2019W01", "F_2019W02", "F_20190114", "F_20190115"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+	t.Run("YWD", func(t *testing.T) {
+		// The week starting 2019-12-30 crosses into 2020, which is covered
+		// by a year view instead.
+		a := viewsByTimeRange("F", mustParseTime("2019-12-25 00:00"), mustParseTime("2021-01-06 00:00"), mustParseTimeQuantum("YWD"))
+		if !reflect.DeepEqual(a, []string{"F_20191225", "F_20191226", "F_20191227", "F_20191228", "F_20191229", "F_20191230", "F_20191231", "F_2020", "F_20210101", "F_20210102", "F_20210103", "F_20210104", "F_20210105"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+	t.Run("YMWDH", func(t *testing.T) {
+		a := viewsByTimeRange("F", mustParseTime("2019-01-27 22:00"), mustParseTime("2019-03-12 01:00"), mustParseTimeQuantum("YMWDH"))
+		if !reflect.DeepEqual(a, []string{"F_2019012722", "F_2019012723", "F_20190128", "F_20190129", "F_20190130", "F_20190131", "F_201902", "F_20190301", "F_20190302", "F_20190303", "F_2019W10", "F_20190311", "F_2019031200"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+}
+
+// Ensure views of units added to a time quantum are only used from their
+// start time.
+func TestViewsByTimeRangeSince(t *testing.T) {
+	q := mustParseTimeQuantum("YMWD")
+	since := map[string]time.Time{"W": mustParseTime("2019-01-28 00:00")}
+
+	t.Run("Before", func(t *testing.T) {
+		a := viewsByTimeRangeSince("F", mustParseTime("2019-01-14 00:00"), mustParseTime("2019-01-28 00:00"), q, since)
+		if !reflect.DeepEqual(a, []string{"F_20190114", "F_20190115", "F_20190116", "F_20190117", "F_20190118", "F_20190119", "F_20190120", "F_20190121", "F_20190122", "F_20190123", "F_20190124", "F_20190125", "F_20190126", "F_20190127"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+	t.Run("Across", func(t *testing.T) {
+		a := viewsByTimeRangeSince("F", mustParseTime("2019-01-25 00:00"), mustParseTime("2019-02-11 00:00"), q, since)
+		if !reflect.DeepEqual(a, []string{"F_20190125", "F_20190126", "F_20190127", "F_2019W05", "F_2019W06"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+	t.Run("After", func(t *testing.T) {
+		a := viewsByTimeRangeSince("F", mustParseTime("2019-02-04 00:00"), mustParseTime("2019-02-12 00:00"), q, since)
+		if !reflect.DeepEqual(a, viewsByTimeRange("F", mustParseTime("2019-02-04 00:00"), mustParseTime("2019-02-12 00:00"), q)) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+	t.Run("PartialMonth", func(t *testing.T) {
+		// The part of the range before week views are used ends within a
+		// month, so it is extended to the end of the month.
+		a := viewsByTimeRangeSince("F", mustParseTime("2019-01-01 00:00"), mustParseTime("2019-03-01 00:00"), mustParseTimeQuantum("MW"), since)
+		if !reflect.DeepEqual(a, []string{"F_201901", "F_2019W05", "F_2019W06", "F_2019W07", "F_2019W08", "F_2019W09"}) {
+			t.Fatalf("unexpected fields: %#v", a)
+		}
+	})
+}
+
+// Ensure the start time of an added unit is the start of its first period at
+// or after the change.
+func TestTimeUnitSince(t *testing.T) {
+	for _, tt := range []struct {
+		t    string
+		unit rune
+		exp  string
+	}{
+		{t: "2019-01-30 12:00", unit: 'W', exp: "2019-02-04 00:00"},
+		{t: "2019-02-04 00:00", unit: 'W', exp: "2019-02-04 00:00"},
+		{t: "2019-12-31 23:00", unit: 'Y', exp: "2020-01-01 00:00"},
+		{t: "2019-01-30 12:30", unit: 'H', exp: "2019-01-30 13:00"},
+	} {
+		if s := timeUnitSince(mustParseTime(tt.t), tt.unit); !s.Equal(mustParseTime(tt.exp)) {
+			t.Fatalf("%s %c: expected %s, got %s", tt.t, tt.unit, tt.exp, s)
+		}
+	}
 }
 
 func TestMinMaxViews(t *testing.T) {
@@ -209,6 +323,12 @@ func TestMinMaxViews(t *testing.T) {
 				"",
 				"",
 			},
+			{
+				[]string{"std_2019W05", "std_20190201", "std_2019W01"},
+				mustParseTimeQuantum("WD"),
+				"std_2019W01",
+				"std_2019W05",
+			},
 		}
 		for i, test := range tests {
 			if min, max := minMaxViews(test.views, test.q); min != test.min {
@@ -252,6 +372,24 @@ func TestTimeOfView(t *testing.T) {
 				time.Date(2019, 2, 3, 9, 0, 0, 0, time.UTC),
 				"",
 			},
+			{
+				"std_2019W05",
+				time.Date(2019, 1, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 4, 0, 0, 0, 0, time.UTC),
+				"",
+			},
+			{
+				"std_2020W53",
+				time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+				"",
+			},
+			{
+				"std_2019W54",
+				time.Time{},
+				time.Time{},
+				"invalid time format on view: std_2019W54",
+			},
 			{
 				"foo",
 				time.Time{},
@@ -0,0 +1,695 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pkg/errors"
+)
+
+// MaxTransactionCalls is the maximum number of calls allowed in a single
+// transactional write.
+const MaxTransactionCalls = 1000
+
+// transactionTimeout is how long a node holds on to a prepared or committed
+// transaction before discarding it.
+const transactionTimeout = time.Minute
+
+// transactionCommitTimeout is how long a commit waits for the queries running
+// on the local node before it fails without applying any write.
+const transactionCommitTimeout = 10 * time.Second
+
+// txGatePreference is how long new queries wait for an exclusive lock which
+// is waiting for the gate, so that a steady load of queries can't starve it.
+const txGatePreference = time.Second
+
+// Transaction actions.
+const (
+	transactionActionPrepare = iota
+	transactionActionCommit
+	transactionActionAbort
+)
+
+// TransactionMessage is an internal message used by the coordinating node of
+// a transactional write to prepare, commit, or abort it on an owner node.
+type TransactionMessage struct {
+	ID     string
+	Index  string
+	Action uint32
+	Query  string
+
+	// OperationID is the client-supplied ID which makes the transaction
+	// idempotent.
+	OperationID string
+}
+
+// transactionOp is a single staged write. It applies the write and returns a
+// function which reverts it.
+type transactionOp func() (undo func() error, err error)
+
+// transaction is a transactional write staged on the local node.
+type transaction struct {
+	index     *Index
+	ops       []transactionOp
+	undo      []func() error
+	committed bool
+	created   time.Time
+
+	// Client-supplied operation ID, if any.
+	opID string
+}
+
+// rollback reverts all applied ops in reverse order.
+func (tx *transaction) rollback() error {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		if err := tx.undo[i](); err != nil {
+			return errors.Wrap(err, "reverting write")
+		}
+	}
+	tx.undo = nil
+	return nil
+}
+
+// validateTransactionCalls ensures that q only contains calls which may be
+// part of a transactional write.
+func validateTransactionCalls(q *pql.Query) error {
+	if len(q.Calls) > MaxTransactionCalls {
+		return ErrTooManyWrites
+	}
+	for _, c := range q.Calls {
+		switch c.Name {
+		case "Set", "Clear", "SetRowAttrs":
+		default:
+			return fmt.Errorf("%s() is not allowed in a transaction", c.Name)
+		}
+	}
+	return nil
+}
+
+// transactionNodes returns the nodes which own data written by q.
+func (e *executor) transactionNodes(index string, idx *Index, q *pql.Query) ([]*Node, error) {
+	nodes := make(map[string]*Node)
+	for _, c := range q.Calls {
+		// Row attributes are stored on every node.
+		if c.Name == "SetRowAttrs" {
+			return e.Cluster.Nodes(), nil
+		}
+
+		colID, ok, err := c.UintArg("_" + columnLabel)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s() column: %v", c.Name, err)
+		} else if !ok {
+			return nil, fmt.Errorf("%s() column argument '%v' required", c.Name, columnLabel)
+		}
+		for _, node := range e.Cluster.shardNodes(index, colID/idx.ShardWidth()) {
+			nodes[node.ID] = node
+		}
+	}
+
+	a := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		a = append(a, node)
+	}
+	return a, nil
+}
+
+// transactionShard is a shard of a field written by a transactional write.
+type transactionShard struct {
+	field *Field
+	shard uint64
+}
+
+// transactionNewShards returns the shards written by the Set() calls in q
+// which must be announced once q is committed. The calls must be valid.
+func transactionNewShards(idx *Index, q *pql.Query) []transactionShard {
+	var a []transactionShard
+	seen := make(map[transactionShard]struct{})
+	for _, c := range q.Calls {
+		if c.Name != "Set" {
+			continue
+		}
+		colID, _, _ := c.UintArg("_" + columnLabel)
+		fieldName, _ := c.FieldArg()
+		f := idx.Field(fieldName)
+		if f == nil {
+			continue
+		}
+		s := transactionShard{field: f, shard: colID / idx.ShardWidth()}
+		if _, ok := seen[s]; ok || !f.shardUnannounced(s.shard) {
+			continue
+		}
+		seen[s] = struct{}{}
+		a = append(a, s)
+	}
+	return a
+}
+
+// receiveTransaction handles a transaction message from the coordinator.
+func (e *executor) receiveTransaction(m *TransactionMessage) error {
+	switch m.Action {
+	case transactionActionPrepare:
+		return e.prepareTransaction(m)
+	case transactionActionCommit:
+		return e.commitTransaction(m.ID)
+	case transactionActionAbort:
+		return e.abortTransaction(m.ID)
+	default:
+		return fmt.Errorf("unknown transaction action: %d", m.Action)
+	}
+}
+
+// prepareTransaction validates a transactional write and stages the portion
+// of it which is owned by the local node.
+func (e *executor) prepareTransaction(m *TransactionMessage) error {
+	q, err := pql.NewParser(strings.NewReader(m.Query)).Parse()
+	if err != nil {
+		return errors.Wrap(err, "parsing")
+	} else if err := validateTransactionCalls(q); err != nil {
+		return err
+	}
+
+	idx, err := e.Holder.AcquireIndex(m.Index)
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+	if idx.ReadOnly() {
+		return ErrIndexReadOnly
+	} else if idx.QuotaExceeded() && addsData(q) {
+		return ErrQuotaExceeded
+	}
+
+	ops, err := e.transactionOps(m.Index, q, false)
+	if err != nil {
+		return err
+	}
+
+	e.txMu.Lock()
+	defer e.txMu.Unlock()
+
+	// Discard transactions abandoned by their coordinator.
+	now := time.Now()
+	for id, tx := range e.txs {
+		if now.Sub(tx.created) > transactionTimeout {
+			delete(e.txs, id)
+		}
+	}
+
+	if e.txs == nil {
+		e.txs = make(map[string]*transaction)
+	} else if _, ok := e.txs[m.ID]; ok {
+		return fmt.Errorf("transaction already exists: %s", m.ID)
+	}
+	e.txs[m.ID] = &transaction{index: idx.Index, ops: ops, created: now, opID: m.OperationID}
+	return nil
+}
+
+// commitTransaction applies a prepared transaction. No query can run on the
+// local node while the transaction is applied. If any write fails then all
+// previous writes are reverted. A transaction whose operation ID was already
+// committed is not applied again.
+func (e *executor) commitTransaction(id string) error {
+	e.txMu.Lock()
+	tx := e.txs[id]
+	e.txMu.Unlock()
+	if tx == nil {
+		return ErrTransactionNotFound
+	} else if tx.committed {
+		return nil
+	}
+
+	idx, err := e.acquireTransactionIndex(tx)
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+
+	if !e.txGate.lock(transactionCommitTimeout) {
+		return ErrTransactionTimeout
+	}
+	defer e.txGate.unlock()
+
+	if tx.opID != "" {
+		e.txMu.Lock()
+		applied := e.transactionOperationIDs().contains(tx.opID)
+		e.txMu.Unlock()
+		if applied {
+			e.Holder.Stats.Count("deduplicatedOps", 1, 1.0)
+			// Aborting this transaction must not forget the ID of the
+			// transaction which applied the writes.
+			tx.opID = ""
+			tx.committed = true
+			return nil
+		}
+	}
+
+	for _, op := range tx.ops {
+		undo, err := op()
+		if err != nil {
+			if rerr := tx.rollback(); rerr != nil {
+				return errors.Wrapf(err, "rolling back: %v", rerr)
+			}
+			return err
+		}
+		tx.undo = append(tx.undo, undo)
+	}
+	tx.committed = true
+
+	if tx.opID != "" {
+		e.txMu.Lock()
+		e.transactionOperationIDs().add(tx.opID)
+		e.txMu.Unlock()
+	}
+	return nil
+}
+
+// abortTransaction discards a transaction. If it has already been committed
+// then its writes are reverted.
+func (e *executor) abortTransaction(id string) error {
+	e.txMu.Lock()
+	tx := e.txs[id]
+	delete(e.txs, id)
+	e.txMu.Unlock()
+	if tx == nil || !tx.committed {
+		return nil
+	}
+
+	// The writes of a transaction were deleted with its index.
+	idx, err := e.acquireTransactionIndex(tx)
+	if err != nil {
+		return nil
+	}
+	defer idx.Close()
+
+	// Reverting a commit must not fail, so it waits without a timeout.
+	e.txGate.lock(0)
+	defer e.txGate.unlock()
+	if tx.opID != "" {
+		e.txMu.Lock()
+		e.transactionOperationIDs().remove(tx.opID)
+		e.txMu.Unlock()
+	}
+	return tx.rollback()
+}
+
+// acquireTransactionIndex returns a handle to the index of tx, unless the
+// index was deleted since tx was prepared.
+func (e *executor) acquireTransactionIndex(tx *transaction) (*IndexHandle, error) {
+	idx, err := e.Holder.AcquireIndex(tx.index.Name())
+	if err != nil {
+		return nil, err
+	} else if idx.Index != tx.index {
+		idx.Close()
+		return nil, ErrIndexNotFound
+	}
+	return idx, nil
+}
+
+// transactionOperationIDs returns the operation IDs of committed
+// transactions. e.txMu must be held.
+func (e *executor) transactionOperationIDs() *operationIDs {
+	if e.txOpIDs == nil {
+		e.txOpIDs = newOperationIDs(e.Holder.opIDOptions)
+	}
+	return e.txOpIDs
+}
+
+// transactionOps returns the writes in q which apply to the local node. If
+// all is true then writes are returned regardless of shard ownership so the
+// entire query can be validated.
+func (e *executor) transactionOps(index string, q *pql.Query, all bool) ([]transactionOp, error) {
+	idx, err := e.Holder.AcquireIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	defer idx.Close()
+
+	var ops []transactionOp
+	for _, c := range q.Calls {
+		if c.Name == "SetRowAttrs" {
+			op, err := e.transactionSetRowAttrsOp(idx.Index, c)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+			continue
+		}
+
+		colID, ok, err := c.UintArg("_" + columnLabel)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s() column: %v", c.Name, err)
+		} else if !ok {
+			return nil, fmt.Errorf("%s() column argument '%v' required", c.Name, columnLabel)
+		}
+
+		fieldName, err := c.FieldArg()
+		if err != nil {
+			return nil, fmt.Errorf("%s() argument required: field", c.Name)
+		}
+		f := idx.Field(fieldName)
+		if f == nil {
+			return nil, ErrFieldNotFound
+		}
+
+		var op transactionOp
+		switch c.Name {
+		case "Set":
+			op, err = transactionSetOp(f, c, fieldName, colID)
+		case "Clear":
+			op, err = transactionClearOp(f, c, fieldName, colID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !all && !e.Cluster.ownsShard(e.Node.ID, index, colID/idx.ShardWidth()) {
+			continue
+		}
+		if c.Name == "Set" {
+			if ef := idx.existenceField(); ef != nil {
+				ops = append(ops, transactionSetBitOp(ef, 0, colID, nil))
+			}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// transactionSetOp returns an op for a Set() call.
+func transactionSetOp(f *Field, c *pql.Call, fieldName string, colID uint64) (transactionOp, error) {
+	if f.Type() == FieldTypeInt {
+		value, ok, err := c.IntArg(fieldName)
+		if err != nil {
+			return nil, fmt.Errorf("reading Set() row: %v", err)
+		} else if !ok {
+			return nil, fmt.Errorf("Set() row argument '%v' required", rowLabel)
+		}
+
+		// Validate the value up front so that it cannot fail on commit.
+		bsig := f.bsiGroup(f.name)
+		if bsig == nil {
+			return nil, ErrBSIGroupNotFound
+		} else if value < bsig.Min {
+			return nil, ErrBSIGroupValueTooLow
+		} else if value > bsig.Max {
+			return nil, ErrBSIGroupValueTooHigh
+		}
+		return transactionSetValueOp(f, colID, value), nil
+	}
+
+	rowID, ok, err := c.UintArg(fieldName)
+	if err != nil {
+		return nil, fmt.Errorf("reading Set() row: %v", err)
+	} else if !ok {
+		return nil, fmt.Errorf("Set() row argument '%v' required", rowLabel)
+	}
+
+	var timestamp *time.Time
+	if s, ok := c.Args["_timestamp"].(string); ok {
+		t, err := time.Parse(TimeFormat, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %s", s)
+		}
+		timestamp = &t
+	}
+	return transactionSetBitOp(f, rowID, colID, timestamp), nil
+}
+
+// transactionClearOp returns an op for a Clear() call.
+func transactionClearOp(f *Field, c *pql.Call, fieldName string, colID uint64) (transactionOp, error) {
+	if f.Type() == FieldTypeInt {
+		return nil, fmt.Errorf("Clear() is not supported on int field '%s' in a transaction", fieldName)
+	}
+
+	rowID, ok, err := c.UintArg(fieldName)
+	if err != nil {
+		return nil, fmt.Errorf("reading Clear() row: %v", err)
+	} else if !ok {
+		return nil, fmt.Errorf("row=<row> argument required to Clear() call")
+	}
+
+	return func() (func() error, error) {
+		undo, err := columnUndo(f, rowID, colID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.ClearBit(rowID, colID); err != nil {
+			return nil, errors.Wrap(err, "clearing bit")
+		}
+		return undo, nil
+	}, nil
+}
+
+// transactionSetBitOp returns an op which sets a bit on f.
+func transactionSetBitOp(f *Field, rowID, colID uint64, t *time.Time) transactionOp {
+	return func() (func() error, error) {
+		undo, err := columnUndo(f, rowID, colID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.SetBit(rowID, colID, t); err != nil {
+			return nil, errors.Wrap(err, "setting bit")
+		}
+		return undo, nil
+	}
+}
+
+// transactionSetValueOp returns an op which sets an int field value.
+func transactionSetValueOp(f *Field, colID uint64, value int64) transactionOp {
+	return func() (func() error, error) {
+		prev, exists, err := f.Value(colID)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading value")
+		}
+		if _, err := f.SetValue(colID, value); err != nil {
+			return nil, errors.Wrap(err, "setting value")
+		}
+		return func() error {
+			if exists {
+				_, err := f.SetValue(colID, prev)
+				return err
+			}
+			v := f.view(viewBSIGroupPrefix + f.name)
+			if v == nil {
+				return nil
+			}
+			frag := v.Fragment(colID / f.shardWidth)
+			if frag == nil {
+				return nil
+			}
+			_, err := frag.clearValue(colID, f.bsiGroup(f.name).BitDepth, 0)
+			return err
+		}, nil
+	}
+}
+
+// transactionSetRowAttrsOp returns an op for a SetRowAttrs() call.
+func (e *executor) transactionSetRowAttrsOp(idx *Index, c *pql.Call) (transactionOp, error) {
+	fieldName, ok := c.Args["_field"].(string)
+	if !ok {
+		return nil, errors.New("SetRowAttrs() field required")
+	}
+	f := idx.Field(fieldName)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+
+	rowID, ok, err := c.UintArg("_" + rowLabel)
+	if err != nil {
+		return nil, fmt.Errorf("reading SetRowAttrs() row: %v", err)
+	} else if !ok {
+		return nil, fmt.Errorf("SetRowAttrs() row field '%v' required", rowLabel)
+	}
+
+	attrs := pql.CopyArgs(c.Args)
+	delete(attrs, "_field")
+	delete(attrs, "_"+rowLabel)
+
+	return func() (func() error, error) {
+		store := f.RowAttrStore()
+		prev, err := store.Attrs(rowID)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading row attributes")
+		}
+		if err := store.SetAttrs(rowID, attrs); err != nil {
+			return nil, errors.Wrap(err, "setting row attributes")
+		}
+		return func() error {
+			// Restore previous values, deleting attributes which did not exist.
+			m := make(map[string]interface{}, len(attrs))
+			for k := range attrs {
+				m[k] = prev[k]
+			}
+			return store.SetAttrs(rowID, m)
+		}, nil
+	}, nil
+}
+
+// columnUndo captures the state of a column in f before rowID is set or
+// cleared and returns a function which restores it.
+func columnUndo(f *Field, rowID, colID uint64) (func() error, error) {
+	// Mutex and bool fields may clear another row when a bit is set, so the
+	// previously set row is restored instead.
+	if f.Type() == FieldTypeMutex || f.Type() == FieldTypeBool {
+		var prevID uint64
+		var found bool
+		if v := f.view(viewStandard); v != nil {
+			if frag := v.Fragment(colID / f.shardWidth); frag != nil {
+				var err error
+				if prevID, found, err = frag.mutexValue(colID); err != nil {
+					return nil, errors.Wrap(err, "reading mutex value")
+				}
+			}
+		}
+		return func() error {
+			if found {
+				_, err := f.SetBit(prevID, colID, nil)
+				return err
+			}
+			_, err := f.ClearBit(rowID, colID)
+			return err
+		}, nil
+	}
+
+	prev := make(map[string]bool)
+	for _, v := range f.views() {
+		set, err := v.bit(rowID, colID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading bit on view %s", v.name)
+		}
+		prev[v.name] = set
+	}
+	return func() error {
+		for _, v := range f.views() {
+			set, err := v.bit(rowID, colID)
+			if err != nil {
+				return errors.Wrapf(err, "reading bit on view %s", v.name)
+			}
+			if set && !prev[v.name] {
+				_, err = v.clearBit(rowID, colID)
+			} else if !set && prev[v.name] {
+				_, err = v.setBit(rowID, colID)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "restoring bit on view %s", v.name)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// txGate prevents queries from observing a partially applied transaction.
+// Any number of queries may hold the gate at once, while a commit holds it
+// exclusively. A waiting commit blocks new queries for txGatePreference only,
+// because queries on one node may depend on queries running on another node.
+type txGate struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	readers int
+	writing bool
+
+	// Number of exclusive locks waiting for the gate, and the time until
+	// which new queries wait for them.
+	waiting   int
+	preferred time.Time
+}
+
+// enter acquires the gate for a query.
+func (g *txGate) enter() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.writing || (g.waiting > 0 && time.Now().Before(g.preferred)) {
+		g.wait()
+	}
+	g.readers++
+}
+
+// exit releases the gate for a query.
+func (g *txGate) exit() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.readers--
+	g.broadcast()
+}
+
+// lock acquires the gate exclusively once the queries holding it exit. It
+// returns false if the gate isn't acquired within timeout. A timeout of zero
+// waits indefinitely.
+func (g *txGate) lock(timeout time.Duration) bool {
+	now := time.Now()
+	preference := time.AfterFunc(txGatePreference, g.wake)
+	defer preference.Stop()
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = now.Add(timeout)
+		expiry := time.AfterFunc(timeout, g.wake)
+		defer expiry.Stop()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := now.Add(txGatePreference); until.After(g.preferred) {
+		g.preferred = until
+	}
+	g.waiting++
+	defer func() {
+		g.waiting--
+		g.broadcast()
+	}()
+
+	for g.writing || g.readers > 0 {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return false
+		}
+		g.wait()
+	}
+	g.writing = true
+	return true
+}
+
+// unlock releases the exclusive gate.
+func (g *txGate) unlock() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.writing = false
+	g.broadcast()
+}
+
+// wait blocks until the gate state changes. g.mu must be held.
+func (g *txGate) wait() {
+	if g.cond == nil {
+		g.cond = sync.NewCond(&g.mu)
+	}
+	g.cond.Wait()
+}
+
+// wake wakes all waiters, so that they check for an expired wait.
+func (g *txGate) wake() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.broadcast()
+}
+
+// broadcast wakes all waiters. g.mu must be held.
+func (g *txGate) broadcast() {
+	if g.cond != nil {
+		g.cond.Broadcast()
+	}
+}
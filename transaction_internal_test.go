@@ -0,0 +1,187 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"io/ioutil"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/pql"
+)
+
+// Ensure that a committed transaction can be reverted to its prior state.
+func TestTransaction_Rollback(t *testing.T) {
+	e := &executor{
+		Holder: NewHolder(),
+	}
+	e.Holder.Path, _ = ioutil.TempDir(*TempDir, "")
+	if err := e.Holder.Open(); err != nil {
+		t.Fatalf("opening holder: %v", err)
+	}
+	defer e.Holder.Close()
+
+	idx, err := e.Holder.CreateIndex("i", IndexOptions{TrackExistence: true})
+	if err != nil {
+		t.Fatalf("creating index: %v", err)
+	}
+	s, err := idx.CreateField("s", OptFieldTypeTime(TimeQuantum("YMD")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := idx.CreateField("m", OptFieldTypeMutex(CacheTypeNone, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := idx.CreateField("v", OptFieldTypeInt(-10, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Initial state.
+	if _, err := s.SetBit(1, 10, nil); err != nil {
+		t.Fatal(err)
+	} else if _, err := m.SetBit(2, 10, nil); err != nil {
+		t.Fatal(err)
+	} else if _, err := v.SetValue(10, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := pql.ParseString(`
+		Set(10, s=2, 2019-01-02T00:00)
+		Clear(10, s=1)
+		Set(10, m=3)
+		Set(10, v=-7)
+		Set(11, v=3)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := e.transactionOps("i", q, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &transaction{ops: ops}
+	for _, op := range tx.ops {
+		undo, err := op()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.undo = append(tx.undo, undo)
+	}
+
+	// Verify the transaction was applied.
+	if cols := mustFieldRow(t, s, 2).Columns(); !reflect.DeepEqual(cols, []uint64{10}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if cols := mustFieldRow(t, m, 2).Columns(); len(cols) != 0 {
+		t.Fatalf("unexpected mutex columns: %v", cols)
+	} else if val, _, err := v.Value(10); err != nil || val != -7 {
+		t.Fatalf("unexpected value: %d, %v", val, err)
+	}
+
+	if err := tx.rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the initial state was restored.
+	for _, view := range s.views() {
+		if set, err := view.bit(2, 10); err != nil {
+			t.Fatal(err)
+		} else if set {
+			t.Fatalf("bit still set in view %s", view.name)
+		}
+	}
+	if cols := mustFieldRow(t, s, 1).Columns(); !reflect.DeepEqual(cols, []uint64{10}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if cols := mustFieldRow(t, m, 2).Columns(); !reflect.DeepEqual(cols, []uint64{10}) {
+		t.Fatalf("unexpected mutex columns: %v", cols)
+	} else if cols := mustFieldRow(t, m, 3).Columns(); len(cols) != 0 {
+		t.Fatalf("unexpected mutex columns: %v", cols)
+	} else if val, _, err := v.Value(10); err != nil || val != 5 {
+		t.Fatalf("unexpected value: %d, %v", val, err)
+	} else if _, exists, err := v.Value(11); err != nil || exists {
+		t.Fatalf("unexpected value existence: %v, %v", exists, err)
+	} else if cols := mustFieldRow(t, idx.existenceField(), 0).Columns(); len(cols) != 0 {
+		t.Fatalf("unexpected existence columns: %v", cols)
+	}
+}
+
+// Ensure that a commit acquires the gate under a steady load of overlapping
+// queries.
+func TestTxGate_ContinuousQueries(t *testing.T) {
+	var g txGate
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			time.Sleep(time.Duration(i) * time.Millisecond / 4)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				g.enter()
+				time.Sleep(2 * time.Millisecond)
+				g.exit()
+			}
+		}(i)
+	}
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if !g.lock(5 * time.Second) {
+		t.Fatal("expected lock to be acquired")
+	}
+	g.unlock()
+}
+
+// Ensure that a lock which can't be acquired in time gives up, and doesn't
+// keep blocking new queries.
+func TestTxGate_LockTimeout(t *testing.T) {
+	var g txGate
+	g.enter()
+	if g.lock(50 * time.Millisecond) {
+		t.Fatal("expected lock to time out")
+	}
+
+	entered := make(chan struct{})
+	go func() {
+		g.enter()
+		close(entered)
+	}()
+	select {
+	case <-entered:
+	case <-time.After(time.Second / 2):
+		t.Fatal("expected query to enter the gate")
+	}
+	g.exit()
+	g.exit()
+}
+
+func mustFieldRow(t *testing.T, f *Field, rowID uint64) *Row {
+	t.Helper()
+	v := f.view(viewStandard)
+	if v == nil {
+		t.Fatalf("standard view not found: %s", f.Name())
+	}
+	return v.row(rowID)
+}
@@ -0,0 +1,217 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultTrashRetention is the default duration for which a deleted
+	// index is kept in the trash.
+	defaultTrashRetention = 24 * time.Hour
+
+	// trashPurgeInterval is the interval at which expired trash entries
+	// are purged.
+	trashPurgeInterval = time.Minute
+
+	// trashDir is the hidden directory of the holder which holds deleted
+	// indexes.
+	trashDir = ".trash"
+
+	// trashDeletedFile is the file of a trash entry which holds the time
+	// the index was deleted.
+	trashDeletedFile = ".deleted"
+)
+
+// TrashEntry describes an index which has been deleted, but can still be
+// restored.
+type TrashEntry struct {
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+// trashPath returns the path of the trash entry of an index.
+func (h *Holder) trashPath(name string) string {
+	return filepath.Join(h.Path, trashDir, name)
+}
+
+// Trash returns the deleted indexes kept in the trash, sorted by name.
+func (h *Holder) Trash() ([]TrashEntry, error) {
+	h.trashMu.Lock()
+	defer h.trashMu.Unlock()
+	return h.trash()
+}
+
+func (h *Holder) trash() ([]TrashEntry, error) {
+	fis, err := ioutil.ReadDir(filepath.Join(h.Path, trashDir))
+	if os.IsNotExist(err) {
+		return []TrashEntry{}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "reading trash")
+	}
+
+	entries := make([]TrashEntry, 0, len(fis))
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		entry := TrashEntry{Name: fi.Name(), DeletedAt: fi.ModTime().UTC()}
+		if buf, err := ioutil.ReadFile(filepath.Join(h.trashPath(fi.Name()), trashDeletedFile)); err == nil {
+			if t, err := time.Parse(time.RFC3339Nano, string(buf)); err == nil {
+				entry.DeletedAt = t
+			}
+		}
+		if h.trashRetention > 0 {
+			entry.PurgeAt = entry.DeletedAt.Add(h.trashRetention)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// moveToTrash moves the directory of a closed index into the trash,
+// replacing an older trash entry with the same name.
+func (h *Holder) moveToTrash(name string) error {
+	h.trashMu.Lock()
+	defer h.trashMu.Unlock()
+
+	path := h.trashPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return errors.Wrap(err, "creating trash directory")
+	} else if err := os.RemoveAll(path); err != nil {
+		return errors.Wrap(err, "removing previous trash entry")
+	} else if err := os.Rename(h.IndexPath(name), path); err != nil {
+		return errors.Wrap(err, "moving directory")
+	}
+	deletedAt := time.Now().UTC().Format(time.RFC3339Nano)
+	if err := ioutil.WriteFile(filepath.Join(path, trashDeletedFile), []byte(deletedAt), 0666); err != nil {
+		return errors.Wrap(err, "writing deletion time")
+	}
+	return nil
+}
+
+// RestoreIndex moves a deleted index out of the trash and opens it. It
+// fails if an index with the same name has been created meanwhile.
+func (h *Holder) RestoreIndex(name string) (*Index, error) {
+	if err := validateName(name); err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.waitIndexDeleted(name)
+	h.trashMu.Lock()
+	defer h.trashMu.Unlock()
+
+	if h.index(name) != nil {
+		return nil, newConflictError(ErrIndexExists)
+	}
+	path := h.trashPath(name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, newNotFoundError(ErrTrashEntryNotFound)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "statting trash entry")
+	}
+
+	if err := os.Remove(filepath.Join(path, trashDeletedFile)); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "removing deletion time")
+	} else if err := os.Rename(path, h.IndexPath(name)); err != nil {
+		return nil, errors.Wrap(err, "moving directory")
+	}
+
+	index, err := h.newIndex(h.IndexPath(name), name)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating")
+	} else if err := index.Open(); err != nil {
+		return nil, errors.Wrap(err, "opening")
+	}
+	h.indexes[name] = index
+	h.bumpSchemaGeneration()
+
+	// Restart replication.
+	go h.refreshTranslateStoreReplicator()
+
+	return index, nil
+}
+
+// PurgeTrash removes a deleted index from the trash, after which it can no
+// longer be restored.
+func (h *Holder) PurgeTrash(name string) error {
+	if err := validateName(name); err != nil {
+		return NewBadRequestError(err)
+	}
+
+	h.trashMu.Lock()
+	defer h.trashMu.Unlock()
+
+	path := h.trashPath(name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return newNotFoundError(ErrTrashEntryNotFound)
+	} else if err != nil {
+		return errors.Wrap(err, "statting trash entry")
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return errors.Wrap(err, "removing trash entry")
+	}
+	return nil
+}
+
+// monitorTrash periodically purges the indexes which have been in the
+// trash for longer than the retention period.
+func (h *Holder) monitorTrash() {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.closing:
+			return
+		case <-ticker.C:
+			h.purgeExpiredTrash(time.Now())
+		}
+	}
+}
+
+// purgeExpiredTrash removes the trash entries which expired before now.
+func (h *Holder) purgeExpiredTrash(now time.Time) {
+	h.trashMu.Lock()
+	defer h.trashMu.Unlock()
+
+	entries, err := h.trash()
+	if err != nil {
+		h.Logger.Printf("ERROR listing trash: %s", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.PurgeAt.IsZero() || entry.PurgeAt.After(now) {
+			continue
+		}
+		h.Logger.Printf("purging deleted index: %s", entry.Name)
+		if err := os.RemoveAll(h.trashPath(entry.Name)); err != nil {
+			h.Logger.Printf("ERROR purging deleted index %s: %s", entry.Name, err)
+			continue
+		}
+		h.Stats.Count("purgeTrash", 1, 1.0)
+	}
+}
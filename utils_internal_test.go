@@ -89,6 +89,9 @@ type ClusterCluster struct {
 	mu         sync.RWMutex
 	resizing   bool
 	resizeDone chan struct{}
+
+	// instructions are the resize instructions sent by the coordinator.
+	instructions []*ResizeInstruction
 }
 
 type commonClusterSettings struct {
@@ -152,12 +155,19 @@ func (t *ClusterCluster) clusterByID(id string) *cluster {
 
 // addNode adds a node to the cluster and (potentially) starts a resize job.
 func (t *ClusterCluster) addNode() error {
+	return t.addNodeVersion(ProtocolVersion)
+}
+
+// addNodeVersion is addNode for a node which speaks protocol versions up
+// to max.
+func (t *ClusterCluster) addNodeVersion(max uint32) error {
 	id := len(t.Clusters)
 
 	c, err := t.addCluster(id, false)
 	if err != nil {
 		return err
 	}
+	c.Node.MaxProtocolVersion = max
 
 	// Send NodeJoin event to coordinator.
 	if id > 0 {
@@ -202,6 +212,9 @@ func (t *ClusterCluster) addCluster(i int, saveTopology bool) (*cluster, error)
 	node := &Node{
 		ID:  id,
 		URI: uri,
+
+		MinProtocolVersion: MinProtocolVersion,
+		MaxProtocolVersion: ProtocolVersion,
 	}
 
 	// add URI to common
@@ -322,11 +335,12 @@ func (b bcast) SendSync(m Message) error {
 				}
 			}
 		}
-		b.t.mu.RLock()
+		b.t.mu.Lock()
 		if obj.State == ClusterStateNormal && b.t.resizing {
 			close(b.t.resizeDone)
+			b.t.resizing = false
 		}
-		b.t.mu.RUnlock()
+		b.t.mu.Unlock()
 	}
 	return nil
 }
@@ -366,11 +380,12 @@ func (b bcast) SendTo(to *Node, m Message) error {
 				}
 			}
 		}
-		b.t.mu.RLock()
+		b.t.mu.Lock()
 		if obj.State == ClusterStateNormal && b.t.resizing {
 			close(b.t.resizeDone)
+			b.t.resizing = false
 		}
-		b.t.mu.RUnlock()
+		b.t.mu.Unlock()
 	default:
 		panic(fmt.Sprintf("message not handled:\n%#v\n", obj))
 	}
@@ -380,6 +395,10 @@ func (b bcast) SendTo(to *Node, m Message) error {
 // FollowResizeInstruction is a version of cluster.FollowResizeInstruction used for testing.
 func (t *ClusterCluster) FollowResizeInstruction(instr *ResizeInstruction) error {
 
+	t.mu.Lock()
+	t.instructions = append(t.instructions, instr)
+	t.mu.Unlock()
+
 	// Prepare the return message.
 	complete := &ResizeInstructionComplete{
 		JobID: instr.JobID,
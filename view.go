@@ -52,6 +52,9 @@ type view struct {
 	cacheType string
 	cacheSize uint32
 
+	// Number of columns in each shard.
+	shardWidth uint64
+
 	// Fragments by shard.
 	fragments map[uint64]*fragment
 
@@ -60,6 +63,13 @@ type view struct {
 	rowAttrStore  AttrStore
 	logger        logger.Logger
 	snapshotQueue chan *fragment
+	opIDOptions   operationIDOptions
+	changes       *changeStream
+
+	// Set by a field which shares its time views, for its day views and
+	// its hour views respectively.
+	sharedChildren func(shard uint64) []*fragment
+	sharedParent   func(shard uint64) *fragment
 }
 
 // newView returns a new instance of View.
@@ -74,11 +84,14 @@ func newView(path, index, field, name string, fieldOptions FieldOptions) *view {
 		cacheType: fieldOptions.CacheType,
 		cacheSize: fieldOptions.CacheSize,
 
+		shardWidth: ShardWidth,
+
 		fragments: make(map[uint64]*fragment),
 
 		broadcaster: NopBroadcaster,
 		stats:       stats.NopStatsClient,
 		logger:      logger.NopLogger,
+		opIDOptions: defaultOperationIDOptions(),
 	}
 }
 
@@ -177,6 +190,16 @@ fileLoop:
 
 // close closes the view and its fragments.
 func (v *view) close() error {
+	return v.closeFragments((*fragment).Close)
+}
+
+// discard closes the view and its fragments before its files are deleted.
+func (v *view) discard() error {
+	return v.closeFragments((*fragment).discard)
+}
+
+// closeFragments closes the fragments of the view with fn.
+func (v *view) closeFragments(fn func(f *fragment) error) error {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
@@ -195,7 +218,7 @@ fragLoop:
 					<-workQueue
 				}()
 
-				if err := frag.Close(); err != nil {
+				if err := fn(frag); err != nil {
 					return errors.Wrap(err, "closing fragment")
 				}
 				return nil
@@ -228,6 +251,20 @@ func (v *view) availableShards() *roaring.Bitmap {
 	return b
 }
 
+// maxRowID returns the highest row ID set in any fragment of the view.
+func (v *view) maxRowID() uint64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var max uint64
+	for _, frag := range v.fragments {
+		if id := frag.rowWatermark(); id > max {
+			max = id
+		}
+	}
+	return max
+}
+
 // fragmentPath returns the path to a fragment in the view.
 func (v *view) fragmentPath(shard uint64) string {
 	return filepath.Join(v.path, "fragments", strconv.FormatUint(shard, 10))
@@ -305,11 +342,20 @@ func (v *view) CreateFragmentIfNotExists(shard uint64) (*fragment, error) {
 
 func (v *view) newFragment(path string, shard uint64) *fragment {
 	frag := newFragment(path, v.index, v.field, v.name, shard, v.flags())
+	frag.shardWidth = v.shardWidth
 	frag.CacheType = v.cacheType
 	frag.CacheSize = v.cacheSize
 	frag.Logger = v.logger
 	frag.stats = v.stats
 	frag.snapshotQueue = v.snapshotQueue
+	frag.opIDs = newOperationIDs(v.opIDOptions)
+	frag.changes = v.changes
+	if v.sharedChildren != nil {
+		frag.sharedChildren = func() []*fragment { return v.sharedChildren(shard) }
+	}
+	if v.sharedParent != nil {
+		frag.sharedParent = func() *fragment { return v.sharedParent(shard) }
+	}
 	if v.fieldType == FieldTypeMutex {
 		frag.mutexVector = newRowsVector(frag)
 	} else if v.fieldType == FieldTypeBool {
@@ -320,6 +366,16 @@ func (v *view) newFragment(path string, shard uint64) *fragment {
 
 // deleteFragment removes the fragment from the view.
 func (v *view) deleteFragment(shard uint64) error {
+	if v.sharedParent != nil {
+		if p := v.sharedParent(shard); p != nil {
+			release, err := p.holdShared()
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
+	}
+
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	fragment := v.fragments[shard]
@@ -344,6 +400,11 @@ func (v *view) deleteFragment(shard uint64) error {
 		v.logger.Printf("no cache file to delete for shard %d", shard)
 	}
 
+	// Delete the keys of the shared containers.
+	if err := fragment.writeSharedKeys(nil); err != nil {
+		return err
+	}
+
 	delete(v.fragments, shard)
 
 	return nil
@@ -365,7 +426,7 @@ func (v *view) row(rowID uint64) *Row {
 
 // setBit sets a bit within the view.
 func (v *view) setBit(rowID, columnID uint64) (changed bool, err error) {
-	shard := columnID / ShardWidth
+	shard := columnID / v.shardWidth
 	frag, err := v.CreateFragmentIfNotExists(shard)
 	if err != nil {
 		return changed, err
@@ -373,9 +434,18 @@ func (v *view) setBit(rowID, columnID uint64) (changed bool, err error) {
 	return frag.setBit(rowID, columnID)
 }
 
+// bit returns whether a bit is set within the view.
+func (v *view) bit(rowID, columnID uint64) (bool, error) {
+	frag := v.Fragment(columnID / v.shardWidth)
+	if frag == nil {
+		return false, nil
+	}
+	return frag.protectedBit(rowID, columnID)
+}
+
 // clearBit clears a bit within the view.
 func (v *view) clearBit(rowID, columnID uint64) (changed bool, err error) {
-	shard := columnID / ShardWidth
+	shard := columnID / v.shardWidth
 	frag := v.Fragment(shard)
 	if frag == nil {
 		return false, nil
@@ -385,7 +455,7 @@ func (v *view) clearBit(rowID, columnID uint64) (changed bool, err error) {
 
 // value uses a column of bits to read a multi-bit value.
 func (v *view) value(columnID uint64, bitDepth uint) (value int64, exists bool, err error) {
-	shard := columnID / ShardWidth
+	shard := columnID / v.shardWidth
 	frag, err := v.CreateFragmentIfNotExists(shard)
 	if err != nil {
 		return value, exists, err
@@ -395,7 +465,7 @@ func (v *view) value(columnID uint64, bitDepth uint) (value int64, exists bool,
 
 // setValue uses a column of bits to set a multi-bit value.
 func (v *view) setValue(columnID uint64, bitDepth uint, value int64) (changed bool, err error) {
-	shard := columnID / ShardWidth
+	shard := columnID / v.shardWidth
 	frag, err := v.CreateFragmentIfNotExists(shard)
 	if err != nil {
 		return changed, err
@@ -403,6 +473,16 @@ func (v *view) setValue(columnID uint64, bitDepth uint, value int64) (changed bo
 	return frag.setValue(columnID, bitDepth, value)
 }
 
+// incrementValue adds delta to a multi-bit value and returns the new value.
+func (v *view) incrementValue(columnID uint64, bitDepth uint, delta, zeroValue, min, max int64, clamp bool) (value int64, err error) {
+	shard := columnID / v.shardWidth
+	frag, err := v.CreateFragmentIfNotExists(shard)
+	if err != nil {
+		return value, err
+	}
+	return frag.incrementValue(columnID, bitDepth, delta, zeroValue, min, max, clamp)
+}
+
 // sum returns the sum & count of a field.
 func (v *view) sum(filter *Row, bitDepth uint) (sum int64, count uint64, err error) {
 	for _, f := range v.allFragments() {
@@ -494,9 +574,13 @@ func upgradeViewBSIv2(v *view, bitDepth uint) (ok bool, _ error) {
 	return ok, nil
 }
 
-// ViewInfo represents schema information for a view.
+// ViewInfo represents schema information for a view. The number of shards
+// of the view on a node, and the bytes used by their fragments, are only set
+// when listing the views of a field.
 type ViewInfo struct {
-	Name string `json:"name"`
+	Name   string `json:"name"`
+	Shards int    `json:"shards,omitempty"`
+	Size   int64  `json:"size,omitempty"`
 }
 
 type viewInfoSlice []*ViewInfo
@@ -0,0 +1,651 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pilosa/pilosa/v2/internal"
+	"github.com/pilosa/pilosa/v2/logger"
+	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultWarmJobConcurrency is the number of fragments warmed at the
+	// same time across all warm jobs.
+	defaultWarmJobConcurrency = 2
+
+	// warmJobSaveInterval is the shortest time between two saves of a
+	// running warm job. Fragments completed since the last save are warmed
+	// again if the node stops, which is safe since warming is idempotent.
+	warmJobSaveInterval = time.Second
+
+	// warmJobType is the type of warm jobs in the jobs API.
+	warmJobType = "warm"
+)
+
+// Statuses of warm jobs and their tasks, which are those of delete jobs.
+const (
+	WarmJobPending   = DeleteJobPending
+	WarmJobRunning   = DeleteJobRunning
+	WarmJobSucceeded = DeleteJobSucceeded
+	WarmJobFailed    = DeleteJobFailed
+)
+
+// WarmJobOptions configures the warm jobs run by a node.
+type WarmJobOptions struct {
+	// Concurrency is the number of fragments warmed at the same time across
+	// all warm jobs.
+	Concurrency int
+
+	// Rate is the largest number of bytes of fragments read per second
+	// across all warm jobs. Zero is unlimited.
+	Rate int
+}
+
+// WarmJob is an asynchronous rebuild of the structures derived from the data
+// of indexes, such as after a restore: ranked caches, block checksums, and
+// existence bits. The job runs on every node of the cluster, with a task for
+// each fragment of the node. Jobs are persisted, so that a node resumes its
+// unfinished tasks when it restarts.
+type WarmJob struct {
+	ID       string                      `json:"id"`
+	Type     string                      `json:"type"`
+	Indexes  []string                    `json:"indexes"`
+	Status   string                      `json:"status"`
+	Created  time.Time                   `json:"created"`
+	Updated  time.Time                   `json:"updated"`
+	Progress map[string]*WarmJobProgress `json:"progress"`
+
+	// Nodes is the status of the job on each node, set when the progress of
+	// the nodes of the cluster is combined.
+	Nodes map[string]string `json:"nodes,omitempty"`
+
+	Tasks []*WarmJobTask `json:"-"`
+}
+
+// WarmJobProgress is the progress of a warm job on an index.
+type WarmJobProgress struct {
+	Fragments int    `json:"fragments"`
+	Pending   int    `json:"pending"`
+	Running   int    `json:"running"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Bytes     uint64 `json:"bytes"`
+}
+
+// add adds the progress of other to p.
+func (p *WarmJobProgress) add(other *WarmJobProgress) {
+	p.Fragments += other.Fragments
+	p.Pending += other.Pending
+	p.Running += other.Running
+	p.Succeeded += other.Succeeded
+	p.Failed += other.Failed
+	p.Bytes += other.Bytes
+}
+
+// status returns the status of a job with the progress p: a job is pending
+// until a fragment starts, and is running until every fragment is done.
+func (p *WarmJobProgress) status() string {
+	switch {
+	case p.Pending == p.Fragments && p.Fragments > 0:
+		return WarmJobPending
+	case p.Pending+p.Running > 0:
+		return WarmJobRunning
+	case p.Failed > 0:
+		return WarmJobFailed
+	}
+	return WarmJobSucceeded
+}
+
+// WarmJobTask is the part of a warm job which warms a single fragment.
+type WarmJobTask struct {
+	Index  string
+	Field  string
+	View   string
+	Shard  uint64
+	Status string
+
+	// Bytes is the size of the fragment when it was warmed.
+	Bytes uint64
+
+	// Error is set if the task failed.
+	Error string
+}
+
+// progress returns the progress of the job on each of its indexes.
+func (j *WarmJob) progress() map[string]*WarmJobProgress {
+	m := make(map[string]*WarmJobProgress, len(j.Indexes))
+	for _, index := range j.Indexes {
+		m[index] = &WarmJobProgress{}
+	}
+	for _, t := range j.Tasks {
+		p := m[t.Index]
+		if p == nil {
+			p = &WarmJobProgress{}
+			m[t.Index] = p
+		}
+		p.Fragments++
+		switch t.Status {
+		case WarmJobPending:
+			p.Pending++
+		case WarmJobRunning:
+			p.Running++
+		case WarmJobSucceeded:
+			p.Succeeded++
+			p.Bytes += t.Bytes
+		case WarmJobFailed:
+			p.Failed++
+		}
+	}
+	return m
+}
+
+// status returns the status of a job from the statuses of its tasks.
+func (j *WarmJob) status() string {
+	var total WarmJobProgress
+	for _, p := range j.progress() {
+		total.add(p)
+	}
+	return total.status()
+}
+
+// clone returns a copy of the job and its tasks, with its status and
+// progress set.
+func (j *WarmJob) clone() *WarmJob {
+	other := *j
+	other.Type = warmJobType
+	other.Indexes = append([]string(nil), j.Indexes...)
+	other.Progress = j.progress()
+	other.Status = j.status()
+	other.Tasks = make([]*WarmJobTask, len(j.Tasks))
+	for i, t := range j.Tasks {
+		tt := *t
+		other.Tasks[i] = &tt
+	}
+	return &other
+}
+
+// mergeWarmJobs combines the jobs of the nodes of the cluster, by node ID,
+// into a job with the progress of the whole cluster. A node without the job
+// is reported with its error, or as missing.
+func mergeWarmJobs(jobs map[string]*WarmJob, errs map[string]error) *WarmJob {
+	var merged *WarmJob
+	var total WarmJobProgress
+	nodes := make(map[string]string, len(jobs)+len(errs))
+	for id, err := range errs {
+		nodes[id] = "error: " + err.Error()
+	}
+	for id, j := range jobs {
+		if j == nil {
+			if _, ok := nodes[id]; !ok {
+				nodes[id] = "missing"
+			}
+			continue
+		}
+		nodes[id] = j.Status
+		if merged == nil {
+			merged = &WarmJob{
+				ID:       j.ID,
+				Type:     warmJobType,
+				Indexes:  j.Indexes,
+				Created:  j.Created,
+				Progress: make(map[string]*WarmJobProgress),
+			}
+		}
+		if j.Updated.After(merged.Updated) {
+			merged.Updated = j.Updated
+		}
+		for index, p := range j.Progress {
+			if merged.Progress[index] == nil {
+				merged.Progress[index] = &WarmJobProgress{}
+			}
+			merged.Progress[index].add(p)
+			total.add(p)
+		}
+	}
+	if merged == nil {
+		return nil
+	}
+	merged.Status = total.status()
+	merged.Nodes = nodes
+	return merged
+}
+
+// warmJobs runs the warm jobs of a node and persists their state in a
+// directory, one file per job.
+type warmJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*WarmJob
+
+	// Directory of the job files. Jobs are not persisted if it is empty.
+	path string
+
+	// Time of the last save of each job.
+	saved map[string]time.Time
+
+	// Fragments wait for a slot of sem, and for their turn under the rate,
+	// which is tracked by the time the next fragment may start.
+	sem  chan struct{}
+	rate int
+	next time.Time
+
+	holder *Holder
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+
+	logger logger.Logger
+	stats  stats.StatsClient
+}
+
+// newWarmJobs returns a new instance of warmJobs which persists jobs in the
+// given data directory.
+func newWarmJobs(opt WarmJobOptions, dir string, holder *Holder) *warmJobs {
+	if opt.Concurrency <= 0 {
+		opt.Concurrency = defaultWarmJobConcurrency
+	}
+	w := &warmJobs{
+		jobs:   make(map[string]*WarmJob),
+		saved:  make(map[string]time.Time),
+		sem:    make(chan struct{}, opt.Concurrency),
+		rate:   opt.Rate,
+		holder: holder,
+		logger: logger.NopLogger,
+		stats:  stats.NopStatsClient,
+	}
+	if dir != "" {
+		w.path = filepath.Join(dir, ".warm-jobs")
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	return w
+}
+
+// open loads the persisted jobs and resumes the unfinished ones. Tasks which
+// were running when the node stopped are warmed again.
+func (w *warmJobs) open() error {
+	if w.path == "" {
+		return nil
+	}
+	fis, err := ioutil.ReadDir(w.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "reading directory")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != "" {
+			continue
+		}
+		j, err := w.load(filepath.Join(w.path, fi.Name()))
+		if err != nil {
+			return errors.Wrapf(err, "loading job %s", fi.Name())
+		}
+		w.jobs[j.ID] = j
+
+		resume := false
+		for _, t := range j.Tasks {
+			if t.Status == WarmJobRunning {
+				t.Status = WarmJobPending
+			}
+			resume = resume || t.Status == WarmJobPending
+		}
+		if resume {
+			w.logger.Printf("resuming warm job %s", j.ID)
+			w.start(j)
+		}
+	}
+	return nil
+}
+
+// close stops the running jobs and waits for their fragments to finish.
+func (w *warmJobs) close() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// create persists and starts a job with the given ID which warms the
+// fragments of indexes on this node. If the job exists, its failed tasks are
+// restarted instead, unless it is running.
+func (w *warmJobs) create(id string, indexes []string, created time.Time) (*WarmJob, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if j := w.jobs[id]; j != nil {
+		if err := w.restart(j); err != nil {
+			return nil, err
+		}
+		return j.clone(), nil
+	}
+
+	j := &WarmJob{
+		ID:      id,
+		Indexes: indexes,
+		Created: created.UTC(),
+		Updated: time.Now().UTC(),
+	}
+	for _, name := range indexes {
+		idx := w.holder.Index(name)
+		if idx == nil {
+			continue
+		}
+		for _, f := range idx.Fields() {
+			for _, v := range f.views() {
+				for _, frag := range v.allFragments() {
+					j.Tasks = append(j.Tasks, &WarmJobTask{
+						Index:  name,
+						Field:  f.Name(),
+						View:   v.name,
+						Shard:  frag.shard,
+						Status: WarmJobPending,
+					})
+				}
+			}
+		}
+	}
+	sort.Slice(j.Tasks, func(a, b int) bool {
+		ta, tb := j.Tasks[a], j.Tasks[b]
+		if ta.Index != tb.Index {
+			return ta.Index < tb.Index
+		} else if ta.Field != tb.Field {
+			return ta.Field < tb.Field
+		} else if ta.View != tb.View {
+			return ta.View < tb.View
+		}
+		return ta.Shard < tb.Shard
+	})
+
+	if err := w.save(j); err != nil {
+		return nil, errors.Wrap(err, "saving job")
+	}
+	w.jobs[j.ID] = j
+	w.start(j)
+	return j.clone(), nil
+}
+
+// job returns a copy of the job with the given ID, or nil if there is no
+// such job.
+func (w *warmJobs) job(id string) *WarmJob {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if j := w.jobs[id]; j != nil {
+		return j.clone()
+	}
+	return nil
+}
+
+// restart restarts the failed tasks of a finished job. unprotected.
+func (w *warmJobs) restart(j *WarmJob) error {
+	if status := j.status(); status == WarmJobPending || status == WarmJobRunning {
+		return newConflictError(errors.Errorf("job is %s", status))
+	}
+
+	var n int
+	for _, t := range j.Tasks {
+		if t.Status == WarmJobFailed {
+			t.Status, t.Error = WarmJobPending, ""
+			n++
+		}
+	}
+	if n > 0 {
+		j.Updated = time.Now().UTC()
+		if err := w.save(j); err != nil {
+			return errors.Wrap(err, "saving job")
+		}
+		w.start(j)
+	}
+	return nil
+}
+
+// start warms the fragments of the pending tasks of j in the background.
+// unprotected.
+func (w *warmJobs) start(j *WarmJob) {
+	var tasks []*WarmJobTask
+	for _, t := range j.Tasks {
+		if t.Status == WarmJobPending {
+			tasks = append(tasks, t)
+		}
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		var wg sync.WaitGroup
+		for _, t := range tasks {
+			select {
+			case w.sem <- struct{}{}:
+			case <-w.ctx.Done():
+				wg.Wait()
+				return
+			}
+			frag := w.holder.fragment(t.Index, t.Field, t.View, t.Shard)
+			if err := w.wait(fragmentFileSize(frag)); err != nil {
+				<-w.sem
+				break
+			}
+			wg.Add(1)
+			go func(t *WarmJobTask) {
+				defer func() { <-w.sem; wg.Done() }()
+				w.run(j, t, frag)
+			}(t)
+		}
+		wg.Wait()
+		w.finish(j)
+	}()
+}
+
+// fragmentFileSize returns the size of the file of a fragment, or zero if
+// there is no such fragment.
+func fragmentFileSize(frag *fragment) int {
+	if frag == nil {
+		return 0
+	}
+	fi, err := os.Stat(frag.path)
+	if err != nil {
+		return 0
+	}
+	return int(fi.Size())
+}
+
+// wait blocks until n bytes can be read without exceeding the rate.
+func (w *warmJobs) wait(n int) error {
+	if w.rate <= 0 {
+		return nil
+	}
+	w.mu.Lock()
+	now := time.Now()
+	if w.next.Before(now) {
+		w.next = now
+	}
+	at := w.next
+	w.next = w.next.Add(time.Duration(n) * time.Second / time.Duration(w.rate))
+	w.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// run warms the fragment of a task and records its status. A fragment which
+// was deleted since the job was created succeeds without being warmed.
+func (w *warmJobs) run(j *WarmJob, t *WarmJobTask, frag *fragment) {
+	w.mu.Lock()
+	t.Status = WarmJobRunning
+	w.mu.Unlock()
+
+	var err error
+	if frag != nil {
+		err = w.holder.warmFragment(w.ctx, frag)
+	}
+	size := fragmentFileSize(frag)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case err != nil && w.ctx.Err() != nil:
+		t.Status = WarmJobPending
+	case err != nil:
+		t.Status, t.Error = WarmJobFailed, err.Error()
+		w.logger.Printf("warm job %s: warming %s/%s/%s/%d: %v", j.ID, t.Index, t.Field, t.View, t.Shard, err)
+	default:
+		t.Status, t.Bytes = WarmJobSucceeded, uint64(size)
+	}
+	j.Updated = time.Now().UTC()
+	if time.Since(w.saved[j.ID]) >= warmJobSaveInterval {
+		if err := w.save(j); err != nil {
+			w.logger.Printf("saving warm job %s: %v", j.ID, err)
+		}
+	}
+}
+
+// finish persists and records the completion of j, unless it was
+// interrupted.
+func (w *warmJobs) finish(j *WarmJob) {
+	w.mu.Lock()
+	if err := w.save(j); err != nil {
+		w.logger.Printf("saving warm job %s: %v", j.ID, err)
+	}
+	status := j.status()
+	var bytes uint64
+	for _, t := range j.Tasks {
+		bytes += t.Bytes
+	}
+	w.mu.Unlock()
+
+	switch status {
+	case WarmJobSucceeded, WarmJobFailed:
+		w.logger.Printf("warm job %s %s, warmed %d fragments of %d bytes", j.ID, status, len(j.Tasks), bytes)
+		w.stats.CountWithCustomTags("warmJob", 1, 1.0, []string{"status:" + status})
+	}
+}
+
+// load reads a persisted job.
+func (w *warmJobs) load(path string) (*WarmJob, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading file")
+	}
+	var pb internal.WarmJob
+	if err := proto.Unmarshal(buf, &pb); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling")
+	}
+
+	j := &WarmJob{
+		ID:      pb.ID,
+		Indexes: pb.Indexes,
+		Created: time.Unix(0, pb.Created).UTC(),
+		Updated: time.Unix(0, pb.Updated).UTC(),
+		Tasks:   make([]*WarmJobTask, len(pb.Tasks)),
+	}
+	for i, t := range pb.Tasks {
+		j.Tasks[i] = &WarmJobTask{
+			Index:  t.Index,
+			Field:  t.Field,
+			View:   t.View,
+			Shard:  t.Shard,
+			Status: t.Status,
+			Bytes:  t.Bytes,
+			Error:  t.Error,
+		}
+	}
+	return j, nil
+}
+
+// save persists j. The file is replaced atomically, so that a crash leaves
+// either the previous or the new state. unprotected.
+func (w *warmJobs) save(j *WarmJob) error {
+	if w.path == "" {
+		return nil
+	}
+
+	pb := &internal.WarmJob{
+		ID:      j.ID,
+		Indexes: j.Indexes,
+		Created: j.Created.UnixNano(),
+		Updated: j.Updated.UnixNano(),
+		Tasks:   make([]*internal.WarmJobTask, len(j.Tasks)),
+	}
+	for i, t := range j.Tasks {
+		pb.Tasks[i] = &internal.WarmJobTask{
+			Index:  t.Index,
+			Field:  t.Field,
+			View:   t.View,
+			Shard:  t.Shard,
+			Status: t.Status,
+			Bytes:  t.Bytes,
+			Error:  t.Error,
+		}
+	}
+	buf, err := proto.Marshal(pb)
+	if err != nil {
+		return errors.Wrap(err, "marshalling")
+	}
+
+	if err := os.MkdirAll(w.path, 0777); err != nil {
+		return errors.Wrap(err, "creating directory")
+	}
+	path := filepath.Join(w.path, j.ID)
+	if err := ioutil.WriteFile(path+".tmp", buf, 0666); err != nil {
+		return errors.Wrap(err, "writing file")
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return errors.Wrap(err, "renaming file")
+	}
+	w.saved[j.ID] = time.Now()
+	return nil
+}
+
+// warmFragment rebuilds the structures derived from the storage of a
+// fragment: its cache, its block checksums, and the existence
+// bits of its columns if the index tracks existence.
+func (h *Holder) warmFragment(ctx context.Context, frag *fragment) error {
+	if frag.CacheType != CacheTypeNone {
+		if err := frag.rebuildCache(); err != nil {
+			return errors.Wrap(err, "rebuilding cache")
+		}
+	}
+	frag.InvalidateChecksums()
+	_ = frag.Blocks()
+
+	idx := h.Index(frag.index)
+	if idx == nil || frag.field == existenceFieldName {
+		return nil
+	}
+	ef := idx.existenceField()
+	if ef == nil {
+		return nil
+	}
+	data, err := frag.existenceData()
+	if err != nil {
+		return errors.Wrap(err, "reading columns")
+	}
+	return errors.Wrap(ef.importRoaring(ctx, data, frag.shard, viewStandard, false, ""), "importing existence")
+}
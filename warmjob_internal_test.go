@@ -0,0 +1,183 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// waitForWarmJob waits until a job is finished and returns it.
+func waitForWarmJob(tb testing.TB, w *warmJobs, id string) *WarmJob {
+	tb.Helper()
+	for i := 0; i < 500; i++ {
+		if j := w.job(id); j.Status == WarmJobSucceeded || j.Status == WarmJobFailed {
+			return j
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestWarmJobs(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := h.CreateIndex("i", IndexOptions{TrackExistence: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := idx.CreateField("f", OptFieldTypeSet(CacheTypeRanked, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bit := range [][2]uint64{{1, 3}, {1, 4}, {2, 4}, {1, ShardWidth + 1}} {
+		if _, err := f.SetBit(bit[0], bit[1], nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Warm", func(t *testing.T) {
+		dir, err := ioutil.TempDir(*TempDir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		// The cache of shard 0 is lost, as after a restore without caches,
+		// and bits were set without their existence.
+		frag := h.fragment("i", "f", viewStandard, 0)
+		frag.cache = NewRankCache(100)
+
+		w := newWarmJobs(WarmJobOptions{}, dir, h.Holder)
+		defer w.close()
+		if _, err := w.create("j", []string{"i", "x"}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		j := waitForWarmJob(t, w, "j")
+		if j.Status != WarmJobSucceeded || j.Type != warmJobType {
+			t.Fatalf("unexpected job: %+v", j)
+		} else if p := j.Progress["i"]; p.Fragments != 2 || p.Succeeded != 2 || p.Bytes == 0 {
+			t.Fatalf("unexpected progress: %+v", p)
+		} else if p := j.Progress["x"]; *p != (WarmJobProgress{}) {
+			t.Fatalf("unexpected progress of missing index: %+v", p)
+		}
+
+		if pairs := frag.cache.Top(); len(pairs) != 2 || pairs[0].ID != 1 || pairs[0].Count != 2 {
+			t.Fatalf("unexpected cache: %+v", pairs)
+		}
+		row, err := idx.existenceField().Row(0)
+		if err != nil {
+			t.Fatal(err)
+		} else if got, want := row.Columns(), []uint64{3, 4, ShardWidth + 1}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected existence: got %v, want %v", got, want)
+		}
+
+		// Creating the job again while it is finished restarts nothing.
+		if other, err := w.create("j", nil, time.Now()); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(other.Progress, j.Progress) {
+			t.Fatalf("unexpected progress: %+v", other.Progress)
+		}
+	})
+
+	t.Run("ResumeRunning", func(t *testing.T) {
+		dir, err := ioutil.TempDir(*TempDir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		// A task which was running when the node stopped is warmed when the
+		// jobs are opened, and a task which succeeded is not.
+		w := newWarmJobs(WarmJobOptions{}, dir, h.Holder)
+		j := &WarmJob{
+			ID:      "j",
+			Indexes: []string{"i"},
+			Created: time.Now().UTC(),
+			Updated: time.Now().UTC(),
+			Tasks: []*WarmJobTask{
+				{Index: "i", Field: "f", View: viewStandard, Shard: 0, Status: WarmJobSucceeded, Bytes: 1},
+				{Index: "i", Field: "f", View: viewStandard, Shard: 1, Status: WarmJobRunning},
+			},
+		}
+		if err := w.save(j); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.open(); err != nil {
+			t.Fatal(err)
+		}
+		defer w.close()
+		j = waitForWarmJob(t, w, "j")
+		if j.Status != WarmJobSucceeded || j.Tasks[0].Bytes != 1 || j.Tasks[1].Bytes <= 1 {
+			t.Fatalf("unexpected job: %+v, tasks %+v %+v", j, j.Tasks[0], j.Tasks[1])
+		}
+	})
+
+	t.Run("Rate", func(t *testing.T) {
+		w := newWarmJobs(WarmJobOptions{Rate: 1000}, "", h.Holder)
+		defer w.close()
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := w.wait(100); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Fatalf("unexpected elapsed time: %s", elapsed)
+		}
+	})
+}
+
+func TestMergeWarmJobs(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	job := func(status string, updated time.Duration, p WarmJobProgress) *WarmJob {
+		return &WarmJob{
+			ID:       "j",
+			Indexes:  []string{"i"},
+			Status:   status,
+			Created:  created,
+			Updated:  created.Add(updated),
+			Progress: map[string]*WarmJobProgress{"i": &p},
+		}
+	}
+
+	j := mergeWarmJobs(map[string]*WarmJob{
+		"n0": job(WarmJobSucceeded, time.Minute, WarmJobProgress{Fragments: 2, Succeeded: 2, Bytes: 10}),
+		"n1": job(WarmJobRunning, time.Second, WarmJobProgress{Fragments: 3, Pending: 1, Running: 1, Succeeded: 1, Bytes: 5}),
+		"n2": nil,
+		"n3": nil,
+	}, map[string]error{"n3": errors.New("marker")})
+
+	if j.Status != WarmJobRunning || !j.Updated.Equal(created.Add(time.Minute)) {
+		t.Fatalf("unexpected job: %+v", j)
+	} else if p := *j.Progress["i"]; p != (WarmJobProgress{Fragments: 5, Pending: 1, Running: 1, Succeeded: 3, Bytes: 15}) {
+		t.Fatalf("unexpected progress: %+v", p)
+	} else if want := map[string]string{"n0": WarmJobSucceeded, "n1": WarmJobRunning, "n2": "missing", "n3": "error: marker"}; !reflect.DeepEqual(j.Nodes, want) {
+		t.Fatalf("unexpected nodes: %v", j.Nodes)
+	}
+
+	if j := mergeWarmJobs(map[string]*WarmJob{"n0": nil}, nil); j != nil {
+		t.Fatalf("unexpected job: %+v", j)
+	}
+}
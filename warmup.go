@@ -0,0 +1,221 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/v2/logger"
+	"github.com/pilosa/pilosa/v2/pql"
+	"github.com/pilosa/pilosa/v2/stats"
+	"github.com/pkg/errors"
+)
+
+// WarmupOptions configures the queries which a node runs against its own
+// fragments when it starts, to load the caches and the pages of the data
+// which the first queries read.
+type WarmupOptions struct {
+	// Queries are run in order, once the holder is open.
+	Queries []WarmupQuery
+
+	// Timeout is the longest time for which the node reports that it isn't
+	// ready while it warms up. The queries which remain then are run in the
+	// background. Zero waits for every query.
+	Timeout time.Duration
+}
+
+// WarmupQuery is a read query run by a node when it starts.
+type WarmupQuery struct {
+	Index string
+	Query string
+}
+
+// WarmupStatus is the progress of the warm-up queries of a node.
+type WarmupStatus struct {
+	Queries int `json:"queries"`
+	Done    int `json:"done"`
+	Failed  int `json:"failed"`
+}
+
+// warmup runs the warm-up queries of a node. The queries only read the
+// shards which the node owns, with a low priority, so that they wait for
+// the other queries which the node executes.
+type warmup struct {
+	opt      WarmupOptions
+	executor *executor
+	logger   logger.Logger
+	stats    stats.StatsClient
+
+	closing chan struct{}
+	wg      sync.WaitGroup
+
+	mu sync.Mutex
+
+	// Set while the node waits for the warm-up before it becomes ready.
+	blocking bool
+
+	// Number of queries run, and of those which failed.
+	done   int
+	failed int
+}
+
+func newWarmup(opt WarmupOptions) *warmup {
+	return &warmup{
+		opt:     opt,
+		logger:  logger.NopLogger,
+		stats:   stats.NopStatsClient,
+		closing: make(chan struct{}),
+	}
+}
+
+// start starts the warm-up in the background. The node isn't ready until
+// the warm-up finishes, or its timeout expires.
+func (w *warmup) start() {
+	if len(w.opt.Queries) == 0 {
+		return
+	}
+	w.setBlocking(true)
+
+	var timer *time.Timer
+	if w.opt.Timeout > 0 {
+		timer = time.AfterFunc(w.opt.Timeout, func() {
+			if w.isBlocking() {
+				w.logger.Printf("warm-up exceeded its timeout of %s, continuing in the background", w.opt.Timeout)
+				w.setBlocking(false)
+			}
+		})
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer w.setBlocking(false)
+		if timer != nil {
+			defer timer.Stop()
+		}
+		w.run()
+	}()
+}
+
+// run runs the warm-up queries, and reports their completion percentage.
+// A query which fails is logged and skipped.
+func (w *warmup) run() {
+	start := time.Now()
+	n := len(w.opt.Queries)
+	w.logger.Printf("warming up with %d queries", n)
+	w.stats.Gauge("warmupCompletion", 0, 1.0)
+
+	for i, q := range w.opt.Queries {
+		select {
+		case <-w.closing:
+			w.logger.Printf("warm-up stopped after %d of %d queries", i, n)
+			return
+		default:
+		}
+
+		t := time.Now()
+		err := w.runQuery(q)
+		if err != nil {
+			w.logger.Printf("warm-up query %d of %d on index %s failed: %v", i+1, n, q.Index, err)
+		} else {
+			w.logger.Debugf("warm-up query %d of %d on index %s took %s", i+1, n, q.Index, time.Since(t))
+		}
+		w.mu.Lock()
+		w.done++
+		if err != nil {
+			w.failed++
+		}
+		w.mu.Unlock()
+		w.stats.Gauge("warmupCompletion", float64(100*(i+1)/n), 1.0)
+	}
+	st := w.status()
+	w.logger.Printf("warmed up with %d queries in %s, %d failed", n, time.Since(start), st.Failed)
+}
+
+// status returns the progress of the warm-up, or nil if there are no
+// warm-up queries.
+func (w *warmup) status() *WarmupStatus {
+	if len(w.opt.Queries) == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return &WarmupStatus{Queries: len(w.opt.Queries), Done: w.done, Failed: w.failed}
+}
+
+// runQuery runs q on the shards of its index which this node owns.
+func (w *warmup) runQuery(q WarmupQuery) error {
+	idx := w.executor.Holder.Index(q.Index)
+	if idx == nil {
+		return ErrIndexNotFound
+	}
+	query, err := pql.NewParser(strings.NewReader(q.Query)).Parse()
+	if err != nil {
+		return errors.Wrap(err, "parsing")
+	} else if query.WriteCallN() > 0 {
+		return errors.New("warm-up queries cannot write")
+	}
+
+	var shards []uint64
+	for _, shard := range idx.AvailableShards().Slice() {
+		if w.executor.Cluster.ownsShard(w.executor.Node.ID, q.Index, shard) {
+			shards = append(shards, shard)
+		}
+	}
+	if len(shards) == 0 {
+		return nil
+	}
+
+	// The query is run as if it was sent by another node, so that it only
+	// reads this node. Its keys are translated here, since remote queries
+	// are received translated.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-w.closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	if err := w.executor.translateCalls(ctx, q.Index, idx, query.Calls); err != nil {
+		return errors.Wrap(err, "translating")
+	}
+	_, err = w.executor.Execute(ctx, q.Index, query, shards, &execOptions{Remote: true, LowPriority: true})
+	return err
+}
+
+// close stops the warm-up and waits for the query being run.
+func (w *warmup) close() {
+	close(w.closing)
+	w.wg.Wait()
+}
+
+// isBlocking returns true while the node waits for the warm-up before it
+// becomes ready.
+func (w *warmup) isBlocking() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.blocking
+}
+
+func (w *warmup) setBlocking(v bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.blocking = v
+}